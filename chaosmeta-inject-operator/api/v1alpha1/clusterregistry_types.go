@@ -0,0 +1,66 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DefaultKubeConfigKey is the key of the kubeconfig in the secret if "key" is empty
+const DefaultKubeConfigKey = "kubeconfig"
+
+// ClusterRegistrySpec registers the clusters which the experiments are run in, so that the operator in a management
+// cluster injects the targets of other clusters. Only the registries in the namespace of the operator are read
+type ClusterRegistrySpec struct {
+	Clusters []RegisteredCluster `json:"clusters"`
+}
+
+type RegisteredCluster struct {
+	// Name is referred by "cluster" of the experiment, it is unique among the registries
+	Name string `json:"name"`
+	// KubeConfigSecret is the secret holding the kubeconfig of the cluster, in the namespace of the registry
+	KubeConfigSecret KubeConfigSecretRef `json:"kubeConfigSecret"`
+}
+
+type KubeConfigSecretRef struct {
+	Name string `json:"name"`
+	// Key Optional: the key of the kubeconfig in the secret, default "kubeconfig"
+	Key string `json:"key,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// ClusterRegistry is the Schema for the clusterregistries API
+type ClusterRegistry struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ClusterRegistrySpec `json:"spec,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// ClusterRegistryList contains a list of ClusterRegistry
+type ClusterRegistryList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterRegistry `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ClusterRegistry{}, &ClusterRegistryList{})
+}
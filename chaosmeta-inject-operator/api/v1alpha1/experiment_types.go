@@ -18,6 +18,7 @@ package v1alpha1
 
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"time"
 )
 
 const (
@@ -26,6 +27,29 @@ const (
 	FinalizerName  = "chaosmeta/experiment"
 	ContainerKey   = "containername"
 	FirstContainer = "firstcontainer"
+
+	// CreatorLabelKey and SourceLabelKey are added by the mutating webhook when the experiment is created
+	CreatorLabelKey = "chaosmeta.io/creator"
+	SourceLabelKey  = "chaosmeta.io/source"
+	// ScheduledByLabelKey is the name of the experiment with a schedule which creates the child experiment
+	ScheduledByLabelKey = "chaosmeta.io/scheduled-by"
+	// ForceDeleteAnnotationKey set to "true" removes the finalizer of a deleted experiment without waiting for its recovery
+	ForceDeleteAnnotationKey = "chaosmeta.io/force-delete"
+	// ExecutorLabelKey on a pod or node selects the remote executor of the target, prior to the executor of the experiment
+	ExecutorLabelKey = "chaosmeta.io/executor"
+	// SidecarInjectionLabelKey set to "enabled" on a namespace injects the chaosmeta sidecar into its new pods,
+	// set to "disabled" on a pod skips the pod
+	SidecarInjectionLabelKey = "chaosmeta.io/sidecar-injection"
+	SidecarInjectionEnabled  = "enabled"
+	SidecarInjectionDisabled = "disabled"
+	// SidecarContainerName is the container of the chaosmeta sidecar, called by the "sidecar" executor
+	SidecarContainerName = "chaosmeta-sidecar"
+	// SidecarSecretAnnotationKey is the name of the Secret of the token and certificate of the grpc service of the
+	// sidecar. The Secret is created for every pod by the operator and is only referenced by the sidecar
+	SidecarSecretAnnotationKey = "chaosmeta.io/sidecar-secret"
+	// UserSource and ServiceAccountSource are the default sources, a creator such as the platform can set its own
+	UserSource           = "user"
+	ServiceAccountSource = "serviceaccount"
 )
 
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
@@ -37,14 +61,30 @@ const (
 	PodScopeType        ScopeType = "pod"
 	NodeScopeType       ScopeType = "node"
 	KubernetesScopeType ScopeType = "kubernetes"
+	// StatefulSetScopeType and DaemonSetScopeType select controllers by name or label, the targets are their pods
+	StatefulSetScopeType ScopeType = "statefulset"
+	DaemonSetScopeType   ScopeType = "daemonset"
+	// JobScopeType and CronJobScopeType target the running pods of the jobs, the new pods are always re-resolved during the experiment
+	JobScopeType     ScopeType = "job"
+	CronJobScopeType ScopeType = "cronjob"
+	// ClusterComponentScopeType targets the pods of a control-plane component, "experiment.target" is the component
+	ClusterComponentScopeType ScopeType = "clustercomponent"
+	// ServiceScopeType targets the ready backends in the endpoint slices of services, the backends added during
+	// the experiment are always re-resolved
+	ServiceScopeType ScopeType = "service"
 )
 
+// IsWorkload reports whether the scope selects pods through their controller, or the service in front of them
+func (s ScopeType) IsWorkload() bool {
+	return s == StatefulSetScopeType || s == DaemonSetScopeType || s == JobScopeType || s == CronJobScopeType || s == ServiceScopeType
+}
+
 // ExperimentSpec defines the desired state of Experiment
 type ExperimentSpec struct {
 	// INSERT ADDITIONAL SPEC FIELDS - desired state of cluster
 	// Important: Run "make" to regenerate code after modifying this file
 
-	// Scope Optional: node, pod. type of experiment object
+	// Scope Optional: node, pod, statefulset, daemonset, job, cronjob, service, kubernetes, clustercomponent. type of experiment object
 	Scope      ScopeType         `json:"scope"`
 	RangeMode  *RangeMode        `json:"rangeMode,omitempty"`
 	Experiment *ExperimentCommon `json:"experiment"`
@@ -53,6 +93,71 @@ type ExperimentSpec struct {
 
 	TargetPhase PhaseType `json:"targetPhase"`
 	//SubObj      bool      `json:"subObj"`
+
+	// Mutex Optional: at most one holder of a mutex is running at a time, the others wait in creation order
+	Mutex *MutexSpec `json:"mutex,omitempty"`
+	// Snapshot Optional: capture a lightweight snapshot of each target right before injection and right after recovery
+	Snapshot bool `json:"snapshot,omitempty"`
+	// Reresolve Optional: resolve the selector again on an interval in the inject phase, and inject into the new pods
+	Reresolve *ReresolveSpec `json:"reresolve,omitempty"`
+	// Schedule Optional: the experiment is a template, a child experiment is created from it on every cron time.
+	// The duration of the experiment is required to be more than 0
+	Schedule *ScheduleSpec `json:"schedule,omitempty"`
+	// Paused Optional: recover all the targets but keep the experiment, and inject again for the rest of the duration when unset
+	Paused bool `json:"paused,omitempty"`
+	// TTLSecondsAfterFinished Optional: the experiment is deleted once it is finished for the seconds, never deleted if not set
+	TTLSecondsAfterFinished *int32 `json:"ttlSecondsAfterFinished,omitempty"`
+	// RetryPolicy Optional: retry the failed inject call of a target before it is marked as failed
+	RetryPolicy *RetryPolicy `json:"retryPolicy,omitempty"`
+	// FailurePolicy Optional: continue、abort、rollback. what to do with the rest of targets when some targets fail to inject, default continue
+	FailurePolicy FailurePolicyType `json:"failurePolicy,omitempty"`
+	// Preflight Optional: check the capability of chaosmetad on every target before injecting any of them,
+	// the experiment fails fast if any target is not ready
+	Preflight bool `json:"preflight,omitempty"`
+	// Cluster Optional: the name of a cluster in the ClusterRegistry, the targets are selected and injected in it.
+	// Empty is the cluster of the operator
+	Cluster string `json:"cluster,omitempty"`
+	// DependsOn Optional: the names of experiments in the same namespace, the experiment starts only after all of them
+	// succeed in their target phase, and fails if any of them finishes without success, if they form a cycle,
+	// or if they have not succeeded within the wait timeout of the operator
+	DependsOn []string `json:"dependsOn,omitempty"`
+}
+
+type FailurePolicyType string
+
+const (
+	// ContinueFailurePolicyType keeps injecting the rest of targets, the experiment is part success
+	ContinueFailurePolicyType FailurePolicyType = "continue"
+	// AbortFailurePolicyType stops injecting the targets not injected yet, the injected targets are kept until recovered
+	AbortFailurePolicyType FailurePolicyType = "abort"
+	// RollbackFailurePolicyType stops injecting like abort, and recovers the injected targets at once
+	RollbackFailurePolicyType FailurePolicyType = "rollback"
+)
+
+type RetryPolicy struct {
+	// MaxRetries is the max count of retries of one target after its first inject call
+	MaxRetries int32 `json:"maxRetries"`
+	// Backoff Optional: the wait before the first retry, doubled for every next retry up to 5m, same format as duration, default 5s
+	Backoff string `json:"backoff,omitempty"`
+}
+
+type ScheduleSpec struct {
+	// Cron is a standard cron expression with 5 fields, e.g. "0 2 * * *"
+	Cron string `json:"cron"`
+	// HistoryLimit Optional: how many finished child experiments are kept, default 3
+	HistoryLimit *int32 `json:"historyLimit,omitempty"`
+}
+
+type ReresolveSpec struct {
+	// Interval Optional: same format as the duration, e.g. 30s, 1m. default 30s
+	Interval string `json:"interval,omitempty"`
+}
+
+type MutexSpec struct {
+	Name string `json:"name"`
+	// Holder experiments with the same holder share the mutex, such as the fault nodes of one platform experiment.
+	// default the experiment itself
+	Holder string `json:"holder,omitempty"`
 }
 
 type PhaseType string
@@ -83,6 +188,41 @@ type ExperimentStatus struct {
 	Detail     ExperimentDetail `json:"detail"`
 	CreateTime string           `json:"createTime"`
 	UpdateTime string           `json:"updateTime"`
+	// Selection is only recorded when the range mode picks a subset of the matched targets
+	Selection *RangeSelection `json:"selection,omitempty"`
+	// LastResolveTime is when the selector is resolved last time, only for the experiments re-resolving targets
+	LastResolveTime string `json:"lastResolveTime,omitempty"`
+	// LastScheduleTime and NextScheduleTime are only recorded for the experiments with a schedule
+	LastScheduleTime string `json:"lastScheduleTime,omitempty"`
+	NextScheduleTime string `json:"nextScheduleTime,omitempty"`
+	// Paused means the recover phase is to pause the experiment, it goes back to the inject phase when resumed
+	Paused bool `json:"paused,omitempty"`
+	// ResumeTime is when the experiment is resumed last time
+	ResumeTime string `json:"resumeTime,omitempty"`
+	// FinishTime is when the experiment is finished, see IsFinished
+	FinishTime string `json:"finishTime,omitempty"`
+	// Conditions are the standard view of the experiment for automation: Selected, PreflightChecked, Injected, Recovered
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+const (
+	// SelectedConditionType is true when the targets are selected
+	SelectedConditionType = "Selected"
+	// PreflightCheckedConditionType is true when all the targets pass the preflight check, only set with "preflight"
+	PreflightCheckedConditionType = "PreflightChecked"
+	// InjectedConditionType is true when the inject phase is over with at least one target injected
+	InjectedConditionType = "Injected"
+	// RecoveredConditionType is true when all the targets are recovered
+	RecoveredConditionType = "Recovered"
+)
+
+// IsFinished reports whether the recover phase is over, a paused experiment is not finished
+func (in *ExperimentStatus) IsFinished() bool {
+	return in.Phase == RecoverPhaseType && !in.Paused &&
+		(in.Status == SuccessStatusType || in.Status == FailedStatusType || in.Status == PartSuccessStatusType)
 }
 
 //+kubebuilder:object:root=true
@@ -122,13 +262,78 @@ type RangeMode struct {
 	// Type Optional: all、percent、count
 	Type  RangeType `json:"type"`
 	Value int       `json:"value,omitempty"`
+	// Seed Optional: the same seed always picks the same targets from the same candidates, a random seed is used when empty
+	Seed *int64 `json:"seed,omitempty"`
+	// Spread Optional: limit the targets picked in each node or zone, also works with type all
+	Spread *SpreadConstraint `json:"spread,omitempty"`
+}
+
+type TopologyKeyType string
+
+const (
+	NodeTopologyKey TopologyKeyType = "node"
+	ZoneTopologyKey TopologyKeyType = "zone"
+)
+
+// SpreadConstraint makes sure an experiment can not take out all the replicas in one node or zone,
+// fewer targets than the range mode asks for are picked when the domains are not enough
+type SpreadConstraint struct {
+	// TopologyKey Optional: node, zone. zone is the label "topology.kubernetes.io/zone" of the node
+	TopologyKey TopologyKeyType `json:"topologyKey"`
+	// MaxPerDomain is the max count of targets in one node or zone
+	MaxPerDomain int `json:"maxPerDomain"`
+}
+
+// RangeSelection records how the range mode picked the targets, set the seed to the spec to reproduce the selection
+type RangeSelection struct {
+	Seed           int64 `json:"seed"`
+	CandidateCount int   `json:"candidateCount"`
+	SelectedCount  int   `json:"selectedCount"`
 }
 
 type SelectorUnit struct {
-	Namespace string            `json:"namespace,omitempty"`
-	Name      []string          `json:"name,omitempty"`
-	IP        []string          `json:"ip,omitempty"`
-	Label     map[string]string `json:"label,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+	// NamespaceSelector Optional: select the namespaces by label instead of "namespace", the unit is resolved in each of them
+	NamespaceSelector map[string]string `json:"namespaceSelector,omitempty"`
+	Name              []string          `json:"name,omitempty"`
+	IP                []string          `json:"ip,omitempty"`
+	Label             map[string]string `json:"label,omitempty"`
+	// Owner Optional: only for scope pod, select the pods controlled by the owner, directly or through a ReplicaSet or Job
+	Owner *OwnerSelector `json:"owner,omitempty"`
+	// Field Optional: filter the matched pods or nodes by their status and topology
+	Field *FieldSelector `json:"field,omitempty"`
+	// ExcludeLabel Optional: drop the pods or nodes having all of these labels from the matched ones
+	ExcludeLabel map[string]string `json:"excludeLabel,omitempty"`
+	// ExcludeNames Optional: drop the pods or nodes with these names from the matched ones
+	ExcludeNames []string `json:"excludeNames,omitempty"`
+}
+
+// OwnerSelector selects pods by the controller owning them, so the selection keeps working when a rollout changes pod labels
+type OwnerSelector struct {
+	// Kind Optional: ReplicaSet, Deployment, StatefulSet, DaemonSet, Job, CronJob
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+}
+
+var OwnerKinds = []string{"ReplicaSet", "Deployment", "StatefulSet", "DaemonSet", "Job", "CronJob"}
+
+var (
+	PodPhases  = []string{"Pending", "Running", "Succeeded", "Failed", "Unknown"}
+	QOSClasses = []string{"Guaranteed", "Burstable", "BestEffort"}
+)
+
+// FieldSelector keeps the objects meeting all the provided conditions, only "zone" is supported in scope node
+type FieldSelector struct {
+	// Phase Optional: Pending, Running, Succeeded, Failed, Unknown
+	Phase []string `json:"phase,omitempty"`
+	// QOSClass Optional: Guaranteed, Burstable, BestEffort
+	QOSClass []string `json:"qosClass,omitempty"`
+	// MinRestartCount Optional: lower bound of the restart count summed over the containers of a pod
+	MinRestartCount *int32 `json:"minRestartCount,omitempty"`
+	// MaxRestartCount Optional: upper bound of the restart count summed over the containers of a pod
+	MaxRestartCount *int32 `json:"maxRestartCount,omitempty"`
+	// Zone Optional: value of the label "topology.kubernetes.io/zone" of the node
+	Zone []string `json:"zone,omitempty"`
 }
 
 //type TargetType string
@@ -140,6 +345,9 @@ type ExperimentCommon struct {
 	Target   string     `json:"target"`
 	Fault    string     `json:"fault"`
 	Args     []ArgsUnit `json:"args,omitempty"`
+	// Executor Optional: the registered remote executor of the targets, such as agent, daemonset, grpc, ssh, ephemeral, sidecar.
+	// Default is the mode in the config of operator
+	Executor string `json:"executor,omitempty"`
 }
 
 type VType string
@@ -169,6 +377,101 @@ type ExperimentDetailUnit struct {
 	StartTime  string     `json:"startTime,omitempty"`
 	UpdateTime string     `json:"updateTime,omitempty"`
 	Backup     string     `json:"backup,omitempty"`
+	// Latency is only recorded in the inject phase
+	Latency *InjectLatency `json:"latency,omitempty"`
+	// Snapshot is taken right before injection in the inject phase, and right after recovery in the recover phase
+	Snapshot *EnvSnapshot `json:"snapshot,omitempty"`
+	// Error is the machine-readable cause of a failed target, the free-text detail stays in Message
+	Error *ErrorInfo `json:"error,omitempty"`
+	// ReplacedBy is the target re-resolved in place of this one after its pod was gone
+	ReplacedBy string `json:"replacedBy,omitempty"`
+	// Lifecycle is only recorded when the targets are re-resolved during the experiment
+	Lifecycle *TargetLifecycle `json:"lifecycle,omitempty"`
+	// InjectAttempts is persisted before every inject call, a target attempted before is queried first,
+	// so that an injection in flight when the leader of operator fails over is adopted instead of injected again
+	InjectAttempts int32 `json:"injectAttempts,omitempty"`
+	// NextRetryTime is set when the failed inject call is retried by the retry policy, the target waits until then
+	NextRetryTime string `json:"nextRetryTime,omitempty"`
+	// EndTime is when the target turns to a final status in the phase, it is cleared when the target is retried
+	EndTime string `json:"endTime,omitempty"`
+	// LastError is the message of the latest failure of the target, it is kept after the target is retried
+	LastError string `json:"lastError,omitempty"`
+	// Executor is the remote executor selected by the label "chaosmeta.io/executor" of the target when it is resolved
+	Executor string `json:"executor,omitempty"`
+	// Preflight is the result of the preflight check, only in the inject phase
+	Preflight *PreflightResult `json:"preflight,omitempty"`
+	// Resolved is what the target was when it is selected, it is kept for review after the pod or container is gone
+	Resolved *ResolvedTarget `json:"resolved,omitempty"`
+}
+
+type ResolvedTarget struct {
+	PodUID           string `json:"podUID,omitempty"`
+	PodIP            string `json:"podIP,omitempty"`
+	NodeName         string `json:"nodeName,omitempty"`
+	NodeIP           string `json:"nodeIP,omitempty"`
+	ContainerName    string `json:"containerName,omitempty"`
+	ContainerID      string `json:"containerID,omitempty"`
+	ContainerRuntime string `json:"containerRuntime,omitempty"`
+	ContainerImage   string `json:"containerImage,omitempty"`
+	ResolvedTime     string `json:"resolvedTime"`
+}
+
+type PreflightResult struct {
+	Passed bool `json:"passed"`
+	// Reasons are why the fault can not be injected into the target
+	Reasons   []string `json:"reasons,omitempty"`
+	CheckTime string   `json:"checkTime"`
+}
+
+// TargetLifecycle records when a target joins and leaves an experiment
+type TargetLifecycle struct {
+	// Source Optional: initial, reresolved
+	Source       TargetSourceType `json:"source"`
+	ResolvedTime string           `json:"resolvedTime"`
+	// GoneTime is when the pod of the target is found gone
+	GoneTime string `json:"goneTime,omitempty"`
+}
+
+type TargetSourceType string
+
+const (
+	InitialTargetSource    TargetSourceType = "initial"
+	ReresolvedTargetSource TargetSourceType = "reresolved"
+)
+
+// ErrorInfo is a structured error shared by chaosmetad, the operator and the platform
+type ErrorInfo struct {
+	// Code is a stable name of the failure cause, e.g. "BadArgs", "InjectFailed", "TargetNotFound"
+	Code string `json:"code"`
+	// Category groups codes, e.g. "InvalidArgument", "NotFound", "Unavailable", "Timeout", "ExecutionFailed", "Internal"
+	Category string `json:"category"`
+	// Component is where the error comes from, e.g. "chaosmetad", "operator"
+	Component string `json:"component"`
+	Retryable bool   `json:"retryable,omitempty"`
+}
+
+// EnvSnapshot is the system state of a target gathered by the agent, a failed item is recorded in Errors only
+type EnvSnapshot struct {
+	Time         string   `json:"time,omitempty"`
+	LoadAvg      string   `json:"loadAvg,omitempty"`
+	MemLimitKB   int64    `json:"memLimitKB,omitempty"`
+	MemUsageKB   int64    `json:"memUsageKB,omitempty"`
+	Connections  int      `json:"connections,omitempty"`
+	TopProcesses []string `json:"topProcesses,omitempty"`
+	Errors       []string `json:"errors,omitempty"`
+}
+
+// InjectLatency is the duration breakdown of injecting a target, diagnostics are gathered when the total exceeds the SLO
+type InjectLatency struct {
+	GetObjectDuration string `json:"getObjectDuration,omitempty"`
+	ExecDuration      string `json:"execDuration,omitempty"`
+	TotalDuration     string `json:"totalDuration,omitempty"`
+	ExceedSLO         bool   `json:"exceedSLO,omitempty"`
+
+	AgentVersion        string `json:"agentVersion,omitempty"`
+	AgentResponseTime   string `json:"agentResponseTime,omitempty"`
+	RuntimeResponseTime string `json:"runtimeResponseTime,omitempty"`
+	DiagnoseMessage     string `json:"diagnoseMessage,omitempty"`
 }
 
 type CloudTargetType string
@@ -183,3 +486,29 @@ const (
 	NamespaceCloudTarget   CloudTargetType = "namespace"
 	JobCloudTarget         CloudTargetType = "job"
 )
+
+// ClusterComponentType is the target of scope clustercomponent, each component supports one fault
+type ClusterComponentType string
+
+const (
+	APIServerComponent ClusterComponentType = "apiserver"
+	EtcdComponent      ClusterComponentType = "etcd"
+	CoreDNSComponent   ClusterComponentType = "coredns"
+
+	// APIServerDelayFault delays the responses of kube-apiserver
+	APIServerDelayFault = "delay"
+	// EtcdIsolateFault drops the traffic of an etcd member to its peers
+	EtcdIsolateFault = "isolate"
+	// CoreDNSFailureFault stops the coredns process, so its pod stops answering
+	CoreDNSFailureFault = "failure"
+
+	MaxClusterComponentDuration = 30 * time.Minute
+	MaxAPIServerLatency         = 5 * time.Second
+)
+
+// ClusterComponentFaults is the only fault supported by each component
+var ClusterComponentFaults = map[ClusterComponentType]string{
+	APIServerComponent: APIServerDelayFault,
+	EtcdComponent:      EtcdIsolateFault,
+	CoreDNSComponent:   CoreDNSFailureFault,
+}
@@ -17,12 +17,16 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"context"
 	"fmt"
+	"github.com/robfig/cron"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"reflect"
 	ctrl "sigs.k8s.io/controller-runtime"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 	"strconv"
 	"strings"
 	"time"
@@ -31,14 +35,60 @@ import (
 // log is for logging in this package.
 var (
 	experimentlog = logf.Log.WithName("experiment-resource")
+
+	// defaultDuration fills an empty duration, and a longer duration is capped to maxDuration. empty means not set
+	defaultDuration, maxDuration string
 )
 
+// SetDurationDefaults is called on start before the webhook is served
+func SetDurationDefaults(defaultD, maxD string) error {
+	for _, d := range []string{defaultD, maxD} {
+		if d == "" {
+			continue
+		}
+
+		if _, err := ConvertDuration(d); err != nil {
+			return fmt.Errorf("duration %s is invalid: %s", d, err.Error())
+		}
+	}
+
+	defaultDuration, maxDuration = defaultD, maxD
+	return nil
+}
+
 func (r *Experiment) SetupWebhookWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(r).
+		WithDefaulter(&experimentDefaulter{}).
 		Complete()
 }
 
+// experimentDefaulter adds the labels from the admission request on top of Experiment.Default
+type experimentDefaulter struct{}
+
+func (d *experimentDefaulter) Default(ctx context.Context, obj runtime.Object) error {
+	r, ok := obj.(*Experiment)
+	if !ok {
+		return fmt.Errorf("expected an Experiment but got a %T", obj)
+	}
+
+	r.Default()
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil || r.Status.Phase != "" {
+		return nil
+	}
+
+	source := UserSource
+	if strings.HasPrefix(req.UserInfo.Username, "system:serviceaccount:") {
+		source = ServiceAccountSource
+	}
+
+	// the creator is always taken from the request, so it can not be forged by the label in the object
+	r.setLabel(CreatorLabelKey, toLabelValue(req.UserInfo.Username))
+	r.setDefaultLabel(SourceLabelKey, source)
+	return nil
+}
+
 //+kubebuilder:webhook:path=/mutate-chaosmeta-io-v1alpha1-experiment,mutating=true,failurePolicy=fail,sideEffects=None,groups=chaosmeta.io,resources=experiments,verbs=create,versions=v1alpha1,name=mexperiment.kb.io,admissionReviewVersions=v1
 
 var _ webhook.Defaulter = &Experiment{}
@@ -62,7 +112,22 @@ func (r *Experiment) Default() {
 		r.ObjectMeta.Finalizers = append(r.ObjectMeta.Finalizers, FinalizerName)
 	}
 
-	if r.Spec.Scope == PodScopeType || (r.Spec.Scope == KubernetesScopeType && strings.Index(r.Spec.Experiment.Target, "container") >= 0) {
+	if r.Spec.RangeMode == nil {
+		r.Spec.RangeMode = &RangeMode{Type: AllRangeType}
+	}
+
+	if r.Spec.Experiment != nil {
+		if r.Spec.Experiment.Duration == "" {
+			r.Spec.Experiment.Duration = defaultDuration
+		}
+
+		if isDurationExceed(r.Spec.Experiment.Duration, maxDuration) {
+			experimentlog.Info("cap duration", "name", r.Name, "duration", r.Spec.Experiment.Duration, "maxDuration", maxDuration)
+			r.Spec.Experiment.Duration = maxDuration
+		}
+	}
+
+	if r.Spec.Scope == PodScopeType || r.Spec.Scope.IsWorkload() || (r.Spec.Scope == KubernetesScopeType && strings.Index(r.Spec.Experiment.Target, "container") >= 0) {
 		var i int
 		for i = 0; i < len(r.Spec.Experiment.Args); i++ {
 			if r.Spec.Experiment.Args[i].Key == ContainerKey {
@@ -96,14 +161,77 @@ func (r *Experiment) ValidateCreate() error {
 		return fmt.Errorf("experiment's duration is invalid: %s", err.Error())
 	}
 
-	if r.Spec.Scope != PodScopeType && r.Spec.Scope != NodeScopeType && r.Spec.Scope != KubernetesScopeType {
-		return fmt.Errorf("\"scope\" not support: %s, only support: %s, %s, %s", r.Spec.Scope, PodScopeType, NodeScopeType, KubernetesScopeType)
+	if r.Spec.Scope != PodScopeType && r.Spec.Scope != NodeScopeType && r.Spec.Scope != KubernetesScopeType && !r.Spec.Scope.IsWorkload() &&
+		r.Spec.Scope != ClusterComponentScopeType {
+		return fmt.Errorf("\"scope\" not support: %s, only support: %s, %s, %s, %s, %s, %s, %s, %s, %s", r.Spec.Scope, PodScopeType, NodeScopeType,
+			StatefulSetScopeType, DaemonSetScopeType, JobScopeType, CronJobScopeType, ServiceScopeType, KubernetesScopeType, ClusterComponentScopeType)
 	}
 
 	if r.Spec.TargetPhase != InjectPhaseType {
 		return fmt.Errorf("initial \"targetPhase\" only support: %s", InjectPhaseType)
 	}
 
+	if r.Spec.Reresolve != nil {
+		if r.Spec.Scope != PodScopeType && !r.Spec.Scope.IsWorkload() {
+			return fmt.Errorf("\"reresolve\" is not supported in scope %s", r.Spec.Scope)
+		}
+
+		if r.Spec.Reresolve.Interval != "" {
+			if _, err := ConvertDuration(r.Spec.Reresolve.Interval); err != nil {
+				return fmt.Errorf("\"reresolve.interval\" is invalid: %s", err.Error())
+			}
+		}
+	}
+
+	if r.Spec.Schedule != nil {
+		if _, err := cron.ParseStandard(r.Spec.Schedule.Cron); err != nil {
+			return fmt.Errorf("\"schedule.cron\" is invalid: %s", err.Error())
+		}
+
+		// a child without a duration is never recovered, and it blocks the following cron times
+		if duration, _ := ConvertDuration(r.Spec.Experiment.Duration); duration <= 0 {
+			return fmt.Errorf("experiment's duration should be more than 0 with \"schedule\"")
+		}
+
+		if r.Spec.Schedule.HistoryLimit != nil && *r.Spec.Schedule.HistoryLimit < 0 {
+			return fmt.Errorf("\"schedule.historyLimit\" should not be less than 0")
+		}
+
+		if errs := validation.IsValidLabelValue(r.Name); len(errs) != 0 {
+			return fmt.Errorf("experiment's name is used as the value of label %s in child experiments: %s", ScheduledByLabelKey, strings.Join(errs, ", "))
+		}
+	}
+
+	if r.Spec.TTLSecondsAfterFinished != nil && *r.Spec.TTLSecondsAfterFinished < 0 {
+		return fmt.Errorf("\"ttlSecondsAfterFinished\" should not be less than 0")
+	}
+
+	if r.Spec.RetryPolicy != nil {
+		if r.Spec.RetryPolicy.MaxRetries < 0 {
+			return fmt.Errorf("\"retryPolicy.maxRetries\" should not be less than 0")
+		}
+
+		if r.Spec.RetryPolicy.Backoff != "" {
+			if _, err := ConvertDuration(r.Spec.RetryPolicy.Backoff); err != nil {
+				return fmt.Errorf("\"retryPolicy.backoff\" is invalid: %s", err.Error())
+			}
+		}
+	}
+
+	for _, name := range r.Spec.DependsOn {
+		if name == "" {
+			return fmt.Errorf("\"dependsOn\" should not contain an empty name")
+		}
+
+		if name == r.Name {
+			return fmt.Errorf("\"dependsOn\" should not contain the experiment itself")
+		}
+	}
+
+	if r.Spec.FailurePolicy != "" && r.Spec.FailurePolicy != ContinueFailurePolicyType && r.Spec.FailurePolicy != AbortFailurePolicyType && r.Spec.FailurePolicy != RollbackFailurePolicyType {
+		return fmt.Errorf("\"failurePolicy\" not support: %s, only support: %s, %s, %s", r.Spec.FailurePolicy, ContinueFailurePolicyType, AbortFailurePolicyType, RollbackFailurePolicyType)
+	}
+
 	if r.Spec.RangeMode != nil {
 		if r.Spec.RangeMode.Type != AllRangeType && r.Spec.RangeMode.Type != PercentRangeType && r.Spec.RangeMode.Type != CountRangeType {
 			return fmt.Errorf("\"rangeMode.type\" not support: %s, only support: %s, %s, %s", r.Spec.RangeMode.Type, AllRangeType, PercentRangeType, CountRangeType)
@@ -120,17 +248,59 @@ func (r *Experiment) ValidateCreate() error {
 				return fmt.Errorf("\"rangeMode.value\" should larger than 0")
 			}
 		}
+
+		if spread := r.Spec.RangeMode.Spread; spread != nil {
+			if r.Spec.Scope != PodScopeType && r.Spec.Scope != NodeScopeType && !r.Spec.Scope.IsWorkload() {
+				return fmt.Errorf("\"rangeMode.spread\" is not supported in scope %s", r.Spec.Scope)
+			}
+
+			if spread.TopologyKey != NodeTopologyKey && spread.TopologyKey != ZoneTopologyKey {
+				return fmt.Errorf("\"rangeMode.spread.topologyKey\" not support: %s, only support: %s, %s", spread.TopologyKey, NodeTopologyKey, ZoneTopologyKey)
+			}
+
+			if spread.MaxPerDomain <= 0 {
+				return fmt.Errorf("\"rangeMode.spread.maxPerDomain\" should larger than 0")
+			}
+		}
 	}
 
-	if len(r.Spec.Selector) == 0 && r.Spec.Scope != KubernetesScopeType {
+	if len(r.Spec.Selector) == 0 && r.Spec.Scope != KubernetesScopeType && r.Spec.Scope != ClusterComponentScopeType {
 		return fmt.Errorf("length of \"selector\" must not be 0")
 	}
 
+	for _, unitSelector := range r.Spec.Selector {
+		if unitSelector.Owner != nil && r.Spec.Scope != PodScopeType {
+			return fmt.Errorf("\"owner\" selector is only supported in scope %s", PodScopeType)
+		}
+
+		if err := validateFieldSelector(r.Spec.Scope, unitSelector.Field); err != nil {
+			return err
+		}
+	}
+
 	if r.Spec.Scope == PodScopeType {
 		for _, unitSelector := range r.Spec.Selector {
-			if unitSelector.Namespace == "" {
-				return fmt.Errorf("namespace in selector must not empty")
+			if err := validateSelectorNamespace(unitSelector); err != nil {
+				return err
 			}
+
+			if err := validateOwnerSelector(unitSelector); err != nil {
+				return err
+			}
+		}
+	} else if r.Spec.Scope.IsWorkload() {
+		for _, unitSelector := range r.Spec.Selector {
+			if err := validateSelectorNamespace(unitSelector); err != nil {
+				return err
+			}
+
+			if len(unitSelector.IP) != 0 {
+				return fmt.Errorf("\"ip\" selector is not supported in scope %s", r.Spec.Scope)
+			}
+		}
+	} else if r.Spec.Scope == ClusterComponentScopeType {
+		if err := validateClusterComponent(&r.Spec); err != nil {
+			return err
 		}
 	} else if r.Spec.Scope == NodeScopeType {
 		for _, unitSelector := range r.Spec.Selector {
@@ -174,11 +344,22 @@ func (r *Experiment) ValidateUpdate(old runtime.Object) error {
 		!reflect.DeepEqual(r.Spec.Selector, oldExp.Spec.Selector) ||
 		!reflect.DeepEqual(r.Spec.RangeMode, oldExp.Spec.RangeMode) ||
 		r.Spec.Scope != oldExp.Spec.Scope {
-		return fmt.Errorf("spec only support update \"targetPhase\"")
+		return fmt.Errorf("spec only support update \"targetPhase\" and \"paused\"")
 	}
 
-	if !(oldExp.Status.Phase == InjectPhaseType && (oldExp.Status.Status == SuccessStatusType || oldExp.Status.Status == FailedStatusType || oldExp.Status.Status == PartSuccessStatusType)) {
-		return fmt.Errorf("only support update when \"status.phase == inject and status.status == success/failed/partSuccess\"")
+	if r.Spec.Paused != oldExp.Spec.Paused {
+		if oldExp.Status.Phase != InjectPhaseType && !oldExp.Status.Paused {
+			return fmt.Errorf("only support update \"paused\" when \"status.phase == inject\" or the experiment is paused")
+		}
+
+		if r.Spec.TargetPhase == oldExp.Spec.TargetPhase {
+			return nil
+		}
+	}
+
+	// a paused experiment has been recovered, so it can be ended at any time
+	if !oldExp.Status.Paused && !(oldExp.Status.Phase == InjectPhaseType && (oldExp.Status.Status == SuccessStatusType || oldExp.Status.Status == FailedStatusType || oldExp.Status.Status == PartSuccessStatusType)) {
+		return fmt.Errorf("only support update when \"status.phase == inject and status.status == success/failed/partSuccess\" or the experiment is paused")
 	}
 
 	if r.Spec.TargetPhase != RecoverPhaseType {
@@ -194,6 +375,196 @@ func (r *Experiment) ValidateDelete() error {
 	return nil
 }
 
+func validateSelectorNamespace(unitSelector SelectorUnit) error {
+	if unitSelector.Namespace == "" && len(unitSelector.NamespaceSelector) == 0 {
+		return fmt.Errorf("must provide one of \"namespace\"、\"namespaceSelector\" in selector")
+	}
+
+	if unitSelector.Namespace != "" && len(unitSelector.NamespaceSelector) != 0 {
+		return fmt.Errorf("can only provide one of \"namespace\"、\"namespaceSelector\" in one selector unit")
+	}
+
+	return nil
+}
+
+func validateOwnerSelector(unitSelector SelectorUnit) error {
+	owner := unitSelector.Owner
+	if owner == nil {
+		return nil
+	}
+
+	// pods are selected by one of name, owner and label, they are not combined
+	if len(unitSelector.Name) != 0 || len(unitSelector.Label) != 0 {
+		return fmt.Errorf("\"owner\" in selector can not be used with \"name\" or \"label\"")
+	}
+
+	if owner.Name == "" {
+		return fmt.Errorf("\"owner.name\" in selector must not empty")
+	}
+
+	if isOneOf(owner.Kind, OwnerKinds) {
+		return nil
+	}
+
+	return fmt.Errorf("\"owner.kind\" not support: %s, only support: %s", owner.Kind, strings.Join(OwnerKinds, ", "))
+}
+
+func validateFieldSelector(scope ScopeType, field *FieldSelector) error {
+	if field == nil {
+		return nil
+	}
+
+	if scope != PodScopeType && scope != NodeScopeType && !scope.IsWorkload() {
+		return fmt.Errorf("\"field\" selector is not supported in scope %s", scope)
+	}
+
+	if scope == NodeScopeType && (len(field.Phase) != 0 || len(field.QOSClass) != 0 || field.MinRestartCount != nil || field.MaxRestartCount != nil) {
+		return fmt.Errorf("only \"field.zone\" is supported in scope %s", NodeScopeType)
+	}
+
+	for _, unitPhase := range field.Phase {
+		if !isOneOf(unitPhase, PodPhases) {
+			return fmt.Errorf("\"field.phase\" not support: %s, only support: %s", unitPhase, strings.Join(PodPhases, ", "))
+		}
+	}
+
+	for _, unitQOS := range field.QOSClass {
+		if !isOneOf(unitQOS, QOSClasses) {
+			return fmt.Errorf("\"field.qosClass\" not support: %s, only support: %s", unitQOS, strings.Join(QOSClasses, ", "))
+		}
+	}
+
+	if (field.MinRestartCount != nil && *field.MinRestartCount < 0) || (field.MaxRestartCount != nil && *field.MaxRestartCount < 0) {
+		return fmt.Errorf("restart count in \"field\" must not be negative")
+	}
+
+	if field.MinRestartCount != nil && field.MaxRestartCount != nil && *field.MinRestartCount > *field.MaxRestartCount {
+		return fmt.Errorf("\"field.minRestartCount\" must not be larger than \"field.maxRestartCount\"")
+	}
+
+	return nil
+}
+
+func (r *Experiment) setDefaultLabel(key, value string) {
+	if value == "" {
+		return
+	}
+
+	if r.ObjectMeta.Labels == nil {
+		r.ObjectMeta.Labels = make(map[string]string)
+	}
+
+	if _, ok := r.ObjectMeta.Labels[key]; !ok {
+		r.ObjectMeta.Labels[key] = value
+	}
+}
+
+func (r *Experiment) setLabel(key, value string) {
+	if r.ObjectMeta.Labels == nil {
+		r.ObjectMeta.Labels = make(map[string]string)
+	}
+
+	r.ObjectMeta.Labels[key] = value
+}
+
+// toLabelValue replaces the characters not allowed in a label value, e.g. the ":" of "system:serviceaccount:ns:name"
+func toLabelValue(value string) string {
+	result := []byte(value)
+	for i := range result {
+		c := result[i]
+		if !(('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9') || c == '-' || c == '_' || c == '.') {
+			result[i] = '.'
+		}
+	}
+
+	value = string(result)
+	if len(value) > validation.LabelValueMaxLength {
+		value = value[:validation.LabelValueMaxLength]
+	}
+
+	return strings.Trim(value, "-_.")
+}
+
+// isDurationExceed an invalid duration is left to the validating webhook
+func isDurationExceed(duration, max string) bool {
+	if duration == "" || max == "" {
+		return false
+	}
+
+	d, err := ConvertDuration(duration)
+	if err != nil {
+		return false
+	}
+
+	m, err := ConvertDuration(max)
+	return err == nil && d > m
+}
+
+// validateClusterComponent holds the guardrails of control-plane chaos: a short duration, a bounded apiserver latency,
+// one etcd member at a time and at least one coredns pod left
+func validateClusterComponent(spec *ExperimentSpec) error {
+	duration, _ := ConvertDuration(spec.Experiment.Duration)
+	if duration > MaxClusterComponentDuration {
+		return fmt.Errorf("duration of scope %s must not be longer than %s", ClusterComponentScopeType, MaxClusterComponentDuration)
+	}
+
+	component := ClusterComponentType(spec.Experiment.Target)
+	fault, ok := ClusterComponentFaults[component]
+	if !ok {
+		return fmt.Errorf("\"experiment.target\" of scope %s not support: %s, only support: %s, %s, %s", ClusterComponentScopeType,
+			component, APIServerComponent, EtcdComponent, CoreDNSComponent)
+	}
+
+	if spec.Experiment.Fault != fault {
+		return fmt.Errorf("\"experiment.fault\" of %s only support: %s", component, fault)
+	}
+
+	for _, unitSelector := range spec.Selector {
+		if len(unitSelector.Label) != 0 || len(unitSelector.IP) != 0 || len(unitSelector.NamespaceSelector) != 0 || unitSelector.Owner != nil {
+			return fmt.Errorf("only \"namespace\" and \"name\" selector are supported in scope %s", ClusterComponentScopeType)
+		}
+	}
+
+	switch component {
+	case APIServerComponent:
+		latency := ""
+		for _, unitArgs := range spec.Experiment.Args {
+			if unitArgs.Key == "latency" {
+				latency = unitArgs.Value
+			}
+		}
+
+		d, err := time.ParseDuration(latency)
+		if err != nil {
+			return fmt.Errorf("\"latency\" of %s must be a duration with unit, such as 200ms: %s", component, latency)
+		}
+
+		if d <= 0 || d > MaxAPIServerLatency {
+			return fmt.Errorf("\"latency\" of %s should be in (0,%s]", component, MaxAPIServerLatency)
+		}
+	case EtcdComponent:
+		if spec.RangeMode == nil || spec.RangeMode.Type != CountRangeType || spec.RangeMode.Value != 1 {
+			return fmt.Errorf("%s only support to isolate one member at a time, \"rangeMode\" must be count 1", component)
+		}
+	case CoreDNSComponent:
+		if spec.RangeMode == nil || spec.RangeMode.Type == AllRangeType || (spec.RangeMode.Type == PercentRangeType && spec.RangeMode.Value >= 100) {
+			return fmt.Errorf("at least one pod of %s must be left, \"rangeMode\" must select part of the pods", component)
+		}
+	}
+
+	return nil
+}
+
+func isOneOf(value string, list []string) bool {
+	for _, unit := range list {
+		if unit == value {
+			return true
+		}
+	}
+
+	return false
+}
+
 func ConvertDuration(d string) (time.Duration, error) {
 	unit := d[len(d)-1]
 	var value string
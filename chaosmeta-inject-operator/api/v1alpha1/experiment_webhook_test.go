@@ -17,6 +17,11 @@
 package v1alpha1
 
 import (
+	"context"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 	"testing"
 	"time"
 )
@@ -83,3 +88,162 @@ func TestConvertDuration(t *testing.T) {
 		})
 	}
 }
+
+func TestExperimentDefaulter_Default(t *testing.T) {
+	defer func() {
+		defaultDuration, maxDuration = "", ""
+	}()
+	if err := SetDurationDefaults("10m", "1h"); err != nil {
+		t.Fatalf("SetDurationDefaults() error = %v", err)
+	}
+
+	exp := &Experiment{
+		Spec: ExperimentSpec{
+			Scope: PodScopeType,
+			Experiment: &ExperimentCommon{
+				Target: "cpu",
+				Fault:  "burn",
+			},
+			TargetPhase: InjectPhaseType,
+		},
+	}
+	ctx := admission.NewContextWithRequest(context.Background(), admission.Request{
+		AdmissionRequest: admissionv1.AdmissionRequest{
+			UserInfo: authenticationv1.UserInfo{Username: "system:serviceaccount:chaosmeta:platform"},
+		},
+	})
+
+	if err := (&experimentDefaulter{}).Default(ctx, exp); err != nil {
+		t.Fatalf("Default() error = %v", err)
+	}
+
+	if exp.Spec.RangeMode == nil || exp.Spec.RangeMode.Type != AllRangeType {
+		t.Errorf("Default() rangeMode = %v, want type %s", exp.Spec.RangeMode, AllRangeType)
+	}
+	if exp.Spec.Experiment.Duration != "10m" {
+		t.Errorf("Default() duration = %s, want 10m", exp.Spec.Experiment.Duration)
+	}
+	if len(exp.Spec.Experiment.Args) != 1 || exp.Spec.Experiment.Args[0].Value != FirstContainer {
+		t.Errorf("Default() args = %v, want container %s", exp.Spec.Experiment.Args, FirstContainer)
+	}
+	if exp.Labels[CreatorLabelKey] != "system.serviceaccount.chaosmeta.platform" || exp.Labels[SourceLabelKey] != ServiceAccountSource {
+		t.Errorf("Default() labels = %v", exp.Labels)
+	}
+
+	// the duration is capped, the source label set by the creator is kept, but the creator label is overwritten
+	exp.Spec.Experiment.Duration, exp.Labels[SourceLabelKey], exp.Labels[CreatorLabelKey] = "2h", "platform", "admin"
+	if err := (&experimentDefaulter{}).Default(ctx, exp); err != nil {
+		t.Fatalf("Default() error = %v", err)
+	}
+	if exp.Spec.Experiment.Duration != "1h" || exp.Labels[SourceLabelKey] != "platform" {
+		t.Errorf("Default() duration = %s, source = %s", exp.Spec.Experiment.Duration, exp.Labels[SourceLabelKey])
+	}
+	if exp.Labels[CreatorLabelKey] != "system.serviceaccount.chaosmeta.platform" {
+		t.Errorf("Default() creator = %s, want the user of the request", exp.Labels[CreatorLabelKey])
+	}
+}
+
+func TestExperiment_ValidateUpdate_Paused(t *testing.T) {
+	newExp := func(phase PhaseType, status StatusType, paused bool) *Experiment {
+		return &Experiment{
+			Spec: ExperimentSpec{
+				Scope:       PodScopeType,
+				Experiment:  &ExperimentCommon{Duration: "10m", Target: "cpu", Fault: "burn"},
+				TargetPhase: InjectPhaseType,
+				Paused:      paused,
+			},
+			Status: ExperimentStatus{Phase: phase, Status: status, Paused: paused},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		old     *Experiment
+		update  func(exp *Experiment)
+		wantErr bool
+	}{
+		{
+			name:   "pause a running experiment",
+			old:    newExp(InjectPhaseType, RunningStatusType, false),
+			update: func(exp *Experiment) { exp.Spec.Paused = true },
+		},
+		{
+			name:   "resume a paused experiment",
+			old:    newExp(RecoverPhaseType, SuccessStatusType, true),
+			update: func(exp *Experiment) { exp.Spec.Paused = false },
+		},
+		{
+			name:   "end a paused experiment",
+			old:    newExp(RecoverPhaseType, RunningStatusType, true),
+			update: func(exp *Experiment) { exp.Spec.TargetPhase = RecoverPhaseType },
+		},
+		{
+			name:    "pause a recovered experiment",
+			old:     newExp(RecoverPhaseType, SuccessStatusType, false),
+			update:  func(exp *Experiment) { exp.Spec.Paused = true },
+			wantErr: true,
+		},
+		{
+			name: "pause with other spec changed",
+			old:  newExp(InjectPhaseType, SuccessStatusType, false),
+			update: func(exp *Experiment) {
+				exp.Spec.Paused = true
+				exp.Spec.Experiment.Duration = "20m"
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exp := tt.old.DeepCopy()
+			tt.update(exp)
+			if err := exp.ValidateUpdate(tt.old); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateUpdate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestExperiment_ValidateCreate_Schedule(t *testing.T) {
+	newExp := func(duration string) *Experiment {
+		return &Experiment{
+			ObjectMeta: metav1.ObjectMeta{Name: "nightly-cpu"},
+			Spec: ExperimentSpec{
+				Scope:       PodScopeType,
+				Experiment:  &ExperimentCommon{Duration: duration, Target: "cpu", Fault: "burn"},
+				Selector:    []SelectorUnit{{Namespace: "chaosmeta", Name: []string{"web-0"}}},
+				TargetPhase: InjectPhaseType,
+				Schedule:    &ScheduleSpec{Cron: "0 2 * * *"},
+			},
+		}
+	}
+
+	if err := newExp("10m").ValidateCreate(); err != nil {
+		t.Errorf("ValidateCreate() error = %v", err)
+	}
+
+	if err := newExp("0s").ValidateCreate(); err == nil {
+		t.Errorf("ValidateCreate() should fail for a schedule without duration")
+	}
+}
+
+func TestValidateOwnerSelector(t *testing.T) {
+	owner := &OwnerSelector{Kind: "Deployment", Name: "web"}
+	tests := []struct {
+		name         string
+		unitSelector SelectorUnit
+		wantErr      bool
+	}{
+		{name: "owner only", unitSelector: SelectorUnit{Namespace: "ns", Owner: owner}, wantErr: false},
+		{name: "owner with label", unitSelector: SelectorUnit{Namespace: "ns", Owner: owner, Label: map[string]string{"app": "web"}}, wantErr: true},
+		{name: "owner with name", unitSelector: SelectorUnit{Namespace: "ns", Owner: owner, Name: []string{"web-0"}}, wantErr: true},
+		{name: "unsupported kind", unitSelector: SelectorUnit{Namespace: "ns", Owner: &OwnerSelector{Kind: "Pod", Name: "web"}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateOwnerSelector(tt.unitSelector); (err != nil) != tt.wantErr {
+				t.Errorf("validateOwnerSelector() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TargetQuerySpec is the target part of an experiment spec, it is resolved the same way as the experiment without injecting,
+// so the blast radius is previewed before the experiment is created
+type TargetQuerySpec struct {
+	// Scope node, pod, statefulset, daemonset, job, cronjob, service, kubernetes, clustercomponent. type of experiment object
+	Scope     ScopeType  `json:"scope"`
+	RangeMode *RangeMode `json:"rangeMode,omitempty"`
+	// Experiment the target and fault of the experiment, "containername" in args selects the container of pods
+	Experiment *ExperimentCommon `json:"experiment"`
+	// Selector The internal part of unit is "AND", and the external part is "OR" and de-duplication
+	Selector []SelectorUnit `json:"selector,omitempty"`
+	// Cluster Optional: the name of a cluster in the ClusterRegistry, empty is the cluster of the operator
+	Cluster string `json:"cluster,omitempty"`
+}
+
+// TargetQueryStatus is resolved once for every generation of the spec, update the spec to resolve again
+type TargetQueryStatus struct {
+	// Status success or failed
+	Status  StatusType `json:"status,omitempty"`
+	Message string     `json:"message,omitempty"`
+	// ObservedGeneration is the generation of the spec the targets are resolved from
+	ObservedGeneration int64  `json:"observedGeneration,omitempty"`
+	ResolvedTime       string `json:"resolvedTime,omitempty"`
+	// Selection is how the range mode picked the targets, the experiment picks others unless it has the same seed
+	Selection *RangeSelection `json:"selection,omitempty"`
+	Targets   []QueriedTarget `json:"targets,omitempty"`
+}
+
+type QueriedTarget struct {
+	// Name is the same as "injectObjectName" of the experiment
+	Name string `json:"name"`
+	// Executor is the remote executor selected by the label "chaosmeta.io/executor" of the target
+	Executor string          `json:"executor,omitempty"`
+	Resolved *ResolvedTarget `json:"resolved,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+
+// TargetQuery is the Schema for the targetqueries API
+type TargetQuery struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   TargetQuerySpec   `json:"spec,omitempty"`
+	Status TargetQueryStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// TargetQueryList contains a list of TargetQuery
+type TargetQueryList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []TargetQuery `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&TargetQuery{}, &TargetQueryList{})
+}
@@ -22,6 +22,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -40,6 +41,109 @@ func (in *ArgsUnit) DeepCopy() *ArgsUnit {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterRegistry) DeepCopyInto(out *ClusterRegistry) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterRegistry.
+func (in *ClusterRegistry) DeepCopy() *ClusterRegistry {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterRegistry)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterRegistry) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterRegistryList) DeepCopyInto(out *ClusterRegistryList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClusterRegistry, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterRegistryList.
+func (in *ClusterRegistryList) DeepCopy() *ClusterRegistryList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterRegistryList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterRegistryList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterRegistrySpec) DeepCopyInto(out *ClusterRegistrySpec) {
+	*out = *in
+	if in.Clusters != nil {
+		in, out := &in.Clusters, &out.Clusters
+		*out = make([]RegisteredCluster, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterRegistrySpec.
+func (in *ClusterRegistrySpec) DeepCopy() *ClusterRegistrySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterRegistrySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EnvSnapshot) DeepCopyInto(out *EnvSnapshot) {
+	*out = *in
+	if in.TopProcesses != nil {
+		in, out := &in.TopProcesses, &out.TopProcesses
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Errors != nil {
+		in, out := &in.Errors, &out.Errors
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnvSnapshot.
+func (in *EnvSnapshot) DeepCopy() *EnvSnapshot {
+	if in == nil {
+		return nil
+	}
+	out := new(EnvSnapshot)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Experiment) DeepCopyInto(out *Experiment) {
 	*out = *in
@@ -93,12 +197,16 @@ func (in *ExperimentDetail) DeepCopyInto(out *ExperimentDetail) {
 	if in.Inject != nil {
 		in, out := &in.Inject, &out.Inject
 		*out = make([]ExperimentDetailUnit, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	if in.Recover != nil {
 		in, out := &in.Recover, &out.Recover
 		*out = make([]ExperimentDetailUnit, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
@@ -115,6 +223,36 @@ func (in *ExperimentDetail) DeepCopy() *ExperimentDetail {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ExperimentDetailUnit) DeepCopyInto(out *ExperimentDetailUnit) {
 	*out = *in
+	if in.Latency != nil {
+		in, out := &in.Latency, &out.Latency
+		*out = new(InjectLatency)
+		**out = **in
+	}
+	if in.Snapshot != nil {
+		in, out := &in.Snapshot, &out.Snapshot
+		*out = new(EnvSnapshot)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Error != nil {
+		in, out := &in.Error, &out.Error
+		*out = new(ErrorInfo)
+		**out = **in
+	}
+	if in.Lifecycle != nil {
+		in, out := &in.Lifecycle, &out.Lifecycle
+		*out = new(TargetLifecycle)
+		**out = **in
+	}
+	if in.Preflight != nil {
+		in, out := &in.Preflight, &out.Preflight
+		*out = new(PreflightResult)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Resolved != nil {
+		in, out := &in.Resolved, &out.Resolved
+		*out = new(ResolvedTarget)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExperimentDetailUnit.
@@ -127,6 +265,21 @@ func (in *ExperimentDetailUnit) DeepCopy() *ExperimentDetailUnit {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ErrorInfo) DeepCopyInto(out *ErrorInfo) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ErrorInfo.
+func (in *ErrorInfo) DeepCopy() *ErrorInfo {
+	if in == nil {
+		return nil
+	}
+	out := new(ErrorInfo)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ExperimentList) DeepCopyInto(out *ExperimentList) {
 	*out = *in
@@ -165,7 +318,7 @@ func (in *ExperimentSpec) DeepCopyInto(out *ExperimentSpec) {
 	if in.RangeMode != nil {
 		in, out := &in.RangeMode, &out.RangeMode
 		*out = new(RangeMode)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.Experiment != nil {
 		in, out := &in.Experiment, &out.Experiment
@@ -179,6 +332,36 @@ func (in *ExperimentSpec) DeepCopyInto(out *ExperimentSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Mutex != nil {
+		in, out := &in.Mutex, &out.Mutex
+		*out = new(MutexSpec)
+		**out = **in
+	}
+	if in.Reresolve != nil {
+		in, out := &in.Reresolve, &out.Reresolve
+		*out = new(ReresolveSpec)
+		**out = **in
+	}
+	if in.Schedule != nil {
+		in, out := &in.Schedule, &out.Schedule
+		*out = new(ScheduleSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TTLSecondsAfterFinished != nil {
+		in, out := &in.TTLSecondsAfterFinished, &out.TTLSecondsAfterFinished
+		*out = new(int32)
+		**out = **in
+	}
+	if in.RetryPolicy != nil {
+		in, out := &in.RetryPolicy, &out.RetryPolicy
+		*out = new(RetryPolicy)
+		**out = **in
+	}
+	if in.DependsOn != nil {
+		in, out := &in.DependsOn, &out.DependsOn
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExperimentSpec.
@@ -195,6 +378,18 @@ func (in *ExperimentSpec) DeepCopy() *ExperimentSpec {
 func (in *ExperimentStatus) DeepCopyInto(out *ExperimentStatus) {
 	*out = *in
 	in.Detail.DeepCopyInto(&out.Detail)
+	if in.Selection != nil {
+		in, out := &in.Selection, &out.Selection
+		*out = new(RangeSelection)
+		**out = **in
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExperimentStatus.
@@ -207,9 +402,159 @@ func (in *ExperimentStatus) DeepCopy() *ExperimentStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FieldSelector) DeepCopyInto(out *FieldSelector) {
+	*out = *in
+	if in.Phase != nil {
+		in, out := &in.Phase, &out.Phase
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.QOSClass != nil {
+		in, out := &in.QOSClass, &out.QOSClass
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.MinRestartCount != nil {
+		in, out := &in.MinRestartCount, &out.MinRestartCount
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxRestartCount != nil {
+		in, out := &in.MaxRestartCount, &out.MaxRestartCount
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Zone != nil {
+		in, out := &in.Zone, &out.Zone
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FieldSelector.
+func (in *FieldSelector) DeepCopy() *FieldSelector {
+	if in == nil {
+		return nil
+	}
+	out := new(FieldSelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InjectLatency) DeepCopyInto(out *InjectLatency) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InjectLatency.
+func (in *InjectLatency) DeepCopy() *InjectLatency {
+	if in == nil {
+		return nil
+	}
+	out := new(InjectLatency)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeConfigSecretRef) DeepCopyInto(out *KubeConfigSecretRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeConfigSecretRef.
+func (in *KubeConfigSecretRef) DeepCopy() *KubeConfigSecretRef {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeConfigSecretRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MutexSpec) DeepCopyInto(out *MutexSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MutexSpec.
+func (in *MutexSpec) DeepCopy() *MutexSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MutexSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OwnerSelector) DeepCopyInto(out *OwnerSelector) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OwnerSelector.
+func (in *OwnerSelector) DeepCopy() *OwnerSelector {
+	if in == nil {
+		return nil
+	}
+	out := new(OwnerSelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PreflightResult) DeepCopyInto(out *PreflightResult) {
+	*out = *in
+	if in.Reasons != nil {
+		in, out := &in.Reasons, &out.Reasons
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PreflightResult.
+func (in *PreflightResult) DeepCopy() *PreflightResult {
+	if in == nil {
+		return nil
+	}
+	out := new(PreflightResult)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QueriedTarget) DeepCopyInto(out *QueriedTarget) {
+	*out = *in
+	if in.Resolved != nil {
+		in, out := &in.Resolved, &out.Resolved
+		*out = new(ResolvedTarget)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QueriedTarget.
+func (in *QueriedTarget) DeepCopy() *QueriedTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(QueriedTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RangeMode) DeepCopyInto(out *RangeMode) {
 	*out = *in
+	if in.Seed != nil {
+		in, out := &in.Seed, &out.Seed
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Spread != nil {
+		in, out := &in.Spread, &out.Spread
+		*out = new(SpreadConstraint)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RangeMode.
@@ -222,9 +567,112 @@ func (in *RangeMode) DeepCopy() *RangeMode {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RangeSelection) DeepCopyInto(out *RangeSelection) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RangeSelection.
+func (in *RangeSelection) DeepCopy() *RangeSelection {
+	if in == nil {
+		return nil
+	}
+	out := new(RangeSelection)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RegisteredCluster) DeepCopyInto(out *RegisteredCluster) {
+	*out = *in
+	out.KubeConfigSecret = in.KubeConfigSecret
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RegisteredCluster.
+func (in *RegisteredCluster) DeepCopy() *RegisteredCluster {
+	if in == nil {
+		return nil
+	}
+	out := new(RegisteredCluster)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReresolveSpec) DeepCopyInto(out *ReresolveSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReresolveSpec.
+func (in *ReresolveSpec) DeepCopy() *ReresolveSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ReresolveSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResolvedTarget) DeepCopyInto(out *ResolvedTarget) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResolvedTarget.
+func (in *ResolvedTarget) DeepCopy() *ResolvedTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(ResolvedTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RetryPolicy) DeepCopyInto(out *RetryPolicy) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RetryPolicy.
+func (in *RetryPolicy) DeepCopy() *RetryPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(RetryPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScheduleSpec) DeepCopyInto(out *ScheduleSpec) {
+	*out = *in
+	if in.HistoryLimit != nil {
+		in, out := &in.HistoryLimit, &out.HistoryLimit
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduleSpec.
+func (in *ScheduleSpec) DeepCopy() *ScheduleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ScheduleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SelectorUnit) DeepCopyInto(out *SelectorUnit) {
 	*out = *in
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	if in.Name != nil {
 		in, out := &in.Name, &out.Name
 		*out = make([]string, len(*in))
@@ -242,6 +690,28 @@ func (in *SelectorUnit) DeepCopyInto(out *SelectorUnit) {
 			(*out)[key] = val
 		}
 	}
+	if in.Owner != nil {
+		in, out := &in.Owner, &out.Owner
+		*out = new(OwnerSelector)
+		**out = **in
+	}
+	if in.Field != nil {
+		in, out := &in.Field, &out.Field
+		*out = new(FieldSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExcludeLabel != nil {
+		in, out := &in.ExcludeLabel, &out.ExcludeLabel
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ExcludeNames != nil {
+		in, out := &in.ExcludeNames, &out.ExcludeNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SelectorUnit.
@@ -253,3 +723,151 @@ func (in *SelectorUnit) DeepCopy() *SelectorUnit {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SpreadConstraint) DeepCopyInto(out *SpreadConstraint) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpreadConstraint.
+func (in *SpreadConstraint) DeepCopy() *SpreadConstraint {
+	if in == nil {
+		return nil
+	}
+	out := new(SpreadConstraint)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TargetLifecycle) DeepCopyInto(out *TargetLifecycle) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TargetLifecycle.
+func (in *TargetLifecycle) DeepCopy() *TargetLifecycle {
+	if in == nil {
+		return nil
+	}
+	out := new(TargetLifecycle)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TargetQuery) DeepCopyInto(out *TargetQuery) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TargetQuery.
+func (in *TargetQuery) DeepCopy() *TargetQuery {
+	if in == nil {
+		return nil
+	}
+	out := new(TargetQuery)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TargetQuery) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TargetQueryList) DeepCopyInto(out *TargetQueryList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]TargetQuery, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TargetQueryList.
+func (in *TargetQueryList) DeepCopy() *TargetQueryList {
+	if in == nil {
+		return nil
+	}
+	out := new(TargetQueryList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TargetQueryList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TargetQuerySpec) DeepCopyInto(out *TargetQuerySpec) {
+	*out = *in
+	if in.RangeMode != nil {
+		in, out := &in.RangeMode, &out.RangeMode
+		*out = new(RangeMode)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Experiment != nil {
+		in, out := &in.Experiment, &out.Experiment
+		*out = new(ExperimentCommon)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = make([]SelectorUnit, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TargetQuerySpec.
+func (in *TargetQuerySpec) DeepCopy() *TargetQuerySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TargetQuerySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TargetQueryStatus) DeepCopyInto(out *TargetQueryStatus) {
+	*out = *in
+	if in.Selection != nil {
+		in, out := &in.Selection, &out.Selection
+		*out = new(RangeSelection)
+		**out = **in
+	}
+	if in.Targets != nil {
+		in, out := &in.Targets, &out.Targets
+		*out = make([]QueriedTarget, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TargetQueryStatus.
+func (in *TargetQueryStatus) DeepCopy() *TargetQueryStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TargetQueryStatus)
+	in.DeepCopyInto(out)
+	return out
+}
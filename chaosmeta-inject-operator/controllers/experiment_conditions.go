@@ -0,0 +1,129 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	ReasonTargetsSelected = "TargetsSelected"
+	ReasonSelectFailed    = "SelectFailed"
+	ReasonNotRecovered    = "NotRecovered"
+	ReasonPreflightPassed = "PreflightPassed"
+	ReasonPreflightFailed = "PreflightFailed"
+	ReasonPreflightCheck  = "PreflightChecking"
+)
+
+// updateConditions derives the conditions from the phase and status, the reasons are the same as the events of them
+func updateConditions(instance *v1alpha1.Experiment) {
+	status := &instance.Status
+	reason := phaseReasons[status.Phase][status.Status]
+	if reason == "" {
+		return
+	}
+
+	setCondition := func(conditionType string, conditionStatus metav1.ConditionStatus, reason, message string) {
+		meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+			Type:               conditionType,
+			Status:             conditionStatus,
+			ObservedGeneration: instance.Generation,
+			Reason:             reason,
+			Message:            message,
+		})
+	}
+
+	if len(status.Detail.Inject) > 0 {
+		setCondition(v1alpha1.SelectedConditionType, metav1.ConditionTrue, ReasonTargetsSelected, fmt.Sprintf("%d targets selected", len(status.Detail.Inject)))
+	} else if status.Phase == v1alpha1.InjectPhaseType && status.Status == v1alpha1.FailedStatusType {
+		setCondition(v1alpha1.SelectedConditionType, metav1.ConditionFalse, ReasonSelectFailed, status.Message)
+	}
+
+	if instance.Spec.Preflight && len(status.Detail.Inject) > 0 {
+		setCondition(getPreflightCondition(status.Detail.Inject))
+	}
+
+	conditionStatus := getConditionStatus(status.Phase, status.Status)
+	switch status.Phase {
+	case v1alpha1.InjectPhaseType:
+		setCondition(v1alpha1.InjectedConditionType, conditionStatus, reason, status.Message)
+		// a resumed experiment is injected again
+		setCondition(v1alpha1.RecoveredConditionType, metav1.ConditionFalse, ReasonNotRecovered, "the recover phase is not started")
+	case v1alpha1.RecoverPhaseType:
+		setCondition(v1alpha1.RecoveredConditionType, conditionStatus, reason, status.Message)
+	}
+}
+
+// getPreflightCondition a failed target fails the check, and the check passes only if all the targets have passed
+func getPreflightCondition(detail []v1alpha1.ExperimentDetailUnit) (string, metav1.ConditionStatus, string, string) {
+	var checked, failed int
+	for _, unit := range detail {
+		if unit.Preflight == nil {
+			continue
+		}
+
+		checked++
+		if !unit.Preflight.Passed {
+			failed++
+		}
+	}
+
+	switch {
+	case failed > 0:
+		return v1alpha1.PreflightCheckedConditionType, metav1.ConditionFalse, ReasonPreflightFailed, fmt.Sprintf("%d/%d targets failed the preflight check", failed, len(detail))
+	case checked == len(detail):
+		return v1alpha1.PreflightCheckedConditionType, metav1.ConditionTrue, ReasonPreflightPassed, fmt.Sprintf("%d targets passed the preflight check", checked)
+	default:
+		return v1alpha1.PreflightCheckedConditionType, metav1.ConditionUnknown, ReasonPreflightCheck, fmt.Sprintf("%d/%d targets checked", checked, len(detail))
+	}
+}
+
+// getConditionStatus a part success injection is injected, but a part success recovery leaves some targets injected
+func getConditionStatus(phase v1alpha1.PhaseType, status v1alpha1.StatusType) metav1.ConditionStatus {
+	switch status {
+	case v1alpha1.SuccessStatusType:
+		return metav1.ConditionTrue
+	case v1alpha1.PartSuccessStatusType:
+		if phase == v1alpha1.InjectPhaseType {
+			return metav1.ConditionTrue
+		}
+		return metav1.ConditionFalse
+	case v1alpha1.FailedStatusType:
+		return metav1.ConditionFalse
+	default:
+		return metav1.ConditionUnknown
+	}
+}
+
+// updateTargetEndTime records when every target turns to a final status, the end time of a retried target is cleared
+func updateTargetEndTime(instance *v1alpha1.Experiment, now string) {
+	for _, detail := range [][]v1alpha1.ExperimentDetailUnit{instance.Status.Detail.Inject, instance.Status.Detail.Recover} {
+		for i := range detail {
+			switch detail[i].Status {
+			case v1alpha1.SuccessStatusType, v1alpha1.FailedStatusType, v1alpha1.PartSuccessStatusType:
+				if detail[i].EndTime == "" {
+					detail[i].EndTime = now
+				}
+			default:
+				detail[i].EndTime = ""
+			}
+		}
+	}
+}
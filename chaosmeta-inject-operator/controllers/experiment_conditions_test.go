@@ -0,0 +1,140 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"testing"
+)
+
+func Test_updateConditions(t *testing.T) {
+	exp := &v1alpha1.Experiment{
+		ObjectMeta: metav1.ObjectMeta{Generation: 2},
+		Status: v1alpha1.ExperimentStatus{
+			Phase:   v1alpha1.InjectPhaseType,
+			Status:  v1alpha1.RunningStatusType,
+			Message: "create finish, start to solve running status",
+			Detail: v1alpha1.ExperimentDetail{
+				Inject: []v1alpha1.ExperimentDetailUnit{
+					{UID: "uid1", Status: v1alpha1.RunningStatusType},
+					{UID: "uid2", Status: v1alpha1.FailedStatusType},
+				},
+			},
+		},
+	}
+
+	updateConditions(exp)
+	selected := meta.FindStatusCondition(exp.Status.Conditions, v1alpha1.SelectedConditionType)
+	assert.Equal(t, metav1.ConditionTrue, selected.Status)
+	assert.Equal(t, "2 targets selected", selected.Message)
+	assert.Equal(t, int64(2), selected.ObservedGeneration)
+	injected := meta.FindStatusCondition(exp.Status.Conditions, v1alpha1.InjectedConditionType)
+	assert.Equal(t, metav1.ConditionUnknown, injected.Status)
+	assert.Equal(t, "InjectRunning", injected.Reason)
+	assert.True(t, meta.IsStatusConditionFalse(exp.Status.Conditions, v1alpha1.RecoveredConditionType))
+
+	exp.Status.Status, exp.Status.Message = v1alpha1.PartSuccessStatusType, "run part success"
+	updateConditions(exp)
+	assert.True(t, meta.IsStatusConditionTrue(exp.Status.Conditions, v1alpha1.InjectedConditionType))
+	assert.Equal(t, "PartiallyInjected", meta.FindStatusCondition(exp.Status.Conditions, v1alpha1.InjectedConditionType).Reason)
+
+	exp.Status.Phase, exp.Status.Status = v1alpha1.RecoverPhaseType, v1alpha1.PartSuccessStatusType
+	updateConditions(exp)
+	assert.True(t, meta.IsStatusConditionTrue(exp.Status.Conditions, v1alpha1.InjectedConditionType))
+	assert.True(t, meta.IsStatusConditionFalse(exp.Status.Conditions, v1alpha1.RecoveredConditionType))
+
+	exp.Status.Status = v1alpha1.SuccessStatusType
+	updateConditions(exp)
+	assert.Equal(t, "Recovered", meta.FindStatusCondition(exp.Status.Conditions, v1alpha1.RecoveredConditionType).Reason)
+	assert.True(t, meta.IsStatusConditionTrue(exp.Status.Conditions, v1alpha1.RecoveredConditionType))
+	assert.Len(t, exp.Status.Conditions, 3)
+}
+
+func Test_updateConditions_SelectFailed(t *testing.T) {
+	exp := &v1alpha1.Experiment{
+		Status: v1alpha1.ExperimentStatus{
+			Phase:   v1alpha1.InjectPhaseType,
+			Status:  v1alpha1.FailedStatusType,
+			Message: "get experiment's objects error: no target",
+		},
+	}
+
+	updateConditions(exp)
+	selected := meta.FindStatusCondition(exp.Status.Conditions, v1alpha1.SelectedConditionType)
+	assert.Equal(t, metav1.ConditionFalse, selected.Status)
+	assert.Equal(t, ReasonSelectFailed, selected.Reason)
+	assert.True(t, meta.IsStatusConditionFalse(exp.Status.Conditions, v1alpha1.InjectedConditionType))
+
+	// the experiment waiting for its mutex has no condition
+	exp = &v1alpha1.Experiment{}
+	updateConditions(exp)
+	assert.Empty(t, exp.Status.Conditions)
+}
+
+func Test_updateConditions_Preflight(t *testing.T) {
+	exp := &v1alpha1.Experiment{
+		Spec: v1alpha1.ExperimentSpec{Preflight: true},
+		Status: v1alpha1.ExperimentStatus{
+			Phase:  v1alpha1.InjectPhaseType,
+			Status: v1alpha1.CreatedStatusType,
+			Detail: v1alpha1.ExperimentDetail{
+				Inject: []v1alpha1.ExperimentDetailUnit{
+					{InjectObjectName: "pod/ns/p1", Preflight: &v1alpha1.PreflightResult{Passed: true}},
+					{InjectObjectName: "pod/ns/p2"},
+				},
+			},
+		},
+	}
+
+	updateConditions(exp)
+	assert.Equal(t, ReasonPreflightCheck, meta.FindStatusCondition(exp.Status.Conditions, v1alpha1.PreflightCheckedConditionType).Reason)
+
+	exp.Status.Detail.Inject[1].Preflight = &v1alpha1.PreflightResult{Passed: true}
+	updateConditions(exp)
+	assert.True(t, meta.IsStatusConditionTrue(exp.Status.Conditions, v1alpha1.PreflightCheckedConditionType))
+
+	exp.Status.Status = v1alpha1.FailedStatusType
+	exp.Status.Detail.Inject[1].Preflight = &v1alpha1.PreflightResult{Reasons: []string{"container is not running"}}
+	updateConditions(exp)
+	preflight := meta.FindStatusCondition(exp.Status.Conditions, v1alpha1.PreflightCheckedConditionType)
+	assert.Equal(t, metav1.ConditionFalse, preflight.Status)
+	assert.Equal(t, "1/2 targets failed the preflight check", preflight.Message)
+}
+
+func Test_updateTargetEndTime(t *testing.T) {
+	exp := &v1alpha1.Experiment{
+		Status: v1alpha1.ExperimentStatus{
+			Detail: v1alpha1.ExperimentDetail{
+				Inject: []v1alpha1.ExperimentDetailUnit{
+					{UID: "uid1", Status: v1alpha1.SuccessStatusType, EndTime: "2023-03-03 19:01:50"},
+					{UID: "uid2", Status: v1alpha1.CreatedStatusType, EndTime: "2023-03-03 19:01:50"},
+				},
+				Recover: []v1alpha1.ExperimentDetailUnit{
+					{UID: "uid1", Status: v1alpha1.FailedStatusType},
+				},
+			},
+		},
+	}
+
+	updateTargetEndTime(exp, "2023-03-03 19:05:00")
+	assert.Equal(t, "2023-03-03 19:01:50", exp.Status.Detail.Inject[0].EndTime)
+	assert.Empty(t, exp.Status.Detail.Inject[1].EndTime)
+	assert.Equal(t, "2023-03-03 19:05:00", exp.Status.Detail.Recover[0].EndTime)
+}
@@ -21,23 +21,42 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/cloudevents"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/common"
 	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/model"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/multicluster"
 	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/phasehandler"
 	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/scopehandler"
 	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/selector"
+	"hash/fnv"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
-	"math/rand"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sort"
+	"strings"
 	"time"
 )
 
+const (
+	mutexRequeueInterval   = 10 * time.Second
+	resolveRequeueInterval = 10 * time.Second
+)
+
 // ExperimentReconciler reconciles a Experiment object
 type ExperimentReconciler struct {
 	client.Client
+	// Recorder Optional: the transitions of experiments are recorded as events
+	Recorder record.EventRecorder
+	// MaxConcurrentReconciles Optional: the max experiments reconciled at the same time, default 1.
+	// One experiment is never reconciled by two workers at the same time
+	MaxConcurrentReconciles int
+	// DependencyWaitTimeout Optional: an experiment waiting for its dependencies longer than it since created fails, 0 means no deadline
+	DependencyWaitTimeout time.Duration
 	//RESTClient rest.Interface
 	//RESTConfig *rest.Config
 	//Scheme     *runtime.Scheme
@@ -46,9 +65,15 @@ type ExperimentReconciler struct {
 //+kubebuilder:rbac:groups=chaosmeta.io,resources=experiments,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=chaosmeta.io,resources=experiments/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=chaosmeta.io,resources=experiments/finalizers,verbs=update
-//+kubebuilder:rbac:groups=core,resources=pods;pods/exec;services;namespaces;nodes,verbs=*
+//+kubebuilder:rbac:groups=core,resources=pods;pods/ephemeralcontainers;pods/exec;services;namespaces;nodes,verbs=*
 //+kubebuilder:rbac:groups=apps,resources=deployments;daemonsets;replicasets;statefulsets,verbs=*
-//+kubebuilder:rbac:groups=batchs,resources=jobs,verbs=*
+//+kubebuilder:rbac:groups=batch,resources=jobs;cronjobs,verbs=*
+//+kubebuilder:rbac:groups=discovery.k8s.io,resources=endpointslices,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=pods/eviction,verbs=create
+//+kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+//+kubebuilder:rbac:groups=chaosmeta.io,resources=clusterregistries,verbs=get;list;watch
+// the kubeconfig secrets are only read in the namespace of the operator, by the role in config/rbac/cluster_registry_role.yaml
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -76,24 +101,33 @@ func (r *ExperimentReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		}
 	}()
 
+	if instance.Spec.Schedule != nil {
+		return r.reconcileSchedule(ctx, instance)
+	}
+
+	// a force deleted experiment is not recovered, so it does not wait for its cluster
+	if instance.Spec.Cluster != "" && (instance.ObjectMeta.DeletionTimestamp.IsZero() || !isForceDeleted(instance)) {
+		targetCluster, err := multicluster.GetCluster(ctx, instance.Spec.Cluster)
+		if err != nil {
+			r.recordEvent(instance, corev1.EventTypeWarning, ReasonClusterUnavailable, err.Error())
+			return ctrl.Result{}, fmt.Errorf("get cluster error: %s", err.Error())
+		}
+
+		ctx = multicluster.WithCluster(ctx, targetCluster)
+	}
+
 	status, _ := json.Marshal(instance.Status)
 	logger.Info(fmt.Sprintf("experiment: %s/%s, get status: %s", instance.Namespace, instance.Name, string(status)))
+	oldPhase, oldStatus, before := instance.Status.Phase, instance.Status.Status, instance.Status.DeepCopy()
 
 	if !instance.ObjectMeta.DeletionTimestamp.IsZero() {
-		if instance.Status.Status == v1alpha1.SuccessStatusType || instance.Status.Status == v1alpha1.FailedStatusType || instance.Status.Status == v1alpha1.PartSuccessStatusType {
-			if instance.Spec.TargetPhase == v1alpha1.InjectPhaseType && instance.Status.Phase == v1alpha1.InjectPhaseType {
-				instance.Spec.TargetPhase = v1alpha1.RecoverPhaseType
-				logger.Info(fmt.Sprintf("update TargetPhase of %s/%s to: %s", instance.Namespace, instance.Name, instance.Spec.TargetPhase))
-				return ctrl.Result{}, r.Update(ctx, instance)
-			} else if instance.Status.Phase == v1alpha1.RecoverPhaseType {
-				solveFinalizer(instance)
-				logger.Info(fmt.Sprintf("update Finalizer of %s/%s to: %s", instance.Namespace, instance.Name, instance.ObjectMeta.Finalizers))
-				return ctrl.Result{}, r.Update(ctx, instance)
-			}
+		if result, done, err := r.reconcileDeletion(ctx, instance); done {
+			return result, err
 		}
 	} else {
-		if instance.Status.Phase == v1alpha1.RecoverPhaseType && (instance.Status.Status == v1alpha1.SuccessStatusType ||
-			instance.Status.Status == v1alpha1.FailedStatusType || instance.Status.Status == v1alpha1.PartSuccessStatusType) {
+		// a paused experiment is recovered, but it keeps the finalizer to be resumed.
+		// A failed recovery also keeps the finalizer, so that it is retried when the experiment is deleted
+		if instance.Status.Phase == v1alpha1.RecoverPhaseType && !instance.Status.Paused && instance.Status.Status == v1alpha1.SuccessStatusType {
 			solveFinalizer(instance)
 			logger.Info(fmt.Sprintf("update Finalizer of %s/%s to: %s", instance.Namespace, instance.Name, instance.ObjectMeta.Finalizers))
 			return ctrl.Result{}, r.Update(ctx, instance)
@@ -101,18 +135,73 @@ func (r *ExperimentReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	}
 
 	if instance.Status.Phase == "" {
-		initProcess(ctx, instance)
+		waitMsg, failMsg, err := getDependencyMessage(ctx, instance)
+		if err != nil {
+			return ctrl.Result{}, fmt.Errorf("check dependencies error: %s", err.Error())
+		}
+
+		if waitMsg != "" && isDependencyWaitTimeout(instance, r.DependencyWaitTimeout, time.Now()) {
+			waitMsg, failMsg = "", fmt.Sprintf("%s, but timeout after %s", waitMsg, r.DependencyWaitTimeout)
+		}
+
+		if waitMsg != "" {
+			return r.waitToStart(ctx, instance, ReasonWaitingForDependency, waitMsg)
+		}
+
+		if failMsg == "" {
+			waitMsg, err = getMutexWaitMessage(ctx, instance)
+			if err != nil {
+				return ctrl.Result{}, fmt.Errorf("check mutex error: %s", err.Error())
+			}
+
+			if waitMsg != "" {
+				return r.waitToStart(ctx, instance, ReasonWaitingForMutex, waitMsg)
+			}
+		}
+
+		// the finalizer is added by the webhook, it is also added here in case the webhook is disabled
+		if !hasFinalizer(instance) {
+			instance.ObjectMeta.Finalizers = append(instance.ObjectMeta.Finalizers, v1alpha1.FinalizerName)
+			return ctrl.Result{}, r.Update(ctx, instance)
+		}
+
+		if failMsg != "" {
+			logger.Info(fmt.Sprintf("experiment: %s/%s, %s", instance.Namespace, instance.Name, failMsg))
+			failDependency(instance, failMsg)
+		} else {
+			initProcess(ctx, instance)
+		}
 	} else {
+		if markInjectAttempts(instance) {
+			if err := r.Client.Status().Update(ctx, instance); err != nil {
+				return ctrl.Result{}, fmt.Errorf("update inject attempts error: %s", err.Error())
+			}
+		}
 		statusProcess(ctx, instance)
 	}
 
+	if !instance.Status.IsFinished() {
+		instance.Status.FinishTime = ""
+	} else if instance.Status.FinishTime == "" {
+		instance.Status.FinishTime = time.Now().Format(model.TimeFormat)
+	}
+	updateTargetEndTime(instance, time.Now().Format(model.TimeFormat))
+	updateConditions(instance)
+
 	status, _ = json.Marshal(instance.Status)
 	logger.Info(fmt.Sprintf("experiment: %s/%s, start to update status: %s", instance.Namespace, instance.Name, string(status)))
 	if err := r.Client.Status().Update(ctx, instance); err != nil {
 		return ctrl.Result{}, fmt.Errorf("update instance error: %s", err.Error())
 	}
+	cloudevents.EmitIfChanged(ctx, instance, oldPhase, oldStatus)
+	r.recordEvents(instance, before)
+
+	requeueAfter := getRetryRequeueAfter(instance)
+	if requeueAfter == 0 {
+		requeueAfter = getResolveRequeueAfter(instance)
+	}
 
-	return ctrl.Result{}, nil
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
 }
 
 func initProcess(ctx context.Context, instance *v1alpha1.Experiment) {
@@ -133,23 +222,93 @@ func initProcess(ctx context.Context, instance *v1alpha1.Experiment) {
 		return
 	}
 	// process with range args
-	injectObjects = solveRange(injectObjects, instance.Spec.RangeMode)
+	var domains map[string]string
+	if instance.Spec.RangeMode != nil && instance.Spec.RangeMode.Spread != nil {
+		domains, err = getTopologyDomains(ctx, injectObjects, instance.Spec.RangeMode.Spread.TopologyKey)
+		if err != nil {
+			instance.Status.Status, instance.Status.Message = v1alpha1.FailedStatusType, fmt.Sprintf("get topology of targets error: %s", err.Error())
+			return
+		}
+	}
+	injectObjects, instance.Status.Selection = solveRange(injectObjects, instance.Spec.RangeMode, domains)
 	details := make([]v1alpha1.ExperimentDetailUnit, len(injectObjects))
 	for i, unitInjectObj := range injectObjects {
 		details[i] = v1alpha1.ExperimentDetailUnit{
 			InjectObjectName: unitInjectObj.GetObjectName(),
 			//InjectObjectInfo: string(objBytes),
-			UID:       newUid(),
+			UID:       common.NewUid(),
 			Status:    v1alpha1.CreatedStatusType,
 			Message:   "Initial experiment created",
 			StartTime: nowTime,
+			Executor:  scopehandler.GetTargetExecutor(unitInjectObj),
+			Resolved:  scopehandler.GetResolvedTarget(unitInjectObj, nowTime),
+		}
+
+		if scopehandler.IsReresolvable(&instance.Spec) {
+			details[i].Lifecycle = &v1alpha1.TargetLifecycle{
+				Source:       v1alpha1.InitialTargetSource,
+				ResolvedTime: nowTime,
+			}
 		}
 	}
 
+	if scopehandler.IsReresolvable(&instance.Spec) {
+		instance.Status.LastResolveTime = nowTime
+	}
+
 	instance.Status.Message = "Initial experiment created"
 	instance.Status.Status, instance.Status.Detail.Inject = v1alpha1.CreatedStatusType, details
 }
 
+// markInjectAttempts counts the inject call of every created target in this round, it must be persisted before the calls
+func markInjectAttempts(instance *v1alpha1.Experiment) bool {
+	if instance.Status.Phase != v1alpha1.InjectPhaseType || instance.Status.Status != v1alpha1.CreatedStatusType {
+		return false
+	}
+
+	var marked bool
+	now := time.Now()
+	for i := range instance.Status.Detail.Inject {
+		if instance.Status.Detail.Inject[i].Status == v1alpha1.CreatedStatusType && common.GetRetryWait(&instance.Status.Detail.Inject[i], now) == 0 {
+			instance.Status.Detail.Inject[i].InjectAttempts++
+			marked = true
+		}
+	}
+
+	return marked
+}
+
+// getRetryRequeueAfter returns the wait of the earliest target waiting for its retry, 0 if no target is waiting
+func getRetryRequeueAfter(instance *v1alpha1.Experiment) time.Duration {
+	if instance.Status.Phase != v1alpha1.InjectPhaseType || instance.Status.Status != v1alpha1.CreatedStatusType {
+		return 0
+	}
+
+	var requeueAfter time.Duration
+	now := time.Now()
+	for i := range instance.Status.Detail.Inject {
+		if wait := common.GetRetryWait(&instance.Status.Detail.Inject[i], now); wait > 0 && (requeueAfter == 0 || wait < requeueAfter) {
+			requeueAfter = wait
+		}
+	}
+
+	return requeueAfter
+}
+
+// getResolveRequeueAfter a finished injection is not updated by any target, so it is checked again to re-resolve its targets
+func getResolveRequeueAfter(instance *v1alpha1.Experiment) time.Duration {
+	if instance.Status.Phase != v1alpha1.InjectPhaseType || instance.Spec.TargetPhase != v1alpha1.InjectPhaseType ||
+		(instance.Status.Status != v1alpha1.SuccessStatusType && instance.Status.Status != v1alpha1.PartSuccessStatusType) ||
+		!scopehandler.IsReresolvable(&instance.Spec) {
+		return 0
+	}
+
+	if interval := scopehandler.GetReresolveInterval(&instance.Spec); interval > resolveRequeueInterval {
+		return interval
+	}
+	return resolveRequeueInterval
+}
+
 func statusProcess(ctx context.Context, instance *v1alpha1.Experiment) {
 	handler := phasehandler.GetHandler(instance.Status.Phase)
 
@@ -167,18 +326,17 @@ func statusProcess(ctx context.Context, instance *v1alpha1.Experiment) {
 	}
 }
 
-func newUid() string {
-	t := time.Now()
-	timeStr := t.Format("20060102150405")
-	return fmt.Sprintf("%s%04d", timeStr, t.Nanosecond()/1000%100000%10000)
-}
-
-func solveRange(initial []model.AtomicObject, rangeMode *v1alpha1.RangeMode) []model.AtomicObject {
-	if rangeMode == nil || rangeMode.Type == v1alpha1.AllRangeType {
-		return initial
+// solveRange picks the targets by ranking the candidates with a hash of the seed and the object name,
+// so the same seed and candidates always give the same targets, no matter the order they are listed in.
+// The selection is only deterministic with "rangeMode.seed": without it, a seed of the current time is used,
+// it differs on every call and is recorded in the returned selection so that the targets can be picked again.
+// With a spread constraint, a candidate is skipped when its domain already has enough targets
+func solveRange(initial []model.AtomicObject, rangeMode *v1alpha1.RangeMode, domains map[string]string) ([]model.AtomicObject, *v1alpha1.RangeSelection) {
+	if rangeMode == nil || (rangeMode.Type == v1alpha1.AllRangeType && rangeMode.Spread == nil) {
+		return initial, nil
 	}
 
-	var count int
+	var count = len(initial)
 	if rangeMode.Type == v1alpha1.CountRangeType {
 		count = rangeMode.Value
 	}
@@ -188,20 +346,95 @@ func solveRange(initial []model.AtomicObject, rangeMode *v1alpha1.RangeMode) []m
 	}
 
 	if count >= len(initial) {
-		return initial
+		if rangeMode.Spread == nil {
+			return initial, nil
+		}
+		count = len(initial)
 	}
 
-	rand.Seed(time.Now().Unix())
-	rand.Shuffle(len(initial), func(i int, j int) {
-		initial[i], initial[j] = initial[j], initial[i]
+	seed := time.Now().UnixNano()
+	if rangeMode.Seed != nil {
+		seed = *rangeMode.Seed
+	}
+
+	rank := make(map[string]uint64, len(initial))
+	for _, unitObj := range initial {
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(fmt.Sprintf("%d/%s", seed, unitObj.GetObjectName())))
+		rank[unitObj.GetObjectName()] = h.Sum64()
+	}
+
+	sort.Slice(initial, func(i, j int) bool {
+		ri, rj := rank[initial[i].GetObjectName()], rank[initial[j].GetObjectName()]
+		if ri != rj {
+			return ri < rj
+		}
+		return initial[i].GetObjectName() < initial[j].GetObjectName()
 	})
 
-	res := initial[:count]
+	var (
+		res         []model.AtomicObject
+		domainCount = make(map[string]int)
+	)
+	for _, unitObj := range initial {
+		if len(res) == count {
+			break
+		}
+
+		if rangeMode.Spread != nil {
+			domain := domains[unitObj.GetObjectName()]
+			if domainCount[domain] >= rangeMode.Spread.MaxPerDomain {
+				continue
+			}
+			domainCount[domain]++
+		}
+
+		res = append(res, unitObj)
+	}
+
 	sort.Slice(res, func(i, j int) bool {
 		return res[i].GetObjectName() < res[j].GetObjectName()
 	})
 
-	return res
+	return res, &v1alpha1.RangeSelection{
+		Seed:           seed,
+		CandidateCount: len(initial),
+		SelectedCount:  len(res),
+	}
+}
+
+// getTopologyDomains returns the node or zone of each target, key is the object name
+func getTopologyDomains(ctx context.Context, objects []model.AtomicObject, topologyKey v1alpha1.TopologyKeyType) (map[string]string, error) {
+	var (
+		nodeZone map[string]string
+		err      error
+	)
+	if topologyKey == v1alpha1.ZoneTopologyKey {
+		if nodeZone, err = selector.GetAnalyzer().GetNodeZoneMap(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	domains := make(map[string]string, len(objects))
+	for _, unitObj := range objects {
+		var nodeName string
+		switch obj := unitObj.(type) {
+		case *model.PodObject:
+			nodeName = obj.NodeName
+		case *model.NodeObject:
+			nodeName = obj.NodeName
+		default:
+			return nil, fmt.Errorf("spread is not supported for target %s", unitObj.GetObjectName())
+		}
+
+		if topologyKey == v1alpha1.ZoneTopologyKey {
+			domains[unitObj.GetObjectName()] = nodeZone[nodeName]
+		} else {
+			domains[unitObj.GetObjectName()] = nodeName
+		}
+	}
+
+	return domains, nil
 }
 
 // SetupWithManager sets up the controller with the Manager.
@@ -220,6 +453,16 @@ func (r *ExperimentReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		return err
 	}
 
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &v1alpha1.Experiment{}, selector.MutexKey, func(rawObj client.Object) []string {
+		exp := rawObj.(*v1alpha1.Experiment)
+		if exp.Spec.Mutex == nil {
+			return nil
+		}
+		return []string{exp.Spec.Mutex.Name}
+	}); err != nil {
+		return err
+	}
+
 	//if err := mgr.GetFieldIndexer().IndexField(context.Background(), &v1alpha1.Experiment{}, selector.StatusKey, func(rawObj client.Object) []string {
 	//	exp := rawObj.(*v1alpha1.Experiment)
 	//	return []string{string(exp.Status.Status)}
@@ -229,9 +472,208 @@ func (r *ExperimentReconciler) SetupWithManager(mgr ctrl.Manager) error {
 
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&v1alpha1.Experiment{}).
+		Owns(&v1alpha1.Experiment{}).
+		WithOptions(controller.Options{MaxConcurrentReconciles: r.MaxConcurrentReconciles}).
 		Complete(r)
 }
 
+// waitToStart keeps the experiment in the empty phase and checks it again later,
+// the message and the event are only updated when the reason of waiting changes
+func (r *ExperimentReconciler) waitToStart(ctx context.Context, instance *v1alpha1.Experiment, reason, waitMsg string) (ctrl.Result, error) {
+	log.FromContext(ctx).Info(fmt.Sprintf("experiment: %s/%s, %s", instance.Namespace, instance.Name, waitMsg))
+	if instance.Status.Message != waitMsg {
+		instance.Status.Message = waitMsg
+		if err := r.Client.Status().Update(ctx, instance); err != nil {
+			return ctrl.Result{}, fmt.Errorf("update instance error: %s", err.Error())
+		}
+		r.recordEvent(instance, corev1.EventTypeNormal, reason, waitMsg)
+	}
+	return ctrl.Result{RequeueAfter: mutexRequeueInterval}, nil
+}
+
+// getDependencyMessage returns why the experiment has to wait for its dependencies, or why it can never start.
+// A dependency is done when it succeeds in its target phase, and it fails the experiment when it finishes in the
+// target phase without success. The experiment with a schedule is never injected, so it can not be a dependency
+func getDependencyMessage(ctx context.Context, instance *v1alpha1.Experiment) (waitMsg, failMsg string, err error) {
+	var waiting []string
+	for _, name := range instance.Spec.DependsOn {
+		dep, err := selector.GetAnalyzer().GetExperiment(ctx, instance.Namespace, name)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				waiting = append(waiting, name)
+				continue
+			}
+			return "", "", fmt.Errorf("get dependency[%s] error: %s", name, err.Error())
+		}
+
+		if dep.Spec.Schedule != nil {
+			return "", fmt.Sprintf("dependency[%s] has a schedule and is never injected", name), nil
+		}
+
+		// a failed injection rolled back by the failure policy never comes back to the target phase inject
+		if !dep.Status.Paused && dep.Spec.TargetPhase == v1alpha1.InjectPhaseType && dep.Status.Phase == v1alpha1.RecoverPhaseType {
+			return "", fmt.Sprintf("dependency[%s] is rolled back by failure policy", name), nil
+		}
+
+		if dep.Status.Paused || dep.Status.Phase != dep.Spec.TargetPhase {
+			waiting = append(waiting, name)
+			continue
+		}
+
+		// a failed injection is still recovered successfully, so the inject phase is checked for the target phase recover
+		injectFailed := meta.IsStatusConditionFalse(dep.Status.Conditions, v1alpha1.InjectedConditionType)
+		switch dep.Status.Status {
+		case v1alpha1.SuccessStatusType:
+			if injectFailed {
+				return "", fmt.Sprintf("dependency[%s] is recovered but not injected", name), nil
+			}
+		case v1alpha1.FailedStatusType, v1alpha1.PartSuccessStatusType:
+			return "", fmt.Sprintf("dependency[%s] finished in phase %s with status %s", name, dep.Status.Phase, dep.Status.Status), nil
+		default:
+			waiting = append(waiting, name)
+		}
+	}
+
+	if len(waiting) > 0 {
+		cycle, err := getDependencyCycle(ctx, instance)
+		if err != nil {
+			return "", "", err
+		}
+
+		if len(cycle) > 0 {
+			return "", fmt.Sprintf("dependencies form a cycle: %s", strings.Join(cycle, " -> ")), nil
+		}
+
+		return fmt.Sprintf("waiting for dependencies%v to succeed", waiting), "", nil
+	}
+
+	return "", "", nil
+}
+
+// getDependencyCycle walks the dependencies in the namespace, and returns the path back to the experiment if there is one.
+// Such experiments wait for each other forever, and a cycle can be formed by any of them, so it is checked on waiting
+func getDependencyCycle(ctx context.Context, instance *v1alpha1.Experiment) ([]string, error) {
+	var (
+		visited = map[string]bool{instance.Name: true}
+		walk    func(path []string, names []string) ([]string, error)
+	)
+
+	walk = func(path []string, names []string) ([]string, error) {
+		for _, name := range names {
+			if name == instance.Name {
+				return append(path, name), nil
+			}
+
+			if visited[name] {
+				continue
+			}
+			visited[name] = true
+
+			dep, err := selector.GetAnalyzer().GetExperiment(ctx, instance.Namespace, name)
+			if err != nil {
+				if errors.IsNotFound(err) {
+					continue
+				}
+				return nil, fmt.Errorf("get dependency[%s] error: %s", name, err.Error())
+			}
+
+			if cycle, err := walk(append(path, name), dep.Spec.DependsOn); err != nil || cycle != nil {
+				return cycle, err
+			}
+		}
+		return nil, nil
+	}
+
+	return walk([]string{instance.Name}, instance.Spec.DependsOn)
+}
+
+func isDependencyWaitTimeout(instance *v1alpha1.Experiment, timeout time.Duration, now time.Time) bool {
+	return timeout > 0 && instance.CreationTimestamp.Add(timeout).Before(now)
+}
+
+// failDependency the experiment fails in the inject phase without any target selected
+func failDependency(instance *v1alpha1.Experiment, failMsg string) {
+	nowTime := time.Now().Format(model.TimeFormat)
+	instance.Status.Phase, instance.Status.Status = v1alpha1.InjectPhaseType, v1alpha1.FailedStatusType
+	instance.Status.CreateTime, instance.Status.UpdateTime = nowTime, nowTime
+	instance.Status.Message = failMsg
+}
+
+// getMutexWaitMessage returns why the experiment has to wait for its mutex, empty means it can start.
+// The mutex is taken by a started experiment of another holder until it is recovered, and the waiting ones start in creation order
+func getMutexWaitMessage(ctx context.Context, instance *v1alpha1.Experiment) (string, error) {
+	if instance.Spec.Mutex == nil || instance.Spec.Mutex.Name == "" {
+		return "", nil
+	}
+
+	expList, err := selector.GetAnalyzer().GetExperimentListByMutex(ctx, instance.Spec.Mutex.Name)
+	if err != nil {
+		return "", err
+	}
+
+	holder := getMutexHolder(instance)
+	for _, exp := range expList.Items {
+		if exp.Namespace == instance.Namespace && exp.Name == instance.Name || getMutexHolder(&exp) == holder {
+			continue
+		}
+
+		// the experiment with a schedule is never injected, its children take the mutex
+		if exp.Spec.Schedule != nil {
+			continue
+		}
+
+		if isMutexHeld(&exp) {
+			return fmt.Sprintf("waiting for mutex[%s] held by %s", instance.Spec.Mutex.Name, getMutexHolder(&exp)), nil
+		}
+
+		if exp.Status.Phase == "" && isQueuedBefore(&exp, instance) {
+			return fmt.Sprintf("waiting for mutex[%s] queued by %s", instance.Spec.Mutex.Name, getMutexHolder(&exp)), nil
+		}
+	}
+
+	return "", nil
+}
+
+// isQueuedBefore the waiting experiments are ordered by the creation time, which is in seconds, and then by
+// namespace/name, so of two experiments created in the same second only one starts first
+func isQueuedBefore(a, b *v1alpha1.Experiment) bool {
+	if !a.CreationTimestamp.Equal(&b.CreationTimestamp) {
+		return a.CreationTimestamp.Before(&b.CreationTimestamp)
+	}
+
+	return fmt.Sprintf("%s/%s", a.Namespace, a.Name) < fmt.Sprintf("%s/%s", b.Namespace, b.Name)
+}
+
+func getMutexHolder(instance *v1alpha1.Experiment) string {
+	if instance.Spec.Mutex != nil && instance.Spec.Mutex.Holder != "" {
+		return instance.Spec.Mutex.Holder
+	}
+	return fmt.Sprintf("%s/%s", instance.Namespace, instance.Name)
+}
+
+// isMutexHeld a failed injection releases the mutex as a finished recovery does, unless it is aborted by the failure
+// policy with some targets injected, which are kept until recovered
+func isMutexHeld(instance *v1alpha1.Experiment) bool {
+	switch instance.Status.Phase {
+	case "":
+		return false
+	case v1alpha1.InjectPhaseType:
+		return instance.Status.Status != v1alpha1.FailedStatusType || hasInjectedTarget(instance)
+	default:
+		return instance.Status.Status != v1alpha1.SuccessStatusType && instance.Status.Status != v1alpha1.FailedStatusType &&
+			instance.Status.Status != v1alpha1.PartSuccessStatusType
+	}
+}
+
+func hasInjectedTarget(instance *v1alpha1.Experiment) bool {
+	for _, unit := range instance.Status.Detail.Inject {
+		if unit.Status == v1alpha1.RunningStatusType || unit.Status == v1alpha1.SuccessStatusType {
+			return true
+		}
+	}
+	return false
+}
+
 func solveFinalizer(instance *v1alpha1.Experiment) {
 	for index := 0; index < len(instance.ObjectMeta.Finalizers); index++ {
 		if instance.ObjectMeta.Finalizers[index] == v1alpha1.FinalizerName {
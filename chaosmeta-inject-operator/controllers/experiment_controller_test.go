@@ -24,10 +24,15 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
 	mockscopehandler "github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/mock/scopehandler"
+	mockselector "github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/mock/selector"
 	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/model"
 	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/scopehandler"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/selector"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"testing"
+	"time"
 )
 
 func Test_solveRange(t *testing.T) {
@@ -99,7 +104,7 @@ func Test_solveRange(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := solveRange(tt.args.initial, tt.args.rangeMode)
+			got, _ := solveRange(tt.args.initial, tt.args.rangeMode, nil)
 			if len(got) != tt.want {
 				t.Errorf("solveRange() = %v, want %v", len(got), tt.want)
 			}
@@ -107,6 +112,77 @@ func Test_solveRange(t *testing.T) {
 	}
 }
 
+func Test_solveRange_Seed(t *testing.T) {
+	var (
+		seed       int64 = 20230801
+		rangeMode        = &v1alpha1.RangeMode{Type: v1alpha1.CountRangeType, Value: 3, Seed: &seed}
+		newObjects       = func(reverse bool) []model.AtomicObject {
+			var objects []model.AtomicObject
+			for i := 0; i < 10; i++ {
+				index := i
+				if reverse {
+					index = 9 - i
+				}
+				objects = append(objects, &model.PodObject{Namespace: "ns", PodName: fmt.Sprintf("pod%d", index)})
+			}
+			return objects
+		}
+		names = func(objects []model.AtomicObject) []string {
+			var re []string
+			for _, unitObj := range objects {
+				re = append(re, unitObj.GetObjectName())
+			}
+			return re
+		}
+	)
+
+	first, selection := solveRange(newObjects(false), rangeMode, nil)
+	second, _ := solveRange(newObjects(true), rangeMode, nil)
+	assert.Equal(t, names(first), names(second))
+	assert.Equal(t, &v1alpha1.RangeSelection{Seed: seed, CandidateCount: 10, SelectedCount: 3}, selection)
+
+	// the recorded random seed reproduces the selection
+	rangeMode.Seed = nil
+	random, randomSelection := solveRange(newObjects(false), rangeMode, nil)
+	rangeMode.Seed = &randomSelection.Seed
+	reproduced, _ := solveRange(newObjects(true), rangeMode, nil)
+	assert.Equal(t, names(random), names(reproduced))
+}
+
+func Test_solveRange_Spread(t *testing.T) {
+	var (
+		objects []model.AtomicObject
+		domains = make(map[string]string)
+	)
+	// 6 pods on node-1, 2 pods on node-2
+	for i := 0; i < 8; i++ {
+		pod := &model.PodObject{Namespace: "ns", PodName: fmt.Sprintf("pod%d", i), NodeName: "node-1"}
+		if i >= 6 {
+			pod.NodeName = "node-2"
+		}
+		objects = append(objects, pod)
+		domains[pod.GetObjectName()] = pod.NodeName
+	}
+
+	countOf := func(res []model.AtomicObject) map[string]int {
+		re := make(map[string]int)
+		for _, unitObj := range res {
+			re[unitObj.(*model.PodObject).NodeName]++
+		}
+		return re
+	}
+
+	spread := &v1alpha1.SpreadConstraint{TopologyKey: v1alpha1.NodeTopologyKey, MaxPerDomain: 1}
+	res, selection := solveRange(objects, &v1alpha1.RangeMode{Type: v1alpha1.PercentRangeType, Value: 50, Spread: spread}, domains)
+	assert.Equal(t, map[string]int{"node-1": 1, "node-2": 1}, countOf(res))
+	assert.Equal(t, 2, selection.SelectedCount)
+	assert.Equal(t, 8, selection.CandidateCount)
+
+	spread.MaxPerDomain = 2
+	res, _ = solveRange(objects, &v1alpha1.RangeMode{Type: v1alpha1.AllRangeType, Spread: spread}, domains)
+	assert.Equal(t, map[string]int{"node-1": 2, "node-2": 2}, countOf(res))
+}
+
 func Test_initProcess(t *testing.T) {
 	var (
 		ctrl = gomock.NewController(t)
@@ -169,12 +245,41 @@ func Test_initProcess(t *testing.T) {
 	assert.Equal(t, v1alpha1.CreatedStatusType, exp.Status.Detail.Inject[0].Status)
 	assert.Equal(t, v1alpha1.CreatedStatusType, exp.Status.Status)
 	assert.Equal(t, v1alpha1.InjectPhaseType, exp.Status.Phase)
+	resolved := exp.Status.Detail.Inject[0].Resolved
+	assert.Equal(t, "d32tg32", resolved.PodUID)
+	assert.Equal(t, "node-1", resolved.NodeName)
+	assert.Equal(t, "g3g3g", resolved.ContainerID)
+	assert.Equal(t, exp.Status.CreateTime, resolved.ResolvedTime)
 
 	scopeHandlerMock.EXPECT().ConvertSelector(ctx, &exp.Spec).Return([]model.AtomicObject{}, nil)
 	initProcess(ctx, exp)
 	assert.Equal(t, v1alpha1.FailedStatusType, exp.Status.Status)
 }
 
+func Test_markInjectAttempts(t *testing.T) {
+	instance := &v1alpha1.Experiment{
+		Status: v1alpha1.ExperimentStatus{
+			Phase:  v1alpha1.InjectPhaseType,
+			Status: v1alpha1.CreatedStatusType,
+			Detail: v1alpha1.ExperimentDetail{
+				Inject: []v1alpha1.ExperimentDetailUnit{
+					{UID: "uid1", Status: v1alpha1.CreatedStatusType},
+					{UID: "uid2", Status: v1alpha1.RunningStatusType, InjectAttempts: 1},
+					{UID: "uid3", Status: v1alpha1.CreatedStatusType, InjectAttempts: 1},
+				},
+			},
+		},
+	}
+
+	assert.True(t, markInjectAttempts(instance))
+	assert.Equal(t, int32(1), instance.Status.Detail.Inject[0].InjectAttempts)
+	assert.Equal(t, int32(1), instance.Status.Detail.Inject[1].InjectAttempts)
+	assert.Equal(t, int32(2), instance.Status.Detail.Inject[2].InjectAttempts)
+
+	instance.Status.Status = v1alpha1.RunningStatusType
+	assert.False(t, markInjectAttempts(instance))
+}
+
 func Test_solveFinalizer(t *testing.T) {
 	instance := &v1alpha1.Experiment{
 		ObjectMeta: metav1.ObjectMeta{
@@ -200,3 +305,193 @@ func Test_solveFinalizer(t *testing.T) {
 	solveFinalizer(instance)
 	assert.Equal(t, []string{}, instance.ObjectMeta.Finalizers)
 }
+
+func Test_getMutexWaitMessage(t *testing.T) {
+	var (
+		ctrl   = gomock.NewController(t)
+		ctx    = context.Background()
+		now    = time.Now()
+		newExp = func(name, holder string, created time.Time, phase v1alpha1.PhaseType, status v1alpha1.StatusType) v1alpha1.Experiment {
+			return v1alpha1.Experiment{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "chaosmeta", Name: name, CreationTimestamp: metav1.NewTime(created)},
+				Spec:       v1alpha1.ExperimentSpec{Mutex: &v1alpha1.MutexSpec{Name: "payment-prod", Holder: holder}},
+				Status:     v1alpha1.ExperimentStatus{Phase: phase, Status: status},
+			}
+		}
+		instance = newExp("exp-c", "team-c", now, "", "")
+	)
+	defer ctrl.Finish()
+	analyzerMock := mockselector.NewMockIAnalyzer(ctrl)
+	gomonkey.ApplyFunc(selector.GetAnalyzer, func() selector.IAnalyzer {
+		return analyzerMock
+	})
+
+	// running experiment of another holder takes the mutex
+	analyzerMock.EXPECT().GetExperimentListByMutex(ctx, "payment-prod").Return(&v1alpha1.ExperimentList{Items: []v1alpha1.Experiment{
+		instance, newExp("exp-a", "team-a", now.Add(-time.Minute), v1alpha1.InjectPhaseType, v1alpha1.SuccessStatusType),
+	}}, nil)
+	msg, err := getMutexWaitMessage(ctx, &instance)
+	assert.Nil(t, err)
+	assert.Equal(t, "waiting for mutex[payment-prod] held by team-a", msg)
+
+	// recovered experiment releases the mutex, the same holder shares it
+	analyzerMock.EXPECT().GetExperimentListByMutex(ctx, "payment-prod").Return(&v1alpha1.ExperimentList{Items: []v1alpha1.Experiment{
+		instance, newExp("exp-a", "team-a", now.Add(-time.Minute), v1alpha1.RecoverPhaseType, v1alpha1.SuccessStatusType),
+		newExp("exp-c2", "team-c", now.Add(-time.Minute), v1alpha1.InjectPhaseType, v1alpha1.RunningStatusType),
+	}}, nil)
+	msg, err = getMutexWaitMessage(ctx, &instance)
+	assert.Nil(t, err)
+	assert.Equal(t, "", msg)
+
+	// aborted experiment holds the mutex until its injected targets are recovered
+	aborted := newExp("exp-a", "team-a", now.Add(-time.Minute), v1alpha1.InjectPhaseType, v1alpha1.FailedStatusType)
+	aborted.Status.Detail.Inject = []v1alpha1.ExperimentDetailUnit{{Status: v1alpha1.FailedStatusType}, {Status: v1alpha1.SuccessStatusType}}
+	analyzerMock.EXPECT().GetExperimentListByMutex(ctx, "payment-prod").Return(&v1alpha1.ExperimentList{Items: []v1alpha1.Experiment{
+		instance, aborted,
+	}}, nil)
+	msg, err = getMutexWaitMessage(ctx, &instance)
+	assert.Nil(t, err)
+	assert.Equal(t, "waiting for mutex[payment-prod] held by team-a", msg)
+
+	// failed experiment with nothing injected releases the mutex
+	aborted.Status.Detail.Inject[1].Status = v1alpha1.FailedStatusType
+	analyzerMock.EXPECT().GetExperimentListByMutex(ctx, "payment-prod").Return(&v1alpha1.ExperimentList{Items: []v1alpha1.Experiment{
+		instance, aborted,
+	}}, nil)
+	msg, err = getMutexWaitMessage(ctx, &instance)
+	assert.Nil(t, err)
+	assert.Equal(t, "", msg)
+
+	// waiting experiments start in creation order
+	analyzerMock.EXPECT().GetExperimentListByMutex(ctx, "payment-prod").Return(&v1alpha1.ExperimentList{Items: []v1alpha1.Experiment{
+		instance, newExp("exp-b", "team-b", now.Add(-time.Minute), "", ""), newExp("exp-d", "team-d", now.Add(time.Minute), "", ""),
+	}}, nil)
+	msg, err = getMutexWaitMessage(ctx, &instance)
+	assert.Nil(t, err)
+	assert.Equal(t, "waiting for mutex[payment-prod] queued by team-b", msg)
+
+	// waiting experiments created in the same second start in name order
+	analyzerMock.EXPECT().GetExperimentListByMutex(ctx, "payment-prod").Return(&v1alpha1.ExperimentList{Items: []v1alpha1.Experiment{
+		instance, newExp("exp-b", "team-b", now, "", ""),
+	}}, nil)
+	msg, err = getMutexWaitMessage(ctx, &instance)
+	assert.Nil(t, err)
+	assert.Equal(t, "waiting for mutex[payment-prod] queued by team-b", msg)
+
+	analyzerMock.EXPECT().GetExperimentListByMutex(ctx, "payment-prod").Return(&v1alpha1.ExperimentList{Items: []v1alpha1.Experiment{
+		instance, newExp("exp-d", "team-d", now, "", ""),
+	}}, nil)
+	msg, err = getMutexWaitMessage(ctx, &instance)
+	assert.Nil(t, err)
+	assert.Equal(t, "", msg)
+
+	// no mutex
+	msg, err = getMutexWaitMessage(ctx, &v1alpha1.Experiment{})
+	assert.Nil(t, err)
+	assert.Equal(t, "", msg)
+}
+
+func Test_getDependencyMessage(t *testing.T) {
+	var (
+		ctrl   = gomock.NewController(t)
+		ctx    = context.Background()
+		newExp = func(name string, target, phase v1alpha1.PhaseType, status v1alpha1.StatusType) *v1alpha1.Experiment {
+			return &v1alpha1.Experiment{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "chaosmeta", Name: name},
+				Spec:       v1alpha1.ExperimentSpec{TargetPhase: target},
+				Status:     v1alpha1.ExperimentStatus{Phase: phase, Status: status},
+			}
+		}
+		instance = &v1alpha1.Experiment{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "chaosmeta", Name: "stage-3"},
+			Spec:       v1alpha1.ExperimentSpec{DependsOn: []string{"stage-1", "stage-2"}},
+		}
+		notFound = errors.NewNotFound(schema.GroupResource{Group: "chaosmeta.io", Resource: "experiments"}, "stage-2")
+	)
+	defer ctrl.Finish()
+	analyzerMock := mockselector.NewMockIAnalyzer(ctrl)
+	gomonkey.ApplyFunc(selector.GetAnalyzer, func() selector.IAnalyzer {
+		return analyzerMock
+	})
+
+	// a running dependency and a missing one are waited for, they are walked again to find a cycle
+	analyzerMock.EXPECT().GetExperiment(ctx, "chaosmeta", "stage-1").Return(newExp("stage-1", v1alpha1.RecoverPhaseType, v1alpha1.InjectPhaseType, v1alpha1.SuccessStatusType), nil).Times(2)
+	analyzerMock.EXPECT().GetExperiment(ctx, "chaosmeta", "stage-2").Return(nil, notFound).Times(2)
+	waitMsg, failMsg, err := getDependencyMessage(ctx, instance)
+	assert.Nil(t, err)
+	assert.Equal(t, "waiting for dependencies[stage-1 stage-2] to succeed", waitMsg)
+	assert.Equal(t, "", failMsg)
+
+	// all the dependencies succeed in their target phase
+	analyzerMock.EXPECT().GetExperiment(ctx, "chaosmeta", "stage-1").Return(newExp("stage-1", v1alpha1.RecoverPhaseType, v1alpha1.RecoverPhaseType, v1alpha1.SuccessStatusType), nil)
+	analyzerMock.EXPECT().GetExperiment(ctx, "chaosmeta", "stage-2").Return(newExp("stage-2", v1alpha1.InjectPhaseType, v1alpha1.InjectPhaseType, v1alpha1.SuccessStatusType), nil)
+	waitMsg, failMsg, err = getDependencyMessage(ctx, instance)
+	assert.Nil(t, err)
+	assert.Equal(t, "", waitMsg)
+	assert.Equal(t, "", failMsg)
+
+	// a dependency finished without success fails the experiment
+	analyzerMock.EXPECT().GetExperiment(ctx, "chaosmeta", "stage-1").Return(newExp("stage-1", v1alpha1.InjectPhaseType, v1alpha1.InjectPhaseType, v1alpha1.PartSuccessStatusType), nil)
+	waitMsg, failMsg, err = getDependencyMessage(ctx, instance)
+	assert.Nil(t, err)
+	assert.Equal(t, "", waitMsg)
+	assert.Equal(t, "dependency[stage-1] finished in phase inject with status partSuccess", failMsg)
+
+	// a dependency recovered after a failed injection fails the experiment
+	recovered := newExp("stage-1", v1alpha1.RecoverPhaseType, v1alpha1.RecoverPhaseType, v1alpha1.SuccessStatusType)
+	recovered.Status.Conditions = []metav1.Condition{{Type: v1alpha1.InjectedConditionType, Status: metav1.ConditionFalse}}
+	analyzerMock.EXPECT().GetExperiment(ctx, "chaosmeta", "stage-1").Return(recovered, nil)
+	_, failMsg, err = getDependencyMessage(ctx, instance)
+	assert.Nil(t, err)
+	assert.Equal(t, "dependency[stage-1] is recovered but not injected", failMsg)
+
+	// a dependency rolled back by the failure policy fails the experiment
+	analyzerMock.EXPECT().GetExperiment(ctx, "chaosmeta", "stage-1").Return(newExp("stage-1", v1alpha1.InjectPhaseType, v1alpha1.RecoverPhaseType, v1alpha1.RunningStatusType), nil)
+	_, failMsg, err = getDependencyMessage(ctx, instance)
+	assert.Nil(t, err)
+	assert.Equal(t, "dependency[stage-1] is rolled back by failure policy", failMsg)
+
+	// dependencies waiting for each other fail the experiment
+	cycled := newExp("stage-1", v1alpha1.InjectPhaseType, "", "")
+	cycled.Spec.DependsOn = []string{"stage-0"}
+	cycledNext := newExp("stage-0", v1alpha1.InjectPhaseType, "", "")
+	cycledNext.Spec.DependsOn = []string{"stage-3"}
+	analyzerMock.EXPECT().GetExperiment(ctx, "chaosmeta", "stage-1").Return(cycled, nil).Times(2)
+	analyzerMock.EXPECT().GetExperiment(ctx, "chaosmeta", "stage-2").Return(newExp("stage-2", v1alpha1.InjectPhaseType, v1alpha1.InjectPhaseType, v1alpha1.SuccessStatusType), nil)
+	analyzerMock.EXPECT().GetExperiment(ctx, "chaosmeta", "stage-0").Return(cycledNext, nil)
+	waitMsg, failMsg, err = getDependencyMessage(ctx, instance)
+	assert.Nil(t, err)
+	assert.Equal(t, "", waitMsg)
+	assert.Equal(t, "dependencies form a cycle: stage-3 -> stage-1 -> stage-0 -> stage-3", failMsg)
+
+	// no dependency
+	waitMsg, failMsg, err = getDependencyMessage(ctx, &v1alpha1.Experiment{})
+	assert.Nil(t, err)
+	assert.Equal(t, "", waitMsg)
+	assert.Equal(t, "", failMsg)
+}
+
+func Test_getResolveRequeueAfter(t *testing.T) {
+	instance := &v1alpha1.Experiment{
+		Spec:   v1alpha1.ExperimentSpec{Scope: v1alpha1.JobScopeType, TargetPhase: v1alpha1.InjectPhaseType},
+		Status: v1alpha1.ExperimentStatus{Phase: v1alpha1.InjectPhaseType, Status: v1alpha1.SuccessStatusType},
+	}
+	assert.Equal(t, resolveRequeueInterval, getResolveRequeueAfter(instance))
+
+	instance.Spec.Reresolve = &v1alpha1.ReresolveSpec{Interval: "1m"}
+	assert.Equal(t, time.Minute, getResolveRequeueAfter(instance))
+
+	instance.Spec.TargetPhase = v1alpha1.RecoverPhaseType
+	assert.Equal(t, time.Duration(0), getResolveRequeueAfter(instance))
+
+	instance.Spec.TargetPhase, instance.Spec.Scope, instance.Spec.Reresolve = v1alpha1.InjectPhaseType, v1alpha1.PodScopeType, nil
+	assert.Equal(t, time.Duration(0), getResolveRequeueAfter(instance))
+}
+
+func Test_isDependencyWaitTimeout(t *testing.T) {
+	now := time.Now()
+	instance := &v1alpha1.Experiment{ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(now.Add(-2 * time.Hour))}}
+	assert.True(t, isDependencyWaitTimeout(instance, time.Hour, now))
+	assert.False(t, isDependencyWaitTimeout(instance, 3*time.Hour, now))
+	assert.False(t, isDependencyWaitTimeout(instance, 0, now))
+}
@@ -0,0 +1,126 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// ReasonInjectFailed and ReasonRecoverFailed are recorded for every failed target
+	ReasonInjectFailed  = "InjectFailed"
+	ReasonRecoverFailed = "RecoverFailed"
+	ReasonPaused        = "Paused"
+	ReasonResumed       = "Resumed"
+	// ReasonWaitingForMutex is recorded when the holder or the queue of the mutex changes
+	ReasonWaitingForMutex = "WaitingForMutex"
+	// ReasonWaitingForDependency is recorded when the dependencies the experiment waits for change
+	ReasonWaitingForDependency = "WaitingForDependency"
+	// ReasonScheduled and ReasonScheduleSkipped are recorded on the experiment with a schedule
+	ReasonScheduled       = "Scheduled"
+	ReasonScheduleSkipped = "ScheduleSkipped"
+	// ReasonRecoverRetried and ReasonRecoverSkipped are recorded on the deleted experiment with failed targets of recovery
+	ReasonRecoverRetried = "RecoverRetried"
+	ReasonRecoverSkipped = "RecoverSkipped"
+	// ReasonClusterUnavailable is recorded when the cluster of the experiment is not registered or its clients fail
+	ReasonClusterUnavailable = "ClusterUnavailable"
+)
+
+// phaseReasons is the reason of the event recorded when the experiment turns to the phase and status
+var phaseReasons = map[v1alpha1.PhaseType]map[v1alpha1.StatusType]string{
+	v1alpha1.InjectPhaseType: {
+		v1alpha1.CreatedStatusType:     "Injecting",
+		v1alpha1.RunningStatusType:     "InjectRunning",
+		v1alpha1.SuccessStatusType:     "Injected",
+		v1alpha1.PartSuccessStatusType: "PartiallyInjected",
+		v1alpha1.FailedStatusType:      "InjectPhaseFailed",
+	},
+	v1alpha1.RecoverPhaseType: {
+		v1alpha1.CreatedStatusType:     "Recovering",
+		v1alpha1.RunningStatusType:     "RecoverRunning",
+		v1alpha1.SuccessStatusType:     "Recovered",
+		v1alpha1.PartSuccessStatusType: "PartiallyRecovered",
+		v1alpha1.FailedStatusType:      "RecoverPhaseFailed",
+	},
+}
+
+type experimentEvent struct {
+	eventType string
+	reason    string
+	message   string
+}
+
+// recordEvents records the events of the transitions from the status before the reconciliation,
+// so that "kubectl describe" shows a timeline of the experiment
+func (r *ExperimentReconciler) recordEvents(instance *v1alpha1.Experiment, before *v1alpha1.ExperimentStatus) {
+	for _, e := range getTransitionEvents(before, &instance.Status) {
+		r.recordEvent(instance, e.eventType, e.reason, e.message)
+	}
+}
+
+func (r *ExperimentReconciler) recordEvent(instance *v1alpha1.Experiment, eventType, reason, message string) {
+	if r.Recorder != nil {
+		r.Recorder.Event(instance, eventType, reason, message)
+	}
+}
+
+func getTransitionEvents(before, after *v1alpha1.ExperimentStatus) []experimentEvent {
+	var events []experimentEvent
+	events = append(events, getTargetFailedEvents(before.Detail.Inject, after.Detail.Inject, ReasonInjectFailed)...)
+	events = append(events, getTargetFailedEvents(before.Detail.Recover, after.Detail.Recover, ReasonRecoverFailed)...)
+
+	if !before.Paused && after.Paused {
+		events = append(events, experimentEvent{corev1.EventTypeNormal, ReasonPaused, "experiment paused, start to recover all the targets"})
+	}
+
+	if after.ResumeTime != "" && after.ResumeTime != before.ResumeTime {
+		events = append(events, experimentEvent{corev1.EventTypeNormal, ReasonResumed, fmt.Sprintf("experiment resumed, start to inject %d targets", len(after.Detail.Inject))})
+	}
+
+	if after.Phase != before.Phase || after.Status != before.Status {
+		if reason, ok := phaseReasons[after.Phase][after.Status]; ok {
+			eventType := corev1.EventTypeNormal
+			if after.Status == v1alpha1.FailedStatusType || after.Status == v1alpha1.PartSuccessStatusType {
+				eventType = corev1.EventTypeWarning
+			}
+			events = append(events, experimentEvent{eventType, reason, after.Message})
+		}
+	}
+
+	return events
+}
+
+// getTargetFailedEvents the targets are matched by uid, a target failed in a previous reconciliation is not recorded again
+func getTargetFailedEvents(before, after []v1alpha1.ExperimentDetailUnit, reason string) []experimentEvent {
+	failed := make(map[string]bool, len(before))
+	for _, unit := range before {
+		if unit.Status == v1alpha1.FailedStatusType {
+			failed[unit.UID] = true
+		}
+	}
+
+	var events []experimentEvent
+	for _, unit := range after {
+		if unit.Status == v1alpha1.FailedStatusType && !failed[unit.UID] {
+			events = append(events, experimentEvent{corev1.EventTypeWarning, reason, fmt.Sprintf("target %s: %s", unit.InjectObjectName, unit.Message)})
+		}
+	}
+
+	return events
+}
@@ -0,0 +1,73 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+	"testing"
+)
+
+func Test_getTransitionEvents(t *testing.T) {
+	before := &v1alpha1.ExperimentStatus{
+		Phase:  v1alpha1.InjectPhaseType,
+		Status: v1alpha1.CreatedStatusType,
+		Detail: v1alpha1.ExperimentDetail{
+			Inject: []v1alpha1.ExperimentDetailUnit{
+				{InjectObjectName: "pod/chaosmeta/chaosmeta-1", UID: "uid1", Status: v1alpha1.CreatedStatusType},
+				{InjectObjectName: "pod/chaosmeta/chaosmeta-2", UID: "uid2", Status: v1alpha1.FailedStatusType},
+				{InjectObjectName: "pod/chaosmeta/chaosmeta-3", UID: "uid3", Status: v1alpha1.CreatedStatusType},
+			},
+		},
+	}
+
+	after := before.DeepCopy()
+	after.Status, after.Message = v1alpha1.RunningStatusType, "create finish, start to solve running status"
+	after.Detail.Inject[0].Status, after.Detail.Inject[0].Message = v1alpha1.FailedStatusType, "experiment inject error: timeout"
+	after.Detail.Inject[2].Status = v1alpha1.RunningStatusType
+
+	events := getTransitionEvents(before, after)
+	assert.Equal(t, []experimentEvent{
+		{corev1.EventTypeWarning, ReasonInjectFailed, "target pod/chaosmeta/chaosmeta-1: experiment inject error: timeout"},
+		{corev1.EventTypeNormal, "InjectRunning", "create finish, start to solve running status"},
+	}, events)
+
+	// nothing changed
+	assert.Empty(t, getTransitionEvents(after, after.DeepCopy()))
+
+	paused := after.DeepCopy()
+	paused.Phase, paused.Status, paused.Paused, paused.Message = v1alpha1.RecoverPhaseType, v1alpha1.PartSuccessStatusType, true, "run part success"
+	events = getTransitionEvents(after, paused)
+	assert.Equal(t, ReasonPaused, events[0].reason)
+	assert.Equal(t, experimentEvent{corev1.EventTypeWarning, "PartiallyRecovered", "run part success"}, events[1])
+}
+
+func TestExperimentReconciler_recordEvents(t *testing.T) {
+	recorder := record.NewFakeRecorder(10)
+	r := &ExperimentReconciler{Recorder: recorder}
+	instance := &v1alpha1.Experiment{
+		Status: v1alpha1.ExperimentStatus{Phase: v1alpha1.RecoverPhaseType, Status: v1alpha1.SuccessStatusType, Message: "run success"},
+	}
+
+	r.recordEvents(instance, &v1alpha1.ExperimentStatus{Phase: v1alpha1.RecoverPhaseType, Status: v1alpha1.RunningStatusType})
+	assert.Equal(t, "Normal Recovered run success", <-recorder.Events)
+
+	// no recorder
+	(&ExperimentReconciler{}).recordEvents(instance, &v1alpha1.ExperimentStatus{})
+}
@@ -0,0 +1,140 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/model"
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"time"
+)
+
+// recoverRetryInterval is the wait before the failed targets of a deleted experiment are recovered again
+const recoverRetryInterval = 30 * time.Second
+
+type deletionAction int
+
+const (
+	// deletionContinue the deleted experiment is still injecting or recovering, it is processed by its phase as usual
+	deletionContinue deletionAction = iota
+	deletionRecover
+	deletionRetryRecover
+	deletionRemoveFinalizer
+)
+
+// reconcileDeletion drives a deleted experiment to recover all the injected targets. The finalizer is removed only when
+// the recovery succeeds, or it is skipped by the force delete annotation. done is false when the experiment needs
+// to be processed by its phase as usual
+func (r *ExperimentReconciler) reconcileDeletion(ctx context.Context, instance *v1alpha1.Experiment) (result ctrl.Result, done bool, err error) {
+	logger := log.FromContext(ctx)
+	if !hasFinalizer(instance) {
+		return ctrl.Result{}, true, nil
+	}
+
+	action, wait := getDeletionAction(instance, time.Now())
+	switch action {
+	case deletionRemoveFinalizer:
+		if !isRecovered(instance) {
+			r.recordEvent(instance, corev1.EventTypeWarning, ReasonRecoverSkipped, "experiment is force deleted, the recovery of targets is skipped")
+		}
+		solveFinalizer(instance)
+		logger.Info(fmt.Sprintf("update Finalizer of %s/%s to: %s", instance.Namespace, instance.Name, instance.ObjectMeta.Finalizers))
+		return ctrl.Result{}, true, r.Update(ctx, instance)
+	case deletionRecover:
+		instance.Spec.TargetPhase = v1alpha1.RecoverPhaseType
+		logger.Info(fmt.Sprintf("update TargetPhase of %s/%s to: %s", instance.Namespace, instance.Name, instance.Spec.TargetPhase))
+		return ctrl.Result{}, true, r.Update(ctx, instance)
+	case deletionRetryRecover:
+		if wait > 0 {
+			return ctrl.Result{RequeueAfter: wait}, true, nil
+		}
+
+		count := retryFailedRecover(instance)
+		logger.Info(fmt.Sprintf("experiment: %s/%s, deleted, retry to recover %d failed targets", instance.Namespace, instance.Name, count))
+		r.recordEvent(instance, corev1.EventTypeWarning, ReasonRecoverRetried, instance.Status.Message)
+		if err := r.Client.Status().Update(ctx, instance); err != nil {
+			return ctrl.Result{}, true, fmt.Errorf("update instance error: %s", err.Error())
+		}
+		return ctrl.Result{}, true, nil
+	}
+
+	return ctrl.Result{}, false, nil
+}
+
+// getDeletionAction returns the next step of a deleted experiment, and the wait before retrying its failed recovery
+func getDeletionAction(instance *v1alpha1.Experiment, now time.Time) (deletionAction, time.Duration) {
+	if isForceDeleted(instance) || isRecovered(instance) {
+		return deletionRemoveFinalizer, 0
+	}
+
+	// a paused experiment is also ended by the target phase, so that it is not resumed after deleted
+	if instance.Spec.TargetPhase != v1alpha1.RecoverPhaseType {
+		return deletionRecover, 0
+	}
+
+	if instance.Status.Phase != v1alpha1.RecoverPhaseType ||
+		(instance.Status.Status != v1alpha1.FailedStatusType && instance.Status.Status != v1alpha1.PartSuccessStatusType) {
+		return deletionContinue, 0
+	}
+
+	updateTime, err := time.ParseInLocation(model.TimeFormat, instance.Status.UpdateTime, time.Local)
+	if err == nil && updateTime.Add(recoverRetryInterval).After(now) {
+		return deletionRetryRecover, updateTime.Add(recoverRetryInterval).Sub(now)
+	}
+
+	return deletionRetryRecover, 0
+}
+
+// retryFailedRecover sets the failed targets of recovery back to created, returns the count of them
+func retryFailedRecover(instance *v1alpha1.Experiment) int {
+	var count int
+	for i := range instance.Status.Detail.Recover {
+		unit := &instance.Status.Detail.Recover[i]
+		if unit.Status == v1alpha1.FailedStatusType {
+			unit.Status, unit.Message, unit.Error = v1alpha1.CreatedStatusType, "experiment is deleted, retry to recover", nil
+			count++
+		}
+	}
+
+	instance.Status.Status = v1alpha1.CreatedStatusType
+	instance.Status.Message = fmt.Sprintf("experiment is deleted, retry to recover %d failed targets", count)
+	instance.Status.UpdateTime = time.Now().Format(model.TimeFormat)
+	return count
+}
+
+// isRecovered returns whether no target of the experiment is left injected, an experiment never started is also recovered
+func isRecovered(instance *v1alpha1.Experiment) bool {
+	return instance.Status.Phase == "" || (instance.Status.Phase == v1alpha1.RecoverPhaseType && instance.Status.Status == v1alpha1.SuccessStatusType)
+}
+
+func isForceDeleted(instance *v1alpha1.Experiment) bool {
+	return instance.Annotations[v1alpha1.ForceDeleteAnnotationKey] == "true"
+}
+
+func hasFinalizer(instance *v1alpha1.Experiment) bool {
+	for _, finalizer := range instance.ObjectMeta.Finalizers {
+		if finalizer == v1alpha1.FinalizerName {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,96 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/model"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"testing"
+	"time"
+)
+
+func Test_getDeletionAction(t *testing.T) {
+	now := time.Now()
+	newExp := func(targetPhase, phase v1alpha1.PhaseType, status v1alpha1.StatusType) *v1alpha1.Experiment {
+		return &v1alpha1.Experiment{
+			ObjectMeta: metav1.ObjectMeta{Finalizers: []string{v1alpha1.FinalizerName}},
+			Spec:       v1alpha1.ExperimentSpec{TargetPhase: targetPhase},
+			Status: v1alpha1.ExperimentStatus{
+				Phase:      phase,
+				Status:     status,
+				UpdateTime: now.Add(-time.Minute).Format(model.TimeFormat),
+			},
+		}
+	}
+
+	tests := []struct {
+		name string
+		exp  *v1alpha1.Experiment
+		want deletionAction
+	}{
+		{"never started", newExp(v1alpha1.InjectPhaseType, "", ""), deletionRemoveFinalizer},
+		{"recovered", newExp(v1alpha1.RecoverPhaseType, v1alpha1.RecoverPhaseType, v1alpha1.SuccessStatusType), deletionRemoveFinalizer},
+		{"injecting", newExp(v1alpha1.InjectPhaseType, v1alpha1.InjectPhaseType, v1alpha1.CreatedStatusType), deletionRecover},
+		{"injected", newExp(v1alpha1.InjectPhaseType, v1alpha1.InjectPhaseType, v1alpha1.SuccessStatusType), deletionRecover},
+		{"paused", newExp(v1alpha1.InjectPhaseType, v1alpha1.RecoverPhaseType, v1alpha1.RunningStatusType), deletionRecover},
+		{"waiting to recover", newExp(v1alpha1.RecoverPhaseType, v1alpha1.InjectPhaseType, v1alpha1.RunningStatusType), deletionContinue},
+		{"recovering", newExp(v1alpha1.RecoverPhaseType, v1alpha1.RecoverPhaseType, v1alpha1.RunningStatusType), deletionContinue},
+		{"recover failed", newExp(v1alpha1.RecoverPhaseType, v1alpha1.RecoverPhaseType, v1alpha1.FailedStatusType), deletionRetryRecover},
+		{"recover part success", newExp(v1alpha1.RecoverPhaseType, v1alpha1.RecoverPhaseType, v1alpha1.PartSuccessStatusType), deletionRetryRecover},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, wait := getDeletionAction(tt.exp, now)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, time.Duration(0), wait)
+		})
+	}
+
+	// the failed recovery was retried just now
+	exp := newExp(v1alpha1.RecoverPhaseType, v1alpha1.RecoverPhaseType, v1alpha1.FailedStatusType)
+	exp.Status.UpdateTime = now.Format(model.TimeFormat)
+	got, wait := getDeletionAction(exp, now)
+	assert.Equal(t, deletionRetryRecover, got)
+	assert.True(t, wait > 0 && wait <= recoverRetryInterval)
+
+	exp.Annotations = map[string]string{v1alpha1.ForceDeleteAnnotationKey: "true"}
+	got, _ = getDeletionAction(exp, now)
+	assert.Equal(t, deletionRemoveFinalizer, got)
+}
+
+func Test_retryFailedRecover(t *testing.T) {
+	exp := &v1alpha1.Experiment{
+		Status: v1alpha1.ExperimentStatus{
+			Phase:  v1alpha1.RecoverPhaseType,
+			Status: v1alpha1.PartSuccessStatusType,
+			Detail: v1alpha1.ExperimentDetail{
+				Recover: []v1alpha1.ExperimentDetailUnit{
+					{UID: "uid1", Status: v1alpha1.SuccessStatusType},
+					{UID: "uid2", Status: v1alpha1.FailedStatusType, Error: &v1alpha1.ErrorInfo{Code: "RecoverFailed"}},
+				},
+			},
+		},
+	}
+
+	assert.Equal(t, 1, retryFailedRecover(exp))
+	assert.Equal(t, v1alpha1.CreatedStatusType, exp.Status.Status)
+	assert.Equal(t, v1alpha1.SuccessStatusType, exp.Status.Detail.Recover[0].Status)
+	assert.Equal(t, v1alpha1.CreatedStatusType, exp.Status.Detail.Recover[1].Status)
+	assert.Nil(t, exp.Status.Detail.Recover[1].Error)
+}
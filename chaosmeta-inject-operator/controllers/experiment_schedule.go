@@ -0,0 +1,174 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"github.com/robfig/cron"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/model"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"reflect"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sort"
+	"time"
+)
+
+const defaultScheduleHistoryLimit = 3
+
+// reconcileSchedule the experiment with a schedule is not injected itself, it creates a child experiment on every cron time.
+// A cron time is skipped when the child of the last time is still running
+func (r *ExperimentReconciler) reconcileSchedule(ctx context.Context, instance *v1alpha1.Experiment) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+	if !instance.ObjectMeta.DeletionTimestamp.IsZero() {
+		// the children are deleted by garbage collection with the owner reference
+		solveFinalizer(instance)
+		logger.Info(fmt.Sprintf("update Finalizer of %s/%s to: %s", instance.Namespace, instance.Name, instance.ObjectMeta.Finalizers))
+		return ctrl.Result{}, r.Update(ctx, instance)
+	}
+
+	before := instance.Status.DeepCopy()
+	sched, err := cron.ParseStandard(instance.Spec.Schedule.Cron)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("parse cron error: %s", err.Error())
+	}
+
+	childList := &v1alpha1.ExperimentList{}
+	if err := r.Client.List(ctx, childList, client.InNamespace(instance.Namespace),
+		client.MatchingLabels{v1alpha1.ScheduledByLabelKey: instance.Name}); err != nil {
+		return ctrl.Result{}, fmt.Errorf("list child experiments error: %s", err.Error())
+	}
+
+	var active []string
+	for _, child := range childList.Items {
+		if !isScheduleChildFinished(&child) {
+			active = append(active, child.Name)
+		}
+	}
+
+	for _, child := range getPrunedScheduleChildren(childList.Items, getScheduleHistoryLimit(instance.Spec.Schedule)) {
+		logger.Info(fmt.Sprintf("experiment: %s/%s, delete history child: %s", instance.Namespace, instance.Name, child.Name))
+		if err := r.Client.Delete(ctx, child); err != nil && !errors.IsNotFound(err) {
+			return ctrl.Result{}, fmt.Errorf("delete child experiment %s error: %s", child.Name, err.Error())
+		}
+	}
+
+	lastTime := instance.CreationTimestamp.Time
+	if instance.Status.LastScheduleTime != "" {
+		if last, err := time.ParseInLocation(model.TimeFormat, instance.Status.LastScheduleTime, time.Local); err == nil {
+			lastTime = last
+		}
+	}
+
+	now := time.Now()
+	dueTime, nextTime := getScheduleTimes(sched, lastTime, now)
+	if !dueTime.IsZero() {
+		if len(active) != 0 {
+			instance.Status.Message = fmt.Sprintf("skip schedule at %s, child experiments are still running: %v", dueTime.Format(model.TimeFormat), active)
+			r.recordEvent(instance, corev1.EventTypeWarning, ReasonScheduleSkipped, instance.Status.Message)
+		} else {
+			child := newScheduleChild(instance, dueTime)
+			logger.Info(fmt.Sprintf("experiment: %s/%s, create child: %s", instance.Namespace, instance.Name, child.Name))
+			if err := r.Client.Create(ctx, child); err != nil && !errors.IsAlreadyExists(err) {
+				return ctrl.Result{}, fmt.Errorf("create child experiment error: %s", err.Error())
+			}
+			instance.Status.Message = fmt.Sprintf("create child experiment: %s", child.Name)
+			r.recordEvent(instance, corev1.EventTypeNormal, ReasonScheduled, instance.Status.Message)
+		}
+		instance.Status.LastScheduleTime = dueTime.Format(model.TimeFormat)
+	}
+
+	// the status is only updated when it changes, otherwise every update triggers another reconcile at once
+	instance.Status.NextScheduleTime = nextTime.Format(model.TimeFormat)
+	if !reflect.DeepEqual(before, &instance.Status) {
+		instance.Status.UpdateTime = now.Format(model.TimeFormat)
+		if err := r.Client.Status().Update(ctx, instance); err != nil {
+			return ctrl.Result{}, fmt.Errorf("update instance error: %s", err.Error())
+		}
+	}
+
+	return ctrl.Result{RequeueAfter: nextTime.Sub(now)}, nil
+}
+
+// getScheduleTimes returns the latest missed cron time after last, zero means nothing is due, and the next cron time after now
+func getScheduleTimes(sched cron.Schedule, last, now time.Time) (time.Time, time.Time) {
+	var due time.Time
+	next := sched.Next(last)
+	for !next.After(now) {
+		due, next = next, sched.Next(next)
+	}
+
+	return due, next
+}
+
+// getPrunedScheduleChildren returns the oldest finished children beyond the history limit
+func getPrunedScheduleChildren(children []v1alpha1.Experiment, limit int) []*v1alpha1.Experiment {
+	var finished []*v1alpha1.Experiment
+	for i := range children {
+		if isScheduleChildFinished(&children[i]) && children[i].DeletionTimestamp.IsZero() {
+			finished = append(finished, &children[i])
+		}
+	}
+
+	if len(finished) <= limit {
+		return nil
+	}
+
+	sort.Slice(finished, func(i, j int) bool {
+		return finished[i].CreationTimestamp.Before(&finished[j].CreationTimestamp)
+	})
+
+	return finished[:len(finished)-limit]
+}
+
+// isScheduleChildFinished a started child is finished when it holds nothing injected, the same as releasing a mutex
+func isScheduleChildFinished(child *v1alpha1.Experiment) bool {
+	return child.Status.Phase != "" && !isMutexHeld(child)
+}
+
+func getScheduleHistoryLimit(schedule *v1alpha1.ScheduleSpec) int {
+	if schedule.HistoryLimit == nil {
+		return defaultScheduleHistoryLimit
+	}
+	return int(*schedule.HistoryLimit)
+}
+
+func newScheduleChild(instance *v1alpha1.Experiment, scheduleTime time.Time) *v1alpha1.Experiment {
+	labels := map[string]string{}
+	for k, v := range instance.Labels {
+		labels[k] = v
+	}
+	labels[v1alpha1.ScheduledByLabelKey] = instance.Name
+
+	spec := instance.Spec.DeepCopy()
+	spec.Schedule, spec.TargetPhase = nil, v1alpha1.InjectPhaseType
+
+	return &v1alpha1.Experiment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            fmt.Sprintf("%s-%d", instance.Name, scheduleTime.Unix()),
+			Namespace:       instance.Namespace,
+			Labels:          labels,
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(instance, v1alpha1.GroupVersion.WithKind("Experiment"))},
+		},
+		Spec: *spec,
+	}
+}
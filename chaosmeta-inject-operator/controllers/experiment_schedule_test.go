@@ -0,0 +1,75 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"github.com/robfig/cron"
+	"github.com/stretchr/testify/assert"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"testing"
+	"time"
+)
+
+func Test_getScheduleTimes(t *testing.T) {
+	sched, err := cron.ParseStandard("0 * * * *")
+	assert.NoError(t, err)
+
+	last := time.Date(2023, 6, 1, 10, 0, 0, 0, time.Local)
+	due, next := getScheduleTimes(sched, last, last.Add(30*time.Minute))
+	assert.True(t, due.IsZero())
+	assert.Equal(t, last.Add(time.Hour), next)
+
+	// only the latest missed time is due
+	due, next = getScheduleTimes(sched, last, last.Add(3*time.Hour+time.Minute))
+	assert.Equal(t, last.Add(3*time.Hour), due)
+	assert.Equal(t, last.Add(4*time.Hour), next)
+
+	due, next = getScheduleTimes(sched, last, last.Add(time.Hour))
+	assert.Equal(t, last.Add(time.Hour), due)
+	assert.Equal(t, last.Add(2*time.Hour), next)
+}
+
+func Test_getPrunedScheduleChildren(t *testing.T) {
+	base := time.Date(2023, 6, 1, 10, 0, 0, 0, time.Local)
+	newChild := func(name string, hour int, phase v1alpha1.PhaseType, status v1alpha1.StatusType) v1alpha1.Experiment {
+		return v1alpha1.Experiment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				CreationTimestamp: metav1.NewTime(base.Add(time.Duration(hour) * time.Hour)),
+			},
+			Status: v1alpha1.ExperimentStatus{Phase: phase, Status: status},
+		}
+	}
+
+	children := []v1alpha1.Experiment{
+		newChild("c3", 3, v1alpha1.RecoverPhaseType, v1alpha1.SuccessStatusType),
+		newChild("c1", 1, v1alpha1.RecoverPhaseType, v1alpha1.SuccessStatusType),
+		newChild("c2", 2, v1alpha1.InjectPhaseType, v1alpha1.FailedStatusType),
+		newChild("c0", 0, v1alpha1.InjectPhaseType, v1alpha1.SuccessStatusType),
+		newChild("c4", 4, v1alpha1.RecoverPhaseType, v1alpha1.RunningStatusType),
+	}
+
+	var names []string
+	for _, child := range getPrunedScheduleChildren(children, 1) {
+		names = append(names, child.Name)
+	}
+	assert.Equal(t, []string{"c1", "c2"}, names)
+
+	assert.Empty(t, getPrunedScheduleChildren(children, 3))
+	assert.Len(t, getPrunedScheduleChildren(children, 0), 3)
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/model"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/multicluster"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/scopehandler"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"time"
+)
+
+// TargetQueryReconciler resolves the targets of a TargetQuery without injecting them
+type TargetQueryReconciler struct {
+	client.Client
+}
+
+//+kubebuilder:rbac:groups=chaosmeta.io,resources=targetqueries,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=chaosmeta.io,resources=targetqueries/status,verbs=get;update;patch
+
+// Reconcile resolves the targets once for every generation of the spec
+func (r *TargetQueryReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	instance, logger := &v1alpha1.TargetQuery{}, log.FromContext(ctx)
+	if err := r.Client.Get(ctx, req.NamespacedName, instance); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+
+		return ctrl.Result{}, fmt.Errorf("get instance error: %s", err.Error())
+	}
+
+	if instance.Status.Status != "" && instance.Status.ObservedGeneration == instance.Generation {
+		return ctrl.Result{}, nil
+	}
+
+	if instance.Spec.Cluster != "" {
+		targetCluster, err := multicluster.GetCluster(ctx, instance.Spec.Cluster)
+		if err != nil {
+			return ctrl.Result{}, fmt.Errorf("get cluster error: %s", err.Error())
+		}
+
+		ctx = multicluster.WithCluster(ctx, targetCluster)
+	}
+
+	resolveTargetQuery(ctx, instance)
+	logger.Info(fmt.Sprintf("target query: %s/%s, resolved %d targets: %s", instance.Namespace, instance.Name, len(instance.Status.Targets), instance.Status.Message))
+	if err := r.Client.Status().Update(ctx, instance); err != nil {
+		return ctrl.Result{}, fmt.Errorf("update instance error: %s", err.Error())
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// resolveTargetQuery selects the targets the same way as the initial targets of an experiment
+func resolveTargetQuery(ctx context.Context, query *v1alpha1.TargetQuery) {
+	nowTime := time.Now().Format(model.TimeFormat)
+	query.Status = v1alpha1.TargetQueryStatus{
+		Status:             v1alpha1.FailedStatusType,
+		ObservedGeneration: query.Generation,
+		ResolvedTime:       nowTime,
+	}
+
+	if query.Spec.Experiment == nil {
+		query.Status.Message = "experiment is not provided"
+		return
+	}
+
+	spec := &v1alpha1.ExperimentSpec{
+		Scope:      query.Spec.Scope,
+		RangeMode:  query.Spec.RangeMode,
+		Experiment: query.Spec.Experiment,
+		Selector:   query.Spec.Selector,
+		Cluster:    query.Spec.Cluster,
+	}
+
+	// a spec the webhook rejects could never select the previewed targets, the duration is not needed by the preview
+	exp := &v1alpha1.Experiment{ObjectMeta: metav1.ObjectMeta{Name: query.Name, Namespace: query.Namespace}, Spec: *spec}
+	exp.Spec.TargetPhase, exp.Spec.Experiment = v1alpha1.InjectPhaseType, query.Spec.Experiment.DeepCopy()
+	if exp.Spec.Experiment.Duration == "" {
+		exp.Spec.Experiment.Duration = "0s"
+	}
+	if err := exp.ValidateCreate(); err != nil {
+		query.Status.Message = fmt.Sprintf("spec is invalid for an experiment: %s", err.Error())
+		return
+	}
+
+	scopeHandler := scopehandler.GetScopeHandler(spec.Scope)
+	if scopeHandler == nil {
+		query.Status.Message = fmt.Sprintf("not support scope: %s", spec.Scope)
+		return
+	}
+
+	injectObjects, err := scopeHandler.ConvertSelector(ctx, spec)
+	if err != nil {
+		query.Status.Message = fmt.Sprintf("convert selector to inject object error: %s", err.Error())
+		return
+	}
+
+	var domains map[string]string
+	if spec.RangeMode != nil && spec.RangeMode.Spread != nil {
+		domains, err = getTopologyDomains(ctx, injectObjects, spec.RangeMode.Spread.TopologyKey)
+		if err != nil {
+			query.Status.Message = fmt.Sprintf("get topology of targets error: %s", err.Error())
+			return
+		}
+	}
+
+	candidateCount := len(injectObjects)
+	injectObjects, query.Status.Selection = solveRange(injectObjects, spec.RangeMode, domains)
+	query.Status.Targets = make([]v1alpha1.QueriedTarget, len(injectObjects))
+	for i, unitInjectObj := range injectObjects {
+		query.Status.Targets[i] = v1alpha1.QueriedTarget{
+			Name:     unitInjectObj.GetObjectName(),
+			Executor: scopehandler.GetTargetExecutor(unitInjectObj),
+			Resolved: scopehandler.GetResolvedTarget(unitInjectObj, nowTime),
+		}
+	}
+
+	query.Status.Status = v1alpha1.SuccessStatusType
+	query.Status.Message = fmt.Sprintf("%d of %d matching targets selected", len(injectObjects), candidateCount)
+	if query.Status.Selection != nil && spec.RangeMode.Seed == nil {
+		// without a seed, an experiment picks its own random subset of the same candidates
+		query.Status.Message += fmt.Sprintf(", an experiment only selects the same targets with \"rangeMode.seed\": %d", query.Status.Selection.Seed)
+	}
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *TargetQueryReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.TargetQuery{}).
+		Complete(r)
+}
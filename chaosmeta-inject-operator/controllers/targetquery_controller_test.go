@@ -0,0 +1,119 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"github.com/agiledragon/gomonkey"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	mockscopehandler "github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/mock/scopehandler"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/model"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/scopehandler"
+	"testing"
+)
+
+func Test_resolveTargetQuery(t *testing.T) {
+	var (
+		ctrl  = gomock.NewController(t)
+		ctx   = context.Background()
+		seed  = int64(1)
+		query = &v1alpha1.TargetQuery{
+			Spec: v1alpha1.TargetQuerySpec{
+				Scope: v1alpha1.PodScopeType,
+				RangeMode: &v1alpha1.RangeMode{
+					Type:  v1alpha1.CountRangeType,
+					Value: 2,
+					Seed:  &seed,
+				},
+				Experiment: &v1alpha1.ExperimentCommon{
+					Target: "cpu",
+					Fault:  "burn",
+				},
+				Selector: []v1alpha1.SelectorUnit{
+					{
+						Namespace: "chaosmeta",
+					},
+				},
+			},
+		}
+	)
+	query.Generation = 2
+
+	newObjects := func() []model.AtomicObject {
+		var objects []model.AtomicObject
+		for i := 0; i < 3; i++ {
+			objects = append(objects, &model.PodObject{
+				Namespace:     "chaosmeta",
+				PodName:       fmt.Sprintf("chaosmeta-%d", i),
+				NodeName:      "node-1",
+				ContainerName: "nginx",
+			})
+		}
+		return objects
+	}
+
+	defer ctrl.Finish()
+	scopeHandlerMock := mockscopehandler.NewMockScopeHandler(ctrl)
+	scopeHandlerMock.EXPECT().ConvertSelector(ctx, gomock.Any()).Return(newObjects(), nil)
+	gomonkey.ApplyFunc(scopehandler.GetScopeHandler, func(v1alpha1.ScopeType) scopehandler.ScopeHandler {
+		return scopeHandlerMock
+	})
+
+	resolveTargetQuery(ctx, query)
+	assert.Equal(t, v1alpha1.SuccessStatusType, query.Status.Status)
+	assert.Equal(t, int64(2), query.Status.ObservedGeneration)
+	assert.Equal(t, 2, len(query.Status.Targets))
+	assert.Equal(t, 3, query.Status.Selection.CandidateCount)
+	assert.Equal(t, "2 of 3 matching targets selected", query.Status.Message)
+	assert.Equal(t, "node-1", query.Status.Targets[0].Resolved.NodeName)
+
+	// the experiment with the same seed picks the same targets
+	exp := &v1alpha1.Experiment{Spec: v1alpha1.ExperimentSpec{
+		Scope:       query.Spec.Scope,
+		RangeMode:   query.Spec.RangeMode,
+		Experiment:  query.Spec.Experiment,
+		Selector:    query.Spec.Selector,
+		TargetPhase: v1alpha1.InjectPhaseType,
+	}}
+	scopeHandlerMock.EXPECT().ConvertSelector(ctx, gomock.Any()).Return(newObjects(), nil)
+	initProcess(ctx, exp)
+	for i := range query.Status.Targets {
+		assert.Equal(t, query.Status.Targets[i].Name, exp.Status.Detail.Inject[i].InjectObjectName)
+	}
+
+	scopeHandlerMock.EXPECT().ConvertSelector(ctx, gomock.Any()).Return(nil, fmt.Errorf("list pods error"))
+	resolveTargetQuery(ctx, query)
+	assert.Equal(t, v1alpha1.FailedStatusType, query.Status.Status)
+	assert.Equal(t, 0, len(query.Status.Targets))
+
+	// the selection without a seed is not reproducible by an experiment
+	query.Spec.RangeMode.Seed = nil
+	scopeHandlerMock.EXPECT().ConvertSelector(ctx, gomock.Any()).Return(newObjects(), nil)
+	resolveTargetQuery(ctx, query)
+	assert.Equal(t, v1alpha1.SuccessStatusType, query.Status.Status)
+	assert.Equal(t, fmt.Sprintf("2 of 3 matching targets selected, an experiment only selects the same targets with \"rangeMode.seed\": %d",
+		query.Status.Selection.Seed), query.Status.Message)
+
+	// the spec rejected by the webhook of experiment is not resolved
+	query.Spec.RangeMode.Value = 0
+	resolveTargetQuery(ctx, query)
+	assert.Equal(t, v1alpha1.FailedStatusType, query.Status.Status)
+	assert.Contains(t, query.Status.Message, "spec is invalid for an experiment")
+}
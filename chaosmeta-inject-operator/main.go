@@ -22,20 +22,29 @@ import (
 	"fmt"
 	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/restclient"
 	"os"
+	"strings"
 	"time"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
 
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/cloudevents"
 	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/common"
 	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/config"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/drill"
 	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/executor/remoteexecutor"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/gc"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/metrics"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/multicluster"
 	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/selector"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/sidecar"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
@@ -61,14 +70,27 @@ func init() {
 }
 
 func main() {
-	//var metricsAddr string
+	var metricsAddr string
+	var poolCount, targetConcurrency, maxConcurrentReconciles int
 	var enableLeaderElection bool
+	var leaseDuration, renewDeadline, retryPeriod time.Duration
 	var probeAddr string
-	//flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
+	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
+	flag.IntVar(&poolCount, "pool-count", 0, "The max goroutines solving targets at the same time, overrides \"worker.poolCount\" of config if positive.")
+	flag.IntVar(&targetConcurrency, "target-concurrency", 0,
+		"The max targets of one experiment solved at the same time, overrides \"worker.targetConcurrency\" of config if positive.")
+	flag.IntVar(&maxConcurrentReconciles, "max-concurrent-reconciles", 0,
+		"The max experiments reconciled at the same time, overrides \"worker.maxConcurrentReconciles\" of config if positive.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flag.DurationVar(&leaseDuration, "leader-elect-lease-duration", 15*time.Second,
+		"The duration that non-leader replicas wait before taking over the leadership of a lost leader.")
+	flag.DurationVar(&renewDeadline, "leader-elect-renew-deadline", 10*time.Second,
+		"The duration that the leader retries refreshing the leadership before giving up.")
+	flag.DurationVar(&retryPeriod, "leader-elect-retry-period", 2*time.Second,
+		"The duration that the replicas wait between tries of the leader election actions.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -78,8 +100,8 @@ func main() {
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
-		Scheme: scheme,
-		//MetricsBindAddress:     metricsAddr,
+		Scheme:                 scheme,
+		MetricsBindAddress:     metricsAddr,
 		Port:                   9443,
 		HealthProbeBindAddress: probeAddr,
 		LeaderElection:         enableLeaderElection,
@@ -94,7 +116,15 @@ func main() {
 		// the manager stops, so would be fine to enable this option. However,
 		// if you are doing or is intended to do any operation such as perform cleanups
 		// after the manager stops then its usage might be unsafe.
-		// LeaderElectionReleaseOnCancel: true,
+		//
+		// The program exits right after the manager stops, and a lost leadership stops the manager,
+		// so an old leader never keeps injecting after a new one takes over.
+		LeaderElectionReleaseOnCancel: true,
+		LeaseDuration:                 &leaseDuration,
+		RenewDeadline:                 &renewDeadline,
+		RetryPeriod:                   &retryPeriod,
+		// the kubeconfig secrets of the registered clusters are read directly, instead of caching all the secrets
+		ClientDisableCacheFor: []client.Object{&corev1.Secret{}},
 	})
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
@@ -111,8 +141,24 @@ func main() {
 	setupLog.Info(fmt.Sprintf("set main config success: %v", mainConfig))
 
 	selector.SetupAnalyzer(mgr.GetClient())
+	multicluster.SetupRegistry(mgr.GetClient(), mgr.GetScheme(), getOperatorNamespace())
+	overrideWorkerConfig(&mainConfig.Worker, poolCount, targetConcurrency, maxConcurrentReconciles)
+	if mainConfig.Worker.PoolCount <= 0 {
+		setupLog.Error(fmt.Errorf("goroutine pool count is invalid"), "must provide a positive integer")
+		os.Exit(1)
+	}
 	common.SetGoroutinePool(mainConfig.Worker.PoolCount)
 	setupLog.Info(fmt.Sprintf("set goroutine pool success: %d", mainConfig.Worker.PoolCount))
+	common.SetTargetConcurrency(mainConfig.Worker.TargetConcurrency)
+	setupLog.Info(fmt.Sprintf("set target concurrency success: %d", mainConfig.Worker.TargetConcurrency))
+	if err := metrics.Register(); err != nil {
+		setupLog.Error(err, "register metrics error")
+		os.Exit(1)
+	}
+	common.SetInjectLatencySLO(mainConfig.SLO.InjectLatency)
+	setupLog.Info(fmt.Sprintf("set inject latency slo success: %ds", mainConfig.SLO.InjectLatency))
+	cloudevents.SetSink(mainConfig.CloudEvents.Sink, mainConfig.CloudEvents.Source)
+	setupLog.Info(fmt.Sprintf("set cloud events sink success: %s", mainConfig.CloudEvents.Sink))
 
 	// create APIServer client
 	t := []injectv1alpha1.CloudTargetType{
@@ -138,16 +184,41 @@ func main() {
 
 	// start watching
 	if err = (&controllers.ExperimentReconciler{
-		Client: mgr.GetClient(),
+		Client:                  mgr.GetClient(),
+		Recorder:                mgr.GetEventRecorderFor("chaosmeta-inject-operator"),
+		MaxConcurrentReconciles: mainConfig.Worker.MaxConcurrentReconciles,
+		DependencyWaitTimeout:   time.Duration(mainConfig.Dependency.WaitTimeout) * time.Second,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Experiment")
 		os.Exit(1)
 	}
 
+	if err = (&controllers.TargetQueryReconciler{
+		Client: mgr.GetClient(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "TargetQuery")
+		os.Exit(1)
+	}
+
+	if err := injectv1alpha1.SetDurationDefaults(mainConfig.Webhook.DefaultDuration, mainConfig.Webhook.MaxDuration); err != nil {
+		setupLog.Error(err, "set duration defaults of webhook error")
+		os.Exit(1)
+	}
 	if err = (&injectv1alpha1.Experiment{}).SetupWebhookWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create webhook", "webhook", "Experiment")
 		os.Exit(1)
 	}
+	if mainConfig.Executor.SidecarConfig.Image != "" {
+		sidecar.SetupWebhookWithManager(mgr, &mainConfig.Executor)
+		if err = (&sidecar.SecretReconciler{
+			Client: mgr.GetClient(),
+			Config: &mainConfig.Executor,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "SidecarSecret")
+			os.Exit(1)
+		}
+		setupLog.Info(fmt.Sprintf("sidecar injection webhook enabled: %s", mainConfig.Executor.SidecarConfig.Image))
+	}
 	//+kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -159,12 +230,38 @@ func main() {
 		os.Exit(1)
 	}
 
+	if mainConfig.Drill.Interval > 0 {
+		if err := mgr.Add(drill.NewDriller(mgr.GetClient(), mainConfig.Drill)); err != nil {
+			setupLog.Error(err, "unable to set up recovery drill")
+			os.Exit(1)
+		}
+		setupLog.Info(fmt.Sprintf("set recovery drill success, interval: %ds", mainConfig.Drill.Interval))
+	}
+
 	// set autoRecoverTicker = config.ticker
+	// the platform relies on the gc to delete the fault experiments it creates
+	if mainConfig.GC.Interval <= 0 {
+		setupLog.Error(fmt.Errorf("gc interval is invalid"), "must provide a positive integer")
+		os.Exit(1)
+	}
+	if err := mgr.Add(gc.NewCollector(mgr.GetClient(), mainConfig.GC)); err != nil {
+		setupLog.Error(err, "unable to set up experiment gc")
+		os.Exit(1)
+	}
+	setupLog.Info(fmt.Sprintf("set experiment gc success, interval: %ds", mainConfig.GC.Interval))
+
 	if mainConfig.Ticker.AutoCheckInterval <= 0 {
 		setupLog.Error(fmt.Errorf("ticker interval is invalid"), "must provide a positive integer")
 		os.Exit(1)
 	}
-	go autoRecoverChecker(context.Background(), mainConfig.Ticker.AutoCheckInterval, mgr.GetClient())
+	// the runnables added to manager only run in the leader, as the reconciliation does
+	if err := mgr.Add(manager.RunnableFunc(func(ctx context.Context) error {
+		autoRecoverChecker(ctx, mainConfig.Ticker.AutoCheckInterval, mgr.GetClient())
+		return nil
+	})); err != nil {
+		setupLog.Error(err, "unable to set up auto recover checker")
+		os.Exit(1)
+	}
 
 	setupLog.Info("starting manager")
 	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
@@ -173,30 +270,73 @@ func main() {
 	}
 }
 
+// overrideWorkerConfig the positive flags take precedence over the config file
+// getOperatorNamespace returns the namespace of the operator pod, env POD_NAMESPACE is used when run out of cluster
+func getOperatorNamespace() string {
+	if ns := os.Getenv("POD_NAMESPACE"); ns != "" {
+		return ns
+	}
+
+	ns, err := os.ReadFile("/var/run/secrets/kubernetes.io/serviceaccount/namespace")
+	if err != nil {
+		setupLog.Error(err, "read namespace of operator error, cluster registry is not available")
+		return ""
+	}
+
+	return strings.TrimSpace(string(ns))
+}
+
+func overrideWorkerConfig(worker *config.WorkerConfig, poolCount, targetConcurrency, maxConcurrentReconciles int) {
+	if poolCount > 0 {
+		worker.PoolCount = poolCount
+	}
+	if targetConcurrency > 0 {
+		worker.TargetConcurrency = targetConcurrency
+	}
+	if maxConcurrentReconciles > 0 {
+		worker.MaxConcurrentReconciles = maxConcurrentReconciles
+	}
+}
+
 func autoRecoverChecker(ctx context.Context, interval int, c client.Client) {
 	logger, ticker := log.FromContext(ctx), time.NewTicker(time.Duration(interval)*time.Second)
 	defer ticker.Stop()
 
 	logger.Info(fmt.Sprintf("start auto recover checker success, ticker second: %d", interval))
 	for {
-		<-ticker.C
-		autoRecover(ctx, c)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			autoRecover(ctx, c)
+		}
 	}
 }
 
 func autoRecover(ctx context.Context, c client.Client) {
 	logger := log.FromContext(ctx)
-	expList, err := selector.GetAnalyzer().GetExperimentListByPhase(ctx, string(injectv1alpha1.InjectPhaseType))
-	if err != nil {
-		logger.Error(err, fmt.Sprintf("get experiment list of phase[%s] error", injectv1alpha1.InjectPhaseType))
-		return
+	var exp []injectv1alpha1.Experiment
+	// a paused experiment is in the recover phase, it is ended instead of waiting for a resume after its duration
+	for _, phase := range []injectv1alpha1.PhaseType{injectv1alpha1.InjectPhaseType, injectv1alpha1.RecoverPhaseType} {
+		expList, err := selector.GetAnalyzer().GetExperimentListByPhase(ctx, string(phase))
+		if err != nil {
+			logger.Error(err, fmt.Sprintf("get experiment list of phase[%s] error", phase))
+			return
+		}
+		exp = append(exp, expList.Items...)
 	}
 
-	exp := expList.Items
 	for i := range exp {
-		if exp[i].Status.Status == injectv1alpha1.CreatedStatusType ||
-			exp[i].Status.Status == injectv1alpha1.RunningStatusType ||
-			exp[i].Spec.TargetPhase == injectv1alpha1.RecoverPhaseType {
+		if exp[i].Spec.TargetPhase == injectv1alpha1.RecoverPhaseType {
+			continue
+		}
+
+		if exp[i].Status.Phase == injectv1alpha1.RecoverPhaseType && !exp[i].Status.Paused {
+			continue
+		}
+
+		if exp[i].Status.Phase == injectv1alpha1.InjectPhaseType && (exp[i].Status.Status == injectv1alpha1.CreatedStatusType ||
+			exp[i].Status.Status == injectv1alpha1.RunningStatusType) {
 			continue
 		}
 
@@ -50,6 +50,51 @@ func (mr *MockScopeHandlerMockRecorder) CheckAlive(ctx, injectObject interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckAlive", reflect.TypeOf((*MockScopeHandler)(nil).CheckAlive), ctx, injectObject)
 }
 
+// Diagnose mocks base method.
+func (m *MockScopeHandler) Diagnose(ctx context.Context, injectObject model.AtomicObject) (*model.AgentDiagnostics, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Diagnose", ctx, injectObject)
+	ret0, _ := ret[0].(*model.AgentDiagnostics)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Diagnose indicates an expected call of Diagnose.
+func (mr *MockScopeHandlerMockRecorder) Diagnose(ctx, injectObject interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Diagnose", reflect.TypeOf((*MockScopeHandler)(nil).Diagnose), ctx, injectObject)
+}
+
+// Snapshot mocks base method.
+func (m *MockScopeHandler) Snapshot(ctx context.Context, injectObject model.AtomicObject) (*v1alpha1.EnvSnapshot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Snapshot", ctx, injectObject)
+	ret0, _ := ret[0].(*v1alpha1.EnvSnapshot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Snapshot indicates an expected call of Snapshot.
+func (mr *MockScopeHandlerMockRecorder) Snapshot(ctx, injectObject interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Snapshot", reflect.TypeOf((*MockScopeHandler)(nil).Snapshot), ctx, injectObject)
+}
+
+// Preflight mocks base method.
+func (m *MockScopeHandler) Preflight(ctx context.Context, injectObject model.AtomicObject, expArgs *v1alpha1.ExperimentCommon) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Preflight", ctx, injectObject, expArgs)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Preflight indicates an expected call of Preflight.
+func (mr *MockScopeHandlerMockRecorder) Preflight(ctx, injectObject, expArgs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Preflight", reflect.TypeOf((*MockScopeHandler)(nil).Preflight), ctx, injectObject, expArgs)
+}
+
 // ConvertSelector mocks base method.
 func (m *MockScopeHandler) ConvertSelector(ctx context.Context, spec *v1alpha1.ExperimentSpec) ([]model.AtomicObject, error) {
 	m.ctrl.T.Helper()
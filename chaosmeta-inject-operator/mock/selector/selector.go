@@ -36,6 +36,96 @@ func (m *MockIAnalyzer) EXPECT() *MockIAnalyzerMockRecorder {
 	return m.recorder
 }
 
+// FilterNodeListByField mocks base method.
+func (m *MockIAnalyzer) FilterNodeListByField(ctx context.Context, nodeList []*model.NodeObject, field *v1alpha1.FieldSelector) ([]*model.NodeObject, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FilterNodeListByField", ctx, nodeList, field)
+	ret0, _ := ret[0].([]*model.NodeObject)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FilterNodeListByField indicates an expected call of FilterNodeListByField.
+func (mr *MockIAnalyzerMockRecorder) FilterNodeListByField(ctx, nodeList, field interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FilterNodeListByField", reflect.TypeOf((*MockIAnalyzer)(nil).FilterNodeListByField), ctx, nodeList, field)
+}
+
+// FilterPodListByField mocks base method.
+func (m *MockIAnalyzer) FilterPodListByField(ctx context.Context, podList []*model.PodObject, field *v1alpha1.FieldSelector) ([]*model.PodObject, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FilterPodListByField", ctx, podList, field)
+	ret0, _ := ret[0].([]*model.PodObject)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FilterPodListByField indicates an expected call of FilterPodListByField.
+func (mr *MockIAnalyzerMockRecorder) FilterPodListByField(ctx, podList, field interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FilterPodListByField", reflect.TypeOf((*MockIAnalyzer)(nil).FilterPodListByField), ctx, podList, field)
+}
+
+// GetCronJobPodListByLabel mocks base method.
+func (m *MockIAnalyzer) GetCronJobPodListByLabel(ctx context.Context, namespace string, label map[string]string, containerName string) ([]*model.PodObject, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCronJobPodListByLabel", ctx, namespace, label, containerName)
+	ret0, _ := ret[0].([]*model.PodObject)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCronJobPodListByLabel indicates an expected call of GetCronJobPodListByLabel.
+func (mr *MockIAnalyzerMockRecorder) GetCronJobPodListByLabel(ctx, namespace, label, containerName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCronJobPodListByLabel", reflect.TypeOf((*MockIAnalyzer)(nil).GetCronJobPodListByLabel), ctx, namespace, label, containerName)
+}
+
+// GetCronJobPodListByName mocks base method.
+func (m *MockIAnalyzer) GetCronJobPodListByName(ctx context.Context, namespace string, name []string, containerName string) ([]*model.PodObject, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCronJobPodListByName", ctx, namespace, name, containerName)
+	ret0, _ := ret[0].([]*model.PodObject)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCronJobPodListByName indicates an expected call of GetCronJobPodListByName.
+func (mr *MockIAnalyzerMockRecorder) GetCronJobPodListByName(ctx, namespace, name, containerName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCronJobPodListByName", reflect.TypeOf((*MockIAnalyzer)(nil).GetCronJobPodListByName), ctx, namespace, name, containerName)
+}
+
+// GetDaemonSetPodListByLabel mocks base method.
+func (m *MockIAnalyzer) GetDaemonSetPodListByLabel(ctx context.Context, namespace string, label map[string]string, containerName string) ([]*model.PodObject, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDaemonSetPodListByLabel", ctx, namespace, label, containerName)
+	ret0, _ := ret[0].([]*model.PodObject)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDaemonSetPodListByLabel indicates an expected call of GetDaemonSetPodListByLabel.
+func (mr *MockIAnalyzerMockRecorder) GetDaemonSetPodListByLabel(ctx, namespace, label, containerName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDaemonSetPodListByLabel", reflect.TypeOf((*MockIAnalyzer)(nil).GetDaemonSetPodListByLabel), ctx, namespace, label, containerName)
+}
+
+// GetDaemonSetPodListByName mocks base method.
+func (m *MockIAnalyzer) GetDaemonSetPodListByName(ctx context.Context, namespace string, name []string, containerName string) ([]*model.PodObject, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDaemonSetPodListByName", ctx, namespace, name, containerName)
+	ret0, _ := ret[0].([]*model.PodObject)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDaemonSetPodListByName indicates an expected call of GetDaemonSetPodListByName.
+func (mr *MockIAnalyzerMockRecorder) GetDaemonSetPodListByName(ctx, namespace, name, containerName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDaemonSetPodListByName", reflect.TypeOf((*MockIAnalyzer)(nil).GetDaemonSetPodListByName), ctx, namespace, name, containerName)
+}
+
 // GetDeploymentListByLabel mocks base method.
 func (m *MockIAnalyzer) GetDeploymentListByLabel(ctx context.Context, namespace string, label map[string]string) ([]*model.DeploymentObject, error) {
 	m.ctrl.T.Helper()
@@ -66,6 +156,36 @@ func (mr *MockIAnalyzerMockRecorder) GetDeploymentListByName(ctx, namespace, nam
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeploymentListByName", reflect.TypeOf((*MockIAnalyzer)(nil).GetDeploymentListByName), ctx, namespace, name)
 }
 
+// GetExperiment mocks base method.
+func (m *MockIAnalyzer) GetExperiment(ctx context.Context, namespace, name string) (*v1alpha1.Experiment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExperiment", ctx, namespace, name)
+	ret0, _ := ret[0].(*v1alpha1.Experiment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExperiment indicates an expected call of GetExperiment.
+func (mr *MockIAnalyzerMockRecorder) GetExperiment(ctx, namespace, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExperiment", reflect.TypeOf((*MockIAnalyzer)(nil).GetExperiment), ctx, namespace, name)
+}
+
+// GetExperimentListByMutex mocks base method.
+func (m *MockIAnalyzer) GetExperimentListByMutex(ctx context.Context, mutex string) (*v1alpha1.ExperimentList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExperimentListByMutex", ctx, mutex)
+	ret0, _ := ret[0].(*v1alpha1.ExperimentList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExperimentListByMutex indicates an expected call of GetExperimentListByMutex.
+func (mr *MockIAnalyzerMockRecorder) GetExperimentListByMutex(ctx, mutex interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExperimentListByMutex", reflect.TypeOf((*MockIAnalyzer)(nil).GetExperimentListByMutex), ctx, mutex)
+}
+
 // GetExperimentListByPhase mocks base method.
 func (m *MockIAnalyzer) GetExperimentListByPhase(ctx context.Context, phase string) (*v1alpha1.ExperimentList, error) {
 	m.ctrl.T.Helper()
@@ -81,6 +201,51 @@ func (mr *MockIAnalyzerMockRecorder) GetExperimentListByPhase(ctx, phase interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExperimentListByPhase", reflect.TypeOf((*MockIAnalyzer)(nil).GetExperimentListByPhase), ctx, phase)
 }
 
+// GetJobPodListByLabel mocks base method.
+func (m *MockIAnalyzer) GetJobPodListByLabel(ctx context.Context, namespace string, label map[string]string, containerName string) ([]*model.PodObject, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetJobPodListByLabel", ctx, namespace, label, containerName)
+	ret0, _ := ret[0].([]*model.PodObject)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetJobPodListByLabel indicates an expected call of GetJobPodListByLabel.
+func (mr *MockIAnalyzerMockRecorder) GetJobPodListByLabel(ctx, namespace, label, containerName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetJobPodListByLabel", reflect.TypeOf((*MockIAnalyzer)(nil).GetJobPodListByLabel), ctx, namespace, label, containerName)
+}
+
+// GetJobPodListByName mocks base method.
+func (m *MockIAnalyzer) GetJobPodListByName(ctx context.Context, namespace string, name []string, containerName string) ([]*model.PodObject, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetJobPodListByName", ctx, namespace, name, containerName)
+	ret0, _ := ret[0].([]*model.PodObject)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetJobPodListByName indicates an expected call of GetJobPodListByName.
+func (mr *MockIAnalyzerMockRecorder) GetJobPodListByName(ctx, namespace, name, containerName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetJobPodListByName", reflect.TypeOf((*MockIAnalyzer)(nil).GetJobPodListByName), ctx, namespace, name, containerName)
+}
+
+// GetNamespaceListByLabel mocks base method.
+func (m *MockIAnalyzer) GetNamespaceListByLabel(ctx context.Context, label map[string]string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNamespaceListByLabel", ctx, label)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNamespaceListByLabel indicates an expected call of GetNamespaceListByLabel.
+func (mr *MockIAnalyzerMockRecorder) GetNamespaceListByLabel(ctx, label interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNamespaceListByLabel", reflect.TypeOf((*MockIAnalyzer)(nil).GetNamespaceListByLabel), ctx, label)
+}
+
 // GetNodeListByLabel mocks base method.
 func (m *MockIAnalyzer) GetNodeListByLabel(ctx context.Context, label map[string]string, containerName string) ([]*model.NodeObject, error) {
 	m.ctrl.T.Helper()
@@ -126,6 +291,21 @@ func (mr *MockIAnalyzerMockRecorder) GetNodeListByNodeName(ctx, nodeName, contai
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNodeListByNodeName", reflect.TypeOf((*MockIAnalyzer)(nil).GetNodeListByNodeName), ctx, nodeName, containerName)
 }
 
+// GetNodeZoneMap mocks base method.
+func (m *MockIAnalyzer) GetNodeZoneMap(ctx context.Context) (map[string]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNodeZoneMap", ctx)
+	ret0, _ := ret[0].(map[string]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNodeZoneMap indicates an expected call of GetNodeZoneMap.
+func (mr *MockIAnalyzerMockRecorder) GetNodeZoneMap(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNodeZoneMap", reflect.TypeOf((*MockIAnalyzer)(nil).GetNodeZoneMap), ctx)
+}
+
 // GetPod mocks base method.
 func (m *MockIAnalyzer) GetPod(ctx context.Context, ns, podName, containerName string) (*model.PodObject, error) {
 	m.ctrl.T.Helper()
@@ -171,6 +351,21 @@ func (mr *MockIAnalyzerMockRecorder) GetPodListByLabelInNode(ctx, namespace, lab
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPodListByLabelInNode", reflect.TypeOf((*MockIAnalyzer)(nil).GetPodListByLabelInNode), ctx, namespace, label, nodeIP)
 }
 
+// GetPodListByOwner mocks base method.
+func (m *MockIAnalyzer) GetPodListByOwner(ctx context.Context, namespace string, owner *v1alpha1.OwnerSelector, containerName string) ([]*model.PodObject, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPodListByOwner", ctx, namespace, owner, containerName)
+	ret0, _ := ret[0].([]*model.PodObject)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPodListByOwner indicates an expected call of GetPodListByOwner.
+func (mr *MockIAnalyzerMockRecorder) GetPodListByOwner(ctx, namespace, owner, containerName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPodListByOwner", reflect.TypeOf((*MockIAnalyzer)(nil).GetPodListByOwner), ctx, namespace, owner, containerName)
+}
+
 // GetPodListByPodName mocks base method.
 func (m *MockIAnalyzer) GetPodListByPodName(ctx context.Context, namespace string, podName []string, containerName string) ([]*model.PodObject, error) {
 	m.ctrl.T.Helper()
@@ -185,3 +380,63 @@ func (mr *MockIAnalyzerMockRecorder) GetPodListByPodName(ctx, namespace, podName
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPodListByPodName", reflect.TypeOf((*MockIAnalyzer)(nil).GetPodListByPodName), ctx, namespace, podName, containerName)
 }
+
+// GetServicePodListByLabel mocks base method.
+func (m *MockIAnalyzer) GetServicePodListByLabel(ctx context.Context, namespace string, label map[string]string, containerName string) ([]*model.PodObject, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetServicePodListByLabel", ctx, namespace, label, containerName)
+	ret0, _ := ret[0].([]*model.PodObject)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetServicePodListByLabel indicates an expected call of GetServicePodListByLabel.
+func (mr *MockIAnalyzerMockRecorder) GetServicePodListByLabel(ctx, namespace, label, containerName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetServicePodListByLabel", reflect.TypeOf((*MockIAnalyzer)(nil).GetServicePodListByLabel), ctx, namespace, label, containerName)
+}
+
+// GetServicePodListByName mocks base method.
+func (m *MockIAnalyzer) GetServicePodListByName(ctx context.Context, namespace string, name []string, containerName string) ([]*model.PodObject, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetServicePodListByName", ctx, namespace, name, containerName)
+	ret0, _ := ret[0].([]*model.PodObject)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetServicePodListByName indicates an expected call of GetServicePodListByName.
+func (mr *MockIAnalyzerMockRecorder) GetServicePodListByName(ctx, namespace, name, containerName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetServicePodListByName", reflect.TypeOf((*MockIAnalyzer)(nil).GetServicePodListByName), ctx, namespace, name, containerName)
+}
+
+// GetStatefulSetPodListByLabel mocks base method.
+func (m *MockIAnalyzer) GetStatefulSetPodListByLabel(ctx context.Context, namespace string, label map[string]string, containerName string) ([]*model.PodObject, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStatefulSetPodListByLabel", ctx, namespace, label, containerName)
+	ret0, _ := ret[0].([]*model.PodObject)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStatefulSetPodListByLabel indicates an expected call of GetStatefulSetPodListByLabel.
+func (mr *MockIAnalyzerMockRecorder) GetStatefulSetPodListByLabel(ctx, namespace, label, containerName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStatefulSetPodListByLabel", reflect.TypeOf((*MockIAnalyzer)(nil).GetStatefulSetPodListByLabel), ctx, namespace, label, containerName)
+}
+
+// GetStatefulSetPodListByName mocks base method.
+func (m *MockIAnalyzer) GetStatefulSetPodListByName(ctx context.Context, namespace string, name []string, containerName string) ([]*model.PodObject, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStatefulSetPodListByName", ctx, namespace, name, containerName)
+	ret0, _ := ret[0].([]*model.PodObject)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStatefulSetPodListByName indicates an expected call of GetStatefulSetPodListByName.
+func (mr *MockIAnalyzerMockRecorder) GetStatefulSetPodListByName(ctx, namespace, name, containerName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStatefulSetPodListByName", reflect.TypeOf((*MockIAnalyzer)(nil).GetStatefulSetPodListByName), ctx, namespace, name, containerName)
+}
@@ -0,0 +1,159 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cloudevents
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"net/http"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"time"
+)
+
+const (
+	SpecVersion = "1.0"
+	TypePrefix  = "io.chaosmeta.experiment"
+
+	sendTimeout = 5 * time.Second
+)
+
+var (
+	sink   string
+	source string
+	client = &http.Client{Timeout: sendTimeout}
+)
+
+// SetSink set the url the lifecycle CloudEvents of experiments are posted to, empty sink disables the events.
+// source is the "source" attribute of the events, default the api path of the experiment
+func SetSink(sinkUrl, eventSource string) {
+	sink, source = sinkUrl, eventSource
+}
+
+// ExperimentEventData is the data of an experiment lifecycle event
+type ExperimentEventData struct {
+	Namespace   string              `json:"namespace"`
+	Name        string              `json:"name"`
+	Scope       v1alpha1.ScopeType  `json:"scope"`
+	Target      string              `json:"target,omitempty"`
+	Fault       string              `json:"fault,omitempty"`
+	TargetPhase v1alpha1.PhaseType  `json:"targetPhase"`
+	Phase       v1alpha1.PhaseType  `json:"phase"`
+	Status      v1alpha1.StatusType `json:"status"`
+	Message     string              `json:"message"`
+	UpdateTime  string              `json:"updateTime"`
+}
+
+// GetEventType is such as "io.chaosmeta.experiment.inject.success"
+func GetEventType(phase v1alpha1.PhaseType, status v1alpha1.StatusType) string {
+	return fmt.Sprintf("%s.%s.%s", TypePrefix, phase, status)
+}
+
+// EmitIfChanged posts a CloudEvent in binary content mode asynchronously if the phase or status of the experiment
+// changed, failures are only logged so that the reconciliation is never blocked by the sink
+func EmitIfChanged(ctx context.Context, instance *v1alpha1.Experiment, oldPhase v1alpha1.PhaseType, oldStatus v1alpha1.StatusType) {
+	if sink == "" || instance.Status.Phase == "" || (instance.Status.Phase == oldPhase && instance.Status.Status == oldStatus) {
+		return
+	}
+
+	req, err := newRequest(instance)
+	if err != nil {
+		log.FromContext(ctx).Error(err, fmt.Sprintf("new cloud event of experiment %s/%s error", instance.Namespace, instance.Name))
+		return
+	}
+
+	go send(ctx, req)
+}
+
+// Emit posts a CloudEvent which is not an experiment lifecycle transition, such as an alert of the operator itself
+func Emit(ctx context.Context, eventType, eventSource, subject string, data interface{}) {
+	if sink == "" {
+		return
+	}
+
+	if source != "" {
+		eventSource = source
+	}
+	req, err := newEventRequest(fmt.Sprintf("%s-%d", subject, time.Now().UnixNano()), eventType, eventSource, subject, data)
+	if err != nil {
+		log.FromContext(ctx).Error(err, fmt.Sprintf("new cloud event[%s] error", eventType))
+		return
+	}
+
+	go send(ctx, req)
+}
+
+func send(ctx context.Context, req *http.Request) {
+	logger := log.FromContext(ctx)
+	resp, err := client.Do(req)
+	if err != nil {
+		logger.Error(err, fmt.Sprintf("send cloud event[%s] error", req.Header.Get("ce-id")))
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		logger.Error(fmt.Errorf("response code: %d", resp.StatusCode), fmt.Sprintf("send cloud event[%s] error", req.Header.Get("ce-id")))
+	}
+}
+
+func newRequest(instance *v1alpha1.Experiment) (*http.Request, error) {
+	data := ExperimentEventData{
+		Namespace:   instance.Namespace,
+		Name:        instance.Name,
+		Scope:       instance.Spec.Scope,
+		TargetPhase: instance.Spec.TargetPhase,
+		Phase:       instance.Status.Phase,
+		Status:      instance.Status.Status,
+		Message:     instance.Status.Message,
+		UpdateTime:  instance.Status.UpdateTime,
+	}
+	if instance.Spec.Experiment != nil {
+		data.Target, data.Fault = instance.Spec.Experiment.Target, instance.Spec.Experiment.Fault
+	}
+
+	eventSource := source
+	if eventSource == "" {
+		eventSource = fmt.Sprintf("/apis/%s/namespaces/%s/experiments/%s", v1alpha1.GroupVersion.String(), instance.Namespace, instance.Name)
+	}
+
+	return newEventRequest(fmt.Sprintf("%s-%s-%s-%d", instance.UID, instance.Status.Phase, instance.Status.Status, time.Now().UnixNano()),
+		GetEventType(instance.Status.Phase, instance.Status.Status), eventSource, fmt.Sprintf("%s/%s", instance.Namespace, instance.Name), data)
+}
+
+func newEventRequest(id, eventType, eventSource, subject string, data interface{}) (*http.Request, error) {
+	body, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("marshal event data error: %s", err.Error())
+	}
+
+	req, err := http.NewRequest(http.MethodPost, sink, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("new request error: %s", err.Error())
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("ce-specversion", SpecVersion)
+	req.Header.Set("ce-id", id)
+	req.Header.Set("ce-type", eventType)
+	req.Header.Set("ce-source", eventSource)
+	req.Header.Set("ce-subject", subject)
+	req.Header.Set("ce-time", time.Now().UTC().Format(time.RFC3339))
+	return req, nil
+}
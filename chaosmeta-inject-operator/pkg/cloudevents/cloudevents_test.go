@@ -0,0 +1,75 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cloudevents
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"io"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestEmitIfChanged(t *testing.T) {
+	received := make(chan *http.Request, 1)
+	var data ExperimentEventData
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &data)
+		received <- r
+	}))
+	defer server.Close()
+
+	SetSink(server.URL, "")
+	defer SetSink("", "")
+
+	instance := &v1alpha1.Experiment{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "exp"},
+		Spec: v1alpha1.ExperimentSpec{
+			Scope:       v1alpha1.PodScopeType,
+			Experiment:  &v1alpha1.ExperimentCommon{Target: "cpu", Fault: "burn"},
+			TargetPhase: v1alpha1.InjectPhaseType,
+		},
+		Status: v1alpha1.ExperimentStatus{Phase: v1alpha1.InjectPhaseType, Status: v1alpha1.SuccessStatusType},
+	}
+
+	// unchanged status emits nothing
+	EmitIfChanged(context.Background(), instance, v1alpha1.InjectPhaseType, v1alpha1.SuccessStatusType)
+	select {
+	case <-received:
+		t.Fatal("unexpected event for unchanged status")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	EmitIfChanged(context.Background(), instance, v1alpha1.InjectPhaseType, v1alpha1.RunningStatusType)
+	select {
+	case r := <-received:
+		assert.Equal(t, SpecVersion, r.Header.Get("ce-specversion"))
+		assert.Equal(t, "io.chaosmeta.experiment.inject.success", r.Header.Get("ce-type"))
+		assert.Equal(t, "/apis/chaosmeta.io/v1alpha1/namespaces/default/experiments/exp", r.Header.Get("ce-source"))
+		assert.Equal(t, "default/exp", r.Header.Get("ce-subject"))
+		assert.Equal(t, "cpu", data.Target)
+		assert.Equal(t, v1alpha1.SuccessStatusType, data.Status)
+	case <-time.After(2 * time.Second):
+		t.Fatal("event not received")
+	}
+}
@@ -0,0 +1,174 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package common
+
+import (
+	"errors"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+)
+
+const (
+	ComponentOperator = "operator"
+	ComponentAgent    = "chaosmetad"
+)
+
+// error categories shared by chaosmetad, the operator and the platform
+const (
+	CategoryInvalidArgument  = "InvalidArgument"
+	CategoryPermissionDenied = "PermissionDenied"
+	CategoryUnsupported      = "Unsupported"
+	CategoryNotFound         = "NotFound"
+	CategoryUnavailable      = "Unavailable"
+	CategoryTimeout          = "Timeout"
+	CategoryExecutionFailed  = "ExecutionFailed"
+	CategoryInternal         = "Internal"
+	CategoryUnknown          = "Unknown"
+)
+
+// error codes raised by the operator itself
+const (
+	CodeTargetNotFound   = "TargetNotFound"
+	CodeAgentUnavailable = "AgentUnavailable"
+	CodeTimeout          = "Timeout"
+	CodeInternal         = "InternalError"
+	CodePreflightFailed  = "PreflightFailed"
+)
+
+// exit codes of chaosmetad, which are also the codes of its http api
+const (
+	agentBadArgsErr    = 1
+	agentDBErr         = 2
+	agentInjectErr     = 3
+	agentInternalErr   = 4
+	agentRecoverErr    = 5
+	agentUnknownErr    = 6
+	agentPermissionErr = 7
+	agentLimitErr      = 8
+	agentExpectedErr   = 99
+
+	// the errors reported by the fault tools of chaosmetad
+	agentToolArgsErr        = 11
+	agentToolPermissionErr  = 12
+	agentToolUnsupportedErr = 13
+	agentToolRuntimeErr     = 14
+)
+
+var agentErrorInfoMap = map[int]v1alpha1.ErrorInfo{
+	agentBadArgsErr:    {Code: "BadArgs", Category: CategoryInvalidArgument},
+	agentDBErr:         {Code: "StorageError", Category: CategoryUnavailable, Retryable: true},
+	agentInjectErr:     {Code: "InjectFailed", Category: CategoryExecutionFailed},
+	agentInternalErr:   {Code: "InternalError", Category: CategoryInternal},
+	agentRecoverErr:    {Code: "RecoverFailed", Category: CategoryExecutionFailed, Retryable: true},
+	agentUnknownErr:    {Code: "Unknown", Category: CategoryUnknown},
+	agentPermissionErr: {Code: "PermissionDenied", Category: CategoryPermissionDenied},
+	agentLimitErr:      {Code: "LimitExceeded", Category: CategoryUnavailable, Retryable: true},
+	agentExpectedErr:   {Code: "ExpectedError", Category: CategoryExecutionFailed},
+
+	agentToolArgsErr:        {Code: "ToolBadArgs", Category: CategoryInvalidArgument},
+	agentToolPermissionErr:  {Code: "ToolPermissionDenied", Category: CategoryPermissionDenied},
+	agentToolUnsupportedErr: {Code: "UnsupportedKernel", Category: CategoryUnsupported},
+	agentToolRuntimeErr:     {Code: "ToolRuntimeError", Category: CategoryExecutionFailed, Retryable: true},
+}
+
+// CodedError carries the structured info of an error through the executors and scope handlers
+type CodedError struct {
+	Info v1alpha1.ErrorInfo
+	Err  error
+}
+
+func (e *CodedError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *CodedError) Unwrap() error {
+	return e.Err
+}
+
+func NewCodedError(info *v1alpha1.ErrorInfo, err error) error {
+	return &CodedError{Info: *info, Err: err}
+}
+
+// NewAgentError attaches the info of a code returned by chaosmetad to err
+func NewAgentError(code int, err error) error {
+	return NewCodedError(GetAgentErrorInfo(code), err)
+}
+
+// WrapError adds msg in front of err, and keeps the info of err if it is a CodedError
+func WrapError(err error, msg string) error {
+	wrapErr := fmt.Errorf("%s: %s", msg, err.Error())
+	var codedErr *CodedError
+	if errors.As(err, &codedErr) {
+		return NewCodedError(&codedErr.Info, wrapErr)
+	}
+
+	return wrapErr
+}
+
+// GetAgentErrorInfo converts a code of chaosmetad, an unknown code is treated as the unknown error of chaosmetad
+func GetAgentErrorInfo(code int) *v1alpha1.ErrorInfo {
+	info, ok := agentErrorInfoMap[code]
+	if !ok {
+		info = agentErrorInfoMap[agentUnknownErr]
+	}
+	info.Component = ComponentAgent
+	return &info
+}
+
+// GetAgentFailedErrorInfo is used when the agent reports a failed task without a code
+func GetAgentFailedErrorInfo(phase v1alpha1.PhaseType) *v1alpha1.ErrorInfo {
+	if phase == v1alpha1.RecoverPhaseType {
+		return GetAgentErrorInfo(agentRecoverErr)
+	}
+
+	return GetAgentErrorInfo(agentInjectErr)
+}
+
+func GetTimeoutErrorInfo() *v1alpha1.ErrorInfo {
+	return &v1alpha1.ErrorInfo{Code: CodeTimeout, Category: CategoryTimeout, Component: ComponentOperator, Retryable: true}
+}
+
+func GetAgentUnavailableErrorInfo() *v1alpha1.ErrorInfo {
+	return &v1alpha1.ErrorInfo{Code: CodeAgentUnavailable, Category: CategoryUnavailable, Component: ComponentOperator, Retryable: true}
+}
+
+func GetPreflightFailedErrorInfo() *v1alpha1.ErrorInfo {
+	return &v1alpha1.ErrorInfo{Code: CodePreflightFailed, Category: CategoryUnsupported, Component: ComponentOperator}
+}
+
+// GetErrorInfo returns the info carried by err, or classifies err as an error of the operator
+func GetErrorInfo(err error) *v1alpha1.ErrorInfo {
+	if err == nil {
+		return nil
+	}
+
+	var codedErr *CodedError
+	if errors.As(err, &codedErr) {
+		info := codedErr.Info
+		return &info
+	}
+
+	if IsNotFoundErr(err) {
+		return &v1alpha1.ErrorInfo{Code: CodeTargetNotFound, Category: CategoryNotFound, Component: ComponentOperator}
+	}
+
+	if IsNetErr(err) {
+		return GetAgentUnavailableErrorInfo()
+	}
+
+	return &v1alpha1.ErrorInfo{Code: CodeInternal, Category: CategoryInternal, Component: ComponentOperator}
+}
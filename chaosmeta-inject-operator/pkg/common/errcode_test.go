@@ -0,0 +1,75 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package common
+
+import (
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"reflect"
+	"testing"
+)
+
+func TestGetErrorInfo(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want *v1alpha1.ErrorInfo
+	}{
+		{
+			name: "nil error",
+			err:  nil,
+			want: nil,
+		},
+		{
+			name: "agent bad args",
+			err:  NewAgentError(1, fmt.Errorf("args error")),
+			want: &v1alpha1.ErrorInfo{Code: "BadArgs", Category: CategoryInvalidArgument, Component: ComponentAgent},
+		},
+		{
+			name: "agent unsupported kernel",
+			err:  NewAgentError(13, fmt.Errorf("inject error: [error:13]netem is not loaded")),
+			want: &v1alpha1.ErrorInfo{Code: "UnsupportedKernel", Category: CategoryUnsupported, Component: ComponentAgent},
+		},
+		{
+			name: "agent unknown code",
+			err:  NewAgentError(127, fmt.Errorf("command not found")),
+			want: &v1alpha1.ErrorInfo{Code: "Unknown", Category: CategoryUnknown, Component: ComponentAgent},
+		},
+		{
+			name: "wrapped agent error",
+			err:  WrapError(NewAgentError(5, fmt.Errorf("recover error")), "kubectl exec error"),
+			want: &v1alpha1.ErrorInfo{Code: "RecoverFailed", Category: CategoryExecutionFailed, Component: ComponentAgent, Retryable: true},
+		},
+		{
+			name: "target not found",
+			err:  fmt.Errorf("pods \"nginx\" not found"),
+			want: &v1alpha1.ErrorInfo{Code: CodeTargetNotFound, Category: CategoryNotFound, Component: ComponentOperator},
+		},
+		{
+			name: "other error",
+			err:  fmt.Errorf("inject object change to pod error"),
+			want: &v1alpha1.ErrorInfo{Code: CodeInternal, Category: CategoryInternal, Component: ComponentOperator},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GetErrorInfo(tt.err); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetErrorInfo() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -22,13 +22,16 @@ import (
 )
 
 type GoroutinePool struct {
-	n    int
-	pool chan struct{}
+	n       int
+	pool    chan struct{}
+	waiting int64
 }
 
 var (
 	workerPool  *GoroutinePool
 	clusterCtrl = &ClusterCtrl{}
+	// targetConcurrency is the max targets of one experiment solved at the same time, 0 means only limited by the pool
+	targetConcurrency int
 )
 
 func SetGoroutinePool(n int) {
@@ -50,14 +53,52 @@ func (g *GoroutinePool) GetLen() int {
 	return len(g.pool)
 }
 
+// GetWaiting is the count of goroutines waiting for the pool, the queue depth of targets
+func (g *GoroutinePool) GetWaiting() int64 {
+	return atomic.LoadInt64(&g.waiting)
+}
+
 func (g *GoroutinePool) GetGoroutine() {
+	atomic.AddInt64(&g.waiting, 1)
 	g.pool <- struct{}{}
+	atomic.AddInt64(&g.waiting, -1)
 }
 
 func (g *GoroutinePool) ReleaseGoroutine() {
 	<-g.pool
 }
 
+func SetTargetConcurrency(n int) {
+	targetConcurrency = n
+}
+
+func GetTargetConcurrency() int {
+	return targetConcurrency
+}
+
+// TargetLimiter limits the targets of one experiment solved at the same time, so that a large experiment
+// does not take the whole pool from the others. A nil limiter is unlimited
+type TargetLimiter chan struct{}
+
+func NewTargetLimiter() TargetLimiter {
+	if targetConcurrency <= 0 {
+		return nil
+	}
+	return make(TargetLimiter, targetConcurrency)
+}
+
+func (l TargetLimiter) Acquire() {
+	if l != nil {
+		l <- struct{}{}
+	}
+}
+
+func (l TargetLimiter) Release() {
+	if l != nil {
+		<-l
+	}
+}
+
 type ClusterCtrl struct {
 	runningWorker int64
 	stopping      bool
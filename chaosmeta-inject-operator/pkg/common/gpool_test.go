@@ -50,6 +50,47 @@ func TestGoroutinePool(t *testing.T) {
 	assert.Equal(t, 3, pool.GetSize())
 }
 
+func TestGoroutinePool_GetWaiting(t *testing.T) {
+	SetGoroutinePool(1)
+
+	pool := GetGoroutinePool()
+	pool.GetGoroutine()
+	go pool.GetGoroutine()
+	time.Sleep(time.Second)
+	assert.Equal(t, int64(1), pool.GetWaiting())
+
+	pool.ReleaseGoroutine()
+	time.Sleep(time.Second)
+	assert.Equal(t, int64(0), pool.GetWaiting())
+	pool.ReleaseGoroutine()
+}
+
+func TestTargetLimiter(t *testing.T) {
+	defer SetTargetConcurrency(0)
+
+	SetTargetConcurrency(0)
+	limiter := NewTargetLimiter()
+	assert.Nil(t, limiter)
+	limiter.Acquire()
+	limiter.Release()
+
+	SetTargetConcurrency(2)
+	limiter = NewTargetLimiter()
+	limiter.Acquire()
+	limiter.Acquire()
+
+	var flag bool
+	go func(t *bool) {
+		limiter.Acquire()
+		*t = true
+	}(&flag)
+	time.Sleep(time.Second)
+	assert.Equal(t, false, flag)
+	limiter.Release()
+	time.Sleep(time.Second)
+	assert.Equal(t, true, flag)
+}
+
 func TestClusterCtrl(t *testing.T) {
 	assert.Equal(t, false, GetClusterCtrl().IsStopping())
 	assert.Equal(t, false, GetClusterCtrl().IsRunning())
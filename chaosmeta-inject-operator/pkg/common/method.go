@@ -43,6 +43,12 @@ func IsTimeout(createTimeStr string, durationStr string) (bool, error) {
 	return createTime.Add(duration).Before(time.Now()), nil
 }
 
+func NewUid() string {
+	t := time.Now()
+	timeStr := t.Format("20060102150405")
+	return fmt.Sprintf("%s%04d", timeStr, t.Nanosecond()/1000%100000%10000)
+}
+
 func GetArgs(args []v1alpha1.ArgsUnit, keys []string) []string {
 	reList := make([]string, len(keys))
 	for i, k := range keys {
@@ -57,6 +63,24 @@ func GetArgs(args []v1alpha1.ArgsUnit, keys []string) []string {
 	return reList
 }
 
+// RecordLastError keeps the message of a failed target, which is not cleared when the target is retried
+func RecordLastError(unit *v1alpha1.ExperimentDetailUnit) {
+	if unit.Status == v1alpha1.FailedStatusType {
+		unit.LastError = unit.Message
+	}
+}
+
+// GetTargetArgs returns the args of experiment with the remote executor selected by the label of target
+func GetTargetArgs(exp *v1alpha1.ExperimentCommon, unit *v1alpha1.ExperimentDetailUnit) *v1alpha1.ExperimentCommon {
+	if unit.Executor == "" || unit.Executor == exp.Executor {
+		return exp
+	}
+
+	args := exp.DeepCopy()
+	args.Executor = unit.Executor
+	return args
+}
+
 func IsKeyUniqueErr(err error) bool {
 	return strings.Index(err.Error(), "UNIQUE") >= 0 && strings.Index(err.Error(), "uid") >= 0
 }
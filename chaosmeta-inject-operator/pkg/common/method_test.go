@@ -145,3 +145,29 @@ func TestGetArgs(t *testing.T) {
 		})
 	}
 }
+
+func TestRecordLastError(t *testing.T) {
+	unit := &v1alpha1.ExperimentDetailUnit{Status: v1alpha1.FailedStatusType, Message: "experiment inject error: timeout"}
+	RecordLastError(unit)
+	if unit.LastError != "experiment inject error: timeout" {
+		t.Errorf("RecordLastError() of a failed target = %s", unit.LastError)
+	}
+
+	unit.Status, unit.Message = v1alpha1.RunningStatusType, "experiment inject start success"
+	RecordLastError(unit)
+	if unit.LastError != "experiment inject error: timeout" {
+		t.Errorf("RecordLastError() should keep the last error, got %s", unit.LastError)
+	}
+}
+
+func TestGetTargetArgs(t *testing.T) {
+	exp := &v1alpha1.ExperimentCommon{Target: "cpu", Fault: "burn", Executor: "grpc"}
+	if got := GetTargetArgs(exp, &v1alpha1.ExperimentDetailUnit{}); got != exp {
+		t.Errorf("GetTargetArgs() without executor of target should return the args of experiment")
+	}
+
+	got := GetTargetArgs(exp, &v1alpha1.ExperimentDetailUnit{Executor: "ssh"})
+	if got.Executor != "ssh" || exp.Executor != "grpc" {
+		t.Errorf("GetTargetArgs() = %s, the args of experiment = %s", got.Executor, exp.Executor)
+	}
+}
@@ -0,0 +1,67 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package common
+
+import (
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/model"
+	"time"
+)
+
+const (
+	defaultRetryBackoff = 5 * time.Second
+	maxRetryBackoff     = 5 * time.Minute
+)
+
+// IsRetryLeft returns whether the policy allows another inject call after the attempts of a target
+func IsRetryLeft(policy *v1alpha1.RetryPolicy, attempts int32) bool {
+	return policy != nil && attempts <= policy.MaxRetries
+}
+
+// GetRetryBackoff returns the wait before the next inject call after the attempts, doubled for every retry
+func GetRetryBackoff(policy *v1alpha1.RetryPolicy, attempts int32) time.Duration {
+	backoff := defaultRetryBackoff
+	if policy != nil && policy.Backoff != "" {
+		if d, err := v1alpha1.ConvertDuration(policy.Backoff); err == nil {
+			backoff = d
+		}
+	}
+
+	for i := int32(1); i < attempts && backoff < maxRetryBackoff; i++ {
+		backoff *= 2
+	}
+
+	if backoff > maxRetryBackoff {
+		backoff = maxRetryBackoff
+	}
+
+	return backoff
+}
+
+// GetRetryWait returns how long the target still waits for its retry, 0 means the target can be injected now
+func GetRetryWait(unit *v1alpha1.ExperimentDetailUnit, now time.Time) time.Duration {
+	if unit.NextRetryTime == "" {
+		return 0
+	}
+
+	nextTime, err := time.ParseInLocation(model.TimeFormat, unit.NextRetryTime, time.Local)
+	if err != nil || !nextTime.After(now) {
+		return 0
+	}
+
+	return nextTime.Sub(now)
+}
@@ -0,0 +1,71 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package common
+
+import (
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/model"
+	"testing"
+	"time"
+)
+
+func TestGetRetryBackoff(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   *v1alpha1.RetryPolicy
+		attempts int32
+		want     time.Duration
+	}{
+		{name: "default backoff", policy: &v1alpha1.RetryPolicy{MaxRetries: 3}, attempts: 1, want: 5 * time.Second},
+		{name: "first retry", policy: &v1alpha1.RetryPolicy{MaxRetries: 3, Backoff: "10s"}, attempts: 1, want: 10 * time.Second},
+		{name: "doubled", policy: &v1alpha1.RetryPolicy{MaxRetries: 3, Backoff: "10s"}, attempts: 3, want: 40 * time.Second},
+		{name: "capped", policy: &v1alpha1.RetryPolicy{MaxRetries: 30, Backoff: "1m"}, attempts: 20, want: 5 * time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GetRetryBackoff(tt.policy, tt.attempts); got != tt.want {
+				t.Errorf("GetRetryBackoff() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsRetryLeft(t *testing.T) {
+	policy := &v1alpha1.RetryPolicy{MaxRetries: 2}
+	if IsRetryLeft(nil, 1) {
+		t.Errorf("IsRetryLeft() without policy should be false")
+	}
+	if !IsRetryLeft(policy, 2) {
+		t.Errorf("IsRetryLeft() after 2 attempts should be true")
+	}
+	if IsRetryLeft(policy, 3) {
+		t.Errorf("IsRetryLeft() after 3 attempts should be false")
+	}
+}
+
+func TestGetRetryWait(t *testing.T) {
+	now := time.Now()
+	if got := GetRetryWait(&v1alpha1.ExperimentDetailUnit{}, now); got != 0 {
+		t.Errorf("GetRetryWait() without retry = %v, want 0", got)
+	}
+	if got := GetRetryWait(&v1alpha1.ExperimentDetailUnit{NextRetryTime: now.Add(-time.Minute).Format(model.TimeFormat)}, now); got != 0 {
+		t.Errorf("GetRetryWait() of a due retry = %v, want 0", got)
+	}
+	if got := GetRetryWait(&v1alpha1.ExperimentDetailUnit{NextRetryTime: now.Add(time.Minute).Format(model.TimeFormat)}, now); got <= 0 {
+		t.Errorf("GetRetryWait() of a future retry = %v, want positive", got)
+	}
+}
@@ -0,0 +1,34 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package common
+
+import "time"
+
+var injectLatencySLO time.Duration
+
+// SetInjectLatencySLO set the expected max duration to inject a target, non-positive value disables the SLO check
+func SetInjectLatencySLO(second int) {
+	injectLatencySLO = time.Duration(second) * time.Second
+}
+
+func IsInjectLatencyExceed(latency time.Duration) bool {
+	return injectLatencySLO > 0 && latency > injectLatencySLO
+}
+
+func GetInjectLatencySLO() time.Duration {
+	return injectLatencySLO
+}
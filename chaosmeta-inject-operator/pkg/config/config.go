@@ -39,26 +39,147 @@ type MainConfig struct {
 	Worker   WorkerConfig   `json:"worker"`
 	Ticker   TickerConfig   `json:"ticker"`
 	Executor ExecutorConfig `json:"executor"`
+	SLO      SLOConfig      `json:"slo"`
+	// CloudEvents Optional: experiment lifecycle transitions are posted to the sink as CloudEvents
+	CloudEvents CloudEventsConfig `json:"cloudEvents"`
+	// Drill Optional: the recovery pipeline is verified periodically by a trivially safe fault on a canary pod
+	Drill DrillConfig `json:"drill"`
+	// Webhook Optional: the defaults filled by the mutating webhook
+	Webhook WebhookConfig `json:"webhook"`
+	// GC the finished experiments with "ttlSecondsAfterFinished" are deleted after the ttl
+	GC GCConfig `json:"gc"`
+	// Dependency Optional: how long the experiments wait for their "dependsOn"
+	Dependency DependencyConfig `json:"dependency"`
 }
 
 type WorkerConfig struct {
+	// PoolCount is the max goroutines solving targets at the same time, shared by all the experiments
 	PoolCount int `json:"poolCount"`
+	// TargetConcurrency is the max targets of one experiment solved at the same time, 0 means only limited by the pool
+	TargetConcurrency int `json:"targetConcurrency"`
+	// MaxConcurrentReconciles is the max experiments reconciled at the same time, default 1
+	MaxConcurrentReconciles int `json:"maxConcurrentReconciles"`
 }
 
 type TickerConfig struct {
 	AutoCheckInterval int `json:"autoCheckInterval"`
 }
 
+type SLOConfig struct {
+	// InjectLatency is the expected max seconds to inject a target, 0 means no SLO
+	InjectLatency int `json:"injectLatency"`
+}
+
+type WebhookConfig struct {
+	// DefaultDuration fills an empty duration of experiment, such as "10m", empty means a duration is required
+	DefaultDuration string `json:"defaultDuration"`
+	// MaxDuration caps the duration of experiment, empty means no cap
+	MaxDuration string `json:"maxDuration"`
+}
+
+type GCConfig struct {
+	// Interval is the seconds between two collections, must be positive
+	Interval int `json:"interval"`
+}
+
+type DependencyConfig struct {
+	// WaitTimeout is the seconds an experiment fails if its dependencies have not succeeded since it is created, 0 means no deadline
+	WaitTimeout int `json:"waitTimeout"`
+}
+
+type CloudEventsConfig struct {
+	// Sink is the url the events are posted to, empty means no event
+	Sink string `json:"sink"`
+	// Source is the "source" attribute of the events, default the api path of the experiment
+	Source string `json:"source"`
+}
+
+type DrillConfig struct {
+	// Interval is the seconds between two drills, 0 means no drill
+	Interval int `json:"interval"`
+	// Namespace and Pod is the canary pod, a file is added to its first container and removed by the auto recovery
+	Namespace string `json:"namespace"`
+	Pod       string `json:"pod"`
+	// Duration of the drill fault, default "10s"
+	Duration string `json:"duration"`
+	// Timeout is the seconds a drill fails if the fault is not recovered, default 300
+	Timeout int `json:"timeout"`
+	// RecoverLatency is the expected max seconds from the end of the duration to the recover success, 0 means no check
+	RecoverLatency int `json:"recoverLatency"`
+	// SuccessRate is the expected min percent of succeeded drills in the last Window drills, Window default 10
+	SuccessRate int `json:"successRate"`
+	Window      int `json:"window"`
+}
+
 type ExecutorConfig struct {
 	Mode            string                  `json:"mode"`
 	Executor        string                  `json:"executor"`
 	Version         string                  `json:"version"`
 	AgentConfig     AgentExecutorConfig     `json:"agentConfig"`
 	DaemonsetConfig DaemonsetExecutorConfig `json:"daemonsetConfig"`
+	// SSHConfig Optional: the "ssh" executor is available only if the private key is provided
+	SSHConfig SSHExecutorConfig `json:"sshConfig"`
+	// EphemeralConfig Optional: the "ephemeral" executor is available only if the image is provided
+	EphemeralConfig EphemeralExecutorConfig `json:"ephemeralConfig"`
+	// SidecarConfig Optional: the pod webhook and the "sidecar" executor are enabled only if the image is provided
+	SidecarConfig SidecarExecutorConfig `json:"sidecarConfig"`
 }
 
 type AgentExecutorConfig struct {
 	AgentPort int `json:"agentPort"`
+	// RuntimeConfig is pushed to the agents whose config version is older, nil means not managed by operator
+	RuntimeConfig *AgentRuntimeConfig `json:"runtimeConfig,omitempty"`
+}
+
+type AgentRuntimeConfig struct {
+	Version               int64    `json:"version"`
+	LogLevel              string   `json:"log_level,omitempty"`
+	EnabledTargets        []string `json:"enabled_targets,omitempty"`
+	MaxRunningExperiments int      `json:"max_running_experiments,omitempty"`
+	MaxTimeout            string   `json:"max_timeout,omitempty"`
+	MaxInjectPerMinute    int      `json:"max_inject_per_minute,omitempty"`
+	ProtectedHosts        []string `json:"protected_hosts,omitempty"`
+}
+
+type SSHExecutorConfig struct {
+	User           string `json:"user"`
+	Port           int    `json:"port"`
+	PrivateKeyPath string `json:"privateKeyPath"`
+	// KnownHostsPath is required, the host keys are verified against it
+	KnownHostsPath string `json:"knownHostsPath"`
+	// LocalExecPath is where chaosmetad is installed on the hosts
+	LocalExecPath string `json:"localExecPath"`
+}
+
+// EphemeralExecutorConfig the "ephemeral" executor attaches an ephemeral container to the target pod and runs chaosmetad in it,
+// for the pods whose image has no shell or tools
+type EphemeralExecutorConfig struct {
+	// Image contains chaosmetad and bash, such as the image of chaosmeta-daemon
+	Image string `json:"image"`
+	// LocalExecPath is where chaosmetad is installed in the image
+	LocalExecPath string `json:"localExecPath"`
+	// MaxLifetime is the seconds the ephemeral container exits if the experiment is never recovered, default 86400.
+	// The experiments without duration or longer than it are rejected
+	MaxLifetime int `json:"maxLifetime,omitempty"`
+}
+
+// SidecarExecutorConfig the pod webhook injects a dormant sidecar of chaosmetad into the pods of the labeled namespaces,
+// the "sidecar" executor calls the grpc service of the sidecar of the target pod
+type SidecarExecutorConfig struct {
+	// Image contains chaosmetad, such as the image of chaosmeta-daemon
+	Image string `json:"image"`
+	// LocalExecPath is where chaosmetad is installed in the image
+	LocalExecPath string `json:"localExecPath"`
+	// GrpcPort the port of the grpc service of the sidecar, must not be used by the containers of the pods
+	GrpcPort int `json:"grpcPort"`
+	// TLSPath is the dir of the mounted secret with ca.crt, tls.crt and tls.key. The operator calls the sidecars with
+	// tls.crt, and the sidecars only accept the callers signed by ca.crt
+	TLSPath string `json:"tlsPath"`
+	// Capabilities Optional: the capabilities added to the sidecar, default NET_ADMIN, SYS_PTRACE, KILL and SYS_RESOURCE
+	Capabilities []string `json:"capabilities,omitempty"`
+	// ShareProcessNamespace Optional: share the process namespace of the pod with the sidecar, so the process faults reach
+	// the other containers. It changes the pid 1 of the containers, so it is off by default
+	ShareProcessNamespace bool `json:"shareProcessNamespace,omitempty"`
 }
 
 type DaemonsetExecutorConfig struct {
@@ -66,6 +187,14 @@ type DaemonsetExecutorConfig struct {
 
 	DaemonNs    string            `json:"daemonNs"`
 	DaemonLabel map[string]string `json:"daemonLabel"`
+	// GrpcPort the port of the grpc service of the DaemonSet agent, used by "grpc" mode
+	GrpcPort int `json:"grpcPort,omitempty"`
+	// GrpcTokenPath is the file of the token shared with the DaemonSet agent, "grpc" mode is not available without it
+	GrpcTokenPath string `json:"grpcTokenPath,omitempty"`
+	// GrpcTLSPath is the dir of the mounted secret with ca.crt, tls.crt and tls.key, the agent only serves mutual TLS
+	GrpcTLSPath string `json:"grpcTLSPath,omitempty"`
+	// GrpcServerName is the DNS name in the certificate of the agent, default "chaosmeta-daemon"
+	GrpcServerName string `json:"grpcServerName,omitempty"`
 
 	AutoLabelNode     bool              `json:"autoLabelNode"`
 	NodeSelectorLabel map[string]string `json:"nodeSelectorLabel"`
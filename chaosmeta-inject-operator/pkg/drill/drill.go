@@ -0,0 +1,233 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package drill
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/cloudevents"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/config"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/model"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"time"
+)
+
+const (
+	NamePrefix        = "chaosmeta-drill-"
+	LabelKey          = "chaosmeta.io/drill"
+	EventTypeDegraded = "io.chaosmeta.drill.degraded"
+	EventSource       = "/chaosmeta/drill"
+
+	drillFilePrefix = "/tmp/chaosmeta_drill_"
+	pollInterval    = time.Second
+
+	defaultDuration = "10s"
+	defaultTimeout  = 300
+	defaultWindow   = 10
+)
+
+// Result is the result of a recovery drill
+type Result struct {
+	Name           string `json:"name"`
+	Success        bool   `json:"success"`
+	RecoverLatency string `json:"recoverLatency,omitempty"`
+	Message        string `json:"message,omitempty"`
+	StartTime      string `json:"startTime"`
+}
+
+// AlertData is the data of the degraded event
+type AlertData struct {
+	Reason      string `json:"reason"`
+	SuccessRate int    `json:"successRate"`
+	Last        Result `json:"last"`
+}
+
+// Driller injects a trivially safe fault to the canary pod periodically, and confirms that it is recovered
+// automatically in time, so that a broken recovery pipeline is found before a real experiment needs it
+type Driller struct {
+	client  client.Client
+	config  config.DrillConfig
+	history []Result
+}
+
+func NewDriller(c client.Client, drillConfig config.DrillConfig) *Driller {
+	if drillConfig.Duration == "" {
+		drillConfig.Duration = defaultDuration
+	}
+	if drillConfig.Timeout <= 0 {
+		drillConfig.Timeout = defaultTimeout
+	}
+	if drillConfig.Window <= 0 {
+		drillConfig.Window = defaultWindow
+	}
+
+	return &Driller{client: c, config: drillConfig}
+}
+
+// Start implements manager.Runnable, a drill runs every interval until the manager stops
+func (d *Driller) Start(ctx context.Context) error {
+	logger, ticker := log.FromContext(ctx), time.NewTicker(time.Duration(d.config.Interval)*time.Second)
+	defer ticker.Stop()
+
+	logger.Info(fmt.Sprintf("start recovery drill success, canary pod: %s/%s, ticker second: %d", d.config.Namespace, d.config.Pod, d.config.Interval))
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			result := d.run(ctx)
+			if result.Success {
+				logger.Info(fmt.Sprintf("recovery drill[%s] success, recover latency: %s", result.Name, result.RecoverLatency))
+			} else {
+				logger.Error(errors.New(result.Message), fmt.Sprintf("recovery drill[%s] failed", result.Name))
+			}
+
+			d.history = append(d.history, result)
+			if len(d.history) > d.config.Window {
+				d.history = d.history[len(d.history)-d.config.Window:]
+			}
+
+			if reason := d.degradedReason(result); reason != "" {
+				logger.Error(errors.New(reason), "recovery pipeline is degraded")
+				cloudevents.Emit(ctx, EventTypeDegraded, EventSource, result.Name, AlertData{
+					Reason:      reason,
+					SuccessRate: d.successRate(),
+					Last:        result,
+				})
+			}
+		}
+	}
+}
+
+// degradedReason is empty if the last drill recovered in time and the success rate of the window is expected
+func (d *Driller) degradedReason(last Result) string {
+	if !last.Success {
+		return fmt.Sprintf("drill[%s] failed: %s", last.Name, last.Message)
+	}
+
+	if d.config.RecoverLatency > 0 {
+		latency, _ := time.ParseDuration(last.RecoverLatency)
+		if latency > time.Duration(d.config.RecoverLatency)*time.Second {
+			return fmt.Sprintf("recover latency[%s] of drill[%s] exceeds %ds", last.RecoverLatency, last.Name, d.config.RecoverLatency)
+		}
+	}
+
+	if rate := d.successRate(); rate < d.config.SuccessRate {
+		return fmt.Sprintf("success rate[%d%%] of the last %d drills is less than %d%%", rate, len(d.history), d.config.SuccessRate)
+	}
+
+	return ""
+}
+
+func (d *Driller) successRate() int {
+	if len(d.history) == 0 {
+		return 100
+	}
+
+	var success int
+	for _, r := range d.history {
+		if r.Success {
+			success++
+		}
+	}
+	return success * 100 / len(d.history)
+}
+
+func (d *Driller) run(ctx context.Context) Result {
+	now := time.Now()
+	exp := d.newExperiment(now)
+	result := Result{Name: exp.Name, StartTime: now.Format(model.TimeFormat)}
+	defer d.cleanup(ctx, exp)
+
+	if err := d.client.Create(ctx, exp); err != nil {
+		result.Message = fmt.Sprintf("create drill experiment error: %s", err.Error())
+		return result
+	}
+
+	duration, _ := v1alpha1.ConvertDuration(d.config.Duration)
+	deadline := now.Add(time.Duration(d.config.Timeout) * time.Second)
+	for time.Now().Before(deadline) {
+		select {
+		case <-ctx.Done():
+			result.Message = "drill is canceled"
+			return result
+		case <-time.After(pollInterval):
+		}
+
+		if err := d.client.Get(ctx, types.NamespacedName{Namespace: exp.Namespace, Name: exp.Name}, exp); err != nil {
+			result.Message = fmt.Sprintf("get drill experiment error: %s", err.Error())
+			return result
+		}
+
+		if exp.Status.Status == v1alpha1.FailedStatusType {
+			result.Message = fmt.Sprintf("drill experiment failed in phase[%s]: %s", exp.Status.Phase, exp.Status.Message)
+			return result
+		}
+
+		if exp.Status.Phase == v1alpha1.RecoverPhaseType && exp.Status.Status == v1alpha1.SuccessStatusType {
+			createTime, err := time.ParseInLocation(model.TimeFormat, exp.Status.CreateTime, time.Local)
+			if err != nil {
+				createTime = now
+			}
+			result.Success = true
+			result.RecoverLatency = time.Since(createTime.Add(duration)).Round(time.Second).String()
+			return result
+		}
+	}
+
+	result.Message = fmt.Sprintf("not recovered automatically in %ds, phase: %s, status: %s", d.config.Timeout, exp.Status.Phase, exp.Status.Status)
+	return result
+}
+
+func (d *Driller) newExperiment(now time.Time) *v1alpha1.Experiment {
+	name := fmt.Sprintf("%s%d", NamePrefix, now.Unix())
+	return &v1alpha1.Experiment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: d.config.Namespace,
+			Labels:    map[string]string{LabelKey: "true"},
+		},
+		Spec: v1alpha1.ExperimentSpec{
+			Scope:     v1alpha1.PodScopeType,
+			RangeMode: &v1alpha1.RangeMode{Type: v1alpha1.AllRangeType},
+			Experiment: &v1alpha1.ExperimentCommon{
+				Duration: d.config.Duration,
+				Target:   "file",
+				Fault:    "add",
+				Args: []v1alpha1.ArgsUnit{
+					{Key: "path", Value: drillFilePrefix + name, ValueType: v1alpha1.StringVType},
+				},
+			},
+			Selector: []v1alpha1.SelectorUnit{
+				{Namespace: d.config.Namespace, Name: []string{d.config.Pod}},
+			},
+			TargetPhase: v1alpha1.InjectPhaseType,
+		},
+	}
+}
+
+// cleanup deletes the drill experiment, it is recovered first by the finalizer if still injected
+func (d *Driller) cleanup(ctx context.Context, exp *v1alpha1.Experiment) {
+	if err := d.client.Delete(ctx, exp); client.IgnoreNotFound(err) != nil {
+		log.FromContext(ctx).Error(err, fmt.Sprintf("delete drill experiment[%s] error", exp.Name))
+	}
+}
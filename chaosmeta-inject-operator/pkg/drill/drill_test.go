@@ -0,0 +1,53 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package drill
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/config"
+	"testing"
+	"time"
+)
+
+func TestDegradedReason(t *testing.T) {
+	d := NewDriller(nil, config.DrillConfig{Interval: 60, RecoverLatency: 30, SuccessRate: 80, Window: 5})
+
+	ok := Result{Name: "ok", Success: true, RecoverLatency: "5s"}
+	d.history = []Result{ok, ok, ok, ok}
+	assert.Equal(t, "", d.degradedReason(ok))
+
+	slow := Result{Name: "slow", Success: true, RecoverLatency: "45s"}
+	assert.Contains(t, d.degradedReason(slow), "exceeds 30s")
+
+	failed := Result{Name: "failed", Message: "timeout"}
+	assert.Contains(t, d.degradedReason(failed), "drill[failed] failed")
+
+	d.history = []Result{ok, failed, ok, failed, ok}
+	assert.Equal(t, 60, d.successRate())
+	assert.Contains(t, d.degradedReason(ok), "success rate[60%]")
+}
+
+func TestNewDrillerDefault(t *testing.T) {
+	d := NewDriller(nil, config.DrillConfig{Interval: 60, Namespace: "default", Pod: "canary"})
+	assert.Equal(t, defaultDuration, d.config.Duration)
+	assert.Equal(t, defaultTimeout, d.config.Timeout)
+	assert.Equal(t, defaultWindow, d.config.Window)
+
+	exp := d.newExperiment(time.Now())
+	assert.Equal(t, []string{"canary"}, exp.Spec.Selector[0].Name)
+	assert.Equal(t, drillFilePrefix+exp.Name, exp.Spec.Experiment.Args[0].Value)
+}
@@ -64,7 +64,7 @@ func createJob(ctx context.Context, namespace, name string) error {
 		},
 	}
 
-	return restclient.GetApiServerClientMap(v1alpha1.JobCloudTarget).Post().Resource("jobs").Namespace(job.Namespace).Body(job).Do(ctx).Error()
+	return restclient.GetApiServerClientMap(ctx, v1alpha1.JobCloudTarget).Post().Resource("jobs").Namespace(job.Namespace).Body(job).Do(ctx).Error()
 }
 
 func (e *ClusterCompletedJobExecutor) Inject(ctx context.Context, injectObject, uid, timeout string, args []v1alpha1.ArgsUnit) (string, error) {
@@ -73,7 +73,7 @@ func (e *ClusterCompletedJobExecutor) Inject(ctx context.Context, injectObject,
 
 func (e *ClusterCompletedJobExecutor) Recover(ctx context.Context, injectObject, uid, backup string) error {
 	common.GetClusterCtrl().Stop()
-	if err := restclient.GetApiServerClientMap(v1alpha1.NamespaceCloudTarget).Delete().Resource("namespaces").
+	if err := restclient.GetApiServerClientMap(ctx, v1alpha1.NamespaceCloudTarget).Delete().Resource("namespaces").
 		Name(injectObject).Do(ctx).Error(); err != nil {
 		return fmt.Errorf("create namespace error: %s", err.Error())
 	}
@@ -66,7 +66,7 @@ func createPendingPod(ctx context.Context, namespace, name string) error {
 		},
 	}
 
-	return restclient.GetApiServerClientMap(v1alpha1.PodCloudTarget).Post().Resource("pods").Namespace(pod.Namespace).Body(pod).Do(ctx).Error()
+	return restclient.GetApiServerClientMap(ctx, v1alpha1.PodCloudTarget).Post().Resource("pods").Namespace(pod.Namespace).Body(pod).Do(ctx).Error()
 }
 
 func (e *ClusterPendingPodExecutor) Inject(ctx context.Context, injectObject, uid, timeout string, args []v1alpha1.ArgsUnit) (string, error) {
@@ -37,7 +37,7 @@ func (e *DeploymentDeleteExecutor) Inject(ctx context.Context, injectObject, uid
 		return "", fmt.Errorf("unexpected deployment format: %s", err.Error())
 	}
 
-	return "", restclient.GetApiServerClientMap(v1alpha1.DeploymentCloudTarget).Delete().Namespace(ns).
+	return "", restclient.GetApiServerClientMap(ctx, v1alpha1.DeploymentCloudTarget).Delete().Namespace(ns).
 		Resource("deployments").Name(name).Do(ctx).Error()
 }
 
@@ -39,7 +39,7 @@ func (e *DeploymentFinalizerExecutor) Inject(ctx context.Context, injectObject,
 		return "", fmt.Errorf("unexpected deployment format: %s", err.Error())
 	}
 
-	c, deploy := restclient.GetApiServerClientMap(v1alpha1.DeploymentCloudTarget), &v1.Deployment{}
+	c, deploy := restclient.GetApiServerClientMap(ctx, v1alpha1.DeploymentCloudTarget), &v1.Deployment{}
 	if err := c.Get().Namespace(ns).Resource("deployments").Name(name).Do(ctx).Into(deploy); err != nil {
 		return "", fmt.Errorf("get deployment error: %s", err.Error())
 	}
@@ -68,7 +68,7 @@ func (e *DeploymentFinalizerExecutor) Recover(ctx context.Context, injectObject,
 		}
 	}
 
-	c := restclient.GetApiServerClientMap(v1alpha1.DeploymentCloudTarget)
+	c := restclient.GetApiServerClientMap(ctx, v1alpha1.DeploymentCloudTarget)
 	return patchFinalizers(ctx, c, "deployments", ns, name, oldFinalizers)
 }
 
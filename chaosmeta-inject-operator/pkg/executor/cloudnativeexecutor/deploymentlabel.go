@@ -39,7 +39,7 @@ func (e *DeploymentLabelExecutor) Inject(ctx context.Context, injectObject, uid,
 		return "", fmt.Errorf("unexpected deployment format: %s", err.Error())
 	}
 
-	c, deploy := restclient.GetApiServerClientMap(v1alpha1.DeploymentCloudTarget), &v1.Deployment{}
+	c, deploy := restclient.GetApiServerClientMap(ctx, v1alpha1.DeploymentCloudTarget), &v1.Deployment{}
 	if err := c.Get().Namespace(ns).Resource("deployments").Name(name).Do(ctx).Into(deploy); err != nil {
 		return "", fmt.Errorf("get deployment error: %s", err.Error())
 	}
@@ -66,7 +66,7 @@ func (e *DeploymentLabelExecutor) Recover(ctx context.Context, injectObject, uid
 		return fmt.Errorf("unexpected deployment format: %s", err.Error())
 	}
 
-	c, deploy := restclient.GetApiServerClientMap(v1alpha1.DeploymentCloudTarget), &v1.Deployment{}
+	c, deploy := restclient.GetApiServerClientMap(ctx, v1alpha1.DeploymentCloudTarget), &v1.Deployment{}
 	if err := c.Get().Namespace(ns).Resource("deployments").Name(name).Do(ctx).Into(deploy); err != nil {
 		return fmt.Errorf("get deployment error: %s", err.Error())
 	}
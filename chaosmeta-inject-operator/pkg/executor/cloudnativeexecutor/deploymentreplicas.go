@@ -45,7 +45,7 @@ func (e *DeploymentReplicasExecutor) Inject(ctx context.Context, injectObject, u
 		return "", fmt.Errorf("args error: %s", err.Error())
 	}
 
-	c := restclient.GetApiServerClientMap(v1alpha1.DeploymentCloudTarget)
+	c := restclient.GetApiServerClientMap(ctx, v1alpha1.DeploymentCloudTarget)
 	deploy := &v1.Deployment{}
 	if err := c.Get().Namespace(ns).Resource("deployments").Name(name).Do(ctx).Into(deploy); err != nil {
 		return "", fmt.Errorf("get deployment error: %s", err.Error())
@@ -76,7 +76,7 @@ func (e *DeploymentReplicasExecutor) Recover(ctx context.Context, injectObject,
 		return fmt.Errorf("old replicas is not a num: %s", err.Error())
 	}
 
-	c := restclient.GetApiServerClientMap(v1alpha1.DeploymentCloudTarget)
+	c := restclient.GetApiServerClientMap(ctx, v1alpha1.DeploymentCloudTarget)
 	if err := c.Patch(types.MergePatchType).Namespace(ns).Resource("deployments").Name(name).
 		Body([]byte(fmt.Sprintf(`{"spec":{"replicas":%d}}`, oldCount))).SubResource("scale").Do(ctx).Error(); err != nil {
 		return fmt.Errorf("patch deployment error: %s", err.Error())
@@ -224,7 +224,7 @@ func patchLabels(ctx context.Context, c rest.Interface, resource, ns, name strin
 }
 
 func createNs(ctx context.Context, name string) error {
-	return restclient.GetApiServerClientMap(v1alpha1.NamespaceCloudTarget).Post().Resource("namespaces").
+	return restclient.GetApiServerClientMap(ctx, v1alpha1.NamespaceCloudTarget).Post().Resource("namespaces").
 		Body(&corev1.Namespace{
 			ObjectMeta: metav1.ObjectMeta{
 				Name: name,
@@ -233,7 +233,7 @@ func createNs(ctx context.Context, name string) error {
 }
 
 func deleteNs(ctx context.Context, name string) error {
-	return restclient.GetApiServerClientMap(v1alpha1.NamespaceCloudTarget).Delete().Resource("namespaces").
+	return restclient.GetApiServerClientMap(ctx, v1alpha1.NamespaceCloudTarget).Delete().Resource("namespaces").
 		Name(name).Do(ctx).Error()
 }
 
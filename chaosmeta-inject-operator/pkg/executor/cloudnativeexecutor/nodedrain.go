@@ -0,0 +1,286 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cloudnativeexecutor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/common"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/model"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/restclient"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The node is uncordoned in recover, and the finalizer of the experiment makes sure recover is done before the
+// experiment is deleted. A node which is unschedulable before the experiment is left unschedulable
+func init() {
+	registerCloudExecutor(v1alpha1.NodeCloudTarget, "cordon", &NodeDrainExecutor{})
+	registerCloudExecutor(v1alpha1.NodeCloudTarget, "drain", &NodeDrainExecutor{drain: true})
+}
+
+// NodeDrainExecutor cordons the node, and for fault drain also deletes or evicts its pods except the ones
+// of DaemonSets and the static pods, the same as "kubectl drain". Like "kubectl drain", the pods are evicted
+// respecting PodDisruptionBudgets by default, and the drain is refused if the node has pods not managed by a
+// controller or using emptyDir, which are lost forever, unless arg "force" is true
+type NodeDrainExecutor struct {
+	drain bool
+}
+
+type nodeDrainBackup struct {
+	Unschedulable bool   `json:"unschedulable"`
+	PDBPolicy     string `json:"pdbPolicy,omitempty"`
+	Force         bool   `json:"force,omitempty"`
+	PDBTimeout    string `json:"pdbTimeout,omitempty"`
+	GracePeriod   *int64 `json:"gracePeriod,omitempty"`
+	StartTime     string `json:"startTime"`
+}
+
+func (e *NodeDrainExecutor) Inject(ctx context.Context, injectObject, uid, timeout string, args []v1alpha1.ArgsUnit) (string, error) {
+	name, _, err := model.ParseNodeInfo(injectObject)
+	if err != nil {
+		return "", fmt.Errorf("unexpected node format: %s", err.Error())
+	}
+
+	backup := &nodeDrainBackup{}
+	if e.drain {
+		if err := setDrainArgs(backup, args, timeout); err != nil {
+			return "", err
+		}
+	}
+
+	c, node := restclient.GetApiServerClientMap(ctx, v1alpha1.NodeCloudTarget), &corev1.Node{}
+	if err := c.Get().Resource("nodes").Name(name).Do(ctx).Into(node); err != nil {
+		return "", fmt.Errorf("get node error: %s", err.Error())
+	}
+
+	backup.Unschedulable, backup.StartTime = node.Spec.Unschedulable, time.Now().Format(model.TimeFormat)
+	backupBytes, err := json.Marshal(backup)
+	if err != nil {
+		return "", fmt.Errorf("backup to string error: %s", err.Error())
+	}
+
+	if err := patchUnschedulable(ctx, name, true); err != nil {
+		return "", err
+	}
+
+	if e.drain {
+		if _, err := drainNode(ctx, name, backup); err != nil {
+			// the backup is dropped when inject fails, so undo the cordon here
+			if !backup.Unschedulable {
+				if undoErr := patchUnschedulable(ctx, name, false); undoErr != nil {
+					log.FromContext(ctx).Error(undoErr, "undo cordon error")
+				}
+			}
+			return "", err
+		}
+	}
+
+	return string(backupBytes), nil
+}
+
+func (e *NodeDrainExecutor) Recover(ctx context.Context, injectObject, uid, backup string) error {
+	name, _, err := model.ParseNodeInfo(injectObject)
+	if err != nil {
+		return fmt.Errorf("unexpected node format: %s", err.Error())
+	}
+
+	b := &nodeDrainBackup{}
+	if backup != "" {
+		if err := json.Unmarshal([]byte(backup), b); err != nil {
+			return fmt.Errorf("unexpected backup format: %s", err.Error())
+		}
+	}
+
+	if b.Unschedulable {
+		return nil
+	}
+
+	return patchUnschedulable(ctx, name, false)
+}
+
+// Query evicting pods blocked by a PodDisruptionBudget is retried in every query until pdbTimeout
+func (e *NodeDrainExecutor) Query(ctx context.Context, injectObject, uid, backup string, phase v1alpha1.PhaseType) (*model.SubExpInfo, error) {
+	info := &model.SubExpInfo{
+		UID:        uid,
+		Status:     v1alpha1.SuccessStatusType,
+		UpdateTime: time.Now().Format(model.TimeFormat),
+	}
+
+	if !e.drain || phase != v1alpha1.InjectPhaseType {
+		return info, nil
+	}
+
+	b := &nodeDrainBackup{}
+	if err := json.Unmarshal([]byte(backup), b); err != nil {
+		return nil, fmt.Errorf("unexpected backup format: %s", err.Error())
+	}
+	info.CreateTime = b.StartTime
+
+	name, _, err := model.ParseNodeInfo(injectObject)
+	if err != nil {
+		return nil, fmt.Errorf("unexpected node format: %s", err.Error())
+	}
+
+	blocked, err := drainNode(ctx, name, b)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(blocked) == 0 {
+		info.Message = "node drained"
+		return info, nil
+	}
+
+	pdbTimeout := defaultPDBTimeout
+	if b.PDBTimeout != "" {
+		pdbTimeout, _ = time.ParseDuration(b.PDBTimeout)
+	}
+
+	startTime, err := time.ParseInLocation(model.TimeFormat, b.StartTime, time.Local)
+	if err == nil && time.Since(startTime) > pdbTimeout {
+		info.Status, info.Message = v1alpha1.FailedStatusType, fmt.Sprintf("eviction of pods[%s] is blocked by PodDisruptionBudget over %s", strings.Join(blocked, ","), pdbTimeout)
+		return info, nil
+	}
+
+	info.Status, info.Message = v1alpha1.RunningStatusType, fmt.Sprintf("eviction of pods[%s] is blocked by PodDisruptionBudget, back off and retry", strings.Join(blocked, ","))
+	return info, nil
+}
+
+// setDrainArgs drain shares the args of pod delete, the pods are deleted only once
+func setDrainArgs(backup *nodeDrainBackup, args []v1alpha1.ArgsUnit, timeout string) error {
+	deleteArgs, err := getPodDeleteArgs(args, false, timeout)
+	if err != nil {
+		return err
+	}
+	if deleteArgs.Interval != "" {
+		return fmt.Errorf("\"interval\" is not supported by drain")
+	}
+
+	reArgs := common.GetArgs(args, []string{"pdbPolicy", "force"})
+	if reArgs[0] == "" {
+		deleteArgs.PDBPolicy = PDBPolicyRespect
+	}
+	if reArgs[1] != "" {
+		if backup.Force, err = strconv.ParseBool(reArgs[1]); err != nil {
+			return fmt.Errorf("\"force\" is not a bool: %s", reArgs[1])
+		}
+	}
+
+	backup.PDBPolicy, backup.PDBTimeout, backup.GracePeriod = deleteArgs.PDBPolicy, deleteArgs.PDBTimeout, deleteArgs.GracePeriod
+	return nil
+}
+
+func patchUnschedulable(ctx context.Context, name string, unschedulable bool) error {
+	if err := restclient.GetApiServerClientMap(ctx, v1alpha1.NodeCloudTarget).Patch(types.MergePatchType).Resource("nodes").
+		Name(name).Body([]byte(fmt.Sprintf(`{"spec":{"unschedulable":%t}}`, unschedulable))).Do(ctx).Error(); err != nil {
+		return fmt.Errorf("patch unschedulable of node error: %s", err.Error())
+	}
+
+	return nil
+}
+
+// drainNode deletes or evicts the pods on the node, and returns the pods whose eviction is blocked
+func drainNode(ctx context.Context, name string, b *nodeDrainBackup) ([]string, error) {
+	podList := &corev1.PodList{}
+	if err := restclient.GetApiServerClientMap(ctx, v1alpha1.PodCloudTarget).Get().Resource("pods").
+		Param("fieldSelector", fmt.Sprintf("spec.nodeName=%s", name)).Do(ctx).Into(podList); err != nil {
+		return nil, fmt.Errorf("list pods of node error: %s", err.Error())
+	}
+
+	pods, err := getDrainPods(podList.Items, b.Force)
+	if err != nil {
+		return nil, err
+	}
+
+	var blocked []string
+	for _, pod := range pods {
+		if b.PDBPolicy == PDBPolicyOverride {
+			if err := deletePod(ctx, pod.Namespace, pod.Name, b.GracePeriod); err != nil && !errors.IsNotFound(err) {
+				return nil, err
+			}
+			continue
+		}
+
+		evicted, err := evictPod(ctx, pod.Namespace, pod.Name, string(pod.UID), b.GracePeriod)
+		if err != nil {
+			return nil, err
+		}
+		if !evicted {
+			blocked = append(blocked, fmt.Sprintf("%s/%s", pod.Namespace, pod.Name))
+		}
+	}
+
+	return blocked, nil
+}
+
+// getDrainPods skips the pods already terminating or finished, the pods of DaemonSets and the static pods. Without
+// force, no pod is drained if any pod is not managed by a controller or uses emptyDir
+func getDrainPods(pods []corev1.Pod, force bool) ([]corev1.Pod, error) {
+	var result []corev1.Pod
+	var unmanaged, emptyDir []string
+	for _, pod := range pods {
+		if pod.DeletionTimestamp != nil || pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+
+		if _, isMirror := pod.Annotations[corev1.MirrorPodAnnotationKey]; isMirror {
+			continue
+		}
+
+		controller := metav1.GetControllerOf(&pod)
+		if controller != nil && controller.Kind == "DaemonSet" {
+			continue
+		}
+
+		podName := fmt.Sprintf("%s/%s", pod.Namespace, pod.Name)
+		if controller == nil {
+			unmanaged = append(unmanaged, podName)
+		}
+		for _, volume := range pod.Spec.Volumes {
+			if volume.EmptyDir != nil {
+				emptyDir = append(emptyDir, podName)
+				break
+			}
+		}
+
+		result = append(result, pod)
+	}
+
+	if !force {
+		var reasons []string
+		if len(unmanaged) > 0 {
+			reasons = append(reasons, fmt.Sprintf("pods[%s] are not managed by a controller", strings.Join(unmanaged, ",")))
+		}
+		if len(emptyDir) > 0 {
+			reasons = append(reasons, fmt.Sprintf("pods[%s] use emptyDir whose data will be deleted", strings.Join(emptyDir, ",")))
+		}
+		if len(reasons) > 0 {
+			return nil, fmt.Errorf("%s, set \"force\" to true to drain them", strings.Join(reasons, "; "))
+		}
+	}
+
+	return result, nil
+}
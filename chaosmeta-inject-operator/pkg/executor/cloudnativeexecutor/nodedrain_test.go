@@ -0,0 +1,81 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cloudnativeexecutor
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"testing"
+)
+
+func Test_getDrainPods(t *testing.T) {
+	isController, now := true, metav1.Now()
+	owner := func(kind string) []metav1.OwnerReference {
+		return []metav1.OwnerReference{{Kind: kind, Name: "owner", Controller: &isController}}
+	}
+	pods := []corev1.Pod{
+		{ObjectMeta: metav1.ObjectMeta{Name: "app", OwnerReferences: owner("ReplicaSet")}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "daemon", OwnerReferences: owner("DaemonSet")}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "static", Annotations: map[string]string{corev1.MirrorPodAnnotationKey: "hash"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "terminating", DeletionTimestamp: &now}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "done"}, Status: corev1.PodStatus{Phase: corev1.PodSucceeded}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "job", OwnerReferences: owner("Job")}},
+	}
+
+	getNames := func(pods []corev1.Pod, force bool) ([]string, error) {
+		result, err := getDrainPods(pods, force)
+		var names []string
+		for _, pod := range result {
+			names = append(names, pod.Name)
+		}
+		return names, err
+	}
+
+	names, err := getNames(pods, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"app", "job"}, names)
+
+	bare := corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "bare"}}
+	cache := corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "cache", OwnerReferences: owner("ReplicaSet")},
+		Spec: corev1.PodSpec{Volumes: []corev1.Volume{{Name: "tmp", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}}}}}
+
+	_, err = getNames(append(pods, bare), false)
+	assert.ErrorContains(t, err, "pods[ns/bare] are not managed by a controller")
+	_, err = getNames(append(pods, cache), false)
+	assert.ErrorContains(t, err, "pods[ns/cache] use emptyDir")
+
+	names, err = getNames(append(pods, bare, cache), true)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"app", "job", "bare", "cache"}, names)
+}
+
+func Test_setDrainArgs(t *testing.T) {
+	backup := &nodeDrainBackup{}
+	assert.NoError(t, setDrainArgs(backup, nil, ""))
+	assert.Equal(t, PDBPolicyRespect, backup.PDBPolicy)
+	assert.False(t, backup.Force)
+
+	backup = &nodeDrainBackup{}
+	assert.NoError(t, setDrainArgs(backup, []v1alpha1.ArgsUnit{{Key: "pdbPolicy", Value: PDBPolicyOverride}, {Key: "force", Value: "true"}}, ""))
+	assert.Equal(t, PDBPolicyOverride, backup.PDBPolicy)
+	assert.True(t, backup.Force)
+
+	assert.Error(t, setDrainArgs(&nodeDrainBackup{}, []v1alpha1.ArgsUnit{{Key: "force", Value: "yes"}}, ""))
+	assert.Error(t, setDrainArgs(&nodeDrainBackup{}, []v1alpha1.ArgsUnit{{Key: "interval", Value: "1m"}}, ""))
+}
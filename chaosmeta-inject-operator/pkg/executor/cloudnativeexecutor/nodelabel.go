@@ -43,7 +43,7 @@ func (e *NodeLabelExecutor) Inject(ctx context.Context, injectObject, uid, timeo
 		return "", fmt.Errorf("unexpected node format: %s", err.Error())
 	}
 
-	c, node := restclient.GetApiServerClientMap(v1alpha1.NodeCloudTarget), &corev1.Node{}
+	c, node := restclient.GetApiServerClientMap(ctx, v1alpha1.NodeCloudTarget), &corev1.Node{}
 	if err := c.Get().Resource("nodes").Name(name).Do(ctx).Into(node); err != nil {
 		return "", fmt.Errorf("get node error: %s", err.Error())
 	}
@@ -70,7 +70,7 @@ func (e *NodeLabelExecutor) Recover(ctx context.Context, injectObject, uid, back
 		return fmt.Errorf("unexpected node format: %s", err.Error())
 	}
 
-	c, node := restclient.GetApiServerClientMap(v1alpha1.NodeCloudTarget), &corev1.Node{}
+	c, node := restclient.GetApiServerClientMap(ctx, v1alpha1.NodeCloudTarget), &corev1.Node{}
 	if err := c.Get().Resource("nodes").Name(name).Do(ctx).Into(node); err != nil {
 		return fmt.Errorf("get node error: %s", err.Error())
 	}
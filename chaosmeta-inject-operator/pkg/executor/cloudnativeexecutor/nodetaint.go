@@ -43,7 +43,7 @@ func (e *NodeTaintExecutor) Inject(ctx context.Context, injectObject, uid, timeo
 		return "", fmt.Errorf("unexpected node format: %s", err.Error())
 	}
 
-	c, node := restclient.GetApiServerClientMap(v1alpha1.NodeCloudTarget), &corev1.Node{}
+	c, node := restclient.GetApiServerClientMap(ctx, v1alpha1.NodeCloudTarget), &corev1.Node{}
 	if err := c.Get().Resource("nodes").Name(name).Do(ctx).Into(node); err != nil {
 		return "", fmt.Errorf("get node error: %s", err.Error())
 	}
@@ -113,7 +113,7 @@ func (e *NodeTaintExecutor) Recover(ctx context.Context, injectObject, uid, back
 		}
 	}
 
-	c := restclient.GetApiServerClientMap(v1alpha1.NodeCloudTarget)
+	c := restclient.GetApiServerClientMap(ctx, v1alpha1.NodeCloudTarget)
 	return patchTaints(ctx, c, name, oldTaints)
 }
 
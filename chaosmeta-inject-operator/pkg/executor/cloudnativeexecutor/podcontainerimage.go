@@ -74,7 +74,7 @@ func (e *PodContainerImageExecutor) Query(ctx context.Context, injectObject, uid
 }
 
 func patchImage(ctx context.Context, ns, name, containerName, newImage string) (string, error) {
-	var c, pod = restclient.GetApiServerClientMap(v1alpha1.PodCloudTarget), &corev1.Pod{}
+	var c, pod = restclient.GetApiServerClientMap(ctx, v1alpha1.PodCloudTarget), &corev1.Pod{}
 	var oldImage string
 	var index int
 	// get container info
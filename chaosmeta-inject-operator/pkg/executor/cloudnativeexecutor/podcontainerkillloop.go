@@ -0,0 +1,184 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cloudnativeexecutor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/common"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/executor/remoteexecutor"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/model"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/restclient"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/selector"
+	corev1 "k8s.io/api/core/v1"
+	"time"
+)
+
+const defaultKillLoopInterval = 30 * time.Second
+
+func init() {
+	registerCloudExecutor(v1alpha1.PodCloudTarget, "containerkillloop", &PodContainerKillLoopExecutor{})
+}
+
+// PodContainerKillLoopExecutor kills the container by the agent on its host, and kills it again every time it has
+// been running for the interval after restarting, until the experiment ends. It simulates a crash-looping dependency
+type PodContainerKillLoopExecutor struct{}
+
+type containerKillLoopBackup struct {
+	HostIP       string `json:"hostIP"`
+	Interval     string `json:"interval"`
+	Duration     string `json:"duration"`
+	StartTime    string `json:"startTime"`
+	RestartCount int32  `json:"restartCount"`
+}
+
+func (e *PodContainerKillLoopExecutor) Inject(ctx context.Context, injectObject, uid, timeout string, args []v1alpha1.ArgsUnit) (string, error) {
+	ns, name, containerName, err := model.ParsePodInfo(injectObject)
+	if err != nil {
+		return "", fmt.Errorf("unexpected pod format: %s", err.Error())
+	}
+
+	if containerName == "" {
+		return "", fmt.Errorf("container name not provide")
+	}
+
+	if timeout == "" {
+		return "", fmt.Errorf("the container is killed in a loop until the experiment ends, duration is required")
+	}
+
+	interval := defaultKillLoopInterval
+	if intervalStr := common.GetArgs(args, []string{"interval"})[0]; intervalStr != "" {
+		if interval, err = time.ParseDuration(intervalStr); err != nil || interval <= 0 {
+			return "", fmt.Errorf("\"interval\" is not a positive duration: %s", intervalStr)
+		}
+	}
+
+	pod, status, err := getTargetContainerStatus(ctx, ns, name, containerName)
+	if err != nil {
+		return "", err
+	}
+
+	backup := &containerKillLoopBackup{
+		HostIP:       pod.Status.HostIP,
+		Interval:     interval.String(),
+		Duration:     timeout,
+		StartTime:    time.Now().Format(model.TimeFormat),
+		RestartCount: status.RestartCount,
+	}
+
+	if err := killContainer(ctx, backup.HostIP, uid, status); err != nil {
+		return "", err
+	}
+
+	backupBytes, err := json.Marshal(backup)
+	if err != nil {
+		return "", fmt.Errorf("backup to string error: %s", err.Error())
+	}
+
+	return string(backupBytes), nil
+}
+
+// Recover a killed container is restarted by kubelet, there is nothing to recover
+func (e *PodContainerKillLoopExecutor) Recover(ctx context.Context, injectObject, uid, backup string) error {
+	return nil
+}
+
+func (e *PodContainerKillLoopExecutor) Query(ctx context.Context, injectObject, uid, backup string, phase v1alpha1.PhaseType) (*model.SubExpInfo, error) {
+	info := &model.SubExpInfo{
+		UID:        uid,
+		Status:     v1alpha1.SuccessStatusType,
+		UpdateTime: time.Now().Format(model.TimeFormat),
+	}
+
+	if phase != v1alpha1.InjectPhaseType {
+		return info, nil
+	}
+
+	b := &containerKillLoopBackup{}
+	if err := json.Unmarshal([]byte(backup), b); err != nil {
+		return nil, fmt.Errorf("unexpected backup format: %s", err.Error())
+	}
+	info.CreateTime = b.StartTime
+
+	ns, name, containerName, err := model.ParsePodInfo(injectObject)
+	if err != nil {
+		return nil, fmt.Errorf("unexpected pod format: %s", err.Error())
+	}
+
+	_, status, err := getTargetContainerStatus(ctx, ns, name, containerName)
+	if err != nil {
+		return nil, err
+	}
+
+	restarts := status.RestartCount - b.RestartCount
+	isEnd, err := common.IsTimeout(b.StartTime, b.Duration)
+	if err != nil {
+		return nil, fmt.Errorf("check if experiment ends error: %s", err.Error())
+	}
+
+	if isEnd {
+		info.Message = fmt.Sprintf("container[%s] killed every %s until the experiment ended, restarted %d times", status.Name, b.Interval, restarts)
+		return info, nil
+	}
+
+	info.Status = v1alpha1.RunningStatusType
+	interval, _ := time.ParseDuration(b.Interval)
+	if status.State.Running == nil || time.Since(status.State.Running.StartedAt.Time) < interval {
+		info.Message = fmt.Sprintf("container[%s] restarted %d times, next kill is %s after it is running", status.Name, restarts, b.Interval)
+		return info, nil
+	}
+
+	// every kill is a new experiment of the agent, the restart count keeps the uid unique
+	if err := killContainer(ctx, b.HostIP, fmt.Sprintf("%s-%d", uid, status.RestartCount), status); err != nil {
+		return nil, err
+	}
+
+	info.Message = fmt.Sprintf("container[%s] killed again, restarted %d times", status.Name, restarts)
+	return info, nil
+}
+
+func getTargetContainerStatus(ctx context.Context, ns, name, containerName string) (*corev1.Pod, *corev1.ContainerStatus, error) {
+	pod := &corev1.Pod{}
+	if err := restclient.GetApiServerClientMap(ctx, v1alpha1.PodCloudTarget).Get().Namespace(ns).Resource("pods").
+		Name(name).Do(ctx).Into(pod); err != nil {
+		return nil, nil, fmt.Errorf("get pod error: %s", err.Error())
+	}
+
+	_, _, targetName, err := selector.GetTargetContainer(containerName, pod.Status.ContainerStatuses)
+	if err != nil {
+		return nil, nil, fmt.Errorf("get target container[%s] in pod[%s] error: %s", containerName, pod.Name, err.Error())
+	}
+
+	for i := range pod.Status.ContainerStatuses {
+		if pod.Status.ContainerStatuses[i].Name == targetName {
+			return pod, &pod.Status.ContainerStatuses[i], nil
+		}
+	}
+
+	return nil, nil, fmt.Errorf("not found container %s", targetName)
+}
+
+func killContainer(ctx context.Context, hostIP, uid string, status *corev1.ContainerStatus) error {
+	r, id, err := model.ParseContainerID(status.ContainerID)
+	if err != nil {
+		return fmt.Errorf("parse container id[%s] error: %s", status.ContainerID, err.Error())
+	}
+
+	return remoteexecutor.GetRemoteExecutor("").Inject(ctx, hostIP, "container", "kill", uid, "", id, r, nil)
+}
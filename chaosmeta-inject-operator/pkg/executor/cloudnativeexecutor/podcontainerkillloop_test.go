@@ -0,0 +1,84 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cloudnativeexecutor
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/agiledragon/gomonkey"
+	"github.com/stretchr/testify/assert"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/model"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"testing"
+	"time"
+)
+
+func TestPodContainerKillLoopExecutor_Query(t *testing.T) {
+	var (
+		ctx       = context.Background()
+		e         = GetCloudNativeExecutor(v1alpha1.PodCloudTarget, "containerkillloop")
+		killCount int
+		status    = &corev1.ContainerStatus{
+			Name:         "nginx",
+			ContainerID:  "docker://fwaf1",
+			RestartCount: 3,
+			State: corev1.ContainerState{
+				Running: &corev1.ContainerStateRunning{StartedAt: metav1.NewTime(time.Now().Add(-time.Minute))},
+			},
+		}
+		backup = &containerKillLoopBackup{
+			HostIP:       "1.2.3.4",
+			Interval:     "30s",
+			Duration:     "10m",
+			StartTime:    time.Now().Add(-5 * time.Minute).Format(model.TimeFormat),
+			RestartCount: 1,
+		}
+	)
+
+	gomonkey.ApplyFunc(getTargetContainerStatus, func(ctx context.Context, ns, name, containerName string) (*corev1.Pod, *corev1.ContainerStatus, error) {
+		return &corev1.Pod{}, status, nil
+	})
+	gomonkey.ApplyFunc(killContainer, func(ctx context.Context, hostIP, uid string, status *corev1.ContainerStatus) error {
+		assert.Equal(t, "fwaf1-3", uid)
+		killCount++
+		return nil
+	})
+
+	backupBytes, _ := json.Marshal(backup)
+	// running longer than the interval, kill it again
+	re, err := e.Query(ctx, "pod/chaosmeta/nginx-0/nginx", "fwaf1", string(backupBytes), v1alpha1.InjectPhaseType)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, v1alpha1.RunningStatusType, re.Status)
+	assert.Equal(t, 1, killCount)
+
+	// just restarted, wait for the interval
+	status.State.Running.StartedAt = metav1.NewTime(time.Now())
+	re, err = e.Query(ctx, "pod/chaosmeta/nginx-0/nginx", "fwaf1", string(backupBytes), v1alpha1.InjectPhaseType)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, v1alpha1.RunningStatusType, re.Status)
+	assert.Equal(t, 1, killCount)
+
+	// the experiment ends
+	backup.StartTime = time.Now().Add(-time.Hour).Format(model.TimeFormat)
+	backupBytes, _ = json.Marshal(backup)
+	re, err = e.Query(ctx, "pod/chaosmeta/nginx-0/nginx", "fwaf1", string(backupBytes), v1alpha1.InjectPhaseType)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, v1alpha1.SuccessStatusType, re.Status)
+	assert.Contains(t, re.Message, "restarted 2 times")
+}
@@ -45,7 +45,7 @@ func (e *PodContainerPauseExecutor) Inject(ctx context.Context, injectObject, ui
 	}
 
 	// get container id and host ip
-	c := restclient.GetApiServerClientMap(v1alpha1.PodCloudTarget)
+	c := restclient.GetApiServerClientMap(ctx, v1alpha1.PodCloudTarget)
 	pod := &corev1.Pod{}
 	if err := c.Get().Namespace(ns).Resource("pods").Name(name).Do(ctx).Into(pod); err != nil {
 		return "", fmt.Errorf("get pod error: %s", err.Error())
@@ -57,12 +57,12 @@ func (e *PodContainerPauseExecutor) Inject(ctx context.Context, injectObject, ui
 		return "", fmt.Errorf("get target container[%s] in pod[%s] error: %s", containerName, pod.Name, err.Error())
 	}
 
-	return hostIP, remoteexecutor.GetRemoteExecutor().Inject(ctx, hostIP, "container", "pause", uid, timeout, id, r, nil)
+	return hostIP, remoteexecutor.GetRemoteExecutor("").Inject(ctx, hostIP, "container", "pause", uid, timeout, id, r, nil)
 }
 
 func (e *PodContainerPauseExecutor) Recover(ctx context.Context, injectObject, uid, backup string) error {
-	return remoteexecutor.GetRemoteExecutor().Recover(ctx, backup, uid)
+	return remoteexecutor.GetRemoteExecutor("").Recover(ctx, backup, uid)
 }
 func (e *PodContainerPauseExecutor) Query(ctx context.Context, injectObject, uid, backup string, phase v1alpha1.PhaseType) (*model.SubExpInfo, error) {
-	return remoteexecutor.GetRemoteExecutor().Query(ctx, backup, uid, phase)
+	return remoteexecutor.GetRemoteExecutor("").Query(ctx, backup, uid, phase)
 }
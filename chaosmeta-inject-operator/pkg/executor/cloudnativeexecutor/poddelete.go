@@ -18,18 +18,103 @@ package cloudnativeexecutor
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/common"
 	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/model"
 	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/restclient"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"strconv"
+	"strings"
 	"time"
 )
 
+const (
+	// PDBPolicyOverride delete the pod directly even if a PodDisruptionBudget protects it
+	PDBPolicyOverride = "override"
+	// PDBPolicyRespect evict the pod by Eviction API, and back off when the budget blocks
+	PDBPolicyRespect = "respect"
+
+	defaultPDBTimeout = 5 * time.Minute
+)
+
 func init() {
 	registerCloudExecutor(v1alpha1.PodCloudTarget, "delete", &PodDeleteExecutor{})
+	registerCloudExecutor(v1alpha1.PodCloudTarget, "evict", &PodDeleteExecutor{evict: true})
+}
+
+// PodDeleteExecutor deletes the pod, or evicts it by Eviction API for fault evict, which always respects PodDisruptionBudgets
+type PodDeleteExecutor struct {
+	evict bool
+}
+
+// podDeleteBackup records the pdb choice, it is kept in the status of experiment for audit
+type podDeleteBackup struct {
+	PDBPolicy   string   `json:"pdbPolicy"`
+	PodUID      string   `json:"podUID,omitempty"`
+	PDBs        []string `json:"pdbs,omitempty"`
+	Evicted     bool     `json:"evicted"`
+	StartTime   string   `json:"startTime"`
+	PDBTimeout  string   `json:"pdbTimeout,omitempty"`
+	GracePeriod *int64   `json:"gracePeriod,omitempty"`
+	Interval    string   `json:"interval,omitempty"`
+	Duration    string   `json:"duration,omitempty"`
 }
 
-type PodDeleteExecutor struct{}
+// getPodDeleteArgs checks the args and returns them in a backup without the pod info
+func getPodDeleteArgs(args []v1alpha1.ArgsUnit, evict bool, duration string) (*podDeleteBackup, error) {
+	reArgs := common.GetArgs(args, []string{"pdbPolicy", "pdbTimeout", "gracePeriod", "interval"})
+	backup := &podDeleteBackup{
+		PDBPolicy:  reArgs[0],
+		PDBTimeout: reArgs[1],
+		Interval:   reArgs[3],
+		Duration:   duration,
+	}
+
+	if backup.PDBPolicy == "" {
+		backup.PDBPolicy = PDBPolicyOverride
+		if evict {
+			backup.PDBPolicy = PDBPolicyRespect
+		}
+	}
+	if backup.PDBPolicy != PDBPolicyOverride && backup.PDBPolicy != PDBPolicyRespect {
+		return nil, fmt.Errorf("\"pdbPolicy\" only support: %s, %s", PDBPolicyOverride, PDBPolicyRespect)
+	}
+	if evict && backup.PDBPolicy != PDBPolicyRespect {
+		return nil, fmt.Errorf("fault evict only support \"pdbPolicy\": %s", PDBPolicyRespect)
+	}
+
+	if backup.PDBTimeout != "" {
+		if _, err := time.ParseDuration(backup.PDBTimeout); err != nil {
+			return nil, fmt.Errorf("\"pdbTimeout\" is invalid: %s", err.Error())
+		}
+	}
+
+	if reArgs[2] != "" {
+		gracePeriod, err := strconv.ParseInt(reArgs[2], 10, 64)
+		if err != nil || gracePeriod < 0 {
+			return nil, fmt.Errorf("\"gracePeriod\" is not a non-negative num: %s", reArgs[2])
+		}
+		backup.GracePeriod = &gracePeriod
+	}
+
+	if backup.Interval != "" {
+		interval, err := time.ParseDuration(backup.Interval)
+		if err != nil || interval <= 0 {
+			return nil, fmt.Errorf("\"interval\" is not a positive duration: %s", backup.Interval)
+		}
+		if duration == "" {
+			return nil, fmt.Errorf("\"interval\" must be used with the duration of experiment")
+		}
+	}
+
+	return backup, nil
+}
 
 func (e *PodDeleteExecutor) Inject(ctx context.Context, injectObject, uid, timeout string, args []v1alpha1.ArgsUnit) (string, error) {
 	ns, name, _, err := model.ParsePodInfo(injectObject)
@@ -37,18 +122,223 @@ func (e *PodDeleteExecutor) Inject(ctx context.Context, injectObject, uid, timeo
 		return "", fmt.Errorf("unexpected pod format: %s", err.Error())
 	}
 
-	return "", restclient.GetApiServerClientMap(v1alpha1.PodCloudTarget).
-		Delete().Namespace(ns).Resource("pods").Name(name).Do(ctx).Error()
+	backup, err := getPodDeleteArgs(args, e.evict, timeout)
+	if err != nil {
+		return "", err
+	}
+
+	c := restclient.GetApiServerClientMap(ctx, v1alpha1.PodCloudTarget)
+	pod := &corev1.Pod{}
+	if err := c.Get().Namespace(ns).Resource("pods").Name(name).Do(ctx).Into(pod); err != nil {
+		return "", fmt.Errorf("get pod error: %s", err.Error())
+	}
+
+	pdbs, err := getPodPDBs(ctx, pod)
+	if err != nil {
+		return "", fmt.Errorf("get PodDisruptionBudgets of pod error: %s", err.Error())
+	}
+
+	backup.PodUID, backup.PDBs, backup.StartTime = string(pod.UID), pdbs, time.Now().Format(model.TimeFormat)
+	if backup.PDBPolicy == PDBPolicyOverride {
+		if err := deletePod(ctx, ns, name, backup.GracePeriod); err != nil {
+			return "", err
+		}
+	} else {
+		evicted, err := evictPod(ctx, ns, name, string(pod.UID), backup.GracePeriod)
+		if err != nil {
+			return "", err
+		}
+		backup.Evicted = evicted
+	}
+
+	backupBytes, err := json.Marshal(backup)
+	if err != nil {
+		return "", fmt.Errorf("backup to string error: %s", err.Error())
+	}
+
+	return string(backupBytes), nil
 }
 
 func (e *PodDeleteExecutor) Recover(ctx context.Context, injectObject, uid, backup string) error {
 	return nil
 }
 
+// Query evicting blocked by a PodDisruptionBudget is retried in every query until pdbTimeout.
+// With an interval, the pod recreated with the same name is deleted again until the experiment ends
 func (e *PodDeleteExecutor) Query(ctx context.Context, injectObject, uid, backup string, phase v1alpha1.PhaseType) (*model.SubExpInfo, error) {
-	return &model.SubExpInfo{
+	info := &model.SubExpInfo{
 		UID:        uid,
 		Status:     v1alpha1.SuccessStatusType,
 		UpdateTime: time.Now().Format(model.TimeFormat),
-	}, nil
+	}
+
+	// experiments created before pdb policy is supported have no backup
+	if backup == "" || phase != v1alpha1.InjectPhaseType {
+		return info, nil
+	}
+
+	b := &podDeleteBackup{}
+	if err := json.Unmarshal([]byte(backup), b); err != nil {
+		return nil, fmt.Errorf("unexpected backup format: %s", err.Error())
+	}
+	info.CreateTime = b.StartTime
+
+	ns, name, _, err := model.ParsePodInfo(injectObject)
+	if err != nil {
+		return nil, fmt.Errorf("unexpected pod format: %s", err.Error())
+	}
+
+	if b.PDBPolicy == PDBPolicyOverride {
+		if len(b.PDBs) > 0 {
+			info.Message = fmt.Sprintf("pod deleted, PodDisruptionBudget overridden: %s", strings.Join(b.PDBs, ","))
+		} else {
+			info.Message = "pod deleted"
+		}
+		return queryDeleteLoop(ctx, ns, name, b, info)
+	}
+
+	if b.Evicted {
+		info.Message = "pod evicted, PodDisruptionBudget respected"
+		return queryDeleteLoop(ctx, ns, name, b, info)
+	}
+
+	evicted, err := evictPod(ctx, ns, name, b.PodUID, b.GracePeriod)
+	if err != nil {
+		return nil, err
+	}
+
+	if evicted {
+		info.Message = "pod evicted, PodDisruptionBudget respected"
+		return queryDeleteLoop(ctx, ns, name, b, info)
+	}
+
+	pdbTimeout := defaultPDBTimeout
+	if b.PDBTimeout != "" {
+		pdbTimeout, _ = time.ParseDuration(b.PDBTimeout)
+	}
+
+	startTime, err := time.ParseInLocation(model.TimeFormat, b.StartTime, time.Local)
+	if err == nil && time.Since(startTime) > pdbTimeout {
+		info.Status, info.Message = v1alpha1.FailedStatusType, fmt.Sprintf("eviction is blocked by PodDisruptionBudget[%s] over %s", strings.Join(b.PDBs, ","), pdbTimeout)
+		return info, nil
+	}
+
+	info.Status, info.Message = v1alpha1.RunningStatusType, fmt.Sprintf("eviction is blocked by PodDisruptionBudget[%s], back off and retry", strings.Join(b.PDBs, ","))
+	return info, nil
+}
+
+// queryDeleteLoop keeps the target running until the experiment ends when an interval is given. A pod recreated
+// with the same name, e.g. by a StatefulSet, is deleted again once it has lived for the interval
+func queryDeleteLoop(ctx context.Context, ns, name string, b *podDeleteBackup, info *model.SubExpInfo) (*model.SubExpInfo, error) {
+	if b.Interval == "" {
+		return info, nil
+	}
+
+	isEnd, err := common.IsTimeout(b.StartTime, b.Duration)
+	if err != nil {
+		return nil, fmt.Errorf("check if experiment ends error: %s", err.Error())
+	}
+
+	if isEnd {
+		info.Message = fmt.Sprintf("%s, and deleted every %s until the experiment ended", info.Message, b.Interval)
+		return info, nil
+	}
+
+	interval, _ := time.ParseDuration(b.Interval)
+	info.Status = v1alpha1.RunningStatusType
+	pod := &corev1.Pod{}
+	if err := restclient.GetApiServerClientMap(ctx, v1alpha1.PodCloudTarget).Get().Namespace(ns).Resource("pods").
+		Name(name).Do(ctx).Into(pod); err != nil {
+		if errors.IsNotFound(err) {
+			info.Message = fmt.Sprintf("%s, wait for the pod to be recreated", info.Message)
+			return info, nil
+		}
+		return nil, fmt.Errorf("get pod error: %s", err.Error())
+	}
+
+	if pod.DeletionTimestamp != nil || time.Since(pod.CreationTimestamp.Time) < interval {
+		info.Message = fmt.Sprintf("%s, next deletion is %s after the pod is recreated", info.Message, b.Interval)
+		return info, nil
+	}
+
+	if b.PDBPolicy == PDBPolicyOverride {
+		err = deletePod(ctx, ns, name, b.GracePeriod)
+	} else {
+		_, err = evictPod(ctx, ns, name, string(pod.UID), b.GracePeriod)
+	}
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, err
+	}
+
+	info.Message = fmt.Sprintf("recreated pod[%s] deleted again, next deletion is %s after the pod is recreated", pod.UID, b.Interval)
+	return info, nil
+}
+
+func deletePod(ctx context.Context, ns, name string, gracePeriod *int64) error {
+	body, err := json.Marshal(&metav1.DeleteOptions{GracePeriodSeconds: gracePeriod})
+	if err != nil {
+		return fmt.Errorf("delete options to string error: %s", err.Error())
+	}
+
+	if err := restclient.GetApiServerClientMap(ctx, v1alpha1.PodCloudTarget).Delete().Namespace(ns).Resource("pods").
+		Name(name).Body(body).Do(ctx).Error(); err != nil {
+		return fmt.Errorf("delete pod error: %s", err.Error())
+	}
+
+	return nil
+}
+
+// evictPod returns false when the eviction is blocked by a PodDisruptionBudget. A pod which is already gone
+// or replaced by a new one with the same name is regarded as evicted
+func evictPod(ctx context.Context, ns, name, podUID string, gracePeriod *int64) (bool, error) {
+	eviction := &policyv1.Eviction{
+		TypeMeta:      metav1.TypeMeta{APIVersion: "policy/v1", Kind: "Eviction"},
+		ObjectMeta:    metav1.ObjectMeta{Namespace: ns, Name: name},
+		DeleteOptions: &metav1.DeleteOptions{},
+	}
+	if podUID != "" {
+		eviction.DeleteOptions = metav1.NewPreconditionDeleteOptions(podUID)
+	}
+	eviction.DeleteOptions.GracePeriodSeconds = gracePeriod
+
+	body, err := json.Marshal(eviction)
+	if err != nil {
+		return false, fmt.Errorf("eviction to string error: %s", err.Error())
+	}
+
+	err = restclient.GetApiServerClientMap(ctx, v1alpha1.PodCloudTarget).Post().Namespace(ns).Resource("pods").
+		Name(name).SubResource("eviction").Body(body).Do(ctx).Error()
+	if err == nil || errors.IsNotFound(err) || errors.IsConflict(err) {
+		return true, nil
+	}
+
+	if errors.IsTooManyRequests(err) {
+		return false, nil
+	}
+
+	return false, fmt.Errorf("evict pod error: %s", err.Error())
+}
+
+func getPodPDBs(ctx context.Context, pod *corev1.Pod) ([]string, error) {
+	pdbList := &policyv1.PodDisruptionBudgetList{}
+	raw, err := restclient.GetApiServerClientMap(ctx, v1alpha1.PodCloudTarget).Get().
+		AbsPath("/apis/policy/v1/namespaces", pod.Namespace, "poddisruptionbudgets").Do(ctx).Raw()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(raw, pdbList); err != nil {
+		return nil, fmt.Errorf("unexpected PodDisruptionBudget list: %s", err.Error())
+	}
+
+	var pdbs []string
+	for _, pdb := range pdbList.Items {
+		s, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+		if err != nil || s.Empty() || !s.Matches(labels.Set(pod.Labels)) {
+			continue
+		}
+		pdbs = append(pdbs, pdb.Name)
+	}
+
+	return pdbs, nil
 }
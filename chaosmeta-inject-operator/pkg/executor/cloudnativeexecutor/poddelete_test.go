@@ -0,0 +1,52 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cloudnativeexecutor
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"testing"
+)
+
+func Test_getPodDeleteArgs(t *testing.T) {
+	backup, err := getPodDeleteArgs(nil, false, "")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, PDBPolicyOverride, backup.PDBPolicy)
+	assert.Nil(t, backup.GracePeriod)
+
+	backup, err = getPodDeleteArgs(nil, true, "")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, PDBPolicyRespect, backup.PDBPolicy)
+
+	backup, err = getPodDeleteArgs([]v1alpha1.ArgsUnit{
+		{Key: "gracePeriod", Value: "0"},
+		{Key: "interval", Value: "30s"},
+	}, false, "5m")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(0), *backup.GracePeriod)
+	assert.Equal(t, "30s", backup.Interval)
+
+	_, err = getPodDeleteArgs([]v1alpha1.ArgsUnit{{Key: "pdbPolicy", Value: PDBPolicyOverride}}, true, "")
+	assert.NotEqual(t, nil, err)
+
+	_, err = getPodDeleteArgs([]v1alpha1.ArgsUnit{{Key: "gracePeriod", Value: "-1"}}, false, "")
+	assert.NotEqual(t, nil, err)
+
+	// the pod is deleted in a loop until the experiment ends, so an interval needs a duration
+	_, err = getPodDeleteArgs([]v1alpha1.ArgsUnit{{Key: "interval", Value: "30s"}}, false, "")
+	assert.NotEqual(t, nil, err)
+}
@@ -39,7 +39,7 @@ func (e *PodFinalizerExecutor) Inject(ctx context.Context, injectObject, uid, ti
 		return "", fmt.Errorf("unexpected pod format: %s", err.Error())
 	}
 
-	c := restclient.GetApiServerClientMap(v1alpha1.PodCloudTarget)
+	c := restclient.GetApiServerClientMap(ctx, v1alpha1.PodCloudTarget)
 	pod := &corev1.Pod{}
 	if err := c.Get().Namespace(ns).Resource("pods").Name(name).Do(ctx).Into(pod); err != nil {
 		return "", fmt.Errorf("get pod error: %s", err.Error())
@@ -69,7 +69,7 @@ func (e *PodFinalizerExecutor) Recover(ctx context.Context, injectObject, uid, b
 		}
 	}
 
-	c := restclient.GetApiServerClientMap(v1alpha1.PodCloudTarget)
+	c := restclient.GetApiServerClientMap(ctx, v1alpha1.PodCloudTarget)
 	return patchFinalizers(ctx, c, "pods", ns, name, oldFinalizers)
 }
 
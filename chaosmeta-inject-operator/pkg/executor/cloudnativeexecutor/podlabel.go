@@ -39,7 +39,7 @@ func (e *PodLabelExecutor) Inject(ctx context.Context, injectObject, uid, timeou
 		return "", fmt.Errorf("unexpected pod format: %s", err.Error())
 	}
 
-	c := restclient.GetApiServerClientMap(v1alpha1.PodCloudTarget)
+	c := restclient.GetApiServerClientMap(ctx, v1alpha1.PodCloudTarget)
 	pod := &corev1.Pod{}
 	if err := c.Get().Namespace(ns).Resource("pods").Name(name).Do(ctx).Into(pod); err != nil {
 		return "", fmt.Errorf("get pod error: %s", err.Error())
@@ -67,7 +67,7 @@ func (e *PodLabelExecutor) Recover(ctx context.Context, injectObject, uid, backu
 		return fmt.Errorf("unexpected pod format: %s", err.Error())
 	}
 
-	c := restclient.GetApiServerClientMap(v1alpha1.PodCloudTarget)
+	c := restclient.GetApiServerClientMap(ctx, v1alpha1.PodCloudTarget)
 	pod := &corev1.Pod{}
 	if err := c.Get().Namespace(ns).Resource("pods").Name(name).Do(ctx).Into(pod); err != nil {
 		return fmt.Errorf("get pod error: %s", err.Error())
@@ -21,17 +21,20 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/common"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/config"
 	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/executor/remoteexecutor/base"
 	httpclient "github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/http"
 	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/model"
-	"strconv"
-	"strings"
+	"net/url"
+	"time"
 )
 
 type AgentRemoteExecutor struct {
-	Client      *httpclient.HTTPClient
-	ServicePort int
-	Version     string
+	Client        *httpclient.HTTPClient
+	ServicePort   int
+	Version       string
+	RuntimeConfig *config.AgentRuntimeConfig
 }
 
 func (r *AgentRemoteExecutor) CheckAlive(ctx context.Context, injectObject string) error {
@@ -53,9 +56,130 @@ func (r *AgentRemoteExecutor) CheckAlive(ctx context.Context, injectObject strin
 		return fmt.Errorf("expected version %s, but get %s", r.Version, resp.Data.Version)
 	}
 
+	if r.RuntimeConfig != nil {
+		if err := r.SyncConfig(ctx, injectObject); err != nil {
+			return fmt.Errorf("sync runtime config error: %s", err.Error())
+		}
+	}
+
+	return nil
+}
+
+// SyncConfig pushes the runtime config to the agent if the agent's config version is older, without restarting the agent
+func (r *AgentRemoteExecutor) SyncConfig(ctx context.Context, injectObject string) error {
+	resBytes, err := r.Client.Get(ctx, fmt.Sprintf("http://%s:%d/v1/config", injectObject, r.ServicePort))
+	if err != nil {
+		return fmt.Errorf("get response error: %s", err.Error())
+	}
+
+	var resp base.ConfigResponse
+	if err := json.Unmarshal(resBytes, &resp); err != nil {
+		return fmt.Errorf("resp[%s] format error: %s", string(resBytes), err.Error())
+	}
+
+	if resp.Data == nil || resp.Code != base.SucCode {
+		return fmt.Errorf("query config error: %s", resp.Message)
+	}
+
+	if resp.Data.Version >= r.RuntimeConfig.Version {
+		return nil
+	}
+
+	bytesData, err := json.Marshal(base.ConfigRequest{AgentRuntimeConfig: *r.RuntimeConfig})
+	if err != nil {
+		return fmt.Errorf("request to string error: %s", err.Error())
+	}
+
+	resBytes, err = r.Client.Post(ctx, fmt.Sprintf("http://%s:%d/v1/config", injectObject, r.ServicePort), bytesData)
+	if err != nil {
+		return fmt.Errorf("get response error: %s", err.Error())
+	}
+
+	if err := json.Unmarshal(resBytes, &resp); err != nil {
+		return fmt.Errorf("resp[%s] format error: %s", string(resBytes), err.Error())
+	}
+
+	// another operator replica may have pushed the same version concurrently
+	if resp.Code != base.SucCode && (resp.Data == nil || resp.Data.Version < r.RuntimeConfig.Version) {
+		return fmt.Errorf("err code: {%d}, err msg: %s", resp.Code, resp.Message)
+	}
+
 	return nil
 }
 
+// Diagnose only measures the agent, the container runtime is not reachable through the agent's http api
+func (r *AgentRemoteExecutor) Diagnose(ctx context.Context, injectObject string, cRuntime string) (*model.AgentDiagnostics, error) {
+	start := time.Now()
+	resBytes, err := r.Client.Get(ctx, fmt.Sprintf("http://%s:%d/v1/version", injectObject, r.ServicePort))
+	if err != nil {
+		return nil, fmt.Errorf("get response error: %s", err.Error())
+	}
+
+	diagnostics := &model.AgentDiagnostics{AgentResponseTime: time.Since(start)}
+	var resp base.VersionResponse
+	if err := json.Unmarshal(resBytes, &resp); err != nil {
+		return diagnostics, fmt.Errorf("resp[%s] format error: %s", string(resBytes), err.Error())
+	}
+
+	if resp.Data == nil || resp.Code != 0 {
+		return diagnostics, fmt.Errorf("query version error: %s", resp.Message)
+	}
+
+	diagnostics.AgentVersion = resp.Data.Version
+	return diagnostics, nil
+}
+
+func (r *AgentRemoteExecutor) Snapshot(ctx context.Context, injectObject string, cID, cRuntime string) (*v1alpha1.EnvSnapshot, error) {
+	query := url.Values{}
+	if cRuntime != "" {
+		query.Set("container_runtime", cRuntime)
+		query.Set("container_id", cID)
+	}
+
+	resBytes, err := r.Client.Get(ctx, fmt.Sprintf("http://%s:%d/v1/snapshot?%s", injectObject, r.ServicePort, query.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("get response error: %s", err.Error())
+	}
+
+	var resp base.SnapshotResponse
+	if err := json.Unmarshal(resBytes, &resp); err != nil {
+		return nil, fmt.Errorf("resp[%s] format error: %s", string(resBytes), err.Error())
+	}
+
+	if resp.Data == nil || resp.Code != base.SucCode {
+		return nil, fmt.Errorf("err code: {%d}, err msg: %s", resp.Code, resp.Message)
+	}
+
+	return resp.Data.ToEnvSnapshot(), nil
+}
+
+func (r *AgentRemoteExecutor) Preflight(ctx context.Context, injectObject string, target, fault, cID, cRuntime string) ([]string, error) {
+	query := url.Values{}
+	query.Set("target", target)
+	query.Set("fault", fault)
+	if cRuntime != "" {
+		query.Set("container_runtime", cRuntime)
+		query.Set("container_id", cID)
+	}
+
+	resBytes, err := r.Client.Get(ctx, fmt.Sprintf("http://%s:%d/v1/capability?%s", injectObject, r.ServicePort, query.Encode()))
+	if err != nil {
+		return nil, common.NewCodedError(common.GetAgentUnavailableErrorInfo(), fmt.Errorf("get response error: %s", err.Error()))
+	}
+
+	var resp base.CapabilityResponse
+	if err := json.Unmarshal(resBytes, &resp); err != nil {
+		return nil, fmt.Errorf("resp[%s] format error: %s", string(resBytes), err.Error())
+	}
+
+	// an unhealthy agent still reports its capability
+	if resp.Data == nil {
+		return nil, base.GetResponseError(resp.Code, resp.Message, resp.Error)
+	}
+
+	return resp.Data.GetUnusableReasons(target, fault), nil
+}
+
 // Init install agent
 func (r *AgentRemoteExecutor) Init(ctx context.Context, target string) error {
 	return nil
@@ -63,33 +187,12 @@ func (r *AgentRemoteExecutor) Init(ctx context.Context, target string) error {
 
 func (r *AgentRemoteExecutor) Inject(ctx context.Context, injectObject string, target, fault, uid, timeout, cID, cRuntime string, args []v1alpha1.ArgsUnit) error {
 	if err := r.CheckAlive(ctx, injectObject); err != nil {
-		return fmt.Errorf("check target's status error: %s", err.Error())
+		return common.NewCodedError(common.GetAgentUnavailableErrorInfo(), fmt.Errorf("check target's status error: %s", err.Error()))
 	}
 
-	var argsMap = make(map[string]interface{})
-	for _, unitArgs := range args {
-		if unitArgs.Key == v1alpha1.ContainerKey {
-			continue
-		}
-
-		unitArgs.Key = strings.ReplaceAll(unitArgs.Key, "-", "_")
-		if unitArgs.ValueType == v1alpha1.IntVType {
-			argsInt, err := strconv.Atoi(unitArgs.Value)
-			if err != nil {
-				return fmt.Errorf("args[%s]'s value[%s] require int type", unitArgs.Key, unitArgs.Value)
-			}
-
-			argsMap[unitArgs.Key] = argsInt
-		} else if unitArgs.ValueType == v1alpha1.StringVType {
-			argsMap[unitArgs.Key] = unitArgs.Value
-		} else {
-			return fmt.Errorf("args[%s] not support value type: %s", unitArgs.Key, unitArgs.ValueType)
-		}
-	}
-
-	argsBytes, err := json.Marshal(argsMap)
+	argsStr, err := base.ConvertArgs(args)
 	if err != nil {
-		return fmt.Errorf("args to json string error: %s", err.Error())
+		return err
 	}
 
 	bytesData, err := json.Marshal(base.InjectRequest{
@@ -99,7 +202,7 @@ func (r *AgentRemoteExecutor) Inject(ctx context.Context, injectObject string, t
 		ContainerId:      cID,
 		ContainerRuntime: cRuntime,
 		Uid:              uid,
-		Args:             string(argsBytes),
+		Args:             argsStr,
 	})
 	if err != nil {
 		return fmt.Errorf("request to string error: %s", err.Error())
@@ -107,7 +210,7 @@ func (r *AgentRemoteExecutor) Inject(ctx context.Context, injectObject string, t
 
 	resBytes, err := r.Client.Post(ctx, fmt.Sprintf("http://%s:%d/v1/experiment/inject", injectObject, r.ServicePort), bytesData)
 	if err != nil {
-		return fmt.Errorf("get response error: %s", err.Error())
+		return common.NewCodedError(common.GetAgentUnavailableErrorInfo(), fmt.Errorf("get response error: %s", err.Error()))
 	}
 
 	var resp base.InjectResponse
@@ -118,7 +221,7 @@ func (r *AgentRemoteExecutor) Inject(ctx context.Context, injectObject string, t
 	if resp.Code == base.SucCode {
 		return nil
 	} else {
-		return fmt.Errorf("err code: {%d}, err msg: %s", resp.Code, resp.Message)
+		return base.GetResponseError(resp.Code, resp.Message, resp.Error)
 	}
 }
 
@@ -133,7 +236,7 @@ func (r *AgentRemoteExecutor) Recover(ctx context.Context, injectObject string,
 
 	resBytes, err := r.Client.Post(ctx, fmt.Sprintf("http://%s:%d/v1/experiment/recover", injectObject, r.ServicePort), bytesData)
 	if err != nil {
-		return fmt.Errorf("get response error: %s", err.Error())
+		return common.NewCodedError(common.GetAgentUnavailableErrorInfo(), fmt.Errorf("get response error: %s", err.Error()))
 	}
 
 	var resp base.CommonResponse
@@ -144,7 +247,7 @@ func (r *AgentRemoteExecutor) Recover(ctx context.Context, injectObject string,
 	if resp.Code == base.SucCode {
 		return nil
 	} else {
-		return fmt.Errorf("err code: {%d}, err msg: %s", resp.Code, resp.Message)
+		return base.GetResponseError(resp.Code, resp.Message, resp.Error)
 	}
 }
 
@@ -159,7 +262,7 @@ func (r *AgentRemoteExecutor) Query(ctx context.Context, injectObject string, ui
 
 	resBytes, err := r.Client.Post(ctx, fmt.Sprintf("http://%s:%d/v1/experiment/query", injectObject, r.ServicePort), bytesData)
 	if err != nil {
-		return nil, fmt.Errorf("get response error: %s", err.Error())
+		return nil, common.NewCodedError(common.GetAgentUnavailableErrorInfo(), fmt.Errorf("get response error: %s", err.Error()))
 	}
 
 	var resp base.QueryResponse
@@ -181,6 +284,6 @@ func (r *AgentRemoteExecutor) Query(ctx context.Context, injectObject string, ui
 			Status:     base.ConvertStatus(task.Status, phase),
 		}, nil
 	} else {
-		return nil, fmt.Errorf("err code: {%d}, err msg: %s", resp.Code, resp.Message)
+		return nil, base.GetResponseError(resp.Code, resp.Message, resp.Error)
 	}
 }
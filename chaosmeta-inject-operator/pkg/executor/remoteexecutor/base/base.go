@@ -16,7 +16,15 @@
 
 package base
 
-import "github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/common"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/config"
+	"strconv"
+	"strings"
+)
 
 type RemoteExpStatus string
 
@@ -59,10 +67,52 @@ const (
 	//ContainerNotFoundCode = 2
 )
 
+// GetResponseError keeps the structured error returned by chaosmetad, and converts the code for an older agent without it
+func GetResponseError(code int, msg string, info *v1alpha1.ErrorInfo) error {
+	err := fmt.Errorf("err code: {%d}, err msg: %s", code, msg)
+	if info != nil {
+		return common.NewCodedError(info, err)
+	}
+
+	return common.NewAgentError(code, err)
+}
+
+// ConvertArgs converts the args of experiment to the json args of chaosmetad, the container args is not passed
+func ConvertArgs(args []v1alpha1.ArgsUnit) (string, error) {
+	var argsMap = make(map[string]interface{})
+	for _, unitArgs := range args {
+		if unitArgs.Key == v1alpha1.ContainerKey {
+			continue
+		}
+
+		unitArgs.Key = strings.ReplaceAll(unitArgs.Key, "-", "_")
+		if unitArgs.ValueType == v1alpha1.IntVType {
+			argsInt, err := strconv.Atoi(unitArgs.Value)
+			if err != nil {
+				return "", fmt.Errorf("args[%s]'s value[%s] require int type", unitArgs.Key, unitArgs.Value)
+			}
+
+			argsMap[unitArgs.Key] = argsInt
+		} else if unitArgs.ValueType == v1alpha1.StringVType {
+			argsMap[unitArgs.Key] = unitArgs.Value
+		} else {
+			return "", fmt.Errorf("args[%s] not support value type: %s", unitArgs.Key, unitArgs.ValueType)
+		}
+	}
+
+	argsBytes, err := json.Marshal(argsMap)
+	if err != nil {
+		return "", fmt.Errorf("args to json string error: %s", err.Error())
+	}
+
+	return string(argsBytes), nil
+}
+
 type CommonResponse struct {
-	Code    int    `json:"code"`
-	Message string `json:"message"`
-	TraceId string `json:"trace_id,omitempty"`
+	Code    int                 `json:"code"`
+	Message string              `json:"message"`
+	Error   *v1alpha1.ErrorInfo `json:"error,omitempty"`
+	TraceId string              `json:"trace_id,omitempty"`
 }
 
 type ExperimentDataUnit struct {
@@ -96,6 +146,7 @@ type InjectRequest struct {
 type InjectResponse struct {
 	Code    int                        `json:"code"`
 	Message string                     `json:"message"`
+	Error   *v1alpha1.ErrorInfo        `json:"error,omitempty"`
 	Data    *InjectSuccessResponseData `json:"data,omitempty"`
 	TraceId string                     `json:"trace_id,omitempty"`
 }
@@ -112,23 +163,136 @@ type QueryRequest struct {
 	Creator          string          `json:"creator,omitempty"`
 	ContainerId      string          `json:"container_id,omitempty"`
 	ContainerRuntime string          `json:"container_runtime,omitempty"`
+	StartTime        string          `json:"start_time,omitempty"`
+	EndTime          string          `json:"end_time,omitempty"`
 	Offset           int32           `json:"offset,omitempty"`
 	Limit            int32           `json:"limit,omitempty"`
 	TraceId          string          `json:"trace_id,omitempty"`
 }
 
 type QueryResponse struct {
-	Code    int                `json:"code"`
-	Message string             `json:"message"`
-	Data    *QueryResponseData `json:"data,omitempty"`
-	TraceId string             `json:"trace_id,omitempty"`
+	Code    int                 `json:"code"`
+	Message string              `json:"message"`
+	Error   *v1alpha1.ErrorInfo `json:"error,omitempty"`
+	Data    *QueryResponseData  `json:"data,omitempty"`
+	TraceId string              `json:"trace_id,omitempty"`
 }
 
 type QueryResponseData struct {
 	Total       int64                `json:"total"`
+	Offset      uint                 `json:"offset"`
+	Limit       uint                 `json:"limit"`
 	Experiments []ExperimentDataUnit `json:"experiments,omitempty"`
 }
 
+type ConfigRequest struct {
+	config.AgentRuntimeConfig
+	TraceId string `json:"trace_id,omitempty"`
+}
+
+type ConfigResponse struct {
+	Code    int                        `json:"code"`
+	Message string                     `json:"message"`
+	Data    *config.AgentRuntimeConfig `json:"data,omitempty"`
+	TraceId string                     `json:"trace_id,omitempty"`
+}
+
+type SnapshotResponse struct {
+	Code    int           `json:"code"`
+	Message string        `json:"message"`
+	Data    *SnapshotData `json:"data,omitempty"`
+	TraceId string        `json:"trace_id,omitempty"`
+}
+
+type SnapshotData struct {
+	Time         string   `json:"time"`
+	LoadAvg      string   `json:"load_avg,omitempty"`
+	MemLimitKB   int64    `json:"mem_limit_kb,omitempty"`
+	MemUsageKB   int64    `json:"mem_usage_kb,omitempty"`
+	Connections  int      `json:"connections"`
+	TopProcesses []string `json:"top_processes,omitempty"`
+	Errors       []string `json:"errors,omitempty"`
+}
+
+func (s *SnapshotData) ToEnvSnapshot() *v1alpha1.EnvSnapshot {
+	return &v1alpha1.EnvSnapshot{
+		Time:         s.Time,
+		LoadAvg:      s.LoadAvg,
+		MemLimitKB:   s.MemLimitKB,
+		MemUsageKB:   s.MemUsageKB,
+		Connections:  s.Connections,
+		TopProcesses: s.TopProcesses,
+		Errors:       s.Errors,
+	}
+}
+
+type CapabilityRequest struct {
+	Target           string `json:"target,omitempty"`
+	Fault            string `json:"fault,omitempty"`
+	ContainerRuntime string `json:"container_runtime,omitempty"`
+	ContainerId      string `json:"container_id,omitempty"`
+}
+
+type CapabilityResponse struct {
+	Code    int                 `json:"code"`
+	Message string              `json:"message"`
+	Data    *CapabilityReport   `json:"data,omitempty"`
+	Error   *v1alpha1.ErrorInfo `json:"error,omitempty"`
+}
+
+type CapabilityReport struct {
+	Version   string               `json:"version"`
+	Health    *CapabilityHealth    `json:"health"`
+	Kernel    string               `json:"kernel"`
+	Faults    []FaultCapability    `json:"faults"`
+	Container *ContainerCapability `json:"container,omitempty"`
+}
+
+type CapabilityHealth struct {
+	Healthy bool     `json:"healthy"`
+	Errors  []string `json:"errors,omitempty"`
+}
+
+type FaultCapability struct {
+	Target  string   `json:"target"`
+	Fault   string   `json:"fault"`
+	Usable  bool     `json:"usable"`
+	Missing []string `json:"missing,omitempty"`
+}
+
+type ContainerCapability struct {
+	Running bool   `json:"running"`
+	Error   string `json:"error,omitempty"`
+}
+
+// GetUnusableReasons returns why the fault can not be injected on the host, empty means it is ready to be injected
+func (r *CapabilityReport) GetUnusableReasons(target, fault string) []string {
+	var reasons []string
+	if r.Health != nil && !r.Health.Healthy {
+		reasons = append(reasons, fmt.Sprintf("chaosmetad is unhealthy: %s", strings.Join(r.Health.Errors, ", ")))
+	}
+
+	var faultCapability *FaultCapability
+	for i := range r.Faults {
+		if r.Faults[i].Target == target && r.Faults[i].Fault == fault {
+			faultCapability = &r.Faults[i]
+			break
+		}
+	}
+
+	if faultCapability == nil {
+		reasons = append(reasons, fmt.Sprintf("fault %s %s is not supported by chaosmetad %s", target, fault, r.Version))
+	} else if !faultCapability.Usable {
+		reasons = append(reasons, fmt.Sprintf("missing %s on kernel %s", strings.Join(faultCapability.Missing, ", "), r.Kernel))
+	}
+
+	if r.Container != nil && !r.Container.Running {
+		reasons = append(reasons, fmt.Sprintf("container is not running: %s", r.Container.Error))
+	}
+
+	return reasons
+}
+
 type RecoverRequest struct {
 	Uid     string `json:"uid"`
 	TraceId string `json:"trace_id"`
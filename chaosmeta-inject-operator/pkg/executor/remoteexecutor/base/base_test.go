@@ -103,3 +103,55 @@ func TestConvertStatus(t *testing.T) {
 		})
 	}
 }
+
+func TestConvertArgs(t *testing.T) {
+	args := []v1alpha1.ArgsUnit{
+		{Key: "percent", Value: "80", ValueType: v1alpha1.IntVType},
+		{Key: "fill-path", Value: "/tmp", ValueType: v1alpha1.StringVType},
+		{Key: v1alpha1.ContainerKey, Value: "c1", ValueType: v1alpha1.StringVType},
+	}
+	got, err := ConvertArgs(args)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"fill_path":"/tmp","percent":80}`, got)
+
+	_, err = ConvertArgs([]v1alpha1.ArgsUnit{{Key: "percent", Value: "a", ValueType: v1alpha1.IntVType}})
+	assert.Error(t, err)
+}
+
+func TestGetInjectCmd(t *testing.T) {
+	args := []v1alpha1.ArgsUnit{
+		{Key: "fill_path", Value: "/tmp", ValueType: v1alpha1.StringVType},
+		{Key: v1alpha1.ContainerKey, Value: "c1", ValueType: v1alpha1.StringVType},
+	}
+	executor := GetExecutorPath("/tmp", "chaosmetad", "0.3.9")
+	assert.Equal(t, "/tmp/chaosmetad-0.3.9/chaosmetad inject disk fill --uid uid1 --fill-path=/tmp --timeout 1m --container-runtime docker --container-id id1",
+		GetInjectCmd(executor, "disk", "fill", "uid1", "1m", "id1", "docker", args))
+	assert.Equal(t, "/tmp/chaosmetad-0.3.9/chaosmetad query -u uid1 --format json", GetQueryCmd(executor, "uid1"))
+}
+
+func TestParseQueryOutput(t *testing.T) {
+	info, err := ParseQueryOutput([]byte(`{"total":1,"experiments":[{"uid":"uid1","status":"success"}]}`), "uid1", v1alpha1.InjectPhaseType)
+	assert.NoError(t, err)
+	assert.Equal(t, v1alpha1.SuccessStatusType, info.Status)
+
+	_, err = ParseQueryOutput([]byte(`{"total":0}`), "uid1", v1alpha1.InjectPhaseType)
+	assert.Error(t, err)
+}
+
+func TestCapabilityReport_GetUnusableReasons(t *testing.T) {
+	report := &CapabilityReport{
+		Version: "0.5.0",
+		Kernel:  "3.10.0",
+		Health:  &CapabilityHealth{Healthy: true},
+		Faults: []FaultCapability{
+			{Target: "cpu", Fault: "burn", Usable: true},
+			{Target: "network", Fault: "delay", Usable: false, Missing: []string{"sch_netem"}},
+		},
+	}
+	assert.Empty(t, report.GetUnusableReasons("cpu", "burn"))
+	assert.Equal(t, []string{"missing sch_netem on kernel 3.10.0"}, report.GetUnusableReasons("network", "delay"))
+	assert.Equal(t, []string{"fault disk fill is not supported by chaosmetad 0.5.0"}, report.GetUnusableReasons("disk", "fill"))
+
+	report.Container = &ContainerCapability{Running: false, Error: "container is exited"}
+	assert.Equal(t, []string{"container is not running: container is exited"}, report.GetUnusableReasons("cpu", "burn"))
+}
@@ -0,0 +1,134 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package base
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/model"
+	"strings"
+)
+
+// The commands of chaosmetad cli, shared by the executors running chaosmetad on the host, such as kubectl exec and ssh
+
+func GetExecutorPath(dir, executor, version string) string {
+	return fmt.Sprintf("%s/%s-%s/%s", dir, executor, version, executor)
+}
+
+func GetVersionCmd(executor string) string {
+	return fmt.Sprintf("%s version", executor)
+}
+
+func GetSnapshotCmd(executor, cID, cRuntime string) string {
+	cmd := fmt.Sprintf("%s snapshot", executor)
+	if cRuntime != "" {
+		cmd = fmt.Sprintf("%s --container-runtime %s --container-id %s", cmd, cRuntime, cID)
+	}
+
+	return cmd
+}
+
+func GetCapabilityCmd(executor, target, fault, cID, cRuntime string) string {
+	cmd := fmt.Sprintf("%s capability %s %s", executor, target, fault)
+	if cRuntime != "" {
+		cmd = fmt.Sprintf("%s --container-runtime %s --container-id %s", cmd, cRuntime, cID)
+	}
+
+	return cmd
+}
+
+func GetInjectCmd(executor, target, fault, uid, timeout, cID, cRuntime string, args []v1alpha1.ArgsUnit) string {
+	cmd := fmt.Sprintf("%s inject %s %s --uid %s", executor, target, fault, uid)
+	for _, unitArgs := range args {
+		if unitArgs.Key == v1alpha1.ContainerKey {
+			continue
+		}
+
+		unitArgs.Key = strings.ReplaceAll(unitArgs.Key, "_", "-")
+		cmd = fmt.Sprintf("%s --%s=%s", cmd, unitArgs.Key, unitArgs.Value)
+	}
+
+	if timeout != "" {
+		cmd = fmt.Sprintf("%s --timeout %s", cmd, timeout)
+	}
+
+	if cRuntime != "" {
+		cmd = fmt.Sprintf("%s --container-runtime %s --container-id %s", cmd, cRuntime, cID)
+	}
+
+	return cmd
+}
+
+func GetRecoverCmd(executor, uid string) string {
+	return fmt.Sprintf("%s recover %s", executor, uid)
+}
+
+func GetQueryCmd(executor, uid string) string {
+	return fmt.Sprintf("%s query -u %s --format json", executor, uid)
+}
+
+// ParseVersionOutput checks the output of version command
+func ParseVersionOutput(stdout []byte, version string) (*VersionInfo, error) {
+	var res VersionInfo
+	if err := json.Unmarshal(stdout, &res); err != nil {
+		return nil, fmt.Errorf("version output [%s] is not json format: %s", string(stdout), err.Error())
+	}
+
+	if version != "" && res.Version != version {
+		return &res, fmt.Errorf("expected version %s, but get %s", version, res.Version)
+	}
+
+	return &res, nil
+}
+
+func ParseQueryOutput(stdout []byte, uid string, phase v1alpha1.PhaseType) (*model.SubExpInfo, error) {
+	var res QueryResponseData
+	if err := json.Unmarshal(stdout, &res); err != nil {
+		return nil, fmt.Errorf("query output [%s] is not json format: %s", string(stdout), err.Error())
+	}
+
+	if res.Total != 1 {
+		return nil, fmt.Errorf("query output expect 1 but get: %d", res.Total)
+	}
+
+	return &model.SubExpInfo{
+		UID:        uid,
+		CreateTime: res.Experiments[0].CreateTime,
+		UpdateTime: res.Experiments[0].UpdateTime,
+		Message:    res.Experiments[0].Error_,
+		Status:     ConvertStatus(res.Experiments[0].Status, phase),
+	}, nil
+}
+
+func ParseSnapshotOutput(stdout []byte) (*v1alpha1.EnvSnapshot, error) {
+	var res SnapshotData
+	if err := json.Unmarshal(stdout, &res); err != nil {
+		return nil, fmt.Errorf("snapshot output [%s] is not json format: %s", string(stdout), err.Error())
+	}
+
+	return res.ToEnvSnapshot(), nil
+}
+
+func ParseCapabilityOutput(stdout []byte) (*CapabilityReport, error) {
+	var res CapabilityReport
+	if err := json.Unmarshal(stdout, &res); err != nil {
+		return nil, fmt.Errorf("capability output [%s] is not json format: %s", string(stdout), err.Error())
+	}
+
+	return &res, nil
+}
@@ -0,0 +1,77 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package base
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/common"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/multicluster"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/restclient"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+	utilexec "k8s.io/client-go/util/exec"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// KubeExec runs the command by bash in the container of pod, an empty container is the default container of pod
+func KubeExec(ctx context.Context, restConfig *rest.Config, schema *runtime.Scheme, ns, podName, container, cmd string) ([]byte, error) {
+	logger := log.FromContext(ctx)
+	logger.Info(fmt.Sprintf("%s/%s,exec: %s", ns, podName, cmd))
+
+	execReq := restclient.GetApiServerClientMap(ctx, v1alpha1.PodCloudTarget).Post().
+		Namespace(ns).Resource("pods").Name(podName).SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Container: container,
+			Command:   []string{"/bin/bash", "-c", cmd},
+			Stdin:     false,
+			Stdout:    true,
+			Stderr:    true,
+		}, runtime.NewParameterCodec(schema))
+
+	exec, err := remotecommand.NewSPDYExecutor(multicluster.GetRESTConfig(ctx, restConfig), "POST", execReq.URL())
+	if err != nil {
+		return nil, fmt.Errorf("create remote cmd executor error: %s", err.Error())
+	}
+
+	var stdout, stderr bytes.Buffer
+	if err := exec.StreamWithContext(ctx, remotecommand.StreamOptions{
+		Stdout: &stdout,
+		Stderr: &stderr,
+	}); err != nil {
+		// TODO: Think about how to solve the basic error information collection of parameter operation
+		execErr := fmt.Errorf("exec remote cmd error: %s %s %s", err.Error(), stdout.String(), stderr.String())
+		// the exit code of chaosmetad is its error code
+		var exitErr utilexec.ExitError
+		if errors.As(err, &exitErr) {
+			return nil, common.NewAgentError(exitErr.ExitStatus(), execErr)
+		}
+
+		return nil, execErr
+	}
+
+	if stderr.String() != "" {
+		return stdout.Bytes(), fmt.Errorf("exec remote cmd get error message: %s", stderr.String())
+	}
+
+	return stdout.Bytes(), nil
+}
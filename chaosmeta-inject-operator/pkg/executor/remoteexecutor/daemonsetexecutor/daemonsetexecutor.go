@@ -17,23 +17,25 @@
 package daemonsetexecutor
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
 	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/common"
 	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/executor/remoteexecutor/base"
 	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/model"
-	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/restclient"
 	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/selector"
-	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/rest"
-	"k8s.io/client-go/tools/remotecommand"
-	"sigs.k8s.io/controller-runtime/pkg/log"
-	"strings"
+	"time"
 )
 
+var runtimeVersionCmd = map[string]string{
+	"docker":     "docker version",
+	"pouch":      "pouch version",
+	"containerd": "ctr version",
+	"cri-o":      "crictl version",
+}
+
 type DaemonsetRemoteExecutor struct {
 	//ApiServer  rest.Interface
 	RESTConfig *rest.Config
@@ -56,25 +58,80 @@ func (r *DaemonsetRemoteExecutor) CheckAlive(ctx context.Context, injectObject s
 		return fmt.Errorf("get agent pod of node[%s] error: %s", injectObject, err.Error())
 	}
 
-	executor := fmt.Sprintf("%s/%s-%s/%s", r.LocalExecPath, r.Executor, r.Version, r.Executor)
-	executeCmd := fmt.Sprintf("nsenter -t 1 -m -u %s version", executor)
-
 	var stdout []byte
-	stdout, err = r.kubeExec(ctx, agentPod.Namespace, agentPod.PodName, executeCmd)
+	stdout, err = r.kubeExec(ctx, agentPod.Namespace, agentPod.PodName, r.hostCmd(base.GetVersionCmd(r.getExecutor())))
 	if err != nil {
 		return fmt.Errorf("kubectl exec error: %s", err.Error())
 	}
 
-	var res base.VersionInfo
-	if err := json.Unmarshal(stdout, &res); err != nil {
-		return fmt.Errorf("version output [%s] is not json format: %s", string(stdout), err.Error())
+	_, err = base.ParseVersionOutput(stdout, r.Version)
+	return err
+}
+
+func (r *DaemonsetRemoteExecutor) Diagnose(ctx context.Context, injectObject string, cRuntime string) (*model.AgentDiagnostics, error) {
+	agentPod, err := r.getAgentPod(ctx, injectObject)
+	if err != nil {
+		return nil, fmt.Errorf("get agent pod of node[%s] error: %s", injectObject, err.Error())
 	}
 
-	if res.Version != r.Version {
-		return fmt.Errorf("expected version %s, but get %s", r.Version, res.Version)
+	start := time.Now()
+	stdout, err := r.kubeExec(ctx, agentPod.Namespace, agentPod.PodName, r.hostCmd(base.GetVersionCmd(r.getExecutor())))
+	if err != nil {
+		return nil, fmt.Errorf("kubectl exec error: %s", err.Error())
 	}
 
-	return nil
+	diagnostics := &model.AgentDiagnostics{AgentResponseTime: time.Since(start)}
+	res, err := base.ParseVersionOutput(stdout, "")
+	if err != nil {
+		return diagnostics, err
+	}
+	diagnostics.AgentVersion = res.Version
+
+	cmd, ok := runtimeVersionCmd[cRuntime]
+	if !ok {
+		return diagnostics, nil
+	}
+
+	start = time.Now()
+	if _, err := r.kubeExec(ctx, agentPod.Namespace, agentPod.PodName, r.hostCmd(cmd)); err != nil {
+		return diagnostics, fmt.Errorf("query %s version error: %s", cRuntime, err.Error())
+	}
+	diagnostics.RuntimeResponseTime = time.Since(start)
+
+	return diagnostics, nil
+}
+
+func (r *DaemonsetRemoteExecutor) Snapshot(ctx context.Context, injectObject string, cID, cRuntime string) (*v1alpha1.EnvSnapshot, error) {
+	agentPod, err := r.getAgentPod(ctx, injectObject)
+	if err != nil {
+		return nil, fmt.Errorf("get agent pod of node[%s] error: %s", injectObject, err.Error())
+	}
+
+	stdout, err := r.kubeExec(ctx, agentPod.Namespace, agentPod.PodName, r.hostCmd(base.GetSnapshotCmd(r.getExecutor(), cID, cRuntime)))
+	if err != nil {
+		return nil, fmt.Errorf("kubectl exec error: %s", err.Error())
+	}
+
+	return base.ParseSnapshotOutput(stdout)
+}
+
+func (r *DaemonsetRemoteExecutor) Preflight(ctx context.Context, injectObject string, target, fault, cID, cRuntime string) ([]string, error) {
+	agentPod, err := r.getAgentPod(ctx, injectObject)
+	if err != nil {
+		return nil, fmt.Errorf("get agent pod of node[%s] error: %s", injectObject, err.Error())
+	}
+
+	stdout, err := r.kubeExec(ctx, agentPod.Namespace, agentPod.PodName, r.hostCmd(base.GetCapabilityCmd(r.getExecutor(), target, fault, cID, cRuntime)))
+	if err != nil {
+		return nil, common.WrapError(err, "kubectl exec error")
+	}
+
+	report, err := base.ParseCapabilityOutput(stdout)
+	if err != nil {
+		return nil, err
+	}
+
+	return report.GetUnusableReasons(target, fault), nil
 }
 
 // Init install agent
@@ -95,27 +152,9 @@ func (r *DaemonsetRemoteExecutor) Inject(ctx context.Context, injectObject strin
 		return fmt.Errorf("get agent pod of node[%s] error: %s", injectObject, err.Error())
 	}
 
-	executor := fmt.Sprintf("%s/%s-%s/%s", r.LocalExecPath, r.Executor, r.Version, r.Executor)
-	executeCmd := fmt.Sprintf("nsenter -t 1 -m -u %s inject %s %s --uid %s", executor, target, fault, uid)
-	for _, unitArgs := range args {
-		if unitArgs.Key == v1alpha1.ContainerKey {
-			continue
-		}
-
-		unitArgs.Key = strings.ReplaceAll(unitArgs.Key, "_", "-")
-		executeCmd = fmt.Sprintf("%s --%s=%s", executeCmd, unitArgs.Key, unitArgs.Value)
-	}
-
-	if timeout != "" {
-		executeCmd = fmt.Sprintf("%s --timeout %s", executeCmd, timeout)
-	}
-
-	if cRuntime != "" {
-		executeCmd = fmt.Sprintf("%s --container-runtime %s --container-id %s", executeCmd, cRuntime, cID)
-	}
-
+	executeCmd := r.hostCmd(base.GetInjectCmd(r.getExecutor(), target, fault, uid, timeout, cID, cRuntime, args))
 	if _, err = r.kubeExec(ctx, agentPod.Namespace, agentPod.PodName, executeCmd); err != nil {
-		return fmt.Errorf("kubectl exec error: %s", err.Error())
+		return common.WrapError(err, "kubectl exec error")
 	}
 
 	return nil
@@ -127,11 +166,8 @@ func (r *DaemonsetRemoteExecutor) Recover(ctx context.Context, injectObject stri
 		return fmt.Errorf("get agent pod of node[%s] error: %s", injectObject, err.Error())
 	}
 
-	executor := fmt.Sprintf("%s/%s-%s/%s", r.LocalExecPath, r.Executor, r.Version, r.Executor)
-	executeCmd := fmt.Sprintf("nsenter -t 1 -m -u %s recover %s", executor, uid)
-
-	if _, err = r.kubeExec(ctx, agentPod.Namespace, agentPod.PodName, executeCmd); err != nil {
-		return fmt.Errorf("kubectl exec error: %s", err.Error())
+	if _, err = r.kubeExec(ctx, agentPod.Namespace, agentPod.PodName, r.hostCmd(base.GetRecoverCmd(r.getExecutor(), uid))); err != nil {
+		return common.WrapError(err, "kubectl exec error")
 	}
 
 	return nil
@@ -143,66 +179,26 @@ func (r *DaemonsetRemoteExecutor) Query(ctx context.Context, injectObject string
 		return nil, fmt.Errorf("get agent pod of node[%s] error: %s", injectObject, err.Error())
 	}
 
-	executor := fmt.Sprintf("%s/%s-%s/%s", r.LocalExecPath, r.Executor, r.Version, r.Executor)
-	executeCmd := fmt.Sprintf("nsenter -t 1 -m -u %s query -u %s --format json", executor, uid)
-
 	var stdout []byte
-	stdout, err = r.kubeExec(ctx, agentPod.Namespace, agentPod.PodName, executeCmd)
+	stdout, err = r.kubeExec(ctx, agentPod.Namespace, agentPod.PodName, r.hostCmd(base.GetQueryCmd(r.getExecutor(), uid)))
 	if err != nil {
-		return nil, fmt.Errorf("kubectl exec error: %s", err.Error())
+		return nil, common.WrapError(err, "kubectl exec error")
 	}
 
-	var res base.QueryResponseData
-	if err := json.Unmarshal(stdout, &res); err != nil {
-		return nil, fmt.Errorf("query output [%s] is not json format: %s", string(stdout), err.Error())
-	}
+	return base.ParseQueryOutput(stdout, uid, phase)
+}
 
-	if res.Total != 1 {
-		return nil, fmt.Errorf("query output expect 1 but get: %d", res.Total)
-	}
+func (r *DaemonsetRemoteExecutor) getExecutor() string {
+	return base.GetExecutorPath(r.LocalExecPath, r.Executor, r.Version)
+}
 
-	return &model.SubExpInfo{
-		UID:        uid,
-		CreateTime: res.Experiments[0].CreateTime,
-		UpdateTime: res.Experiments[0].UpdateTime,
-		Message:    res.Experiments[0].Error_,
-		Status:     base.ConvertStatus(res.Experiments[0].Status, phase),
-	}, nil
+// hostCmd runs the command in the mount and uts namespace of the host
+func (r *DaemonsetRemoteExecutor) hostCmd(cmd string) string {
+	return fmt.Sprintf("nsenter -t 1 -m -u %s", cmd)
 }
 
 func (r *DaemonsetRemoteExecutor) kubeExec(ctx context.Context, ns, podName, cmd string) ([]byte, error) {
-	logger := log.FromContext(ctx)
-	logger.Info(fmt.Sprintf("%s/%s,exec: %s", ns, podName, cmd))
-
-	execReq := restclient.GetApiServerClientMap(v1alpha1.PodCloudTarget).Post().
-		Namespace(ns).Resource("pods").Name(podName).SubResource("exec").
-		VersionedParams(&corev1.PodExecOptions{
-			//Container: cName,
-			Command: []string{"/bin/bash", "-c", cmd},
-			Stdin:   false,
-			Stdout:  true,
-			Stderr:  true,
-		}, runtime.NewParameterCodec(r.Schema))
-
-	exec, err := remotecommand.NewSPDYExecutor(r.RESTConfig, "POST", execReq.URL())
-	if err != nil {
-		return nil, fmt.Errorf("create remote cmd executor error: %s", err.Error())
-	}
-
-	var stdout, stderr bytes.Buffer
-	if err := exec.StreamWithContext(ctx, remotecommand.StreamOptions{
-		Stdout: &stdout,
-		Stderr: &stderr,
-	}); err != nil {
-		// TODO: Think about how to solve the basic error information collection of parameter operation
-		return nil, fmt.Errorf("exec remote cmd error: %s %s %s", err.Error(), stdout.String(), stderr.String())
-	}
-
-	if stderr.String() != "" {
-		return stdout.Bytes(), fmt.Errorf("exec remote cmd get error message: %s", stderr.String())
-	}
-
-	return stdout.Bytes(), nil
+	return base.KubeExec(ctx, r.RESTConfig, r.Schema, ns, podName, "", cmd)
 }
 
 func (r *DaemonsetRemoteExecutor) getAgentPod(ctx context.Context, nodeIp string) (*model.PodObject, error) {
@@ -0,0 +1,375 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ephemeralexecutor
+
+import (
+	"context"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/common"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/executor/remoteexecutor/base"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/model"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/restclient"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/rest"
+	"time"
+)
+
+const (
+	containerPrefix = "chaosmeta-"
+	// aliveFile keeps the ephemeral container running, it is removed after the experiment is recovered.
+	// An ephemeral container can not be deleted from the pod, so exiting is its cleanup
+	aliveFile          = "/tmp/chaosmeta-alive"
+	defaultMaxLifetime = 86400
+	// expiredExitCode the container exits with it at the max lifetime, only exiting after recovered is 0
+	expiredExitCode = 124
+
+	startInterval = time.Second
+	startTimeout  = time.Minute
+)
+
+// supportedTargets the ephemeral container only shares the processes and the network of the target,
+// its filesystem is the one of the chaosmetad image, so the faults on the files and disks of the target are not supported
+var supportedTargets = []string{"cpu", "mem", "network", "process"}
+
+// EphemeralRemoteExecutor runs chaosmetad in an ephemeral container attached to the target pod, one container for one experiment.
+// The container shares the process namespace of the target container and the network namespace of the pod,
+// so the faults run without the shell and tools of the target image, and without the container runtime of the node.
+// The injectObject of this executor is the object name of the target pod instead of the node ip
+type EphemeralRemoteExecutor struct {
+	RESTConfig *rest.Config
+	Schema     *runtime.Scheme
+
+	Image         string
+	LocalExecPath string
+	Executor      string
+	Version       string
+	MaxLifetime   int
+}
+
+func (r *EphemeralRemoteExecutor) CheckAlive(ctx context.Context, injectObject string) error {
+	ns, podName, _, err := parseInjectObject(injectObject)
+	if err != nil {
+		return err
+	}
+
+	pod, err := r.getPod(ctx, ns, podName)
+	if err != nil {
+		return fmt.Errorf("get pod error: %s", err.Error())
+	}
+
+	if pod.Status.Phase != corev1.PodRunning {
+		return fmt.Errorf("pod is %s", pod.Status.Phase)
+	}
+
+	return nil
+}
+
+// Diagnose the agent is the apiserver, the ephemeral container is not created until the experiment is injected
+func (r *EphemeralRemoteExecutor) Diagnose(ctx context.Context, injectObject string, cRuntime string) (*model.AgentDiagnostics, error) {
+	ns, podName, _, err := parseInjectObject(injectObject)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	if _, err := r.getPod(ctx, ns, podName); err != nil {
+		return nil, fmt.Errorf("get pod error: %s", err.Error())
+	}
+
+	return &model.AgentDiagnostics{
+		AgentVersion:      r.Version,
+		AgentResponseTime: time.Since(start),
+	}, nil
+}
+
+func (r *EphemeralRemoteExecutor) Snapshot(ctx context.Context, injectObject string, cID, cRuntime string) (*v1alpha1.EnvSnapshot, error) {
+	return nil, fmt.Errorf("snapshot is not supported by ephemeral executor")
+}
+
+// Preflight only checks the target and the target container, the capability of chaosmetad is unknown before the ephemeral container is attached
+func (r *EphemeralRemoteExecutor) Preflight(ctx context.Context, injectObject string, target, fault, cID, cRuntime string) ([]string, error) {
+	ns, podName, containerName, err := parseInjectObject(injectObject)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkTarget(target); err != nil {
+		return []string{err.Error()}, nil
+	}
+
+	pod, err := r.getPod(ctx, ns, podName)
+	if err != nil {
+		return nil, fmt.Errorf("get pod error: %s", err.Error())
+	}
+
+	if pod.Status.Phase != corev1.PodRunning {
+		return []string{fmt.Sprintf("pod is %s", pod.Status.Phase)}, nil
+	}
+
+	status := getContainerStatus(pod.Status.ContainerStatuses, containerName)
+	if status == nil || status.State.Running == nil {
+		return []string{fmt.Sprintf("container %s is not running", containerName)}, nil
+	}
+
+	return nil, nil
+}
+
+// Init install agent
+func (r *EphemeralRemoteExecutor) Init(ctx context.Context, target string) error {
+	return nil
+}
+
+// Inject attaches the ephemeral container of the experiment if it is not attached, and injects the fault in it.
+// The container id and runtime are ignored because the ephemeral container is already in the namespaces of the target
+func (r *EphemeralRemoteExecutor) Inject(ctx context.Context, injectObject string, target, fault, uid, timeout, cID, cRuntime string, args []v1alpha1.ArgsUnit) error {
+	ns, podName, containerName, err := parseInjectObject(injectObject)
+	if err != nil {
+		return err
+	}
+
+	if err := checkTarget(target); err != nil {
+		return err
+	}
+
+	if err := r.checkTimeout(timeout); err != nil {
+		return err
+	}
+
+	name := getContainerName(uid)
+	if err := r.attachContainer(ctx, ns, podName, containerName, name); err != nil {
+		return fmt.Errorf("attach ephemeral container error: %s", err.Error())
+	}
+
+	if err := r.waitContainerRunning(ctx, ns, podName, name); err != nil {
+		return fmt.Errorf("wait ephemeral container running error: %s", err.Error())
+	}
+
+	executeCmd := base.GetInjectCmd(r.getExecutor(), target, fault, uid, timeout, "", "", args)
+	if _, err := base.KubeExec(ctx, r.RESTConfig, r.Schema, ns, podName, name, executeCmd); err != nil {
+		return common.WrapError(err, "kubectl exec error")
+	}
+
+	return nil
+}
+
+// Recover recovers the fault and lets the ephemeral container exit.
+// A target whose ephemeral container is never attached has nothing to recover
+func (r *EphemeralRemoteExecutor) Recover(ctx context.Context, injectObject string, uid string) error {
+	ns, podName, _, err := parseInjectObject(injectObject)
+	if err != nil {
+		return err
+	}
+
+	pod, err := r.getPod(ctx, ns, podName)
+	if err != nil {
+		return fmt.Errorf("get pod error: %s", err.Error())
+	}
+
+	name := getContainerName(uid)
+	status := getContainerStatus(pod.Status.EphemeralContainerStatuses, name)
+	if status == nil {
+		return nil
+	}
+
+	if status.State.Terminated != nil {
+		return checkExited(name, status.State.Terminated)
+	}
+
+	executeCmd := fmt.Sprintf("%s && rm -f %s", base.GetRecoverCmd(r.getExecutor(), uid), aliveFile)
+	if _, err = base.KubeExec(ctx, r.RESTConfig, r.Schema, ns, podName, name, executeCmd); err != nil {
+		return common.WrapError(err, "kubectl exec error")
+	}
+
+	return nil
+}
+
+func (r *EphemeralRemoteExecutor) Query(ctx context.Context, injectObject string, uid string, phase v1alpha1.PhaseType) (*model.SubExpInfo, error) {
+	ns, podName, _, err := parseInjectObject(injectObject)
+	if err != nil {
+		return nil, err
+	}
+
+	// the container exits once it is recovered, so it can not be queried any more
+	if phase == v1alpha1.RecoverPhaseType {
+		pod, err := r.getPod(ctx, ns, podName)
+		if err != nil {
+			return nil, fmt.Errorf("get pod error: %s", err.Error())
+		}
+
+		name := getContainerName(uid)
+		status := getContainerStatus(pod.Status.EphemeralContainerStatuses, name)
+		if status == nil {
+			return &model.SubExpInfo{UID: uid, Status: v1alpha1.SuccessStatusType, Message: "ephemeral container is never attached"}, nil
+		}
+
+		if status.State.Terminated != nil {
+			if err := checkExited(name, status.State.Terminated); err != nil {
+				return nil, err
+			}
+			return &model.SubExpInfo{UID: uid, Status: v1alpha1.SuccessStatusType, Message: "ephemeral container exited"}, nil
+		}
+	}
+
+	stdout, err := base.KubeExec(ctx, r.RESTConfig, r.Schema, ns, podName, getContainerName(uid), base.GetQueryCmd(r.getExecutor(), uid))
+	if err != nil {
+		return nil, common.WrapError(err, "kubectl exec error")
+	}
+
+	return base.ParseQueryOutput(stdout, uid, phase)
+}
+
+func (r *EphemeralRemoteExecutor) getExecutor() string {
+	return base.GetExecutorPath(r.LocalExecPath, r.Executor, r.Version)
+}
+
+func (r *EphemeralRemoteExecutor) getMaxLifetime() int {
+	if r.MaxLifetime <= 0 {
+		return defaultMaxLifetime
+	}
+
+	return r.MaxLifetime
+}
+
+// getKeepAliveCmd the ephemeral container exits after the experiment is recovered or the max lifetime is reached
+func (r *EphemeralRemoteExecutor) getKeepAliveCmd() string {
+	return fmt.Sprintf("touch %s; end=$((SECONDS+%d)); while [ -f %s ] && [ $SECONDS -lt $end ]; do sleep 1; done; [ ! -f %s ] || exit %d",
+		aliveFile, r.getMaxLifetime(), aliveFile, aliveFile, expiredExitCode)
+}
+
+// checkTimeout the experiment must end before the container exits at the max lifetime. The faults out of the
+// processes of chaosmetad, such as the rules of tc and iptables and the stopped processes, are left after it exits
+func (r *EphemeralRemoteExecutor) checkTimeout(timeout string) error {
+	maxLifetime := time.Duration(r.getMaxLifetime()) * time.Second
+	if timeout == "" {
+		return fmt.Errorf("experiment without duration is not supported by ephemeral executor, the container exits in %s", maxLifetime)
+	}
+
+	d, err := v1alpha1.ConvertDuration(timeout)
+	if err != nil {
+		return fmt.Errorf("duration[%s] is invalid: %s", timeout, err.Error())
+	}
+
+	if d > maxLifetime {
+		return fmt.Errorf("duration[%s] exceeds the max lifetime %s of the ephemeral container", timeout, maxLifetime)
+	}
+
+	return nil
+}
+
+func (r *EphemeralRemoteExecutor) getPod(ctx context.Context, ns, podName string) (*corev1.Pod, error) {
+	pod := &corev1.Pod{}
+	err := restclient.GetApiServerClientMap(ctx, v1alpha1.PodCloudTarget).Get().
+		Namespace(ns).Resource("pods").Name(podName).Do(ctx).Into(pod)
+	return pod, err
+}
+
+func (r *EphemeralRemoteExecutor) attachContainer(ctx context.Context, ns, podName, targetContainer, name string) error {
+	pod, err := r.getPod(ctx, ns, podName)
+	if err != nil {
+		return fmt.Errorf("get pod error: %s", err.Error())
+	}
+
+	// attached by the last inject which failed after it
+	for _, c := range pod.Spec.EphemeralContainers {
+		if c.Name == name {
+			return nil
+		}
+	}
+
+	privileged := true
+	pod.Spec.EphemeralContainers = append(pod.Spec.EphemeralContainers, corev1.EphemeralContainer{
+		EphemeralContainerCommon: corev1.EphemeralContainerCommon{
+			Name:            name,
+			Image:           r.Image,
+			ImagePullPolicy: corev1.PullIfNotPresent,
+			Command:         []string{"/bin/bash", "-c", r.getKeepAliveCmd()},
+			SecurityContext: &corev1.SecurityContext{Privileged: &privileged},
+		},
+		TargetContainerName: targetContainer,
+	})
+
+	return restclient.GetApiServerClientMap(ctx, v1alpha1.PodCloudTarget).Put().
+		Namespace(ns).Resource("pods").Name(podName).SubResource("ephemeralcontainers").
+		Body(pod).Do(ctx).Error()
+}
+
+func (r *EphemeralRemoteExecutor) waitContainerRunning(ctx context.Context, ns, podName, name string) error {
+	return wait.PollImmediateWithContext(ctx, startInterval, startTimeout, func(ctx context.Context) (bool, error) {
+		pod, err := r.getPod(ctx, ns, podName)
+		if err != nil {
+			return false, fmt.Errorf("get pod error: %s", err.Error())
+		}
+
+		status := getContainerStatus(pod.Status.EphemeralContainerStatuses, name)
+		if status == nil {
+			return false, nil
+		}
+
+		if status.State.Terminated != nil {
+			return false, fmt.Errorf("ephemeral container %s exited: %s", name, status.State.Terminated.Reason)
+		}
+
+		return status.State.Running != nil, nil
+	})
+}
+
+// checkExited the container exits with 0 only after the experiment is recovered, otherwise it exited at the max
+// lifetime or crashed, and the fault may be left in the pod without a container to recover it
+func checkExited(name string, state *corev1.ContainerStateTerminated) error {
+	if state.ExitCode == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("ephemeral container %s exited with code %d before the experiment is recovered, the fault may be left in the pod: %s",
+		name, state.ExitCode, state.Reason)
+}
+
+func checkTarget(target string) error {
+	for _, t := range supportedTargets {
+		if t == target {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("target %s is not supported by ephemeral executor, only support: %v", target, supportedTargets)
+}
+
+func getContainerName(uid string) string {
+	return containerPrefix + uid
+}
+
+func getContainerStatus(statuses []corev1.ContainerStatus, name string) *corev1.ContainerStatus {
+	for i := range statuses {
+		if statuses[i].Name == name {
+			return &statuses[i]
+		}
+	}
+
+	return nil
+}
+
+func parseInjectObject(injectObject string) (ns, podName, containerName string, err error) {
+	ns, podName, containerName, err = model.ParsePodInfo(injectObject)
+	if err != nil {
+		err = fmt.Errorf("ephemeral executor only supports the targets of pod scope: %s", err.Error())
+	}
+
+	return
+}
@@ -0,0 +1,201 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ephemeralexecutor
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/agiledragon/gomonkey"
+	"github.com/stretchr/testify/assert"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/executor/remoteexecutor/base"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/restclient"
+	"io"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeApiServer serves the pod, an attached ephemeral container is running at once
+type fakeApiServer struct {
+	lock sync.Mutex
+	pod  *corev1.Pod
+}
+
+func (f *fakeApiServer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if req.Method == http.MethodPut && strings.HasSuffix(req.URL.Path, "/ephemeralcontainers") {
+		// the body is encoded by the content type of the client, such as protobuf
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		obj, err := runtime.Decode(scheme.Codecs.UniversalDeserializer(), body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		pod := obj.(*corev1.Pod)
+		f.pod.Spec.EphemeralContainers = pod.Spec.EphemeralContainers
+		f.pod.Status.EphemeralContainerStatuses = nil
+		for _, c := range pod.Spec.EphemeralContainers {
+			f.pod.Status.EphemeralContainerStatuses = append(f.pod.Status.EphemeralContainerStatuses, corev1.ContainerStatus{
+				Name:  c.Name,
+				State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+			})
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(f.pod)
+}
+
+func newTestExecutor(t *testing.T, server *fakeApiServer) *EphemeralRemoteExecutor {
+	srv := httptest.NewServer(server)
+	t.Cleanup(srv.Close)
+
+	restConfig := &rest.Config{Host: srv.URL}
+	assert.NoError(t, restclient.SetApiServerClientMap(restConfig, scheme.Scheme, []v1alpha1.CloudTargetType{v1alpha1.PodCloudTarget}))
+	return &EphemeralRemoteExecutor{
+		RESTConfig:    restConfig,
+		Schema:        scheme.Scheme,
+		Image:         "chaosmeta-daemon:v0.3.9",
+		LocalExecPath: "/opt/chaosmeta",
+		Executor:      "chaosmetad",
+		Version:       "0.3.9",
+	}
+}
+
+func TestEphemeralRemoteExecutor_InjectAndRecover(t *testing.T) {
+	server := &fakeApiServer{pod: &corev1.Pod{
+		TypeMeta:   metav1.TypeMeta{Kind: "Pod", APIVersion: "v1"},
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pod1"},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+	}}
+	r := newTestExecutor(t, server)
+
+	var execContainers, execCmds []string
+	patches := gomonkey.ApplyFunc(base.KubeExec, func(ctx context.Context, restConfig *rest.Config, schema *runtime.Scheme, ns, podName, container, cmd string) ([]byte, error) {
+		execContainers = append(execContainers, container)
+		execCmds = append(execCmds, cmd)
+		return nil, nil
+	})
+	defer patches.Reset()
+
+	ctx := context.Background()
+	err := r.Inject(ctx, "pod/default/pod1/c1", "cpu", "burn", "uid1", "10m", "cid", "docker", []v1alpha1.ArgsUnit{{Key: "percent", Value: "80"}})
+	assert.NoError(t, err)
+
+	containers := server.pod.Spec.EphemeralContainers
+	assert.Equal(t, 1, len(containers))
+	assert.Equal(t, "chaosmeta-uid1", containers[0].Name)
+	assert.Equal(t, "c1", containers[0].TargetContainerName)
+	assert.Equal(t, "chaosmeta-daemon:v0.3.9", containers[0].Image)
+	assert.True(t, strings.Contains(containers[0].Command[2], "SECONDS+86400"), containers[0].Command[2])
+
+	// the container of the target is not used by chaosmetad in the ephemeral container
+	assert.Equal(t, []string{"chaosmeta-uid1"}, execContainers)
+	assert.Equal(t, "/opt/chaosmeta/chaosmetad-0.3.9/chaosmetad inject cpu burn --uid uid1 --percent=80 --timeout 10m", execCmds[0])
+
+	// injected again, the attached container is reused
+	err = r.Inject(ctx, "pod/default/pod1/c1", "cpu", "burn", "uid1", "10m", "cid", "docker", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(server.pod.Spec.EphemeralContainers))
+
+	err = r.Recover(ctx, "pod/default/pod1/c1", "uid1")
+	assert.NoError(t, err)
+	assert.Equal(t, "/opt/chaosmeta/chaosmetad-0.3.9/chaosmetad recover uid1 && rm -f /tmp/chaosmeta-alive", execCmds[2])
+
+	// never attached, nothing to recover
+	err = r.Recover(ctx, "pod/default/pod1/c1", "uid2")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(execCmds))
+
+	// exited after recovered, it is recovered without exec
+	server.pod.Status.EphemeralContainerStatuses[0].State = corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "Completed"}}
+	err = r.Recover(ctx, "pod/default/pod1/c1", "uid1")
+	assert.NoError(t, err)
+	info, err := r.Query(ctx, "pod/default/pod1/c1", "uid1", v1alpha1.RecoverPhaseType)
+	assert.NoError(t, err)
+	assert.Equal(t, v1alpha1.SuccessStatusType, info.Status)
+	assert.Equal(t, 3, len(execCmds))
+
+	// exited at the max lifetime or crashed, the fault may be left
+	server.pod.Status.EphemeralContainerStatuses[0].State = corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+		Reason: "Error", ExitCode: expiredExitCode}}
+	err = r.Recover(ctx, "pod/default/pod1/c1", "uid1")
+	assert.Error(t, err)
+	_, err = r.Query(ctx, "pod/default/pod1/c1", "uid1", v1alpha1.RecoverPhaseType)
+	assert.Error(t, err)
+	assert.Equal(t, 3, len(execCmds))
+
+	// the experiment must end before the container exits
+	err = r.Inject(ctx, "pod/default/pod1/c1", "cpu", "burn", "uid4", "", "cid", "docker", nil)
+	assert.Error(t, err)
+	err = r.Inject(ctx, "pod/default/pod1/c1", "cpu", "burn", "uid4", "25h", "cid", "docker", nil)
+	assert.Error(t, err)
+	assert.Equal(t, 1, len(server.pod.Spec.EphemeralContainers))
+
+	// the filesystem of the target is not shared
+	err = r.Inject(ctx, "pod/default/pod1/c1", "file", "add", "uid3", "10m", "cid", "docker", nil)
+	assert.Error(t, err)
+	assert.Equal(t, 1, len(server.pod.Spec.EphemeralContainers))
+}
+
+func TestEphemeralRemoteExecutor_Preflight(t *testing.T) {
+	server := &fakeApiServer{pod: &corev1.Pod{
+		TypeMeta:   metav1.TypeMeta{Kind: "Pod", APIVersion: "v1"},
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pod1"},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			ContainerStatuses: []corev1.ContainerStatus{
+				{Name: "c1", State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}},
+				{Name: "c2", State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{}}},
+			},
+		},
+	}}
+	r := newTestExecutor(t, server)
+
+	ctx := context.Background()
+	reasons, err := r.Preflight(ctx, "pod/default/pod1/c1", "cpu", "burn", "", "")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(reasons))
+
+	reasons, err = r.Preflight(ctx, "pod/default/pod1/c2", "cpu", "burn", "", "")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"container c2 is not running"}, reasons)
+
+	reasons, err = r.Preflight(ctx, "pod/default/pod1/c1", "disk", "fill", "", "")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"target disk is not supported by ephemeral executor, only support: [cpu mem network process]"}, reasons)
+
+	// only the targets of pod scope
+	_, err = r.Preflight(ctx, "1.1.1.1", "cpu", "burn", "", "")
+	assert.Error(t, err)
+}
@@ -0,0 +1,376 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package grpcexecutor
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/common"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/executor/remoteexecutor/base"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/model"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/selector"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/sidecar"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"os"
+	"path/filepath"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sync"
+	"time"
+)
+
+const (
+	serviceName = "chaosmetad.v1.Agent"
+	// maxRecvMsgSize the output of the experiment is not limited by the stdout of kubectl exec
+	maxRecvMsgSize = 64 * 1024 * 1024
+	// tokenMetadataKey the agent rejects the calls without "Bearer <token>" in the metadata
+	tokenMetadataKey = "authorization"
+
+	// the files of the mounted secret of the mutual TLS with the agent
+	tlsCAFile   = "ca.crt"
+	tlsCertFile = "tls.crt"
+	tlsKeyFile  = "tls.key"
+)
+
+type snapshotRequest struct {
+	ContainerRuntime string `json:"container_runtime,omitempty"`
+	ContainerId      string `json:"container_id,omitempty"`
+}
+
+// GrpcRemoteExecutor calls the grpc service of the chaosmetad DaemonSet pod on the node of target
+type GrpcRemoteExecutor struct {
+	Version        string
+	Port           int
+	DaemonsetNs    string
+	DaemonsetLabel map[string]string
+	// Token is shared with the DaemonSet agent, the token of a sidecar is in the Secret of its pod
+	Token string
+	// TLSConfig verifies the agent and presents the client certificate, the agent only serves mutual TLS.
+	// The certificate of a sidecar is pinned by the Secret of its pod instead of the CA
+	TLSConfig *tls.Config
+	// Sidecar calls the chaosmeta sidecar of the target pod instead of the DaemonSet pod,
+	// the injectObject is the object name of the target pod instead of the node ip
+	Sidecar bool
+	// Client reads the Secret of the sidecar, only used by the sidecar mode
+	Client client.Reader
+
+	connLock sync.Mutex
+	conns    map[string]*grpc.ClientConn
+}
+
+func (r *GrpcRemoteExecutor) CheckAlive(ctx context.Context, injectObject string) error {
+	var resp base.VersionResponse
+	if err := r.invoke(ctx, injectObject, "Version", struct{}{}, &resp); err != nil {
+		return err
+	}
+
+	if resp.Data == nil || resp.Code != base.SucCode {
+		return fmt.Errorf("query version error: %s", resp.Message)
+	}
+
+	if resp.Data.Version != r.Version {
+		return fmt.Errorf("expected version %s, but get %s", r.Version, resp.Data.Version)
+	}
+
+	return nil
+}
+
+// Diagnose only measures the agent, the container runtime is not reachable through the agent's grpc api
+func (r *GrpcRemoteExecutor) Diagnose(ctx context.Context, injectObject string, cRuntime string) (*model.AgentDiagnostics, error) {
+	start := time.Now()
+	var resp base.VersionResponse
+	if err := r.invoke(ctx, injectObject, "Version", struct{}{}, &resp); err != nil {
+		return nil, err
+	}
+
+	diagnostics := &model.AgentDiagnostics{AgentResponseTime: time.Since(start)}
+	if resp.Data == nil || resp.Code != base.SucCode {
+		return diagnostics, fmt.Errorf("query version error: %s", resp.Message)
+	}
+
+	diagnostics.AgentVersion = resp.Data.Version
+	return diagnostics, nil
+}
+
+func (r *GrpcRemoteExecutor) Snapshot(ctx context.Context, injectObject string, cID, cRuntime string) (*v1alpha1.EnvSnapshot, error) {
+	req := snapshotRequest{}
+	cID, cRuntime = r.getContainer(cID, cRuntime)
+	if cRuntime != "" {
+		req.ContainerRuntime, req.ContainerId = cRuntime, cID
+	}
+
+	var resp base.SnapshotResponse
+	if err := r.invoke(ctx, injectObject, "Snapshot", req, &resp); err != nil {
+		return nil, err
+	}
+
+	if resp.Data == nil || resp.Code != base.SucCode {
+		return nil, fmt.Errorf("err code: {%d}, err msg: %s", resp.Code, resp.Message)
+	}
+
+	return resp.Data.ToEnvSnapshot(), nil
+}
+
+func (r *GrpcRemoteExecutor) Preflight(ctx context.Context, injectObject string, target, fault, cID, cRuntime string) ([]string, error) {
+	req := base.CapabilityRequest{Target: target, Fault: fault}
+	cID, cRuntime = r.getContainer(cID, cRuntime)
+	if cRuntime != "" {
+		req.ContainerRuntime, req.ContainerId = cRuntime, cID
+	}
+
+	var resp base.CapabilityResponse
+	if err := r.invoke(ctx, injectObject, "Capability", req, &resp); err != nil {
+		return nil, common.NewCodedError(common.GetAgentUnavailableErrorInfo(), err)
+	}
+
+	// an unhealthy agent still reports its capability
+	if resp.Data == nil {
+		return nil, base.GetResponseError(resp.Code, resp.Message, resp.Error)
+	}
+
+	return resp.Data.GetUnusableReasons(target, fault), nil
+}
+
+// Init the agent is deployed by DaemonSet
+func (r *GrpcRemoteExecutor) Init(ctx context.Context, target string) error {
+	return nil
+}
+
+func (r *GrpcRemoteExecutor) Inject(ctx context.Context, injectObject string, target, fault, uid, timeout, cID, cRuntime string, args []v1alpha1.ArgsUnit) error {
+	if err := r.CheckAlive(ctx, injectObject); err != nil {
+		return common.NewCodedError(common.GetAgentUnavailableErrorInfo(), fmt.Errorf("check target's status error: %s", err.Error()))
+	}
+
+	argsStr, err := base.ConvertArgs(args)
+	if err != nil {
+		return err
+	}
+
+	cID, cRuntime = r.getContainer(cID, cRuntime)
+	var resp base.InjectResponse
+	if err := r.invoke(ctx, injectObject, "Inject", base.InjectRequest{
+		Target:           target,
+		Fault:            fault,
+		Timeout:          timeout,
+		ContainerId:      cID,
+		ContainerRuntime: cRuntime,
+		Uid:              uid,
+		Args:             argsStr,
+	}, &resp); err != nil {
+		return common.NewCodedError(common.GetAgentUnavailableErrorInfo(), err)
+	}
+
+	if resp.Code != base.SucCode {
+		return base.GetResponseError(resp.Code, resp.Message, resp.Error)
+	}
+
+	return nil
+}
+
+func (r *GrpcRemoteExecutor) Recover(ctx context.Context, injectObject string, uid string) error {
+	var resp base.CommonResponse
+	if err := r.invoke(ctx, injectObject, "Recover", base.RecoverRequest{Uid: uid}, &resp); err != nil {
+		return common.NewCodedError(common.GetAgentUnavailableErrorInfo(), err)
+	}
+
+	if resp.Code != base.SucCode {
+		return base.GetResponseError(resp.Code, resp.Message, resp.Error)
+	}
+
+	return nil
+}
+
+func (r *GrpcRemoteExecutor) Query(ctx context.Context, injectObject string, uid string, phase v1alpha1.PhaseType) (*model.SubExpInfo, error) {
+	var resp base.QueryResponse
+	if err := r.invoke(ctx, injectObject, "Query", base.QueryRequest{Uid: uid}, &resp); err != nil {
+		return nil, common.NewCodedError(common.GetAgentUnavailableErrorInfo(), err)
+	}
+
+	if resp.Code != base.SucCode {
+		return nil, base.GetResponseError(resp.Code, resp.Message, resp.Error)
+	}
+
+	if resp.Data == nil || resp.Data.Total == 0 {
+		return nil, fmt.Errorf("task not found")
+	}
+
+	task := resp.Data.Experiments[0]
+	return &model.SubExpInfo{
+		UID:        uid,
+		CreateTime: task.CreateTime,
+		UpdateTime: task.UpdateTime,
+		Message:    task.Error_,
+		Status:     base.ConvertStatus(task.Status, phase),
+	}, nil
+}
+
+func (r *GrpcRemoteExecutor) invoke(ctx context.Context, injectObject, method string, req, resp interface{}) error {
+	agentPod, err := r.getAgentPod(ctx, injectObject)
+	if err != nil {
+		return fmt.Errorf("get agent pod of %s error: %s", injectObject, err.Error())
+	}
+
+	addr, token, tlsConfig := fmt.Sprintf("%s:%d", agentPod.PodIP, r.Port), r.Token, r.TLSConfig
+	connKey := addr
+	if r.Sidecar {
+		secretName := agentPod.Annotations[v1alpha1.SidecarSecretAnnotationKey]
+		if token, tlsConfig, err = r.getSidecarCredential(ctx, agentPod, secretName); err != nil {
+			return fmt.Errorf("get credential of sidecar of pod[%s/%s] error: %s", agentPod.Namespace, agentPod.PodName, err.Error())
+		}
+		// the ip may be reused by another pod with another certificate
+		connKey = fmt.Sprintf("%s/%s", addr, secretName)
+	}
+
+	conn, err := r.getConn(connKey, addr, tlsConfig)
+	if err != nil {
+		return fmt.Errorf("connect to agent pod[%s/%s] error: %s", agentPod.Namespace, agentPod.PodName, err.Error())
+	}
+
+	ctx = metadata.AppendToOutgoingContext(ctx, tokenMetadataKey, "Bearer "+token)
+	if err := conn.Invoke(ctx, fmt.Sprintf("/%s/%s", serviceName, method), req, resp); err != nil {
+		return fmt.Errorf("call %s of agent pod[%s/%s] error: %s", method, agentPod.Namespace, agentPod.PodName, err.Error())
+	}
+
+	return nil
+}
+
+// getSidecarCredential reads the token and the certificate of the sidecar from the Secret created for its pod
+func (r *GrpcRemoteExecutor) getSidecarCredential(ctx context.Context, agentPod *model.PodObject, secretName string) (string, *tls.Config, error) {
+	if secretName == "" {
+		return "", nil, fmt.Errorf("no secret of sidecar, the pod is injected by an old version")
+	}
+
+	if r.Client == nil || r.TLSConfig == nil || len(r.TLSConfig.Certificates) == 0 {
+		return "", nil, fmt.Errorf("client or tls config of sidecar executor is not provided")
+	}
+
+	secret := &corev1.Secret{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: agentPod.Namespace, Name: secretName}, secret); err != nil {
+		return "", nil, fmt.Errorf("get secret %s error: %s", secretName, err.Error())
+	}
+
+	if !sidecar.IsOwnedBy(secret, agentPod.PodUID) {
+		return "", nil, fmt.Errorf("secret %s is not created for the pod", secretName)
+	}
+
+	return sidecar.GetCredential(secret, r.TLSConfig.Certificates[0])
+}
+
+// getConn reuses the connection to the agent, a connection is redialed after shutdown
+func (r *GrpcRemoteExecutor) getConn(key, addr string, tlsConfig *tls.Config) (*grpc.ClientConn, error) {
+	r.connLock.Lock()
+	defer r.connLock.Unlock()
+
+	if conn, ok := r.conns[key]; ok && conn.GetState() != connectivity.Shutdown {
+		return conn, nil
+	}
+
+	if tlsConfig == nil {
+		return nil, fmt.Errorf("tls config is not provided, the token is never sent in plaintext")
+	}
+
+	conn, err := grpc.Dial(addr,
+		grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)),
+		grpc.WithDefaultCallOptions(grpc.ForceCodec(jsonCodec{}), grpc.MaxCallRecvMsgSize(maxRecvMsgSize)),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.conns == nil {
+		r.conns = make(map[string]*grpc.ClientConn)
+	}
+	r.conns[key] = conn
+	return conn, nil
+}
+
+// LoadTLSConfig loads the mutual TLS config from the dir of a mounted secret with ca.crt, tls.crt and tls.key.
+// The agents are dialed by pod ip, so they are verified by the CA and the serverName in their certificate
+func LoadTLSConfig(dir, serverName string) (*tls.Config, error) {
+	if dir == "" {
+		return nil, fmt.Errorf("tls path is not provided")
+	}
+
+	if serverName == "" {
+		return nil, fmt.Errorf("server name is not provided")
+	}
+
+	ca, err := os.ReadFile(filepath.Join(dir, tlsCAFile))
+	if err != nil {
+		return nil, fmt.Errorf("read CA error: %s", err.Error())
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(ca) {
+		return nil, fmt.Errorf("%s is not a PEM certificate", tlsCAFile)
+	}
+
+	keyPair, err := tls.LoadX509KeyPair(filepath.Join(dir, tlsCertFile), filepath.Join(dir, tlsKeyFile))
+	if err != nil {
+		return nil, fmt.Errorf("load certificate error: %s", err.Error())
+	}
+
+	return &tls.Config{
+		RootCAs:      pool,
+		ServerName:   serverName,
+		Certificates: []tls.Certificate{keyPair},
+		MinVersion:   tls.VersionTLS12,
+	}, nil
+}
+
+// getContainer the sidecar is already in the namespaces of the target pod, it does not reach the container runtime
+func (r *GrpcRemoteExecutor) getContainer(cID, cRuntime string) (string, string) {
+	if r.Sidecar {
+		return "", ""
+	}
+
+	return cID, cRuntime
+}
+
+func (r *GrpcRemoteExecutor) getAgentPod(ctx context.Context, injectObject string) (*model.PodObject, error) {
+	if r.Sidecar {
+		return getSidecarPod(ctx, injectObject)
+	}
+
+	podList, err := selector.GetAnalyzer().GetPodListByLabelInNode(ctx, r.DaemonsetNs, r.DaemonsetLabel, injectObject)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(podList) != 1 {
+		return nil, fmt.Errorf("length of agent pod is not 1")
+	}
+
+	return podList[0], nil
+}
+
+// getSidecarPod the target pod is the agent pod, whose ip is the address of its sidecar
+func getSidecarPod(ctx context.Context, injectObject string) (*model.PodObject, error) {
+	ns, podName, _, err := model.ParsePodInfo(injectObject)
+	if err != nil {
+		return nil, fmt.Errorf("sidecar executor only supports the targets of pod scope: %s", err.Error())
+	}
+
+	return selector.GetAnalyzer().GetPod(ctx, ns, podName, v1alpha1.SidecarContainerName)
+}
@@ -0,0 +1,274 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package grpcexecutor
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"github.com/agiledragon/gomonkey"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	mockselector "github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/mock/selector"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/executor/remoteexecutor/base"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/model"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/selector"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/sidecar"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/encoding"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"testing"
+	"time"
+)
+
+type fakeAgent struct {
+	token     string
+	injectReq *base.InjectRequest
+}
+
+func (f *fakeAgent) methodDesc(name string, newReq func() interface{}, call func(req interface{}) interface{}) grpc.MethodDesc {
+	return grpc.MethodDesc{
+		MethodName: name,
+		Handler: func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+			req := newReq()
+			if err := dec(req); err != nil {
+				return nil, err
+			}
+
+			md, _ := metadata.FromIncomingContext(ctx)
+			if values := md.Get(tokenMetadataKey); len(values) != 1 || values[0] != "Bearer "+f.token {
+				return nil, status.Error(codes.Unauthenticated, "invalid token")
+			}
+			return call(req), nil
+		},
+	}
+}
+
+// writeTestTLS writes ca.crt and a tls.crt of both server and client auth for "chaosmeta-daemon" into a dir
+func writeTestTLS(t *testing.T) string {
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "chaosmeta-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	caDer, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	assert.NoError(t, err)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "chaosmeta-daemon"},
+		DNSNames:     []string{"chaosmeta-daemon"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, caTemplate, &key.PublicKey, caKey)
+	assert.NoError(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	assert.NoError(t, err)
+
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, tlsCAFile), pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDer}), 0600))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, tlsCertFile), pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, tlsKeyFile), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600))
+	return dir
+}
+
+// startFakeAgent serves mutual TLS like chaosmetad with the certificate, the callers are signed by clientCAs
+func startFakeAgent(t *testing.T, agent *fakeAgent, cert tls.Certificate, clientCAs *x509.CertPool) int {
+	encoding.RegisterCodec(jsonCodec{})
+	server := grpc.NewServer(grpc.Creds(credentials.NewTLS(&tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientCAs:    clientCAs,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+	})))
+	server.RegisterService(&grpc.ServiceDesc{
+		ServiceName: serviceName,
+		HandlerType: (*interface{})(nil),
+		Methods: []grpc.MethodDesc{
+			agent.methodDesc("Version", func() interface{} { return &struct{}{} }, func(req interface{}) interface{} {
+				return &base.VersionResponse{Data: &base.VersionInfo{Version: "0.3.9"}}
+			}),
+			agent.methodDesc("Inject", func() interface{} { return &base.InjectRequest{} }, func(req interface{}) interface{} {
+				agent.injectReq = req.(*base.InjectRequest)
+				return &base.InjectResponse{}
+			}),
+			agent.methodDesc("Query", func() interface{} { return &base.QueryRequest{} }, func(req interface{}) interface{} {
+				return &base.QueryResponse{Data: &base.QueryResponseData{Total: 1, Experiments: []base.ExperimentDataUnit{
+					{Uid: req.(*base.QueryRequest).Uid, Status: base.SuccessStatus},
+				}}}
+			}),
+			agent.methodDesc("Recover", func() interface{} { return &base.RecoverRequest{} }, func(req interface{}) interface{} {
+				return &base.CommonResponse{Code: 1, Message: "task not found"}
+			}),
+		},
+	}, agent)
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen error: %s", err.Error())
+	}
+	go func() {
+		_ = server.Serve(lis)
+	}()
+	t.Cleanup(server.Stop)
+
+	return lis.Addr().(*net.TCPAddr).Port
+}
+
+func TestGrpcRemoteExecutor(t *testing.T) {
+	var (
+		ctx    = context.Background()
+		agent  = &fakeAgent{token: "secret"}
+		nodeIP = "10.0.0.1"
+		label  = map[string]string{"app.chaosmeta.io": "chaosmeta-daemon"}
+	)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	analyzerMock := mockselector.NewMockIAnalyzer(ctrl)
+	analyzerMock.EXPECT().GetPodListByLabelInNode(ctx, "chaosmeta-inject", label, nodeIP).
+		Return([]*model.PodObject{{Namespace: "chaosmeta-inject", PodName: "chaosmeta-daemon-x", PodIP: "127.0.0.1"}}, nil).AnyTimes()
+	gomonkey.ApplyFunc(selector.GetAnalyzer, func() selector.IAnalyzer {
+		return analyzerMock
+	})
+
+	tlsConfig, err := LoadTLSConfig(writeTestTLS(t), "chaosmeta-daemon")
+	assert.NoError(t, err)
+	r := &GrpcRemoteExecutor{
+		Version:        "0.3.9",
+		Port:           startFakeAgent(t, agent, tlsConfig.Certificates[0], tlsConfig.RootCAs),
+		DaemonsetNs:    "chaosmeta-inject",
+		DaemonsetLabel: label,
+		Token:          "wrong",
+	}
+
+	assert.Error(t, r.CheckAlive(ctx, nodeIP), "the token is never sent without tls")
+	r.TLSConfig = tlsConfig
+	assert.Error(t, r.CheckAlive(ctx, nodeIP), "the agent rejects a wrong token")
+	r.Token = "secret"
+	assert.NoError(t, r.CheckAlive(ctx, nodeIP))
+
+	err = r.Inject(ctx, nodeIP, "cpu", "burn", "uid1", "1m", "", "", []v1alpha1.ArgsUnit{
+		{Key: "percent", Value: "80", ValueType: v1alpha1.IntVType},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "uid1", agent.injectReq.Uid)
+	assert.Equal(t, `{"percent":80}`, agent.injectReq.Args)
+
+	info, err := r.Query(ctx, nodeIP, "uid1", v1alpha1.InjectPhaseType)
+	assert.NoError(t, err)
+	assert.Equal(t, v1alpha1.SuccessStatusType, info.Status)
+
+	assert.Error(t, r.Recover(ctx, nodeIP, "uid1"))
+
+	r.Version = "0.4.0"
+	assert.Error(t, r.CheckAlive(ctx, nodeIP))
+}
+
+func TestGrpcRemoteExecutor_Sidecar(t *testing.T) {
+	var (
+		ctx    = context.Background()
+		agent  = &fakeAgent{}
+		podStr = "pod/default/pod1/nginx"
+	)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	analyzerMock := mockselector.NewMockIAnalyzer(ctrl)
+	podObject := &model.PodObject{Namespace: "default", PodName: "pod1", PodUID: "uid1", PodIP: "127.0.0.1",
+		Annotations: map[string]string{v1alpha1.SidecarSecretAnnotationKey: "chaosmeta-sidecar-x"}}
+	analyzerMock.EXPECT().GetPod(ctx, "default", "pod1", v1alpha1.SidecarContainerName).Return(podObject, nil).AnyTimes()
+	gomonkey.ApplyFunc(selector.GetAnalyzer, func() selector.IAnalyzer {
+		return analyzerMock
+	})
+
+	tlsConfig, err := LoadTLSConfig(writeTestTLS(t), sidecar.ServerName)
+	assert.NoError(t, err)
+	secret, err := sidecar.NewSecret(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pod1", UID: "uid1"}},
+		"chaosmeta-sidecar-x", nil)
+	assert.NoError(t, err)
+	agent.token = string(secret.Data[sidecar.TokenKey])
+	sidecarCert, err := tls.X509KeyPair(secret.Data[sidecar.CertKey], secret.Data[sidecar.KeyKey])
+	assert.NoError(t, err)
+
+	r := &GrpcRemoteExecutor{
+		Version:   "0.3.9",
+		Port:      startFakeAgent(t, agent, sidecarCert, tlsConfig.RootCAs),
+		Token:     "secret",
+		TLSConfig: tlsConfig,
+		Sidecar:   true,
+		Client:    fake.NewClientBuilder().Build(),
+	}
+	assert.Error(t, r.CheckAlive(ctx, podStr), "the secret of the pod is not created")
+
+	// the token and certificate of the sidecar are the ones in the secret of its pod instead of the DaemonSet agent
+	r.Client = fake.NewClientBuilder().WithObjects(secret).Build()
+	assert.NoError(t, r.CheckAlive(ctx, podStr))
+
+	// the sidecar is already in the namespaces of the target container
+	err = r.Inject(ctx, podStr, "cpu", "burn", "uid1", "1m", "cid", "docker", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "", agent.injectReq.ContainerId)
+	assert.Equal(t, "", agent.injectReq.ContainerRuntime)
+
+	assert.Error(t, r.CheckAlive(ctx, "10.0.0.1"))
+
+	// a secret of another pod is never trusted
+	podObject.PodUID = "uid2"
+	assert.Error(t, r.CheckAlive(ctx, podStr))
+}
+
+func TestLoadTLSConfig(t *testing.T) {
+	dir := writeTestTLS(t)
+	_, err := LoadTLSConfig("", "chaosmeta-daemon")
+	assert.Error(t, err)
+	_, err = LoadTLSConfig(dir, "")
+	assert.Error(t, err)
+	_, err = LoadTLSConfig(t.TempDir(), "chaosmeta-daemon")
+	assert.Error(t, err, "the secret is not mounted")
+
+	tlsConfig, err := LoadTLSConfig(dir, "chaosmeta-daemon")
+	assert.NoError(t, err)
+	assert.Equal(t, "chaosmeta-daemon", tlsConfig.ServerName)
+	assert.Len(t, tlsConfig.Certificates, 1)
+}
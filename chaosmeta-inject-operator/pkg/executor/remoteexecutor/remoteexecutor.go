@@ -23,11 +23,18 @@ import (
 	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/config"
 	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/executor/remoteexecutor/agentexecutor"
 	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/executor/remoteexecutor/daemonsetexecutor"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/executor/remoteexecutor/ephemeralexecutor"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/executor/remoteexecutor/grpcexecutor"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/executor/remoteexecutor/sshexecutor"
 	httpclient "github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/http"
 	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/model"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/sidecar"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/rest"
 	"net/http"
+	"os"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"strings"
 )
 
 type RemoteModeType string
@@ -35,8 +42,15 @@ type RemoteModeType string
 const (
 	AgentRemoteMode     RemoteModeType = "agent"
 	DaemonsetRemoteMode RemoteModeType = "daemonset"
+	EphemeralRemoteMode RemoteModeType = "ephemeral"
+	GrpcRemoteMode      RemoteModeType = "grpc"
+	SidecarRemoteMode   RemoteModeType = "sidecar"
+	SSHRemoteMode       RemoteModeType = "ssh"
 )
 
+// defaultGrpcServerName is the DNS name in the certificate of the DaemonSet agent
+const defaultGrpcServerName = "chaosmeta-daemon"
+
 type RemoteExecutor interface {
 	// CheckAlive check service alive
 	CheckAlive(ctx context.Context, injectObject string) error
@@ -45,44 +59,264 @@ type RemoteExecutor interface {
 	Inject(ctx context.Context, injectObject string, target, fault, uid, timeout, cID, cRuntime string, args []v1alpha1.ArgsUnit) error
 	Recover(ctx context.Context, injectObject string, uid string) error
 	Query(ctx context.Context, injectObject string, uid string, phase v1alpha1.PhaseType) (*model.SubExpInfo, error)
+	// Diagnose gather the agent and container runtime information of a slow target
+	Diagnose(ctx context.Context, injectObject string, cRuntime string) (*model.AgentDiagnostics, error)
+	// Snapshot gather the system state of the host, or of the container if cRuntime is not empty
+	Snapshot(ctx context.Context, injectObject string, cID, cRuntime string) (*v1alpha1.EnvSnapshot, error)
+	// Preflight returns the reasons why the fault can not be injected into the target, empty means ready
+	Preflight(ctx context.Context, injectObject string, target, fault, cID, cRuntime string) ([]string, error)
 	//SyncStatus(ctx context.Context, exp *v1alpha1.ExperimentStatus)
 }
 
-var globalRemoteExecutor RemoteExecutor
+// Factory creates the remote executor from the config of operator
+type Factory func(config *config.ExecutorConfig, restConfig *rest.Config, schema *runtime.Scheme) (RemoteExecutor, error)
 
-func SetGlobalRemoteExecutor(config *config.ExecutorConfig, restConfig *rest.Config, schema *runtime.Scheme) error {
-	switch RemoteModeType(config.Mode) {
-	case AgentRemoteMode:
-		globalRemoteExecutor = &agentexecutor.AgentRemoteExecutor{
-			Client: &httpclient.HTTPClient{
-				Client: &http.Client{},
-			},
-			Version:     config.Version,
-			ServicePort: config.AgentConfig.AgentPort,
-		}
-	case DaemonsetRemoteMode:
-		globalRemoteExecutor = &daemonsetexecutor.DaemonsetRemoteExecutor{
-			//ApiServer:  apiServer,
-			RESTConfig: restConfig,
-			Schema:     schema,
+var (
+	factoryMap          = make(map[RemoteModeType]Factory)
+	remoteExecutorMap   = make(map[RemoteModeType]RemoteExecutor)
+	unavailableErrorMap = make(map[RemoteModeType]error)
+	defaultMode         RemoteModeType
+)
 
-			LocalExecPath: config.DaemonsetConfig.LocalExecPath,
-			Executor:      config.Executor,
-			Version:       config.Version,
+func init() {
+	RegisterRemoteExecutor(AgentRemoteMode, newAgentRemoteExecutor)
+	RegisterRemoteExecutor(DaemonsetRemoteMode, newDaemonsetRemoteExecutor)
+	RegisterRemoteExecutor(EphemeralRemoteMode, newEphemeralRemoteExecutor)
+	RegisterRemoteExecutor(GrpcRemoteMode, newGrpcRemoteExecutor)
+	RegisterRemoteExecutor(SidecarRemoteMode, newSidecarRemoteExecutor)
+	RegisterRemoteExecutor(SSHRemoteMode, newSSHRemoteExecutor)
+}
 
-			DaemonsetNs:    config.DaemonsetConfig.DaemonNs,
-			DaemonsetLabel: config.DaemonsetConfig.DaemonLabel,
+// RegisterRemoteExecutor makes a new transport selectable by the mode in config, "executor" of experiment and the label of target
+func RegisterRemoteExecutor(mode RemoteModeType, factory Factory) {
+	factoryMap[mode] = factory
+}
 
-			//AutoLabelNode:     config.DaemonsetConfig.AutoLabelNode,
-			//NodeSelectorLabel: config.DaemonsetConfig.NodeSelectorLabel,
-		}
-	default:
+// SetGlobalRemoteExecutor creates all the registered remote executors, the one of config mode is the default and must be created.
+// The others failing to create are only unavailable to the experiments selecting them
+func SetGlobalRemoteExecutor(config *config.ExecutorConfig, restConfig *rest.Config, schema *runtime.Scheme) error {
+	if _, ok := factoryMap[RemoteModeType(config.Mode)]; !ok {
 		return fmt.Errorf("not support remote executor: %s", config.Mode)
 	}
 
+	for mode, factory := range factoryMap {
+		executor, err := factory(config, restConfig, schema)
+		if err != nil {
+			if mode == RemoteModeType(config.Mode) {
+				return fmt.Errorf("create remote executor %s error: %s", mode, err.Error())
+			}
+
+			unavailableErrorMap[mode] = err
+			continue
+		}
+
+		remoteExecutorMap[mode] = executor
+	}
+
+	defaultMode = RemoteModeType(config.Mode)
 	return nil
 }
 
-func GetRemoteExecutor() RemoteExecutor {
-	return globalRemoteExecutor
+// GetRemoteExecutor returns the default remote executor if mode is empty,
+// an unavailable mode returns an executor failing every call with the reason
+func GetRemoteExecutor(mode string) RemoteExecutor {
+	if mode == "" {
+		return remoteExecutorMap[defaultMode]
+	}
+
+	if executor, ok := remoteExecutorMap[RemoteModeType(mode)]; ok {
+		return executor
+	}
+
+	err, ok := unavailableErrorMap[RemoteModeType(mode)]
+	if !ok {
+		err = fmt.Errorf("not registered")
+	}
+
+	return &unavailableExecutor{err: fmt.Errorf("remote executor %s is not available: %s", mode, err.Error())}
+}
+
+// IsPodMode the executor of the mode runs in the target pod, so it is called with the pod instead of the node ip
+func IsPodMode(mode string) bool {
+	if mode == "" {
+		mode = string(defaultMode)
+	}
+
+	return RemoteModeType(mode) == EphemeralRemoteMode || RemoteModeType(mode) == SidecarRemoteMode
+}
+
+func newAgentRemoteExecutor(config *config.ExecutorConfig, restConfig *rest.Config, schema *runtime.Scheme) (RemoteExecutor, error) {
+	return &agentexecutor.AgentRemoteExecutor{
+		Client: &httpclient.HTTPClient{
+			Client: &http.Client{},
+		},
+		Version:       config.Version,
+		ServicePort:   config.AgentConfig.AgentPort,
+		RuntimeConfig: config.AgentConfig.RuntimeConfig,
+	}, nil
+}
+
+func newDaemonsetRemoteExecutor(config *config.ExecutorConfig, restConfig *rest.Config, schema *runtime.Scheme) (RemoteExecutor, error) {
+	return &daemonsetexecutor.DaemonsetRemoteExecutor{
+		//ApiServer:  apiServer,
+		RESTConfig: restConfig,
+		Schema:     schema,
+
+		LocalExecPath: config.DaemonsetConfig.LocalExecPath,
+		Executor:      config.Executor,
+		Version:       config.Version,
+
+		DaemonsetNs:    config.DaemonsetConfig.DaemonNs,
+		DaemonsetLabel: config.DaemonsetConfig.DaemonLabel,
+
+		//AutoLabelNode:     config.DaemonsetConfig.AutoLabelNode,
+		//NodeSelectorLabel: config.DaemonsetConfig.NodeSelectorLabel,
+	}, nil
+}
+
+func newEphemeralRemoteExecutor(config *config.ExecutorConfig, restConfig *rest.Config, schema *runtime.Scheme) (RemoteExecutor, error) {
+	if config.EphemeralConfig.Image == "" {
+		return nil, fmt.Errorf("image of ephemeral container is not provided")
+	}
+
+	return &ephemeralexecutor.EphemeralRemoteExecutor{
+		RESTConfig: restConfig,
+		Schema:     schema,
+
+		Image:         config.EphemeralConfig.Image,
+		LocalExecPath: config.EphemeralConfig.LocalExecPath,
+		Executor:      config.Executor,
+		Version:       config.Version,
+		MaxLifetime:   config.EphemeralConfig.MaxLifetime,
+	}, nil
+}
+
+func newGrpcRemoteExecutor(config *config.ExecutorConfig, restConfig *rest.Config, schema *runtime.Scheme) (RemoteExecutor, error) {
+	if config.DaemonsetConfig.GrpcPort == 0 {
+		return nil, fmt.Errorf("grpc port of daemonset is not provided")
+	}
+
+	token, err := readToken(config.DaemonsetConfig.GrpcTokenPath)
+	if err != nil {
+		return nil, fmt.Errorf("read grpc token of daemonset error: %s", err.Error())
+	}
+
+	serverName := config.DaemonsetConfig.GrpcServerName
+	if serverName == "" {
+		serverName = defaultGrpcServerName
+	}
+
+	tlsConfig, err := grpcexecutor.LoadTLSConfig(config.DaemonsetConfig.GrpcTLSPath, serverName)
+	if err != nil {
+		return nil, fmt.Errorf("load grpc tls config of daemonset error: %s", err.Error())
+	}
+
+	return &grpcexecutor.GrpcRemoteExecutor{
+		Version:        config.Version,
+		Port:           config.DaemonsetConfig.GrpcPort,
+		DaemonsetNs:    config.DaemonsetConfig.DaemonNs,
+		DaemonsetLabel: config.DaemonsetConfig.DaemonLabel,
+		Token:          token,
+		TLSConfig:      tlsConfig,
+	}, nil
+}
+
+// readToken the token file is usually mounted from a secret, an empty token is not accepted
+func readToken(path string) (string, error) {
+	if path == "" {
+		return "", fmt.Errorf("token path is not provided")
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	token := strings.TrimSpace(string(content))
+	if token == "" {
+		return "", fmt.Errorf("token in %s is empty", path)
+	}
+
+	return token, nil
+}
+
+// newSidecarRemoteExecutor the sidecar serves the same grpc service as the DaemonSet agent
+func newSidecarRemoteExecutor(config *config.ExecutorConfig, restConfig *rest.Config, schema *runtime.Scheme) (RemoteExecutor, error) {
+	if config.SidecarConfig.Image == "" {
+		return nil, fmt.Errorf("image of sidecar is not provided")
+	}
+
+	if config.SidecarConfig.GrpcPort == 0 {
+		return nil, fmt.Errorf("grpc port of sidecar is not provided")
+	}
+
+	tlsConfig, err := grpcexecutor.LoadTLSConfig(config.SidecarConfig.TLSPath, sidecar.ServerName)
+	if err != nil {
+		return nil, fmt.Errorf("load tls config of sidecar error: %s", err.Error())
+	}
+
+	// the secrets are not cached by the manager
+	secretClient, err := client.New(restConfig, client.Options{Scheme: schema})
+	if err != nil {
+		return nil, fmt.Errorf("create client error: %s", err.Error())
+	}
+
+	return &grpcexecutor.GrpcRemoteExecutor{
+		Version:   config.Version,
+		Port:      config.SidecarConfig.GrpcPort,
+		TLSConfig: tlsConfig,
+		Sidecar:   true,
+		Client:    secretClient,
+	}, nil
+}
+
+func newSSHRemoteExecutor(config *config.ExecutorConfig, restConfig *rest.Config, schema *runtime.Scheme) (RemoteExecutor, error) {
+	clientConfig, err := sshexecutor.NewClientConfig(&config.SSHConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sshexecutor.SSHRemoteExecutor{
+		Port:          sshexecutor.GetPort(&config.SSHConfig),
+		LocalExecPath: config.SSHConfig.LocalExecPath,
+		Executor:      config.Executor,
+		Version:       config.Version,
+		ClientConfig:  clientConfig,
+	}, nil
+}
+
+type unavailableExecutor struct {
+	err error
+}
+
+func (e *unavailableExecutor) CheckAlive(ctx context.Context, injectObject string) error {
+	return e.err
+}
+
+func (e *unavailableExecutor) Init(ctx context.Context, target string) error {
+	return e.err
+}
+
+func (e *unavailableExecutor) Inject(ctx context.Context, injectObject string, target, fault, uid, timeout, cID, cRuntime string, args []v1alpha1.ArgsUnit) error {
+	return e.err
+}
+
+func (e *unavailableExecutor) Recover(ctx context.Context, injectObject string, uid string) error {
+	return e.err
+}
+
+func (e *unavailableExecutor) Query(ctx context.Context, injectObject string, uid string, phase v1alpha1.PhaseType) (*model.SubExpInfo, error) {
+	return nil, e.err
+}
+
+func (e *unavailableExecutor) Diagnose(ctx context.Context, injectObject string, cRuntime string) (*model.AgentDiagnostics, error) {
+	return nil, e.err
+}
+
+func (e *unavailableExecutor) Snapshot(ctx context.Context, injectObject string, cID, cRuntime string) (*v1alpha1.EnvSnapshot, error) {
+	return nil, e.err
+}
+
+func (e *unavailableExecutor) Preflight(ctx context.Context, injectObject string, target, fault, cID, cRuntime string) ([]string, error) {
+	return nil, e.err
 }
@@ -0,0 +1,117 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package remoteexecutor
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"github.com/stretchr/testify/assert"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/config"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/executor/remoteexecutor/grpcexecutor"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSetGlobalRemoteExecutor(t *testing.T) {
+	err := SetGlobalRemoteExecutor(&config.ExecutorConfig{Mode: "unknown"}, nil, nil)
+	assert.Error(t, err)
+
+	err = SetGlobalRemoteExecutor(&config.ExecutorConfig{Mode: string(GrpcRemoteMode)}, nil, nil)
+	assert.Error(t, err, "the default executor must be created")
+
+	err = SetGlobalRemoteExecutor(&config.ExecutorConfig{
+		Mode:            string(GrpcRemoteMode),
+		Version:         "0.3.9",
+		DaemonsetConfig: config.DaemonsetExecutorConfig{GrpcPort: 29596},
+	}, nil, nil)
+	assert.Error(t, err, "the grpc executor requires a token")
+
+	tokenPath := filepath.Join(t.TempDir(), "token")
+	assert.NoError(t, os.WriteFile(tokenPath, []byte("secret\n"), 0600))
+	err = SetGlobalRemoteExecutor(&config.ExecutorConfig{
+		Mode:            string(GrpcRemoteMode),
+		Version:         "0.3.9",
+		DaemonsetConfig: config.DaemonsetExecutorConfig{GrpcPort: 29596, GrpcTokenPath: tokenPath},
+	}, nil, nil)
+	assert.Error(t, err, "the grpc executor requires the tls secret")
+
+	err = SetGlobalRemoteExecutor(&config.ExecutorConfig{
+		Mode:            string(GrpcRemoteMode),
+		Version:         "0.3.9",
+		DaemonsetConfig: config.DaemonsetExecutorConfig{GrpcPort: 29596, GrpcTokenPath: tokenPath, GrpcTLSPath: writeSelfSignedTLS(t)},
+	}, nil, nil)
+	assert.NoError(t, err)
+
+	executor, ok := GetRemoteExecutor("").(*grpcexecutor.GrpcRemoteExecutor)
+	assert.True(t, ok)
+	assert.Equal(t, "secret", executor.Token)
+	assert.Equal(t, defaultGrpcServerName, executor.TLSConfig.ServerName)
+	_, ok = GetRemoteExecutor(string(GrpcRemoteMode)).(*grpcexecutor.GrpcRemoteExecutor)
+	assert.True(t, ok)
+
+	// ssh is registered but not available without private key
+	err = GetRemoteExecutor(string(SSHRemoteMode)).Recover(context.Background(), "10.0.0.1", "uid1")
+	assert.Error(t, err)
+	assert.True(t, strings.Contains(err.Error(), "private key"), err.Error())
+
+	// ephemeral is registered but not available without image
+	err = GetRemoteExecutor(string(EphemeralRemoteMode)).Recover(context.Background(), "pod/default/pod1/c1", "uid1")
+	assert.Error(t, err)
+	assert.True(t, strings.Contains(err.Error(), "image"), err.Error())
+
+	assert.False(t, IsPodMode(""))
+	assert.False(t, IsPodMode(string(SSHRemoteMode)))
+	assert.True(t, IsPodMode(string(EphemeralRemoteMode)))
+	assert.True(t, IsPodMode(string(SidecarRemoteMode)))
+
+	err = GetRemoteExecutor("unknown").Recover(context.Background(), "10.0.0.1", "uid1")
+	assert.Error(t, err)
+	assert.True(t, strings.Contains(err.Error(), "not registered"), err.Error())
+}
+
+// writeSelfSignedTLS writes a self-signed certificate as both ca.crt and tls.crt
+func writeSelfSignedTLS(t *testing.T) string {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: defaultGrpcServerName},
+		DNSNames:     []string{defaultGrpcServerName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.NoError(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	assert.NoError(t, err)
+
+	dir := t.TempDir()
+	cert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "ca.crt"), cert, 0600))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "tls.crt"), cert, 0600))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "tls.key"), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600))
+	return dir
+}
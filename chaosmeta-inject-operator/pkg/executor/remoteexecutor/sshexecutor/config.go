@@ -0,0 +1,74 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sshexecutor
+
+import (
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/config"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+	"os"
+)
+
+const defaultPort = 22
+
+// NewClientConfig loads the private key and the known hosts of the ssh config
+func NewClientConfig(sshConfig *config.SSHExecutorConfig) (*ssh.ClientConfig, error) {
+	if sshConfig.PrivateKeyPath == "" {
+		return nil, fmt.Errorf("private key of ssh is not provided")
+	}
+
+	// the host keys are always verified, otherwise the private key is offered to whoever answers on the address
+	if sshConfig.KnownHostsPath == "" {
+		return nil, fmt.Errorf("known hosts of ssh is not provided")
+	}
+
+	keyBytes, err := os.ReadFile(sshConfig.PrivateKeyPath)
+	if err != nil {
+		return nil, fmt.Errorf("read private key error: %s", err.Error())
+	}
+
+	signer, err := ssh.ParsePrivateKey(keyBytes)
+	if err != nil {
+		return nil, fmt.Errorf("parse private key error: %s", err.Error())
+	}
+
+	hostKeyCallback, err := knownhosts.New(sshConfig.KnownHostsPath)
+	if err != nil {
+		return nil, fmt.Errorf("load known hosts error: %s", err.Error())
+	}
+
+	user := sshConfig.User
+	if user == "" {
+		user = "root"
+	}
+
+	return &ssh.ClientConfig{
+		User:            user,
+		Auth:            []ssh.AuthMethod{ssh.PublicKeys(signer)},
+		HostKeyCallback: hostKeyCallback,
+		Timeout:         dialTimeout,
+	}, nil
+}
+
+func GetPort(sshConfig *config.SSHExecutorConfig) int {
+	if sshConfig.Port == 0 {
+		return defaultPort
+	}
+
+	return sshConfig.Port
+}
@@ -0,0 +1,195 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sshexecutor
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/common"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/executor/remoteexecutor/base"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/model"
+	"golang.org/x/crypto/ssh"
+	"net"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"strconv"
+	"time"
+)
+
+const dialTimeout = 10 * time.Second
+
+// SSHRemoteExecutor runs the chaosmetad cli installed on the node of target through ssh,
+// for the nodes where the DaemonSet agent can not be deployed
+type SSHRemoteExecutor struct {
+	Port          int
+	LocalExecPath string
+	Executor      string
+	Version       string
+	ClientConfig  *ssh.ClientConfig
+}
+
+func (r *SSHRemoteExecutor) CheckAlive(ctx context.Context, injectObject string) error {
+	stdout, err := r.run(ctx, injectObject, base.GetVersionCmd(r.getExecutor()))
+	if err != nil {
+		return fmt.Errorf("ssh exec error: %s", err.Error())
+	}
+
+	_, err = base.ParseVersionOutput(stdout, r.Version)
+	return err
+}
+
+// Diagnose only measures the agent, the container runtime is not measured through ssh
+func (r *SSHRemoteExecutor) Diagnose(ctx context.Context, injectObject string, cRuntime string) (*model.AgentDiagnostics, error) {
+	start := time.Now()
+	stdout, err := r.run(ctx, injectObject, base.GetVersionCmd(r.getExecutor()))
+	if err != nil {
+		return nil, fmt.Errorf("ssh exec error: %s", err.Error())
+	}
+
+	diagnostics := &model.AgentDiagnostics{AgentResponseTime: time.Since(start)}
+	res, err := base.ParseVersionOutput(stdout, "")
+	if err != nil {
+		return diagnostics, err
+	}
+
+	diagnostics.AgentVersion = res.Version
+	return diagnostics, nil
+}
+
+func (r *SSHRemoteExecutor) Snapshot(ctx context.Context, injectObject string, cID, cRuntime string) (*v1alpha1.EnvSnapshot, error) {
+	stdout, err := r.run(ctx, injectObject, base.GetSnapshotCmd(r.getExecutor(), cID, cRuntime))
+	if err != nil {
+		return nil, fmt.Errorf("ssh exec error: %s", err.Error())
+	}
+
+	return base.ParseSnapshotOutput(stdout)
+}
+
+func (r *SSHRemoteExecutor) Preflight(ctx context.Context, injectObject string, target, fault, cID, cRuntime string) ([]string, error) {
+	stdout, err := r.run(ctx, injectObject, base.GetCapabilityCmd(r.getExecutor(), target, fault, cID, cRuntime))
+	if err != nil {
+		return nil, common.WrapError(err, "ssh exec error")
+	}
+
+	report, err := base.ParseCapabilityOutput(stdout)
+	if err != nil {
+		return nil, err
+	}
+
+	return report.GetUnusableReasons(target, fault), nil
+}
+
+// Init chaosmetad is installed on the hosts in advance
+func (r *SSHRemoteExecutor) Init(ctx context.Context, target string) error {
+	return nil
+}
+
+func (r *SSHRemoteExecutor) Inject(ctx context.Context, injectObject string, target, fault, uid, timeout, cID, cRuntime string, args []v1alpha1.ArgsUnit) error {
+	if _, err := r.run(ctx, injectObject, base.GetInjectCmd(r.getExecutor(), target, fault, uid, timeout, cID, cRuntime, args)); err != nil {
+		return common.WrapError(err, "ssh exec error")
+	}
+
+	return nil
+}
+
+func (r *SSHRemoteExecutor) Recover(ctx context.Context, injectObject string, uid string) error {
+	if _, err := r.run(ctx, injectObject, base.GetRecoverCmd(r.getExecutor(), uid)); err != nil {
+		return common.WrapError(err, "ssh exec error")
+	}
+
+	return nil
+}
+
+func (r *SSHRemoteExecutor) Query(ctx context.Context, injectObject string, uid string, phase v1alpha1.PhaseType) (*model.SubExpInfo, error) {
+	stdout, err := r.run(ctx, injectObject, base.GetQueryCmd(r.getExecutor(), uid))
+	if err != nil {
+		return nil, common.WrapError(err, "ssh exec error")
+	}
+
+	return base.ParseQueryOutput(stdout, uid, phase)
+}
+
+func (r *SSHRemoteExecutor) getExecutor() string {
+	return base.GetExecutorPath(r.LocalExecPath, r.Executor, r.Version)
+}
+
+func (r *SSHRemoteExecutor) run(ctx context.Context, host, cmd string) ([]byte, error) {
+	log.FromContext(ctx).Info(fmt.Sprintf("%s, ssh exec: %s", host, cmd))
+
+	client, err := r.dial(ctx, host)
+	if err != nil {
+		return nil, common.NewCodedError(common.GetAgentUnavailableErrorInfo(), fmt.Errorf("ssh connect to %s error: %s", host, err.Error()))
+	}
+	defer client.Close()
+
+	session, err := client.NewSession()
+	if err != nil {
+		return nil, fmt.Errorf("create ssh session error: %s", err.Error())
+	}
+	defer session.Close()
+
+	var stdout, stderr bytes.Buffer
+	session.Stdout, session.Stderr = &stdout, &stderr
+
+	// the session is interrupted by closing the client when the context is done
+	done := make(chan error, 1)
+	go func() {
+		done <- session.Run(cmd)
+	}()
+
+	select {
+	case <-ctx.Done():
+		return nil, fmt.Errorf("exec remote cmd error: %s", ctx.Err().Error())
+	case err = <-done:
+	}
+
+	if err != nil {
+		execErr := fmt.Errorf("exec remote cmd error: %s %s %s", err.Error(), stdout.String(), stderr.String())
+		// the exit code of chaosmetad is its error code
+		var exitErr *ssh.ExitError
+		if errors.As(err, &exitErr) {
+			return nil, common.NewAgentError(exitErr.ExitStatus(), execErr)
+		}
+
+		return nil, execErr
+	}
+
+	if stderr.String() != "" {
+		return stdout.Bytes(), fmt.Errorf("exec remote cmd get error message: %s", stderr.String())
+	}
+
+	return stdout.Bytes(), nil
+}
+
+func (r *SSHRemoteExecutor) dial(ctx context.Context, host string) (*ssh.Client, error) {
+	addr := net.JoinHostPort(host, strconv.Itoa(r.Port))
+	dialer := &net.Dialer{Timeout: dialTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	c, chans, reqs, err := ssh.NewClientConn(conn, addr, r.ClientConfig)
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+
+	return ssh.NewClient(c, chans, reqs), nil
+}
@@ -0,0 +1,100 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gc
+
+import (
+	"context"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/config"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/model"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"time"
+)
+
+// Collector deletes the finished experiments whose "ttlSecondsAfterFinished" is expired, so that the experiments
+// do not pile up in the cluster
+type Collector struct {
+	client client.Client
+	config config.GCConfig
+}
+
+func NewCollector(c client.Client, gcConfig config.GCConfig) *Collector {
+	return &Collector{client: c, config: gcConfig}
+}
+
+// Start implements manager.Runnable, the expired experiments are collected every interval until the manager stops
+func (c *Collector) Start(ctx context.Context) error {
+	logger, ticker := log.FromContext(ctx), time.NewTicker(time.Duration(c.config.Interval)*time.Second)
+	defer ticker.Stop()
+
+	logger.Info(fmt.Sprintf("start experiment gc success, ticker second: %d", c.config.Interval))
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			c.collect(ctx)
+		}
+	}
+}
+
+func (c *Collector) collect(ctx context.Context) {
+	logger := log.FromContext(ctx)
+	expList := &v1alpha1.ExperimentList{}
+	if err := c.client.List(ctx, expList); err != nil {
+		logger.Error(err, "list experiments for gc error")
+		return
+	}
+
+	now := time.Now()
+	for i := range expList.Items {
+		exp := &expList.Items[i]
+		if !isExpired(exp, now) {
+			continue
+		}
+
+		logger.Info(fmt.Sprintf("experiment: %s/%s, finished at %s, ttl[%ds] expired, start to delete", exp.Namespace, exp.Name, getFinishTime(exp), *exp.Spec.TTLSecondsAfterFinished))
+		if err := c.client.Delete(ctx, exp); err != nil && !errors.IsNotFound(err) {
+			logger.Error(err, fmt.Sprintf("delete expired experiment %s/%s error", exp.Namespace, exp.Name))
+		}
+	}
+}
+
+// isExpired an experiment without ttl is never expired
+func isExpired(exp *v1alpha1.Experiment, now time.Time) bool {
+	if exp.Spec.TTLSecondsAfterFinished == nil || !exp.Status.IsFinished() || !exp.DeletionTimestamp.IsZero() {
+		return false
+	}
+
+	finishTime, err := time.ParseInLocation(model.TimeFormat, getFinishTime(exp), time.Local)
+	if err != nil {
+		return false
+	}
+
+	return !finishTime.Add(time.Duration(*exp.Spec.TTLSecondsAfterFinished) * time.Second).After(now)
+}
+
+// getFinishTime the experiments finished before the finish time is recorded are finished at the last update
+func getFinishTime(exp *v1alpha1.Experiment) string {
+	if exp.Status.FinishTime != "" {
+		return exp.Status.FinishTime
+	}
+	return exp.Status.UpdateTime
+}
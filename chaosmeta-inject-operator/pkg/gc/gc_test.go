@@ -0,0 +1,59 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gc
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/model"
+	"testing"
+	"time"
+)
+
+func TestIsExpired(t *testing.T) {
+	var (
+		now = time.Now()
+		ttl = int32(3600)
+		exp = &v1alpha1.Experiment{
+			Spec: v1alpha1.ExperimentSpec{TTLSecondsAfterFinished: &ttl},
+			Status: v1alpha1.ExperimentStatus{
+				Phase:      v1alpha1.RecoverPhaseType,
+				Status:     v1alpha1.SuccessStatusType,
+				FinishTime: now.Add(-2 * time.Hour).Format(model.TimeFormat),
+				UpdateTime: now.Format(model.TimeFormat),
+			},
+		}
+	)
+
+	assert.True(t, isExpired(exp, now))
+
+	exp.Status.FinishTime = now.Add(-time.Minute).Format(model.TimeFormat)
+	assert.False(t, isExpired(exp, now))
+
+	// finished before the finish time is recorded
+	exp.Status.FinishTime, exp.Status.UpdateTime = "", now.Add(-2*time.Hour).Format(model.TimeFormat)
+	assert.True(t, isExpired(exp, now))
+
+	exp.Status.Paused = true
+	assert.False(t, isExpired(exp, now))
+
+	exp.Status.Paused, exp.Status.Phase = false, v1alpha1.InjectPhaseType
+	assert.False(t, isExpired(exp, now))
+
+	exp.Status.Phase, exp.Spec.TTLSecondsAfterFinished = v1alpha1.RecoverPhaseType, nil
+	assert.False(t, isExpired(exp, now))
+}
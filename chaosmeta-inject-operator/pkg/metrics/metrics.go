@@ -0,0 +1,70 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/common"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const namespace = "chaosmeta_inject"
+
+// Register adds the gauges of the goroutine pool to the metrics endpoint of manager,
+// the depth of the reconcile queue is already exported by controller-runtime as "workqueue_depth"
+func Register() error {
+	collectors := []prometheus.Collector{
+		newPoolGauge("goroutine_pool_size", "The max goroutines solving targets at the same time.", func(pool *common.GoroutinePool) float64 {
+			return float64(pool.GetSize())
+		}),
+		newPoolGauge("goroutine_pool_in_use", "The goroutines solving targets now.", func(pool *common.GoroutinePool) float64 {
+			return float64(pool.GetLen())
+		}),
+		newPoolGauge("goroutine_pool_waiting", "The targets waiting for a goroutine of the pool.", func(pool *common.GoroutinePool) float64 {
+			return float64(pool.GetWaiting())
+		}),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "target_concurrency",
+			Help:      "The max targets of one experiment solved at the same time, 0 means only limited by the pool.",
+		}, func() float64 {
+			return float64(common.GetTargetConcurrency())
+		}),
+	}
+
+	for _, c := range collectors {
+		if err := ctrlmetrics.Registry.Register(c); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func newPoolGauge(name, help string, value func(pool *common.GoroutinePool) float64) prometheus.GaugeFunc {
+	return prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      name,
+		Help:      help,
+	}, func() float64 {
+		pool := common.GetGoroutinePool()
+		if pool == nil {
+			return 0
+		}
+		return value(pool)
+	})
+}
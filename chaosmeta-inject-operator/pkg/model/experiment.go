@@ -16,7 +16,10 @@
 
 package model
 
-import "github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+import (
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"time"
+)
 
 type SubExpInfo struct {
 	UID        string              `json:"uid"`
@@ -26,6 +29,14 @@ type SubExpInfo struct {
 	Message    string              `json:"error,omitempty"`
 }
 
+// AgentDiagnostics is gathered from the agent of a target whose injection exceeds the latency SLO
+type AgentDiagnostics struct {
+	AgentVersion      string
+	AgentResponseTime time.Duration
+	// RuntimeResponseTime is zero if the executor can not reach the container runtime
+	RuntimeResponseTime time.Duration
+}
+
 const (
 	TimeFormat    = "2006-01-02 15:04:05"
 	DockerRuntime = "docker"
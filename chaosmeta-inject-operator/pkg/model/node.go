@@ -27,6 +27,10 @@ type NodeObject struct {
 	HostName         string
 	ContainerID      string
 	ContainerRuntime string
+	// Labels is only used to filter the objects when resolving the selector
+	Labels map[string]string
+	// Executor is the remote executor of the target, empty is the default one
+	Executor string
 }
 
 func (n *NodeObject) GetObjectName() string {
@@ -33,6 +33,17 @@ type PodObject struct {
 	ContainerName    string
 	ContainerID      string
 	ContainerRuntime string
+	ContainerImage   string
+	// Labels is only used to filter the objects when resolving the selector
+	Labels map[string]string
+	// Phase, QOSClass and RestartCount are only used to filter the objects by the field selector
+	Phase        string
+	QOSClass     string
+	RestartCount int32
+	// Annotations is only set by GetPod
+	Annotations map[string]string
+	// Executor is the remote executor of the target, empty is the default one
+	Executor string
 }
 
 func (p *PodObject) GetObjectName() string {
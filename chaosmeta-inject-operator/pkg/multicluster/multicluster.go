@@ -0,0 +1,166 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package multicluster
+
+import (
+	"context"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sync"
+)
+
+var (
+	registryClient    client.Client
+	registryNamespace string
+	clusterScheme     *runtime.Scheme
+	clusterLock       sync.Mutex
+	clusterMap        = make(map[string]*Cluster)
+)
+
+// Cluster is a cluster registered in the ClusterRegistry, the experiments referring to it select and inject
+// the targets by its clients instead of the clients of the operator
+type Cluster struct {
+	Name       string
+	RESTConfig *rest.Config
+	Client     client.Client
+	// version is the uid and resource version of the kubeconfig secret, the clients are created again once it changes
+	version string
+}
+
+type clusterKey struct{}
+
+// SetupRegistry sets the client reading the ClusterRegistry and the kubeconfig secrets in the cluster of the operator.
+// Only the namespace of the operator is read, so the users creating experiments in their namespaces can not register
+// the clusters or refer to the kubeconfig of others
+func SetupRegistry(apiServer client.Client, scheme *runtime.Scheme, namespace string) {
+	registryClient, clusterScheme, registryNamespace = apiServer, scheme, namespace
+}
+
+// WithCluster returns a copy of ctx in which the targets are selected and injected in the cluster
+func WithCluster(ctx context.Context, c *Cluster) context.Context {
+	return context.WithValue(ctx, clusterKey{}, c)
+}
+
+// FromContext returns the cluster of ctx, nil is the cluster of the operator
+func FromContext(ctx context.Context) *Cluster {
+	c, _ := ctx.Value(clusterKey{}).(*Cluster)
+	return c
+}
+
+// GetRESTConfig returns the rest config of the cluster in ctx, or the default one for the cluster of the operator
+func GetRESTConfig(ctx context.Context, defaultConfig *rest.Config) *rest.Config {
+	if c := FromContext(ctx); c != nil {
+		return c.RESTConfig
+	}
+
+	return defaultConfig
+}
+
+// GetCluster finds the cluster in the registries of the operator namespace, its clients are cached until the kubeconfig
+// secret is updated
+func GetCluster(ctx context.Context, name string) (*Cluster, error) {
+	if registryNamespace == "" {
+		return nil, fmt.Errorf("namespace of operator is unknown, cluster registry is not available")
+	}
+
+	registryList := &v1alpha1.ClusterRegistryList{}
+	if err := registryClient.List(ctx, registryList, client.InNamespace(registryNamespace)); err != nil {
+		return nil, fmt.Errorf("list cluster registry error: %s", err.Error())
+	}
+
+	ref, err := findCluster(registryList.Items, name)
+	if err != nil {
+		return nil, err
+	}
+
+	secret := &corev1.Secret{}
+	if err := registryClient.Get(ctx, client.ObjectKey{Namespace: registryNamespace, Name: ref.Name}, secret); err != nil {
+		return nil, fmt.Errorf("get kubeconfig secret %s/%s error: %s", registryNamespace, ref.Name, err.Error())
+	}
+
+	version := fmt.Sprintf("%s/%s", secret.UID, secret.ResourceVersion)
+	clusterLock.Lock()
+	defer clusterLock.Unlock()
+	if c, ok := clusterMap[name]; ok && c.version == version {
+		return c, nil
+	}
+
+	c, err := newCluster(name, secret, ref.Key)
+	if err != nil {
+		return nil, fmt.Errorf("create clients of cluster %s error: %s", name, err.Error())
+	}
+
+	c.version = version
+	clusterMap[name] = c
+	return c, nil
+}
+
+// findCluster returns the kubeconfig secret of the cluster
+func findCluster(registries []v1alpha1.ClusterRegistry, name string) (*v1alpha1.KubeConfigSecretRef, error) {
+	var ref *v1alpha1.KubeConfigSecretRef
+	for i := range registries {
+		for j := range registries[i].Spec.Clusters {
+			if registries[i].Spec.Clusters[j].Name != name {
+				continue
+			}
+
+			if ref != nil {
+				return nil, fmt.Errorf("cluster %s is registered more than once", name)
+			}
+
+			ref = &registries[i].Spec.Clusters[j].KubeConfigSecret
+		}
+	}
+
+	if ref == nil {
+		return nil, fmt.Errorf("cluster %s is not registered", name)
+	}
+
+	return ref, nil
+}
+
+func newCluster(name string, secret *corev1.Secret, key string) (*Cluster, error) {
+	if key == "" {
+		key = v1alpha1.DefaultKubeConfigKey
+	}
+
+	kubeConfig, ok := secret.Data[key]
+	if !ok {
+		return nil, fmt.Errorf("key %s is not found in secret %s/%s", key, secret.Namespace, secret.Name)
+	}
+
+	restConfig, err := clientcmd.RESTConfigFromKubeConfig(kubeConfig)
+	if err != nil {
+		return nil, fmt.Errorf("parse kubeconfig error: %s", err.Error())
+	}
+
+	apiServer, err := client.New(restConfig, client.Options{Scheme: clusterScheme})
+	if err != nil {
+		return nil, fmt.Errorf("create client error: %s", err.Error())
+	}
+
+	return &Cluster{
+		Name:       name,
+		RESTConfig: restConfig,
+		Client:     apiServer,
+	}, nil
+}
@@ -0,0 +1,104 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package multicluster
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"testing"
+)
+
+func newRegistry(namespace, name string, clusters ...v1alpha1.RegisteredCluster) *v1alpha1.ClusterRegistry {
+	return &v1alpha1.ClusterRegistry{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Spec:       v1alpha1.ClusterRegistrySpec{Clusters: clusters},
+	}
+}
+
+func Test_findCluster(t *testing.T) {
+	registries := []v1alpha1.ClusterRegistry{
+		*newRegistry("ns1", "r1", v1alpha1.RegisteredCluster{Name: "a", KubeConfigSecret: v1alpha1.KubeConfigSecretRef{Name: "a-kubeconfig"}}),
+		*newRegistry("ns1", "r2",
+			v1alpha1.RegisteredCluster{Name: "b", KubeConfigSecret: v1alpha1.KubeConfigSecretRef{Name: "b-kubeconfig", Key: "config"}},
+			v1alpha1.RegisteredCluster{Name: "c", KubeConfigSecret: v1alpha1.KubeConfigSecretRef{Name: "c-kubeconfig"}}),
+		*newRegistry("ns1", "r3", v1alpha1.RegisteredCluster{Name: "c", KubeConfigSecret: v1alpha1.KubeConfigSecretRef{Name: "c-kubeconfig"}}),
+	}
+
+	ref, err := findCluster(registries, "b")
+	assert.NoError(t, err)
+	assert.Equal(t, "b-kubeconfig", ref.Name)
+	assert.Equal(t, "config", ref.Key)
+
+	_, err = findCluster(registries, "c")
+	assert.EqualError(t, err, "cluster c is registered more than once")
+
+	_, err = findCluster(registries, "d")
+	assert.EqualError(t, err, "cluster d is not registered")
+}
+
+func TestGetCluster_Error(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = v1alpha1.AddToScheme(scheme)
+	SetupRegistry(fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		newRegistry("chaosmeta", "clusters",
+			v1alpha1.RegisteredCluster{Name: "a", KubeConfigSecret: v1alpha1.KubeConfigSecretRef{Name: "a-kubeconfig"}},
+			v1alpha1.RegisteredCluster{Name: "b", KubeConfigSecret: v1alpha1.KubeConfigSecretRef{Name: "b-kubeconfig"}}),
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "chaosmeta", Name: "a-kubeconfig"},
+			Data:       map[string][]byte{"config": []byte("")},
+		},
+		// the registries of other namespaces are never read
+		newRegistry("tenant", "clusters",
+			v1alpha1.RegisteredCluster{Name: "a", KubeConfigSecret: v1alpha1.KubeConfigSecretRef{Name: "a-kubeconfig"}},
+			v1alpha1.RegisteredCluster{Name: "c", KubeConfigSecret: v1alpha1.KubeConfigSecretRef{Name: "c-kubeconfig"}}),
+	).Build(), scheme, "chaosmeta")
+
+	_, err := GetCluster(context.Background(), "a")
+	assert.EqualError(t, err, "create clients of cluster a error: key kubeconfig is not found in secret chaosmeta/a-kubeconfig")
+
+	_, err = GetCluster(context.Background(), "b")
+	assert.ErrorContains(t, err, "get kubeconfig secret chaosmeta/b-kubeconfig error")
+
+	_, err = GetCluster(context.Background(), "c")
+	assert.EqualError(t, err, "cluster c is not registered")
+
+	SetupRegistry(fake.NewClientBuilder().WithScheme(scheme).Build(), scheme, "")
+	_, err = GetCluster(context.Background(), "a")
+	assert.ErrorContains(t, err, "namespace of operator is unknown")
+}
+
+func TestGetRESTConfig(t *testing.T) {
+	var (
+		defaultConfig = &rest.Config{Host: "https://10.0.0.1"}
+		c             = &Cluster{Name: "a", RESTConfig: &rest.Config{Host: "https://10.0.0.2"}}
+	)
+
+	assert.Nil(t, FromContext(context.Background()))
+	assert.Equal(t, defaultConfig, GetRESTConfig(context.Background(), defaultConfig))
+
+	ctx := WithCluster(context.Background(), c)
+	assert.Equal(t, c, FromContext(ctx))
+	assert.Equal(t, c.RESTConfig, GetRESTConfig(ctx, defaultConfig))
+}
@@ -42,35 +42,55 @@ func (h *InjectPhaseHandler) SolveCreated(ctx context.Context, exp *v1alpha1.Exp
 		logger.Error(err, "check if experiment timeout error")
 	}
 
+	if exp.Spec.Preflight && !isTimeout && !solvePreflight(ctx, exp) {
+		return
+	}
+
 	var (
 		targetSubExp = exp.Status.Detail.Inject
 		wg           = sync.WaitGroup{}
+		limiter      = common.NewTargetLimiter()
 	)
 
 	for i := range exp.Status.Detail.Inject {
-		if targetSubExp[i].Status != v1alpha1.CreatedStatusType {
+		if targetSubExp[i].Status != v1alpha1.CreatedStatusType || common.GetRetryWait(&targetSubExp[i], time.Now()) > 0 {
 			continue
 		}
 
+		limiter.Acquire()
 		common.GetGoroutinePool().GetGoroutine()
 		wg.Add(1)
-		go solveCreated(ctx, &wg, exp, i, isTimeout)
+		go func(i int) {
+			defer limiter.Release()
+			solveCreated(ctx, &wg, exp, i, isTimeout)
+		}(i)
 	}
 
 	wg.Wait()
+	aborted := isAborted(exp)
+	if aborted {
+		abortCreatedTargets(exp)
+	}
+
 	// Summarize subtask execution results
-	var failCount, createdCount int
+	var failCount, createdCount, slowCount int
 	for i := range targetSubExp {
 		if targetSubExp[i].Status == v1alpha1.FailedStatusType {
 			failCount++
 		} else if targetSubExp[i].Status == v1alpha1.CreatedStatusType {
 			createdCount++
 		}
+
+		if targetSubExp[i].Latency != nil && targetSubExp[i].Latency.ExceedSLO {
+			slowCount++
+		}
 	}
 
-	logger.Info(fmt.Sprintf("experiment: %s/%s, SolveCreated: totalCount[%d], failCount[%d], createdCount[%d]", exp.Namespace, exp.Name, len(targetSubExp), failCount, createdCount))
+	logger.Info(fmt.Sprintf("experiment: %s/%s, SolveCreated: totalCount[%d], failCount[%d], createdCount[%d], slowCount[%d]", exp.Namespace, exp.Name, len(targetSubExp), failCount, createdCount, slowCount))
 	// Update the overall task status
-	if createdCount > 0 {
+	if aborted {
+		exp.Status.Status, exp.Status.Message = v1alpha1.FailedStatusType, fmt.Sprintf("create failed, abort by failure policy: %s", exp.Spec.FailurePolicy)
+	} else if createdCount > 0 {
 		exp.Status.Status, exp.Status.Message = v1alpha1.CreatedStatusType, "created count is more than 0, need to retry"
 	} else {
 		if failCount == len(targetSubExp) {
@@ -80,6 +100,10 @@ func (h *InjectPhaseHandler) SolveCreated(ctx context.Context, exp *v1alpha1.Exp
 		}
 	}
 
+	if slowCount > 0 {
+		exp.Status.Message = fmt.Sprintf("%s, %d targets exceed inject latency SLO[%s], see latency in detail", exp.Status.Message, slowCount, common.GetInjectLatencySLO())
+	}
+
 	exp.Status.UpdateTime = time.Now().Format(model.TimeFormat)
 }
 
@@ -90,32 +114,59 @@ func solveCreated(ctx context.Context, wg *sync.WaitGroup, exp *v1alpha1.Experim
 		scopeHandler = scopehandler.GetScopeHandler(exp.Spec.Scope)
 		commonObject model.AtomicObject
 		err          error
+		latency      = &v1alpha1.InjectLatency{}
+		start        = time.Now()
 	)
 
 	logger.Info(fmt.Sprintf("experiment: %s/%s/%s, solveCreated start, now Goroutine: %d", exp.Namespace, exp.Name, targetSubExp[i].InjectObjectName, common.GetGoroutinePool().GetLen()))
 
 	defer func() {
+		recordLatency(ctx, scopeHandler, commonObject, latency, time.Since(start))
+		targetSubExp[i].Latency = latency
+		common.RecordLastError(&targetSubExp[i])
+		if !isTimeout {
+			retryFailedTarget(exp, i)
+		}
+		// the error of a previous round is stale once the target is not failed
+		if targetSubExp[i].Status != v1alpha1.FailedStatusType {
+			targetSubExp[i].Error = nil
+		}
 		common.GetGoroutinePool().ReleaseGoroutine()
 		wg.Done()
 		logger.Info(fmt.Sprintf("experiment: %s/%s/%s, solveCreated finish, status: %s, now Goroutine: %d", exp.Namespace, exp.Name, targetSubExp[i].InjectObjectName, targetSubExp[i].Status, common.GetGoroutinePool().GetLen()))
 	}()
 
-	commonObject, err = scopeHandler.GetInjectObject(ctx, exp.Spec.Experiment, targetSubExp[i].InjectObjectName)
+	targetSubExp[i].NextRetryTime = ""
+	commonObject, err = scopeHandler.GetInjectObject(ctx, common.GetTargetArgs(exp.Spec.Experiment, &targetSubExp[i]), targetSubExp[i].InjectObjectName)
+	latency.GetObjectDuration = time.Since(start).String()
 	if err != nil {
 		if common.IsNetErr(err) {
 			targetSubExp[i].Status, targetSubExp[i].Message = v1alpha1.CreatedStatusType, "GetInjectObject network error, need to retry"
 			if isTimeout {
 				targetSubExp[i].Status, targetSubExp[i].Message = v1alpha1.FailedStatusType, "GetInjectObject network error, timeout"
+				targetSubExp[i].Error = common.GetTimeoutErrorInfo()
 			}
 		} else {
 			// include not found
 			targetSubExp[i].Status, targetSubExp[i].Message = v1alpha1.FailedStatusType, fmt.Sprintf("GetInjectObject error: %s", err.Error())
+			targetSubExp[i].Error = common.GetErrorInfo(err)
 		}
 
 		return
 	}
 
-	backup, err := scopeHandler.ExecuteInject(ctx, commonObject, targetSubExp[i].UID, exp.Spec.Experiment)
+	if exp.Spec.Snapshot && targetSubExp[i].Snapshot == nil {
+		targetSubExp[i].Snapshot = scopehandler.TakeSnapshot(ctx, scopeHandler, commonObject)
+	}
+
+	if targetSubExp[i].InjectAttempts > 1 && isInjectionInFlight(ctx, scopeHandler, commonObject, exp, i) {
+		targetSubExp[i].Status, targetSubExp[i].Message = v1alpha1.RunningStatusType, "adopt the injection of a previous attempt"
+		return
+	}
+
+	execStart := time.Now()
+	backup, err := scopeHandler.ExecuteInject(ctx, commonObject, targetSubExp[i].UID, getInjectArgs(exp))
+	latency.ExecDuration = time.Since(execStart).String()
 	if err != nil {
 		if common.IsKeyUniqueErr(err) {
 			targetSubExp[i].Status, targetSubExp[i].Message = v1alpha1.RunningStatusType, "experiment start success"
@@ -123,15 +174,111 @@ func solveCreated(ctx context.Context, wg *sync.WaitGroup, exp *v1alpha1.Experim
 			targetSubExp[i].Status, targetSubExp[i].Message = v1alpha1.CreatedStatusType, "experiment inject network error, need to retry"
 			if isTimeout {
 				targetSubExp[i].Status, targetSubExp[i].Message = v1alpha1.FailedStatusType, "experiment inject network error, timeout"
+				targetSubExp[i].Error = common.GetTimeoutErrorInfo()
 			}
 		} else {
 			targetSubExp[i].Status, targetSubExp[i].Message = v1alpha1.FailedStatusType, fmt.Sprintf("experiment inject error: %s", err.Error())
+			targetSubExp[i].Error = common.GetErrorInfo(err)
 		}
 	} else {
 		targetSubExp[i].Backup, targetSubExp[i].Status, targetSubExp[i].Message = backup, v1alpha1.RunningStatusType, "experiment inject start success"
 	}
 }
 
+// isAborted returns whether the failure policy stops the experiment because some targets fail to inject
+func isAborted(exp *v1alpha1.Experiment) bool {
+	if exp.Spec.FailurePolicy != v1alpha1.AbortFailurePolicyType && exp.Spec.FailurePolicy != v1alpha1.RollbackFailurePolicyType {
+		return false
+	}
+
+	for i := range exp.Status.Detail.Inject {
+		if exp.Status.Detail.Inject[i].Status == v1alpha1.FailedStatusType {
+			return true
+		}
+	}
+
+	return false
+}
+
+// abortCreatedTargets gives up the targets not injected yet, including the targets waiting for a retry
+func abortCreatedTargets(exp *v1alpha1.Experiment) {
+	for i := range exp.Status.Detail.Inject {
+		if exp.Status.Detail.Inject[i].Status == v1alpha1.CreatedStatusType {
+			exp.Status.Detail.Inject[i].Status, exp.Status.Detail.Inject[i].Message = v1alpha1.FailedStatusType, "not injected, aborted by failure policy"
+			exp.Status.Detail.Inject[i].NextRetryTime = ""
+		}
+	}
+}
+
+// retryFailedTarget keeps a target failed by a retryable error created until its next retry, if the retry policy allows
+func retryFailedTarget(exp *v1alpha1.Experiment, i int) {
+	unit, policy := &exp.Status.Detail.Inject[i], exp.Spec.RetryPolicy
+	if unit.Status != v1alpha1.FailedStatusType || unit.Error == nil || !unit.Error.Retryable {
+		return
+	}
+
+	if !common.IsRetryLeft(policy, unit.InjectAttempts) {
+		if policy != nil {
+			unit.Message = fmt.Sprintf("%s, no retry left after %d attempts", unit.Message, unit.InjectAttempts)
+		}
+		return
+	}
+
+	backoff := common.GetRetryBackoff(policy, unit.InjectAttempts)
+	unit.Status, unit.NextRetryTime = v1alpha1.CreatedStatusType, time.Now().Add(backoff).Format(model.TimeFormat)
+	unit.Message = fmt.Sprintf("%s, retry %d/%d after %s", unit.Message, unit.InjectAttempts, policy.MaxRetries, backoff)
+}
+
+// isInjectionInFlight the result of a previous attempt is unknown, such as a network error or a failover of the operator,
+// so the target is queried by its uid before injecting again. The cloud native faults keep their state in the backup
+// returned by the inject call, which can not be queried without it
+func isInjectionInFlight(ctx context.Context, scopeHandler scopehandler.ScopeHandler, injectObject model.AtomicObject, exp *v1alpha1.Experiment, i int) bool {
+	if exp.Spec.Scope == v1alpha1.KubernetesScopeType {
+		return false
+	}
+
+	unit := exp.Status.Detail.Inject[i]
+	expInfo, err := scopeHandler.QueryExperiment(ctx, injectObject, unit.UID, unit.Backup, exp.Spec.Experiment, v1alpha1.InjectPhaseType)
+	if err != nil || expInfo == nil {
+		return false
+	}
+
+	if expInfo.Status != v1alpha1.RunningStatusType && expInfo.Status != v1alpha1.SuccessStatusType {
+		return false
+	}
+
+	log.FromContext(ctx).Info(fmt.Sprintf("experiment: %s/%s/%s, adopt the injection in flight, status: %s", exp.Namespace, exp.Name, unit.InjectObjectName, expInfo.Status))
+	return true
+}
+
+// recordLatency compares the total duration with the SLO, and gathers diagnostics from the agent of a slow target
+func recordLatency(ctx context.Context, scopeHandler scopehandler.ScopeHandler, injectObject model.AtomicObject, latency *v1alpha1.InjectLatency, total time.Duration) {
+	latency.TotalDuration = total.String()
+	if !common.IsInjectLatencyExceed(total) {
+		return
+	}
+
+	latency.ExceedSLO = true
+	if injectObject == nil {
+		latency.DiagnoseMessage = "inject object not found, skip diagnose"
+		return
+	}
+
+	diagnostics, err := scopeHandler.Diagnose(ctx, injectObject)
+	if diagnostics != nil {
+		latency.AgentVersion = diagnostics.AgentVersion
+		latency.AgentResponseTime = diagnostics.AgentResponseTime.String()
+		if diagnostics.RuntimeResponseTime > 0 {
+			latency.RuntimeResponseTime = diagnostics.RuntimeResponseTime.String()
+		}
+	}
+
+	if err != nil {
+		latency.DiagnoseMessage = fmt.Sprintf("diagnose error: %s", err.Error())
+		log.FromContext(ctx).Error(err, fmt.Sprintf("diagnose slow target %s error", injectObject.GetObjectName()))
+	}
+}
+
 func (h *InjectPhaseHandler) SolveRunning(ctx context.Context, exp *v1alpha1.Experiment) {
 	logger := log.FromContext(ctx)
 	logger.Info(fmt.Sprintf("experiment: %s/%s, SolveRunning start", exp.Namespace, exp.Name))
@@ -146,6 +293,7 @@ func (h *InjectPhaseHandler) SolveRunning(ctx context.Context, exp *v1alpha1.Exp
 	var (
 		targetSubExp = exp.Status.Detail.Inject
 		wg           = sync.WaitGroup{}
+		limiter      = common.NewTargetLimiter()
 	)
 
 	for i := range targetSubExp {
@@ -153,13 +301,25 @@ func (h *InjectPhaseHandler) SolveRunning(ctx context.Context, exp *v1alpha1.Exp
 			continue
 		}
 
+		limiter.Acquire()
 		common.GetGoroutinePool().GetGoroutine()
 		wg.Add(1)
-		go solveRunning(ctx, &wg, exp, i, isTimeout)
+		go func(i int) {
+			defer limiter.Release()
+			solveRunning(ctx, &wg, exp, i, isTimeout)
+		}(i)
 	}
 
 	wg.Wait()
 
+	var newCount int
+	aborted := isAborted(exp)
+	if !aborted && !isTimeout && scopehandler.IsReresolvable(&exp.Spec) && isResolveDue(exp) {
+		newCount = reresolveTargets(ctx, exp)
+		targetSubExp = exp.Status.Detail.Inject
+		exp.Status.LastResolveTime = time.Now().Format(model.TimeFormat)
+	}
+
 	var runCount, failCount int
 	for i := range targetSubExp {
 		if targetSubExp[i].Status == v1alpha1.RunningStatusType {
@@ -169,9 +329,13 @@ func (h *InjectPhaseHandler) SolveRunning(ctx context.Context, exp *v1alpha1.Exp
 		}
 	}
 
-	logger.Info(fmt.Sprintf("experiment: %s/%s, SolveRunning: totalCount[%d], failCount[%d], runCount[%d]", exp.Namespace, exp.Name, len(targetSubExp), failCount, runCount))
+	logger.Info(fmt.Sprintf("experiment: %s/%s, SolveRunning: totalCount[%d], failCount[%d], runCount[%d], newCount[%d]", exp.Namespace, exp.Name, len(targetSubExp), failCount, runCount, newCount))
 
-	if runCount > 0 {
+	if aborted {
+		exp.Status.Status, exp.Status.Message = v1alpha1.FailedStatusType, fmt.Sprintf("run failed, abort by failure policy: %s", exp.Spec.FailurePolicy)
+	} else if newCount > 0 {
+		exp.Status.Status, exp.Status.Message = v1alpha1.CreatedStatusType, fmt.Sprintf("re-resolved %d new targets, start to inject", newCount)
+	} else if runCount > 0 {
 		exp.Status.Status, exp.Status.Message = v1alpha1.RunningStatusType, "run count is more than 0, need to retry"
 	} else {
 		if failCount == 0 {
@@ -199,22 +363,30 @@ func solveRunning(ctx context.Context, wg *sync.WaitGroup, exp *v1alpha1.Experim
 	logger.Info(fmt.Sprintf("experiment: %s/%s/%s, solveRunning start, now Goroutine: %d", exp.Namespace, exp.Name, targetSubExp[i].InjectObjectName, common.GetGoroutinePool().GetLen()))
 
 	defer func() {
+		common.RecordLastError(&targetSubExp[i])
+		// the error of a previous round is stale once the target is not failed
+		if targetSubExp[i].Status != v1alpha1.FailedStatusType {
+			targetSubExp[i].Error = nil
+		}
 		common.GetGoroutinePool().ReleaseGoroutine()
 		wg.Done()
 		logger.Info(fmt.Sprintf("experiment: %s/%s/%s, solveRunning finish, status: %s, now Goroutine: %d", exp.Namespace, exp.Name, targetSubExp[i].InjectObjectName, targetSubExp[i].Status, common.GetGoroutinePool().GetLen()))
 	}()
 
-	commonObject, err = scopeHandler.GetInjectObject(ctx, exp.Spec.Experiment, targetSubExp[i].InjectObjectName)
+	commonObject, err = scopeHandler.GetInjectObject(ctx, common.GetTargetArgs(exp.Spec.Experiment, &targetSubExp[i]), targetSubExp[i].InjectObjectName)
 	if err != nil {
 		if common.IsNetErr(err) {
 			targetSubExp[i].Status, targetSubExp[i].Message = v1alpha1.RunningStatusType, "GetInjectObject network error, need to retry"
 			if isTimeout {
 				targetSubExp[i].Status, targetSubExp[i].Message = v1alpha1.FailedStatusType, "GetInjectObject network error, timeout"
+				targetSubExp[i].Error = common.GetTimeoutErrorInfo()
 			}
 		} else if common.IsNotFoundErr(err) {
 			targetSubExp[i].Status, targetSubExp[i].Message = v1alpha1.SuccessStatusType, err.Error()
+			markTargetGone(&targetSubExp[i])
 		} else {
 			targetSubExp[i].Status, targetSubExp[i].Message = v1alpha1.FailedStatusType, fmt.Sprintf("GetInjectObject error: %s", err.Error())
+			targetSubExp[i].Error = common.GetErrorInfo(err)
 		}
 
 		return
@@ -226,9 +398,11 @@ func solveRunning(ctx context.Context, wg *sync.WaitGroup, exp *v1alpha1.Experim
 			targetSubExp[i].Status, targetSubExp[i].Message = v1alpha1.RunningStatusType, "experiment query network error, need to retry"
 			if isTimeout {
 				targetSubExp[i].Status, targetSubExp[i].Message = v1alpha1.FailedStatusType, "experiment query network error, timeout"
+				targetSubExp[i].Error = common.GetTimeoutErrorInfo()
 			}
 		} else {
 			targetSubExp[i].Status, targetSubExp[i].Message = v1alpha1.FailedStatusType, fmt.Sprintf("experiment query error: %s", err.Error())
+			targetSubExp[i].Error = common.GetErrorInfo(err)
 			targetSubExp[i].UpdateTime = time.Now().Format(model.TimeFormat)
 		}
 
@@ -237,6 +411,9 @@ func solveRunning(ctx context.Context, wg *sync.WaitGroup, exp *v1alpha1.Experim
 		if expInfo.Status == v1alpha1.SuccessStatusType || expInfo.Status == v1alpha1.FailedStatusType || expInfo.Status == v1alpha1.RunningStatusType {
 			targetSubExp[i].StartTime, targetSubExp[i].UpdateTime = expInfo.CreateTime, expInfo.UpdateTime
 			targetSubExp[i].Status, targetSubExp[i].Message = expInfo.Status, expInfo.Message
+			if expInfo.Status == v1alpha1.FailedStatusType {
+				targetSubExp[i].Error = common.GetAgentFailedErrorInfo(v1alpha1.InjectPhaseType)
+			}
 		} else {
 			logger.Error(fmt.Errorf("unexpected status"), fmt.Sprintf("expInfo.Status is %s", expInfo.Status))
 			return
@@ -244,14 +421,192 @@ func solveRunning(ctx context.Context, wg *sync.WaitGroup, exp *v1alpha1.Experim
 	}
 }
 
+// getInjectArgs a resumed experiment only injects for the rest of its duration
+func getInjectArgs(exp *v1alpha1.Experiment) *v1alpha1.ExperimentCommon {
+	if exp.Status.ResumeTime == "" || exp.Spec.Experiment.Duration == "" {
+		return exp.Spec.Experiment
+	}
+
+	duration, err := v1alpha1.ConvertDuration(exp.Spec.Experiment.Duration)
+	if err != nil {
+		return exp.Spec.Experiment
+	}
+
+	createTime, err := time.ParseInLocation(model.TimeFormat, exp.Status.CreateTime, time.Local)
+	if err != nil {
+		return exp.Spec.Experiment
+	}
+
+	remaining := time.Until(createTime.Add(duration))
+	if remaining < time.Second {
+		remaining = time.Second
+	}
+
+	args := exp.Spec.Experiment.DeepCopy()
+	args.Duration = fmt.Sprintf("%ds", int64(remaining/time.Second))
+	return args
+}
+
+// getTargetKey identifies a target by the name and the UID of its pod
+func getTargetKey(name string, resolved *v1alpha1.ResolvedTarget) string {
+	if resolved == nil || resolved.PodUID == "" {
+		return name
+	}
+
+	return fmt.Sprintf("%s/%s", name, resolved.PodUID)
+}
+
+// isRecreated reports whether the pod of the target is replaced by another one with the same name
+func isRecreated(target *v1alpha1.ExperimentDetailUnit, obj model.AtomicObject) bool {
+	if target.Resolved == nil || target.Resolved.PodUID == "" {
+		return false
+	}
+
+	resolved := scopehandler.GetResolvedTarget(obj, "")
+	return resolved != nil && resolved.PodUID != "" && resolved.PodUID != target.Resolved.PodUID
+}
+
+func isResolveDue(exp *v1alpha1.Experiment) bool {
+	lastTime := exp.Status.LastResolveTime
+	if lastTime == "" {
+		lastTime = exp.Status.CreateTime
+	}
+
+	last, err := time.ParseInLocation(model.TimeFormat, lastTime, time.Local)
+	if err != nil {
+		return true
+	}
+
+	return !last.Add(scopehandler.GetReresolveInterval(&exp.Spec)).After(time.Now())
+}
+
+// reresolveTargets appends the pods not selected yet as created targets, so they are injected in the next round.
+// A pod recreated with the same name, such as a pod of a StatefulSet, is a new pod as its UID changes.
+// Unless the range mode is all, a new pod only takes the place of a target whose pod is gone,
+// which keeps the number of targets selected at the start
+func reresolveTargets(ctx context.Context, exp *v1alpha1.Experiment) int {
+	var (
+		logger       = log.FromContext(ctx)
+		scopeHandler = scopehandler.GetScopeHandler(exp.Spec.Scope)
+		targetSubExp = exp.Status.Detail.Inject
+		isExist      = make(map[string]bool)
+		newObjects   []model.AtomicObject
+	)
+
+	injectObjects, err := scopeHandler.ConvertSelector(ctx, &exp.Spec)
+	if err != nil {
+		logger.Error(err, fmt.Sprintf("experiment: %s/%s, re-resolve targets error", exp.Namespace, exp.Name))
+		return 0
+	}
+
+	for i := range targetSubExp {
+		isExist[getTargetKey(targetSubExp[i].InjectObjectName, targetSubExp[i].Resolved)] = true
+	}
+
+	for _, unitObj := range injectObjects {
+		if !isExist[getTargetKey(unitObj.GetObjectName(), scopehandler.GetResolvedTarget(unitObj, ""))] {
+			newObjects = append(newObjects, unitObj)
+		}
+	}
+
+	if len(newObjects) == 0 {
+		return 0
+	}
+
+	if exp.Spec.RangeMode != nil && exp.Spec.RangeMode.Type != v1alpha1.AllRangeType {
+		var goneIndex []int
+		for i := range targetSubExp {
+			if targetSubExp[i].ReplacedBy != "" {
+				continue
+			}
+
+			// a succeeded target is no longer queried in the running round, so its pod is checked here
+			if targetSubExp[i].Status == v1alpha1.SuccessStatusType && (targetSubExp[i].Lifecycle == nil || targetSubExp[i].Lifecycle.GoneTime == "") {
+				obj, err := scopeHandler.GetInjectObject(ctx, exp.Spec.Experiment, targetSubExp[i].InjectObjectName)
+				if (err != nil && common.IsNotFoundErr(err)) || (err == nil && isRecreated(&targetSubExp[i], obj)) {
+					markTargetGone(&targetSubExp[i])
+				}
+			}
+
+			if targetSubExp[i].Lifecycle != nil && targetSubExp[i].Lifecycle.GoneTime != "" {
+				goneIndex = append(goneIndex, i)
+			}
+		}
+
+		if len(newObjects) > len(goneIndex) {
+			newObjects = newObjects[:len(goneIndex)]
+		}
+
+		for j := range newObjects {
+			targetSubExp[goneIndex[j]].ReplacedBy = newObjects[j].GetObjectName()
+		}
+	}
+
+	nowTime := time.Now().Format(model.TimeFormat)
+	for _, unitObj := range newObjects {
+		logger.Info(fmt.Sprintf("experiment: %s/%s, re-resolved new target: %s", exp.Namespace, exp.Name, unitObj.GetObjectName()))
+		exp.Status.Detail.Inject = append(exp.Status.Detail.Inject, v1alpha1.ExperimentDetailUnit{
+			InjectObjectName: unitObj.GetObjectName(),
+			UID:              common.NewUid(),
+			Status:           v1alpha1.CreatedStatusType,
+			Message:          "Re-resolved target created",
+			StartTime:        nowTime,
+			Executor:         scopehandler.GetTargetExecutor(unitObj),
+			Resolved:         scopehandler.GetResolvedTarget(unitObj, nowTime),
+			Lifecycle: &v1alpha1.TargetLifecycle{
+				Source:       v1alpha1.ReresolvedTargetSource,
+				ResolvedTime: nowTime,
+			},
+		})
+	}
+
+	return len(newObjects)
+}
+
+// markTargetGone records when the pod of the target is found gone, a target selected before its lifecycle is
+// recorded has none yet
+func markTargetGone(unit *v1alpha1.ExperimentDetailUnit) {
+	if unit.Lifecycle == nil {
+		unit.Lifecycle = &v1alpha1.TargetLifecycle{}
+	}
+
+	if unit.Lifecycle.GoneTime == "" {
+		unit.Lifecycle.GoneTime = time.Now().Format(model.TimeFormat)
+	}
+}
+
+// solveFinishedReresolve the pods of a finished injection can still be replaced during its duration,
+// so its targets are re-resolved as in the running round, and the new targets start another round of inject
+func solveFinishedReresolve(ctx context.Context, exp *v1alpha1.Experiment) {
+	if exp.Status.Phase != v1alpha1.InjectPhaseType || !scopehandler.IsReresolvable(&exp.Spec) || !isResolveDue(exp) {
+		return
+	}
+
+	isTimeout, err := common.IsTimeout(exp.Status.CreateTime, exp.Spec.Experiment.Duration)
+	if err != nil || isTimeout {
+		return
+	}
+
+	newCount := reresolveTargets(ctx, exp)
+	nowTime := time.Now().Format(model.TimeFormat)
+	exp.Status.LastResolveTime = nowTime
+	if newCount > 0 {
+		log.FromContext(ctx).Info(fmt.Sprintf("experiment: %s/%s, re-resolved %d new targets after %s", exp.Namespace, exp.Name, newCount, exp.Status.Status))
+		exp.Status.Status, exp.Status.Message = v1alpha1.CreatedStatusType, fmt.Sprintf("re-resolved %d new targets, start to inject", newCount)
+		exp.Status.UpdateTime = nowTime
+	}
+}
+
 func (h *InjectPhaseHandler) SolveSuccess(ctx context.Context, exp *v1alpha1.Experiment) {
 	log.FromContext(ctx).Info(fmt.Sprintf("experiment: %s/%s, SolveSuccess start", exp.Namespace, exp.Name))
 	solveFinalStatus(ctx, exp)
+	solveFinishedReresolve(ctx, exp)
 }
 
 func (h *InjectPhaseHandler) SolvePartSuccess(ctx context.Context, exp *v1alpha1.Experiment) {
 	log.FromContext(ctx).Info(fmt.Sprintf("experiment: %s/%s, SolvePartSuccess start", exp.Namespace, exp.Name))
 	solveFinalStatus(ctx, exp)
+	solveFinishedReresolve(ctx, exp)
 }
 
 func (h *InjectPhaseHandler) SolveFailed(ctx context.Context, exp *v1alpha1.Experiment) {
@@ -259,14 +614,26 @@ func (h *InjectPhaseHandler) SolveFailed(ctx context.Context, exp *v1alpha1.Expe
 	solveFinalStatus(ctx, exp)
 }
 
+// solveFinalStatus starts the recover phase when the target phase is recover, when the experiment is paused,
+// or when the failed experiment is rolled back by the failure policy
 func solveFinalStatus(ctx context.Context, exp *v1alpha1.Experiment) {
-	if exp.Spec.TargetPhase == exp.Status.Phase || exp.Spec.TargetPhase != v1alpha1.RecoverPhaseType {
+	paused := exp.Spec.Paused && exp.Spec.TargetPhase == v1alpha1.InjectPhaseType
+	rollback := exp.Spec.FailurePolicy == v1alpha1.RollbackFailurePolicyType && exp.Spec.TargetPhase == v1alpha1.InjectPhaseType && exp.Status.Status == v1alpha1.FailedStatusType
+	if !paused && !rollback && (exp.Spec.TargetPhase == exp.Status.Phase || exp.Spec.TargetPhase != v1alpha1.RecoverPhaseType) {
 		return
 	}
 
+	if paused {
+		log.FromContext(ctx).Info(fmt.Sprintf("experiment: %s/%s, paused, start to recover", exp.Namespace, exp.Name))
+		exp.Status.Message = "experiment paused, start to recover"
+	} else if rollback {
+		log.FromContext(ctx).Info(fmt.Sprintf("experiment: %s/%s, inject failed, start to roll back", exp.Namespace, exp.Name))
+		exp.Status.Message = "inject failed, roll back the injected targets by failure policy"
+	}
+
 	injectDetail := exp.Status.Detail.Inject
 	nowTime := time.Now().Format(model.TimeFormat)
-	exp.Status.Phase, exp.Status.UpdateTime = v1alpha1.RecoverPhaseType, nowTime
+	exp.Status.Phase, exp.Status.UpdateTime, exp.Status.Paused = v1alpha1.RecoverPhaseType, nowTime, paused
 
 	if len(injectDetail) != 0 {
 		recoverDetail := make([]v1alpha1.ExperimentDetailUnit, len(injectDetail))
@@ -278,6 +645,7 @@ func solveFinalStatus(ctx context.Context, exp *v1alpha1.Experiment) {
 				Message:          "start to recover",
 				StartTime:        nowTime,
 				Backup:           injectDetail[i].Backup,
+				Executor:         injectDetail[i].Executor,
 			}
 		}
 
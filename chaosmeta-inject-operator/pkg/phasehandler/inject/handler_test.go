@@ -118,6 +118,152 @@ func TestInjectPhaseHandler_SolveCreated_OneToRunning(t *testing.T) {
 	assert.Equal(t, 0, common.GetGoroutinePool().GetLen())
 }
 
+func TestInjectPhaseHandler_SolveCreated_AdoptInFlight(t *testing.T) {
+	var (
+		ctx     = context.Background()
+		nowTime = time.Now().Format(model.TimeFormat)
+		exp     = &v1alpha1.Experiment{
+			Spec: v1alpha1.ExperimentSpec{
+				Scope: v1alpha1.PodScopeType,
+				Experiment: &v1alpha1.ExperimentCommon{
+					Duration: "2m",
+					Target:   "cpu",
+					Fault:    "burn",
+				},
+				TargetPhase: v1alpha1.InjectPhaseType,
+			},
+			Status: v1alpha1.ExperimentStatus{
+				Phase:      v1alpha1.InjectPhaseType,
+				Status:     v1alpha1.CreatedStatusType,
+				CreateTime: nowTime,
+				UpdateTime: nowTime,
+				Detail: v1alpha1.ExperimentDetail{
+					Inject: []v1alpha1.ExperimentDetailUnit{
+						{
+							InjectObjectName: "pod/chaosmeta/chaosmeta-0",
+							UID:              "fwaf0",
+							Status:           v1alpha1.CreatedStatusType,
+							InjectAttempts:   2,
+						},
+						{
+							InjectObjectName: "pod/chaosmeta/chaosmeta-1",
+							UID:              "fwaf1",
+							Status:           v1alpha1.CreatedStatusType,
+							InjectAttempts:   2,
+						},
+					},
+				},
+			},
+		}
+		re0 = model.AtomicObject(&model.PodObject{Namespace: "chaosmeta", PodName: "chaosmeta-0", NodeIP: "2.2.2.2"})
+		re1 = model.AtomicObject(&model.PodObject{Namespace: "chaosmeta", PodName: "chaosmeta-1", NodeIP: "2.2.2.2"})
+	)
+	common.SetGoroutinePool(5)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	scopeHandlerMock := mockscopehandler.NewMockScopeHandler(ctrl)
+	scopeHandlerMock.EXPECT().GetInjectObject(ctx, exp.Spec.Experiment, "pod/chaosmeta/chaosmeta-0").Return(re0, nil)
+	scopeHandlerMock.EXPECT().GetInjectObject(ctx, exp.Spec.Experiment, "pod/chaosmeta/chaosmeta-1").Return(re1, nil)
+	// the injection of chaosmeta-0 is in flight, and chaosmeta-1 is not injected by the previous attempt
+	scopeHandlerMock.EXPECT().QueryExperiment(ctx, re0, "fwaf0", "", exp.Spec.Experiment, v1alpha1.InjectPhaseType).Return(&model.SubExpInfo{
+		UID:    "fwaf0",
+		Status: v1alpha1.SuccessStatusType,
+	}, nil)
+	scopeHandlerMock.EXPECT().QueryExperiment(ctx, re1, "fwaf1", "", exp.Spec.Experiment, v1alpha1.InjectPhaseType).Return(nil, fmt.Errorf("not found"))
+	scopeHandlerMock.EXPECT().ExecuteInject(ctx, re1, "fwaf1", exp.Spec.Experiment).Return("", nil)
+
+	gomonkey.ApplyFunc(scopehandler.GetScopeHandler, func(v1alpha1.ScopeType) scopehandler.ScopeHandler {
+		return scopeHandlerMock
+	})
+
+	phaseHandler := InjectPhaseHandler{}
+	phaseHandler.SolveCreated(ctx, exp)
+
+	assert.Equal(t, v1alpha1.RunningStatusType, exp.Status.Status)
+	assert.Equal(t, v1alpha1.RunningStatusType, exp.Status.Detail.Inject[0].Status)
+	assert.Equal(t, "adopt the injection of a previous attempt", exp.Status.Detail.Inject[0].Message)
+	assert.Equal(t, v1alpha1.RunningStatusType, exp.Status.Detail.Inject[1].Status)
+}
+
+func TestInjectPhaseHandler_SolveCreated_RetryPolicy(t *testing.T) {
+	var (
+		ctx     = context.Background()
+		nowTime = time.Now().Format(model.TimeFormat)
+		exp     = &v1alpha1.Experiment{
+			Spec: v1alpha1.ExperimentSpec{
+				Scope: v1alpha1.PodScopeType,
+				Experiment: &v1alpha1.ExperimentCommon{
+					Duration: "2m",
+					Target:   "cpu",
+					Fault:    "burn",
+				},
+				TargetPhase: v1alpha1.InjectPhaseType,
+				RetryPolicy: &v1alpha1.RetryPolicy{MaxRetries: 2, Backoff: "10s"},
+			},
+			Status: v1alpha1.ExperimentStatus{
+				Phase:      v1alpha1.InjectPhaseType,
+				Status:     v1alpha1.CreatedStatusType,
+				CreateTime: nowTime,
+				UpdateTime: nowTime,
+				Detail: v1alpha1.ExperimentDetail{
+					Inject: []v1alpha1.ExperimentDetailUnit{
+						{
+							InjectObjectName: "pod/chaosmeta/chaosmeta-0",
+							UID:              "fwaf0",
+							Status:           v1alpha1.CreatedStatusType,
+							InjectAttempts:   1,
+						},
+						{
+							InjectObjectName: "pod/chaosmeta/chaosmeta-1",
+							UID:              "fwaf1",
+							Status:           v1alpha1.CreatedStatusType,
+							InjectAttempts:   3,
+						},
+						{
+							InjectObjectName: "pod/chaosmeta/chaosmeta-2",
+							UID:              "fwaf2",
+							Status:           v1alpha1.CreatedStatusType,
+							InjectAttempts:   2,
+							NextRetryTime:    time.Now().Add(time.Minute).Format(model.TimeFormat),
+						},
+					},
+				},
+			},
+		}
+		re0         = model.AtomicObject(&model.PodObject{Namespace: "chaosmeta", PodName: "chaosmeta-0", NodeIP: "2.2.2.2"})
+		re1         = model.AtomicObject(&model.PodObject{Namespace: "chaosmeta", PodName: "chaosmeta-1", NodeIP: "2.2.2.2"})
+		unreachable = common.NewCodedError(common.GetAgentUnavailableErrorInfo(), fmt.Errorf("get response error: connection refused"))
+	)
+	common.SetGoroutinePool(5)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	// chaosmeta-2 is waiting for its retry, so it is not injected in this round
+	scopeHandlerMock := mockscopehandler.NewMockScopeHandler(ctrl)
+	scopeHandlerMock.EXPECT().GetInjectObject(ctx, exp.Spec.Experiment, "pod/chaosmeta/chaosmeta-0").Return(re0, nil)
+	scopeHandlerMock.EXPECT().GetInjectObject(ctx, exp.Spec.Experiment, "pod/chaosmeta/chaosmeta-1").Return(re1, nil)
+	scopeHandlerMock.EXPECT().QueryExperiment(ctx, re1, "fwaf1", "", exp.Spec.Experiment, v1alpha1.InjectPhaseType).Return(nil, fmt.Errorf("not found"))
+	scopeHandlerMock.EXPECT().ExecuteInject(ctx, re0, "fwaf0", exp.Spec.Experiment).Return("", unreachable)
+	scopeHandlerMock.EXPECT().ExecuteInject(ctx, re1, "fwaf1", exp.Spec.Experiment).Return("", unreachable)
+
+	gomonkey.ApplyFunc(scopehandler.GetScopeHandler, func(v1alpha1.ScopeType) scopehandler.ScopeHandler {
+		return scopeHandlerMock
+	})
+
+	phaseHandler := InjectPhaseHandler{}
+	phaseHandler.SolveCreated(ctx, exp)
+
+	assert.Equal(t, v1alpha1.CreatedStatusType, exp.Status.Status)
+	assert.Equal(t, v1alpha1.CreatedStatusType, exp.Status.Detail.Inject[0].Status)
+	assert.NotEmpty(t, exp.Status.Detail.Inject[0].NextRetryTime)
+	assert.Nil(t, exp.Status.Detail.Inject[0].Error)
+	assert.Equal(t, v1alpha1.FailedStatusType, exp.Status.Detail.Inject[1].Status)
+	assert.Equal(t, common.CodeAgentUnavailable, exp.Status.Detail.Inject[1].Error.Code)
+	assert.Empty(t, exp.Status.Detail.Inject[1].NextRetryTime)
+	assert.Equal(t, v1alpha1.CreatedStatusType, exp.Status.Detail.Inject[2].Status)
+}
+
 func TestInjectPhaseHandler_SolveCreated_OneInjectFailedInTwo(t *testing.T) {
 	// init data
 	var (
@@ -223,6 +369,82 @@ func TestInjectPhaseHandler_SolveCreated_OneInjectFailedInTwo(t *testing.T) {
 	assert.Equal(t, v1alpha1.FailedStatusType, exp.Status.Detail.Inject[0].Status)
 }
 
+func TestInjectPhaseHandler_SolveCreated_SlowTargetDiagnosed(t *testing.T) {
+	// init data
+	var (
+		ctx     = context.Background()
+		nowTime = time.Now().Format(model.TimeFormat)
+		exp     = &v1alpha1.Experiment{
+			Spec: v1alpha1.ExperimentSpec{
+				Scope: v1alpha1.NodeScopeType,
+				Experiment: &v1alpha1.ExperimentCommon{
+					Duration: "2m",
+					Target:   "cpu",
+					Fault:    "burn",
+				},
+				TargetPhase: v1alpha1.InjectPhaseType,
+			},
+			Status: v1alpha1.ExperimentStatus{
+				Phase:      v1alpha1.InjectPhaseType,
+				Status:     v1alpha1.CreatedStatusType,
+				CreateTime: nowTime,
+				UpdateTime: nowTime,
+				Detail: v1alpha1.ExperimentDetail{
+					Inject: []v1alpha1.ExperimentDetailUnit{
+						{
+							InjectObjectName: "node/node-1/1.1.1.1",
+							UID:              "fwaf",
+							Status:           v1alpha1.CreatedStatusType,
+						},
+					},
+				},
+			},
+		}
+		reNode = &model.NodeObject{
+			NodeName:       "node-1",
+			NodeInternalIP: "1.1.1.1",
+		}
+		re = model.AtomicObject(reNode)
+	)
+	common.SetGoroutinePool(5)
+	common.SetInjectLatencySLO(1)
+	defer common.SetInjectLatencySLO(0)
+
+	// mock
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	scopeHandlerMock := mockscopehandler.NewMockScopeHandler(ctrl)
+	scopeHandlerMock.EXPECT().GetInjectObject(ctx, exp.Spec.Experiment, reNode.GetObjectName()).Return(re, nil)
+	scopeHandlerMock.EXPECT().ExecuteInject(ctx, re, exp.Status.Detail.Inject[0].UID, exp.Spec.Experiment).DoAndReturn(
+		func(context.Context, model.AtomicObject, string, *v1alpha1.ExperimentCommon) (string, error) {
+			time.Sleep(1100 * time.Millisecond)
+			return "", nil
+		})
+	scopeHandlerMock.EXPECT().Diagnose(ctx, re).Return(&model.AgentDiagnostics{
+		AgentVersion:        "0.3.9",
+		AgentResponseTime:   time.Second,
+		RuntimeResponseTime: 0,
+	}, nil)
+
+	gomonkey.ApplyFunc(scopehandler.GetScopeHandler, func(v1alpha1.ScopeType) scopehandler.ScopeHandler {
+		return scopeHandlerMock
+	})
+
+	// execute test
+	phaseHandler := InjectPhaseHandler{}
+	phaseHandler.SolveCreated(ctx, exp)
+
+	// check result
+	latency := exp.Status.Detail.Inject[0].Latency
+	assert.Equal(t, v1alpha1.RunningStatusType, exp.Status.Status)
+	assert.NotNil(t, latency)
+	assert.True(t, latency.ExceedSLO)
+	assert.Equal(t, "0.3.9", latency.AgentVersion)
+	assert.Equal(t, "1s", latency.AgentResponseTime)
+	assert.Equal(t, "", latency.RuntimeResponseTime)
+	assert.Contains(t, exp.Status.Message, "1 targets exceed inject latency SLO")
+}
+
 func TestInjectPhaseHandler_SolveRunning_TwoQueryFailedInThree(t *testing.T) {
 	// init data
 	var (
@@ -541,3 +763,405 @@ func Test_solveFinalStatus(t *testing.T) {
 	assert.Equal(t, v1alpha1.RecoverPhaseType, exp.Status.Phase)
 	assert.Equal(t, len(exp.Status.Detail.Inject), len(exp.Status.Detail.Recover))
 }
+
+func Test_reresolveTargets(t *testing.T) {
+	var (
+		ctx     = context.Background()
+		nowTime = time.Now().Format(model.TimeFormat)
+		exp     = &v1alpha1.Experiment{
+			Spec: v1alpha1.ExperimentSpec{
+				Scope: v1alpha1.JobScopeType,
+				RangeMode: &v1alpha1.RangeMode{
+					Type:  v1alpha1.CountRangeType,
+					Value: 1,
+				},
+				Experiment: &v1alpha1.ExperimentCommon{
+					Duration: "2m",
+					Target:   "cpu",
+					Fault:    "burn",
+				},
+				Selector: []v1alpha1.SelectorUnit{
+					{
+						Namespace: "chaosmeta",
+						Name:      []string{"batch"},
+					},
+				},
+				TargetPhase: v1alpha1.InjectPhaseType,
+			},
+			Status: v1alpha1.ExperimentStatus{
+				Phase:      v1alpha1.InjectPhaseType,
+				Status:     v1alpha1.RunningStatusType,
+				CreateTime: nowTime,
+				UpdateTime: nowTime,
+				Detail: v1alpha1.ExperimentDetail{
+					Inject: []v1alpha1.ExperimentDetailUnit{
+						{
+							InjectObjectName: "pod/chaosmeta/batch-a",
+							UID:              "fwaf1",
+							Status:           v1alpha1.SuccessStatusType,
+							Lifecycle: &v1alpha1.TargetLifecycle{
+								Source:       v1alpha1.InitialTargetSource,
+								ResolvedTime: nowTime,
+								GoneTime:     nowTime,
+							},
+						},
+					},
+				},
+			},
+		}
+		newPods = []model.AtomicObject{
+			&model.PodObject{Namespace: "chaosmeta", PodName: "batch-b"},
+			&model.PodObject{Namespace: "chaosmeta", PodName: "batch-c"},
+		}
+	)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	scopeHandlerMock := mockscopehandler.NewMockScopeHandler(ctrl)
+	scopeHandlerMock.EXPECT().ConvertSelector(ctx, &exp.Spec).Return(newPods, nil).Times(2)
+
+	gomonkey.ApplyFunc(scopehandler.GetScopeHandler, func(v1alpha1.ScopeType) scopehandler.ScopeHandler {
+		return scopeHandlerMock
+	})
+
+	// only the gone target is replaced under range mode count
+	assert.Equal(t, 1, reresolveTargets(ctx, exp))
+	assert.Equal(t, 2, len(exp.Status.Detail.Inject))
+	assert.Equal(t, "pod/chaosmeta/batch-b", exp.Status.Detail.Inject[0].ReplacedBy)
+	assert.Equal(t, "pod/chaosmeta/batch-b", exp.Status.Detail.Inject[1].InjectObjectName)
+	assert.Equal(t, v1alpha1.CreatedStatusType, exp.Status.Detail.Inject[1].Status)
+	assert.Equal(t, v1alpha1.ReresolvedTargetSource, exp.Status.Detail.Inject[1].Lifecycle.Source)
+
+	// every new pod is a target under range mode all
+	exp.Spec.RangeMode = nil
+	assert.Equal(t, 1, reresolveTargets(ctx, exp))
+	assert.Equal(t, "pod/chaosmeta/batch-c", exp.Status.Detail.Inject[2].InjectObjectName)
+}
+
+func Test_reresolveTargets_SuccessWithoutGoneTime(t *testing.T) {
+	var (
+		ctx     = context.Background()
+		nowTime = time.Now().Format(model.TimeFormat)
+		exp     = &v1alpha1.Experiment{
+			Spec: v1alpha1.ExperimentSpec{
+				Scope:       v1alpha1.JobScopeType,
+				RangeMode:   &v1alpha1.RangeMode{Type: v1alpha1.CountRangeType, Value: 2},
+				Experiment:  &v1alpha1.ExperimentCommon{Duration: "2m", Target: "cpu", Fault: "burn"},
+				TargetPhase: v1alpha1.InjectPhaseType,
+			},
+			Status: v1alpha1.ExperimentStatus{
+				Phase:      v1alpha1.InjectPhaseType,
+				Status:     v1alpha1.SuccessStatusType,
+				CreateTime: nowTime,
+				Detail: v1alpha1.ExperimentDetail{
+					Inject: []v1alpha1.ExperimentDetailUnit{
+						{InjectObjectName: "pod/chaosmeta/batch-a", UID: "fwaf1", Status: v1alpha1.SuccessStatusType},
+						{InjectObjectName: "pod/chaosmeta/batch-b", UID: "fwaf2", Status: v1alpha1.SuccessStatusType},
+					},
+				},
+			},
+		}
+		newPods = []model.AtomicObject{
+			&model.PodObject{Namespace: "chaosmeta", PodName: "batch-b"},
+			&model.PodObject{Namespace: "chaosmeta", PodName: "batch-c"},
+		}
+	)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	scopeHandlerMock := mockscopehandler.NewMockScopeHandler(ctrl)
+	scopeHandlerMock.EXPECT().ConvertSelector(ctx, &exp.Spec).Return(newPods, nil)
+	scopeHandlerMock.EXPECT().GetInjectObject(ctx, exp.Spec.Experiment, "pod/chaosmeta/batch-a").Return(nil, fmt.Errorf("pod batch-a not found"))
+	scopeHandlerMock.EXPECT().GetInjectObject(ctx, exp.Spec.Experiment, "pod/chaosmeta/batch-b").Return(newPods[0], nil)
+
+	gomonkey.ApplyFunc(scopehandler.GetScopeHandler, func(v1alpha1.ScopeType) scopehandler.ScopeHandler {
+		return scopeHandlerMock
+	})
+
+	// the succeeded target without a lifecycle is found gone and replaced, the experiment injects again
+	solveFinishedReresolve(ctx, exp)
+	assert.Equal(t, v1alpha1.CreatedStatusType, exp.Status.Status)
+	assert.NotEqual(t, "", exp.Status.LastResolveTime)
+	assert.Equal(t, 3, len(exp.Status.Detail.Inject))
+	assert.NotEqual(t, "", exp.Status.Detail.Inject[0].Lifecycle.GoneTime)
+	assert.Equal(t, "pod/chaosmeta/batch-c", exp.Status.Detail.Inject[0].ReplacedBy)
+	assert.Nil(t, exp.Status.Detail.Inject[1].Lifecycle)
+	assert.Equal(t, "pod/chaosmeta/batch-c", exp.Status.Detail.Inject[2].InjectObjectName)
+}
+
+func Test_reresolveTargets_Recreated(t *testing.T) {
+	var (
+		ctx     = context.Background()
+		nowTime = time.Now().Format(model.TimeFormat)
+		exp     = &v1alpha1.Experiment{
+			Spec: v1alpha1.ExperimentSpec{
+				Scope:       v1alpha1.PodScopeType,
+				RangeMode:   &v1alpha1.RangeMode{Type: v1alpha1.CountRangeType, Value: 1},
+				Experiment:  &v1alpha1.ExperimentCommon{Duration: "2m", Target: "cpu", Fault: "burn"},
+				TargetPhase: v1alpha1.InjectPhaseType,
+			},
+			Status: v1alpha1.ExperimentStatus{
+				Phase:      v1alpha1.InjectPhaseType,
+				Status:     v1alpha1.SuccessStatusType,
+				CreateTime: nowTime,
+				Detail: v1alpha1.ExperimentDetail{
+					Inject: []v1alpha1.ExperimentDetailUnit{
+						{InjectObjectName: "pod/chaosmeta/db-0", UID: "fwaf1", Status: v1alpha1.SuccessStatusType,
+							Resolved: &v1alpha1.ResolvedTarget{PodUID: "uid-1"}},
+					},
+				},
+			},
+		}
+		// the StatefulSet recreates db-0 under the same name
+		newPods = []model.AtomicObject{
+			&model.PodObject{Namespace: "chaosmeta", PodName: "db-0", PodUID: "uid-2"},
+		}
+	)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	scopeHandlerMock := mockscopehandler.NewMockScopeHandler(ctrl)
+	scopeHandlerMock.EXPECT().ConvertSelector(ctx, &exp.Spec).Return(newPods, nil).Times(2)
+	scopeHandlerMock.EXPECT().GetInjectObject(ctx, exp.Spec.Experiment, "pod/chaosmeta/db-0").Return(newPods[0], nil)
+
+	gomonkey.ApplyFunc(scopehandler.GetScopeHandler, func(v1alpha1.ScopeType) scopehandler.ScopeHandler {
+		return scopeHandlerMock
+	})
+
+	assert.Equal(t, 1, reresolveTargets(ctx, exp))
+	assert.Equal(t, 2, len(exp.Status.Detail.Inject))
+	assert.NotEqual(t, "", exp.Status.Detail.Inject[0].Lifecycle.GoneTime)
+	assert.Equal(t, "pod/chaosmeta/db-0", exp.Status.Detail.Inject[0].ReplacedBy)
+	assert.Equal(t, "uid-2", exp.Status.Detail.Inject[1].Resolved.PodUID)
+
+	// the recreated pod is a target now
+	assert.Equal(t, 0, reresolveTargets(ctx, exp))
+}
+
+func Test_isResolveDue(t *testing.T) {
+	exp := &v1alpha1.Experiment{
+		Spec: v1alpha1.ExperimentSpec{
+			Scope:     v1alpha1.PodScopeType,
+			Reresolve: &v1alpha1.ReresolveSpec{Interval: "1m"},
+		},
+		Status: v1alpha1.ExperimentStatus{
+			CreateTime: time.Now().Add(-2 * time.Minute).Format(model.TimeFormat),
+		},
+	}
+
+	assert.True(t, isResolveDue(exp))
+
+	exp.Status.LastResolveTime = time.Now().Format(model.TimeFormat)
+	assert.False(t, isResolveDue(exp))
+}
+
+func Test_solveFinalStatus_Paused(t *testing.T) {
+	var (
+		ctx     = context.Background()
+		nowTime = time.Now().Format(model.TimeFormat)
+		exp     = &v1alpha1.Experiment{
+			Spec: v1alpha1.ExperimentSpec{
+				Scope: v1alpha1.PodScopeType,
+				Experiment: &v1alpha1.ExperimentCommon{
+					Duration: "2m",
+					Target:   "cpu",
+					Fault:    "burn",
+				},
+				TargetPhase: v1alpha1.InjectPhaseType,
+				Paused:      true,
+			},
+			Status: v1alpha1.ExperimentStatus{
+				Phase:      v1alpha1.InjectPhaseType,
+				Status:     v1alpha1.SuccessStatusType,
+				CreateTime: nowTime,
+				Detail: v1alpha1.ExperimentDetail{
+					Inject: []v1alpha1.ExperimentDetailUnit{
+						{
+							InjectObjectName: "pod/chaosmeta/chaosmeta-1",
+							UID:              "fwaf1",
+							Status:           v1alpha1.SuccessStatusType,
+							Backup:           "backup1",
+						},
+					},
+				},
+			},
+		}
+	)
+
+	solveFinalStatus(ctx, exp)
+
+	assert.Equal(t, v1alpha1.RecoverPhaseType, exp.Status.Phase)
+	assert.Equal(t, v1alpha1.CreatedStatusType, exp.Status.Status)
+	assert.True(t, exp.Status.Paused)
+	assert.Equal(t, "fwaf1", exp.Status.Detail.Recover[0].UID)
+	assert.Equal(t, "backup1", exp.Status.Detail.Recover[0].Backup)
+}
+
+func Test_solveFinalStatus_Rollback(t *testing.T) {
+	newExp := func(policy v1alpha1.FailurePolicyType) *v1alpha1.Experiment {
+		return &v1alpha1.Experiment{
+			Spec: v1alpha1.ExperimentSpec{
+				Scope: v1alpha1.PodScopeType,
+				Experiment: &v1alpha1.ExperimentCommon{
+					Duration: "2m",
+					Target:   "cpu",
+					Fault:    "burn",
+				},
+				TargetPhase:   v1alpha1.InjectPhaseType,
+				FailurePolicy: policy,
+			},
+			Status: v1alpha1.ExperimentStatus{
+				Phase:  v1alpha1.InjectPhaseType,
+				Status: v1alpha1.FailedStatusType,
+				Detail: v1alpha1.ExperimentDetail{
+					Inject: []v1alpha1.ExperimentDetailUnit{
+						{
+							InjectObjectName: "pod/chaosmeta/chaosmeta-0",
+							UID:              "fwaf0",
+							Status:           v1alpha1.FailedStatusType,
+						},
+						{
+							InjectObjectName: "pod/chaosmeta/chaosmeta-1",
+							UID:              "fwaf1",
+							Status:           v1alpha1.RunningStatusType,
+							Backup:           "backup1",
+						},
+					},
+				},
+			},
+		}
+	}
+
+	exp := newExp(v1alpha1.RollbackFailurePolicyType)
+	solveFinalStatus(context.Background(), exp)
+	assert.Equal(t, v1alpha1.RecoverPhaseType, exp.Status.Phase)
+	assert.Equal(t, v1alpha1.CreatedStatusType, exp.Status.Status)
+	assert.False(t, exp.Status.Paused)
+	assert.Equal(t, "backup1", exp.Status.Detail.Recover[1].Backup)
+
+	exp = newExp(v1alpha1.AbortFailurePolicyType)
+	solveFinalStatus(context.Background(), exp)
+	assert.Equal(t, v1alpha1.InjectPhaseType, exp.Status.Phase)
+	assert.Equal(t, v1alpha1.FailedStatusType, exp.Status.Status)
+}
+
+func Test_abortCreatedTargets(t *testing.T) {
+	exp := &v1alpha1.Experiment{
+		Spec: v1alpha1.ExperimentSpec{FailurePolicy: v1alpha1.AbortFailurePolicyType},
+		Status: v1alpha1.ExperimentStatus{
+			Detail: v1alpha1.ExperimentDetail{
+				Inject: []v1alpha1.ExperimentDetailUnit{
+					{UID: "fwaf0", Status: v1alpha1.CreatedStatusType},
+					{UID: "fwaf1", Status: v1alpha1.RunningStatusType},
+					{UID: "fwaf2", Status: v1alpha1.CreatedStatusType, NextRetryTime: time.Now().Format(model.TimeFormat)},
+				},
+			},
+		},
+	}
+
+	assert.False(t, isAborted(exp))
+	exp.Status.Detail.Inject[1].Status = v1alpha1.FailedStatusType
+	assert.True(t, isAborted(exp))
+	exp.Spec.FailurePolicy = v1alpha1.ContinueFailurePolicyType
+	assert.False(t, isAborted(exp))
+
+	abortCreatedTargets(exp)
+	assert.Equal(t, v1alpha1.FailedStatusType, exp.Status.Detail.Inject[0].Status)
+	assert.Equal(t, "not injected, aborted by failure policy", exp.Status.Detail.Inject[0].Message)
+	assert.Equal(t, v1alpha1.FailedStatusType, exp.Status.Detail.Inject[2].Status)
+	assert.Empty(t, exp.Status.Detail.Inject[2].NextRetryTime)
+}
+
+func Test_getInjectArgs(t *testing.T) {
+	exp := &v1alpha1.Experiment{
+		Spec: v1alpha1.ExperimentSpec{
+			Experiment: &v1alpha1.ExperimentCommon{
+				Duration: "10m",
+				Target:   "cpu",
+				Fault:    "burn",
+			},
+		},
+		Status: v1alpha1.ExperimentStatus{
+			CreateTime: time.Now().Add(-4 * time.Minute).Format(model.TimeFormat),
+		},
+	}
+
+	assert.Equal(t, exp.Spec.Experiment, getInjectArgs(exp))
+
+	exp.Status.ResumeTime = time.Now().Format(model.TimeFormat)
+	args := getInjectArgs(exp)
+	remaining, err := v1alpha1.ConvertDuration(args.Duration)
+	assert.NoError(t, err)
+	assert.InDelta(t, (6 * time.Minute).Seconds(), remaining.Seconds(), 2)
+	assert.Equal(t, "10m", exp.Spec.Experiment.Duration)
+
+	exp.Status.CreateTime = time.Now().Add(-time.Hour).Format(model.TimeFormat)
+	assert.Equal(t, "1s", getInjectArgs(exp).Duration)
+}
+
+func TestInjectPhaseHandler_SolveCreated_PreflightFailed(t *testing.T) {
+	var (
+		ctx     = context.Background()
+		nowTime = time.Now().Format(model.TimeFormat)
+		exp     = &v1alpha1.Experiment{
+			Spec: v1alpha1.ExperimentSpec{
+				Scope: v1alpha1.PodScopeType,
+				Experiment: &v1alpha1.ExperimentCommon{
+					Duration: "2m",
+					Target:   "network",
+					Fault:    "delay",
+				},
+				TargetPhase: v1alpha1.InjectPhaseType,
+				Preflight:   true,
+			},
+			Status: v1alpha1.ExperimentStatus{
+				Phase:      v1alpha1.InjectPhaseType,
+				Status:     v1alpha1.CreatedStatusType,
+				CreateTime: nowTime,
+				UpdateTime: nowTime,
+				Detail: v1alpha1.ExperimentDetail{
+					Inject: []v1alpha1.ExperimentDetailUnit{
+						{
+							InjectObjectName: "pod/chaosmeta/chaosmeta-0",
+							UID:              "uid0",
+							Status:           v1alpha1.CreatedStatusType,
+						},
+						{
+							InjectObjectName: "pod/chaosmeta/chaosmeta-1",
+							UID:              "uid1",
+							Status:           v1alpha1.CreatedStatusType,
+						},
+					},
+				},
+			},
+		}
+		pod0 = model.AtomicObject(&model.PodObject{Namespace: "chaosmeta", PodName: "chaosmeta-0"})
+		pod1 = model.AtomicObject(&model.PodObject{Namespace: "chaosmeta", PodName: "chaosmeta-1"})
+	)
+	common.SetGoroutinePool(5)
+
+	// mock: no target is injected if one of them fails the check
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	scopeHandlerMock := mockscopehandler.NewMockScopeHandler(ctrl)
+	scopeHandlerMock.EXPECT().GetInjectObject(ctx, exp.Spec.Experiment, "pod/chaosmeta/chaosmeta-0").Return(pod0, nil)
+	scopeHandlerMock.EXPECT().GetInjectObject(ctx, exp.Spec.Experiment, "pod/chaosmeta/chaosmeta-1").Return(pod1, nil)
+	scopeHandlerMock.EXPECT().Preflight(ctx, pod0, exp.Spec.Experiment).Return(nil, nil)
+	scopeHandlerMock.EXPECT().Preflight(ctx, pod1, exp.Spec.Experiment).Return([]string{"missing sch_netem on kernel 3.10.0"}, nil)
+
+	gomonkey.ApplyFunc(scopehandler.GetScopeHandler, func(v1alpha1.ScopeType) scopehandler.ScopeHandler {
+		return scopeHandlerMock
+	})
+
+	phaseHandler := InjectPhaseHandler{}
+	phaseHandler.SolveCreated(ctx, exp)
+
+	assert.Equal(t, v1alpha1.FailedStatusType, exp.Status.Status)
+	assert.Equal(t, "preflight check failed on 1 targets: pod/chaosmeta/chaosmeta-1: missing sch_netem on kernel 3.10.0", exp.Status.Message)
+	assert.True(t, exp.Status.Detail.Inject[0].Preflight.Passed)
+	assert.Equal(t, v1alpha1.FailedStatusType, exp.Status.Detail.Inject[0].Status)
+	assert.False(t, exp.Status.Detail.Inject[1].Preflight.Passed)
+	assert.Equal(t, v1alpha1.FailedStatusType, exp.Status.Detail.Inject[1].Status)
+	assert.Equal(t, common.CodePreflightFailed, exp.Status.Detail.Inject[1].Error.Code)
+	assert.Equal(t, 0, common.GetGoroutinePool().GetLen())
+}
@@ -0,0 +1,121 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package injecthandler
+
+import (
+	"context"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/common"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/model"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/scopehandler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"strings"
+	"sync"
+	"time"
+)
+
+// solvePreflight checks the created targets not checked yet before they are injected. If a target fails the check
+// before any target is injected, all the targets fail fast with the experiment. The re-resolved targets failing
+// the check later only fail themselves. It returns false if the experiment fails fast
+func solvePreflight(ctx context.Context, exp *v1alpha1.Experiment) bool {
+	var (
+		logger       = log.FromContext(ctx)
+		targetSubExp = exp.Status.Detail.Inject
+		wg           = sync.WaitGroup{}
+		limiter      = common.NewTargetLimiter()
+		injected     bool
+	)
+
+	for i := range targetSubExp {
+		if targetSubExp[i].Status != v1alpha1.CreatedStatusType {
+			injected = true
+			continue
+		}
+
+		if targetSubExp[i].Preflight != nil {
+			continue
+		}
+
+		limiter.Acquire()
+		common.GetGoroutinePool().GetGoroutine()
+		wg.Add(1)
+		go func(i int) {
+			defer func() {
+				limiter.Release()
+				common.GetGoroutinePool().ReleaseGoroutine()
+				wg.Done()
+			}()
+			targetSubExp[i].Preflight = checkTarget(ctx, exp, &targetSubExp[i])
+		}(i)
+	}
+
+	wg.Wait()
+
+	var reasons []string
+	for i := range targetSubExp {
+		result := targetSubExp[i].Preflight
+		if targetSubExp[i].Status != v1alpha1.CreatedStatusType || result == nil || result.Passed {
+			continue
+		}
+
+		reason := strings.Join(result.Reasons, "; ")
+		reasons = append(reasons, fmt.Sprintf("%s: %s", targetSubExp[i].InjectObjectName, reason))
+		targetSubExp[i].Status, targetSubExp[i].Message = v1alpha1.FailedStatusType, fmt.Sprintf("preflight check failed: %s", reason)
+		targetSubExp[i].Error = common.GetPreflightFailedErrorInfo()
+		common.RecordLastError(&targetSubExp[i])
+	}
+
+	if len(reasons) == 0 || injected {
+		return true
+	}
+
+	logger.Info(fmt.Sprintf("experiment: %s/%s, preflight check failed on %d targets, fail fast", exp.Namespace, exp.Name, len(reasons)))
+	for i := range targetSubExp {
+		if targetSubExp[i].Status == v1alpha1.CreatedStatusType {
+			targetSubExp[i].Status, targetSubExp[i].Message = v1alpha1.FailedStatusType, "not injected, preflight check of other targets failed"
+		}
+	}
+
+	exp.Status.Status = v1alpha1.FailedStatusType
+	exp.Status.Message = fmt.Sprintf("preflight check failed on %d targets: %s", len(reasons), strings.Join(reasons, ", "))
+	exp.Status.UpdateTime = time.Now().Format(model.TimeFormat)
+	return false
+}
+
+func checkTarget(ctx context.Context, exp *v1alpha1.Experiment, unit *v1alpha1.ExperimentDetailUnit) *v1alpha1.PreflightResult {
+	var (
+		scopeHandler = scopehandler.GetScopeHandler(exp.Spec.Scope)
+		args         = common.GetTargetArgs(exp.Spec.Experiment, unit)
+		result       = &v1alpha1.PreflightResult{CheckTime: time.Now().Format(model.TimeFormat)}
+	)
+
+	injectObject, err := scopeHandler.GetInjectObject(ctx, args, unit.InjectObjectName)
+	if err != nil {
+		result.Reasons = []string{fmt.Sprintf("get inject object error: %s", err.Error())}
+		return result
+	}
+
+	reasons, err := scopeHandler.Preflight(ctx, injectObject, args)
+	if err != nil {
+		result.Reasons = []string{fmt.Sprintf("check capability error: %s", err.Error())}
+		return result
+	}
+
+	result.Passed, result.Reasons = len(reasons) == 0, reasons
+	return result
+}
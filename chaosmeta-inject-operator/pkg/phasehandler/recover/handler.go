@@ -45,6 +45,7 @@ func (h *RecoverPhaseHandler) SolveCreated(ctx context.Context, exp *v1alpha1.Ex
 	var (
 		targetSubExp = exp.Status.Detail.Recover
 		wg           = sync.WaitGroup{}
+		limiter      = common.NewTargetLimiter()
 	)
 
 	for i := range targetSubExp {
@@ -52,9 +53,13 @@ func (h *RecoverPhaseHandler) SolveCreated(ctx context.Context, exp *v1alpha1.Ex
 			continue
 		}
 
+		limiter.Acquire()
 		common.GetGoroutinePool().GetGoroutine()
 		wg.Add(1)
-		go solveCreated(ctx, &wg, exp, i, isTimeout)
+		go func(i int) {
+			defer limiter.Release()
+			solveCreated(ctx, &wg, exp, i, isTimeout)
+		}(i)
 	}
 
 	wg.Wait()
@@ -97,6 +102,7 @@ func (h *RecoverPhaseHandler) SolveRunning(ctx context.Context, exp *v1alpha1.Ex
 	var (
 		targetSubExp = exp.Status.Detail.Recover
 		wg           = sync.WaitGroup{}
+		limiter      = common.NewTargetLimiter()
 	)
 
 	for i := range targetSubExp {
@@ -104,9 +110,13 @@ func (h *RecoverPhaseHandler) SolveRunning(ctx context.Context, exp *v1alpha1.Ex
 			continue
 		}
 
+		limiter.Acquire()
 		common.GetGoroutinePool().GetGoroutine()
 		wg.Add(1)
-		go solveRunning(ctx, &wg, exp, i, isTimeout)
+		go func(i int) {
+			defer limiter.Release()
+			solveRunning(ctx, &wg, exp, i, isTimeout)
+		}(i)
 	}
 
 	wg.Wait()
@@ -138,14 +148,68 @@ func (h *RecoverPhaseHandler) SolveRunning(ctx context.Context, exp *v1alpha1.Ex
 
 func (h *RecoverPhaseHandler) SolveSuccess(ctx context.Context, exp *v1alpha1.Experiment) {
 	log.FromContext(ctx).Info(fmt.Sprintf("experiment: %s/%s, SolveSuccess start", exp.Namespace, exp.Name))
+	solvePausedStatus(ctx, exp)
 }
 
 func (h *RecoverPhaseHandler) SolvePartSuccess(ctx context.Context, exp *v1alpha1.Experiment) {
 	log.FromContext(ctx).Info(fmt.Sprintf("experiment: %s/%s, SolvePartSuccess start", exp.Namespace, exp.Name))
+	solvePausedStatus(ctx, exp)
 }
 
 func (h *RecoverPhaseHandler) SolveFailed(ctx context.Context, exp *v1alpha1.Experiment) {
 	log.FromContext(ctx).Info(fmt.Sprintf("experiment: %s/%s, SolveFailed start", exp.Namespace, exp.Name))
+	solvePausedStatus(ctx, exp)
+}
+
+// solvePausedStatus a paused experiment goes back to the inject phase once it is resumed within its duration,
+// the targets are injected again with new uids. It is finished as recovered when ended or timeout during the pause
+func solvePausedStatus(ctx context.Context, exp *v1alpha1.Experiment) {
+	if !exp.Status.Paused || (exp.Spec.Paused && exp.Spec.TargetPhase == v1alpha1.InjectPhaseType) {
+		return
+	}
+
+	logger := log.FromContext(ctx)
+	exp.Status.Paused = false
+	if exp.Spec.TargetPhase == v1alpha1.RecoverPhaseType {
+		logger.Info(fmt.Sprintf("experiment: %s/%s, ended during the pause", exp.Namespace, exp.Name))
+		return
+	}
+
+	isTimeout, err := common.IsTimeout(exp.Status.CreateTime, exp.Spec.Experiment.Duration)
+	if err != nil || isTimeout {
+		logger.Info(fmt.Sprintf("experiment: %s/%s, timeout during the pause, skip resume", exp.Namespace, exp.Name))
+		exp.Status.Message = "experiment timeout during the pause"
+		return
+	}
+
+	nowTime := time.Now().Format(model.TimeFormat)
+	var injectDetail []v1alpha1.ExperimentDetailUnit
+	for _, unit := range exp.Status.Detail.Inject {
+		// the gone targets have nothing to resume
+		if unit.ReplacedBy != "" || (unit.Lifecycle != nil && unit.Lifecycle.GoneTime != "") {
+			continue
+		}
+
+		injectDetail = append(injectDetail, v1alpha1.ExperimentDetailUnit{
+			InjectObjectName: unit.InjectObjectName,
+			UID:              common.NewUid(),
+			Status:           v1alpha1.CreatedStatusType,
+			Message:          "experiment resumed",
+			StartTime:        nowTime,
+			Lifecycle:        unit.Lifecycle,
+			Executor:         unit.Executor,
+		})
+	}
+
+	if len(injectDetail) == 0 {
+		exp.Status.Message = "no target to resume"
+		return
+	}
+
+	logger.Info(fmt.Sprintf("experiment: %s/%s, resumed, start to inject %d targets", exp.Namespace, exp.Name, len(injectDetail)))
+	exp.Status.Phase, exp.Status.Status, exp.Status.Message = v1alpha1.InjectPhaseType, v1alpha1.CreatedStatusType, "experiment resumed"
+	exp.Status.Detail.Inject, exp.Status.Detail.Recover = injectDetail, nil
+	exp.Status.ResumeTime, exp.Status.UpdateTime = nowTime, nowTime
 }
 
 func solveCreated(ctx context.Context, wg *sync.WaitGroup, exp *v1alpha1.Experiment, i int, isTimeout bool) {
@@ -160,17 +224,23 @@ func solveCreated(ctx context.Context, wg *sync.WaitGroup, exp *v1alpha1.Experim
 	logger.Info(fmt.Sprintf("experiment: %s/%s/%s, solveCreated start, now Goroutine: %d", exp.Namespace, exp.Name, targetSubExp[i].InjectObjectName, common.GetGoroutinePool().GetLen()))
 
 	defer func() {
+		common.RecordLastError(&targetSubExp[i])
+		// the error of a previous round is stale once the target is not failed
+		if targetSubExp[i].Status != v1alpha1.FailedStatusType {
+			targetSubExp[i].Error = nil
+		}
 		common.GetGoroutinePool().ReleaseGoroutine()
 		wg.Done()
 		logger.Info(fmt.Sprintf("experiment: %s/%s/%s, solveCreated finish, status: %s, now Goroutine: %d", exp.Namespace, exp.Name, targetSubExp[i].InjectObjectName, targetSubExp[i].Status, common.GetGoroutinePool().GetLen()))
 	}()
 
-	commonObject, err = scopeHandler.GetInjectObject(ctx, exp.Spec.Experiment, targetSubExp[i].InjectObjectName)
+	commonObject, err = scopeHandler.GetInjectObject(ctx, common.GetTargetArgs(exp.Spec.Experiment, &targetSubExp[i]), targetSubExp[i].InjectObjectName)
 	if err != nil {
 		if common.IsNetErr(err) {
 			targetSubExp[i].Status, targetSubExp[i].Message = v1alpha1.CreatedStatusType, "GetInjectObject network error, need to retry"
 			if isTimeout {
 				targetSubExp[i].Status, targetSubExp[i].Message = v1alpha1.FailedStatusType, "GetInjectObject network error, timeout"
+				targetSubExp[i].Error = common.GetTimeoutErrorInfo()
 			}
 		} else if common.IsNotFoundErr(err) {
 			// not found as success in recover stage
@@ -189,9 +259,11 @@ func solveCreated(ctx context.Context, wg *sync.WaitGroup, exp *v1alpha1.Experim
 			targetSubExp[i].Status, targetSubExp[i].Message = v1alpha1.CreatedStatusType, "experiment recover network error, need to retry"
 			if isTimeout {
 				targetSubExp[i].Status, targetSubExp[i].Message = v1alpha1.FailedStatusType, "experiment recover network error, timeout"
+				targetSubExp[i].Error = common.GetTimeoutErrorInfo()
 			}
 		} else {
 			targetSubExp[i].Status, targetSubExp[i].Message = v1alpha1.FailedStatusType, fmt.Sprintf("experiment recover error: %s", err.Error())
+			targetSubExp[i].Error = common.GetErrorInfo(err)
 		}
 	} else {
 		targetSubExp[i].Status, targetSubExp[i].Message = v1alpha1.RunningStatusType, "experiment recover start success"
@@ -210,22 +282,29 @@ func solveRunning(ctx context.Context, wg *sync.WaitGroup, exp *v1alpha1.Experim
 	logger.Info(fmt.Sprintf("experiment: %s/%s/%s, solveRunning start, now Goroutine: %d", exp.Namespace, exp.Name, targetSubExp[i].InjectObjectName, common.GetGoroutinePool().GetLen()))
 
 	defer func() {
+		common.RecordLastError(&targetSubExp[i])
+		// the error of a previous round is stale once the target is not failed
+		if targetSubExp[i].Status != v1alpha1.FailedStatusType {
+			targetSubExp[i].Error = nil
+		}
 		common.GetGoroutinePool().ReleaseGoroutine()
 		wg.Done()
 		logger.Info(fmt.Sprintf("experiment: %s/%s/%s, solveRunning finish, status: %s, now Goroutine: %d", exp.Namespace, exp.Name, targetSubExp[i].InjectObjectName, targetSubExp[i].Status, common.GetGoroutinePool().GetLen()))
 	}()
 
-	commonObject, err = scopeHandler.GetInjectObject(ctx, exp.Spec.Experiment, targetSubExp[i].InjectObjectName)
+	commonObject, err = scopeHandler.GetInjectObject(ctx, common.GetTargetArgs(exp.Spec.Experiment, &targetSubExp[i]), targetSubExp[i].InjectObjectName)
 	if err != nil {
 		if common.IsNetErr(err) {
 			targetSubExp[i].Status, targetSubExp[i].Message = v1alpha1.RunningStatusType, "GetInjectObject network error, need to retry"
 			if isTimeout {
 				targetSubExp[i].Status, targetSubExp[i].Message = v1alpha1.FailedStatusType, "GetInjectObject network error, timeout"
+				targetSubExp[i].Error = common.GetTimeoutErrorInfo()
 			}
 		} else if common.IsNotFoundErr(err) {
 			targetSubExp[i].Status, targetSubExp[i].Message = v1alpha1.SuccessStatusType, err.Error()
 		} else {
 			targetSubExp[i].Status, targetSubExp[i].Message = v1alpha1.FailedStatusType, fmt.Sprintf("GetInjectObject error: %s", err.Error())
+			targetSubExp[i].Error = common.GetErrorInfo(err)
 		}
 
 		return
@@ -237,16 +316,24 @@ func solveRunning(ctx context.Context, wg *sync.WaitGroup, exp *v1alpha1.Experim
 			targetSubExp[i].Status, targetSubExp[i].Message = v1alpha1.RunningStatusType, "experiment query network error, need to retry"
 			if isTimeout {
 				targetSubExp[i].Status, targetSubExp[i].Message = v1alpha1.FailedStatusType, "experiment query network error, timeout"
+				targetSubExp[i].Error = common.GetTimeoutErrorInfo()
 			}
 		} else {
 			targetSubExp[i].Status, targetSubExp[i].Message = v1alpha1.FailedStatusType, fmt.Sprintf("experiment query error: %s", err.Error())
+			targetSubExp[i].Error = common.GetErrorInfo(err)
 		}
 
 		return
 	} else {
 		if expInfo.Status == v1alpha1.SuccessStatusType || expInfo.Status == v1alpha1.FailedStatusType || expInfo.Status == v1alpha1.RunningStatusType {
 			targetSubExp[i].Status, targetSubExp[i].Message = expInfo.Status, expInfo.Message
+			if expInfo.Status == v1alpha1.FailedStatusType {
+				targetSubExp[i].Error = common.GetAgentFailedErrorInfo(v1alpha1.RecoverPhaseType)
+			}
 			targetSubExp[i].StartTime, targetSubExp[i].UpdateTime = expInfo.CreateTime, expInfo.UpdateTime
+			if expInfo.Status == v1alpha1.SuccessStatusType && exp.Spec.Snapshot && targetSubExp[i].Snapshot == nil {
+				targetSubExp[i].Snapshot = scopehandler.TakeSnapshot(ctx, scopeHandler, commonObject)
+			}
 		} else {
 			logger.Error(fmt.Errorf("unexpected status"), fmt.Sprintf("expInfo.Status is %s", expInfo.Status))
 			return
@@ -249,3 +249,72 @@ func TestRecoverPhaseHandler_SolveRunning(t *testing.T) {
 	assert.Equal(t, v1alpha1.FailedStatusType, exp.Status.Detail.Recover[0].Status)
 	assert.Equal(t, v1alpha1.FailedStatusType, exp.Status.Detail.Recover[1].Status)
 }
+
+func Test_solvePausedStatus(t *testing.T) {
+	var (
+		ctx    = context.Background()
+		newExp = func() *v1alpha1.Experiment {
+			return &v1alpha1.Experiment{
+				Spec: v1alpha1.ExperimentSpec{
+					Experiment: &v1alpha1.ExperimentCommon{
+						Duration: "10m",
+						Target:   "cpu",
+						Fault:    "burn",
+					},
+					TargetPhase: v1alpha1.InjectPhaseType,
+					Paused:      true,
+				},
+				Status: v1alpha1.ExperimentStatus{
+					Phase:      v1alpha1.RecoverPhaseType,
+					Status:     v1alpha1.SuccessStatusType,
+					Paused:     true,
+					CreateTime: time.Now().Add(-time.Minute).Format(model.TimeFormat),
+					Detail: v1alpha1.ExperimentDetail{
+						Inject: []v1alpha1.ExperimentDetailUnit{
+							{InjectObjectName: "pod/chaosmeta/chaosmeta-1", UID: "fwaf1", Status: v1alpha1.SuccessStatusType},
+							{InjectObjectName: "pod/chaosmeta/chaosmeta-2", UID: "fwaf2", Status: v1alpha1.SuccessStatusType, ReplacedBy: "pod/chaosmeta/chaosmeta-3"},
+						},
+						Recover: []v1alpha1.ExperimentDetailUnit{
+							{InjectObjectName: "pod/chaosmeta/chaosmeta-1", UID: "fwaf1", Status: v1alpha1.SuccessStatusType},
+							{InjectObjectName: "pod/chaosmeta/chaosmeta-2", UID: "fwaf2", Status: v1alpha1.SuccessStatusType},
+						},
+					},
+				},
+			}
+		}
+	)
+
+	// still paused
+	exp := newExp()
+	solvePausedStatus(ctx, exp)
+	assert.Equal(t, v1alpha1.RecoverPhaseType, exp.Status.Phase)
+	assert.True(t, exp.Status.Paused)
+
+	// resumed
+	exp.Spec.Paused = false
+	solvePausedStatus(ctx, exp)
+	assert.Equal(t, v1alpha1.InjectPhaseType, exp.Status.Phase)
+	assert.Equal(t, v1alpha1.CreatedStatusType, exp.Status.Status)
+	assert.False(t, exp.Status.Paused)
+	assert.NotEmpty(t, exp.Status.ResumeTime)
+	assert.Len(t, exp.Status.Detail.Inject, 1)
+	assert.Equal(t, "pod/chaosmeta/chaosmeta-1", exp.Status.Detail.Inject[0].InjectObjectName)
+	assert.NotEqual(t, "fwaf1", exp.Status.Detail.Inject[0].UID)
+	assert.Empty(t, exp.Status.Detail.Recover)
+
+	// ended during the pause
+	exp = newExp()
+	exp.Spec.TargetPhase = v1alpha1.RecoverPhaseType
+	solvePausedStatus(ctx, exp)
+	assert.Equal(t, v1alpha1.RecoverPhaseType, exp.Status.Phase)
+	assert.False(t, exp.Status.Paused)
+
+	// timeout during the pause
+	exp = newExp()
+	exp.Spec.Paused = false
+	exp.Status.CreateTime = time.Now().Add(-time.Hour).Format(model.TimeFormat)
+	solvePausedStatus(ctx, exp)
+	assert.Equal(t, v1alpha1.RecoverPhaseType, exp.Status.Phase)
+	assert.False(t, exp.Status.Paused)
+	assert.Empty(t, exp.Status.ResumeTime)
+}
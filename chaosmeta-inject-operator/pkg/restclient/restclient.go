@@ -17,24 +17,67 @@
 package restclient
 
 import (
+	"context"
 	"fmt"
 	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/multicluster"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
 	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sync"
 )
 
 var (
 	apiServerClientMap = make(map[v1alpha1.CloudTargetType]rest.Interface)
+	apiServerScheme    *runtime.Scheme
+	clusterClientLock  sync.Mutex
+	clusterClientMap   = make(map[string]*clusterClients)
 )
 
-func GetApiServerClientMap(targetType v1alpha1.CloudTargetType) rest.Interface {
+// clusterClients are the clients of a registered cluster, created when they are used for the first time
+type clusterClients struct {
+	cluster *multicluster.Cluster
+	clients map[v1alpha1.CloudTargetType]rest.Interface
+}
+
+// GetApiServerClientMap returns the client of the cluster which the experiment in ctx is run in
+func GetApiServerClientMap(ctx context.Context, targetType v1alpha1.CloudTargetType) rest.Interface {
+	if c := multicluster.FromContext(ctx); c != nil {
+		return getClusterClient(c, targetType)
+	}
+
 	return apiServerClientMap[targetType]
 }
 
+func getClusterClient(c *multicluster.Cluster, targetType v1alpha1.CloudTargetType) rest.Interface {
+	clusterClientLock.Lock()
+	defer clusterClientLock.Unlock()
+
+	// the cluster is created again when its kubeconfig is updated
+	cc, ok := clusterClientMap[c.Name]
+	if !ok || cc.cluster != c {
+		cc = &clusterClients{cluster: c, clients: make(map[v1alpha1.CloudTargetType]rest.Interface)}
+		clusterClientMap[c.Name] = cc
+	}
+
+	if e, ok := cc.clients[targetType]; ok {
+		return e
+	}
+
+	e, err := newClient(targetType, c.RESTConfig, apiServerScheme)
+	if err != nil {
+		// the same as an unset target of the cluster of the operator
+		return nil
+	}
+
+	cc.clients[targetType] = e
+	return e
+}
+
 func SetApiServerClientMap(c *rest.Config, s *runtime.Scheme, t []v1alpha1.CloudTargetType) error {
+	apiServerScheme = s
 	for _, unitTarget := range t {
 		e, err := newClient(unitTarget, c, s)
 		if err != nil {
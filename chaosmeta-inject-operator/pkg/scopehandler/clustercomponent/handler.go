@@ -0,0 +1,191 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package clustercomponent
+
+import (
+	"context"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/common"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/model"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/scopehandler/pod"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/selector"
+)
+
+const (
+	defaultComponentNamespace = "kube-system"
+	defaultAPIServerPort      = "6443"
+	defaultEtcdPeerPort       = "2380"
+	minEtcdMemberCount        = 3
+)
+
+type componentInfo struct {
+	label     map[string]string
+	container string
+}
+
+// the labels of the components deployed by kubeadm
+var componentInfoMap = map[v1alpha1.ClusterComponentType]componentInfo{
+	v1alpha1.APIServerComponent: {label: map[string]string{"component": "kube-apiserver"}, container: "kube-apiserver"},
+	v1alpha1.EtcdComponent:      {label: map[string]string{"component": "etcd"}, container: "etcd"},
+	v1alpha1.CoreDNSComponent:   {label: map[string]string{"k8s-app": "kube-dns"}, container: "coredns"},
+}
+
+// ClusterComponentScopeHandler finds the pods of a control-plane component by its well-known labels, and translates
+// the component fault to the agent fault. The inject objects are pods, so the rest is the same as scope pod
+type ClusterComponentScopeHandler struct {
+	*pod.PodScopeHandler
+}
+
+var globalClusterComponentHandler = &ClusterComponentScopeHandler{PodScopeHandler: pod.GetGlobalPodHandler()}
+
+func GetGlobalClusterComponentHandler() *ClusterComponentScopeHandler {
+	return globalClusterComponentHandler
+}
+
+func (h *ClusterComponentScopeHandler) ConvertSelector(ctx context.Context, spec *v1alpha1.ExperimentSpec) ([]model.AtomicObject, error) {
+	component := v1alpha1.ClusterComponentType(spec.Experiment.Target)
+	info, ok := componentInfoMap[component]
+	if !ok {
+		return nil, fmt.Errorf("not support cluster component: %s", component)
+	}
+
+	containerName := common.GetArgs(spec.Experiment.Args, []string{v1alpha1.ContainerKey})[0]
+	if containerName == "" {
+		containerName = info.container
+	}
+
+	namespace, names := defaultComponentNamespace, make(map[string]bool)
+	for _, unitSelector := range spec.Selector {
+		if unitSelector.Namespace != "" {
+			namespace = unitSelector.Namespace
+		}
+		for _, name := range unitSelector.Name {
+			names[name] = true
+		}
+	}
+
+	podList, err := selector.GetAnalyzer().GetPodListByLabel(ctx, namespace, info.label, containerName)
+	if err != nil {
+		return nil, fmt.Errorf("get pods of %s error: %s", component, err.Error())
+	}
+
+	var result []model.AtomicObject
+	for _, unitPod := range podList {
+		if len(names) != 0 && !names[unitPod.PodName] {
+			continue
+		}
+		result = append(result, unitPod)
+	}
+
+	if err := checkGuardrail(component, spec.RangeMode, len(result), len(podList)); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (h *ClusterComponentScopeHandler) ExecuteInject(ctx context.Context, injectObject model.AtomicObject, UID string, expArgs *v1alpha1.ExperimentCommon) (string, error) {
+	agentExp, err := getAgentExperiment(expArgs)
+	if err != nil {
+		return "", err
+	}
+
+	return h.PodScopeHandler.ExecuteInject(ctx, injectObject, UID, agentExp)
+}
+
+func (h *ClusterComponentScopeHandler) Preflight(ctx context.Context, injectObject model.AtomicObject, expArgs *v1alpha1.ExperimentCommon) ([]string, error) {
+	agentExp, err := getAgentExperiment(expArgs)
+	if err != nil {
+		return nil, err
+	}
+
+	return h.PodScopeHandler.Preflight(ctx, injectObject, agentExp)
+}
+
+// checkGuardrail checks the size of the component, the webhook has already limited the range mode.
+// The count of targets is computed the same way as the range mode is solved
+func checkGuardrail(component v1alpha1.ClusterComponentType, rangeMode *v1alpha1.RangeMode, candidates, total int) error {
+	switch component {
+	case v1alpha1.EtcdComponent:
+		if total < minEtcdMemberCount {
+			return fmt.Errorf("%s has %d members, isolating one of them may break the quorum, at least %d members are required", component, total, minEtcdMemberCount)
+		}
+	case v1alpha1.CoreDNSComponent:
+		if rangeMode == nil {
+			return fmt.Errorf("at least one pod of %s must be left, \"rangeMode\" is required", component)
+		}
+
+		count := candidates
+		if rangeMode.Type == v1alpha1.CountRangeType && rangeMode.Value < count {
+			count = rangeMode.Value
+		} else if rangeMode.Type == v1alpha1.PercentRangeType {
+			count = rangeMode.Value * candidates / 100
+		}
+		if count >= total {
+			return fmt.Errorf("%s has %d pods, at least one of them must be left, but %d are selected", component, total, count)
+		}
+	}
+
+	return nil
+}
+
+// getAgentExperiment translates the component fault to the fault of chaosmetad executed in the pod
+func getAgentExperiment(expArgs *v1alpha1.ExperimentCommon) (*v1alpha1.ExperimentCommon, error) {
+	agentExp := &v1alpha1.ExperimentCommon{
+		Duration: expArgs.Duration,
+	}
+
+	switch v1alpha1.ClusterComponentType(expArgs.Target) {
+	case v1alpha1.APIServerComponent:
+		reArgs := common.GetArgs(expArgs.Args, []string{"latency", "jitter", "port"})
+		if reArgs[2] == "" {
+			reArgs[2] = defaultAPIServerPort
+		}
+
+		// kube-apiserver is in the host network, only its responses are delayed
+		agentExp.Target, agentExp.Fault = "network", "delay"
+		agentExp.Args = []v1alpha1.ArgsUnit{
+			{Key: "latency", Value: reArgs[0], ValueType: v1alpha1.StringVType},
+			{Key: "src-port", Value: reArgs[2], ValueType: v1alpha1.StringVType},
+		}
+		if reArgs[1] != "" {
+			agentExp.Args = append(agentExp.Args, v1alpha1.ArgsUnit{Key: "jitter", Value: reArgs[1], ValueType: v1alpha1.StringVType})
+		}
+	case v1alpha1.EtcdComponent:
+		port := common.GetArgs(expArgs.Args, []string{"port"})[0]
+		if port == "" {
+			port = defaultEtcdPeerPort
+		}
+
+		// only the peer traffic is dropped, the member is still reachable by kube-apiserver
+		agentExp.Target, agentExp.Fault = "network", "loss"
+		agentExp.Args = []v1alpha1.ArgsUnit{
+			{Key: "percent", Value: "100", ValueType: v1alpha1.IntVType},
+			{Key: "dst-port", Value: port, ValueType: v1alpha1.StringVType},
+		}
+	case v1alpha1.CoreDNSComponent:
+		agentExp.Target, agentExp.Fault = "process", "stop"
+		agentExp.Args = []v1alpha1.ArgsUnit{
+			{Key: "key", Value: "coredns", ValueType: v1alpha1.StringVType},
+		}
+	default:
+		return nil, fmt.Errorf("not support cluster component: %s", expArgs.Target)
+	}
+
+	return agentExp, nil
+}
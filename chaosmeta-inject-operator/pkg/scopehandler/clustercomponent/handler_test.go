@@ -0,0 +1,100 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package clustercomponent
+
+import (
+	"context"
+	"github.com/agiledragon/gomonkey"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	mockselector "github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/mock/selector"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/model"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/selector"
+	"testing"
+)
+
+func TestClusterComponentScopeHandler_ConvertSelector(t *testing.T) {
+	var (
+		spec = &v1alpha1.ExperimentSpec{
+			Scope: v1alpha1.ClusterComponentScopeType,
+			RangeMode: &v1alpha1.RangeMode{
+				Type:  v1alpha1.CountRangeType,
+				Value: 1,
+			},
+			Experiment: &v1alpha1.ExperimentCommon{
+				Duration: "2m",
+				Target:   string(v1alpha1.CoreDNSComponent),
+				Fault:    v1alpha1.CoreDNSFailureFault,
+			},
+			TargetPhase: v1alpha1.InjectPhaseType,
+		}
+		podList = []*model.PodObject{
+			{Namespace: defaultComponentNamespace, PodName: "coredns-a", ContainerName: "coredns"},
+			{Namespace: defaultComponentNamespace, PodName: "coredns-b", ContainerName: "coredns"},
+		}
+	)
+
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	analyzerMock := mockselector.NewMockIAnalyzer(ctrl)
+	analyzerMock.EXPECT().GetPodListByLabel(ctx, defaultComponentNamespace, componentInfoMap[v1alpha1.CoreDNSComponent].label, "coredns").Return(podList, nil).Times(3)
+	gomonkey.ApplyFunc(selector.GetAnalyzer, func() selector.IAnalyzer {
+		return analyzerMock
+	})
+
+	reList, err := GetGlobalClusterComponentHandler().ConvertSelector(ctx, spec)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 2, len(reList))
+
+	// the other coredns pod is left when selecting by name
+	spec.Selector = []v1alpha1.SelectorUnit{{Name: []string{"coredns-b"}}}
+	reList, err = GetGlobalClusterComponentHandler().ConvertSelector(ctx, spec)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "pod/kube-system/coredns-b/coredns", reList[0].GetObjectName())
+
+	spec.Selector, spec.RangeMode.Value = nil, 2
+	_, err = GetGlobalClusterComponentHandler().ConvertSelector(ctx, spec)
+	assert.NotEqual(t, nil, err)
+}
+
+func Test_checkGuardrail(t *testing.T) {
+	countOne := &v1alpha1.RangeMode{Type: v1alpha1.CountRangeType, Value: 1}
+	assert.NotEqual(t, nil, checkGuardrail(v1alpha1.EtcdComponent, countOne, 2, 2))
+	assert.Equal(t, nil, checkGuardrail(v1alpha1.EtcdComponent, countOne, 3, 3))
+
+	half := &v1alpha1.RangeMode{Type: v1alpha1.PercentRangeType, Value: 50}
+	assert.Equal(t, nil, checkGuardrail(v1alpha1.CoreDNSComponent, half, 2, 2))
+	assert.NotEqual(t, nil, checkGuardrail(v1alpha1.CoreDNSComponent, countOne, 1, 1))
+}
+
+func Test_getAgentExperiment(t *testing.T) {
+	agentExp, err := getAgentExperiment(&v1alpha1.ExperimentCommon{
+		Duration: "1m",
+		Target:   string(v1alpha1.APIServerComponent),
+		Fault:    v1alpha1.APIServerDelayFault,
+		Args:     []v1alpha1.ArgsUnit{{Key: "latency", Value: "200ms"}},
+	})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "network", agentExp.Target)
+	assert.Equal(t, "delay", agentExp.Fault)
+	assert.Equal(t, []v1alpha1.ArgsUnit{
+		{Key: "latency", Value: "200ms", ValueType: v1alpha1.StringVType},
+		{Key: "src-port", Value: defaultAPIServerPort, ValueType: v1alpha1.StringVType},
+	}, agentExp.Args)
+}
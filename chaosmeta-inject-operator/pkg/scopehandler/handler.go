@@ -18,11 +18,16 @@ package scopehandler
 
 import (
 	"context"
+	"fmt"
 	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
 	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/model"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/scopehandler/clustercomponent"
 	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/scopehandler/kubernetes"
 	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/scopehandler/node"
 	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/scopehandler/pod"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/scopehandler/workload"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"time"
 )
 
 type ScopeHandler interface {
@@ -32,6 +37,10 @@ type ScopeHandler interface {
 	ExecuteRecover(ctx context.Context, injectObject model.AtomicObject, UID, backup string, expArgs *v1alpha1.ExperimentCommon) error
 	GetInjectObject(ctx context.Context, exp *v1alpha1.ExperimentCommon, objectName string) (model.AtomicObject, error)
 	CheckAlive(ctx context.Context, injectObject model.AtomicObject) error
+	Diagnose(ctx context.Context, injectObject model.AtomicObject) (*model.AgentDiagnostics, error)
+	Snapshot(ctx context.Context, injectObject model.AtomicObject) (*v1alpha1.EnvSnapshot, error)
+	// Preflight returns the reasons why the fault can not be injected into the target, empty means ready
+	Preflight(ctx context.Context, injectObject model.AtomicObject, expArgs *v1alpha1.ExperimentCommon) ([]string, error)
 }
 
 func GetScopeHandler(scope v1alpha1.ScopeType) ScopeHandler {
@@ -42,7 +51,102 @@ func GetScopeHandler(scope v1alpha1.ScopeType) ScopeHandler {
 		return node.GetGlobalNodeHandler()
 	case v1alpha1.KubernetesScopeType:
 		return kubernetes.GetGlobalKubernetesHandler()
+	case v1alpha1.StatefulSetScopeType:
+		return workload.GetGlobalStatefulSetHandler()
+	case v1alpha1.DaemonSetScopeType:
+		return workload.GetGlobalDaemonSetHandler()
+	case v1alpha1.JobScopeType:
+		return workload.GetGlobalJobHandler()
+	case v1alpha1.CronJobScopeType:
+		return workload.GetGlobalCronJobHandler()
+	case v1alpha1.ServiceScopeType:
+		return workload.GetGlobalServiceHandler()
+	case v1alpha1.ClusterComponentScopeType:
+		return clustercomponent.GetGlobalClusterComponentHandler()
 	default:
 		return nil
 	}
 }
+
+// GetTargetExecutor returns the remote executor selected by the label "chaosmeta.io/executor" of the pod or node,
+// empty means the executor of the experiment
+func GetTargetExecutor(obj model.AtomicObject) string {
+	switch o := obj.(type) {
+	case *model.PodObject:
+		return o.Labels[v1alpha1.ExecutorLabelKey]
+	case *model.NodeObject:
+		return o.Labels[v1alpha1.ExecutorLabelKey]
+	default:
+		return ""
+	}
+}
+
+// GetResolvedTarget returns what the pod or node is when it is selected, nil for the other targets
+func GetResolvedTarget(obj model.AtomicObject, resolvedTime string) *v1alpha1.ResolvedTarget {
+	switch o := obj.(type) {
+	case *model.PodObject:
+		return &v1alpha1.ResolvedTarget{
+			PodUID:           o.PodUID,
+			PodIP:            o.PodIP,
+			NodeName:         o.NodeName,
+			NodeIP:           o.NodeIP,
+			ContainerName:    o.ContainerName,
+			ContainerID:      o.ContainerID,
+			ContainerRuntime: o.ContainerRuntime,
+			ContainerImage:   o.ContainerImage,
+			ResolvedTime:     resolvedTime,
+		}
+	case *model.NodeObject:
+		return &v1alpha1.ResolvedTarget{
+			NodeName:         o.NodeName,
+			NodeIP:           o.NodeInternalIP,
+			ContainerID:      o.ContainerID,
+			ContainerRuntime: o.ContainerRuntime,
+			ResolvedTime:     resolvedTime,
+		}
+	default:
+		return nil
+	}
+}
+
+const defaultReresolveInterval = 30 * time.Second
+
+// IsReresolvable reports whether the selector is resolved again while the experiment is running to inject into the new pods,
+// always for the pods of jobs which are expected to be replaced and the backends of services which churn,
+// and for the other pods when "reresolve" is set
+func IsReresolvable(spec *v1alpha1.ExperimentSpec) bool {
+	return spec.Reresolve != nil || spec.Scope == v1alpha1.JobScopeType || spec.Scope == v1alpha1.CronJobScopeType ||
+		spec.Scope == v1alpha1.ServiceScopeType
+}
+
+// GetReresolveInterval returns 0 for the jobs and services without "reresolve", their targets are resolved again in every round
+func GetReresolveInterval(spec *v1alpha1.ExperimentSpec) time.Duration {
+	if spec.Reresolve == nil {
+		return 0
+	}
+
+	if spec.Reresolve.Interval == "" {
+		return defaultReresolveInterval
+	}
+
+	interval, err := v1alpha1.ConvertDuration(spec.Reresolve.Interval)
+	if err != nil {
+		return defaultReresolveInterval
+	}
+
+	return interval
+}
+
+// TakeSnapshot never fails the experiment, the error is recorded in the snapshot instead
+func TakeSnapshot(ctx context.Context, h ScopeHandler, injectObject model.AtomicObject) *v1alpha1.EnvSnapshot {
+	snapshot, err := h.Snapshot(ctx, injectObject)
+	if err != nil {
+		log.FromContext(ctx).Error(err, fmt.Sprintf("take snapshot of %s error", injectObject.GetObjectName()))
+		return &v1alpha1.EnvSnapshot{
+			Time:   time.Now().Format(model.TimeFormat),
+			Errors: []string{fmt.Sprintf("take snapshot error: %s", err.Error())},
+		}
+	}
+
+	return snapshot
+}
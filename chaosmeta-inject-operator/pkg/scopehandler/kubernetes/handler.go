@@ -55,6 +55,20 @@ func (k KubernetesScopeHandler) ConvertSelector(ctx context.Context, spec *v1alp
 func (k KubernetesScopeHandler) GetInjectObject(ctx context.Context, exp *v1alpha1.ExperimentCommon, objectName string) (model.AtomicObject, error) {
 	switch v1alpha1.CloudTargetType(exp.Target) {
 	case v1alpha1.PodCloudTarget:
+		// the pod of fault delete and evict is expected to be gone, its executor checks the pod by itself
+		if exp.Fault == "delete" || exp.Fault == "evict" {
+			ns, name, containerName, err := model.ParsePodInfo(objectName)
+			if err != nil {
+				return nil, fmt.Errorf("unexpected pod object name: %s", objectName)
+			}
+
+			return &model.PodObject{
+				Namespace:     ns,
+				PodName:       name,
+				ContainerName: containerName,
+			}, nil
+		}
+
 		return pod.GetGlobalPodHandler().GetInjectObject(ctx, exp, objectName)
 	case v1alpha1.DeploymentCloudTarget:
 		ns, name, err := model.ParseDeploymentInfo(objectName)
@@ -96,6 +110,21 @@ func (k KubernetesScopeHandler) CheckAlive(ctx context.Context, injectObject mod
 	return nil
 }
 
+// Diagnose kubernetes faults are executed by the operator itself, there is no agent to diagnose
+func (k KubernetesScopeHandler) Diagnose(ctx context.Context, injectObject model.AtomicObject) (*model.AgentDiagnostics, error) {
+	return nil, nil
+}
+
+// Snapshot kubernetes targets have no host to take a snapshot of
+func (k KubernetesScopeHandler) Snapshot(ctx context.Context, injectObject model.AtomicObject) (*v1alpha1.EnvSnapshot, error) {
+	return nil, nil
+}
+
+// Preflight kubernetes faults are executed by the operator itself, there is no agent to check
+func (k KubernetesScopeHandler) Preflight(ctx context.Context, injectObject model.AtomicObject, expArgs *v1alpha1.ExperimentCommon) ([]string, error) {
+	return nil, nil
+}
+
 func convertCluster(ctx context.Context, spec *v1alpha1.ExperimentSpec) ([]model.AtomicObject, error) {
 	args := common.GetArgs(spec.Experiment.Args, []string{"namespace"})
 	if args[0] == "" {
@@ -116,22 +145,30 @@ func convertDeploy(ctx context.Context, spec *v1alpha1.ExperimentSpec) ([]model.
 	)
 
 	for _, unitSelector := range spec.Selector {
-		if unitSelector.Namespace == "" {
-			return nil, fmt.Errorf("selector of scope deployment must provide namespace")
+		if unitSelector.Namespace == "" && len(unitSelector.NamespaceSelector) == 0 {
+			return nil, fmt.Errorf("selector of scope deployment must provide namespace or namespaceSelector")
 		}
 
-		resultUnitSelector, err := getDeployObjectFromSelector(ctx, unitSelector)
+		namespaces, err := selector.GetSelectorNamespaces(ctx, unitSelector)
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("get namespaces of selector error: %s", err.Error())
 		}
 
-		for _, unitObj := range resultUnitSelector {
-			// Deduplication
-			if isExist[unitObj.GetObjectName()] {
-				continue
+		for _, unitNamespace := range namespaces {
+			unitSelector.Namespace = unitNamespace
+			resultUnitSelector, err := getDeployObjectFromSelector(ctx, unitSelector)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, unitObj := range resultUnitSelector {
+				// Deduplication
+				if isExist[unitObj.GetObjectName()] {
+					continue
+				}
+				isExist[unitObj.GetObjectName()] = true
+				result = append(result, unitObj)
 			}
-			isExist[unitObj.GetObjectName()] = true
-			result = append(result, unitObj)
 		}
 	}
 
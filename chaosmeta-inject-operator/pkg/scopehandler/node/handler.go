@@ -68,7 +68,34 @@ func (h *NodeScopeHandler) CheckAlive(ctx context.Context, injectObject model.At
 		return fmt.Errorf("inject object change to node error")
 	}
 
-	return remoteexecutor.GetRemoteExecutor().CheckAlive(ctx, node.NodeInternalIP)
+	return remoteexecutor.GetRemoteExecutor(node.Executor).CheckAlive(ctx, node.NodeInternalIP)
+}
+
+func (h *NodeScopeHandler) Diagnose(ctx context.Context, injectObject model.AtomicObject) (*model.AgentDiagnostics, error) {
+	node, ok := injectObject.(*model.NodeObject)
+	if !ok {
+		return nil, fmt.Errorf("inject object change to node error")
+	}
+
+	return remoteexecutor.GetRemoteExecutor(node.Executor).Diagnose(ctx, node.NodeInternalIP, node.ContainerRuntime)
+}
+
+func (h *NodeScopeHandler) Preflight(ctx context.Context, injectObject model.AtomicObject, expArgs *v1alpha1.ExperimentCommon) ([]string, error) {
+	node, ok := injectObject.(*model.NodeObject)
+	if !ok {
+		return nil, fmt.Errorf("inject object change to node error")
+	}
+
+	return remoteexecutor.GetRemoteExecutor(node.Executor).Preflight(ctx, node.NodeInternalIP, expArgs.Target, expArgs.Fault, node.ContainerID, node.ContainerRuntime)
+}
+
+func (h *NodeScopeHandler) Snapshot(ctx context.Context, injectObject model.AtomicObject) (*v1alpha1.EnvSnapshot, error) {
+	node, ok := injectObject.(*model.NodeObject)
+	if !ok {
+		return nil, fmt.Errorf("inject object change to node error")
+	}
+
+	return remoteexecutor.GetRemoteExecutor(node.Executor).Snapshot(ctx, node.NodeInternalIP, "", "")
 }
 
 func (h *NodeScopeHandler) QueryExperiment(ctx context.Context, injectObject model.AtomicObject, UID, backup string, expArgs *v1alpha1.ExperimentCommon, phase v1alpha1.PhaseType) (*model.SubExpInfo, error) {
@@ -77,7 +104,7 @@ func (h *NodeScopeHandler) QueryExperiment(ctx context.Context, injectObject mod
 		return nil, fmt.Errorf("inject object change to node error")
 	}
 
-	return remoteexecutor.GetRemoteExecutor().Query(ctx, node.NodeInternalIP, UID, phase)
+	return remoteexecutor.GetRemoteExecutor(node.Executor).Query(ctx, node.NodeInternalIP, UID, phase)
 }
 
 func (h *NodeScopeHandler) ExecuteInject(ctx context.Context, injectObject model.AtomicObject, UID string, expArgs *v1alpha1.ExperimentCommon) (string, error) {
@@ -87,10 +114,10 @@ func (h *NodeScopeHandler) ExecuteInject(ctx context.Context, injectObject model
 	}
 
 	if node.ContainerID != "" {
-		return "", remoteexecutor.GetRemoteExecutor().Inject(ctx, node.NodeInternalIP, expArgs.Target, expArgs.Fault, UID, expArgs.Duration, node.ContainerID, node.ContainerRuntime, expArgs.Args)
+		return "", remoteexecutor.GetRemoteExecutor(node.Executor).Inject(ctx, node.NodeInternalIP, expArgs.Target, expArgs.Fault, UID, expArgs.Duration, node.ContainerID, node.ContainerRuntime, expArgs.Args)
 	}
 
-	return "", remoteexecutor.GetRemoteExecutor().Inject(ctx, node.NodeInternalIP, expArgs.Target, expArgs.Fault, UID, expArgs.Duration, "", "", expArgs.Args)
+	return "", remoteexecutor.GetRemoteExecutor(node.Executor).Inject(ctx, node.NodeInternalIP, expArgs.Target, expArgs.Fault, UID, expArgs.Duration, "", "", expArgs.Args)
 }
 
 func (h *NodeScopeHandler) ExecuteRecover(ctx context.Context, injectObject model.AtomicObject, UID, backup string, expArgs *v1alpha1.ExperimentCommon) error {
@@ -99,7 +126,7 @@ func (h *NodeScopeHandler) ExecuteRecover(ctx context.Context, injectObject mode
 		return fmt.Errorf("inject object change to node error")
 	}
 
-	return remoteexecutor.GetRemoteExecutor().Recover(ctx, node.NodeInternalIP, UID)
+	return remoteexecutor.GetRemoteExecutor(node.Executor).Recover(ctx, node.NodeInternalIP, UID)
 }
 
 func (h *NodeScopeHandler) GetInjectObject(ctx context.Context, exp *v1alpha1.ExperimentCommon, objectName string) (model.AtomicObject, error) {
@@ -111,6 +138,7 @@ func (h *NodeScopeHandler) GetInjectObject(ctx context.Context, exp *v1alpha1.Ex
 	nodeInfo := &model.NodeObject{
 		NodeName:       nodeName,
 		NodeInternalIP: nodeIP,
+		Executor:       exp.Executor,
 	}
 
 	containerName := common.GetArgs(exp.Args, []string{v1alpha1.ContainerKey})[0]
@@ -143,6 +171,14 @@ func getNodeObjectList(ctx context.Context, selectorUnit v1alpha1.SelectorUnit,
 		return nil, fmt.Errorf("get node list error: %s", err.Error())
 	}
 
+	nodeList = selector.ExcludeNodes(selectorUnit, nodeList)
+	if selectorUnit.Field != nil {
+		nodeList, err = analyzer.FilterNodeListByField(ctx, nodeList, selectorUnit.Field)
+		if err != nil {
+			return nil, fmt.Errorf("filter node list by field error: %s", err.Error())
+		}
+	}
+
 	var result = make([]model.AtomicObject, len(nodeList))
 	for i := range nodeList {
 		result[i] = nodeList[i]
@@ -47,22 +47,30 @@ func (h *PodScopeHandler) ConvertSelector(ctx context.Context, spec *v1alpha1.Ex
 	//}
 
 	for _, unitSelector := range spec.Selector {
-		if unitSelector.Namespace == "" {
-			return nil, fmt.Errorf("selector of scope pod must provide namespace")
+		if unitSelector.Namespace == "" && len(unitSelector.NamespaceSelector) == 0 {
+			return nil, fmt.Errorf("selector of scope pod must provide namespace or namespaceSelector")
 		}
 
-		resultUnitSelector, err := getPodObjectList(ctx, unitSelector, argsList[0])
+		namespaces, err := selector.GetSelectorNamespaces(ctx, unitSelector)
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("get namespaces of selector error: %s", err.Error())
 		}
 
-		for _, unitObj := range resultUnitSelector {
-			// Pod Deduplication
-			if isExist[unitObj.GetObjectName()] {
-				continue
+		for _, unitNamespace := range namespaces {
+			unitSelector.Namespace = unitNamespace
+			resultUnitSelector, err := getPodObjectList(ctx, unitSelector, argsList[0])
+			if err != nil {
+				return nil, err
+			}
+
+			for _, unitObj := range resultUnitSelector {
+				// Pod Deduplication
+				if isExist[unitObj.GetObjectName()] {
+					continue
+				}
+				isExist[unitObj.GetObjectName()] = true
+				result = append(result, unitObj)
 			}
-			isExist[unitObj.GetObjectName()] = true
-			result = append(result, unitObj)
 		}
 	}
 
@@ -76,7 +84,13 @@ func (h *PodScopeHandler) GetInjectObject(ctx context.Context, exp *v1alpha1.Exp
 		return nil, fmt.Errorf("unexpected pod object name: %s", objectName)
 	}
 
-	return analyzer.GetPod(ctx, ns, podName, containerName)
+	pod, err := analyzer.GetPod(ctx, ns, podName, containerName)
+	if err != nil {
+		return nil, err
+	}
+
+	pod.Executor = exp.Executor
+	return pod, nil
 }
 
 func (h *PodScopeHandler) CheckAlive(ctx context.Context, injectObject model.AtomicObject) error {
@@ -85,7 +99,38 @@ func (h *PodScopeHandler) CheckAlive(ctx context.Context, injectObject model.Ato
 		return fmt.Errorf("inject object change to pod error")
 	}
 
-	return remoteexecutor.GetRemoteExecutor().CheckAlive(ctx, pod.NodeIP)
+	executor, object := getExecutor(pod)
+	return executor.CheckAlive(ctx, object)
+}
+
+func (h *PodScopeHandler) Diagnose(ctx context.Context, injectObject model.AtomicObject) (*model.AgentDiagnostics, error) {
+	pod, ok := injectObject.(*model.PodObject)
+	if !ok {
+		return nil, fmt.Errorf("inject object change to pod error")
+	}
+
+	executor, object := getExecutor(pod)
+	return executor.Diagnose(ctx, object, pod.ContainerRuntime)
+}
+
+func (h *PodScopeHandler) Preflight(ctx context.Context, injectObject model.AtomicObject, expArgs *v1alpha1.ExperimentCommon) ([]string, error) {
+	pod, ok := injectObject.(*model.PodObject)
+	if !ok {
+		return nil, fmt.Errorf("inject object change to pod error")
+	}
+
+	executor, object := getExecutor(pod)
+	return executor.Preflight(ctx, object, expArgs.Target, expArgs.Fault, pod.ContainerID, pod.ContainerRuntime)
+}
+
+func (h *PodScopeHandler) Snapshot(ctx context.Context, injectObject model.AtomicObject) (*v1alpha1.EnvSnapshot, error) {
+	pod, ok := injectObject.(*model.PodObject)
+	if !ok {
+		return nil, fmt.Errorf("inject object change to pod error")
+	}
+
+	executor, object := getExecutor(pod)
+	return executor.Snapshot(ctx, object, pod.ContainerID, pod.ContainerRuntime)
 }
 
 func (h *PodScopeHandler) QueryExperiment(ctx context.Context, injectObject model.AtomicObject, UID, backup string, expArgs *v1alpha1.ExperimentCommon, phase v1alpha1.PhaseType) (*model.SubExpInfo, error) {
@@ -94,7 +139,8 @@ func (h *PodScopeHandler) QueryExperiment(ctx context.Context, injectObject mode
 		return nil, fmt.Errorf("inject object change to container error")
 	}
 
-	return remoteexecutor.GetRemoteExecutor().Query(ctx, container.NodeIP, UID, phase)
+	executor, object := getExecutor(container)
+	return executor.Query(ctx, object, UID, phase)
 
 }
 
@@ -108,7 +154,8 @@ func (h *PodScopeHandler) ExecuteInject(ctx context.Context, injectObject model.
 		return "", fmt.Errorf("container not provide")
 	}
 
-	return "", remoteexecutor.GetRemoteExecutor().Inject(ctx, p.NodeIP, expArgs.Target, expArgs.Fault, UID, expArgs.Duration, p.ContainerID, p.ContainerRuntime, expArgs.Args)
+	executor, object := getExecutor(p)
+	return "", executor.Inject(ctx, object, expArgs.Target, expArgs.Fault, UID, expArgs.Duration, p.ContainerID, p.ContainerRuntime, expArgs.Args)
 }
 
 func (h *PodScopeHandler) ExecuteRecover(ctx context.Context, injectObject model.AtomicObject, UID, backup string, expArgs *v1alpha1.ExperimentCommon) error {
@@ -117,7 +164,18 @@ func (h *PodScopeHandler) ExecuteRecover(ctx context.Context, injectObject model
 		return fmt.Errorf("inject object change to pod error")
 	}
 
-	return remoteexecutor.GetRemoteExecutor().Recover(ctx, container.NodeIP, UID)
+	executor, object := getExecutor(container)
+	return executor.Recover(ctx, object, UID)
+}
+
+// getExecutor returns the remote executor of the pod and the inject object it is called with,
+// the executors of pod mode run in the pod itself, the others run on the node of the pod
+func getExecutor(pod *model.PodObject) (remoteexecutor.RemoteExecutor, string) {
+	if remoteexecutor.IsPodMode(pod.Executor) {
+		return remoteexecutor.GetRemoteExecutor(pod.Executor), pod.GetObjectName()
+	}
+
+	return remoteexecutor.GetRemoteExecutor(pod.Executor), pod.NodeIP
 }
 
 func getPodObjectList(ctx context.Context, selectorUnit v1alpha1.SelectorUnit, containerName string) ([]model.AtomicObject, error) {
@@ -129,6 +187,11 @@ func getPodObjectList(ctx context.Context, selectorUnit v1alpha1.SelectorUnit, c
 		if err != nil {
 			return nil, fmt.Errorf("get pod info by podname list error: %s", err.Error())
 		}
+	} else if selectorUnit.Owner != nil {
+		podList, err = analyzer.GetPodListByOwner(ctx, selectorUnit.Namespace, selectorUnit.Owner, containerName)
+		if err != nil {
+			return nil, fmt.Errorf("get pod info by owner error: %s", err.Error())
+		}
 	} else {
 		podList, err = analyzer.GetPodListByLabel(ctx, selectorUnit.Namespace, selectorUnit.Label, containerName)
 		if err != nil {
@@ -136,6 +199,14 @@ func getPodObjectList(ctx context.Context, selectorUnit v1alpha1.SelectorUnit, c
 		}
 	}
 
+	podList = selector.ExcludePods(selectorUnit, podList)
+	if selectorUnit.Field != nil {
+		podList, err = analyzer.FilterPodListByField(ctx, podList, selectorUnit.Field)
+		if err != nil {
+			return nil, fmt.Errorf("filter pod info by field error: %s", err.Error())
+		}
+	}
+
 	var result = make([]model.AtomicObject, len(podList))
 	for i := range podList {
 		result[i] = podList[i]
@@ -23,6 +23,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
 	mockselector "github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/mock/selector"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/executor/remoteexecutor"
 	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/model"
 	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/selector"
 	"testing"
@@ -100,3 +101,58 @@ func TestPodScopeHandler_ConvertSelector(t *testing.T) {
 	assert.Equal(t, nil, err)
 	assert.Equal(t, 2, len(reList))
 }
+
+func TestPodScopeHandler_ConvertSelector_NamespaceSelector(t *testing.T) {
+	var (
+		nsLabel = map[string]string{"team": "payments"}
+		label   = map[string]string{"app": "pay"}
+		spec    = &v1alpha1.ExperimentSpec{
+			Scope: v1alpha1.PodScopeType,
+			Experiment: &v1alpha1.ExperimentCommon{
+				Duration: "2m",
+				Target:   "cpu",
+				Fault:    "burn",
+			},
+			Selector: []v1alpha1.SelectorUnit{
+				{
+					NamespaceSelector: nsLabel,
+					Label:             label,
+				},
+			},
+			TargetPhase: v1alpha1.InjectPhaseType,
+		}
+	)
+
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	analyzerMock := mockselector.NewMockIAnalyzer(ctrl)
+	analyzerMock.EXPECT().GetNamespaceListByLabel(ctx, nsLabel).Return([]string{"pay-1", "pay-2"}, nil)
+	analyzerMock.EXPECT().GetPodListByLabel(ctx, "pay-1", label, "").Return([]*model.PodObject{{Namespace: "pay-1", PodName: "pod1"}}, nil)
+	analyzerMock.EXPECT().GetPodListByLabel(ctx, "pay-2", label, "").Return([]*model.PodObject{{Namespace: "pay-2", PodName: "pod1"}}, nil)
+	gomonkey.ApplyFunc(selector.GetAnalyzer, func() selector.IAnalyzer {
+		return analyzerMock
+	})
+
+	reList, err := GetGlobalPodHandler().ConvertSelector(ctx, spec)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 2, len(reList))
+	assert.Equal(t, "pod/pay-2/pod1", reList[1].GetObjectName())
+}
+
+func Test_getExecutor(t *testing.T) {
+	pod := &model.PodObject{
+		Namespace:     "default",
+		PodName:       "pod1",
+		ContainerName: "c1",
+		NodeIP:        "1.1.1.1",
+	}
+
+	_, object := getExecutor(pod)
+	assert.Equal(t, "1.1.1.1", object)
+
+	// the ephemeral executor runs in the pod
+	pod.Executor = string(remoteexecutor.EphemeralRemoteMode)
+	_, object = getExecutor(pod)
+	assert.Equal(t, "pod/default/pod1/c1", object)
+}
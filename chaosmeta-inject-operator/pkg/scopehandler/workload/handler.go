@@ -0,0 +1,157 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package workload
+
+import (
+	"context"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/common"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/model"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/scopehandler/pod"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/selector"
+)
+
+// WorkloadScopeHandler selects pods through their controller or service, the inject objects are still pods,
+// so everything except the selector conversion is handled the same way as scope pod
+type WorkloadScopeHandler struct {
+	*pod.PodScopeHandler
+	scope v1alpha1.ScopeType
+}
+
+var (
+	globalStatefulSetHandler = &WorkloadScopeHandler{PodScopeHandler: pod.GetGlobalPodHandler(), scope: v1alpha1.StatefulSetScopeType}
+	globalDaemonSetHandler   = &WorkloadScopeHandler{PodScopeHandler: pod.GetGlobalPodHandler(), scope: v1alpha1.DaemonSetScopeType}
+	globalJobHandler         = &WorkloadScopeHandler{PodScopeHandler: pod.GetGlobalPodHandler(), scope: v1alpha1.JobScopeType}
+	globalCronJobHandler     = &WorkloadScopeHandler{PodScopeHandler: pod.GetGlobalPodHandler(), scope: v1alpha1.CronJobScopeType}
+	globalServiceHandler     = &WorkloadScopeHandler{PodScopeHandler: pod.GetGlobalPodHandler(), scope: v1alpha1.ServiceScopeType}
+)
+
+func GetGlobalStatefulSetHandler() *WorkloadScopeHandler {
+	return globalStatefulSetHandler
+}
+
+func GetGlobalDaemonSetHandler() *WorkloadScopeHandler {
+	return globalDaemonSetHandler
+}
+
+func GetGlobalJobHandler() *WorkloadScopeHandler {
+	return globalJobHandler
+}
+
+func GetGlobalCronJobHandler() *WorkloadScopeHandler {
+	return globalCronJobHandler
+}
+
+func GetGlobalServiceHandler() *WorkloadScopeHandler {
+	return globalServiceHandler
+}
+
+func (h *WorkloadScopeHandler) ConvertSelector(ctx context.Context, spec *v1alpha1.ExperimentSpec) ([]model.AtomicObject, error) {
+	var (
+		result  []model.AtomicObject
+		isExist = make(map[string]bool)
+	)
+
+	argsList := common.GetArgs(spec.Experiment.Args, []string{v1alpha1.ContainerKey})
+	for _, unitSelector := range spec.Selector {
+		if unitSelector.Namespace == "" && len(unitSelector.NamespaceSelector) == 0 {
+			return nil, fmt.Errorf("selector of scope %s must provide namespace or namespaceSelector", h.scope)
+		}
+
+		namespaces, err := selector.GetSelectorNamespaces(ctx, unitSelector)
+		if err != nil {
+			return nil, fmt.Errorf("get namespaces of selector error: %s", err.Error())
+		}
+
+		for _, unitNamespace := range namespaces {
+			unitSelector.Namespace = unitNamespace
+			podList, err := h.getPodObjectList(ctx, unitSelector, argsList[0])
+			if err != nil {
+				return nil, err
+			}
+
+			for _, unitObj := range podList {
+				// Pod Deduplication
+				if isExist[unitObj.GetObjectName()] {
+					continue
+				}
+				isExist[unitObj.GetObjectName()] = true
+				result = append(result, unitObj)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+func (h *WorkloadScopeHandler) getPodObjectList(ctx context.Context, selectorUnit v1alpha1.SelectorUnit, containerName string) ([]*model.PodObject, error) {
+	var (
+		podList []*model.PodObject
+		err     error
+	)
+
+	analyzer := selector.GetAnalyzer()
+	switch h.scope {
+	case v1alpha1.StatefulSetScopeType:
+		if len(selectorUnit.Name) != 0 {
+			podList, err = analyzer.GetStatefulSetPodListByName(ctx, selectorUnit.Namespace, selectorUnit.Name, containerName)
+		} else {
+			podList, err = analyzer.GetStatefulSetPodListByLabel(ctx, selectorUnit.Namespace, selectorUnit.Label, containerName)
+		}
+	case v1alpha1.DaemonSetScopeType:
+		if len(selectorUnit.Name) != 0 {
+			podList, err = analyzer.GetDaemonSetPodListByName(ctx, selectorUnit.Namespace, selectorUnit.Name, containerName)
+		} else {
+			podList, err = analyzer.GetDaemonSetPodListByLabel(ctx, selectorUnit.Namespace, selectorUnit.Label, containerName)
+		}
+	case v1alpha1.JobScopeType:
+		if len(selectorUnit.Name) != 0 {
+			podList, err = analyzer.GetJobPodListByName(ctx, selectorUnit.Namespace, selectorUnit.Name, containerName)
+		} else {
+			podList, err = analyzer.GetJobPodListByLabel(ctx, selectorUnit.Namespace, selectorUnit.Label, containerName)
+		}
+	case v1alpha1.CronJobScopeType:
+		if len(selectorUnit.Name) != 0 {
+			podList, err = analyzer.GetCronJobPodListByName(ctx, selectorUnit.Namespace, selectorUnit.Name, containerName)
+		} else {
+			podList, err = analyzer.GetCronJobPodListByLabel(ctx, selectorUnit.Namespace, selectorUnit.Label, containerName)
+		}
+	case v1alpha1.ServiceScopeType:
+		if len(selectorUnit.Name) != 0 {
+			podList, err = analyzer.GetServicePodListByName(ctx, selectorUnit.Namespace, selectorUnit.Name, containerName)
+		} else {
+			podList, err = analyzer.GetServicePodListByLabel(ctx, selectorUnit.Namespace, selectorUnit.Label, containerName)
+		}
+	default:
+		return nil, fmt.Errorf("unexpected workload scope: %s", h.scope)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("get pod info of %s error: %s", h.scope, err.Error())
+	}
+
+	podList = selector.ExcludePods(selectorUnit, podList)
+	if selectorUnit.Field != nil {
+		podList, err = analyzer.FilterPodListByField(ctx, podList, selectorUnit.Field)
+		if err != nil {
+			return nil, fmt.Errorf("filter pod info by field error: %s", err.Error())
+		}
+	}
+
+	return podList, nil
+}
@@ -0,0 +1,102 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package workload
+
+import (
+	"context"
+	"github.com/agiledragon/gomonkey"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	mockselector "github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/mock/selector"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/model"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/selector"
+	"testing"
+)
+
+func TestWorkloadScopeHandler_ConvertSelector(t *testing.T) {
+	var (
+		namespace     = "ns"
+		containerName = "mysql"
+		spec          = &v1alpha1.ExperimentSpec{
+			Scope: v1alpha1.StatefulSetScopeType,
+			Experiment: &v1alpha1.ExperimentCommon{
+				Duration: "2m",
+				Target:   "cpu",
+				Fault:    "burn",
+				Args: []v1alpha1.ArgsUnit{
+					{
+						Key:   v1alpha1.ContainerKey,
+						Value: containerName,
+					},
+				},
+			},
+			Selector: []v1alpha1.SelectorUnit{
+				{
+					Namespace: namespace,
+					Name:      []string{"mysql"},
+				},
+				{
+					Namespace: namespace,
+					Label: map[string]string{
+						"app": "mysql",
+					},
+				},
+			},
+			TargetPhase: v1alpha1.InjectPhaseType,
+		}
+		podList = []*model.PodObject{
+			{
+				Namespace:        namespace,
+				PodName:          "mysql-0",
+				NodeName:         "node1",
+				NodeIP:           "1.1.1.1",
+				ContainerName:    containerName,
+				ContainerID:      "ef2g24g21",
+				ContainerRuntime: "docker",
+			},
+			{
+				Namespace:        namespace,
+				PodName:          "mysql-1",
+				NodeName:         "node2",
+				NodeIP:           "1.1.1.2",
+				ContainerName:    containerName,
+				ContainerID:      "ef2g24g22",
+				ContainerRuntime: "docker",
+			},
+		}
+	)
+
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	analyzerMock := mockselector.NewMockIAnalyzer(ctrl)
+	analyzerMock.EXPECT().GetStatefulSetPodListByName(ctx, namespace, spec.Selector[0].Name, containerName).Return(podList, nil)
+	analyzerMock.EXPECT().GetStatefulSetPodListByLabel(ctx, namespace, spec.Selector[1].Label, containerName).Return(podList[1:], nil)
+	gomonkey.ApplyFunc(selector.GetAnalyzer, func() selector.IAnalyzer {
+		return analyzerMock
+	})
+
+	reList, err := GetGlobalStatefulSetHandler().ConvertSelector(ctx, spec)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 2, len(reList))
+	assert.Equal(t, "pod/ns/mysql-0/mysql", reList[0].GetObjectName())
+
+	spec.Selector[0].Namespace = ""
+	_, err = GetGlobalDaemonSetHandler().ConvertSelector(ctx, spec)
+	assert.NotEqual(t, nil, err)
+}
@@ -21,14 +21,22 @@ import (
 	"fmt"
 	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
 	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/model"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/multicluster"
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 const (
 	HostIPKey = ".status.hostIP"
 	PhaseKey  = ".status.phase"
+	MutexKey  = ".spec.mutex.name"
 )
 
 var (
@@ -46,25 +54,64 @@ func GetAnalyzer() IAnalyzer {
 }
 
 type IAnalyzer interface {
+	GetExperiment(ctx context.Context, namespace, name string) (*v1alpha1.Experiment, error)
 	GetExperimentListByPhase(ctx context.Context, phase string) (*v1alpha1.ExperimentList, error)
+	GetExperimentListByMutex(ctx context.Context, mutex string) (*v1alpha1.ExperimentList, error)
+
+	GetNamespaceListByLabel(ctx context.Context, label map[string]string) ([]string, error)
 
 	GetPod(ctx context.Context, ns, podName, containerName string) (*model.PodObject, error)
 	GetPodListByLabelInNode(ctx context.Context, namespace string, label map[string]string, nodeIP string) ([]*model.PodObject, error)
 	GetPodListByLabel(ctx context.Context, namespace string, label map[string]string, containerName string) ([]*model.PodObject, error)
 	GetPodListByPodName(ctx context.Context, namespace string, podName []string, containerName string) ([]*model.PodObject, error)
+	GetPodListByOwner(ctx context.Context, namespace string, owner *v1alpha1.OwnerSelector, containerName string) ([]*model.PodObject, error)
+	FilterPodListByField(ctx context.Context, podList []*model.PodObject, field *v1alpha1.FieldSelector) ([]*model.PodObject, error)
 
 	GetNodeListByLabel(ctx context.Context, label map[string]string, containerName string) ([]*model.NodeObject, error)
 	GetNodeListByNodeName(ctx context.Context, nodeName []string, containerName string) ([]*model.NodeObject, error)
 	GetNodeListByNodeIP(ctx context.Context, nodeIP []string, containerName string) ([]*model.NodeObject, error)
+	FilterNodeListByField(ctx context.Context, nodeList []*model.NodeObject, field *v1alpha1.FieldSelector) ([]*model.NodeObject, error)
+	GetNodeZoneMap(ctx context.Context) (map[string]string, error)
 
 	GetDeploymentListByLabel(ctx context.Context, namespace string, label map[string]string) ([]*model.DeploymentObject, error)
 	GetDeploymentListByName(ctx context.Context, namespace string, name []string) ([]*model.DeploymentObject, error)
+
+	GetStatefulSetPodListByLabel(ctx context.Context, namespace string, label map[string]string, containerName string) ([]*model.PodObject, error)
+	GetStatefulSetPodListByName(ctx context.Context, namespace string, name []string, containerName string) ([]*model.PodObject, error)
+	GetDaemonSetPodListByLabel(ctx context.Context, namespace string, label map[string]string, containerName string) ([]*model.PodObject, error)
+	GetDaemonSetPodListByName(ctx context.Context, namespace string, name []string, containerName string) ([]*model.PodObject, error)
+	GetJobPodListByLabel(ctx context.Context, namespace string, label map[string]string, containerName string) ([]*model.PodObject, error)
+	GetJobPodListByName(ctx context.Context, namespace string, name []string, containerName string) ([]*model.PodObject, error)
+	GetCronJobPodListByLabel(ctx context.Context, namespace string, label map[string]string, containerName string) ([]*model.PodObject, error)
+	GetCronJobPodListByName(ctx context.Context, namespace string, name []string, containerName string) ([]*model.PodObject, error)
+	GetServicePodListByLabel(ctx context.Context, namespace string, label map[string]string, containerName string) ([]*model.PodObject, error)
+	GetServicePodListByName(ctx context.Context, namespace string, name []string, containerName string) ([]*model.PodObject, error)
 }
 
 type Analyzer struct {
 	ApiServer client.Client
 }
 
+// getClient returns the client of the cluster which the experiment is run in, the experiments are always listed
+// in the cluster of the operator
+func (a *Analyzer) getClient(ctx context.Context) client.Client {
+	if c := multicluster.FromContext(ctx); c != nil {
+		return c.Client
+	}
+
+	return a.ApiServer
+}
+
+// GetExperiment the error is returned as it is, so a missing experiment can be checked by errors.IsNotFound
+func (a *Analyzer) GetExperiment(ctx context.Context, namespace, name string) (*v1alpha1.Experiment, error) {
+	exp := &v1alpha1.Experiment{}
+	if err := a.ApiServer.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, exp); err != nil {
+		return nil, err
+	}
+
+	return exp, nil
+}
+
 func (a *Analyzer) GetExperimentListByPhase(ctx context.Context, phase string) (*v1alpha1.ExperimentList, error) {
 	opts := []client.ListOption{
 		//client.MatchingFields{
@@ -83,30 +130,120 @@ func (a *Analyzer) GetExperimentListByPhase(ctx context.Context, phase string) (
 	return expList, nil
 }
 
+func (a *Analyzer) GetExperimentListByMutex(ctx context.Context, mutex string) (*v1alpha1.ExperimentList, error) {
+	expList := &v1alpha1.ExperimentList{}
+	if err := a.ApiServer.List(ctx, expList, client.MatchingFields{MutexKey: mutex}); err != nil {
+		return nil, fmt.Errorf("list experiment info by mutex error: %s", err.Error())
+	}
+
+	return expList, nil
+}
+
+// GetSelectorNamespaces returns the namespaces a selector unit is resolved in, "namespace" takes precedence over "namespaceSelector"
+func GetSelectorNamespaces(ctx context.Context, selectorUnit v1alpha1.SelectorUnit) ([]string, error) {
+	if selectorUnit.Namespace != "" {
+		return []string{selectorUnit.Namespace}, nil
+	}
+
+	if len(selectorUnit.NamespaceSelector) == 0 {
+		return nil, fmt.Errorf("namespace and namespaceSelector are both empty")
+	}
+
+	return GetAnalyzer().GetNamespaceListByLabel(ctx, selectorUnit.NamespaceSelector)
+}
+
+// ExcludePods drops the pods matching "excludeLabel" or "excludeNames" of the selector unit
+func ExcludePods(selectorUnit v1alpha1.SelectorUnit, podList []*model.PodObject) []*model.PodObject {
+	if len(selectorUnit.ExcludeLabel) == 0 && len(selectorUnit.ExcludeNames) == 0 {
+		return podList
+	}
+
+	var result []*model.PodObject
+	for _, unitPod := range podList {
+		if !isExcluded(selectorUnit, unitPod.PodName, unitPod.Labels) {
+			result = append(result, unitPod)
+		}
+	}
+
+	return result
+}
+
+// ExcludeNodes drops the nodes matching "excludeLabel" or "excludeNames" of the selector unit
+func ExcludeNodes(selectorUnit v1alpha1.SelectorUnit, nodeList []*model.NodeObject) []*model.NodeObject {
+	if len(selectorUnit.ExcludeLabel) == 0 && len(selectorUnit.ExcludeNames) == 0 {
+		return nodeList
+	}
+
+	var result []*model.NodeObject
+	for _, unitNode := range nodeList {
+		if !isExcluded(selectorUnit, unitNode.NodeName, unitNode.Labels) {
+			result = append(result, unitNode)
+		}
+	}
+
+	return result
+}
+
+func isExcluded(selectorUnit v1alpha1.SelectorUnit, name string, labels map[string]string) bool {
+	for _, unitName := range selectorUnit.ExcludeNames {
+		if unitName == name {
+			return true
+		}
+	}
+
+	if len(selectorUnit.ExcludeLabel) == 0 {
+		return false
+	}
+
+	for k, v := range selectorUnit.ExcludeLabel {
+		if labels[k] != v {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (a *Analyzer) GetNamespaceListByLabel(ctx context.Context, label map[string]string) ([]string, error) {
+	nsList := &corev1.NamespaceList{}
+	if err := a.getClient(ctx).List(ctx, nsList, client.MatchingLabels(label)); err != nil {
+		return nil, fmt.Errorf("list namespace by label error: %s", err.Error())
+	}
+
+	var result = make([]string, len(nsList.Items))
+	for i, unitNs := range nsList.Items {
+		result[i] = unitNs.Name
+	}
+
+	return result, nil
+}
+
 func (a *Analyzer) GetPodListByLabelInNode(ctx context.Context, namespace string, label map[string]string, nodeIP string) ([]*model.PodObject, error) {
 	opts := []client.ListOption{
 		client.InNamespace(namespace),
 		client.MatchingLabels(label),
-		client.MatchingFields{
+	}
+
+	// the field index of host ip is only in the cache of the operator, the pods of other clusters are filtered here
+	isRemote := multicluster.FromContext(ctx) != nil
+	if !isRemote {
+		opts = append(opts, client.MatchingFields{
 			HostIPKey: nodeIP,
-		},
+		})
 	}
 
 	podList := &corev1.PodList{}
-	if err := a.ApiServer.List(ctx, podList, opts...); err != nil {
+	if err := a.getClient(ctx).List(ctx, podList, opts...); err != nil {
 		return nil, fmt.Errorf("list pod in node[%s] error: %s", nodeIP, err.Error())
 	}
 
-	var result = make([]*model.PodObject, len(podList.Items))
-	for i, unitPod := range podList.Items {
-		result[i] = &model.PodObject{
-			PodName:   unitPod.Name,
-			PodUID:    string(unitPod.UID),
-			PodIP:     unitPod.Status.PodIP,
-			Namespace: unitPod.Namespace,
-			NodeName:  unitPod.Spec.NodeName,
-			NodeIP:    unitPod.Status.HostIP,
+	var result = make([]*model.PodObject, 0, len(podList.Items))
+	for _, unitPod := range podList.Items {
+		if isRemote && unitPod.Status.HostIP != nodeIP {
+			continue
 		}
+
+		result = append(result, newPodObject(&unitPod))
 	}
 
 	return result, nil
@@ -119,20 +256,13 @@ func (a *Analyzer) GetPodListByLabel(ctx context.Context, namespace string, labe
 	}
 
 	podList := &corev1.PodList{}
-	if err := a.ApiServer.List(ctx, podList, opts...); err != nil {
+	if err := a.getClient(ctx).List(ctx, podList, opts...); err != nil {
 		return nil, fmt.Errorf("list pod info by label error: %s", err.Error())
 	}
 
 	var result []*model.PodObject
 	for _, unitPod := range podList.Items {
-		podInfo := &model.PodObject{
-			PodName:   unitPod.Name,
-			PodUID:    string(unitPod.UID),
-			PodIP:     unitPod.Status.PodIP,
-			Namespace: unitPod.Namespace,
-			NodeName:  unitPod.Spec.NodeName,
-			NodeIP:    unitPod.Status.HostIP,
-		}
+		podInfo := newPodObject(&unitPod)
 
 		if containerName != "" {
 			var err error
@@ -140,6 +270,7 @@ func (a *Analyzer) GetPodListByLabel(ctx context.Context, namespace string, labe
 			if err != nil {
 				return nil, fmt.Errorf("get target container[%s] in pod[%s] error: %s", containerName, unitPod.Name, err.Error())
 			}
+			podInfo.ContainerImage = GetContainerImage(podInfo.ContainerName, unitPod.Status.ContainerStatuses)
 		}
 
 		result = append(result, podInfo)
@@ -154,7 +285,7 @@ func (a *Analyzer) GetPodListByPodName(ctx context.Context, namespace string, po
 	}
 
 	podList := &corev1.PodList{}
-	if err := a.ApiServer.List(ctx, podList, opts...); err != nil {
+	if err := a.getClient(ctx).List(ctx, podList, opts...); err != nil {
 		return nil, fmt.Errorf("list pod info error: %s", err.Error())
 	}
 
@@ -169,14 +300,7 @@ func (a *Analyzer) GetPodListByPodName(ctx context.Context, namespace string, po
 			continue
 		}
 
-		podInfo := &model.PodObject{
-			PodName:   unitPod.Name,
-			PodUID:    string(unitPod.UID),
-			PodIP:     unitPod.Status.PodIP,
-			Namespace: unitPod.Namespace,
-			NodeName:  unitPod.Spec.NodeName,
-			NodeIP:    unitPod.Status.HostIP,
-		}
+		podInfo := newPodObject(&unitPod)
 
 		if containerName != "" {
 			var err error
@@ -184,6 +308,7 @@ func (a *Analyzer) GetPodListByPodName(ctx context.Context, namespace string, po
 			if err != nil {
 				return nil, fmt.Errorf("get target container[%s] in pod[%s] error: %s", containerName, unitPod.Name, err.Error())
 			}
+			podInfo.ContainerImage = GetContainerImage(podInfo.ContainerName, unitPod.Status.ContainerStatuses)
 		}
 
 		result = append(result, podInfo)
@@ -192,6 +317,198 @@ func (a *Analyzer) GetPodListByPodName(ctx context.Context, namespace string, po
 	return result, nil
 }
 
+// GetPodListByOwner walks the controller ownerReferences of each pod, through its ReplicaSet or Job, to find the owner.
+// Unlike labels, the owner chain does not change when a rollout creates a new ReplicaSet
+func (a *Analyzer) GetPodListByOwner(ctx context.Context, namespace string, owner *v1alpha1.OwnerSelector, containerName string) ([]*model.PodObject, error) {
+	podList := &corev1.PodList{}
+	if err := a.getClient(ctx).List(ctx, podList, client.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("list pod info error: %s", err.Error())
+	}
+
+	// the owner of the intermediate ReplicaSet or Job, key is "kind/name"
+	ownerCache := make(map[string]*metav1.OwnerReference)
+	var result []*model.PodObject
+	for _, unitPod := range podList.Items {
+		isOwned, err := a.isOwnedBy(ctx, namespace, metav1.GetControllerOf(&unitPod), owner, ownerCache)
+		if err != nil {
+			return nil, fmt.Errorf("get owner of pod[%s] error: %s", unitPod.Name, err.Error())
+		}
+
+		if !isOwned {
+			continue
+		}
+
+		podInfo := newPodObject(&unitPod)
+
+		if containerName != "" {
+			podInfo.ContainerRuntime, podInfo.ContainerID, podInfo.ContainerName, err = GetTargetContainer(containerName, unitPod.Status.ContainerStatuses)
+			if err != nil {
+				return nil, fmt.Errorf("get target container[%s] in pod[%s] error: %s", containerName, unitPod.Name, err.Error())
+			}
+			podInfo.ContainerImage = GetContainerImage(podInfo.ContainerName, unitPod.Status.ContainerStatuses)
+		}
+
+		result = append(result, podInfo)
+	}
+
+	return result, nil
+}
+
+func (a *Analyzer) isOwnedBy(ctx context.Context, namespace string, ref *metav1.OwnerReference, owner *v1alpha1.OwnerSelector, ownerCache map[string]*metav1.OwnerReference) (bool, error) {
+	// a pod is at most two levels below its top owner: Deployment -> ReplicaSet -> Pod, CronJob -> Job -> Pod
+	for level := 0; level < 2 && ref != nil; level++ {
+		if isOwnerRef(ref, owner) {
+			return true, nil
+		}
+
+		if (ref.Kind != "ReplicaSet" && ref.Kind != "Job") || refGroup(ref) != ownerKindGroups[ref.Kind] {
+			return false, nil
+		}
+
+		cacheKey := fmt.Sprintf("%s/%s", ref.Kind, ref.Name)
+		parent, ok := ownerCache[cacheKey]
+		if !ok {
+			var obj client.Object = &appsv1.ReplicaSet{}
+			if ref.Kind == "Job" {
+				obj = &batchv1.Job{}
+			}
+
+			if err := a.getClient(ctx).Get(ctx, client.ObjectKey{Namespace: namespace, Name: ref.Name}, obj); err != nil {
+				if !errors.IsNotFound(err) {
+					return false, fmt.Errorf("get %s error: %s", cacheKey, err.Error())
+				}
+			} else {
+				parent = metav1.GetControllerOf(obj)
+			}
+
+			ownerCache[cacheKey] = parent
+		}
+
+		ref = parent
+	}
+
+	return ref != nil && isOwnerRef(ref, owner), nil
+}
+
+// ownerKindGroups is the API group of each kind supported by the owner selector, a CRD of the same kind in another group is not matched
+var ownerKindGroups = map[string]string{
+	"ReplicaSet":  appsv1.GroupName,
+	"Deployment":  appsv1.GroupName,
+	"StatefulSet": appsv1.GroupName,
+	"DaemonSet":   appsv1.GroupName,
+	"Job":         batchv1.GroupName,
+	"CronJob":     batchv1.GroupName,
+}
+
+func isOwnerRef(ref *metav1.OwnerReference, owner *v1alpha1.OwnerSelector) bool {
+	group, ok := ownerKindGroups[ref.Kind]
+	return ok && refGroup(ref) == group && ref.Kind == owner.Kind && ref.Name == owner.Name
+}
+
+func refGroup(ref *metav1.OwnerReference) string {
+	return schema.FromAPIVersionAndKind(ref.APIVersion, ref.Kind).Group
+}
+
+// FilterPodListByField keeps the pods meeting all the conditions of the field selector, a pod gone since it was listed is dropped
+func (a *Analyzer) FilterPodListByField(ctx context.Context, podList []*model.PodObject, field *v1alpha1.FieldSelector) ([]*model.PodObject, error) {
+	if field == nil || len(podList) == 0 {
+		return podList, nil
+	}
+
+	var nodeZone map[string]string
+	if len(field.Zone) != 0 {
+		var err error
+		if nodeZone, err = a.GetNodeZoneMap(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	var result []*model.PodObject
+	for _, unitPod := range podList {
+		if len(field.Phase) != 0 && !contains(field.Phase, unitPod.Phase) {
+			continue
+		}
+
+		if len(field.QOSClass) != 0 && !contains(field.QOSClass, unitPod.QOSClass) {
+			continue
+		}
+
+		if (field.MinRestartCount != nil && unitPod.RestartCount < *field.MinRestartCount) || (field.MaxRestartCount != nil && unitPod.RestartCount > *field.MaxRestartCount) {
+			continue
+		}
+
+		if len(field.Zone) != 0 && !contains(field.Zone, nodeZone[unitPod.NodeName]) {
+			continue
+		}
+
+		result = append(result, unitPod)
+	}
+
+	return result, nil
+}
+
+// newPodObject keeps the status of the pod used by the field selector, so that the pods are not listed again to filter
+func newPodObject(pod *corev1.Pod) *model.PodObject {
+	var restartCount int32
+	for _, unitStatus := range pod.Status.ContainerStatuses {
+		restartCount += unitStatus.RestartCount
+	}
+
+	return &model.PodObject{
+		PodName:      pod.Name,
+		PodUID:       string(pod.UID),
+		PodIP:        pod.Status.PodIP,
+		Namespace:    pod.Namespace,
+		NodeName:     pod.Spec.NodeName,
+		NodeIP:       pod.Status.HostIP,
+		Labels:       pod.Labels,
+		Phase:        string(pod.Status.Phase),
+		QOSClass:     string(pod.Status.QOSClass),
+		RestartCount: restartCount,
+	}
+}
+
+// FilterNodeListByField keeps the nodes in the zones of the field selector, the other conditions only apply to pods
+func (a *Analyzer) FilterNodeListByField(ctx context.Context, nodeList []*model.NodeObject, field *v1alpha1.FieldSelector) ([]*model.NodeObject, error) {
+	if field == nil || len(field.Zone) == 0 {
+		return nodeList, nil
+	}
+
+	var result []*model.NodeObject
+	for _, unitNode := range nodeList {
+		if contains(field.Zone, unitNode.Labels[corev1.LabelTopologyZone]) {
+			result = append(result, unitNode)
+		}
+	}
+
+	return result, nil
+}
+
+// GetNodeZoneMap returns the zone of each node, key is the node name
+func (a *Analyzer) GetNodeZoneMap(ctx context.Context) (map[string]string, error) {
+	nodeList := &corev1.NodeList{}
+	if err := a.getClient(ctx).List(ctx, nodeList); err != nil {
+		return nil, fmt.Errorf("list node error: %s", err.Error())
+	}
+
+	nodeZone := make(map[string]string, len(nodeList.Items))
+	for _, unitNode := range nodeList.Items {
+		nodeZone[unitNode.Name] = unitNode.Labels[corev1.LabelTopologyZone]
+	}
+
+	return nodeZone, nil
+}
+
+func contains(list []string, value string) bool {
+	for _, unit := range list {
+		if unit == value {
+			return true
+		}
+	}
+
+	return false
+}
+
 func GetTargetContainer(containerName string, status []corev1.ContainerStatus) (r, id, name string, err error) {
 	if len(status) == 0 {
 		err = fmt.Errorf("no container in pod")
@@ -226,6 +543,17 @@ func GetTargetContainer(containerName string, status []corev1.ContainerStatus) (
 	return
 }
 
+// GetContainerImage returns the image of the container in the pod, empty if the container is not found
+func GetContainerImage(containerName string, status []corev1.ContainerStatus) string {
+	for _, unitC := range status {
+		if unitC.Name == containerName {
+			return unitC.Image
+		}
+	}
+
+	return ""
+}
+
 // GetNodeListByLabel return all node when label is empty map or nil
 func (a *Analyzer) GetNodeListByLabel(ctx context.Context, label map[string]string, containerName string) ([]*model.NodeObject, error) {
 	opts := []client.ListOption{
@@ -233,7 +561,7 @@ func (a *Analyzer) GetNodeListByLabel(ctx context.Context, label map[string]stri
 	}
 
 	nodeList := &corev1.NodeList{}
-	if err := a.ApiServer.List(ctx, nodeList, opts...); err != nil {
+	if err := a.getClient(ctx).List(ctx, nodeList, opts...); err != nil {
 		return nil, fmt.Errorf("list node error: %s", err.Error())
 	}
 
@@ -241,6 +569,7 @@ func (a *Analyzer) GetNodeListByLabel(ctx context.Context, label map[string]stri
 	for i, unitNode := range nodeList.Items {
 		result[i] = &model.NodeObject{
 			NodeName: unitNode.Name,
+			Labels:   unitNode.Labels,
 		}
 
 		for _, unitAddress := range unitNode.Status.Addresses {
@@ -267,7 +596,7 @@ func (a *Analyzer) GetNodeListByLabel(ctx context.Context, label map[string]stri
 func (a *Analyzer) GetNodeListByNodeName(ctx context.Context, nodeName []string, containerName string) ([]*model.NodeObject, error) {
 	nodeList := &corev1.NodeList{}
 
-	if err := a.ApiServer.List(ctx, nodeList, []client.ListOption{}...); err != nil {
+	if err := a.getClient(ctx).List(ctx, nodeList, []client.ListOption{}...); err != nil {
 		return nil, fmt.Errorf("list node error: %s", err.Error())
 	}
 
@@ -284,6 +613,7 @@ func (a *Analyzer) GetNodeListByNodeName(ctx context.Context, nodeName []string,
 
 		tmpNode := &model.NodeObject{
 			NodeName: unitNode.Name,
+			Labels:   unitNode.Labels,
 		}
 
 		for _, unitAddress := range unitNode.Status.Addresses {
@@ -313,7 +643,7 @@ func (a *Analyzer) GetNodeListByNodeName(ctx context.Context, nodeName []string,
 func (a *Analyzer) GetNodeListByNodeIP(ctx context.Context, nodeIP []string, containerName string) ([]*model.NodeObject, error) {
 	nodeList := &corev1.NodeList{}
 
-	if err := a.ApiServer.List(ctx, nodeList, []client.ListOption{}...); err != nil {
+	if err := a.getClient(ctx).List(ctx, nodeList, []client.ListOption{}...); err != nil {
 		return nil, fmt.Errorf("list node error: %s", err.Error())
 	}
 
@@ -338,6 +668,7 @@ func (a *Analyzer) GetNodeListByNodeIP(ctx context.Context, nodeIP []string, con
 
 		tmpNode := &model.NodeObject{
 			NodeName:       unitNode.Name,
+			Labels:         unitNode.Labels,
 			NodeInternalIP: unitIP,
 			HostName:       unitHostName,
 		}
@@ -384,21 +715,15 @@ func (a *Analyzer) GetNodeListByNodeIP(ctx context.Context, nodeIP []string, con
 func (a *Analyzer) GetPod(ctx context.Context, ns, podName, containerName string) (*model.PodObject, error) {
 	pod := &corev1.Pod{}
 
-	if err := a.ApiServer.Get(ctx, client.ObjectKey{
+	if err := a.getClient(ctx).Get(ctx, client.ObjectKey{
 		Namespace: ns,
 		Name:      podName,
 	}, pod); err != nil {
 		return nil, fmt.Errorf("get pod error: %s", err.Error())
 	}
 
-	podInfo := &model.PodObject{
-		Namespace: pod.Namespace,
-		PodName:   pod.Name,
-		PodUID:    string(pod.UID),
-		PodIP:     pod.Status.PodIP,
-		NodeName:  pod.Spec.NodeName,
-		NodeIP:    pod.Status.HostIP,
-	}
+	podInfo := newPodObject(pod)
+	podInfo.Annotations = pod.Annotations
 
 	if containerName != "" {
 		var err error
@@ -406,6 +731,7 @@ func (a *Analyzer) GetPod(ctx context.Context, ns, podName, containerName string
 		if err != nil {
 			return nil, fmt.Errorf("get target container[%s] in pod[%s] error: %s", containerName, pod.Name, err.Error())
 		}
+		podInfo.ContainerImage = GetContainerImage(podInfo.ContainerName, pod.Status.ContainerStatuses)
 	}
 
 	return podInfo, nil
@@ -418,7 +744,7 @@ func (a *Analyzer) GetDeploymentListByLabel(ctx context.Context, namespace strin
 	}
 
 	deployList := &appsv1.DeploymentList{}
-	if err := a.ApiServer.List(ctx, deployList, opts...); err != nil {
+	if err := a.getClient(ctx).List(ctx, deployList, opts...); err != nil {
 		return nil, fmt.Errorf("list deployment info error: %s", err.Error())
 	}
 
@@ -439,7 +765,7 @@ func (a *Analyzer) GetDeploymentListByName(ctx context.Context, namespace string
 	}
 
 	deployList := &appsv1.DeploymentList{}
-	if err := a.ApiServer.List(ctx, deployList, opts...); err != nil {
+	if err := a.getClient(ctx).List(ctx, deployList, opts...); err != nil {
 		return nil, fmt.Errorf("list deployment info error: %s", err.Error())
 	}
 
@@ -462,3 +788,280 @@ func (a *Analyzer) GetDeploymentListByName(ctx context.Context, namespace string
 
 	return result, nil
 }
+
+func (a *Analyzer) GetStatefulSetPodListByLabel(ctx context.Context, namespace string, label map[string]string, containerName string) ([]*model.PodObject, error) {
+	stsList := &appsv1.StatefulSetList{}
+	if err := a.getClient(ctx).List(ctx, stsList, client.InNamespace(namespace), client.MatchingLabels(label)); err != nil {
+		return nil, fmt.Errorf("list statefulset info error: %s", err.Error())
+	}
+
+	var controllers []workloadController
+	for _, unitSts := range stsList.Items {
+		controllers = append(controllers, workloadController{name: unitSts.Name, uid: unitSts.UID, selector: unitSts.Spec.Selector})
+	}
+
+	return a.getWorkloadPodList(ctx, namespace, controllers, containerName, false)
+}
+
+func (a *Analyzer) GetStatefulSetPodListByName(ctx context.Context, namespace string, name []string, containerName string) ([]*model.PodObject, error) {
+	stsList := &appsv1.StatefulSetList{}
+	if err := a.getClient(ctx).List(ctx, stsList, client.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("list statefulset info error: %s", err.Error())
+	}
+
+	stsNameMap := make(map[string]bool)
+	for _, unitName := range name {
+		stsNameMap[unitName] = true
+	}
+
+	var controllers []workloadController
+	for _, unitSts := range stsList.Items {
+		if !stsNameMap[unitSts.Name] {
+			continue
+		}
+
+		controllers = append(controllers, workloadController{name: unitSts.Name, uid: unitSts.UID, selector: unitSts.Spec.Selector})
+	}
+
+	return a.getWorkloadPodList(ctx, namespace, controllers, containerName, false)
+}
+
+func (a *Analyzer) GetDaemonSetPodListByLabel(ctx context.Context, namespace string, label map[string]string, containerName string) ([]*model.PodObject, error) {
+	dsList := &appsv1.DaemonSetList{}
+	if err := a.getClient(ctx).List(ctx, dsList, client.InNamespace(namespace), client.MatchingLabels(label)); err != nil {
+		return nil, fmt.Errorf("list daemonset info error: %s", err.Error())
+	}
+
+	var controllers []workloadController
+	for _, unitDs := range dsList.Items {
+		controllers = append(controllers, workloadController{name: unitDs.Name, uid: unitDs.UID, selector: unitDs.Spec.Selector})
+	}
+
+	return a.getWorkloadPodList(ctx, namespace, controllers, containerName, false)
+}
+
+func (a *Analyzer) GetDaemonSetPodListByName(ctx context.Context, namespace string, name []string, containerName string) ([]*model.PodObject, error) {
+	dsList := &appsv1.DaemonSetList{}
+	if err := a.getClient(ctx).List(ctx, dsList, client.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("list daemonset info error: %s", err.Error())
+	}
+
+	dsNameMap := make(map[string]bool)
+	for _, unitName := range name {
+		dsNameMap[unitName] = true
+	}
+
+	var controllers []workloadController
+	for _, unitDs := range dsList.Items {
+		if !dsNameMap[unitDs.Name] {
+			continue
+		}
+
+		controllers = append(controllers, workloadController{name: unitDs.Name, uid: unitDs.UID, selector: unitDs.Spec.Selector})
+	}
+
+	return a.getWorkloadPodList(ctx, namespace, controllers, containerName, false)
+}
+
+func (a *Analyzer) GetJobPodListByLabel(ctx context.Context, namespace string, label map[string]string, containerName string) ([]*model.PodObject, error) {
+	jobList := &batchv1.JobList{}
+	if err := a.getClient(ctx).List(ctx, jobList, client.InNamespace(namespace), client.MatchingLabels(label)); err != nil {
+		return nil, fmt.Errorf("list job info error: %s", err.Error())
+	}
+
+	var controllers []workloadController
+	for _, unitJob := range jobList.Items {
+		controllers = append(controllers, workloadController{name: unitJob.Name, uid: unitJob.UID, selector: unitJob.Spec.Selector})
+	}
+
+	return a.getWorkloadPodList(ctx, namespace, controllers, containerName, true)
+}
+
+func (a *Analyzer) GetJobPodListByName(ctx context.Context, namespace string, name []string, containerName string) ([]*model.PodObject, error) {
+	jobList := &batchv1.JobList{}
+	if err := a.getClient(ctx).List(ctx, jobList, client.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("list job info error: %s", err.Error())
+	}
+
+	jobNameMap := make(map[string]bool)
+	for _, unitName := range name {
+		jobNameMap[unitName] = true
+	}
+
+	var controllers []workloadController
+	for _, unitJob := range jobList.Items {
+		if !jobNameMap[unitJob.Name] {
+			continue
+		}
+
+		controllers = append(controllers, workloadController{name: unitJob.Name, uid: unitJob.UID, selector: unitJob.Spec.Selector})
+	}
+
+	return a.getWorkloadPodList(ctx, namespace, controllers, containerName, true)
+}
+
+func (a *Analyzer) GetCronJobPodListByLabel(ctx context.Context, namespace string, label map[string]string, containerName string) ([]*model.PodObject, error) {
+	cronJobList := &batchv1.CronJobList{}
+	if err := a.getClient(ctx).List(ctx, cronJobList, client.InNamespace(namespace), client.MatchingLabels(label)); err != nil {
+		return nil, fmt.Errorf("list cronjob info error: %s", err.Error())
+	}
+
+	cronJobUIDMap := make(map[types.UID]bool)
+	for _, unitCronJob := range cronJobList.Items {
+		cronJobUIDMap[unitCronJob.UID] = true
+	}
+
+	return a.getCronJobPodList(ctx, namespace, cronJobUIDMap, containerName)
+}
+
+func (a *Analyzer) GetCronJobPodListByName(ctx context.Context, namespace string, name []string, containerName string) ([]*model.PodObject, error) {
+	cronJobList := &batchv1.CronJobList{}
+	if err := a.getClient(ctx).List(ctx, cronJobList, client.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("list cronjob info error: %s", err.Error())
+	}
+
+	cronJobNameMap := make(map[string]bool)
+	for _, unitName := range name {
+		cronJobNameMap[unitName] = true
+	}
+
+	cronJobUIDMap := make(map[types.UID]bool)
+	for _, unitCronJob := range cronJobList.Items {
+		if cronJobNameMap[unitCronJob.Name] {
+			cronJobUIDMap[unitCronJob.UID] = true
+		}
+	}
+
+	return a.getCronJobPodList(ctx, namespace, cronJobUIDMap, containerName)
+}
+
+// getCronJobPodList resolves the running pods of the jobs currently spawned by the cronjobs
+func (a *Analyzer) getCronJobPodList(ctx context.Context, namespace string, cronJobUIDMap map[types.UID]bool, containerName string) ([]*model.PodObject, error) {
+	if len(cronJobUIDMap) == 0 {
+		return nil, nil
+	}
+
+	jobList := &batchv1.JobList{}
+	if err := a.getClient(ctx).List(ctx, jobList, client.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("list job info error: %s", err.Error())
+	}
+
+	var controllers []workloadController
+	for _, unitJob := range jobList.Items {
+		owner := metav1.GetControllerOf(&unitJob)
+		if owner == nil || !cronJobUIDMap[owner.UID] {
+			continue
+		}
+
+		controllers = append(controllers, workloadController{name: unitJob.Name, uid: unitJob.UID, selector: unitJob.Spec.Selector})
+	}
+
+	return a.getWorkloadPodList(ctx, namespace, controllers, containerName, true)
+}
+
+func (a *Analyzer) GetServicePodListByLabel(ctx context.Context, namespace string, label map[string]string, containerName string) ([]*model.PodObject, error) {
+	svcList := &corev1.ServiceList{}
+	if err := a.getClient(ctx).List(ctx, svcList, client.InNamespace(namespace), client.MatchingLabels(label)); err != nil {
+		return nil, fmt.Errorf("list service info error: %s", err.Error())
+	}
+
+	var services []string
+	for _, unitSvc := range svcList.Items {
+		services = append(services, unitSvc.Name)
+	}
+
+	return a.getServicePodList(ctx, namespace, services, containerName)
+}
+
+func (a *Analyzer) GetServicePodListByName(ctx context.Context, namespace string, name []string, containerName string) ([]*model.PodObject, error) {
+	return a.getServicePodList(ctx, namespace, name, containerName)
+}
+
+// getServicePodList returns the pods of the ready endpoints in the endpoint slices of the services,
+// the endpoints not backed by a pod are skipped
+func (a *Analyzer) getServicePodList(ctx context.Context, namespace string, services []string, containerName string) ([]*model.PodObject, error) {
+	var (
+		podNames []string
+		isExist  = make(map[string]bool)
+	)
+
+	for _, unitSvc := range services {
+		sliceList := &discoveryv1.EndpointSliceList{}
+		if err := a.getClient(ctx).List(ctx, sliceList, client.InNamespace(namespace), client.MatchingLabels{discoveryv1.LabelServiceName: unitSvc}); err != nil {
+			return nil, fmt.Errorf("list endpoint slice of service[%s] error: %s", unitSvc, err.Error())
+		}
+
+		for _, unitSlice := range sliceList.Items {
+			for _, unitEndpoint := range unitSlice.Endpoints {
+				if unitEndpoint.TargetRef == nil || unitEndpoint.TargetRef.Kind != "Pod" {
+					continue
+				}
+
+				// nil means ready
+				if unitEndpoint.Conditions.Ready != nil && !*unitEndpoint.Conditions.Ready {
+					continue
+				}
+
+				if !isExist[unitEndpoint.TargetRef.Name] {
+					isExist[unitEndpoint.TargetRef.Name] = true
+					podNames = append(podNames, unitEndpoint.TargetRef.Name)
+				}
+			}
+		}
+	}
+
+	if len(podNames) == 0 {
+		return nil, nil
+	}
+
+	return a.GetPodListByPodName(ctx, namespace, podNames, containerName)
+}
+
+type workloadController struct {
+	name     string
+	uid      types.UID
+	selector *metav1.LabelSelector
+}
+
+// getWorkloadPodList lists the pods matching each controller's selector and keeps the ones it really owns,
+// so pods of another controller that happen to share the labels are not selected.
+// onlyRunning skips the pods not in phase running, e.g. the finished pods of a job
+func (a *Analyzer) getWorkloadPodList(ctx context.Context, namespace string, controllers []workloadController, containerName string, onlyRunning bool) ([]*model.PodObject, error) {
+	var result []*model.PodObject
+	for _, unitController := range controllers {
+		labelSelector, err := metav1.LabelSelectorAsSelector(unitController.selector)
+		if err != nil {
+			return nil, fmt.Errorf("convert selector of %s error: %s", unitController.name, err.Error())
+		}
+
+		podList := &corev1.PodList{}
+		if err := a.getClient(ctx).List(ctx, podList, client.InNamespace(namespace), client.MatchingLabelsSelector{Selector: labelSelector}); err != nil {
+			return nil, fmt.Errorf("list pod of %s error: %s", unitController.name, err.Error())
+		}
+
+		for _, unitPod := range podList.Items {
+			owner := metav1.GetControllerOf(&unitPod)
+			if owner == nil || owner.UID != unitController.uid {
+				continue
+			}
+
+			if onlyRunning && unitPod.Status.Phase != corev1.PodRunning {
+				continue
+			}
+
+			podInfo := newPodObject(&unitPod)
+
+			if containerName != "" {
+				podInfo.ContainerRuntime, podInfo.ContainerID, podInfo.ContainerName, err = GetTargetContainer(containerName, unitPod.Status.ContainerStatuses)
+				if err != nil {
+					return nil, fmt.Errorf("get target container[%s] in pod[%s] error: %s", containerName, unitPod.Name, err.Error())
+				}
+				podInfo.ContainerImage = GetContainerImage(podInfo.ContainerName, unitPod.Status.ContainerStatuses)
+			}
+
+			result = append(result, podInfo)
+		}
+	}
+
+	return result, nil
+}
@@ -17,8 +17,21 @@
 package selector
 
 import (
+	"context"
 	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/model"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/multicluster"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"reflect"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"testing"
 )
 
@@ -114,3 +127,380 @@ func TestGetTargetContainer(t *testing.T) {
 		})
 	}
 }
+
+func TestGetContainerImage(t *testing.T) {
+	testStatus := []corev1.ContainerStatus{
+		{Name: "chaosmeta", Image: "chaosmeta:v0.5.0"},
+		{Name: "nginx", Image: "nginx:1.25"},
+	}
+
+	if got := GetContainerImage("nginx", testStatus); got != "nginx:1.25" {
+		t.Errorf("GetContainerImage() got = %v, want %v", got, "nginx:1.25")
+	}
+	if got := GetContainerImage("centos", testStatus); got != "" {
+		t.Errorf("GetContainerImage() got = %v, want empty", got)
+	}
+}
+
+func TestAnalyzer_GetStatefulSetPodListByName(t *testing.T) {
+	var (
+		isController = true
+		sts          = &appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "mysql", Namespace: "ns", UID: "sts-uid"},
+			Spec: appsv1.StatefulSetSpec{
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "mysql"}},
+			},
+		}
+		newPod = func(name string, ownerUID types.UID) *corev1.Pod {
+			return &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: "ns",
+					Labels:    map[string]string{"app": "mysql"},
+					OwnerReferences: []metav1.OwnerReference{
+						{Kind: "StatefulSet", Name: "mysql", UID: ownerUID, Controller: &isController},
+					},
+				},
+				Status: corev1.PodStatus{
+					ContainerStatuses: []corev1.ContainerStatus{{Name: "mysql", ContainerID: "docker://33124124"}},
+				},
+			}
+		}
+	)
+
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	analyzer := &Analyzer{
+		ApiServer: fake.NewClientBuilder().WithScheme(scheme).WithObjects(sts, newPod("mysql-0", "sts-uid"), newPod("other-0", "other-uid")).Build(),
+	}
+
+	podList, err := analyzer.GetStatefulSetPodListByName(context.Background(), "ns", []string{"mysql"}, v1alpha1.FirstContainer)
+	if err != nil {
+		t.Fatalf("GetStatefulSetPodListByName() error = %v", err)
+	}
+	if len(podList) != 1 || podList[0].PodName != "mysql-0" || podList[0].ContainerID != "33124124" {
+		t.Errorf("GetStatefulSetPodListByName() got = %v, want only pod mysql-0", podList)
+	}
+}
+
+func TestAnalyzer_GetCronJobPodListByName(t *testing.T) {
+	var (
+		isController = true
+		cronJob      = &batchv1.CronJob{
+			ObjectMeta: metav1.ObjectMeta{Name: "report", Namespace: "ns", UID: "cronjob-uid"},
+		}
+		job = &batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            "report-1",
+				Namespace:       "ns",
+				UID:             "job-uid",
+				OwnerReferences: []metav1.OwnerReference{{Kind: "CronJob", Name: "report", UID: "cronjob-uid", Controller: &isController}},
+			},
+			Spec: batchv1.JobSpec{
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"controller-uid": "job-uid"}},
+			},
+		}
+		newPod = func(name string, phase corev1.PodPhase) *corev1.Pod {
+			return &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:            name,
+					Namespace:       "ns",
+					Labels:          map[string]string{"controller-uid": "job-uid"},
+					OwnerReferences: []metav1.OwnerReference{{Kind: "Job", Name: "report-1", UID: "job-uid", Controller: &isController}},
+				},
+				Status: corev1.PodStatus{
+					Phase:             phase,
+					ContainerStatuses: []corev1.ContainerStatus{{Name: "report", ContainerID: "docker://33124124"}},
+				},
+			}
+		}
+	)
+
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	analyzer := &Analyzer{
+		ApiServer: fake.NewClientBuilder().WithScheme(scheme).WithObjects(cronJob, job,
+			newPod("report-1-a", corev1.PodFailed), newPod("report-1-b", corev1.PodRunning)).Build(),
+	}
+
+	podList, err := analyzer.GetCronJobPodListByName(context.Background(), "ns", []string{"report"}, v1alpha1.FirstContainer)
+	if err != nil {
+		t.Fatalf("GetCronJobPodListByName() error = %v", err)
+	}
+	if len(podList) != 1 || podList[0].PodName != "report-1-b" {
+		t.Errorf("GetCronJobPodListByName() got = %v, want only running pod report-1-b", podList)
+	}
+}
+
+func TestAnalyzer_GetServicePodListByLabel(t *testing.T) {
+	var (
+		ready, notReady = true, false
+		svc             = &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "ns", Labels: map[string]string{"tier": "frontend"}}}
+		newPod          = func(name string) *corev1.Pod {
+			return &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns"},
+				Status: corev1.PodStatus{
+					ContainerStatuses: []corev1.ContainerStatus{{Name: "web", ContainerID: "docker://33124124", Image: "nginx:1.25"}},
+				},
+			}
+		}
+		newEndpoint = func(kind, name string, isReady *bool) discoveryv1.Endpoint {
+			return discoveryv1.Endpoint{
+				Addresses:  []string{"1.2.3.4"},
+				Conditions: discoveryv1.EndpointConditions{Ready: isReady},
+				TargetRef:  &corev1.ObjectReference{Kind: kind, Name: name, Namespace: "ns"},
+			}
+		}
+		slice = &discoveryv1.EndpointSlice{
+			ObjectMeta:  metav1.ObjectMeta{Name: "web-abcde", Namespace: "ns", Labels: map[string]string{discoveryv1.LabelServiceName: "web"}},
+			AddressType: discoveryv1.AddressTypeIPv4,
+			Endpoints: []discoveryv1.Endpoint{
+				newEndpoint("Pod", "web-0", &ready),
+				newEndpoint("Pod", "web-1", nil),
+				newEndpoint("Pod", "web-2", &notReady),
+				newEndpoint("Node", "node-1", &ready),
+			},
+		}
+	)
+
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	analyzer := &Analyzer{
+		ApiServer: fake.NewClientBuilder().WithScheme(scheme).WithObjects(svc, slice, newPod("web-0"), newPod("web-1"), newPod("web-2"), newPod("other-0")).Build(),
+	}
+
+	podList, err := analyzer.GetServicePodListByLabel(context.Background(), "ns", map[string]string{"tier": "frontend"}, v1alpha1.FirstContainer)
+	if err != nil {
+		t.Fatalf("GetServicePodListByLabel() error = %v", err)
+	}
+
+	var got []string
+	for _, unitPod := range podList {
+		got = append(got, unitPod.PodName)
+	}
+	if !reflect.DeepEqual(got, []string{"web-0", "web-1"}) {
+		t.Errorf("GetServicePodListByLabel() got = %v, want the ready backends [web-0 web-1]", got)
+	}
+
+	podList, err = analyzer.GetServicePodListByName(context.Background(), "ns", []string{"api"}, v1alpha1.FirstContainer)
+	if err != nil || len(podList) != 0 {
+		t.Errorf("GetServicePodListByName() got = %v, error = %v, want no pod of a service without endpoints", podList, err)
+	}
+}
+
+func TestExcludePods(t *testing.T) {
+	podList := []*model.PodObject{
+		{PodName: "chaosmeta-inject-0", Labels: map[string]string{"app": "chaosmeta-inject"}},
+		{PodName: "calico-node-x", Labels: map[string]string{"k8s-app": "calico-node", "tier": "cni"}},
+		{PodName: "nginx-0", Labels: map[string]string{"app": "nginx", "tier": "cni"}},
+	}
+
+	tests := []struct {
+		name         string
+		selectorUnit v1alpha1.SelectorUnit
+		want         []string
+	}{
+		{
+			name:         "no exclusion",
+			selectorUnit: v1alpha1.SelectorUnit{},
+			want:         []string{"chaosmeta-inject-0", "calico-node-x", "nginx-0"},
+		},
+		{
+			name:         "exclude names",
+			selectorUnit: v1alpha1.SelectorUnit{ExcludeNames: []string{"chaosmeta-inject-0"}},
+			want:         []string{"calico-node-x", "nginx-0"},
+		},
+		{
+			name:         "exclude label matches all pairs",
+			selectorUnit: v1alpha1.SelectorUnit{ExcludeLabel: map[string]string{"k8s-app": "calico-node", "tier": "cni"}},
+			want:         []string{"chaosmeta-inject-0", "nginx-0"},
+		},
+		{
+			name: "exclude both",
+			selectorUnit: v1alpha1.SelectorUnit{
+				ExcludeNames: []string{"chaosmeta-inject-0"},
+				ExcludeLabel: map[string]string{"tier": "cni"},
+			},
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, unitPod := range ExcludePods(tt.selectorUnit, podList) {
+				got = append(got, unitPod.PodName)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ExcludePods() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnalyzer_GetPodListByOwner(t *testing.T) {
+	var (
+		isController = true
+		apiVersions  = map[string]string{"ReplicaSet": "apps/v1", "Deployment": "apps/v1", "StatefulSet": "apps/v1", "Job": "batch/v1", "CronJob": "batch/v1"}
+		controlledBy = func(kind, name string) []metav1.OwnerReference {
+			return []metav1.OwnerReference{{APIVersion: apiVersions[kind], Kind: kind, Name: name, UID: types.UID(name), Controller: &isController}}
+		}
+		newPod = func(name string, owner []metav1.OwnerReference) *corev1.Pod {
+			return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns", OwnerReferences: owner}}
+		}
+		objects = []client.Object{
+			// two ReplicaSets of the same deployment around a rollout
+			&appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Name: "web-5d8f", Namespace: "ns", OwnerReferences: controlledBy("Deployment", "web")}},
+			&appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Name: "web-7c9a", Namespace: "ns", OwnerReferences: controlledBy("Deployment", "web")}},
+			&batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "report-1", Namespace: "ns", OwnerReferences: controlledBy("CronJob", "report")}},
+			newPod("web-5d8f-a", controlledBy("ReplicaSet", "web-5d8f")),
+			newPod("web-7c9a-a", controlledBy("ReplicaSet", "web-7c9a")),
+			newPod("report-1-a", controlledBy("Job", "report-1")),
+			newPod("db-0", controlledBy("StatefulSet", "db")),
+			newPod("standalone", nil),
+			// a CRD of the same kind in another group
+			newPod("kruise-0", []metav1.OwnerReference{{APIVersion: "apps.kruise.io/v1beta1", Kind: "StatefulSet", Name: "db", UID: "kruise", Controller: &isController}}),
+		}
+	)
+
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	analyzer := &Analyzer{
+		ApiServer: fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build(),
+	}
+
+	tests := []struct {
+		name  string
+		owner *v1alpha1.OwnerSelector
+		want  []string
+	}{
+		{name: "deployment", owner: &v1alpha1.OwnerSelector{Kind: "Deployment", Name: "web"}, want: []string{"web-5d8f-a", "web-7c9a-a"}},
+		{name: "replicaset", owner: &v1alpha1.OwnerSelector{Kind: "ReplicaSet", Name: "web-7c9a"}, want: []string{"web-7c9a-a"}},
+		{name: "cronjob", owner: &v1alpha1.OwnerSelector{Kind: "CronJob", Name: "report"}, want: []string{"report-1-a"}},
+		{name: "statefulset", owner: &v1alpha1.OwnerSelector{Kind: "StatefulSet", Name: "db"}, want: []string{"db-0"}},
+		{name: "not found", owner: &v1alpha1.OwnerSelector{Kind: "Deployment", Name: "api"}, want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			podList, err := analyzer.GetPodListByOwner(context.Background(), "ns", tt.owner, "")
+			if err != nil {
+				t.Fatalf("GetPodListByOwner() error = %v", err)
+			}
+
+			var got []string
+			for _, unitPod := range podList {
+				got = append(got, unitPod.PodName)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetPodListByOwner() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnalyzer_FilterPodListByField(t *testing.T) {
+	var (
+		newPod = func(name, node string, phase corev1.PodPhase, qos corev1.PodQOSClass, restarts ...int32) *corev1.Pod {
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns"},
+				Spec:       corev1.PodSpec{NodeName: node},
+				Status:     corev1.PodStatus{Phase: phase, QOSClass: qos},
+			}
+			for _, unitRestart := range restarts {
+				pod.Status.ContainerStatuses = append(pod.Status.ContainerStatuses, corev1.ContainerStatus{RestartCount: unitRestart})
+			}
+			return pod
+		}
+		newNode = func(name, zone string) *corev1.Node {
+			return &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{corev1.LabelTopologyZone: zone}}}
+		}
+		objects = []client.Object{
+			newNode("node-a", "zone-a"),
+			newNode("node-b", "zone-b"),
+			newPod("burstable-a", "node-a", corev1.PodRunning, corev1.PodQOSBurstable, 1, 2),
+			newPod("burstable-b", "node-b", corev1.PodRunning, corev1.PodQOSBurstable),
+			newPod("guaranteed-a", "node-a", corev1.PodRunning, corev1.PodQOSGuaranteed),
+			newPod("pending-a", "node-a", corev1.PodPending, corev1.PodQOSBurstable),
+		}
+		three int32 = 3
+	)
+
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	analyzer := &Analyzer{
+		ApiServer: fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build(),
+	}
+	// the pods are filtered by the status kept in the list, without listing them again
+	podList, err := analyzer.GetPodListByPodName(context.Background(), "ns", []string{"burstable-a", "burstable-b", "guaranteed-a", "pending-a"}, "")
+	if err != nil {
+		t.Fatalf("GetPodListByPodName() error = %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		field *v1alpha1.FieldSelector
+		want  []string
+	}{
+		{
+			name:  "burstable in zone-a",
+			field: &v1alpha1.FieldSelector{QOSClass: []string{"Burstable"}, Zone: []string{"zone-a"}},
+			want:  []string{"burstable-a", "pending-a"},
+		},
+		{
+			name:  "running with restart",
+			field: &v1alpha1.FieldSelector{Phase: []string{"Running"}, MinRestartCount: &three},
+			want:  []string{"burstable-a"},
+		},
+		{
+			name:  "max restart",
+			field: &v1alpha1.FieldSelector{Phase: []string{"Running"}, MaxRestartCount: new(int32)},
+			want:  []string{"burstable-b", "guaranteed-a"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotList, err := analyzer.FilterPodListByField(context.Background(), podList, tt.field)
+			if err != nil {
+				t.Fatalf("FilterPodListByField() error = %v", err)
+			}
+
+			var got []string
+			for _, unitPod := range gotList {
+				got = append(got, unitPod.PodName)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FilterPodListByField() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnalyzer_GetPodListByLabelInNode_Cluster(t *testing.T) {
+	var (
+		label  = map[string]string{"app": "chaosmetad"}
+		newPod = func(name, hostIP string) *corev1.Pod {
+			return &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "chaosmeta", Labels: label},
+				Status:     corev1.PodStatus{HostIP: hostIP},
+			}
+		}
+	)
+
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	// the operator's client is not used for the experiments run in another cluster
+	analyzer := &Analyzer{
+		ApiServer: fake.NewClientBuilder().WithScheme(scheme).Build(),
+	}
+	ctx := multicluster.WithCluster(context.Background(), &multicluster.Cluster{
+		Name:   "cluster-a",
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(newPod("chaosmetad-a", "10.0.0.1"), newPod("chaosmetad-b", "10.0.0.2")).Build(),
+	})
+
+	podList, err := analyzer.GetPodListByLabelInNode(ctx, "chaosmeta", label, "10.0.0.2")
+	if err != nil {
+		t.Fatalf("GetPodListByLabelInNode() error = %v", err)
+	}
+	if len(podList) != 1 || podList[0].PodName != "chaosmetad-b" {
+		t.Errorf("GetPodListByLabelInNode() got = %v, want [chaosmetad-b]", podList)
+	}
+}
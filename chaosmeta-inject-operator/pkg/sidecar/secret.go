@@ -0,0 +1,227 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sidecar
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/config"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"math/big"
+	"os"
+	"path/filepath"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"time"
+)
+
+const (
+	// TokenKey, CertKey, KeyKey and CAKey are the keys of the Secret of a pod
+	TokenKey = "token"
+	CertKey  = "tls.crt"
+	KeyKey   = "tls.key"
+	CAKey    = "ca.crt"
+	// ServerName is the DNS name in the self-signed certificate of every sidecar, the caller pins the certificate
+	ServerName = "chaosmeta-sidecar"
+
+	certValidity = time.Hour * 24 * 365 * 10
+)
+
+//+kubebuilder:rbac:groups=core,resources=secrets,verbs=get;create
+
+// SecretReconciler creates the Secret of the pods injected with the sidecar. The Secret is owned by the pod, so it
+// is deleted with the pod, which is unknown when the webhook injects the sidecar
+type SecretReconciler struct {
+	Client client.Client
+	Config *config.ExecutorConfig
+}
+
+func (r *SecretReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	pod, logger := &corev1.Pod{}, log.FromContext(ctx)
+	if err := r.Client.Get(ctx, req.NamespacedName, pod); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+
+		return ctrl.Result{}, fmt.Errorf("get pod error: %s", err.Error())
+	}
+
+	name := pod.Annotations[v1alpha1.SidecarSecretAnnotationKey]
+	if name == "" || pod.DeletionTimestamp != nil {
+		return ctrl.Result{}, nil
+	}
+
+	secret := &corev1.Secret{}
+	err := r.Client.Get(ctx, types.NamespacedName{Namespace: pod.Namespace, Name: name}, secret)
+	if err == nil {
+		if !IsOwnedBy(secret, string(pod.UID)) {
+			logger.Error(fmt.Errorf("secret is not owned by the pod"), fmt.Sprintf("secret %s/%s of sidecar is not created", pod.Namespace, name))
+		}
+		return ctrl.Result{}, nil
+	}
+
+	if !errors.IsNotFound(err) {
+		return ctrl.Result{}, fmt.Errorf("get secret error: %s", err.Error())
+	}
+
+	clientCA, err := os.ReadFile(filepath.Join(r.Config.SidecarConfig.TLSPath, CAKey))
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("read CA of the callers error: %s", err.Error())
+	}
+
+	secret, err = NewSecret(pod, name, clientCA)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err := r.Client.Create(ctx, secret); err != nil && !errors.IsAlreadyExists(err) {
+		return ctrl.Result{}, fmt.Errorf("create secret error: %s", err.Error())
+	}
+
+	logger.Info(fmt.Sprintf("secret %s/%s of sidecar is created", pod.Namespace, name))
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager only the creation of the pods is watched, which includes the pods listed when the operator starts
+func (r *SecretReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("sidecar-secret").
+		For(&corev1.Pod{}, builder.WithPredicates(predicate.Funcs{
+			CreateFunc: func(e event.CreateEvent) bool {
+				return e.Object.GetAnnotations()[v1alpha1.SidecarSecretAnnotationKey] != ""
+			},
+			UpdateFunc:  func(e event.UpdateEvent) bool { return false },
+			DeleteFunc:  func(e event.DeleteEvent) bool { return false },
+			GenericFunc: func(e event.GenericEvent) bool { return false },
+		})).
+		Complete(r)
+}
+
+// NewSecret generates the token and the self-signed certificate of the sidecar of the pod, clientCA verifies the callers
+func NewSecret(pod *corev1.Pod, name string, clientCA []byte) (*corev1.Secret, error) {
+	token, err := newRandomHex(32)
+	if err != nil {
+		return nil, fmt.Errorf("generate token error: %s", err.Error())
+	}
+
+	cert, key, err := newSelfSignedCert()
+	if err != nil {
+		return nil, fmt.Errorf("generate certificate error: %s", err.Error())
+	}
+
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: pod.Namespace,
+			Name:      name,
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: "v1",
+				Kind:       "Pod",
+				Name:       pod.Name,
+				UID:        pod.UID,
+			}},
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{
+			TokenKey: []byte(token),
+			CertKey:  cert,
+			KeyKey:   key,
+			CAKey:    clientCA,
+		},
+	}, nil
+}
+
+// IsOwnedBy a Secret named by the annotation of a pod is only trusted if it is created for the pod
+func IsOwnedBy(secret *corev1.Secret, podUID string) bool {
+	for _, ref := range secret.OwnerReferences {
+		if ref.Kind == "Pod" && string(ref.UID) == podUID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GetCredential returns the token of the sidecar and the TLS config pinning its certificate
+func GetCredential(secret *corev1.Secret, clientCert tls.Certificate) (string, *tls.Config, error) {
+	token := string(secret.Data[TokenKey])
+	if token == "" {
+		return "", nil, fmt.Errorf("no %s in secret %s/%s", TokenKey, secret.Namespace, secret.Name)
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(secret.Data[CertKey]) {
+		return "", nil, fmt.Errorf("%s in secret %s/%s is not a PEM certificate", CertKey, secret.Namespace, secret.Name)
+	}
+
+	return token, &tls.Config{
+		RootCAs:      pool,
+		ServerName:   ServerName,
+		Certificates: []tls.Certificate{clientCert},
+		MinVersion:   tls.VersionTLS12,
+	}, nil
+}
+
+func newSelfSignedCert() ([]byte, []byte, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: ServerName},
+		DNSNames:              []string{ServerName},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(certValidity),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), nil
+}
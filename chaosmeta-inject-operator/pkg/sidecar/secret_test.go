@@ -0,0 +1,92 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sidecar
+
+import (
+	"context"
+	"crypto/tls"
+	"github.com/stretchr/testify/assert"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/config"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"os"
+	"path/filepath"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"testing"
+)
+
+func TestSecretReconciler(t *testing.T) {
+	caPEM, _, err := newSelfSignedCert()
+	assert.NoError(t, err)
+	tlsPath := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(tlsPath, CAKey), caPEM, 0600))
+
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pod1", UID: "uid1",
+		Annotations: map[string]string{v1alpha1.SidecarSecretAnnotationKey: "chaosmeta-sidecar-0123456789abcdef"}}}
+	plain := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pod2", UID: "uid2"}}
+	c := fake.NewClientBuilder().WithObjects(pod, plain).Build()
+	r := &SecretReconciler{Client: c, Config: &config.ExecutorConfig{SidecarConfig: config.SidecarExecutorConfig{TLSPath: tlsPath}}}
+
+	ctx := context.Background()
+	_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "pod1"}})
+	assert.NoError(t, err)
+
+	secret := &corev1.Secret{}
+	assert.NoError(t, c.Get(ctx, types.NamespacedName{Namespace: "default", Name: "chaosmeta-sidecar-0123456789abcdef"}, secret))
+	assert.True(t, IsOwnedBy(secret, "uid1"))
+	assert.False(t, IsOwnedBy(secret, "uid2"))
+	assert.Equal(t, 64, len(secret.Data[TokenKey]))
+	assert.Equal(t, caPEM, secret.Data[CAKey])
+	_, err = tls.X509KeyPair(secret.Data[CertKey], secret.Data[KeyKey])
+	assert.NoError(t, err)
+
+	// the secret is never regenerated
+	_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "pod1"}})
+	assert.NoError(t, err)
+	again := &corev1.Secret{}
+	assert.NoError(t, c.Get(ctx, types.NamespacedName{Namespace: "default", Name: "chaosmeta-sidecar-0123456789abcdef"}, again))
+	assert.Equal(t, secret.Data[TokenKey], again.Data[TokenKey])
+
+	// the pods without sidecar and the missing pods are ignored
+	_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "pod2"}})
+	assert.NoError(t, err)
+	_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "pod3"}})
+	assert.NoError(t, err)
+	secretList := &corev1.SecretList{}
+	assert.NoError(t, c.List(ctx, secretList))
+	assert.Equal(t, 1, len(secretList.Items))
+}
+
+func TestGetCredential(t *testing.T) {
+	secret, err := NewSecret(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pod1", UID: "uid1"}}, "s1", nil)
+	assert.NoError(t, err)
+
+	token, tlsConfig, err := GetCredential(secret, tls.Certificate{})
+	assert.NoError(t, err)
+	assert.Equal(t, string(secret.Data[TokenKey]), token)
+	assert.Equal(t, ServerName, tlsConfig.ServerName)
+
+	delete(secret.Data, TokenKey)
+	_, _, err = GetCredential(secret, tls.Certificate{})
+	assert.Error(t, err)
+
+	_, _, err = GetCredential(&corev1.Secret{Data: map[string][]byte{TokenKey: []byte("t"), CertKey: []byte("not a pem")}}, tls.Certificate{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,199 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sidecar
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/config"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/executor/remoteexecutor/base"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"net/http"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+	"strconv"
+)
+
+const (
+	webhookPath = "/mutate-v1-pod"
+	portName    = "chaosmeta-grpc"
+	// secretPrefix the name of the Secret of a pod is generated by the webhook, the name of the pod may be unknown
+	secretPrefix = "chaosmeta-sidecar-"
+)
+
+// the env read by the grpc service of chaosmetad and the keys of the Secret of a pod
+var secretEnvs = []struct {
+	env string
+	key string
+}{
+	{env: "CHAOSMETAD_GRPC_TOKEN", key: TokenKey},
+	{env: "CHAOSMETAD_GRPC_TLS_CERT", key: CertKey},
+	{env: "CHAOSMETAD_GRPC_TLS_KEY", key: KeyKey},
+	{env: "CHAOSMETAD_GRPC_TLS_CA", key: CAKey},
+}
+
+// defaultCapabilities are enough for the faults of cpu, memory, network and process in the pod
+var defaultCapabilities = []string{"NET_ADMIN", "SYS_PTRACE", "KILL", "SYS_RESOURCE"}
+
+// the namespaceSelector of the webhook is added to the manifests by hand, the namespace is checked again by the handler
+//+kubebuilder:webhook:path=/mutate-v1-pod,mutating=true,failurePolicy=ignore,sideEffects=None,groups="",resources=pods,verbs=create,versions=v1,name=mpod.kb.io,admissionReviewVersions=v1
+
+// SetupWebhookWithManager registers the pod webhook injecting the chaosmeta sidecar
+func SetupWebhookWithManager(mgr manager.Manager, executorConfig *config.ExecutorConfig) {
+	mgr.GetWebhookServer().Register(webhookPath, &webhook.Admission{Handler: &Injector{
+		Client: mgr.GetClient(),
+		Config: executorConfig,
+	}})
+}
+
+// Injector injects the chaosmeta sidecar into the new pods of the namespaces labeled "chaosmeta.io/sidecar-injection: enabled".
+// The sidecar serves the grpc service of chaosmetad and is dormant until an experiment of "sidecar" executor calls it.
+// A pod is never rejected by the injector, it is created without the sidecar if anything goes wrong
+type Injector struct {
+	Client  client.Client
+	Config  *config.ExecutorConfig
+	decoder *admission.Decoder
+}
+
+var _ admission.DecoderInjector = &Injector{}
+
+func (i *Injector) InjectDecoder(d *admission.Decoder) error {
+	i.decoder = d
+	return nil
+}
+
+func (i *Injector) Handle(ctx context.Context, req admission.Request) admission.Response {
+	logger := log.FromContext(ctx)
+	pod := &corev1.Pod{}
+	if err := i.decoder.Decode(req, pod); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	ns := &corev1.Namespace{}
+	if err := i.Client.Get(ctx, types.NamespacedName{Name: req.Namespace}, ns); err != nil {
+		logger.Error(err, fmt.Sprintf("get namespace %s error, sidecar is not injected", req.Namespace))
+		return admission.Allowed("get namespace error")
+	}
+
+	if ns.Labels[v1alpha1.SidecarInjectionLabelKey] != v1alpha1.SidecarInjectionEnabled {
+		return admission.Allowed("sidecar injection is not enabled in namespace")
+	}
+
+	if pod.Labels[v1alpha1.SidecarInjectionLabelKey] == v1alpha1.SidecarInjectionDisabled {
+		return admission.Allowed("sidecar injection is disabled by pod")
+	}
+
+	injected, err := InjectSidecar(pod, i.Config)
+	if err != nil {
+		logger.Error(err, "inject sidecar error, sidecar is not injected")
+		return admission.Allowed("inject sidecar error")
+	}
+
+	if !injected {
+		return admission.Allowed("sidecar is already injected")
+	}
+
+	marshaledPod, err := json.Marshal(pod)
+	if err != nil {
+		logger.Error(err, "marshal pod error, sidecar is not injected")
+		return admission.Allowed("marshal pod error")
+	}
+
+	return admission.PatchResponseFromRaw(req.Object.Raw, marshaledPod)
+}
+
+// InjectSidecar adds the sidecar only serving the grpc service, the token and certificate of the sidecar are in a
+// Secret of the pod, which is created by the SecretReconciler after the pod, and the sidecar waits for it. The process
+// namespace of the pod is shared with the sidecar only if configured, so the processes of the other containers are
+// visible to the faults. False means the sidecar is already in the pod
+func InjectSidecar(pod *corev1.Pod, executorConfig *config.ExecutorConfig) (bool, error) {
+	for _, c := range pod.Spec.Containers {
+		if c.Name == v1alpha1.SidecarContainerName {
+			return false, nil
+		}
+	}
+
+	suffix, err := newRandomHex(8)
+	if err != nil {
+		return false, fmt.Errorf("generate name of secret error: %s", err.Error())
+	}
+	secretName := secretPrefix + suffix
+
+	sidecarConfig := executorConfig.SidecarConfig
+	if sidecarConfig.ShareProcessNamespace {
+		shareProcessNamespace := true
+		pod.Spec.ShareProcessNamespace = &shareProcessNamespace
+	}
+
+	capabilities := sidecarConfig.Capabilities
+	if len(capabilities) == 0 {
+		capabilities = defaultCapabilities
+	}
+	addCapabilities := make([]corev1.Capability, 0, len(capabilities))
+	for _, c := range capabilities {
+		addCapabilities = append(addCapabilities, corev1.Capability(c))
+	}
+
+	if pod.Annotations == nil {
+		pod.Annotations = make(map[string]string)
+	}
+	pod.Annotations[v1alpha1.SidecarSecretAnnotationKey] = secretName
+
+	env := make([]corev1.EnvVar, 0, len(secretEnvs))
+	for _, e := range secretEnvs {
+		env = append(env, corev1.EnvVar{
+			Name: e.env,
+			ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: secretName},
+				Key:                  e.key,
+			}},
+		})
+	}
+	pod.Spec.Containers = append(pod.Spec.Containers, corev1.Container{
+		Name:            v1alpha1.SidecarContainerName,
+		Image:           sidecarConfig.Image,
+		ImagePullPolicy: corev1.PullIfNotPresent,
+		Command: []string{
+			base.GetExecutorPath(sidecarConfig.LocalExecPath, executorConfig.Executor, executorConfig.Version),
+			"server", "--grpc-port", strconv.Itoa(sidecarConfig.GrpcPort), "--disable-http",
+		},
+		Env: env,
+		Ports: []corev1.ContainerPort{
+			{Name: portName, ContainerPort: int32(sidecarConfig.GrpcPort), Protocol: corev1.ProtocolTCP},
+		},
+		SecurityContext: &corev1.SecurityContext{Capabilities: &corev1.Capabilities{Add: addCapabilities}},
+	})
+
+	return true, nil
+}
+
+func newRandomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,138 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sidecar
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/config"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+	"strings"
+	"testing"
+)
+
+var testConfig = &config.ExecutorConfig{
+	Executor: "chaosmetad",
+	Version:  "0.3.9",
+	SidecarConfig: config.SidecarExecutorConfig{
+		Image:         "chaosmeta-daemon:v0.3.9",
+		LocalExecPath: "/opt/chaosmeta",
+		GrpcPort:      29597,
+	},
+}
+
+func newRequest(t *testing.T, pod *corev1.Pod) admission.Request {
+	raw, err := json.Marshal(pod)
+	assert.NoError(t, err)
+	return admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+		Namespace: pod.Namespace,
+		Operation: admissionv1.Create,
+		Object:    runtime.RawExtension{Raw: raw},
+	}}
+}
+
+func TestInjector_Handle(t *testing.T) {
+	c := fake.NewClientBuilder().WithObjects(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "enabled", Labels: map[string]string{
+			v1alpha1.SidecarInjectionLabelKey: v1alpha1.SidecarInjectionEnabled,
+		}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+	).Build()
+
+	decoder, err := admission.NewDecoder(scheme.Scheme)
+	assert.NoError(t, err)
+	injector := &Injector{Client: c, Config: testConfig}
+	assert.NoError(t, injector.InjectDecoder(decoder))
+
+	ctx := context.Background()
+	newPod := func(ns string, labels map[string]string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: "pod1", Labels: labels},
+			Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "nginx", Image: "nginx"}}},
+		}
+	}
+
+	resp := injector.Handle(ctx, newRequest(t, newPod("enabled", nil)))
+	assert.True(t, resp.Allowed)
+	assert.NotEqual(t, 0, len(resp.Patches))
+
+	// not labeled namespace
+	resp = injector.Handle(ctx, newRequest(t, newPod("default", nil)))
+	assert.True(t, resp.Allowed)
+	assert.Equal(t, 0, len(resp.Patches))
+
+	// disabled by pod
+	resp = injector.Handle(ctx, newRequest(t, newPod("enabled", map[string]string{
+		v1alpha1.SidecarInjectionLabelKey: v1alpha1.SidecarInjectionDisabled,
+	})))
+	assert.True(t, resp.Allowed)
+	assert.Equal(t, 0, len(resp.Patches))
+
+	// the pod is never rejected
+	resp = injector.Handle(ctx, newRequest(t, newPod("unknown", nil)))
+	assert.True(t, resp.Allowed)
+	assert.Equal(t, 0, len(resp.Patches))
+}
+
+func TestInjectSidecar(t *testing.T) {
+	pod := &corev1.Pod{Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "nginx", Image: "nginx"}}}}
+
+	injected, err := InjectSidecar(pod, testConfig)
+	assert.NoError(t, err)
+	assert.True(t, injected)
+	assert.Equal(t, 2, len(pod.Spec.Containers))
+	assert.Nil(t, pod.Spec.ShareProcessNamespace, "the process namespace is shared only if configured")
+	secretName := pod.Annotations[v1alpha1.SidecarSecretAnnotationKey]
+	assert.True(t, strings.HasPrefix(secretName, secretPrefix), secretName)
+	assert.Equal(t, len(secretPrefix)+16, len(secretName))
+
+	sidecar := pod.Spec.Containers[1]
+	assert.Equal(t, v1alpha1.SidecarContainerName, sidecar.Name)
+	assert.Equal(t, "chaosmeta-daemon:v0.3.9", sidecar.Image)
+	assert.Equal(t, []string{"/opt/chaosmeta/chaosmetad-0.3.9/chaosmetad", "server", "--grpc-port", "29597", "--disable-http"}, sidecar.Command)
+	assert.Equal(t, 4, len(sidecar.Env))
+	for _, env := range sidecar.Env {
+		assert.Nil(t, env.ValueFrom.FieldRef, "the token is never in the annotations")
+		assert.Equal(t, secretName, env.ValueFrom.SecretKeyRef.Name)
+	}
+	assert.Equal(t, "CHAOSMETAD_GRPC_TOKEN", sidecar.Env[0].Name)
+	assert.Equal(t, TokenKey, sidecar.Env[0].ValueFrom.SecretKeyRef.Key)
+	assert.Equal(t, int32(29597), sidecar.Ports[0].ContainerPort)
+	assert.Nil(t, sidecar.SecurityContext.Privileged)
+	assert.Equal(t, []corev1.Capability{"NET_ADMIN", "SYS_PTRACE", "KILL", "SYS_RESOURCE"}, sidecar.SecurityContext.Capabilities.Add)
+
+	// injected only once
+	injected, err = InjectSidecar(pod, testConfig)
+	assert.NoError(t, err)
+	assert.False(t, injected)
+	assert.Equal(t, 2, len(pod.Spec.Containers))
+
+	// every pod has its own secret
+	another := &corev1.Pod{Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "nginx", Image: "nginx"}}}}
+	_, err = InjectSidecar(another, &config.ExecutorConfig{SidecarConfig: config.SidecarExecutorConfig{ShareProcessNamespace: true}})
+	assert.NoError(t, err)
+	assert.True(t, *another.Spec.ShareProcessNamespace)
+	assert.NotEqual(t, secretName, another.Annotations[v1alpha1.SidecarSecretAnnotationKey])
+}
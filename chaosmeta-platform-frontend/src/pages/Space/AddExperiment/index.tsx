@@ -213,7 +213,11 @@ const AddExperiment = () => {
       };
       const experimentId = history?.location?.query?.experimentId;
       if (experimentId) {
-        editExperiment?.run({ ...params, uuid: experimentId });
+        editExperiment?.run({
+          ...params,
+          uuid: experimentId,
+          version: baseInfo?.version,
+        });
       } else {
         handleCreateExperiment?.run(params);
       }
@@ -18,9 +18,14 @@ package app
 
 import (
 	"chaosmeta-platform/config"
+	"chaosmeta-platform/pkg/service/app"
+	"chaosmeta-platform/pkg/service/authz"
 	"chaosmeta-platform/pkg/service/experiment"
+	"chaosmeta-platform/pkg/service/host"
 	"chaosmeta-platform/pkg/service/inject"
 	"chaosmeta-platform/pkg/service/namespace"
+	"chaosmeta-platform/pkg/service/sandbox"
+	"chaosmeta-platform/pkg/service/trigger"
 	"chaosmeta-platform/pkg/service/user"
 	"chaosmeta-platform/util/log"
 	"fmt"
@@ -61,12 +66,19 @@ func initConfig() {
 	})
 
 	config.Setup()
+	if err := authz.Init(); err != nil {
+		log.Panic(err)
+	}
 	user.Init()
 	namespace.Init()
 	if err := inject.Init(); err != nil {
 		log.Panic(err)
 	}
 	experiment.Init()
+	app.Init()
+	host.Init()
+	trigger.Init()
+	sandbox.Init()
 	//if err := clientset.Init(); err != nil {
 	//	log.Panic(err)
 	//}
@@ -17,10 +17,13 @@
 package config
 
 import (
+	"fmt"
 	"github.com/spf13/viper"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"log"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
 var DefaultRunOptIns *Config
@@ -30,8 +33,17 @@ type RunMode string
 const (
 	RunModeKubeConfig     RunMode = "KubeConfig"
 	RunModeServiceAccount RunMode = "ServiceAccount"
+	// RunModeStandalone runs experiments on the hosts of the inventory through chaosmetad servers, without kubernetes
+	RunModeStandalone RunMode = "Standalone"
+
+	DefaultAgentPort   = 29595
+	DefaultLabelDomain = "chaosmeta.io"
 )
 
+func (r RunMode) IsStandalone() bool {
+	return r == RunModeStandalone
+}
+
 func (r RunMode) Int() int {
 	switch r {
 	case RunModeKubeConfig:
@@ -60,6 +72,45 @@ type Config struct {
 		Path  string `yaml:"path"`
 		Level string `yaml:"level"`
 	} `yaml:"log"`
+	UserPolicy struct {
+		InactiveDays int `yaml:"inactiveDays"`
+	} `yaml:"userPolicy"`
+	Budget struct {
+		WebhookUrl string `yaml:"webhookUrl"`
+	} `yaml:"budget"`
+	NamespaceMirror struct {
+		Enabled       bool     `yaml:"enabled"`
+		LabelSelector string   `yaml:"labelSelector"`
+		AdminRoles    []string `yaml:"adminRoles"`
+		Interval      string   `yaml:"interval"`
+	} `yaml:"namespaceMirror"`
+	Sandbox struct {
+		DefaultTTL          string `yaml:"defaultTTL"`
+		MaxTTL              string `yaml:"maxTTL"`
+		KubeNamespacePrefix string `yaml:"kubeNamespacePrefix"`
+		Interval            string `yaml:"interval"`
+	} `yaml:"sandbox"`
+	Naming struct {
+		WorkflowPrefix string `yaml:"workflowPrefix"`
+		LabelDomain    string `yaml:"labelDomain"`
+	} `yaml:"naming"`
+	Standalone struct {
+		InventoryPath string `yaml:"inventoryPath"`
+		AgentPort     int    `yaml:"agentPort"`
+	} `yaml:"standalone"`
+	EventTrigger struct {
+		Nats struct {
+			Url      string   `yaml:"url"`
+			Subjects []string `yaml:"subjects"`
+			Token    string   `yaml:"token"`
+		} `yaml:"nats"`
+	} `yaml:"eventTrigger"`
+	Authz struct {
+		Provider string `yaml:"provider"`
+		Url      string `yaml:"url"`
+		Timeout  string `yaml:"timeout"`
+		FailOpen bool   `yaml:"failOpen"`
+	} `yaml:"authz"`
 	RunMode RunMode `yaml:"runmode"`
 }
 
@@ -92,6 +143,27 @@ func InitConfig() {
 	if DefaultRunOptIns.WorkflowNamespace == "" {
 		DefaultRunOptIns.WorkflowNamespace = "chaosmeta-inject"
 	}
+	if DefaultRunOptIns.Naming.LabelDomain == "" {
+		DefaultRunOptIns.Naming.LabelDomain = DefaultLabelDomain
+	}
+	if DefaultRunOptIns.Standalone.AgentPort == 0 {
+		DefaultRunOptIns.Standalone.AgentPort = DefaultAgentPort
+	}
+	if err := DefaultRunOptIns.validateNaming(); err != nil {
+		log.Panic(err)
+	}
+}
+
+// validateNaming the prefix is joined with the experiment instance id as the workflow name, and the domain is the
+// prefix of the label keys, both must be accepted by kubernetes
+func (c *Config) validateNaming() error {
+	if errs := validation.IsDNS1123Subdomain(c.Naming.WorkflowPrefix + "experiment"); len(errs) > 0 {
+		return fmt.Errorf("naming.workflowPrefix[%s] is invalid: %s", c.Naming.WorkflowPrefix, strings.Join(errs, "; "))
+	}
+	if errs := validation.IsDNS1123Subdomain(c.Naming.LabelDomain); len(errs) > 0 {
+		return fmt.Errorf("naming.labelDomain[%s] is invalid: %s", c.Naming.LabelDomain, strings.Join(errs, "; "))
+	}
+	return nil
 }
 
 func getCurrentPath() string {
@@ -22,8 +22,11 @@ import (
 	modelCommon "chaosmeta-platform/pkg/models/common"
 	"chaosmeta-platform/pkg/models/experiment"
 	"chaosmeta-platform/pkg/models/experiment_instance"
+	"chaosmeta-platform/pkg/models/host"
 	"chaosmeta-platform/pkg/models/inject/basic"
 	"chaosmeta-platform/pkg/models/namespace"
+	"chaosmeta-platform/pkg/models/scenario"
+	"chaosmeta-platform/pkg/models/trigger"
 	"chaosmeta-platform/pkg/models/user"
 	"chaosmeta-platform/util/log"
 	"fmt"
@@ -34,12 +37,14 @@ import (
 
 func Setup() {
 	orm.RegisterModel(
-		new(namespace.ClusterNamespace), new(namespace.Label), new(namespace.Namespace), new(namespace.UserNamespace), new(user.User),
+		new(namespace.ClusterNamespace), new(namespace.Label), new(namespace.Namespace), new(namespace.UserNamespace), new(namespace.Budget), new(namespace.Mirror), new(namespace.Sandbox), new(user.User),
 		new(cluster.Cluster),
-		new(agent.Agent),
+		new(agent.Agent), new(agent.App), new(agent.AppWorkload), new(host.Host),
 		new(basic.Scope), new(basic.Target), new(basic.Fault), new(basic.FlowInject), new(basic.MeasureInject), new(basic.Args),
 		new(experiment.WorkflowNode), new(experiment.LabelExperiment), new(experiment.FaultRange), new(experiment.FlowRange), new(experiment.MeasureRange), new(experiment.Experiment), new(experiment.ArgsValue),
 		new(experiment_instance.WorkflowNodeInstance), new(experiment_instance.LabelExperimentInstance), new(experiment_instance.FaultRangeInstance), new(experiment_instance.FlowRangeInstance), new(experiment_instance.MeasureRangeInstance), new(experiment_instance.ExperimentInstance), new(experiment_instance.ArgsValueInstance),
+		new(scenario.Scenario),
+		new(trigger.EventTrigger),
 	)
 
 	ticker := time.NewTicker(5 * time.Second)
@@ -0,0 +1,127 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package app
+
+import (
+	"chaosmeta-platform/pkg/gateway/apiserver/v1alpha1"
+	"chaosmeta-platform/pkg/service/app"
+	"chaosmeta-platform/util/log"
+	"context"
+	"encoding/json"
+	beego "github.com/beego/beego/v2/server/web"
+	"time"
+)
+
+type AppController struct {
+	v1alpha1.BeegoOutputController
+	beego.Controller
+}
+
+func (c *AppController) GetList() {
+	sort := c.GetString("sort")
+	name := c.GetString("name")
+	namespace := c.GetString("namespace")
+	clusterId, _ := c.GetInt("cluster_id", 0)
+	page, _ := c.GetInt("page", 1)
+	pageSize, _ := c.GetInt("page_size", 10)
+
+	appService := &app.AppService{}
+	total, appList, err := appService.GetList(context.Background(), name, clusterId, namespace, sort, page, pageSize)
+	if err != nil {
+		c.Error(&c.Controller, err)
+		return
+	}
+	c.Success(&c.Controller, ListAppResponse{Total: total, Page: page, PageSize: pageSize, Apps: appList})
+}
+
+func (c *AppController) Get() {
+	id, err := c.GetInt(":id")
+	if err != nil {
+		c.Error(&c.Controller, err)
+		return
+	}
+
+	appService := &app.AppService{}
+	appDetail, err := appService.Get(context.Background(), id)
+	if err != nil {
+		c.Error(&c.Controller, err)
+		return
+	}
+	c.Success(&c.Controller, appDetail)
+}
+
+func (c *AppController) Delete() {
+	id, err := c.GetInt(":id")
+	if err != nil {
+		c.Error(&c.Controller, err)
+		return
+	}
+
+	appService := &app.AppService{}
+	if err := appService.Delete(context.Background(), c.Ctx.Input.GetData("userName").(string), id); err != nil {
+		c.Error(&c.Controller, err)
+		return
+	}
+	c.Success(&c.Controller, "ok")
+}
+
+func (c *AppController) ListDiscovered() {
+	clusterId, _ := c.GetInt("cluster_id", 0)
+	namespace := c.GetString("namespace")
+	name := c.GetString("name")
+
+	appService := &app.AppService{}
+	apps, scanTime, err := appService.ListSuggestions(context.Background(), clusterId, namespace, name)
+	if err != nil {
+		c.Error(&c.Controller, err)
+		return
+	}
+	c.Success(&c.Controller, ListSuggestedAppResponse{ClusterID: clusterId, ScanTime: scanTime, Total: len(apps), Apps: apps})
+}
+
+func (c *AppController) Scan() {
+	var requestBody ScanAppRequest
+	if err := json.Unmarshal(c.Ctx.Input.RequestBody, &requestBody); err != nil {
+		c.Error(&c.Controller, err)
+		return
+	}
+
+	appService := &app.AppService{}
+	apps, err := appService.Scan(context.Background(), c.Ctx.Input.GetData("userName").(string), requestBody.ClusterID)
+	if err != nil {
+		c.Error(&c.Controller, err)
+		return
+	}
+	c.Success(&c.Controller, ListSuggestedAppResponse{ClusterID: requestBody.ClusterID, ScanTime: time.Now(), Total: len(apps), Apps: apps})
+}
+
+func (c *AppController) Adopt() {
+	var requestBody AdoptAppRequest
+	if err := json.Unmarshal(c.Ctx.Input.RequestBody, &requestBody); err != nil {
+		c.Error(&c.Controller, err)
+		return
+	}
+
+	appService := &app.AppService{}
+	appId, err := appService.Adopt(context.Background(), c.Ctx.Input.GetData("userName").(string), requestBody.ClusterID, requestBody.Namespace, requestBody.Name, requestBody.AppName)
+	if err != nil {
+		c.Error(&c.Controller, err)
+		return
+	}
+	c.Success(&c.Controller, AdoptAppResponse{ID: appId})
+	log.Info(c.Ctx.Input.GetData("userName").(string), "adopt app:", requestBody.Namespace, "/", requestBody.Name)
+}
@@ -0,0 +1,53 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package app
+
+import (
+	"chaosmeta-platform/pkg/models/agent"
+	"chaosmeta-platform/pkg/service/app"
+	"time"
+)
+
+type ListAppResponse struct {
+	Page     int         `json:"page"`
+	PageSize int         `json:"pageSize"`
+	Total    int64       `json:"total"`
+	Apps     []agent.App `json:"apps"`
+}
+
+type ListSuggestedAppResponse struct {
+	ClusterID int                `json:"clusterId"`
+	ScanTime  time.Time          `json:"scanTime"`
+	Total     int                `json:"total"`
+	Apps      []app.SuggestedApp `json:"apps"`
+}
+
+type AdoptAppRequest struct {
+	ClusterID int    `json:"cluster_id"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	// AppName overrides the suggested name when registering the application
+	AppName string `json:"app_name,omitempty"`
+}
+
+type AdoptAppResponse struct {
+	ID int64 `json:"id"`
+}
+
+type ScanAppRequest struct {
+	ClusterID int `json:"cluster_id"`
+}
@@ -26,7 +26,7 @@ type BeegoOutputController struct{}
 
 func (c BeegoOutputController) Error(bc *beego.Controller, err error) {
 	log.Error(err)
-	bc.Data["json"] = errors.ErrServer().WithMessage(err.Error())
+	bc.Data["json"] = errors.ErrServer().WithMessage(err.Error()).WithErrorInfo(errors.GetErrorInfo(err))
 	bc.ServeJSON()
 }
 
@@ -74,6 +74,7 @@ func (c *ExperimentController) GetExperimentDetail() {
 		c.Error(&c.Controller, err)
 		return
 	}
+	experiment.MaskSensitiveArgs(experimentGet.WorkflowNodes)
 	c.Success(&c.Controller, GetExperimentResponse{
 		Experiment: *experimentGet,
 	})
@@ -106,6 +107,33 @@ func (c *ExperimentController) CreateExperiment() {
 	})
 }
 
+func (c *ExperimentController) LintExperiment() {
+	var lintExperimentRequest experiment.ExperimentCreate
+	if err := json.Unmarshal(c.Ctx.Input.RequestBody, &lintExperimentRequest); err != nil {
+		c.Error(&c.Controller, err)
+		return
+	}
+
+	experimentService := experiment.ExperimentService{}
+	result, err := experimentService.LintExperiment(&lintExperimentRequest)
+	if err != nil {
+		c.Error(&c.Controller, err)
+		return
+	}
+	c.Success(&c.Controller, result)
+}
+
+func (c *ExperimentController) LintExistingExperiment() {
+	uuid := c.Ctx.Input.Param(":uuid")
+	experimentService := experiment.ExperimentService{}
+	result, err := experimentService.LintExperimentByUUID(uuid)
+	if err != nil {
+		c.Error(&c.Controller, err)
+		return
+	}
+	c.Success(&c.Controller, result)
+}
+
 func (c *ExperimentController) UpdateExperiment() {
 	uuid := c.Ctx.Input.Param(":uuid")
 	experimentService := experiment.ExperimentService{}
@@ -132,6 +160,10 @@ func (c *ExperimentController) StartExperiment() {
 		c.Error(&c.Controller, err)
 		return
 	}
+	if experimentGet.Draft {
+		c.Error(&c.Controller, fmt.Errorf("experiment is a draft, publish it before running"))
+		return
+	}
 	if experimentGet.ScheduleType != string(experimentModel.ManualMode) {
 		c.Error(&c.Controller, fmt.Errorf("manual mode is required to perform the walkthrough"))
 		return
@@ -159,6 +191,29 @@ func (c *ExperimentController) StopExperiment() {
 	c.Success(&c.Controller, "ok")
 }
 
+// KeepEditSession is called periodically by the editing page, the response tells who else is editing the experiment
+func (c *ExperimentController) KeepEditSession() {
+	uuid := c.Ctx.Input.Param(":uuid")
+	username := c.Ctx.Input.GetData("userName").(string)
+	experimentService := experiment.ExperimentService{}
+	if _, err := experimentService.GetExperimentByUUID(uuid); err != nil {
+		c.Error(&c.Controller, err)
+		return
+	}
+
+	c.Success(&c.Controller, EditSessionResponse{
+		Editors: experimentService.KeepEditSession(uuid, username),
+	})
+}
+
+func (c *ExperimentController) EndEditSession() {
+	uuid := c.Ctx.Input.Param(":uuid")
+	username := c.Ctx.Input.GetData("userName").(string)
+	experimentService := experiment.ExperimentService{}
+	experimentService.EndEditSession(uuid, username)
+	c.Success(&c.Controller, "ok")
+}
+
 func (c *ExperimentController) DeleteExperiment() {
 	uuid := c.Ctx.Input.Param(":uuid")
 	if uuid == "" {
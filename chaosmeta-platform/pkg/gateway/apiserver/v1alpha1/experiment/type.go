@@ -28,6 +28,10 @@ type GetExperimentResponse struct {
 	Experiment experiment.ExperimentGet `json:"experiments"`
 }
 
+type EditSessionResponse struct {
+	Editors []string `json:"editors"`
+}
+
 type ExperimentListResponse struct {
 	Page        int                        `json:"page"`
 	PageSize    int                        `json:"pageSize"`
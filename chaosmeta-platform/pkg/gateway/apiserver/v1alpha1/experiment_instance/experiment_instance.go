@@ -91,6 +91,7 @@ func (c *ExperimentInstanceController) GetExperimentInstanceNode() {
 		c.Error(&c.Controller, err)
 		return
 	}
+	experiment.MaskSensitiveArgsInstance(nodeDetail)
 	c.Success(&c.Controller, GetExperimentInstanceResponse{WorkflowNode: *nodeDetail})
 }
 
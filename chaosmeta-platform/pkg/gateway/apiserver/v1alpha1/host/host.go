@@ -0,0 +1,98 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package host
+
+import (
+	"chaosmeta-platform/pkg/gateway/apiserver/v1alpha1"
+	"chaosmeta-platform/pkg/service/host"
+	"chaosmeta-platform/util/log"
+	"context"
+	"encoding/json"
+	beego "github.com/beego/beego/v2/server/web"
+)
+
+type HostController struct {
+	v1alpha1.BeegoOutputController
+	beego.Controller
+}
+
+func (c *HostController) GetList() {
+	hostname := c.GetString("hostname")
+	ip := c.GetString("ip")
+	status := c.GetString("status")
+	orderBy := c.GetString("sort")
+	page, _ := c.GetInt("page", 1)
+	pageSize, _ := c.GetInt("page_size", 10)
+
+	hostService := host.HostService{}
+	total, hosts, err := hostService.Query(hostname, ip, status, orderBy, page, pageSize)
+	if err != nil {
+		c.Error(&c.Controller, err)
+		return
+	}
+	c.Success(&c.Controller, ListHostResponse{Total: total, Page: page, PageSize: pageSize, Hosts: hosts})
+}
+
+func (c *HostController) Get() {
+	id, err := c.GetInt(":id")
+	if err != nil {
+		c.Error(&c.Controller, err)
+		return
+	}
+
+	hostService := host.HostService{}
+	hostGet, err := hostService.Get(id)
+	if err != nil {
+		c.Error(&c.Controller, err)
+		return
+	}
+	c.Success(&c.Controller, hostGet)
+}
+
+func (c *HostController) Create() {
+	userName := c.Ctx.Input.GetData("userName").(string)
+	var requestBody host.HostCreate
+	if err := json.Unmarshal(c.Ctx.Input.RequestBody, &requestBody); err != nil {
+		c.Error(&c.Controller, err)
+		return
+	}
+
+	hostService := host.HostService{}
+	id, err := hostService.Create(context.Background(), userName, &requestBody)
+	if err != nil {
+		c.Error(&c.Controller, err)
+		return
+	}
+	c.Success(&c.Controller, CreateHostResponse{ID: id})
+	log.Info(userName, "register host:", requestBody.Hostname, requestBody.IP)
+}
+
+func (c *HostController) Delete() {
+	userName := c.Ctx.Input.GetData("userName").(string)
+	id, err := c.GetInt(":id")
+	if err != nil {
+		c.Error(&c.Controller, err)
+		return
+	}
+
+	hostService := host.HostService{}
+	if err := hostService.Delete(context.Background(), userName, id); err != nil {
+		c.Error(&c.Controller, err)
+		return
+	}
+	c.Success(&c.Controller, "ok")
+}
@@ -0,0 +1,103 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package namespace
+
+import (
+	"chaosmeta-platform/pkg/service/namespace"
+	"context"
+	"encoding/json"
+)
+
+func (c *NamespaceController) GetBudget() {
+	namespaceId, err := c.GetInt(":id")
+	if err != nil {
+		c.Error(&c.Controller, err)
+		return
+	}
+
+	namespace := &namespace.NamespaceService{}
+	budget, err := namespace.GetBudget(context.Background(), namespaceId)
+	if err != nil {
+		c.Error(&c.Controller, err)
+		return
+	}
+	c.Success(&c.Controller, GetBudgetResponse{Budget: budget})
+}
+
+func (c *NamespaceController) SetBudget() {
+	namespaceId, err := c.GetInt(":id")
+	if err != nil {
+		c.Error(&c.Controller, err)
+		return
+	}
+
+	var requestBody SetBudgetRequest
+	if err := json.Unmarshal(c.Ctx.Input.RequestBody, &requestBody); err != nil {
+		c.Error(&c.Controller, err)
+		return
+	}
+
+	username := c.Ctx.Input.GetData("userName").(string)
+	namespace := &namespace.NamespaceService{}
+	if err := namespace.SetBudget(context.Background(), username, namespaceId, requestBody.MaxRuns, requestBody.MaxInjectMinutes, requestBody.Thresholds); err != nil {
+		c.Error(&c.Controller, err)
+		return
+	}
+	c.Success(&c.Controller, "ok")
+}
+
+func (c *NamespaceController) DeleteBudget() {
+	namespaceId, err := c.GetInt(":id")
+	if err != nil {
+		c.Error(&c.Controller, err)
+		return
+	}
+
+	username := c.Ctx.Input.GetData("userName").(string)
+	namespace := &namespace.NamespaceService{}
+	if err := namespace.DeleteBudget(context.Background(), username, namespaceId); err != nil {
+		c.Error(&c.Controller, err)
+		return
+	}
+	c.Success(&c.Controller, "ok")
+}
+
+func (c *NamespaceController) GetConsumption() {
+	namespaceId, err := c.GetInt(":id")
+	if err != nil {
+		c.Error(&c.Controller, err)
+		return
+	}
+
+	namespace := &namespace.NamespaceService{}
+	consumption, err := namespace.GetConsumption(context.Background(), namespaceId)
+	if err != nil {
+		c.Error(&c.Controller, err)
+		return
+	}
+	c.Success(&c.Controller, consumption)
+}
+
+func (c *NamespaceController) ListConsumption() {
+	namespace := &namespace.NamespaceService{}
+	consumptions, err := namespace.ListConsumption(context.Background())
+	if err != nil {
+		c.Error(&c.Controller, err)
+		return
+	}
+	c.Success(&c.Controller, ListConsumptionResponse{Total: len(consumptions), Consumptions: consumptions})
+}
@@ -0,0 +1,42 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package namespace
+
+import (
+	"chaosmeta-platform/pkg/service/namespace"
+	"context"
+)
+
+func (c *NamespaceController) ListMirrors() {
+	namespace := &namespace.NamespaceService{}
+	mirrors, err := namespace.ListMirrors(context.Background())
+	if err != nil {
+		c.Error(&c.Controller, err)
+		return
+	}
+	c.Success(&c.Controller, ListMirrorsResponse{Total: len(mirrors), Mirrors: mirrors})
+}
+
+func (c *NamespaceController) SyncMirrors() {
+	username := c.Ctx.Input.GetData("userName").(string)
+	namespace := &namespace.NamespaceService{}
+	if err := namespace.TriggerSyncMirrors(context.Background(), username); err != nil {
+		c.Error(&c.Controller, err)
+		return
+	}
+	c.Success(&c.Controller, "ok")
+}
@@ -0,0 +1,66 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package namespace
+
+import (
+	"chaosmeta-platform/pkg/service/sandbox"
+	"context"
+	"encoding/json"
+)
+
+func (c *NamespaceController) CreateSandbox() {
+	var requestBody CreateSandboxRequest
+	if err := json.Unmarshal(c.Ctx.Input.RequestBody, &requestBody); err != nil {
+		c.Error(&c.Controller, err)
+		return
+	}
+
+	username := c.Ctx.Input.GetData("userName").(string)
+	sandboxService := &sandbox.SandboxService{}
+	sb, err := sandboxService.Create(context.Background(), username, requestBody.Name, requestBody.Description, requestBody.TTL)
+	if err != nil {
+		c.Error(&c.Controller, err)
+		return
+	}
+	c.Success(&c.Controller, CreateSandboxResponse{Sandbox: sb})
+}
+
+func (c *NamespaceController) ListSandboxes() {
+	sandboxService := &sandbox.SandboxService{}
+	sandboxes, err := sandboxService.List(context.Background())
+	if err != nil {
+		c.Error(&c.Controller, err)
+		return
+	}
+	c.Success(&c.Controller, ListSandboxesResponse{Total: len(sandboxes), Sandboxes: sandboxes})
+}
+
+func (c *NamespaceController) TeardownSandbox() {
+	namespaceId, err := c.GetInt(":id")
+	if err != nil {
+		c.Error(&c.Controller, err)
+		return
+	}
+
+	username := c.Ctx.Input.GetData("userName").(string)
+	sandboxService := &sandbox.SandboxService{}
+	if err := sandboxService.Teardown(context.Background(), username, namespaceId); err != nil {
+		c.Error(&c.Controller, err)
+		return
+	}
+	c.Success(&c.Controller, "ok")
+}
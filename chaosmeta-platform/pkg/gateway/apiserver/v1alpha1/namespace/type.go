@@ -142,3 +142,39 @@ type GetOverviewResponse struct {
 	TotalExperimentInstances  int64 `json:"total_experiment_instances"`
 	FailedExperimentInstances int64 `json:"failed_experiment_instances"`
 }
+
+type SetBudgetRequest struct {
+	MaxRuns          int   `json:"maxRuns"`
+	MaxInjectMinutes int   `json:"maxInjectMinutes"`
+	Thresholds       []int `json:"thresholds,omitempty"`
+}
+
+type GetBudgetResponse struct {
+	Budget *namespace.Budget `json:"budget,omitempty"`
+}
+
+type ListConsumptionResponse struct {
+	Total        int                                   `json:"total"`
+	Consumptions []*namespaceService.BudgetConsumption `json:"consumptions,omitempty"`
+}
+
+type ListMirrorsResponse struct {
+	Total   int                `json:"total"`
+	Mirrors []namespace.Mirror `json:"mirrors,omitempty"`
+}
+
+type CreateSandboxRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// TTL is a duration like 4h, the configured default ttl is used if empty
+	TTL string `json:"ttl"`
+}
+
+type CreateSandboxResponse struct {
+	Sandbox *namespace.Sandbox `json:"sandbox"`
+}
+
+type ListSandboxesResponse struct {
+	Total     int                 `json:"total"`
+	Sandboxes []namespace.Sandbox `json:"sandboxes,omitempty"`
+}
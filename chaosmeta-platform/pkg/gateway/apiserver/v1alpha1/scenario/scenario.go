@@ -0,0 +1,126 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package scenario
+
+import (
+	"chaosmeta-platform/pkg/gateway/apiserver/v1alpha1"
+	"chaosmeta-platform/pkg/service/experiment"
+	"chaosmeta-platform/pkg/service/scenario"
+	"chaosmeta-platform/pkg/service/user"
+	"encoding/json"
+	beego "github.com/beego/beego/v2/server/web"
+)
+
+type ScenarioController struct {
+	v1alpha1.BeegoOutputController
+	beego.Controller
+}
+
+func (c *ScenarioController) CreateScenario() {
+	username := c.Ctx.Input.GetData("userName").(string)
+	creatorId, err := user.GetIdByName(username)
+	if err != nil {
+		c.Error(&c.Controller, err)
+		return
+	}
+
+	var requestBody scenario.ScenarioCreate
+	if err := json.Unmarshal(c.Ctx.Input.RequestBody, &requestBody); err != nil {
+		c.Error(&c.Controller, err)
+		return
+	}
+
+	scenarioService := scenario.ScenarioService{}
+	id, err := scenarioService.Record(&requestBody, creatorId)
+	if err != nil {
+		c.Error(&c.Controller, err)
+		return
+	}
+	c.Success(&c.Controller, CreateScenarioResponse{Id: id})
+}
+
+func (c *ScenarioController) GetScenarioList() {
+	namespaceID, _ := c.GetInt("namespace_id")
+	name := c.GetString("name")
+	incident := c.GetString("incident")
+	orderBy := c.GetString("sort")
+	page, _ := c.GetInt("page", 1)
+	pageSize, _ := c.GetInt("page_size", 10)
+
+	scenarioService := scenario.ScenarioService{}
+	total, scenarios, err := scenarioService.Search(namespaceID, name, incident, orderBy, page, pageSize)
+	if err != nil {
+		c.Error(&c.Controller, err)
+		return
+	}
+	c.Success(&c.Controller, ScenarioListResponse{Page: page, PageSize: pageSize, Total: total, Scenarios: scenarios})
+}
+
+func (c *ScenarioController) GetScenarioDetail() {
+	id, err := c.GetInt(":id")
+	if err != nil {
+		c.Error(&c.Controller, err)
+		return
+	}
+
+	scenarioService := scenario.ScenarioService{}
+	scenarioGet, err := scenarioService.Get(id)
+	if err != nil {
+		c.Error(&c.Controller, err)
+		return
+	}
+	experiment.MaskSensitiveArgs(scenarioGet.WorkflowNodes)
+	c.Success(&c.Controller, scenarioGet)
+}
+
+func (c *ScenarioController) DeleteScenario() {
+	id, err := c.GetInt(":id")
+	if err != nil {
+		c.Error(&c.Controller, err)
+		return
+	}
+
+	scenarioService := scenario.ScenarioService{}
+	if err := scenarioService.Delete(id); err != nil {
+		c.Error(&c.Controller, err)
+		return
+	}
+	c.Success(&c.Controller, "ok")
+}
+
+func (c *ScenarioController) ReplayScenario() {
+	id, err := c.GetInt(":id")
+	if err != nil {
+		c.Error(&c.Controller, err)
+		return
+	}
+
+	username := c.Ctx.Input.GetData("userName").(string)
+	creatorId, err := user.GetIdByName(username)
+	if err != nil {
+		c.Error(&c.Controller, err)
+		return
+	}
+
+	scenarioService := scenario.ScenarioService{}
+	experimentUUID, err := scenarioService.Replay(id, creatorId, username)
+	if err != nil {
+		c.Error(&c.Controller, err)
+		return
+	}
+	c.Success(&c.Controller, ReplayScenarioResponse{ExperimentUUID: experimentUUID})
+}
@@ -0,0 +1,34 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package scenario
+
+import scenarioModel "chaosmeta-platform/pkg/models/scenario"
+
+type CreateScenarioResponse struct {
+	Id int `json:"id"`
+}
+
+type ScenarioListResponse struct {
+	Page      int                       `json:"page"`
+	PageSize  int                       `json:"pageSize"`
+	Total     int64                     `json:"total"`
+	Scenarios []*scenarioModel.Scenario `json:"scenarios,omitempty"`
+}
+
+type ReplayScenarioResponse struct {
+	ExperimentUUID string `json:"experiment_uuid"`
+}
@@ -0,0 +1,108 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package trigger
+
+import (
+	"chaosmeta-platform/pkg/gateway/apiserver/v1alpha1"
+	"chaosmeta-platform/pkg/service/trigger"
+	"context"
+	"encoding/json"
+	beego "github.com/beego/beego/v2/server/web"
+)
+
+type EventTriggerController struct {
+	v1alpha1.BeegoOutputController
+	beego.Controller
+}
+
+func (c *EventTriggerController) GetEventTriggerList() {
+	triggerService := trigger.EventTriggerService{}
+	triggers, err := triggerService.List()
+	if err != nil {
+		c.Error(&c.Controller, err)
+		return
+	}
+	c.Success(&c.Controller, EventTriggerListResponse{Triggers: triggers})
+}
+
+func (c *EventTriggerController) CreateEventTrigger() {
+	username := c.Ctx.Input.GetData("userName").(string)
+	var requestBody trigger.EventTriggerCreate
+	if err := json.Unmarshal(c.Ctx.Input.RequestBody, &requestBody); err != nil {
+		c.Error(&c.Controller, err)
+		return
+	}
+
+	triggerService := trigger.EventTriggerService{}
+	id, err := triggerService.Create(context.Background(), username, &requestBody)
+	if err != nil {
+		c.Error(&c.Controller, err)
+		return
+	}
+	c.Success(&c.Controller, CreateEventTriggerResponse{Id: id})
+}
+
+func (c *EventTriggerController) UpdateEventTrigger() {
+	id, err := c.GetInt(":id")
+	if err != nil {
+		c.Error(&c.Controller, err)
+		return
+	}
+	username := c.Ctx.Input.GetData("userName").(string)
+	var requestBody trigger.EventTriggerCreate
+	if err := json.Unmarshal(c.Ctx.Input.RequestBody, &requestBody); err != nil {
+		c.Error(&c.Controller, err)
+		return
+	}
+
+	triggerService := trigger.EventTriggerService{}
+	if err := triggerService.Update(context.Background(), username, id, &requestBody); err != nil {
+		c.Error(&c.Controller, err)
+		return
+	}
+	c.Success(&c.Controller, "ok")
+}
+
+func (c *EventTriggerController) DeleteEventTrigger() {
+	id, err := c.GetInt(":id")
+	if err != nil {
+		c.Error(&c.Controller, err)
+		return
+	}
+	username := c.Ctx.Input.GetData("userName").(string)
+
+	triggerService := trigger.EventTriggerService{}
+	if err := triggerService.Delete(context.Background(), username, id); err != nil {
+		c.Error(&c.Controller, err)
+		return
+	}
+	c.Success(&c.Controller, "ok")
+}
+
+// ReceiveEvent is the endpoint of argo events http triggers, the request body is the event payload
+func (c *EventTriggerController) ReceiveEvent() {
+	topic := c.Ctx.Input.Param(":topic")
+	username := c.Ctx.Input.GetData("userName").(string)
+
+	triggerService := trigger.EventTriggerService{}
+	started, err := triggerService.Receive(context.Background(), username, topic, c.Ctx.Input.RequestBody)
+	if err != nil {
+		c.Error(&c.Controller, err)
+		return
+	}
+	c.Success(&c.Controller, ReceiveEventResponse{Experiments: started})
+}
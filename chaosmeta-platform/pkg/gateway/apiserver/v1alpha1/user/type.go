@@ -74,6 +74,28 @@ type UserUpdateRoleRequest struct {
 	Role    string `json:"role"`
 }
 
+type UsersActivateRequest struct {
+	UserIDs []int `json:"user_ids"`
+}
+
+type UsersDisableRequest UsersActivateRequest
+
+type UserActivity struct {
+	User
+	Disabled       bool      `json:"disabled"`
+	DisabledReason string    `json:"disabledReason"`
+	LastLoginTime  time.Time `json:"lastLoginTime"`
+	LastActiveTime time.Time `json:"lastActiveTime"`
+	InactiveDays   int       `json:"inactiveDays"`
+}
+
+type UserActivityListResponse struct {
+	Page     int             `json:"page"`
+	PageSize int             `json:"pageSize"`
+	Total    int64           `json:"total"`
+	Users    []*UserActivity `json:"users"`
+}
+
 type NameSpaceListResponse struct {
 	Page       int                      `json:"page"`
 	PageSize   int                      `json:"pageSize"`
@@ -26,6 +26,7 @@ import (
 	beego "github.com/beego/beego/v2/server/web"
 	"strconv"
 	"strings"
+	"time"
 )
 
 type UserController struct {
@@ -244,6 +245,89 @@ func (c *UserController) UpdateListRole() {
 	c.Success(&c.Controller, "ok")
 }
 
+func (c *UserController) GetActivityList() {
+	userName := c.Ctx.Input.GetData("userName").(string)
+	sort := c.GetString("sort")
+	name := c.GetString("name")
+	inactiveDays, _ := c.GetInt("inactive_days", 0)
+	page, _ := c.GetInt("page", 1)
+	pageSize, _ := c.GetInt("page_size", 10)
+
+	var disabled *bool
+	if c.GetString("disabled") != "" {
+		disabledGet, err := c.GetBool("disabled")
+		if err != nil {
+			c.Error(&c.Controller, err)
+			return
+		}
+		disabled = &disabledGet
+	}
+
+	a := &user.UserService{}
+	total, userList, err := a.GetActivityList(context.Background(), userName, name, inactiveDays, disabled, sort, page, pageSize)
+	if err != nil {
+		c.Error(&c.Controller, err)
+		return
+	}
+
+	now := time.Now()
+	userActivityListResponse := UserActivityListResponse{Total: total, Page: page, PageSize: pageSize}
+	for _, userGet := range userList {
+		userActivity := &UserActivity{
+			User: User{
+				ID:         userGet.ID,
+				Name:       userGet.Email,
+				Role:       userGet.Role,
+				CreateTime: userGet.CreateTime,
+				UpdateTime: userGet.UpdateTime,
+			},
+			Disabled:       userGet.Disabled,
+			DisabledReason: userGet.DisabledReason,
+			LastLoginTime:  userGet.LastLoginTime,
+			LastActiveTime: userGet.LastActiveTime,
+		}
+		if !userGet.LastActiveTime.IsZero() {
+			userActivity.InactiveDays = int(now.Sub(userGet.LastActiveTime).Hours() / 24)
+		}
+		userActivityListResponse.Users = append(userActivityListResponse.Users, userActivity)
+	}
+	c.Success(&c.Controller, userActivityListResponse)
+}
+
+func (c *UserController) ActivateList() {
+	userName := c.Ctx.Input.GetData("userName").(string)
+
+	var usersActivateRequest UsersActivateRequest
+	if err := json.Unmarshal(c.Ctx.Input.RequestBody, &usersActivateRequest); err != nil {
+		c.Error(&c.Controller, err)
+		return
+	}
+
+	a := &user.UserService{}
+	if err := a.ActivateList(context.Background(), userName, usersActivateRequest.UserIDs); err != nil {
+		c.Error(&c.Controller, err)
+		return
+	}
+	c.Success(&c.Controller, "ok")
+}
+
+func (c *UserController) DisableList() {
+	userName := c.Ctx.Input.GetData("userName").(string)
+
+	var usersDisableRequest UsersDisableRequest
+	if err := json.Unmarshal(c.Ctx.Input.RequestBody, &usersDisableRequest); err != nil {
+		c.Error(&c.Controller, err)
+		return
+	}
+
+	a := &user.UserService{}
+	if err := a.DisableList(context.Background(), userName, usersDisableRequest.UserIDs); err != nil {
+		c.Error(&c.Controller, err)
+		return
+	}
+	c.Success(&c.Controller, "ok")
+}
+
 // 获取用户下的命名空间列表
 func (c *UserController) GetNamespaceList() {
 	userName := c.Ctx.Input.GetData("userName").(string)
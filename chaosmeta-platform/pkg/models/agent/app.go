@@ -21,9 +21,21 @@ import (
 	"context"
 )
 
+const (
+	AppSourceManual    = "manual"
+	AppSourceDiscovery = "discovery"
+)
+
 type App struct {
-	ID   int    `json:"id" orm:"pk;auto;column(id)"`
-	Name string `json:"name" orm:"unique;index;column(name);size(255)"`
+	ID          int    `json:"id" orm:"pk;auto;column(id)"`
+	Name        string `json:"name" orm:"unique;index;column(name);size(255)"`
+	ClusterID   int    `json:"clusterId" orm:"column(cluster_id);index"`
+	Namespace   string `json:"namespace" orm:"column(namespace);size(255);index"`
+	Owner       string `json:"owner" orm:"column(owner);size(255)"`
+	SelectLabel string `json:"selectLabel" orm:"column(select_label);size(1024)"`
+	Source      string `json:"source" orm:"column(source);size(32);default(manual)"`
+	// DiscoveryKey is the key of the discovered application adopted as this one, the name may be overridden in adoption
+	DiscoveryKey string `json:"discoveryKey,omitempty" orm:"column(discovery_key);size(512);index"`
 	models.BaseTimeModel
 }
 
@@ -43,25 +55,49 @@ func GetAppById(ctx context.Context, a *App) error {
 	return models.GetORM().Read(a)
 }
 
-//func DeleteUsersByIdList(ctx context.Context, userId []int) error {
-//	user := User{}
-//	querySeter := models.GetORM().QueryTable(user.TableName())
-//	userQuery, err := models.NewDataSelectQuery(&querySeter)
-//	if err != nil {
-//		return err
-//	}
-//	userQuery.Filter("id", models.IN, false, userId)
-//	_, err = userQuery.Update(orm.Params{
-//		"is_deleted": true,
-//	})
-//	return err
-//}
-//
-//func UpdateUser(ctx context.Context, u *User) error {
-//	suc, err := models.GetORM().Update(u)
-//	if suc == 0 {
-//		return fmt.Errorf("record[email: %s] not found", u.Email)
-//	}
-//
-//	return err
-//}
+func DeleteApp(ctx context.Context, id int) error {
+	if _, err := models.GetORM().Delete(&App{ID: id}); err != nil {
+		return err
+	}
+	return DeleteAppWorkloadsByAppId(ctx, id)
+}
+
+func QueryApps(ctx context.Context, name string, clusterId int, namespace, orderBy string, page, pageSize int) (int64, []App, error) {
+	a, apps := App{}, new([]App)
+	querySeter := models.GetORM().QueryTable(a.TableName())
+	appQuery, err := models.NewDataSelectQuery(&querySeter)
+	if err != nil {
+		return 0, nil, err
+	}
+	if len(name) > 0 {
+		appQuery.Filter("name", models.CONTAINS, true, name)
+	}
+	if clusterId > 0 {
+		appQuery.Filter("cluster_id", models.NEGLECT, false, clusterId)
+	}
+	if len(namespace) > 0 {
+		appQuery.Filter("namespace", models.NEGLECT, false, namespace)
+	}
+
+	var totalCount int64
+	totalCount, err = appQuery.GetOamQuerySeter().Count()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	orderByList := []string{"id"}
+	if len(orderBy) > 0 {
+		if err := models.CheckOrderBy(orderBy, "name", "cluster_id", "namespace", "source", "create_time", "update_time"); err != nil {
+			return 0, nil, err
+		}
+		orderByList = append(orderByList, orderBy)
+	}
+	appQuery.OrderBy(orderByList...)
+
+	if err := appQuery.Limit(pageSize, (page-1)*pageSize); err != nil {
+		return 0, nil, err
+	}
+
+	_, err = appQuery.GetOamQuerySeter().All(apps)
+	return totalCount, *apps, err
+}
@@ -0,0 +1,77 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package agent
+
+import (
+	models "chaosmeta-platform/pkg/models/common"
+	"context"
+	"github.com/beego/beego/v2/client/orm"
+)
+
+// AppWorkload is a kubernetes workload(deployment, statefulset) which belongs to an App
+type AppWorkload struct {
+	ID        int    `json:"id" orm:"pk;auto;column(id)"`
+	AppID     int    `json:"appId" orm:"column(app_id);index"`
+	Kind      string `json:"kind" orm:"column(kind);size(32)"`
+	Namespace string `json:"namespace" orm:"column(namespace);size(255)"`
+	Name      string `json:"name" orm:"column(name);size(255)"`
+	Replicas  int32  `json:"replicas" orm:"column(replicas)"`
+	models.BaseTimeModel
+}
+
+func (a *AppWorkload) TableName() string {
+	return "agent_app_workload"
+}
+
+func (a *AppWorkload) TableUnique() [][]string {
+	return [][]string{{"app_id", "kind", "namespace", "name"}}
+}
+
+func InsertAppWorkloads(ctx context.Context, workloads []*AppWorkload) error {
+	if len(workloads) == 0 {
+		return nil
+	}
+	_, err := models.GetORM().InsertMulti(len(workloads), workloads)
+	return err
+}
+
+func ListAppWorkloadsByAppId(ctx context.Context, appId int) ([]AppWorkload, error) {
+	a, workloads := AppWorkload{}, new([]AppWorkload)
+	querySeter := models.GetORM().QueryTable(a.TableName())
+	workloadQuery, err := models.NewDataSelectQuery(&querySeter)
+	if err != nil {
+		return nil, err
+	}
+	workloadQuery.Filter("app_id", models.NEGLECT, false, appId)
+	_, err = workloadQuery.GetOamQuerySeter().All(workloads)
+	if err == orm.ErrNoRows {
+		return nil, nil
+	}
+	return *workloads, err
+}
+
+func DeleteAppWorkloadsByAppId(ctx context.Context, appId int) error {
+	a := AppWorkload{}
+	querySeter := models.GetORM().QueryTable(a.TableName())
+	workloadQuery, err := models.NewDataSelectQuery(&querySeter)
+	if err != nil {
+		return err
+	}
+	workloadQuery.Filter("app_id", models.NEGLECT, false, appId)
+	_, err = workloadQuery.Delete()
+	return err
+}
@@ -20,6 +20,7 @@ import (
 	"errors"
 	"fmt"
 	"github.com/beego/beego/v2/client/orm"
+	"strings"
 )
 
 type OperatorType int
@@ -104,6 +105,18 @@ func (d *DataSelectQuery) OrderBy(exprs ...string) {
 	d.OamQuerySeter = d.OamQuerySeter.OrderBy(exprs...)
 }
 
+// CheckOrderBy makes sure the order expression from the request is one of the columns, with an optional "-" for descending.
+// The orm panics on an unknown column and the expression must not reach the sql unchecked
+func CheckOrderBy(orderBy string, columns ...string) error {
+	column := strings.TrimPrefix(orderBy, "-")
+	for _, c := range columns {
+		if column == c {
+			return nil
+		}
+	}
+	return fmt.Errorf("sort by %s is not supported, only support: %s", orderBy, strings.Join(columns, ", "))
+}
+
 func (d *DataSelectQuery) GetOamQuerySeter() orm.QuerySeter {
 	return d.OamQuerySeter
 }
@@ -0,0 +1,43 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package models
+
+import (
+	"testing"
+)
+
+func TestCheckOrderBy(t *testing.T) {
+	columns := []string{"email", "last_active_time"}
+	tests := []struct {
+		name    string
+		orderBy string
+		wantErr bool
+	}{
+		{name: "ascending", orderBy: "email", wantErr: false},
+		{name: "descending", orderBy: "-last_active_time", wantErr: false},
+		{name: "unknown column", orderBy: "password", wantErr: true},
+		{name: "sql expression", orderBy: "email; drop table user", wantErr: true},
+		{name: "relation", orderBy: "email__id", wantErr: true},
+		{name: "empty", orderBy: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := CheckOrderBy(tt.orderBy, columns...); (err != nil) != tt.wantErr {
+				t.Errorf("CheckOrderBy() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
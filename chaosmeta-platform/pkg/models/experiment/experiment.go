@@ -36,6 +36,7 @@ const (
 
 	ToBeExecuted = ExperimentStatus(0) //待执行
 	Executed     = ExperimentStatus(1)
+	Draft        = ExperimentStatus(2) //草稿, 不会被调度执行
 
 	ManualMode = ScheduleType("manual") //手动模式
 	OnceMode   = ScheduleType("once")   //自动模式
@@ -47,6 +48,8 @@ const (
 	TimeLayout = "2006-01-02 15:04:05"
 )
 
+var ErrConcurrentModification = errors.New("Concurrent modification detected")
+
 type Experiment struct {
 	UUID         string           `json:"uuid,omitempty" orm:"column(uuid);size(128);pk"`
 	Name         string           `json:"name" orm:"index;column(name);size(255)"`
@@ -58,6 +61,8 @@ type Experiment struct {
 	NextExec     time.Time        `json:"next_exec,omitempty" orm:"null;column(next_exec);type(datetime)"`
 	Status       ExperimentStatus `json:"-" orm:"index;column(status);type:tinyint(1)"`
 	LastInstance string           `json:"last_instance" orm:"column(last_instance);size(64)"`
+	Mutex        string           `json:"mutex" orm:"column(mutex);size(128);index"`
+	Rollback     string           `json:"rollback" orm:"column(rollback);size(255)"`
 	Version      int              `json:"-" orm:"column(version);default(0);index"`
 	models.BaseTimeModel
 }
@@ -72,32 +77,59 @@ func CreateExperiment(experiment *Experiment) error {
 }
 
 func UpdateExperiment(experiment *Experiment) error {
+	return UpdateExperimentAndChildren(experiment, nil)
+}
+
+// ExperimentChildren are the rows rewritten with the experiment, the labels are kept if LabelIDs is empty
+type ExperimentChildren struct {
+	LabelIDs      []int
+	WorkflowNodes []*WorkflowNode
+	// ArgsValues are keyed by the uuid of the workflow node
+	ArgsValues    map[string][]*ArgsValue
+	FaultRanges   []*FaultRange
+	FlowRanges    []*FlowRange
+	MeasureRanges []*MeasureRange
+}
+
+// UpdateExperimentAndChildren updates the experiment and rewrites its children in one transaction, which is started
+// by checking and increasing the version, so the children are never rewritten by a stale update
+func UpdateExperimentAndChildren(experiment *Experiment, children *ExperimentChildren) error {
 	o := models.GetORM()
 	tx, err := o.Begin()
 	if err != nil {
 		return err
 	}
 
-	existing := Experiment{UUID: experiment.UUID}
-	err = tx.Read(&existing)
+	// the version is checked and increased by one statement, so only one of the concurrent updates affects the row
+	num, err := tx.QueryTable(new(Experiment).TableName()).Filter("uuid", experiment.UUID).Filter("version", experiment.Version).
+		Update(orm.Params{"version": experiment.Version + 1})
 	if err != nil {
 		tx.Rollback()
 		return err
 	}
 
-	if experiment.Version != existing.Version {
+	if num != 1 {
 		tx.Rollback()
-		return errors.New("Concurrent modification detected")
+		return ErrConcurrentModification
 	}
 
-	experiment.Version = existing.Version + 1
+	if children != nil {
+		if err := rewriteChildren(tx, experiment.UUID, children); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	experiment.Version++
 	if _, err = tx.Update(experiment); err != nil {
 		tx.Rollback()
+		experiment.Version--
 		return err
 	}
 
 	if err = tx.Commit(); err != nil {
 		tx.Rollback()
+		experiment.Version--
 		return err
 	}
 
@@ -105,6 +137,69 @@ func UpdateExperiment(experiment *Experiment) error {
 
 }
 
+func rewriteChildren(tx orm.TxOrmer, experimentUUID string, children *ExperimentChildren) error {
+	if len(children.LabelIDs) > 0 {
+		if _, err := tx.QueryTable(new(LabelExperiment).TableName()).Filter("experiment_uuid", experimentUUID).Delete(); err != nil {
+			return err
+		}
+		for _, id := range children.LabelIDs {
+			if _, err := tx.Insert(&LabelExperiment{LabelID: id, ExperimentUUID: experimentUUID}); err != nil {
+				return err
+			}
+		}
+	}
+
+	if _, err := tx.QueryTable(new(WorkflowNode).TableName()).Filter("experiment_uuid", experimentUUID).Delete(); err != nil {
+		return err
+	}
+	for _, node := range children.WorkflowNodes {
+		if _, err := tx.Insert(node); err != nil {
+			return err
+		}
+	}
+
+	for nodeUUID, argsValues := range children.ArgsValues {
+		if _, err := tx.QueryTable(new(ArgsValue).TableName()).Filter("workflow_node_uuid", nodeUUID).Delete(); err != nil {
+			return err
+		}
+		for _, argsValue := range argsValues {
+			argsValue.WorkflowNodeUUID = nodeUUID
+			if _, err := tx.Insert(argsValue); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, faultRange := range children.FaultRanges {
+		if _, err := tx.QueryTable(new(FaultRange).TableName()).Filter("workflow_node_instance_uuid", faultRange.WorkflowNodeInstanceUUID).Delete(); err != nil {
+			return err
+		}
+		if _, err := tx.Insert(faultRange); err != nil {
+			return err
+		}
+	}
+
+	for _, flowRange := range children.FlowRanges {
+		if _, err := tx.QueryTable(new(FlowRange).TableName()).Filter("workflow_node_instance_uuid", flowRange.WorkflowNodeInstanceUUID).Delete(); err != nil {
+			return err
+		}
+		if _, err := tx.Insert(flowRange); err != nil {
+			return err
+		}
+	}
+
+	for _, measureRange := range children.MeasureRanges {
+		if _, err := tx.QueryTable(new(MeasureRange).TableName()).Filter("workflow_node_instance_uuid", measureRange.WorkflowNodeInstanceUUID).Delete(); err != nil {
+			return err
+		}
+		if _, err := tx.Insert(measureRange); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func GetExperimentByUUID(uuid string) (*Experiment, error) {
 	var exp Experiment
 	err := models.GetORM().QueryTable(new(Experiment).TableName()).Filter("uuid", uuid).One(&exp)
@@ -123,6 +218,15 @@ func DeleteExperimentByUUID(uuid string) error {
 	return err
 }
 
+func ListExperimentsByNamespaceId(namespaceId int) ([]*Experiment, error) {
+	var experiments []*Experiment
+	_, err := models.GetORM().QueryTable(new(Experiment).TableName()).Filter("namespace_id", namespaceId).OrderBy("create_time").All(&experiments)
+	if err == orm.ErrNoRows {
+		return nil, nil
+	}
+	return experiments, err
+}
+
 func ListExperimentsByScheduleTypeAndStatus(scheduleType ScheduleType, experimentStatus ExperimentStatus) (int64, []*Experiment, error) {
 	o := models.GetORM()
 	experiments := []*Experiment{}
@@ -52,6 +52,8 @@ type ExperimentInstance struct {
 	Creator        int    `json:"creator" orm:"index;column(creator)"`
 	Status         string `json:"status" orm:"column(status);size(32);index"`
 	Message        string `json:"message" orm:"column(message);size(1024)"`
+	Mutex          string `json:"mutex" orm:"column(mutex);size(128);index"`
+	PostAction     string `json:"post_action" orm:"column(post_action);size(1024)"`
 	Version        int    `json:"-" orm:"column(version);default(0);index"`
 	models.BaseTimeModel
 }
@@ -113,6 +115,15 @@ func UpdateExperimentInstanceStatus(uuid string, status, message string) error {
 	return UpdateExperimentInstance(experimentInstance)
 }
 
+func UpdateExperimentInstancePostAction(uuid string, postAction string) error {
+	experimentInstance, err := GetExperimentInstanceByUUID(uuid)
+	if err != nil || experimentInstance == nil {
+		return fmt.Errorf("error:%v", err)
+	}
+	experimentInstance.PostAction = postAction
+	return UpdateExperimentInstance(experimentInstance)
+}
+
 func GetExperimentInstanceByUUID(uuid string) (*ExperimentInstance, error) {
 	var exp ExperimentInstance
 	err := models.GetORM().QueryTable(new(ExperimentInstance).TableName()).Filter("uuid", uuid).One(&exp)
@@ -125,6 +136,43 @@ func GetExperimentInstanceByUUID(uuid string) (*ExperimentInstance, error) {
 	return &exp, nil
 }
 
+// ListExperimentInstancesByMutex returns the instances holding or waiting for the mutex in creation order
+func ListExperimentInstancesByMutex(mutex string, status []string) ([]*ExperimentInstance, error) {
+	var experiments []*ExperimentInstance
+	qs := models.GetORM().QueryTable(new(ExperimentInstance).TableName()).Filter("mutex", mutex)
+	if len(status) > 0 {
+		qs = qs.Filter("status__in", status)
+	}
+	_, err := qs.OrderBy("create_time", "uuid").All(&experiments)
+	return experiments, err
+}
+
+// ListMutexExperimentInstancesByStatus returns the instances with a mutex in creation order
+func ListMutexExperimentInstancesByStatus(status string) ([]*ExperimentInstance, error) {
+	var experiments []*ExperimentInstance
+	_, err := models.GetORM().QueryTable(new(ExperimentInstance).TableName()).Filter("status", status).Exclude("mutex", "").
+		OrderBy("create_time", "uuid").All(&experiments)
+	return experiments, err
+}
+
+func ListExperimentInstancesByNamespaceId(namespaceId int, status []string) ([]*ExperimentInstance, error) {
+	var experiments []*ExperimentInstance
+	qs := models.GetORM().QueryTable(new(ExperimentInstance).TableName()).Filter("namespace_id", namespaceId)
+	if len(status) > 0 {
+		qs = qs.Filter("status__in", status)
+	}
+	_, err := qs.OrderBy("create_time", "uuid").All(&experiments)
+	return experiments, err
+}
+
+// ListExperimentInstancesByStatusList returns the instances in any of the status in creation order
+func ListExperimentInstancesByStatusList(status []string) ([]*ExperimentInstance, error) {
+	var experiments []*ExperimentInstance
+	_, err := models.GetORM().QueryTable(new(ExperimentInstance).TableName()).Filter("status__in", status).
+		OrderBy("create_time", "uuid").All(&experiments)
+	return experiments, err
+}
+
 func DeleteExperimentInstanceByUUID(uuid string) error {
 	experiment := &ExperimentInstance{UUID: uuid}
 	_, err := models.GetORM().Delete(experiment)
@@ -298,3 +346,33 @@ func CountExperimentInstances(namespaceID int, experimentUUID string, status str
 	}
 	return total, err
 }
+
+// ListFaultDurationsSince returns the durations of the fault nodes of the experiment instances created in the namespace since start
+func ListFaultDurationsSince(namespaceID int, start time.Time) ([]string, error) {
+	var (
+		values    orm.ParamsList
+		durations []string
+	)
+	sql := "SELECT w.duration FROM " + new(WorkflowNodeInstance).TableName() + " w INNER JOIN " + new(ExperimentInstance).TableName() +
+		" e ON w.experiment_instance_uuid = e.uuid WHERE e.namespace_id = ? AND e.create_time >= ? AND w.exec_type = ?"
+	if _, err := models.GetORM().Raw(sql, namespaceID, start.Format(TimeLayout), "fault").ValuesFlat(&values); err != nil {
+		if err == orm.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	for _, v := range values {
+		if d, ok := v.(string); ok {
+			durations = append(durations, d)
+		}
+	}
+	return durations, nil
+}
+
+func CountExperimentInstancesSince(namespaceID int, start time.Time) (int64, error) {
+	total, err := models.GetORM().QueryTable(new(ExperimentInstance).TableName()).Filter("namespace_id", namespaceID).Filter("create_time__gte", start.Format(TimeLayout)).Count()
+	if err == orm.ErrNoRows {
+		return 0, nil
+	}
+	return total, err
+}
@@ -20,6 +20,7 @@ import (
 	models "chaosmeta-platform/pkg/models/common"
 	"errors"
 	"github.com/beego/beego/v2/client/orm"
+	"time"
 )
 
 type WorkflowNodeInstance struct {
@@ -37,6 +38,8 @@ type WorkflowNodeInstance struct {
 	Status                 string `json:"status" orm:"column(status);size(32);default(to_be_executed);index"`
 	Message                string `json:"message" orm:"column(message);type(text)"`
 	Version                int    `json:"-" orm:"column(version);default(0);index"`
+	// StartTime is when the node becomes running, for the progress of the experiment instance
+	StartTime time.Time `json:"start_time" orm:"column(start_time);null;type(datetime)"`
 	models.BaseTimeModel
 }
 
@@ -94,6 +97,9 @@ func UpdateWorkflowNodeInstanceStatus(uuid string, status, message string) error
 	if err != nil {
 		return err
 	}
+	if status == string(Running) && nodeInstance.StartTime.IsZero() {
+		nodeInstance.StartTime = time.Now()
+	}
 	nodeInstance.Status = status
 	if message != "" {
 		nodeInstance.Message = message
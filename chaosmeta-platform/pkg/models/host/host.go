@@ -0,0 +1,135 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package host
+
+import (
+	models "chaosmeta-platform/pkg/models/common"
+	"errors"
+	"github.com/beego/beego/v2/client/orm"
+	"time"
+)
+
+const (
+	StatusOnline  = "online"
+	StatusOffline = "offline"
+)
+
+// Host is a machine of the standalone mode inventory, experiments are executed by the chaosmetad server on it.
+// Labels is in the same "key:value,key:value" format as the target label of a fault node
+type Host struct {
+	Id            int       `json:"id" orm:"pk;auto;column(id)"`
+	Hostname      string    `json:"hostname" orm:"column(hostname);size(255);unique"`
+	IP            string    `json:"ip" orm:"column(ip);size(64);index"`
+	SSHPort       int       `json:"sshPort" orm:"column(ssh_port);default(22)"`
+	SSHUser       string    `json:"sshUser" orm:"column(ssh_user);size(64)"`
+	AgentPort     int       `json:"agentPort" orm:"column(agent_port);default(29595)"`
+	Labels        string    `json:"labels" orm:"column(labels);size(1024)"`
+	Status        string    `json:"status" orm:"column(status);size(32);index"`
+	AgentVersion  string    `json:"agentVersion" orm:"column(agent_version);size(32)"`
+	LastCheckTime time.Time `json:"lastCheckTime" orm:"column(last_check_time);type(datetime);null"`
+	models.BaseTimeModel
+}
+
+func (h *Host) TableName() string {
+	return "host"
+}
+
+func InsertOrUpdateHost(h *Host) error {
+	if h == nil {
+		return errors.New("host is nil")
+	}
+	existed := Host{Hostname: h.Hostname}
+	if err := models.GetORM().Read(&existed, "hostname"); err != nil {
+		if err != orm.ErrNoRows {
+			return err
+		}
+		_, err = models.GetORM().Insert(h)
+		return err
+	}
+
+	h.Id = existed.Id
+	_, err := models.GetORM().Update(h, "ip", "ssh_port", "ssh_user", "agent_port", "labels", "update_time")
+	return err
+}
+
+func GetHostById(id int) (*Host, error) {
+	h := &Host{Id: id}
+	if err := models.GetORM().Read(h); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+func UpdateHostStatus(id int, status, agentVersion string, checkTime time.Time) error {
+	_, err := models.GetORM().QueryTable(new(Host).TableName()).Filter("id", id).Update(orm.Params{
+		"status":          status,
+		"agent_version":   agentVersion,
+		"last_check_time": checkTime,
+	})
+	return err
+}
+
+func DeleteHostById(id int) error {
+	_, err := models.GetORM().Delete(&Host{Id: id})
+	return err
+}
+
+func ListAllHosts() ([]Host, error) {
+	var hosts []Host
+	if _, err := models.GetORM().QueryTable(new(Host).TableName()).OrderBy("id").All(&hosts); err != nil && err != orm.ErrNoRows {
+		return nil, err
+	}
+	return hosts, nil
+}
+
+func QueryHosts(hostname, ip, status, orderBy string, page, pageSize int) (int64, []Host, error) {
+	var hosts []Host
+	querySeter := models.GetORM().QueryTable(new(Host).TableName())
+	hostQuery, err := models.NewDataSelectQuery(&querySeter)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if hostname != "" {
+		hostQuery.Filter("hostname", models.CONTAINS, true, hostname)
+	}
+	if ip != "" {
+		hostQuery.Filter("ip", models.CONTAINS, false, ip)
+	}
+	if status != "" {
+		hostQuery.Filter("status", models.NEGLECT, false, status)
+	}
+
+	totalCount, err := hostQuery.GetOamQuerySeter().Count()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if orderBy == "" {
+		orderBy = "id"
+	}
+	hostQuery.OrderBy(orderBy)
+	if err := hostQuery.Limit(pageSize, (page-1)*pageSize); err != nil {
+		return 0, nil, err
+	}
+
+	_, err = hostQuery.GetOamQuerySeter().All(&hosts)
+	if err == orm.ErrNoRows {
+		return 0, nil, nil
+	}
+	return totalCount, hosts, err
+}
@@ -0,0 +1,88 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package namespace
+
+import (
+	"chaosmeta-platform/pkg/models/common"
+	"context"
+	"errors"
+	"github.com/beego/beego/v2/client/orm"
+)
+
+// Budget is the monthly soft limit of a namespace, exceeding it only triggers notifications and never blocks experiments
+type Budget struct {
+	Id               int    `json:"id" orm:"pk;auto;column(id)"`
+	NamespaceId      int    `json:"namespaceId" orm:"column(namespace_id);unique"`
+	MaxRuns          int    `json:"maxRuns" orm:"column(max_runs);default(0)"`
+	MaxInjectMinutes int    `json:"maxInjectMinutes" orm:"column(max_inject_minutes);default(0)"`
+	Thresholds       string `json:"thresholds" orm:"column(thresholds);size(64)"`
+	NotifiedMonth    string `json:"notifiedMonth" orm:"column(notified_month);size(16)"`
+	NotifiedLevel    int    `json:"notifiedLevel" orm:"column(notified_level);default(0)"`
+	Creator          string `json:"creator" orm:"column(creator);size(255)"`
+	models.BaseTimeModel
+}
+
+func (b *Budget) TableName() string {
+	return "namespace_budget"
+}
+
+func GetBudgetByNamespaceId(ctx context.Context, budget *Budget) error {
+	if budget == nil {
+		return errors.New("budget is nil")
+	}
+	return models.GetORM().Read(budget, "namespace_id")
+}
+
+func InsertOrUpdateBudget(ctx context.Context, budget *Budget) error {
+	if budget == nil {
+		return errors.New("budget is nil")
+	}
+	existed := Budget{NamespaceId: budget.NamespaceId}
+	if err := GetBudgetByNamespaceId(ctx, &existed); err != nil {
+		if err != orm.ErrNoRows {
+			return err
+		}
+		_, err = models.GetORM().Insert(budget)
+		return err
+	}
+
+	budget.Id = existed.Id
+	_, err := models.GetORM().Update(budget, "max_runs", "max_inject_minutes", "thresholds", "notified_month", "notified_level", "creator", "update_time")
+	return err
+}
+
+func UpdateBudgetNotified(ctx context.Context, id int, month string, level int) error {
+	_, err := models.GetORM().QueryTable(new(Budget).TableName()).Filter("id", id).Update(orm.Params{
+		"notified_month": month,
+		"notified_level": level,
+	})
+	return err
+}
+
+func DeleteBudgetByNamespaceId(ctx context.Context, namespaceId int) error {
+	_, err := models.GetORM().QueryTable(new(Budget).TableName()).Filter("namespace_id", namespaceId).Delete()
+	return err
+}
+
+func ListBudgets(ctx context.Context) ([]Budget, error) {
+	var budgets []Budget
+	_, err := models.GetORM().QueryTable(new(Budget).TableName()).OrderBy("namespace_id").All(&budgets)
+	if err == orm.ErrNoRows {
+		return nil, nil
+	}
+	return budgets, err
+}
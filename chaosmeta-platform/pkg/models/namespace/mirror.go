@@ -0,0 +1,83 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package namespace
+
+import (
+	"chaosmeta-platform/pkg/models/common"
+	"context"
+	"errors"
+	"github.com/beego/beego/v2/client/orm"
+)
+
+// Mirror records that a platform namespace is synced from a kubernetes namespace of a cluster
+type Mirror struct {
+	Id            int    `json:"id" orm:"pk;auto;column(id)"`
+	NamespaceId   int    `json:"namespaceId" orm:"column(namespace_id);index"`
+	ClusterId     int    `json:"clusterId" orm:"column(cluster_id)"`
+	KubeNamespace string `json:"kubeNamespace" orm:"column(kube_namespace);size(255)"`
+	models.BaseTimeModel
+}
+
+func (m *Mirror) TableName() string {
+	return "namespace_mirror"
+}
+
+func (m *Mirror) TableUnique() [][]string {
+	return [][]string{{"cluster_id", "kube_namespace"}}
+}
+
+func InsertMirror(ctx context.Context, mirror *Mirror) error {
+	if mirror == nil {
+		return errors.New("mirror is nil")
+	}
+	_, err := models.GetORM().Insert(mirror)
+	return err
+}
+
+func UpdateMirrorNamespaceId(ctx context.Context, id, namespaceId int) error {
+	_, err := models.GetORM().QueryTable(new(Mirror).TableName()).Filter("id", id).Update(orm.Params{
+		"namespace_id": namespaceId,
+	})
+	return err
+}
+
+func DeleteMirror(ctx context.Context, id int) error {
+	_, err := models.GetORM().Delete(&Mirror{Id: id})
+	return err
+}
+
+func DeleteMirrorsByNamespaceId(ctx context.Context, namespaceId int) error {
+	_, err := models.GetORM().QueryTable(new(Mirror).TableName()).Filter("namespace_id", namespaceId).Delete()
+	return err
+}
+
+// ListMirrors returns the mirrors of the cluster, or of all clusters if clusterId is nil
+func ListMirrors(ctx context.Context, clusterId *int) ([]Mirror, error) {
+	var mirrors []Mirror
+	q := models.GetORM().QueryTable(new(Mirror).TableName())
+	if clusterId != nil {
+		q = q.Filter("cluster_id", *clusterId)
+	}
+	if _, err := q.OrderBy("id").All(&mirrors); err != nil && err != orm.ErrNoRows {
+		return nil, err
+	}
+	return mirrors, nil
+}
+
+func IsNamespaceMirrored(ctx context.Context, namespaceId int) bool {
+	return models.GetORM().QueryTable(new(Mirror).TableName()).Filter("namespace_id", namespaceId).Exist()
+}
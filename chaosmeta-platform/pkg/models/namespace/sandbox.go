@@ -0,0 +1,76 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package namespace
+
+import (
+	"chaosmeta-platform/pkg/models/common"
+	"context"
+	"errors"
+	"github.com/beego/beego/v2/client/orm"
+	"time"
+)
+
+// Sandbox marks a platform namespace as time-boxed, it is torn down with everything created in it at ExpireTime.
+// KubeNamespace is the kubernetes namespace created for the sandbox in ClusterId, empty if none
+type Sandbox struct {
+	Id            int       `json:"id" orm:"pk;auto;column(id)"`
+	NamespaceId   int       `json:"namespaceId" orm:"column(namespace_id);unique"`
+	ClusterId     int       `json:"clusterId" orm:"column(cluster_id)"`
+	KubeNamespace string    `json:"kubeNamespace" orm:"column(kube_namespace);size(255)"`
+	ExpireTime    time.Time `json:"expireTime" orm:"column(expire_time);type(datetime);index"`
+	models.BaseTimeModel
+}
+
+func (s *Sandbox) TableName() string {
+	return "namespace_sandbox"
+}
+
+func InsertSandbox(ctx context.Context, sandbox *Sandbox) error {
+	if sandbox == nil {
+		return errors.New("sandbox is nil")
+	}
+	_, err := models.GetORM().Insert(sandbox)
+	return err
+}
+
+func DeleteSandbox(ctx context.Context, id int) error {
+	_, err := models.GetORM().Delete(&Sandbox{Id: id})
+	return err
+}
+
+func GetSandboxByNamespaceId(ctx context.Context, sandbox *Sandbox) error {
+	if sandbox == nil {
+		return errors.New("sandbox is nil")
+	}
+	return models.GetORM().Read(sandbox, "namespace_id")
+}
+
+func ListSandboxes(ctx context.Context) ([]Sandbox, error) {
+	var sandboxes []Sandbox
+	if _, err := models.GetORM().QueryTable(new(Sandbox).TableName()).OrderBy("expire_time").All(&sandboxes); err != nil && err != orm.ErrNoRows {
+		return nil, err
+	}
+	return sandboxes, nil
+}
+
+func ListExpiredSandboxes(ctx context.Context, now time.Time) ([]Sandbox, error) {
+	var sandboxes []Sandbox
+	if _, err := models.GetORM().QueryTable(new(Sandbox).TableName()).Filter("expire_time__lte", now).OrderBy("expire_time").All(&sandboxes); err != nil && err != orm.ErrNoRows {
+		return nil, err
+	}
+	return sandboxes, nil
+}
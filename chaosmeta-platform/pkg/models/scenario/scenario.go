@@ -0,0 +1,114 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package scenario
+
+import (
+	models "chaosmeta-platform/pkg/models/common"
+	"errors"
+	"github.com/beego/beego/v2/client/orm"
+)
+
+// Scenario is a recorded experiment instance bound to an incident, Content is the json of the resolved workflow nodes and the timeline
+type Scenario struct {
+	Id                   int    `json:"id" orm:"pk;auto;column(id)"`
+	Name                 string `json:"name" orm:"column(name);size(255);index"`
+	Incident             string `json:"incident" orm:"column(incident);size(255);index"`
+	Description          string `json:"description" orm:"column(description);size(1024)"`
+	NamespaceID          int    `json:"namespace_id" orm:"column(namespace_id);index"`
+	SourceInstanceUUID   string `json:"source_instance_uuid" orm:"column(source_instance_uuid);size(128)"`
+	Content              string `json:"-" orm:"column(content);type(text)"`
+	Creator              int    `json:"creator" orm:"column(creator);index"`
+	LastReplayExperiment string `json:"last_replay_experiment" orm:"column(last_replay_experiment);size(128)"`
+	models.BaseTimeModel
+}
+
+func (s *Scenario) TableName() string {
+	return "scenario"
+}
+
+func (s *Scenario) TableUnique() [][]string {
+	return [][]string{{"name", "namespace_id"}}
+}
+
+func CreateScenario(s *Scenario) error {
+	if s == nil {
+		return errors.New("scenario is nil")
+	}
+	_, err := models.GetORM().Insert(s)
+	return err
+}
+
+func GetScenarioById(id int) (*Scenario, error) {
+	s := &Scenario{Id: id}
+	if err := models.GetORM().Read(s); err != nil {
+		if err == orm.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return s, nil
+}
+
+func UpdateScenarioLastReplay(id int, experimentUUID string) error {
+	_, err := models.GetORM().QueryTable(new(Scenario).TableName()).Filter("id", id).Update(orm.Params{
+		"last_replay_experiment": experimentUUID,
+	})
+	return err
+}
+
+func DeleteScenarioById(id int) error {
+	_, err := models.GetORM().Delete(&Scenario{Id: id})
+	return err
+}
+
+func SearchScenarios(namespaceId int, name, incident string, orderBy string, page, pageSize int) (int64, []*Scenario, error) {
+	var scenarios []*Scenario
+	querySeter := models.GetORM().QueryTable(new(Scenario).TableName())
+	scenarioQuery, err := models.NewDataSelectQuery(&querySeter)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if namespaceId > 0 {
+		scenarioQuery.Filter("namespace_id", models.NEGLECT, false, namespaceId)
+	}
+	if name != "" {
+		scenarioQuery.Filter("name", models.CONTAINS, true, name)
+	}
+	if incident != "" {
+		scenarioQuery.Filter("incident", models.CONTAINS, true, incident)
+	}
+
+	totalCount, err := scenarioQuery.GetOamQuerySeter().Count()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if orderBy == "" {
+		orderBy = "-create_time"
+	}
+	scenarioQuery.OrderBy(orderBy)
+	if err := scenarioQuery.Limit(pageSize, (page-1)*pageSize); err != nil {
+		return 0, nil, err
+	}
+
+	_, err = scenarioQuery.GetOamQuerySeter().All(&scenarios, "id", "name", "incident", "description", "namespace_id", "source_instance_uuid", "creator", "last_replay_experiment", "create_time", "update_time")
+	if err == orm.ErrNoRows {
+		return 0, nil, nil
+	}
+	return totalCount, scenarios, err
+}
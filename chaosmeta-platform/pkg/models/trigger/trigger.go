@@ -0,0 +1,101 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package trigger
+
+import (
+	models "chaosmeta-platform/pkg/models/common"
+	"errors"
+	"github.com/beego/beego/v2/client/orm"
+	"time"
+)
+
+// EventTrigger starts ExperimentUUID when an event is received on Topic, Filter is an optional "path=value"
+// condition on the json payload of the event, e.g. "canary.weight=50"
+type EventTrigger struct {
+	Id             int       `json:"id" orm:"pk;auto;column(id)"`
+	Name           string    `json:"name" orm:"column(name);size(255);unique"`
+	Topic          string    `json:"topic" orm:"column(topic);size(255);index"`
+	Filter         string    `json:"filter" orm:"column(filter);size(1024)"`
+	ExperimentUUID string    `json:"experiment_uuid" orm:"column(experiment_uuid);size(64);index"`
+	Enabled        bool      `json:"enabled" orm:"column(enabled);default(true)"`
+	Creator        string    `json:"creator" orm:"column(creator);size(255)"`
+	LastTriggered  time.Time `json:"last_triggered" orm:"column(last_triggered);type(datetime);null"`
+	models.BaseTimeModel
+}
+
+func (t *EventTrigger) TableName() string {
+	return "event_trigger"
+}
+
+func CreateEventTrigger(t *EventTrigger) (int64, error) {
+	if t == nil {
+		return 0, errors.New("event trigger is nil")
+	}
+	return models.GetORM().Insert(t)
+}
+
+func GetEventTriggerById(id int) (*EventTrigger, error) {
+	t := &EventTrigger{Id: id}
+	if err := models.GetORM().Read(t); err != nil {
+		if err == orm.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return t, nil
+}
+
+func UpdateEventTrigger(t *EventTrigger) error {
+	_, err := models.GetORM().Update(t, "topic", "filter", "experiment_uuid", "enabled", "update_time")
+	return err
+}
+
+func UpdateEventTriggerLastTriggered(id int, triggerTime time.Time) error {
+	_, err := models.GetORM().QueryTable(new(EventTrigger).TableName()).Filter("id", id).Update(orm.Params{
+		"last_triggered": triggerTime,
+	})
+	return err
+}
+
+func DeleteEventTriggerById(id int) error {
+	_, err := models.GetORM().Delete(&EventTrigger{Id: id})
+	return err
+}
+
+func ListEventTriggers() ([]*EventTrigger, error) {
+	var triggers []*EventTrigger
+	if _, err := models.GetORM().QueryTable(new(EventTrigger).TableName()).OrderBy("id").All(&triggers); err != nil && err != orm.ErrNoRows {
+		return nil, err
+	}
+	return triggers, nil
+}
+
+func ListEnabledEventTriggersByTopic(topic string) ([]*EventTrigger, error) {
+	var triggers []*EventTrigger
+	if _, err := models.GetORM().QueryTable(new(EventTrigger).TableName()).Filter("topic", topic).Filter("enabled", true).All(&triggers); err != nil && err != orm.ErrNoRows {
+		return nil, err
+	}
+	return triggers, nil
+}
+
+func ListEventTriggersByExperimentUUID(experimentUUID string) ([]*EventTrigger, error) {
+	var triggers []*EventTrigger
+	if _, err := models.GetORM().QueryTable(new(EventTrigger).TableName()).Filter("experiment_uuid", experimentUUID).All(&triggers); err != nil && err != orm.ErrNoRows {
+		return nil, err
+	}
+	return triggers, nil
+}
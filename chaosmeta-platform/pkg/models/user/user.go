@@ -29,15 +29,21 @@ const (
 	NormalRole = "normal"
 )
 
+const (
+	DisabledReasonInactive = "inactive"
+)
+
 type User struct {
-	ID            int       `json:"id" orm:"pk;auto;column(id)"`
-	Email         string    `json:"email" orm:"unique;index;column(email);size(255)"`
-	Password      string    `json:"password" orm:"column(password);size(255)"`
-	Role          string    `json:"role" orm:"index; column(role);size(32)"`
-	Token         string    `json:"token" orm:"column(token);size(255)"`
-	Disabled      bool      `json:"disabled" orm:"column(disabled)"`
-	IsDeleted     bool      `json:"isDeleted" orm:"column(is_deleted);default(0)"`
-	LastLoginTime time.Time `json:"lastLoginTime" orm:"column(last_login_time);auto_now;type(datetime)"`
+	ID             int       `json:"id" orm:"pk;auto;column(id)"`
+	Email          string    `json:"email" orm:"unique;index;column(email);size(255)"`
+	Password       string    `json:"password" orm:"column(password);size(255)"`
+	Role           string    `json:"role" orm:"index; column(role);size(32)"`
+	Token          string    `json:"token" orm:"column(token);size(255)"`
+	Disabled       bool      `json:"disabled" orm:"column(disabled)"`
+	IsDeleted      bool      `json:"isDeleted" orm:"column(is_deleted);default(0)"`
+	LastLoginTime  time.Time `json:"lastLoginTime" orm:"column(last_login_time);auto_now;type(datetime)"`
+	LastActiveTime time.Time `json:"lastActiveTime" orm:"column(last_active_time);null;type(datetime)"`
+	DisabledReason string    `json:"disabledReason" orm:"column(disabled_reason);size(255);null"`
 	models.BaseTimeModel
 }
 
@@ -141,3 +147,119 @@ func DeleteUsersByNameList(ctx context.Context, names []string) error {
 	_, err = userQuery.Delete()
 	return err
 }
+
+func UpdateUsersDisabled(ctx context.Context, userId []int, disabled bool, reason string) error {
+	user := User{}
+	querySeter := models.GetORM().QueryTable(user.TableName())
+	userQuery, err := models.NewDataSelectQuery(&querySeter)
+	if err != nil {
+		return err
+	}
+	userQuery.Filter("id", models.IN, false, userId)
+	params := orm.Params{
+		"disabled":        disabled,
+		"disabled_reason": reason,
+	}
+	// re-activation restarts the inactivity window, otherwise the policy would disable the user again immediately
+	if !disabled {
+		params["last_active_time"] = time.Now()
+	}
+	_, err = userQuery.Update(params)
+	return err
+}
+
+func UpdateUserActiveTime(ctx context.Context, id int, activeTime time.Time) error {
+	user := User{}
+	querySeter := models.GetORM().QueryTable(user.TableName())
+	userQuery, err := models.NewDataSelectQuery(&querySeter)
+	if err != nil {
+		return err
+	}
+	userQuery.Filter("id", models.NEGLECT, false, id)
+	_, err = userQuery.Update(orm.Params{
+		"last_active_time": activeTime,
+	})
+	return err
+}
+
+// QueryUserActivity lists the undeleted users, when inactiveBefore is not zero, only users whose last activity is earlier than it are returned
+func QueryUserActivity(ctx context.Context, name string, inactiveBefore time.Time, disabled *bool, orderBy string, page, pageSize int) (int64, []User, error) {
+	u, users := User{}, new([]User)
+	querySeter := models.GetORM().QueryTable(u.TableName())
+	userQuery, err := models.NewDataSelectQuery(&querySeter)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	userQuery.Filter("is_deleted", models.NEGLECT, false, false)
+	if len(name) > 0 {
+		userQuery.Filter("email", models.CONTAINS, true, name)
+	}
+
+	if disabled != nil {
+		userQuery.Filter("disabled", models.NEGLECT, false, *disabled)
+	}
+
+	if !inactiveBefore.IsZero() {
+		userQuery.Filter("last_active_time", models.LT, false, inactiveBefore)
+	}
+
+	totalCount, err := userQuery.GetOamQuerySeter().Count()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	orderByList := []string{"last_active_time"}
+	if len(orderBy) > 0 {
+		if err := models.CheckOrderBy(orderBy, "email", "last_active_time", "last_login_time", "create_time"); err != nil {
+			return 0, nil, err
+		}
+		orderByList = []string{orderBy}
+	}
+	userQuery.OrderBy(orderByList...)
+	if err := userQuery.Limit(pageSize, (page-1)*pageSize); err != nil {
+		return 0, nil, err
+	}
+
+	_, err = userQuery.GetOamQuerySeter().All(users)
+	if err == orm.ErrNoRows {
+		return 0, nil, nil
+	}
+	return totalCount, *users, err
+}
+
+// FillUsersActiveTime sets the activity time of users which have never been tracked, so that the inactivity window starts now
+func FillUsersActiveTime(ctx context.Context, activeTime time.Time) error {
+	user := User{}
+	querySeter := models.GetORM().QueryTable(user.TableName())
+	userQuery, err := models.NewDataSelectQuery(&querySeter)
+	if err != nil {
+		return err
+	}
+	userQuery.Filter("last_active_time", models.ISNULL, false, true)
+	_, err = userQuery.Update(orm.Params{
+		"last_active_time": activeTime,
+	})
+	return err
+}
+
+// ListInactiveUsers returns the enabled normal users whose last activity is earlier than inactiveBefore
+func ListInactiveUsers(ctx context.Context, inactiveBefore time.Time) ([]User, error) {
+	u, users := User{}, new([]User)
+	querySeter := models.GetORM().QueryTable(u.TableName())
+	userQuery, err := models.NewDataSelectQuery(&querySeter)
+	if err != nil {
+		return nil, err
+	}
+
+	userQuery.Filter("is_deleted", models.NEGLECT, false, false)
+	userQuery.Filter("disabled", models.NEGLECT, false, false)
+	userQuery.Filter("role", models.NEGLECT, false, NormalRole)
+	userQuery.Filter("last_active_time", models.LT, false, inactiveBefore)
+
+	_, err = userQuery.GetOamQuerySeter().All(users)
+	if err == orm.ErrNoRows {
+		return nil, nil
+	}
+	return *users, err
+}
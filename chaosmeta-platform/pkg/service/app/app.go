@@ -0,0 +1,121 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package app
+
+import (
+	"chaosmeta-platform/pkg/models/agent"
+	"chaosmeta-platform/pkg/service/user"
+	"context"
+	"errors"
+	"fmt"
+)
+
+type AppService struct{}
+
+// checkAdmin the applications are shared by all namespaces, so only admins can change them
+func checkAdmin(ctx context.Context, userName string) error {
+	userService := user.UserService{}
+	if !userService.IsAdmin(ctx, userName) {
+		return errors.New("only admins can manage apps")
+	}
+	return nil
+}
+
+type AppDetail struct {
+	agent.App
+	Workloads []agent.AppWorkload `json:"workloads"`
+}
+
+func (s *AppService) Get(ctx context.Context, id int) (*AppDetail, error) {
+	appGet := agent.App{ID: id}
+	if err := agent.GetAppById(ctx, &appGet); err != nil {
+		return nil, err
+	}
+
+	workloads, err := agent.ListAppWorkloadsByAppId(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &AppDetail{App: appGet, Workloads: workloads}, nil
+}
+
+func (s *AppService) GetList(ctx context.Context, name string, clusterId int, namespace, orderBy string, page, pageSize int) (int64, []agent.App, error) {
+	return agent.QueryApps(ctx, name, clusterId, namespace, orderBy, page, pageSize)
+}
+
+func (s *AppService) Delete(ctx context.Context, userName string, id int) error {
+	if err := checkAdmin(ctx, userName); err != nil {
+		return err
+	}
+	if id <= 0 {
+		return errors.New("invalid id")
+	}
+	return agent.DeleteApp(ctx, id)
+}
+
+// Adopt registers a discovered application into the application registry, appName overrides the suggested name if provided
+func (s *AppService) Adopt(ctx context.Context, userName string, clusterId int, namespace, name, appName string) (int64, error) {
+	if err := checkAdmin(ctx, userName); err != nil {
+		return 0, err
+	}
+	suggestion, err := s.GetSuggestion(ctx, clusterId, namespace, name)
+	if err != nil {
+		return 0, err
+	}
+	if suggestion.Adopted {
+		return 0, fmt.Errorf("app[%s] in namespace[%s] has been adopted", name, namespace)
+	}
+
+	if appName == "" {
+		appName = suggestion.Name
+	}
+	appCreate := agent.App{Name: appName}
+	if err := agent.GetAppByName(ctx, &appCreate); err == nil {
+		return 0, fmt.Errorf("app[%s] already exists", appName)
+	}
+
+	appCreate = agent.App{
+		Name:         appName,
+		ClusterID:    clusterId,
+		Namespace:    suggestion.Namespace,
+		Owner:        suggestion.Owner,
+		SelectLabel:  suggestion.SelectLabel,
+		Source:       agent.AppSourceDiscovery,
+		DiscoveryKey: suggestion.Key,
+	}
+	appId, err := agent.InsertApp(ctx, &appCreate)
+	if err != nil {
+		return 0, err
+	}
+
+	var workloads []*agent.AppWorkload
+	for _, workload := range suggestion.Workloads {
+		workloads = append(workloads, &agent.AppWorkload{
+			AppID:     int(appId),
+			Kind:      workload.Kind,
+			Namespace: workload.Namespace,
+			Name:      workload.Name,
+			Replicas:  workload.Replicas,
+		})
+	}
+	if err := agent.InsertAppWorkloads(ctx, workloads); err != nil {
+		return appId, err
+	}
+
+	markSuggestionAdopted(clusterId, namespace, name)
+	return appId, nil
+}
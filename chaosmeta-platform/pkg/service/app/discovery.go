@@ -0,0 +1,422 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package app
+
+import (
+	"chaosmeta-platform/config"
+	"chaosmeta-platform/pkg/models/agent"
+	clusterModel "chaosmeta-platform/pkg/models/cluster"
+	"chaosmeta-platform/pkg/service/cluster"
+	"chaosmeta-platform/util/log"
+	"context"
+	"fmt"
+	"github.com/robfig/cron"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	KindDeployment  = "Deployment"
+	KindStatefulSet = "StatefulSet"
+)
+
+var (
+	// the first label found in the pod selector is used to group workloads into one application
+	appLabelKeys = []string{"app.kubernetes.io/name", "app.kubernetes.io/instance", "app", "k8s-app"}
+	// the first annotation found on the workload is used as the owner of the application
+	ownerAnnotationKeys = []string{"chaosmeta.io/owner", "app.kubernetes.io/owner", "owner", "team"}
+	ignoredNamespaces   = []string{"kube-system", "kube-public", "kube-node-lease"}
+)
+
+type WorkloadInfo struct {
+	Kind          string `json:"kind"`
+	Namespace     string `json:"namespace"`
+	Name          string `json:"name"`
+	Replicas      int32  `json:"replicas"`
+	ReadyReplicas int32  `json:"readyReplicas"`
+}
+
+type SuggestedApp struct {
+	// Key identifies the discovered application across scans, it is recorded in the adopted application
+	Key         string            `json:"key"`
+	Name        string            `json:"name"`
+	ClusterID   int               `json:"clusterId"`
+	Namespace   string            `json:"namespace"`
+	Owner       string            `json:"owner"`
+	SelectLabel string            `json:"selectLabel"`
+	Labels      map[string]string `json:"labels"`
+	Replicas    int32             `json:"replicas"`
+	Workloads   []WorkloadInfo    `json:"workloads"`
+	Adopted     bool              `json:"adopted"`
+}
+
+type discoveryResult struct {
+	scanTime time.Time
+	apps     []SuggestedApp
+}
+
+var discoveryCache = struct {
+	sync.RWMutex
+	results map[int]*discoveryResult
+}{results: make(map[int]*discoveryResult)}
+
+func Init() {
+	// there is no cluster to discover applications from in standalone mode
+	if config.DefaultRunOptIns.RunMode.IsStandalone() {
+		return
+	}
+
+	dr := DiscoveryRoutine{context: context.Background()}
+	go dr.Start()
+}
+
+type DiscoveryRoutine struct {
+	context   context.Context
+	localCron *cron.Cron
+}
+
+func (d *DiscoveryRoutine) ScanAll() {
+	s := AppService{}
+	for _, clusterId := range getDiscoveryClusterIds(d.context) {
+		if _, err := s.scan(d.context, clusterId); err != nil {
+			log.Errorf("discover apps in cluster[%d] error: %s", clusterId, err.Error())
+		}
+	}
+}
+
+func (d *DiscoveryRoutine) Start() {
+	d.ScanAll()
+
+	localCron := cron.New()
+	if err := localCron.AddFunc("@every 30m", d.ScanAll); err != nil {
+		log.Error(err)
+		return
+	}
+
+	localCron.Start()
+	d.localCron = localCron
+
+	select {
+	case <-d.context.Done():
+		log.Info("Receive stop signal")
+	}
+}
+
+// getDiscoveryClusterIds returns the registered clusters, or the cluster of the run mode if none is registered
+func getDiscoveryClusterIds(ctx context.Context) []int {
+	clusters, err := clusterModel.ListCluster()
+	if err != nil {
+		log.Error(err)
+	}
+	if len(clusters) == 0 {
+		return []int{config.DefaultRunOptIns.RunMode.Int()}
+	}
+
+	var ids []int
+	for _, c := range clusters {
+		ids = append(ids, c.ID)
+	}
+	return ids
+}
+
+// Scan rescans the cluster on request of an admin
+func (s *AppService) Scan(ctx context.Context, userName string, clusterId int) ([]SuggestedApp, error) {
+	if err := checkAdmin(ctx, userName); err != nil {
+		return nil, err
+	}
+	return s.scan(ctx, clusterId)
+}
+
+// scan lists the workloads of the cluster, groups them into suggested applications and caches the result
+func (s *AppService) scan(ctx context.Context, clusterId int) ([]SuggestedApp, error) {
+	clusterService := cluster.ClusterService{}
+	kubeClient, _, err := clusterService.GetRestConfig(ctx, clusterId)
+	if err != nil {
+		return nil, err
+	}
+
+	workloads, err := listWorkloads(ctx, kubeClient)
+	if err != nil {
+		return nil, err
+	}
+
+	apps := groupWorkloads(clusterId, workloads)
+	if err := markAdopted(ctx, clusterId, apps); err != nil {
+		log.Error(err)
+	}
+
+	discoveryCache.Lock()
+	discoveryCache.results[clusterId] = &discoveryResult{scanTime: time.Now(), apps: apps}
+	discoveryCache.Unlock()
+	return apps, nil
+}
+
+// ListSuggestions returns the cached discovery result of the cluster, scanning it first if it has never been scanned
+func (s *AppService) ListSuggestions(ctx context.Context, clusterId int, namespace, name string) ([]SuggestedApp, time.Time, error) {
+	discoveryCache.RLock()
+	result, ok := discoveryCache.results[clusterId]
+	discoveryCache.RUnlock()
+	if !ok {
+		if _, err := s.scan(ctx, clusterId); err != nil {
+			return nil, time.Time{}, err
+		}
+		discoveryCache.RLock()
+		result = discoveryCache.results[clusterId]
+		discoveryCache.RUnlock()
+	}
+
+	var apps []SuggestedApp
+	for _, app := range result.apps {
+		if namespace != "" && app.Namespace != namespace {
+			continue
+		}
+		if name != "" && !strings.Contains(app.Name, name) {
+			continue
+		}
+		apps = append(apps, app)
+	}
+	return apps, result.scanTime, nil
+}
+
+func (s *AppService) GetSuggestion(ctx context.Context, clusterId int, namespace, name string) (*SuggestedApp, error) {
+	apps, _, err := s.ListSuggestions(ctx, clusterId, namespace, "")
+	if err != nil {
+		return nil, err
+	}
+	for i := range apps {
+		if apps[i].Name == name {
+			return &apps[i], nil
+		}
+	}
+	return nil, fmt.Errorf("no discovered app[%s] in namespace[%s] of cluster[%d]", name, namespace, clusterId)
+}
+
+func markSuggestionAdopted(clusterId int, namespace, name string) {
+	discoveryCache.Lock()
+	defer discoveryCache.Unlock()
+	result, ok := discoveryCache.results[clusterId]
+	if !ok {
+		return
+	}
+	for i := range result.apps {
+		if result.apps[i].Namespace == namespace && result.apps[i].Name == name {
+			result.apps[i].Adopted = true
+		}
+	}
+}
+
+func markAdopted(ctx context.Context, clusterId int, apps []SuggestedApp) error {
+	_, registered, err := agent.QueryApps(ctx, "", clusterId, "", "", 1, 100000)
+	if err != nil {
+		return err
+	}
+
+	setAdopted(apps, registered)
+	return nil
+}
+
+// setAdopted matches the apps by the discovery key recorded in adoption, which is kept when the name is overridden.
+// The apps adopted without the key are matched by namespace, name and select label
+func setAdopted(apps []SuggestedApp, registered []agent.App) {
+	adopted := make(map[string]bool)
+	for _, app := range registered {
+		if app.DiscoveryKey != "" {
+			adopted[app.DiscoveryKey] = true
+		} else {
+			adopted[getAdoptedKey(app.Namespace, app.Name, app.SelectLabel)] = true
+		}
+	}
+	for i := range apps {
+		apps[i].Adopted = adopted[apps[i].Key] || adopted[getAdoptedKey(apps[i].Namespace, apps[i].Name, apps[i].SelectLabel)]
+	}
+}
+
+func getDiscoveryKey(clusterId int, namespace, name string) string {
+	return fmt.Sprintf("%d/%s/%s", clusterId, namespace, name)
+}
+
+func getAdoptedKey(namespace, name, selectLabel string) string {
+	return fmt.Sprintf("%s/%s/%s", namespace, name, selectLabel)
+}
+
+type workload struct {
+	info        WorkloadInfo
+	labels      map[string]string
+	selector    *metav1.LabelSelector
+	annotations map[string]string
+}
+
+func listWorkloads(ctx context.Context, kubeClient kubernetes.Interface) ([]workload, error) {
+	var workloads []workload
+	deployments, err := kubeClient.AppsV1().Deployments(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("list deployments error: %s", err.Error())
+	}
+	for _, d := range deployments.Items {
+		workloads = append(workloads, newDeploymentWorkload(d))
+	}
+
+	statefulSets, err := kubeClient.AppsV1().StatefulSets(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("list statefulsets error: %s", err.Error())
+	}
+	for _, s := range statefulSets.Items {
+		workloads = append(workloads, newStatefulSetWorkload(s))
+	}
+	return workloads, nil
+}
+
+func newDeploymentWorkload(d appsv1.Deployment) workload {
+	var replicas int32 = 1
+	if d.Spec.Replicas != nil {
+		replicas = *d.Spec.Replicas
+	}
+	return workload{
+		info: WorkloadInfo{
+			Kind:          KindDeployment,
+			Namespace:     d.Namespace,
+			Name:          d.Name,
+			Replicas:      replicas,
+			ReadyReplicas: d.Status.ReadyReplicas,
+		},
+		labels:      d.Spec.Template.Labels,
+		selector:    d.Spec.Selector,
+		annotations: d.Annotations,
+	}
+}
+
+func newStatefulSetWorkload(s appsv1.StatefulSet) workload {
+	var replicas int32 = 1
+	if s.Spec.Replicas != nil {
+		replicas = *s.Spec.Replicas
+	}
+	return workload{
+		info: WorkloadInfo{
+			Kind:          KindStatefulSet,
+			Namespace:     s.Namespace,
+			Name:          s.Name,
+			Replicas:      replicas,
+			ReadyReplicas: s.Status.ReadyReplicas,
+		},
+		labels:      s.Spec.Template.Labels,
+		selector:    s.Spec.Selector,
+		annotations: s.Annotations,
+	}
+}
+
+// getAppLabel returns the label which identifies the application of the workload, the workload name is used if no known label exists
+func getAppLabel(w workload) (string, string) {
+	var matchLabels map[string]string
+	if w.selector != nil {
+		matchLabels = w.selector.MatchLabels
+	}
+	for _, key := range appLabelKeys {
+		if value, ok := matchLabels[key]; ok {
+			return key, value
+		}
+	}
+	for _, key := range appLabelKeys {
+		if value, ok := w.labels[key]; ok {
+			return key, value
+		}
+	}
+	return "", w.info.Name
+}
+
+func groupWorkloads(clusterId int, workloads []workload) []SuggestedApp {
+	appMap := make(map[string]*SuggestedApp)
+	var keys []string
+	for _, w := range workloads {
+		if isIgnoredNamespace(w.info.Namespace) {
+			continue
+		}
+
+		labelKey, appName := getAppLabel(w)
+		key := fmt.Sprintf("%s/%s", w.info.Namespace, appName)
+		app, ok := appMap[key]
+		if !ok {
+			app = &SuggestedApp{
+				Key:       getDiscoveryKey(clusterId, w.info.Namespace, appName),
+				Name:      appName,
+				ClusterID: clusterId,
+				Namespace: w.info.Namespace,
+				Labels:    copyLabels(w.labels),
+			}
+			if labelKey != "" {
+				app.SelectLabel = fmt.Sprintf("%s=%s", labelKey, appName)
+			}
+			appMap[key] = app
+			keys = append(keys, key)
+		} else {
+			app.Labels = intersectLabels(app.Labels, w.labels)
+		}
+
+		if app.Owner == "" {
+			app.Owner = getOwner(w.annotations)
+		}
+		app.Replicas += w.info.Replicas
+		app.Workloads = append(app.Workloads, w.info)
+	}
+
+	sort.Strings(keys)
+	apps := make([]SuggestedApp, 0, len(keys))
+	for _, key := range keys {
+		apps = append(apps, *appMap[key])
+	}
+	return apps
+}
+
+func getOwner(annotations map[string]string) string {
+	for _, key := range ownerAnnotationKeys {
+		if value, ok := annotations[key]; ok && value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+func isIgnoredNamespace(namespace string) bool {
+	for _, ns := range ignoredNamespaces {
+		if ns == namespace {
+			return true
+		}
+	}
+	return false
+}
+
+func copyLabels(labels map[string]string) map[string]string {
+	re := make(map[string]string, len(labels))
+	for k, v := range labels {
+		re[k] = v
+	}
+	return re
+}
+
+func intersectLabels(a, b map[string]string) map[string]string {
+	re := make(map[string]string)
+	for k, v := range a {
+		if b[k] == v {
+			re[k] = v
+		}
+	}
+	return re
+}
@@ -0,0 +1,91 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package app
+
+import (
+	"chaosmeta-platform/pkg/models/agent"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"testing"
+)
+
+func TestGroupWorkloads(t *testing.T) {
+	workloads := []workload{
+		{
+			info:        WorkloadInfo{Kind: KindDeployment, Namespace: "shop", Name: "cart-v1", Replicas: 2},
+			labels:      map[string]string{"app": "cart", "version": "v1"},
+			selector:    &metav1.LabelSelector{MatchLabels: map[string]string{"app": "cart"}},
+			annotations: map[string]string{"owner": "team-a"},
+		},
+		{
+			info:     WorkloadInfo{Kind: KindDeployment, Namespace: "shop", Name: "cart-v2", Replicas: 3},
+			labels:   map[string]string{"app": "cart", "version": "v2"},
+			selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "cart"}},
+		},
+		{
+			info:   WorkloadInfo{Kind: KindStatefulSet, Namespace: "shop", Name: "redis", Replicas: 1},
+			labels: map[string]string{"tier": "cache"},
+		},
+		{
+			info:     WorkloadInfo{Kind: KindDeployment, Namespace: "kube-system", Name: "coredns", Replicas: 2},
+			selector: &metav1.LabelSelector{MatchLabels: map[string]string{"k8s-app": "kube-dns"}},
+		},
+	}
+
+	apps := groupWorkloads(1, workloads)
+	if len(apps) != 2 {
+		t.Fatalf("expect 2 apps, got %d", len(apps))
+	}
+
+	cart := apps[0]
+	if cart.Name != "cart" || cart.SelectLabel != "app=cart" || cart.Owner != "team-a" {
+		t.Errorf("unexpected cart app: %+v", cart)
+	}
+	if cart.Replicas != 5 || len(cart.Workloads) != 2 {
+		t.Errorf("expect 5 replicas in 2 workloads, got %d in %d", cart.Replicas, len(cart.Workloads))
+	}
+	if len(cart.Labels) != 1 || cart.Labels["app"] != "cart" {
+		t.Errorf("expect only common labels, got %v", cart.Labels)
+	}
+
+	redis := apps[1]
+	if redis.Name != "redis" || redis.SelectLabel != "" || redis.ClusterID != 1 || redis.Key != "1/shop/redis" {
+		t.Errorf("unexpected redis app: %+v", redis)
+	}
+}
+
+func TestSetAdopted(t *testing.T) {
+	apps := []SuggestedApp{
+		{Key: "1/shop/cart", Name: "cart", Namespace: "shop", SelectLabel: "app=cart"},
+		{Key: "1/shop/redis", Name: "redis", Namespace: "shop"},
+		{Key: "1/shop/mysql", Name: "mysql", Namespace: "shop"},
+		{Key: "1/shop-test/cart", Name: "cart", Namespace: "shop-test", SelectLabel: "app=cart"},
+		{Key: "1/shop/order", Name: "order", Namespace: "shop", SelectLabel: "app=order"},
+	}
+	setAdopted(apps, []agent.App{
+		{Name: "cart", Namespace: "shop", SelectLabel: "app=cart"},
+		{Name: "redis", Namespace: "shop"},
+		// adopted with an overridden name
+		{Name: "shop-order", Namespace: "shop", SelectLabel: "app=order", DiscoveryKey: "1/shop/order"},
+	})
+
+	want := []bool{true, true, false, false, true}
+	for i := range apps {
+		if apps[i].Adopted != want[i] {
+			t.Errorf("app %s/%s adopted = %v, want %v", apps[i].Namespace, apps[i].Name, apps[i].Adopted, want[i])
+		}
+	}
+}
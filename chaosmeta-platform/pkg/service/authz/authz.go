@@ -0,0 +1,248 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package authz
+
+import (
+	"bytes"
+	"chaosmeta-platform/config"
+	"chaosmeta-platform/util/log"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	ActionRunExperiment = "run_experiment"
+
+	ProviderOPA  = "opa"
+	ProviderRest = "rest"
+
+	defaultTimeout = 5 * time.Second
+)
+
+// Request describes who wants to do what and where, it is posted to the policy engine as json
+type Request struct {
+	Action         string  `json:"action"`
+	User           string  `json:"user"`
+	NamespaceId    int     `json:"namespaceId"`
+	NamespaceName  string  `json:"namespaceName"`
+	ExperimentUUID string  `json:"experimentUUID"`
+	ExperimentName string  `json:"experimentName"`
+	InstanceUUID   string  `json:"instanceUUID,omitempty"`
+	Faults         []Fault `json:"faults"`
+}
+
+type Fault struct {
+	Node            string            `json:"node"`
+	Scope           string            `json:"scope"`
+	Target          string            `json:"target"`
+	Fault           string            `json:"fault"`
+	Duration        string            `json:"duration"`
+	Args            map[string]string `json:"args,omitempty"`
+	TargetNamespace string            `json:"targetNamespace,omitempty"`
+	TargetName      string            `json:"targetName,omitempty"`
+	TargetIP        string            `json:"targetIP,omitempty"`
+	TargetLabel     string            `json:"targetLabel,omitempty"`
+}
+
+type Decision struct {
+	Allowed bool   `json:"allowed"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+// Authorizer delegates the decision to a policy engine, an error means that no decision has been made
+type Authorizer interface {
+	Authorize(ctx context.Context, req *Request) (*Decision, error)
+}
+
+type Factory func(url string, timeout time.Duration) (Authorizer, error)
+
+var (
+	factories = map[string]Factory{
+		ProviderOPA:  NewOPAAuthorizer,
+		ProviderRest: NewRestAuthorizer,
+	}
+	factoriesLock sync.Mutex
+
+	globalAuthorizer Authorizer
+)
+
+// Register makes an authorizer compiled into the platform available as authz.provider
+func Register(provider string, factory Factory) {
+	factoriesLock.Lock()
+	defer factoriesLock.Unlock()
+	factories[provider] = factory
+}
+
+func Init() error {
+	authzConfig := config.DefaultRunOptIns.Authz
+	if authzConfig.Provider == "" {
+		return nil
+	}
+
+	factoriesLock.Lock()
+	factory, ok := factories[authzConfig.Provider]
+	factoriesLock.Unlock()
+	if !ok {
+		return fmt.Errorf("authz provider[%s] is not supported", authzConfig.Provider)
+	}
+
+	timeout := defaultTimeout
+	if authzConfig.Timeout != "" {
+		var err error
+		if timeout, err = time.ParseDuration(authzConfig.Timeout); err != nil {
+			return fmt.Errorf("authz timeout[%s] format error: %s", authzConfig.Timeout, err.Error())
+		}
+	}
+
+	authorizer, err := factory(authzConfig.Url, timeout)
+	if err != nil {
+		return fmt.Errorf("init authz provider[%s] error: %s", authzConfig.Provider, err.Error())
+	}
+	globalAuthorizer = authorizer
+	return nil
+}
+
+// Enabled whether the decisions are delegated, the callers can skip building the request if not
+func Enabled() bool {
+	return globalAuthorizer != nil
+}
+
+// Authorize returns an error if the request is denied. When the policy engine is unavailable, the request is denied
+// unless authz.failOpen is set
+func Authorize(ctx context.Context, req *Request) error {
+	if globalAuthorizer == nil {
+		return nil
+	}
+	return authorize(ctx, globalAuthorizer, req, config.DefaultRunOptIns.Authz.FailOpen)
+}
+
+func authorize(ctx context.Context, authorizer Authorizer, req *Request, failOpen bool) error {
+	decision, err := authorizer.Authorize(ctx, req)
+	if err != nil {
+		if failOpen {
+			log.Warnf("authorize %s of user[%s] error, allowed as fail open: %s", req.Action, req.User, err.Error())
+			return nil
+		}
+		return fmt.Errorf("authorization unavailable: %s", err.Error())
+	}
+
+	if !decision.Allowed {
+		if decision.Reason == "" {
+			return errors.New("denied by authorization policy")
+		}
+		return fmt.Errorf("denied by authorization policy: %s", decision.Reason)
+	}
+	return nil
+}
+
+// OPAAuthorizer queries the data api of OPA, such as http://opa:8181/v1/data/chaosmeta/allow. The result of the rule
+// is either a boolean, or an object with "allowed" and "reason"
+type OPAAuthorizer struct {
+	url    string
+	client *http.Client
+}
+
+func NewOPAAuthorizer(url string, timeout time.Duration) (Authorizer, error) {
+	if url == "" {
+		return nil, errors.New("url is empty")
+	}
+	return &OPAAuthorizer{url: url, client: &http.Client{Timeout: timeout}}, nil
+}
+
+func (a *OPAAuthorizer) Authorize(ctx context.Context, req *Request) (*Decision, error) {
+	var resp struct {
+		Result json.RawMessage `json:"result"`
+	}
+	if err := postJSON(ctx, a.client, a.url, map[string]interface{}{"input": req}, &resp); err != nil {
+		return nil, err
+	}
+
+	// an undefined rule has no result
+	if len(resp.Result) == 0 {
+		return &Decision{Reason: "policy result is undefined"}, nil
+	}
+
+	var allowed bool
+	if err := json.Unmarshal(resp.Result, &allowed); err == nil {
+		return &Decision{Allowed: allowed}, nil
+	}
+
+	var decision Decision
+	if err := json.Unmarshal(resp.Result, &decision); err != nil {
+		return nil, fmt.Errorf("policy result[%s] format error: %s", string(resp.Result), err.Error())
+	}
+	return &decision, nil
+}
+
+// RestAuthorizer posts the request to a REST service, which returns a Decision
+type RestAuthorizer struct {
+	url    string
+	client *http.Client
+}
+
+func NewRestAuthorizer(url string, timeout time.Duration) (Authorizer, error) {
+	if url == "" {
+		return nil, errors.New("url is empty")
+	}
+	return &RestAuthorizer{url: url, client: &http.Client{Timeout: timeout}}, nil
+}
+
+func (a *RestAuthorizer) Authorize(ctx context.Context, req *Request) (*Decision, error) {
+	var decision Decision
+	if err := postJSON(ctx, a.client, a.url, req, &decision); err != nil {
+		return nil, err
+	}
+	return &decision, nil
+}
+
+func postJSON(ctx context.Context, client *http.Client, url string, body interface{}, result interface{}) error {
+	reqBody, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(reqBody))
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("policy engine returns status code %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	if err := json.Unmarshal(respBody, result); err != nil {
+		return fmt.Errorf("response[%s] format error: %s", string(respBody), err.Error())
+	}
+	return nil
+}
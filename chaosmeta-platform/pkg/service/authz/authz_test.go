@@ -0,0 +1,93 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package authz
+
+import (
+	"chaosmeta-platform/util/log"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestOPAAuthorizer(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		want    Decision
+		wantErr bool
+	}{
+		{name: "boolean allowed", body: `{"result": true}`, want: Decision{Allowed: true}},
+		{name: "boolean denied", body: `{"result": false}`, want: Decision{}},
+		{name: "object", body: `{"result": {"allowed": false, "reason": "no pod kill in prod"}}`, want: Decision{Reason: "no pod kill in prod"}},
+		{name: "undefined", body: `{}`, want: Decision{Reason: "policy result is undefined"}},
+		{name: "bad result", body: `{"result": "yes"}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			authorizer, _ := NewOPAAuthorizer(server.URL, time.Second)
+			got, err := authorizer.Authorize(context.Background(), &Request{Action: ActionRunExperiment})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Authorize() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && *got != tt.want {
+				t.Errorf("Authorize() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+type fakeAuthorizer struct {
+	decision *Decision
+	err      error
+}
+
+func (f *fakeAuthorizer) Authorize(ctx context.Context, req *Request) (*Decision, error) {
+	return f.decision, f.err
+}
+
+func TestAuthorize(t *testing.T) {
+	// the fail open case is logged, keep the log file out of the source tree
+	log.SetDefaultLogOption(log.LogOption{LogPath: filepath.Join(t.TempDir(), "default.log"), Level: "info", OutPutType: "bothfileandstderrput"})
+	tests := []struct {
+		name       string
+		authorizer *fakeAuthorizer
+		failOpen   bool
+		wantErr    bool
+	}{
+		{name: "allowed", authorizer: &fakeAuthorizer{decision: &Decision{Allowed: true}}},
+		{name: "denied", authorizer: &fakeAuthorizer{decision: &Decision{Reason: "frozen"}}, wantErr: true},
+		{name: "unavailable", authorizer: &fakeAuthorizer{err: errors.New("timeout")}, wantErr: true},
+		{name: "unavailable fail open", authorizer: &fakeAuthorizer{err: errors.New("timeout")}, failOpen: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := authorize(context.Background(), tt.authorizer, &Request{Action: ActionRunExperiment}, tt.failOpen)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("authorize() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package experiment
+
+import (
+	"chaosmeta-platform/pkg/models/inject/basic"
+	namespaceModel "chaosmeta-platform/pkg/models/namespace"
+	"chaosmeta-platform/pkg/service/authz"
+	"chaosmeta-platform/pkg/service/experiment_instance"
+	"context"
+	"fmt"
+)
+
+// authorizeRun asks the policy engine whether the user may run the faults of the instance. It is called before the
+// sensitive args are decrypted, so that their values never leave the platform
+func authorizeRun(experimentGet *ExperimentGet, experimentInstanceId string, nodes []*experiment_instance.WorkflowNodesDetail, userName string) error {
+	if !authz.Enabled() {
+		return nil
+	}
+
+	ctx := context.Background()
+	if userName == "" {
+		// scheduled runs are on behalf of the creator
+		userName = experimentGet.CreatorName
+	}
+	req := &authz.Request{
+		Action:         authz.ActionRunExperiment,
+		User:           userName,
+		NamespaceId:    experimentGet.NamespaceID,
+		ExperimentUUID: experimentGet.UUID,
+		ExperimentName: experimentGet.Name,
+		InstanceUUID:   experimentInstanceId,
+	}
+
+	ns := namespaceModel.Namespace{Id: experimentGet.NamespaceID}
+	if err := namespaceModel.GetNamespaceById(ctx, &ns); err != nil {
+		return fmt.Errorf("get namespace[%d] error: %s", experimentGet.NamespaceID, err.Error())
+	}
+	req.NamespaceName = ns.Name
+
+	for _, node := range nodes {
+		if node.ExecType != string(FaultExecType) {
+			continue
+		}
+		fault, err := getAuthzFault(ctx, node)
+		if err != nil {
+			return fmt.Errorf("get fault of node[%s] error: %s", node.UUID, err.Error())
+		}
+		req.Faults = append(req.Faults, *fault)
+	}
+
+	return authz.Authorize(ctx, req)
+}
+
+func getAuthzFault(ctx context.Context, node *experiment_instance.WorkflowNodesDetail) (*authz.Fault, error) {
+	scope, err := basic.GetScopeById(ctx, node.ScopeId)
+	if err != nil {
+		return nil, err
+	}
+	target, err := basic.GetTargetById(ctx, node.TargetId)
+	if err != nil {
+		return nil, err
+	}
+	fault, err := basic.GetFaultById(ctx, node.ExecId)
+	if err != nil {
+		return nil, err
+	}
+
+	authzFault := &authz.Fault{
+		Node:     node.UUID,
+		Scope:    scope.Name,
+		Target:   target.Name,
+		Fault:    fault.Name,
+		Duration: node.Duration,
+		Args:     make(map[string]string),
+	}
+	for _, arg := range node.ArgsValues {
+		argGet, err := basic.GetArgsById(ctx, arg.ArgsId)
+		if err != nil {
+			return nil, err
+		}
+		authzFault.Args[argGet.Key] = arg.Value
+		if VType(argGet.ValueType) == SensitiveVType {
+			authzFault.Args[argGet.Key] = SensitiveMask
+		}
+	}
+	if node.Subtasks != nil {
+		authzFault.TargetNamespace = node.Subtasks.TargetNamespace
+		authzFault.TargetName = node.Subtasks.TargetName
+		authzFault.TargetIP = node.Subtasks.TargetIP
+		authzFault.TargetLabel = node.Subtasks.TargetLabel
+	}
+	return authzFault, nil
+}
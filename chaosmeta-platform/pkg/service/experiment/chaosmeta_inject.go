@@ -38,6 +38,8 @@ const (
 	ExperimentKind      = "Experiment"
 	ExperimentsResource = "experiments"
 	RecoverTargetPhase  = "recover"
+	// InjectTTLSecondsAfterFinished keeps a finished fault experiment for one day before the operator deletes it
+	InjectTTLSecondsAfterFinished int32 = 86400
 )
 
 type ChaosmetaInterface interface {
@@ -202,12 +204,18 @@ func (c *ChaosmetaService) Patch(ctx context.Context, namespace, name string, pt
 	return err
 }
 
+// DeleteExpiredList deletes the fault experiments created before "ttlSecondsAfterFinished" is set, the others are
+// deleted by the inject operator
 func (c *ChaosmetaService) DeleteExpiredList(ctx context.Context, namespace string) error {
 	chaosmetaList, err := c.List(ctx, namespace)
 	if err != nil {
 		return err
 	}
 	for _, experiment := range chaosmetaList.Items {
+		if experiment.Spec.TTLSecondsAfterFinished != nil {
+			continue
+		}
+
 		expirationTime := time.Now().AddDate(0, 0, -1)
 		experimentCreateTime, err := time.Parse(TimeLayout, experiment.Status.CreateTime)
 		if err != nil {
@@ -17,6 +17,7 @@ limitations under the License.
 package experiment
 
 import (
+	"chaosmeta-platform/util/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -52,6 +53,15 @@ type ExperimentSpec struct {
 	Selector []SelectorUnit `json:"selector,omitempty"`
 
 	TargetPhase PhaseType `json:"targetPhase"`
+
+	Mutex *MutexSpec `json:"mutex,omitempty"`
+	// TTLSecondsAfterFinished the experiment is deleted by the operator once it is finished for the seconds
+	TTLSecondsAfterFinished *int32 `json:"ttlSecondsAfterFinished,omitempty"`
+}
+
+type MutexSpec struct {
+	Name   string `json:"name"`
+	Holder string `json:"holder,omitempty"`
 }
 
 type PhaseType string
@@ -150,6 +160,8 @@ type ExperimentDetailUnit struct {
 	StartTime        string     `json:"startTime,omitempty"`
 	UpdateTime       string     `json:"updateTime,omitempty"`
 	Backup           string     `json:"backup,omitempty"`
+	// Error is the machine-readable cause of a failed target set by the operator
+	Error *errors.ErrorInfo `json:"error,omitempty"`
 }
 
 type CloudTargetType string
@@ -0,0 +1,76 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package experiment
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// editSessionTTL an edit session expires if the editing page stops keeping it, such as the browser is closed
+const editSessionTTL = 2 * time.Minute
+
+var (
+	editSessionLock sync.Mutex
+	// editSessions experiment uuid -> user name -> last keep time
+	editSessions = map[string]map[string]time.Time{}
+)
+
+// KeepEditSession starts or renews the edit session of the user, and returns the other users editing the experiment
+func (es *ExperimentService) KeepEditSession(uuid, userName string) []string {
+	editSessionLock.Lock()
+	if editSessions[uuid] == nil {
+		editSessions[uuid] = map[string]time.Time{}
+	}
+	editSessions[uuid][userName] = time.Now()
+	editSessionLock.Unlock()
+
+	return getEditors(uuid, userName)
+}
+
+func (es *ExperimentService) EndEditSession(uuid, userName string) {
+	editSessionLock.Lock()
+	defer editSessionLock.Unlock()
+
+	delete(editSessions[uuid], userName)
+	if len(editSessions[uuid]) == 0 {
+		delete(editSessions, uuid)
+	}
+}
+
+// getEditors returns the users editing the experiment except the given one, expired sessions are cleaned
+func getEditors(uuid, exclude string) []string {
+	editSessionLock.Lock()
+	defer editSessionLock.Unlock()
+
+	var editors []string
+	for userName, keepTime := range editSessions[uuid] {
+		if time.Since(keepTime) > editSessionTTL {
+			delete(editSessions[uuid], userName)
+			continue
+		}
+		if userName != exclude {
+			editors = append(editors, userName)
+		}
+	}
+	if len(editSessions[uuid]) == 0 {
+		delete(editSessions, uuid)
+	}
+
+	sort.Strings(editors)
+	return editors
+}
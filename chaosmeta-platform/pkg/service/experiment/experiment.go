@@ -51,6 +51,14 @@ type ExperimentInfo struct {
 	CreateTime   time.Time `json:"create_time,omitempty"`
 	UpdateTime   time.Time `json:"update_time,omitempty"`
 	LastInstance string    `json:"last_instance,omitempty"`
+	// Mutex at most one experiment holding the same mutex is running at a time, others are queued
+	Mutex string `json:"mutex,omitempty"`
+	// Rollback "namespace/deployment" rolled back to its previous revision when a measure fails during the run
+	Rollback string `json:"rollback,omitempty"`
+	// Draft a draft experiment is never scheduled or started, updating it with draft false publishes it
+	Draft bool `json:"draft,omitempty"`
+	// Version is required by the update, the update is rejected if the experiment has been modified since this version
+	Version *int `json:"version,omitempty"`
 }
 
 type LabelGet struct {
@@ -78,6 +86,11 @@ type ExperimentGet struct {
 	CreatorName   string          `json:"creator_name,omitempty"`
 	Status        int             `json:"status"`
 	LastInstance  string          `json:"last_instance"`
+	Mutex         string          `json:"mutex,omitempty"`
+	Rollback      string          `json:"rollback,omitempty"`
+	Draft         bool            `json:"draft"`
+	Version       int             `json:"version"`
+	Editors       []string        `json:"editors,omitempty"`
 	CreateTime    time.Time       `json:"create_time,omitempty"`
 	UpdateTime    time.Time       `json:"update_time,omitempty"`
 	Labels        []LabelGet      `json:"labels,omitempty"`
@@ -117,6 +130,9 @@ func (es *ExperimentService) CreateExperiment(experimentParam *ExperimentCreate)
 	if experimentParam == nil {
 		return "", errors.New("experimentParam is nil")
 	}
+	if err := validateRollback(experimentParam.Rollback); err != nil {
+		return "", err
+	}
 	experimentUUid := es.createUUID(experimentParam.Creator, "")
 
 	//label
@@ -148,6 +164,9 @@ func (es *ExperimentService) CreateExperiment(experimentParam *ExperimentCreate)
 
 		//args_value
 		if len(node.ArgsValue) > 0 {
+			if err := encryptSensitiveArgs(experimentParam.NamespaceID, node.ArgsValue, nil); err != nil {
+				return "", err
+			}
 			if err := experiment.BatchInsertArgsValues(node.UUID, node.ArgsValue); err != nil {
 				return "", err
 			}
@@ -187,6 +206,11 @@ func (es *ExperimentService) CreateExperiment(experimentParam *ExperimentCreate)
 		ScheduleType: experimentParam.ScheduleType,
 		ScheduleRule: experimentParam.ScheduleRule,
 		Creator:      experimentParam.Creator,
+		Mutex:        experimentParam.Mutex,
+		Rollback:     experimentParam.Rollback,
+	}
+	if experimentParam.Draft {
+		experimentCreate.Status = experiment.Draft
 	}
 	if err := experiment.CreateExperiment(&experimentCreate); err != nil {
 		return "", err
@@ -199,36 +223,31 @@ func (es *ExperimentService) UpdateExperiment(uuid string, experimentParam *Expe
 		return errors.New("experimentParam is nil")
 	}
 	getExperiment, err := experiment.GetExperimentByUUID(uuid)
-	if err != nil {
+	if err != nil || getExperiment == nil {
 		return fmt.Errorf("no this experiment")
 	}
-
-	experimentUUid := getExperiment.UUID
-	log.Error(1)
-	//label
-	if len(experimentParam.Labels) > 0 {
-		if err := experiment.ClearLabelIDsByExperimentUUID(uuid); err != nil {
-			log.Error(err)
-			return err
-		}
-		if err := experiment.AddLabelIDsToExperiment(uuid, experimentParam.Labels); err != nil {
-			log.Error(err)
-			return err
-		}
+	if experimentParam.Version == nil {
+		return errors.New("version is required")
 	}
-
-	if err := experiment.DeleteWorkflowNodeByExperimentUUID(uuid); err != nil {
-		log.Error(err)
+	// check before the workflow nodes are replaced, otherwise the nodes of others are overwritten silently
+	if *experimentParam.Version != getExperiment.Version {
+		return fmt.Errorf("experiment has been modified by others since version %d, current version is %d, please reload it", *experimentParam.Version, getExperiment.Version)
+	}
+	if err := validateRollback(experimentParam.Rollback); err != nil {
 		return err
 	}
 
-	//workflow_nodes
+	// the children are rewritten in the transaction of the version check, which fails if others update it meanwhile
+	children := &experiment.ExperimentChildren{
+		LabelIDs:   experimentParam.Labels,
+		ArgsValues: map[string][]*experiment.ArgsValue{},
+	}
 	for _, node := range experimentParam.WorkflowNodes {
-		node.ExperimentUUID = experimentUUid
-		workflowNodeCreate := experiment.WorkflowNode{
+		node.ExperimentUUID = getExperiment.UUID
+		children.WorkflowNodes = append(children.WorkflowNodes, &experiment.WorkflowNode{
 			UUID:           node.UUID,
 			Name:           node.Name,
-			ExperimentUUID: experimentUUid,
+			ExperimentUUID: getExperiment.UUID,
 			Row:            node.Row,
 			Column:         node.Column,
 			Duration:       node.Duration,
@@ -237,70 +256,54 @@ func (es *ExperimentService) UpdateExperiment(uuid string, experimentParam *Expe
 			ExecType:       node.ExecType,
 			ExecName:       node.ExecName,
 			ExecID:         node.ExecID,
-		}
+		})
 
-		if err := experiment.CreateWorkflowNode(&workflowNodeCreate); err != nil {
-			log.Error(err)
-			return err
-		}
 		//args_value
 		if len(node.ArgsValue) > 0 {
-			if err := experiment.ClearArgsValuesByWorkflowNodeUUID(node.UUID); err != nil {
+			oldArgsValue, err := experiment.GetArgsValuesByWorkflowNodeUUID(node.UUID)
+			if err != nil {
 				log.Error(err)
 				return err
 			}
-			if err := experiment.BatchInsertArgsValues(node.UUID, node.ArgsValue); err != nil {
+			if err := encryptSensitiveArgs(getExperiment.NamespaceID, node.ArgsValue, oldArgsValue); err != nil {
 				log.Error(err)
 				return err
 			}
+			children.ArgsValues[node.UUID] = node.ArgsValue
 		}
 
 		switch node.ExecType {
 		case string(FaultExecType):
 			if node.FaultRange != nil {
-				if err := experiment.ClearFaultRangesByWorkflowNodeInstanceUUID(node.UUID); err != nil {
-					log.Error(err)
-					return err
-				}
 				node.FaultRange.WorkflowNodeInstanceUUID = node.UUID
-				if err := experiment.CreateFaultRange(node.FaultRange); err != nil {
-					return err
-				}
+				children.FaultRanges = append(children.FaultRanges, node.FaultRange)
 			}
 		case string(FlowExecType):
 			if node.FlowRange != nil {
-				if err := experiment.ClearFlowRangesByWorkflowNodeInstanceUUID(node.UUID); err != nil {
-					log.Error(err)
-					return err
-				}
 				node.FlowRange.WorkflowNodeInstanceUUID = node.UUID
-				if err := experiment.CreateFlowRange(node.FlowRange); err != nil {
-					return err
-				}
+				children.FlowRanges = append(children.FlowRanges, node.FlowRange)
 			}
 		case string(MeasureExecType):
 			if node.MeasureRange != nil {
-				if err := experiment.ClearMeasureRangesByWorkflowNodeInstanceUUID(node.UUID); err != nil {
-					log.Error(err)
-					return err
-				}
 				node.MeasureRange.WorkflowNodeInstanceUUID = node.UUID
-				if err := experiment.CreateMeasureRange(node.MeasureRange); err != nil {
-					return err
-				}
+				children.MeasureRanges = append(children.MeasureRanges, node.MeasureRange)
 			}
 		}
 	}
 
-	if getExperiment.ScheduleType != experimentParam.ScheduleType {
+	if experimentParam.Draft {
+		getExperiment.Status = experiment.Draft
+	} else if getExperiment.Status == experiment.Draft || getExperiment.ScheduleType != experimentParam.ScheduleType {
 		getExperiment.Status = experiment.ToBeExecuted
 	}
 	getExperiment.Name = experimentParam.Name
 	getExperiment.Description = experimentParam.Description
 	getExperiment.ScheduleType = experimentParam.ScheduleType
 	getExperiment.ScheduleRule = experimentParam.ScheduleRule
+	getExperiment.Mutex = experimentParam.Mutex
+	getExperiment.Rollback = experimentParam.Rollback
 
-	return experiment.UpdateExperiment(getExperiment)
+	return experiment.UpdateExperimentAndChildren(getExperiment, children)
 	//experimentParam.Creator = getExperiment.Creator
 	//if err := es.DeleteExperimentByUUID(uuid); err != nil {
 	//	return err
@@ -371,6 +374,11 @@ func (es *ExperimentService) GetExperimentByUUID(uuid string) (*ExperimentGet, e
 		Creator:      experimentGet.Creator,
 		Status:       int(experimentGet.Status),
 		LastInstance: experimentGet.LastInstance,
+		Mutex:        experimentGet.Mutex,
+		Rollback:     experimentGet.Rollback,
+		Draft:        experimentGet.Status == experiment.Draft,
+		Version:      experimentGet.Version,
+		Editors:      getEditors(experimentGet.UUID, ""),
 		CreateTime:   experimentGet.CreateTime,
 		UpdateTime:   experimentGet.UpdateTime,
 	}
@@ -63,18 +63,20 @@ const (
 )
 
 func getWorFlowName(experimentInstanceId string) string {
-	return experimentInstanceId
+	return config.DefaultRunOptIns.Naming.WorkflowPrefix + experimentInstanceId
 }
 
-func GetWorkflowStruct(experimentInstanceId string, nodes []*experiment_instance.WorkflowNodesDetail) *v1alpha1.Workflow {
+func GetWorkflowStruct(experimentInstanceId string, nodes []*experiment_instance.WorkflowNodesDetail, meta *WorkflowMetadata) *v1alpha1.Workflow {
 	var newWorkflow = v1alpha1.Workflow{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: "argoproj.io/v1alpha1",
 			Kind:       "Workflow",
 		},
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      "",
-			Namespace: config.DefaultRunOptIns.ArgoWorkflowNamespace,
+			Name:        "",
+			Namespace:   config.DefaultRunOptIns.ArgoWorkflowNamespace,
+			Labels:      meta.GetLabels(),
+			Annotations: meta.GetAnnotations(),
 		},
 		Spec: v1alpha1.WorkflowSpec{
 			ArtifactGC: &v1alpha1.ArtifactGC{
@@ -134,7 +136,7 @@ func GetWorkflowStruct(experimentInstanceId string, nodes []*experiment_instance
 	newWorkflow.Name = getWorFlowName(experimentInstanceId)
 	newWorkflow.Spec.Templates = append(newWorkflow.Spec.Templates, v1alpha1.Template{
 		Name: WorkflowMainStep,
-		DAG:  convertToSteps(experimentInstanceId, nodes),
+		DAG:  convertToSteps(experimentInstanceId, nodes, meta),
 	})
 
 	return &newWorkflow
@@ -172,7 +174,7 @@ func getMeasureStepName(measureType, measureName, experimentInstanceUUID, nodeID
 	return fmt.Sprintf("inject-measure-%s-%s-%s-%s", measureType, measureName, "e", nodeID)
 }
 
-func getFaultStep(experimentInstanceUUID string, node *experiment_instance.WorkflowNodesDetail, phaseType PhaseType) *v1alpha1.DAGTask {
+func getFaultStep(experimentInstanceUUID string, node *experiment_instance.WorkflowNodesDetail, phaseType PhaseType, meta *WorkflowMetadata) *v1alpha1.DAGTask {
 	if node == nil {
 		log.Error("node is nil")
 		return nil
@@ -200,6 +202,7 @@ func getFaultStep(experimentInstanceUUID string, node *experiment_instance.Workf
 	}
 	log.Error(scope.Name, target.Name, experimentInstanceUUID, node.UUID)
 	injectStep.Name = getInjectStepName(scope.Name, target.Name, experimentInstanceUUID, node.UUID)
+	ttlSecondsAfterFinished := InjectTTLSecondsAfterFinished
 	experimentTemplate := ExperimentInjectStruct{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: APIVersion,
@@ -207,8 +210,10 @@ func getFaultStep(experimentInstanceUUID string, node *experiment_instance.Workf
 		},
 
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      injectStep.Name,
-			Namespace: config.DefaultRunOptIns.WorkflowNamespace,
+			Name:        injectStep.Name,
+			Namespace:   config.DefaultRunOptIns.WorkflowNamespace,
+			Labels:      meta.getNodeLabels(node.UUID),
+			Annotations: meta.GetAnnotations(),
 		},
 
 		Spec: ExperimentSpec{
@@ -219,6 +224,8 @@ func getFaultStep(experimentInstanceUUID string, node *experiment_instance.Workf
 				Fault:    fault.Name,
 				Duration: node.Duration,
 			},
+			Mutex:                   meta.getMutex(),
+			TTLSecondsAfterFinished: &ttlSecondsAfterFinished,
 		},
 	}
 	if node.Subtasks != nil {
@@ -253,10 +260,14 @@ func getFaultStep(experimentInstanceUUID string, node *experiment_instance.Workf
 			log.Error(err)
 			return nil
 		}
+		valueType := VType(argGet.ValueType)
+		if valueType == SensitiveVType {
+			valueType = StringVType
+		}
 		experimentTemplate.Spec.Experiment.Args = append(experimentTemplate.Spec.Experiment.Args, ArgsUnit{
 			Key:       argGet.Key,
 			Value:     arg.Value,
-			ValueType: VType(argGet.ValueType),
+			ValueType: valueType,
 		})
 	}
 
@@ -277,7 +288,7 @@ func getFaultStep(experimentInstanceUUID string, node *experiment_instance.Workf
 	return &injectStep
 }
 
-func getFlowStep(experimentInstanceUUID string, node *experiment_instance.WorkflowNodesDetail) *v1alpha1.DAGTask {
+func getFlowStep(experimentInstanceUUID string, node *experiment_instance.WorkflowNodesDetail, meta *WorkflowMetadata) *v1alpha1.DAGTask {
 	if node == nil {
 		log.Error("node is nil")
 		return nil
@@ -302,8 +313,10 @@ func getFlowStep(experimentInstanceUUID string, node *experiment_instance.Workfl
 		},
 
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      injectStep.Name,
-			Namespace: config.DefaultRunOptIns.WorkflowNamespace,
+			Name:        injectStep.Name,
+			Namespace:   config.DefaultRunOptIns.WorkflowNamespace,
+			Labels:      meta.getNodeLabels(node.UUID),
+			Annotations: meta.GetAnnotations(),
 		},
 	}
 
@@ -343,7 +356,7 @@ func getFlowStep(experimentInstanceUUID string, node *experiment_instance.Workfl
 	return &injectStep
 }
 
-func getMeasureStep(experimentInstanceUUID string, node *experiment_instance.WorkflowNodesDetail) *v1alpha1.DAGTask {
+func getMeasureStep(experimentInstanceUUID string, node *experiment_instance.WorkflowNodesDetail, meta *WorkflowMetadata) *v1alpha1.DAGTask {
 	if node == nil {
 		log.Error("node is nil")
 		return nil
@@ -368,8 +381,10 @@ func getMeasureStep(experimentInstanceUUID string, node *experiment_instance.Wor
 		},
 
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      injectStep.Name,
-			Namespace: config.DefaultRunOptIns.WorkflowNamespace,
+			Name:        injectStep.Name,
+			Namespace:   config.DefaultRunOptIns.WorkflowNamespace,
+			Labels:      meta.getNodeLabels(node.UUID),
+			Annotations: meta.GetAnnotations(),
 		},
 	}
 
@@ -427,7 +442,7 @@ func getMeasureStep(experimentInstanceUUID string, node *experiment_instance.Wor
 //	return maxRow, maxColumn
 //}
 
-func getStepArguments(experimentInstanceId string, node *experiment_instance.WorkflowNodesDetail) *v1alpha1.DAGTask {
+func getStepArguments(experimentInstanceId string, node *experiment_instance.WorkflowNodesDetail, meta *WorkflowMetadata) *v1alpha1.DAGTask {
 	if node == nil {
 		return &v1alpha1.DAGTask{}
 	}
@@ -435,17 +450,17 @@ func getStepArguments(experimentInstanceId string, node *experiment_instance.Wor
 	case string(WaitExecType):
 		return getWaitStep(node.Duration, experimentInstanceId, node.UUID)
 	case string(FaultExecType):
-		return getFaultStep(experimentInstanceId, node, InjectPhaseType)
+		return getFaultStep(experimentInstanceId, node, InjectPhaseType, meta)
 	case string(FlowExecType):
-		return getFlowStep(experimentInstanceId, node)
+		return getFlowStep(experimentInstanceId, node, meta)
 	case string(MeasureExecType):
-		return getMeasureStep(experimentInstanceId, node)
+		return getMeasureStep(experimentInstanceId, node, meta)
 	default:
 		return nil
 	}
 }
 
-func convertToSteps(experimentInstanceId string, nodes []*experiment_instance.WorkflowNodesDetail) *v1alpha1.DAGTemplate {
+func convertToSteps(experimentInstanceId string, nodes []*experiment_instance.WorkflowNodesDetail, meta *WorkflowMetadata) *v1alpha1.DAGTemplate {
 	failFast := true
 	dAGTemplate := v1alpha1.DAGTemplate{
 		FailFast: &failFast,
@@ -471,9 +486,9 @@ func convertToSteps(experimentInstanceId string, nodes []*experiment_instance.Wo
 
 	var prevNode *experiment_instance.WorkflowNodesDetail
 	for _, node := range nodes {
-		task := *getStepArguments(experimentInstanceId, node)
+		task := *getStepArguments(experimentInstanceId, node, meta)
 		if prevNode != nil && prevNode.Row != node.Row {
-			//endTask.Dependencies = append(endTask.Dependencies, getStepArguments(experimentInstanceId, prevNode).Name)
+			//endTask.Dependencies = append(endTask.Dependencies, getStepArguments(experimentInstanceId, prevNode, meta).Name)
 			log.Debugf("End of row %d", prevNode.Row)
 			task.Dependencies = []string{"BeginWaitTask"}
 		}
@@ -483,27 +498,25 @@ func convertToSteps(experimentInstanceId string, nodes []*experiment_instance.Wo
 			task.Dependencies = []string{"BeginWaitTask"}
 		}
 		if prevNode != nil && prevNode.Row == node.Row {
-			task.Dependencies = []string{getStepArguments(experimentInstanceId, prevNode).Name}
+			task.Dependencies = []string{getStepArguments(experimentInstanceId, prevNode, meta).Name}
 		}
 
 		steps = append(steps, task)
 		prevNode = node
 
 	}
-	//endTask.Dependencies = append(endTask.Dependencies, getStepArguments(experimentInstanceId, prevNode).Name)
+	//endTask.Dependencies = append(endTask.Dependencies, getStepArguments(experimentInstanceId, prevNode, meta).Name)
 	//steps = append(steps, endTask)
 	dAGTemplate.Tasks = steps
 	return &dAGTemplate
 }
 
+// getExperimentInstanceIdFromWorkflowName strips the configured prefix, which may contain "-" itself
 func getExperimentInstanceIdFromWorkflowName(workflowName string) (string, error) {
-	parts := strings.Split(workflowName, "-")
-	experimentID := ""
-	for i := len(parts) - 1; i >= 0; i-- {
-		if strings.HasSuffix(parts[i], "experiment") {
-			experimentID = parts[i]
-			break
-		}
+	prefix := config.DefaultRunOptIns.Naming.WorkflowPrefix
+	experimentID := strings.TrimPrefix(workflowName, prefix)
+	if !strings.HasPrefix(workflowName, prefix) || !strings.HasSuffix(experimentID, "experiment") || strings.Contains(experimentID, "-") {
+		return "", fmt.Errorf("workflow[%s] is not named by prefix[%s] and experiment instance id", workflowName, prefix)
 	}
 	return experimentID, nil
 }
@@ -0,0 +1,53 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package experiment
+
+import (
+	"chaosmeta-platform/config"
+	"testing"
+)
+
+func TestGetExperimentInstanceIdFromWorkflowName(t *testing.T) {
+	config.DefaultRunOptIns = &config.Config{}
+	defer func() { config.DefaultRunOptIns = nil }()
+
+	tests := []struct {
+		name    string
+		prefix  string
+		wfName  string
+		want    string
+		wantErr bool
+	}{
+		{name: "no prefix", wfName: "1234experiment", want: "1234experiment"},
+		{name: "prefix with dash", prefix: "team-a-", wfName: "team-a-1234experiment", want: "1234experiment"},
+		{name: "prefix ending with experiment", prefix: "experiment-", wfName: "experiment-1234experiment", want: "1234experiment"},
+		{name: "other prefix", prefix: "team-a-", wfName: "team-b-1234experiment", wantErr: true},
+		{name: "not an instance id", prefix: "team-a-", wfName: "team-a-1234", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config.DefaultRunOptIns.Naming.WorkflowPrefix = tt.prefix
+			got, err := getExperimentInstanceIdFromWorkflowName(tt.wfName)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("getExperimentInstanceIdFromWorkflowName() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("getExperimentInstanceIdFromWorkflowName() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
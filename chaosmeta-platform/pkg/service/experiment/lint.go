@@ -0,0 +1,358 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package experiment
+
+import (
+	"chaosmeta-platform/pkg/models/experiment"
+	namespaceModel "chaosmeta-platform/pkg/models/namespace"
+	"chaosmeta-platform/util/log"
+	"context"
+	"fmt"
+	"github.com/robfig/cron"
+	"strings"
+	"time"
+	"unicode"
+)
+
+type LintSeverity string
+
+const (
+	LintError   LintSeverity = "error"
+	LintWarning LintSeverity = "warning"
+	LintInfo    LintSeverity = "info"
+
+	// scheduleLintWindow is how far ahead the cron runs are checked for overlaps
+	scheduleLintWindow = 7 * 24 * time.Hour
+	// maxScheduleRuns bounds the runs checked in the window for very frequent schedules
+	maxScheduleRuns = 500
+)
+
+// productionKeywords are the tokens of a namespace or label name that mark a production environment
+var productionKeywords = []string{"prod", "production", "prd", "online"}
+
+type LintFinding struct {
+	Rule     string       `json:"rule"`
+	Severity LintSeverity `json:"severity"`
+	Message  string       `json:"message"`
+	NodeUUID string       `json:"node_uuid,omitempty"`
+	NodeName string       `json:"node_name,omitempty"`
+}
+
+type LintResult struct {
+	// Passed is false if there is any finding of error severity
+	Passed   bool          `json:"passed"`
+	Findings []LintFinding `json:"findings"`
+}
+
+// lintSchedule is a cron experiment of the namespace, Duration is the time its workflow takes
+type lintSchedule struct {
+	UUID     string
+	Name     string
+	Rule     string
+	Duration time.Duration
+}
+
+type lintInput struct {
+	UUID          string
+	ScheduleType  string
+	ScheduleRule  string
+	Labels        []string
+	WorkflowNodes []*WorkflowNode
+	// Schedules are the other cron experiments of the namespace
+	Schedules []lintSchedule
+}
+
+// LintExperiment reviews an experiment definition which may not be saved yet
+func (es *ExperimentService) LintExperiment(experimentParam *ExperimentCreate) (*LintResult, error) {
+	if experimentParam == nil {
+		return nil, fmt.Errorf("experiment is nil")
+	}
+	in := &lintInput{
+		UUID:          experimentParam.UUID,
+		ScheduleType:  experimentParam.ScheduleType,
+		ScheduleRule:  experimentParam.ScheduleRule,
+		WorkflowNodes: experimentParam.WorkflowNodes,
+	}
+	for _, labelId := range experimentParam.Labels {
+		label := namespaceModel.Label{Id: labelId}
+		if err := namespaceModel.GetLabelById(context.Background(), &label); err != nil {
+			log.Warnf("get label[%d] error: %s", labelId, err.Error())
+			continue
+		}
+		in.Labels = append(in.Labels, label.Name)
+	}
+	schedules, err := listLintSchedules(experimentParam.NamespaceID, experimentParam.UUID)
+	if err != nil {
+		return nil, err
+	}
+	in.Schedules = schedules
+	return lintExperiment(in, time.Now()), nil
+}
+
+// LintExperimentByUUID reviews a saved experiment
+func (es *ExperimentService) LintExperimentByUUID(uuid string) (*LintResult, error) {
+	experimentGet, err := es.GetExperimentByUUID(uuid)
+	if err != nil {
+		return nil, err
+	}
+	in := &lintInput{
+		UUID:          experimentGet.UUID,
+		ScheduleType:  experimentGet.ScheduleType,
+		ScheduleRule:  experimentGet.ScheduleRule,
+		WorkflowNodes: experimentGet.WorkflowNodes,
+	}
+	for _, label := range experimentGet.Labels {
+		in.Labels = append(in.Labels, label.Name)
+	}
+	schedules, err := listLintSchedules(experimentGet.NamespaceID, experimentGet.UUID)
+	if err != nil {
+		return nil, err
+	}
+	in.Schedules = schedules
+	return lintExperiment(in, time.Now()), nil
+}
+
+func listLintSchedules(namespaceId int, excludeUUID string) ([]lintSchedule, error) {
+	experiments, err := experiment.ListExperimentsByNamespaceId(namespaceId)
+	if err != nil {
+		return nil, err
+	}
+	var schedules []lintSchedule
+	for _, e := range experiments {
+		if e.UUID == excludeUUID || e.ScheduleType != string(experiment.CronMode) || e.Status == experiment.Draft {
+			continue
+		}
+		nodes, err := experiment.GetWorkflowNodesByExperimentUUID(e.UUID)
+		if err != nil {
+			return nil, err
+		}
+		var durations []durationNode
+		for _, node := range nodes {
+			durations = append(durations, durationNode{Row: node.Row, Duration: node.Duration})
+		}
+		schedules = append(schedules, lintSchedule{UUID: e.UUID, Name: e.Name, Rule: e.ScheduleRule, Duration: workflowDuration(durations)})
+	}
+	return schedules, nil
+}
+
+func lintExperiment(in *lintInput, now time.Time) *LintResult {
+	var findings []LintFinding
+	findings = append(findings, lintGuardMetric(in)...)
+	findings = append(findings, lintDurations(in)...)
+	findings = append(findings, lintBlastRadius(in)...)
+	findings = append(findings, lintSchedules(in, now)...)
+
+	result := &LintResult{Passed: true, Findings: findings}
+	for _, finding := range findings {
+		if finding.Severity == LintError {
+			result.Passed = false
+		}
+	}
+	if result.Findings == nil {
+		result.Findings = []LintFinding{}
+	}
+	return result
+}
+
+// lintGuardMetric flags faults without any measure node to tell whether the system is still healthy
+func lintGuardMetric(in *lintInput) []LintFinding {
+	var faults, measures int
+	for _, node := range in.WorkflowNodes {
+		switch ExecType(node.ExecType) {
+		case FaultExecType:
+			faults++
+		case MeasureExecType:
+			measures++
+		}
+	}
+	if faults == 0 || measures > 0 {
+		return nil
+	}
+	return []LintFinding{{
+		Rule:     "no-guard-metric",
+		Severity: LintWarning,
+		Message:  "the experiment injects faults without any measure node, the impact can not be detected nor stopped automatically",
+	}}
+}
+
+// lintDurations flags the faults which are never recovered automatically
+func lintDurations(in *lintInput) []LintFinding {
+	var findings []LintFinding
+	for _, node := range in.WorkflowNodes {
+		if ExecType(node.ExecType) != FaultExecType && ExecType(node.ExecType) != WaitExecType {
+			continue
+		}
+		if node.Duration == "" {
+			findings = append(findings, nodeFinding(node, "missing-duration", LintError, "no duration is set, the fault is not recovered until the experiment is stopped"))
+			continue
+		}
+		d, err := time.ParseDuration(node.Duration)
+		if err != nil {
+			findings = append(findings, nodeFinding(node, "invalid-duration", LintError, fmt.Sprintf("duration[%s] is invalid: %s", node.Duration, err.Error())))
+			continue
+		}
+		if d <= 0 {
+			findings = append(findings, nodeFinding(node, "missing-duration", LintError, fmt.Sprintf("duration[%s] must be positive", node.Duration)))
+		}
+	}
+	return findings
+}
+
+// lintBlastRadius flags the faults hitting every target of the namespace, which is an error in production
+func lintBlastRadius(in *lintInput) []LintFinding {
+	prodLabel := false
+	for _, label := range in.Labels {
+		if isProductionName(label) {
+			prodLabel = true
+		}
+	}
+
+	var findings []LintFinding
+	for _, node := range in.WorkflowNodes {
+		if ExecType(node.ExecType) != FaultExecType {
+			continue
+		}
+		r := node.FaultRange
+		if r != nil && r.RangeType != string(AllRangeType) &&
+			(r.TargetName != "" || r.TargetIP != "" || r.TargetHostname != "" || r.TargetLabel != "" || r.TargetApp != "") {
+			continue
+		}
+
+		var targetNamespace string
+		if r != nil {
+			targetNamespace = r.TargetNamespace
+		}
+		if prodLabel || isProductionName(targetNamespace) {
+			findings = append(findings, nodeFinding(node, "full-blast-radius-in-prod", LintError,
+				fmt.Sprintf("the fault hits 100%% of the targets in production namespace[%s], restrict it by name, ip or label", targetNamespace)))
+		} else {
+			findings = append(findings, nodeFinding(node, "full-blast-radius", LintInfo,
+				fmt.Sprintf("the fault hits 100%% of the targets in namespace[%s]", targetNamespace)))
+		}
+	}
+	return findings
+}
+
+// lintSchedules flags an invalid cron rule, and the runs overlapping with the other cron experiments in the coming week
+func lintSchedules(in *lintInput, now time.Time) []LintFinding {
+	if in.ScheduleType != string(experiment.CronMode) {
+		return nil
+	}
+	schedule, err := cron.Parse(in.ScheduleRule)
+	if err != nil {
+		return []LintFinding{{
+			Rule:     "invalid-schedule",
+			Severity: LintError,
+			Message:  fmt.Sprintf("schedule rule[%s] is invalid: %s", in.ScheduleRule, err.Error()),
+		}}
+	}
+
+	var durations []durationNode
+	for _, node := range in.WorkflowNodes {
+		durations = append(durations, durationNode{Row: node.Row, Duration: node.Duration})
+	}
+	runs := scheduleRuns(schedule, workflowDuration(durations), now)
+
+	var findings []LintFinding
+	for _, other := range in.Schedules {
+		otherSchedule, err := cron.Parse(other.Rule)
+		if err != nil {
+			continue
+		}
+		if at, ok := firstOverlap(runs, scheduleRuns(otherSchedule, other.Duration, now)); ok {
+			findings = append(findings, LintFinding{
+				Rule:     "overlapping-schedule",
+				Severity: LintWarning,
+				Message:  fmt.Sprintf("the runs overlap with cron experiment %s[%s] at %s", other.Name, other.UUID, at.Format(TimeLayout)),
+			})
+		}
+	}
+	return findings
+}
+
+type durationNode struct {
+	Row      int
+	Duration string
+}
+
+type timeWindow struct {
+	Start time.Time
+	End   time.Time
+}
+
+// workflowDuration is the longest row, the rows of a workflow run in parallel and the nodes of a row one by one
+func workflowDuration(nodes []durationNode) time.Duration {
+	rows := make(map[int]time.Duration)
+	var longest time.Duration
+	for _, node := range nodes {
+		d, _ := time.ParseDuration(node.Duration)
+		if d < time.Second {
+			d = time.Second
+		}
+		rows[node.Row] += d
+		if rows[node.Row] > longest {
+			longest = rows[node.Row]
+		}
+	}
+	return longest
+}
+
+func scheduleRuns(schedule cron.Schedule, duration time.Duration, now time.Time) []timeWindow {
+	var runs []timeWindow
+	end := now.Add(scheduleLintWindow)
+	for t := schedule.Next(now); !t.IsZero() && t.Before(end) && len(runs) < maxScheduleRuns; t = schedule.Next(t) {
+		runs = append(runs, timeWindow{Start: t, End: t.Add(duration)})
+	}
+	return runs
+}
+
+// firstOverlap returns the start of the first overlap of two sorted lists of runs
+func firstOverlap(a, b []timeWindow) (time.Time, bool) {
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		if a[i].Start.Before(b[j].End) && b[j].Start.Before(a[i].End) {
+			if a[i].Start.After(b[j].Start) {
+				return a[i].Start, true
+			}
+			return b[j].Start, true
+		}
+		if a[i].End.Before(b[j].End) {
+			i++
+		} else {
+			j++
+		}
+	}
+	return time.Time{}, false
+}
+
+func isProductionName(name string) bool {
+	tokens := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, token := range tokens {
+		for _, keyword := range productionKeywords {
+			if token == keyword {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func nodeFinding(node *WorkflowNode, rule string, severity LintSeverity, message string) LintFinding {
+	return LintFinding{Rule: rule, Severity: severity, Message: message, NodeUUID: node.UUID, NodeName: node.Name}
+}
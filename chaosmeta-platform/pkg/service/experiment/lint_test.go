@@ -0,0 +1,105 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package experiment
+
+import (
+	"chaosmeta-platform/pkg/models/experiment"
+	"testing"
+	"time"
+)
+
+func TestLintExperiment(t *testing.T) {
+	now := time.Date(2023, 6, 1, 0, 0, 0, 0, time.Local)
+	node := func(execType, duration string, faultRange *experiment.FaultRange) *WorkflowNode {
+		n := &WorkflowNode{FaultRange: faultRange}
+		n.ExecType, n.Duration = execType, duration
+		return n
+	}
+	restricted := &experiment.FaultRange{TargetNamespace: "prod-pay", TargetName: "pay-0"}
+
+	tests := []struct {
+		name       string
+		in         *lintInput
+		wantRules  []string
+		wantPassed bool
+	}{
+		{
+			name:       "safe",
+			in:         &lintInput{WorkflowNodes: []*WorkflowNode{node("fault", "10m", restricted), node("measure", "10m", nil)}},
+			wantPassed: true,
+		},
+		{
+			name:       "no guard metric",
+			in:         &lintInput{WorkflowNodes: []*WorkflowNode{node("fault", "10m", restricted)}},
+			wantRules:  []string{"no-guard-metric"},
+			wantPassed: true,
+		},
+		{
+			name:      "missing duration",
+			in:        &lintInput{WorkflowNodes: []*WorkflowNode{node("fault", "", restricted), node("wait", "abc", nil), node("measure", "", nil)}},
+			wantRules: []string{"missing-duration", "invalid-duration"},
+		},
+		{
+			name:      "all targets in prod namespace",
+			in:        &lintInput{WorkflowNodes: []*WorkflowNode{node("fault", "10m", &experiment.FaultRange{TargetNamespace: "pay-prod"}), node("measure", "10m", nil)}},
+			wantRules: []string{"full-blast-radius-in-prod"},
+		},
+		{
+			name:      "all targets with prod label",
+			in:        &lintInput{Labels: []string{"Production"}, WorkflowNodes: []*WorkflowNode{node("fault", "10m", &experiment.FaultRange{TargetNamespace: "pay", RangeType: "all", TargetName: "pay-0"}), node("measure", "10m", nil)}},
+			wantRules: []string{"full-blast-radius-in-prod"},
+		},
+		{
+			name:       "all targets out of prod",
+			in:         &lintInput{WorkflowNodes: []*WorkflowNode{node("fault", "10m", &experiment.FaultRange{TargetNamespace: "product-test"}), node("measure", "10m", nil)}},
+			wantRules:  []string{"full-blast-radius"},
+			wantPassed: true,
+		},
+		{
+			name:      "invalid schedule",
+			in:        &lintInput{ScheduleType: "cron", ScheduleRule: "every day", WorkflowNodes: []*WorkflowNode{node("fault", "10m", restricted), node("measure", "10m", nil)}},
+			wantRules: []string{"invalid-schedule"},
+		},
+		{
+			name: "overlapping schedules",
+			in: &lintInput{ScheduleType: "cron", ScheduleRule: "0 0 2 * * *", WorkflowNodes: []*WorkflowNode{node("fault", "30m", restricted), node("measure", "30m", nil)},
+				Schedules: []lintSchedule{{UUID: "a", Name: "a", Rule: "0 20 2 * * *", Duration: 5 * time.Minute}, {UUID: "b", Name: "b", Rule: "0 0 3 * * *", Duration: time.Hour}}},
+			wantRules:  []string{"overlapping-schedule"},
+			wantPassed: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := lintExperiment(tt.in, now)
+			if result.Passed != tt.wantPassed {
+				t.Errorf("passed = %v, want %v, findings %+v", result.Passed, tt.wantPassed, result.Findings)
+			}
+			var rules []string
+			for _, finding := range result.Findings {
+				rules = append(rules, finding.Rule)
+			}
+			if len(rules) != len(tt.wantRules) {
+				t.Fatalf("rules = %v, want %v", rules, tt.wantRules)
+			}
+			for i := range rules {
+				if rules[i] != tt.wantRules[i] {
+					t.Errorf("rules = %v, want %v", rules, tt.wantRules)
+				}
+			}
+		})
+	}
+}
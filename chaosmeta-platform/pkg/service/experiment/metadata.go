@@ -0,0 +1,154 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package experiment
+
+import (
+	"chaosmeta-platform/config"
+	namespaceModel "chaosmeta-platform/pkg/models/namespace"
+	"chaosmeta-platform/util/log"
+	"context"
+	"fmt"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"strconv"
+	"strings"
+)
+
+const (
+	ManagedByValue = "chaosmeta-platform"
+
+	managedByLabel            = "app.kubernetes.io/managed-by"
+	experimentUUIDKey         = "experiment-uuid"
+	experimentInstanceUUIDKey = "experiment-instance-uuid"
+	namespaceIdKey            = "namespace-id"
+	workflowNodeUUIDKey       = "workflow-node-uuid"
+	experimentNameKey         = "experiment-name"
+	namespaceNameKey          = "namespace"
+	creatorKey                = "creator"
+	mutexKey                  = "mutex"
+	labelsKey                 = "labels"
+	platformLabelPrefix       = "label."
+)
+
+// WorkflowMetadata is the platform metadata propagated onto the argo workflow and the CRs created by it,
+// so that the cluster-side objects can be traced back and selected by external tooling
+type WorkflowMetadata struct {
+	ExperimentUUID         string
+	ExperimentName         string
+	ExperimentInstanceUUID string
+	NamespaceId            int
+	NamespaceName          string
+	Creator                string
+	Mutex                  string
+	Labels                 []string
+}
+
+func newWorkflowMetadata(experimentGet *ExperimentGet, experimentInstanceId, creatorName string) *WorkflowMetadata {
+	meta := &WorkflowMetadata{
+		ExperimentUUID:         experimentGet.UUID,
+		ExperimentName:         experimentGet.Name,
+		ExperimentInstanceUUID: experimentInstanceId,
+		NamespaceId:            experimentGet.NamespaceID,
+		Creator:                creatorName,
+		Mutex:                  experimentGet.Mutex,
+	}
+	if meta.Creator == "" {
+		meta.Creator = experimentGet.CreatorName
+	}
+
+	namespace := namespaceModel.Namespace{Id: experimentGet.NamespaceID}
+	if err := namespaceModel.GetNamespaceById(context.Background(), &namespace); err != nil {
+		log.Warnf("get namespace[%d] error: %s", experimentGet.NamespaceID, err.Error())
+	} else {
+		meta.NamespaceName = namespace.Name
+	}
+
+	for _, label := range experimentGet.Labels {
+		meta.Labels = append(meta.Labels, label.Name)
+	}
+	return meta
+}
+
+func metadataKey(key string) string {
+	return fmt.Sprintf("%s/%s", config.DefaultRunOptIns.Naming.LabelDomain, key)
+}
+
+// GetLabels only contains values that are valid label values, the others are put into annotations.
+// A platform label becomes a "label.<domain>/<name>" label when the name is a valid label key
+func (m *WorkflowMetadata) GetLabels() map[string]string {
+	if m == nil {
+		return nil
+	}
+
+	labels := map[string]string{
+		managedByLabel:                         ManagedByValue,
+		metadataKey(experimentUUIDKey):         m.ExperimentUUID,
+		metadataKey(experimentInstanceUUIDKey): m.ExperimentInstanceUUID,
+		metadataKey(namespaceIdKey):            strconv.Itoa(m.NamespaceId),
+	}
+	if m.Mutex != "" && len(validation.IsValidLabelValue(m.Mutex)) == 0 {
+		labels[metadataKey(mutexKey)] = m.Mutex
+	}
+	for _, label := range m.Labels {
+		key := platformLabelPrefix + metadataKey(label)
+		if len(validation.IsQualifiedName(key)) == 0 {
+			labels[key] = "true"
+		}
+	}
+
+	for k, v := range labels {
+		if len(validation.IsValidLabelValue(v)) != 0 {
+			log.Warnf("label[%s] value[%s] is invalid, skip it", k, v)
+			delete(labels, k)
+		}
+	}
+	return labels
+}
+
+// getMutex lets the operator keep the mutex as well, the fault nodes of one instance share it
+func (m *WorkflowMetadata) getMutex() *MutexSpec {
+	if m == nil || m.Mutex == "" {
+		return nil
+	}
+
+	return &MutexSpec{Name: m.Mutex, Holder: m.ExperimentInstanceUUID}
+}
+
+func (m *WorkflowMetadata) GetAnnotations() map[string]string {
+	if m == nil {
+		return nil
+	}
+
+	return map[string]string{
+		metadataKey(experimentNameKey): m.ExperimentName,
+		metadataKey(namespaceNameKey):  m.NamespaceName,
+		metadataKey(creatorKey):        m.Creator,
+		metadataKey(labelsKey):         strings.Join(m.Labels, ","),
+	}
+}
+
+// getNodeLabels adds the workflow node to the labels of the CR created for it
+func (m *WorkflowMetadata) getNodeLabels(nodeUUID string) map[string]string {
+	labels := m.GetLabels()
+	if labels == nil {
+		return nil
+	}
+
+	if len(validation.IsValidLabelValue(nodeUUID)) == 0 {
+		labels[metadataKey(workflowNodeUUIDKey)] = nodeUUID
+	}
+	return labels
+}
@@ -0,0 +1,96 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package experiment
+
+import (
+	experimentInstanceModel "chaosmeta-platform/pkg/models/experiment_instance"
+	"chaosmeta-platform/util/log"
+	"fmt"
+	"sync"
+)
+
+// experimentMutexLock serializes checking and taking the mutexes of experiments
+var experimentMutexLock sync.Mutex
+
+// getMutexWaitMessage returns why the instance has to wait for the mutex, empty means it can run.
+// The mutex is held by the pending and running instances, and the queued instances run in creation order
+func getMutexWaitMessage(mutex, experimentInstanceId string) (string, error) {
+	instances, err := experimentInstanceModel.ListExperimentInstancesByMutex(mutex, []string{WorkflowPending, WorkflowRunning, WorkflowQueued})
+	if err != nil {
+		return "", fmt.Errorf("list instances of mutex[%s] error: %s", mutex, err.Error())
+	}
+
+	var queuedBefore string
+	for _, instance := range instances {
+		if instance.UUID == experimentInstanceId {
+			if queuedBefore != "" {
+				return fmt.Sprintf("waiting for mutex[%s] queued by experiment instance %s", mutex, queuedBefore), nil
+			}
+			continue
+		}
+
+		switch instance.Status {
+		case WorkflowPending, WorkflowRunning:
+			return fmt.Sprintf("waiting for mutex[%s] held by experiment instance %s", mutex, instance.UUID), nil
+		case WorkflowQueued:
+			if queuedBefore == "" {
+				queuedBefore = instance.UUID
+			}
+		}
+	}
+
+	return "", nil
+}
+
+// DealQueuedExperiment starts the queued instances whose mutex is released
+func (e *ExperimentRoutine) DealQueuedExperiment() {
+	instances, err := experimentInstanceModel.ListMutexExperimentInstancesByStatus(WorkflowQueued)
+	if err != nil {
+		log.Error(err)
+		return
+	}
+
+	for _, instance := range instances {
+		if err := startQueuedExperimentInstance(instance); err != nil {
+			log.Errorf("start queued experiment instance[%s] error: %s", instance.UUID, err.Error())
+		}
+	}
+}
+
+func startQueuedExperimentInstance(instance *experimentInstanceModel.ExperimentInstance) error {
+	experimentMutexLock.Lock()
+	defer experimentMutexLock.Unlock()
+
+	waitMsg, err := getMutexWaitMessage(instance.Mutex, instance.UUID)
+	if err != nil || waitMsg != "" {
+		return err
+	}
+
+	experimentService := ExperimentService{}
+	experimentGet, err := experimentService.GetExperimentByUUID(instance.ExperimentUUID)
+	if err != nil {
+		if uErr := experimentInstanceModel.UpdateExperimentInstanceStatus(instance.UUID, WorkflowFailed, fmt.Sprintf("get experiment error: %s", err.Error())); uErr != nil {
+			log.Error(uErr)
+		}
+		return err
+	}
+
+	if err := experimentInstanceModel.UpdateExperimentInstanceStatus(instance.UUID, WorkflowPending, fmt.Sprintf("mutex[%s] acquired", instance.Mutex)); err != nil {
+		return err
+	}
+	return launchExperimentInstance(experimentGet, instance.UUID, "")
+}
@@ -0,0 +1,97 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package experiment
+
+import (
+	"chaosmeta-platform/config"
+	experimentModel "chaosmeta-platform/pkg/models/experiment"
+	experimentInstanceModel "chaosmeta-platform/pkg/models/experiment_instance"
+	"chaosmeta-platform/pkg/service/cluster"
+	"chaosmeta-platform/pkg/service/kubernetes"
+	"chaosmeta-platform/pkg/service/kubernetes/kube"
+	"chaosmeta-platform/util/log"
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// rollingBack the failed workflow is synced repeatedly, an instance is only rolled back once
+var rollingBack sync.Map
+
+func validateRollback(rollback string) error {
+	if rollback == "" {
+		return nil
+	}
+
+	if _, _, err := parseRollback(rollback); err != nil {
+		return err
+	}
+	return nil
+}
+
+func parseRollback(rollback string) (string, string, error) {
+	parts := strings.Split(rollback, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("rollback[%s] must be \"namespace/deployment\"", rollback)
+	}
+	return parts[0], parts[1], nil
+}
+
+// rollbackOnMeasureFailure rolls the deployment of the experiment back to its previous revision and records the action
+// in the instance, a failed measure means the guarded service does not tolerate the fault
+func rollbackOnMeasureFailure(experimentInstanceId string) {
+	if _, loaded := rollingBack.LoadOrStore(experimentInstanceId, struct{}{}); loaded {
+		return
+	}
+
+	instance, err := experimentInstanceModel.GetExperimentInstanceByUUID(experimentInstanceId)
+	if err != nil || instance == nil || instance.PostAction != "" {
+		return
+	}
+
+	experimentGet, err := experimentModel.GetExperimentByUUID(instance.ExperimentUUID)
+	if err != nil || experimentGet == nil || experimentGet.Rollback == "" {
+		return
+	}
+
+	postAction := rollbackDeployment(experimentGet.Rollback)
+	log.Infof("experiment instance[%s] post action: %s", experimentInstanceId, postAction)
+	if err := experimentInstanceModel.UpdateExperimentInstancePostAction(experimentInstanceId, postAction); err != nil {
+		log.Error(err)
+	}
+}
+
+func rollbackDeployment(rollback string) string {
+	namespace, name, err := parseRollback(rollback)
+	if err != nil {
+		return fmt.Sprintf("rollback failed: %s", err.Error())
+	}
+
+	clusterService := cluster.ClusterService{}
+	kubeClient, restConfig, err := clusterService.GetRestConfig(context.Background(), config.DefaultRunOptIns.RunMode.Int())
+	if err != nil {
+		return fmt.Sprintf("rollback deployment %s failed: %s", rollback, err.Error())
+	}
+
+	deploymentService := kube.NewDeploymentService(&kubernetes.KubernetesParam{RestConfig: restConfig, KubernetesClient: kubeClient})
+	fromRevision, toRevision, err := deploymentService.Rollback(namespace, name)
+	if err != nil {
+		return fmt.Sprintf("rollback deployment %s failed: %s", rollback, err.Error())
+	}
+
+	return fmt.Sprintf("rolled back deployment %s from revision %d to %d", rollback, fromRevision, toRevision)
+}
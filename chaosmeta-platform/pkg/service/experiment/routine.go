@@ -43,6 +43,7 @@ const (
 	WorkflowSucceeded = "Succeeded"
 	WorkflowFailed    = "Failed" // it maybe that the workflow was terminated
 	WorkflowError     = "Error"
+	WorkflowQueued    = "Queued" // waiting for the mutex of the experiment, the workflow is not created yet
 )
 
 type ExperimentRoutine struct {
@@ -94,6 +95,7 @@ func convertToExperimentInstance(experiment *ExperimentGet, status string) *expe
 			Description: experiment.Description,
 			Creator:     experiment.Creator,
 			NamespaceId: experiment.NamespaceID,
+			Mutex:       experiment.Mutex,
 			Status:      status,
 		},
 		Labels: getLabelIdsFromLabelGet(experiment.Labels),
@@ -135,6 +137,9 @@ func StartExperiment(experimentID string, creatorName string) error {
 	if err != nil || experimentGet == nil {
 		return fmt.Errorf("error %v", err)
 	}
+	if experimentGet.Draft {
+		return fmt.Errorf("experiment[%s] is a draft, publish it before running", experimentID)
+	}
 
 	experimentInstance := convertToExperimentInstance(experimentGet, string(experimentInstanceModel.Running))
 	if creatorName != "" {
@@ -151,24 +156,65 @@ func StartExperiment(experimentID string, creatorName string) error {
 		return err
 	}
 
-	clusterService := cluster.ClusterService{}
-	_, restConfig, err := clusterService.GetRestConfig(context.Background(), config.DefaultRunOptIns.RunMode.Int())
+	if experimentGet.Mutex != "" {
+		experimentMutexLock.Lock()
+		defer experimentMutexLock.Unlock()
+
+		waitMsg, err := getMutexWaitMessage(experimentGet.Mutex, experimentInstanceId)
+		if err != nil {
+			return err
+		}
+		if waitMsg != "" {
+			return experimentInstanceModel.UpdateExperimentInstanceStatus(experimentInstanceId, WorkflowQueued, waitMsg)
+		}
+	}
+
+	return launchExperimentInstance(experimentGet, experimentInstanceId, creatorName)
+}
+
+// launchExperimentInstance creates the workflow of the instance, the instance fails if the workflow can not be created,
+// so that it never holds its mutex forever
+func launchExperimentInstance(experimentGet *ExperimentGet, experimentInstanceId string, creatorName string) error {
+	err := createWorkflow(experimentGet, experimentInstanceId, creatorName)
 	if err != nil {
+		if uErr := experimentInstanceModel.UpdateExperimentInstanceStatus(experimentInstanceId, WorkflowFailed, fmt.Sprintf("create workflow error: %s", err.Error())); uErr != nil {
+			log.Error(uErr)
+		}
+	}
+	return err
+}
+
+func createWorkflow(experimentGet *ExperimentGet, experimentInstanceId string, creatorName string) error {
+	experimentInstanceService := experiment_instance.ExperimentInstanceService{}
+	nodes, err := experimentInstanceService.GetWorkflowNodeInstanceDetailList(experimentInstanceId)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+	if err := authorizeRun(experimentGet, experimentInstanceId, nodes, creatorName); err != nil {
+		return err
+	}
+	if err := decryptSensitiveArgs(experimentGet.NamespaceID, nodes); err != nil {
+		log.Error(err)
 		return err
 	}
 
-	argoWorkFlowCtl, err := NewArgoWorkFlowService(restConfig, config.DefaultRunOptIns.ArgoWorkflowNamespace)
+	if config.DefaultRunOptIns.RunMode.IsStandalone() {
+		return startStandaloneExperiment(experimentInstanceId, nodes)
+	}
+
+	clusterService := cluster.ClusterService{}
+	_, restConfig, err := clusterService.GetRestConfig(context.Background(), config.DefaultRunOptIns.RunMode.Int())
 	if err != nil {
 		return err
 	}
 
-	nodes, err := experimentInstanceService.GetWorkflowNodeInstanceDetailList(experimentInstanceId)
+	argoWorkFlowCtl, err := NewArgoWorkFlowService(restConfig, config.DefaultRunOptIns.ArgoWorkflowNamespace)
 	if err != nil {
-		log.Error(err)
 		return err
 	}
 
-	_, err = argoWorkFlowCtl.Create(*GetWorkflowStruct(experimentInstanceId, nodes))
+	_, err = argoWorkFlowCtl.Create(*GetWorkflowStruct(experimentInstanceId, nodes, newWorkflowMetadata(experimentGet, experimentInstanceId, creatorName)))
 	return err
 }
 
@@ -277,6 +323,10 @@ func injectRecoverByArgo(node v1alpha1.NodeStatus, experimentStatus *string, res
 }
 
 func stopExperiment(experimentInstanceID string, experimentStatus *string, tolerateFailure bool) error {
+	if config.DefaultRunOptIns.RunMode.IsStandalone() {
+		return stopStandaloneExperiment(experimentInstanceID)
+	}
+
 	clusterService := cluster.ClusterService{}
 	_, restConfig, err := clusterService.GetRestConfig(context.Background(), config.DefaultRunOptIns.RunMode.Int())
 	if err != nil {
@@ -321,6 +371,10 @@ func StopExperiment(experimentInstanceID string, tolerateFailure bool) error {
 	if err != nil || experimentInstanceInfo == nil {
 		return fmt.Errorf("can not find experimentInstance")
 	}
+	// a queued instance has no workflow yet
+	if experimentInstanceInfo.Status == WorkflowQueued {
+		return experimentInstanceModel.UpdateExperimentInstanceStatus(experimentInstanceID, WorkflowFailed, "stopped while waiting for mutex")
+	}
 	var experimentStatus = WorkflowSucceeded
 	if err := stopExperiment(experimentInstanceID, &experimentStatus, tolerateFailure); err != nil {
 		log.Error("stopExperiment error:", err)
@@ -439,6 +493,9 @@ func (e *ExperimentRoutine) syncExperimentStatusByWorkflow(workflow v1alpha1.Wor
 				continue
 			}
 			if node.Phase == v1alpha1.NodeFailed || node.Phase == v1alpha1.NodeError {
+				if injectType, _ := getInjectSecondField(node.DisplayName); injectType == string(MeasureExecType) {
+					rollbackOnMeasureFailure(experimentInstanceId)
+				}
 				return StopExperiment(experimentInstanceId, true)
 			}
 
@@ -517,6 +574,7 @@ func (e *ExperimentRoutine) DeleteExecutedInstanceCR() {
 	}
 	log.Info("expired workflows have been deleted successfully.")
 
+	// the fault experiments with "ttlSecondsAfterFinished" are deleted by the inject operator
 	ctx := context.Background()
 	chaosmetaService := NewChaosmetaService(restConfig)
 	if err := chaosmetaService.DeleteExpiredList(ctx, config.DefaultRunOptIns.WorkflowNamespace); err != nil {
@@ -536,6 +594,11 @@ func (e *ExperimentRoutine) DeleteExecutedInstanceCR() {
 }
 
 func (e *ExperimentRoutine) Start() {
+	// before any instance is started by the routine, so that only the runners of the last process are failed
+	if config.DefaultRunOptIns.RunMode.IsStandalone() {
+		failInterruptedStandaloneExperiments()
+	}
+
 	localCron := cron.New()
 	spec := "@every 3s"
 
@@ -547,15 +610,22 @@ func (e *ExperimentRoutine) Start() {
 		log.Error(err)
 		return
 	}
-
-	if err := localCron.AddFunc(spec, e.SyncExperimentsStatus); err != nil {
+	if err := localCron.AddFunc(spec, e.DealQueuedExperiment); err != nil {
 		log.Error(err)
 		return
 	}
 
-	if err := localCron.AddFunc("@every 6h", e.DeleteExecutedInstanceCR); err != nil {
-		log.Error(err)
-		return
+	// the status of standalone experiments is updated by the runner, and there is no CR to clean up
+	if !config.DefaultRunOptIns.RunMode.IsStandalone() {
+		if err := localCron.AddFunc(spec, e.SyncExperimentsStatus); err != nil {
+			log.Error(err)
+			return
+		}
+
+		if err := localCron.AddFunc("@every 6h", e.DeleteExecutedInstanceCR); err != nil {
+			log.Error(err)
+			return
+		}
 	}
 
 	localCron.Start()
@@ -0,0 +1,225 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package experiment
+
+import (
+	"chaosmeta-platform/config"
+	"chaosmeta-platform/pkg/models/experiment"
+	"chaosmeta-platform/pkg/models/inject/basic"
+	"chaosmeta-platform/pkg/service/experiment_instance"
+	"chaosmeta-platform/util/enc_dec"
+	"chaosmeta-platform/util/log"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"strings"
+)
+
+const (
+	// SensitiveVType is the value type of args containing credentials, such as a db dsn or an api key.
+	// Its value is encrypted at rest, masked in the apis and only decrypted when the workflow is rendered
+	SensitiveVType  VType = "sensitive"
+	SensitiveMask         = "******"
+	sensitivePrefix       = "enc:"
+)
+
+// getNamespaceKey derives the key of a namespace, so that a value copied to another namespace can not be decrypted
+func getNamespaceKey(namespaceId int) []byte {
+	key := sha256.Sum256([]byte(fmt.Sprintf("%s/%d", config.DefaultRunOptIns.SecretKey, namespaceId)))
+	return key[:]
+}
+
+func isEncryptedValue(value string) bool {
+	return strings.HasPrefix(value, sensitivePrefix)
+}
+
+func encryptSensitiveValue(namespaceId int, value string) (string, error) {
+	encrypted, err := enc_dec.Encrypt([]byte(value), getNamespaceKey(namespaceId))
+	if err != nil {
+		return "", err
+	}
+	return sensitivePrefix + string(encrypted), nil
+}
+
+func decryptSensitiveValue(namespaceId int, value string) (string, error) {
+	if !isEncryptedValue(value) {
+		return value, nil
+	}
+	decrypted, err := enc_dec.Decrypt([]byte(strings.TrimPrefix(value, sensitivePrefix)), getNamespaceKey(namespaceId))
+	if err != nil {
+		return "", err
+	}
+	return string(decrypted), nil
+}
+
+// getSensitiveArgsIds returns the ids of the sensitive ones among the args. Only the type of an arg decides whether its
+// value is a secret, a plain value from the user may start with the prefix of encrypted values as well
+func getSensitiveArgsIds(argsIds []int) (map[int]bool, error) {
+	sensitive := make(map[int]bool)
+	for _, argsId := range argsIds {
+		if _, ok := sensitive[argsId]; ok {
+			continue
+		}
+		argGet, err := basic.GetArgsById(context.Background(), argsId)
+		if err != nil {
+			return nil, fmt.Errorf("get args[%d] error: %s", argsId, err.Error())
+		}
+		sensitive[argsId] = argGet != nil && VType(argGet.ValueType) == SensitiveVType
+	}
+	return sensitive, nil
+}
+
+func getArgsValueIds(argsValues []*experiment.ArgsValue) []int {
+	argsIds := make([]int, len(argsValues))
+	for i, arg := range argsValues {
+		argsIds[i] = arg.ArgsID
+	}
+	return argsIds
+}
+
+// encryptSensitiveArgs encrypts the values of sensitive args in place. A masked value means that the user did not
+// change it, so the value in oldValues is kept
+func encryptSensitiveArgs(namespaceId int, argsValues []*experiment.ArgsValue, oldValues []*experiment.ArgsValue) error {
+	sensitive, err := getSensitiveArgsIds(getArgsValueIds(argsValues))
+	if err != nil {
+		return err
+	}
+	return encryptArgsValues(namespaceId, sensitive, argsValues, oldValues)
+}
+
+// encryptArgsValues every value from the user is plain, even if it starts with the prefix, so a stored encrypted value
+// replayed by the user is encrypted again instead of being decrypted
+func encryptArgsValues(namespaceId int, sensitive map[int]bool, argsValues []*experiment.ArgsValue, oldValues []*experiment.ArgsValue) error {
+	for _, arg := range argsValues {
+		if !sensitive[arg.ArgsID] || arg.Value == "" {
+			continue
+		}
+
+		if arg.Value == SensitiveMask {
+			oldValue := ""
+			for _, oldArg := range oldValues {
+				if oldArg.ArgsID == arg.ArgsID {
+					oldValue = oldArg.Value
+				}
+			}
+			if !isEncryptedValue(oldValue) {
+				return fmt.Errorf("value of args[%d] is masked and there is no stored value, provide the value", arg.ArgsID)
+			}
+			arg.Value = oldValue
+			continue
+		}
+
+		var err error
+		if arg.Value, err = encryptSensitiveValue(namespaceId, arg.Value); err != nil {
+			return fmt.Errorf("encrypt args[%d] error: %s", arg.ArgsID, err.Error())
+		}
+	}
+	return nil
+}
+
+// DecryptSensitiveArgsValues replaces the stored values of sensitive args with the plain ones, for the values copied
+// to a new experiment, such as the replay of a scenario, which are encrypted again when the experiment is created
+func DecryptSensitiveArgsValues(namespaceId int, argsValues []*experiment.ArgsValue) error {
+	sensitive, err := getSensitiveArgsIds(getArgsValueIds(argsValues))
+	if err != nil {
+		return err
+	}
+
+	for _, arg := range argsValues {
+		if !sensitive[arg.ArgsID] {
+			continue
+		}
+		if arg.Value, err = decryptSensitiveValue(namespaceId, arg.Value); err != nil {
+			return fmt.Errorf("decrypt args[%d] error: %s", arg.ArgsID, err.Error())
+		}
+	}
+	return nil
+}
+
+// decryptSensitiveArgs replaces the encrypted values of the nodes with the plain ones before the workflow is rendered
+func decryptSensitiveArgs(namespaceId int, nodes []*experiment_instance.WorkflowNodesDetail) error {
+	var argsIds []int
+	for _, node := range nodes {
+		for _, arg := range node.ArgsValues {
+			argsIds = append(argsIds, arg.ArgsId)
+		}
+	}
+	sensitive, err := getSensitiveArgsIds(argsIds)
+	if err != nil {
+		return err
+	}
+
+	for _, node := range nodes {
+		for i := range node.ArgsValues {
+			if !sensitive[node.ArgsValues[i].ArgsId] {
+				continue
+			}
+			value, err := decryptSensitiveValue(namespaceId, node.ArgsValues[i].Value)
+			if err != nil {
+				return fmt.Errorf("decrypt args[%d] of node[%s] error: %s", node.ArgsValues[i].ArgsId, node.UUID, err.Error())
+			}
+			node.ArgsValues[i].Value = value
+		}
+	}
+	return nil
+}
+
+// MaskSensitiveArgs hides the values of sensitive args of the workflow nodes before they are returned by the apis,
+// an encrypted value is hidden as well when the type of its arg can not be read
+func MaskSensitiveArgs(nodes []*WorkflowNode) {
+	var argsIds []int
+	for _, node := range nodes {
+		argsIds = append(argsIds, getArgsValueIds(node.ArgsValue)...)
+	}
+	sensitive, err := getSensitiveArgsIds(argsIds)
+	if err != nil {
+		log.Error(err)
+	}
+
+	for _, node := range nodes {
+		for _, arg := range node.ArgsValue {
+			if isMasked(sensitive, arg.ArgsID, arg.Value) {
+				arg.Value = SensitiveMask
+			}
+		}
+	}
+}
+
+// MaskSensitiveArgsInstance is the same as MaskSensitiveArgs for the node of an experiment instance
+func MaskSensitiveArgsInstance(node *experiment_instance.WorkflowNodesDetail) {
+	argsIds := make([]int, len(node.ArgsValues))
+	for i, arg := range node.ArgsValues {
+		argsIds[i] = arg.ArgsId
+	}
+	sensitive, err := getSensitiveArgsIds(argsIds)
+	if err != nil {
+		log.Error(err)
+	}
+
+	for i := range node.ArgsValues {
+		if isMasked(sensitive, node.ArgsValues[i].ArgsId, node.ArgsValues[i].Value) {
+			node.ArgsValues[i].Value = SensitiveMask
+		}
+	}
+}
+
+func isMasked(sensitive map[int]bool, argsId int, value string) bool {
+	if sensitive == nil {
+		return isEncryptedValue(value)
+	}
+	return value != "" && sensitive[argsId]
+}
@@ -0,0 +1,78 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package experiment
+
+import (
+	"chaosmeta-platform/config"
+	"chaosmeta-platform/pkg/models/experiment"
+	"chaosmeta-platform/util/enc_dec"
+	"testing"
+)
+
+func TestEncryptArgsValues(t *testing.T) {
+	config.DefaultRunOptIns = &config.Config{SecretKey: "test-secret"}
+	var (
+		namespaceId = 1
+		sensitive   = map[int]bool{1: true, 2: false}
+		stored, _   = encryptSensitiveValue(namespaceId, "old-secret")
+		oldValues   = []*experiment.ArgsValue{{ArgsID: 1, Value: stored}}
+	)
+
+	tests := []struct {
+		name      string
+		arg       *experiment.ArgsValue
+		oldValues []*experiment.ArgsValue
+		wantPlain string
+		wantErr   bool
+	}{
+		{name: "plain value", arg: &experiment.ArgsValue{ArgsID: 1, Value: "secret"}, wantPlain: "secret"},
+		{name: "value with the prefix is plain", arg: &experiment.ArgsValue{ArgsID: 1, Value: stored}, wantPlain: stored},
+		{name: "masked value keeps the stored one", arg: &experiment.ArgsValue{ArgsID: 1, Value: SensitiveMask}, oldValues: oldValues, wantPlain: "old-secret"},
+		{name: "masked value without stored one", arg: &experiment.ArgsValue{ArgsID: 1, Value: SensitiveMask}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := encryptArgsValues(namespaceId, sensitive, []*experiment.ArgsValue{tt.arg}, tt.oldValues)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("encryptArgsValues() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			plain, err := decryptSensitiveValue(namespaceId, tt.arg.Value)
+			if err != nil || plain != tt.wantPlain || !isEncryptedValue(tt.arg.Value) {
+				t.Errorf("encryptArgsValues() value = %s, decrypted = %s, want %s", tt.arg.Value, plain, tt.wantPlain)
+			}
+		})
+	}
+
+	// the args which are not sensitive are kept as they are
+	arg := &experiment.ArgsValue{ArgsID: 2, Value: sensitivePrefix + "AAAA"}
+	if err := encryptArgsValues(namespaceId, sensitive, []*experiment.ArgsValue{arg}, nil); err != nil || arg.Value != sensitivePrefix+"AAAA" {
+		t.Errorf("encryptArgsValues() changed the value of a plain arg: %s, error: %v", arg.Value, err)
+	}
+}
+
+func TestDecryptShortCipherText(t *testing.T) {
+	key := make([]byte, 32)
+	for _, value := range []string{"AAAA", ""} {
+		if _, err := enc_dec.Decrypt([]byte(value), key); err == nil {
+			t.Fatalf("expect error for %q", value)
+		}
+	}
+}
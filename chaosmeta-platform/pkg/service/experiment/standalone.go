@@ -0,0 +1,309 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package experiment
+
+import (
+	experimentInstanceModel "chaosmeta-platform/pkg/models/experiment_instance"
+	"chaosmeta-platform/pkg/models/inject/basic"
+	"chaosmeta-platform/pkg/service/experiment_instance"
+	"chaosmeta-platform/pkg/service/host"
+	"chaosmeta-platform/util/log"
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// standaloneRunners holds the cancel functions of the experiment instances running in standalone mode
+var standaloneRunners = struct {
+	sync.Mutex
+	cancels map[string]context.CancelFunc
+}{cancels: make(map[string]context.CancelFunc)}
+
+// startStandaloneExperiment runs the workflow of the experiment instance against inventory hosts without argo:
+// rows run in parallel, nodes in a row run by column order, and the first failed node stops the whole workflow
+func startStandaloneExperiment(experimentInstanceId string, nodes []*experiment_instance.WorkflowNodesDetail) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	standaloneRunners.Lock()
+	standaloneRunners.cancels[experimentInstanceId] = cancel
+	standaloneRunners.Unlock()
+
+	if err := experimentInstanceModel.UpdateExperimentInstanceStatus(experimentInstanceId, WorkflowRunning, ""); err != nil {
+		log.Error(err)
+	}
+
+	go func() {
+		defer func() {
+			standaloneRunners.Lock()
+			delete(standaloneRunners.cancels, experimentInstanceId)
+			standaloneRunners.Unlock()
+			cancel()
+		}()
+
+		err := runStandaloneWorkflow(ctx, cancel, nodes)
+		if ctx.Err() != nil && err == nil {
+			// stopped by user, the status is updated by StopExperiment
+			return
+		}
+
+		status, message := WorkflowSucceeded, ""
+		if err != nil {
+			status, message = WorkflowFailed, err.Error()
+		}
+		if err := experimentInstanceModel.UpdateExperimentInstanceStatus(experimentInstanceId, status, message); err != nil {
+			log.Error(err)
+		}
+	}()
+	return nil
+}
+
+// failInterruptedStandaloneExperiments marks the instances left pending or running by the last process as failed, their
+// runners are gone with it. The faults on the hosts are recovered by chaosmetad when the node duration is over
+func failInterruptedStandaloneExperiments() {
+	instances, err := experimentInstanceModel.ListExperimentInstancesByStatusList([]string{WorkflowPending, WorkflowRunning})
+	if err != nil {
+		log.Error(err)
+		return
+	}
+
+	message := "interrupted by the restart of the platform"
+	for _, instance := range instances {
+		nodes, err := experimentInstanceModel.GetWorkflowNodeInstancesByExperimentUUID(instance.UUID)
+		if err != nil {
+			log.Error(err)
+			continue
+		}
+
+		for _, node := range nodes {
+			if node.Status == WorkflowPending || node.Status == WorkflowRunning {
+				if err := experimentInstanceModel.UpdateWorkflowNodeInstanceStatus(node.UUID, WorkflowFailed, message); err != nil {
+					log.Error(err)
+				}
+			}
+		}
+
+		if err := experimentInstanceModel.UpdateExperimentInstanceStatus(instance.UUID, WorkflowFailed, message); err != nil {
+			log.Error(err)
+			continue
+		}
+		log.Infof("standalone experiment instance %s is %s", instance.UUID, message)
+	}
+}
+
+func stopStandaloneExperiment(experimentInstanceId string) error {
+	standaloneRunners.Lock()
+	cancel, ok := standaloneRunners.cancels[experimentInstanceId]
+	standaloneRunners.Unlock()
+	if !ok {
+		return errors.New("experiment has ended")
+	}
+
+	cancel()
+	return nil
+}
+
+func runStandaloneWorkflow(ctx context.Context, cancel context.CancelFunc, nodes []*experiment_instance.WorkflowNodesDetail) error {
+	rows := make(map[int][]*experiment_instance.WorkflowNodesDetail)
+	for _, node := range nodes {
+		rows[node.Row] = append(rows[node.Row], node)
+	}
+
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+	for _, rowNodes := range rows {
+		sort.Slice(rowNodes, func(i, j int) bool {
+			return rowNodes[i].Column < rowNodes[j].Column
+		})
+
+		wg.Add(1)
+		go func(rowNodes []*experiment_instance.WorkflowNodesDetail) {
+			defer wg.Done()
+			for _, node := range rowNodes {
+				if ctx.Err() != nil {
+					return
+				}
+
+				if err := runStandaloneNode(ctx, node); err != nil {
+					errOnce.Do(func() {
+						firstErr = fmt.Errorf("node[%s] failed: %s", node.Name, err.Error())
+						cancel()
+					})
+					return
+				}
+			}
+		}(rowNodes)
+	}
+	wg.Wait()
+
+	return firstErr
+}
+
+func runStandaloneNode(ctx context.Context, node *experiment_instance.WorkflowNodesDetail) error {
+	if err := experimentInstanceModel.UpdateWorkflowNodeInstanceStatus(node.UUID, WorkflowRunning, ""); err != nil {
+		log.Error(err)
+	}
+
+	var err error
+	switch ExecType(node.ExecType) {
+	case WaitExecType:
+		err = sleepWithContext(ctx, node.Duration)
+	case FaultExecType:
+		err = runStandaloneFault(ctx, node)
+	default:
+		err = fmt.Errorf("exec type[%s] is not supported in standalone mode", node.ExecType)
+	}
+
+	status, message := WorkflowSucceeded, ""
+	if err != nil {
+		status, message = WorkflowFailed, err.Error()
+	} else if ctx.Err() != nil {
+		status, message = WorkflowFailed, "stopped"
+	}
+	if err := experimentInstanceModel.UpdateWorkflowNodeInstanceStatus(node.UUID, status, message); err != nil {
+		log.Error(err)
+	}
+	return err
+}
+
+// runStandaloneFault injects the fault into every selected host, holds it for the node duration and then recovers.
+// hosts already injected are recovered if any injection fails
+func runStandaloneFault(ctx context.Context, node *experiment_instance.WorkflowNodesDetail) error {
+	scope, err := basic.GetScopeById(ctx, node.ScopeId)
+	if err != nil {
+		return fmt.Errorf("get scope error: %s", err.Error())
+	}
+	if scope.Name != "node" {
+		return fmt.Errorf("scope[%s] is not supported in standalone mode, only node is supported", scope.Name)
+	}
+
+	target, err := basic.GetTargetById(ctx, node.TargetId)
+	if err != nil {
+		return fmt.Errorf("get target error: %s", err.Error())
+	}
+	fault, err := basic.GetFaultById(ctx, node.ExecId)
+	if err != nil {
+		return fmt.Errorf("get fault error: %s", err.Error())
+	}
+
+	args, err := getStandaloneArgs(ctx, node)
+	if err != nil {
+		return err
+	}
+
+	if node.Subtasks == nil {
+		return errors.New("target hosts are required")
+	}
+	hostService := host.HostService{}
+	hosts, err := hostService.SelectHosts(node.Subtasks.TargetIP, node.Subtasks.TargetHostname, node.Subtasks.TargetLabel)
+	if err != nil {
+		return err
+	}
+
+	var injected []*host.AgentClient
+	recoverAll := func() error {
+		var errMsgs []string
+		for _, client := range injected {
+			// recover even if the workflow is stopped
+			if err := client.Recover(context.Background(), getStandaloneUid(node.UUID, client.IP)); err != nil {
+				errMsgs = append(errMsgs, fmt.Sprintf("recover %s error: %s", client.IP, err.Error()))
+			}
+		}
+		if len(errMsgs) > 0 {
+			return errors.New(strings.Join(errMsgs, "; "))
+		}
+		return nil
+	}
+
+	for _, h := range hosts {
+		client := &host.AgentClient{IP: h.IP, Port: h.AgentPort}
+		if err := client.Inject(ctx, getStandaloneUid(node.UUID, h.IP), target.Name, fault.Name, node.Duration, args); err != nil {
+			injectErr := fmt.Errorf("inject %s error: %s", h.IP, err.Error())
+			if err := recoverAll(); err != nil {
+				return fmt.Errorf("%s, %s", injectErr.Error(), err.Error())
+			}
+			return injectErr
+		}
+		injected = append(injected, client)
+	}
+
+	waitErr := sleepWithContext(ctx, node.Duration)
+	if err := recoverAll(); err != nil {
+		return err
+	}
+	return waitErr
+}
+
+func getStandaloneArgs(ctx context.Context, node *experiment_instance.WorkflowNodesDetail) (map[string]interface{}, error) {
+	args := make(map[string]interface{})
+	for _, arg := range node.ArgsValues {
+		argGet, err := basic.GetArgsById(ctx, arg.ArgsId)
+		if err != nil {
+			return nil, fmt.Errorf("get args error: %s", err.Error())
+		}
+
+		key := strings.ReplaceAll(argGet.Key, "-", "_")
+		switch VType(argGet.ValueType) {
+		case IntVType:
+			v, err := strconv.Atoi(arg.Value)
+			if err != nil {
+				return nil, fmt.Errorf("args[%s] is not an int: %s", argGet.Key, arg.Value)
+			}
+			args[key] = v
+		case "bool":
+			v, err := strconv.ParseBool(arg.Value)
+			if err != nil {
+				return nil, fmt.Errorf("args[%s] is not a bool: %s", argGet.Key, arg.Value)
+			}
+			args[key] = v
+		default:
+			args[key] = arg.Value
+		}
+	}
+	return args, nil
+}
+
+// getStandaloneUid is stable for the same node and host, so that a recover can always find the injection
+func getStandaloneUid(nodeUUID, ip string) string {
+	h := fnv.New64a()
+	h.Write([]byte(nodeUUID + ip))
+	return fmt.Sprintf("cm%x", h.Sum64())
+}
+
+func sleepWithContext(ctx context.Context, duration string) error {
+	if duration == "" {
+		return nil
+	}
+
+	d, err := time.ParseDuration(duration)
+	if err != nil {
+		return fmt.Errorf("duration[%s] format error: %s", duration, err.Error())
+	}
+
+	select {
+	case <-time.After(d):
+	case <-ctx.Done():
+	}
+	return nil
+}
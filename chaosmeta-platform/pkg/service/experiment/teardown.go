@@ -0,0 +1,103 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package experiment
+
+import (
+	"chaosmeta-platform/config"
+	"chaosmeta-platform/pkg/models/experiment"
+	experimentInstanceModel "chaosmeta-platform/pkg/models/experiment_instance"
+	triggerModel "chaosmeta-platform/pkg/models/trigger"
+	"chaosmeta-platform/pkg/service/cluster"
+	"chaosmeta-platform/util/log"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/argoproj/argo-workflows/v3/pkg/client/clientset/versioned"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+// TeardownNamespace stops the unfinished instances of the namespace, deletes its experiments together with their
+// schedules and event triggers, then deletes the workflows and CRs created for the namespace in the cluster
+func TeardownNamespace(ctx context.Context, namespaceId int) error {
+	instances, err := experimentInstanceModel.ListExperimentInstancesByNamespaceId(namespaceId, []string{WorkflowPending, WorkflowRunning, WorkflowQueued})
+	if err != nil {
+		return err
+	}
+	for _, instance := range instances {
+		if err := StopExperiment(instance.UUID, true); err != nil {
+			log.Errorf("stop experiment instance[%s] error: %s", instance.UUID, err.Error())
+		}
+	}
+
+	experiments, err := experiment.ListExperimentsByNamespaceId(namespaceId)
+	if err != nil {
+		return err
+	}
+	es := ExperimentService{}
+	for _, e := range experiments {
+		triggers, err := triggerModel.ListEventTriggersByExperimentUUID(e.UUID)
+		if err != nil {
+			return err
+		}
+		for _, t := range triggers {
+			if err := triggerModel.DeleteEventTriggerById(t.Id); err != nil {
+				return err
+			}
+		}
+		if err := es.DeleteExperimentByUUID(e.UUID); err != nil {
+			return fmt.Errorf("delete experiment[%s] error: %s", e.UUID, err.Error())
+		}
+	}
+
+	if config.DefaultRunOptIns.RunMode.IsStandalone() {
+		return nil
+	}
+	return deleteNamespaceCustomResources(ctx, namespaceId)
+}
+
+// deleteNamespaceCustomResources deletes the workflows and CRs labeled with the namespace, the recovery of the
+// stopped instances is already done so the CRs are removed without waiting for their phase
+func deleteNamespaceCustomResources(ctx context.Context, namespaceId int) error {
+	clusterService := cluster.ClusterService{}
+	_, restConfig, err := clusterService.GetRestConfig(ctx, config.DefaultRunOptIns.RunMode.Int())
+	if err != nil {
+		return err
+	}
+	listOpts := metav1.ListOptions{LabelSelector: fmt.Sprintf("%s=%d", metadataKey(namespaceIdKey), namespaceId)}
+
+	var errMsg string
+	wfClient := versioned.NewForConfigOrDie(restConfig).ArgoprojV1alpha1().Workflows(config.DefaultRunOptIns.ArgoWorkflowNamespace)
+	if err := wfClient.DeleteCollection(ctx, metav1.DeleteOptions{}, listOpts); err != nil {
+		errMsg = fmt.Sprintf("%sworkflows: %s; ", errMsg, err.Error())
+	}
+
+	client, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+	for _, resource := range []schema.GroupVersionResource{gvr, gvrFlow, gvrMeasure} {
+		if err := client.Resource(resource).Namespace(config.DefaultRunOptIns.WorkflowNamespace).DeleteCollection(ctx, metav1.DeleteOptions{}, listOpts); err != nil {
+			errMsg = fmt.Sprintf("%s%s: %s; ", errMsg, resource.Resource, err.Error())
+		}
+	}
+	if errMsg != "" {
+		return errors.New(errMsg)
+	}
+	return nil
+}
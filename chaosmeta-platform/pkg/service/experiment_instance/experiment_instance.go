@@ -62,6 +62,7 @@ func (s *ExperimentInstanceService) CreateExperimentInstance(experimentParam *Ex
 		ExperimentUUID: experimentParam.UUID,
 		Creator:        experimentParam.Creator,
 		Message:        experimentParam.Message,
+		Mutex:          experimentParam.Mutex,
 		Status:         status,
 	}
 
@@ -145,12 +146,15 @@ type ExperimentInstanceInfo struct {
 	Creator     int    `json:"creator"`
 	CreatorName string `json:"creator_name,omitempty"`
 	NamespaceId int    `json:"namespace_id"`
+	Mutex       string `json:"mutex,omitempty"`
+	PostAction  string `json:"post_action,omitempty"`
 
 	CreateTime string      `json:"create_time"`
 	UpdateTime string      `json:"update_time"`
 	Status     string      `json:"status"`
 	Message    string      `json:"message"`
 	Labels     []LabelInfo `json:"labels"`
+	Progress   *Progress   `json:"progress,omitempty"`
 }
 
 func (s *ExperimentInstanceService) GetExperimentInstanceByUUID(uuid string) (*ExperimentInstanceInfo, error) {
@@ -178,11 +182,16 @@ func (s *ExperimentInstanceService) GetExperimentInstanceByUUID(uuid string) (*E
 		Creator:     exp.Creator,
 		CreatorName: userGet.Email,
 		NamespaceId: exp.NamespaceID,
+		Mutex:       exp.Mutex,
+		PostAction:  exp.PostAction,
 		CreateTime:  exp.CreateTime.Format(time.RFC3339),
 		UpdateTime:  exp.UpdateTime.Format(time.RFC3339),
 		Status:      exp.Status,
 		Message:     exp.Message,
 	}
+	if expData.Progress, err = s.GetProgress(exp.UUID, exp.Status); err != nil {
+		log.Error(err)
+	}
 
 	for _, label := range labels {
 		labelModel := namespace.Label{Id: label.LabelID, NamespaceId: expData.NamespaceId}
@@ -292,6 +301,7 @@ type WorkflowNodesDetail struct {
 	Subtasks        *experiment_instance.FaultRangeInstance   `json:"subtasks"`
 	FlowSubtasks    *experiment_instance.FlowRangeInstance    `json:"flow_subtasks"`
 	MeasureSubtasks *experiment_instance.MeasureRangeInstance `json:"measure_subtasks"`
+	TargetErrors    []TargetError                             `json:"target_errors,omitempty"`
 }
 
 func (s *ExperimentInstanceService) GetWorkflowNodeInstanceDetailByUUIDAndNodeId(experimentUUID, nodeId string) (*WorkflowNodesDetail, error) {
@@ -321,6 +331,7 @@ func (s *ExperimentInstanceService) GetWorkflowNodeInstanceDetailByUUIDAndNodeId
 			return &workflowNodesDetail, err
 		}
 		workflowNodesDetail.Subtasks = faultRange
+		workflowNodesDetail.TargetErrors = parseTargetErrors(workflowNodesDetail.Message)
 	case FlowExecType:
 		flowRange, err := experiment_instance.GetFlowRangeInstancesByWorkflowNodeInstanceUUID(nodeId)
 		if err != nil {
@@ -436,11 +447,16 @@ func (s *ExperimentInstanceService) SearchExperimentInstances(lastInstance strin
 			Creator:     experiment.Creator,
 			CreatorName: userGet.Email,
 			NamespaceId: experiment.NamespaceID,
+			Mutex:       experiment.Mutex,
+			PostAction:  experiment.PostAction,
 			CreateTime:  experiment.CreateTime.Format(time.RFC3339),
 			UpdateTime:  experiment.UpdateTime.Format(time.RFC3339),
 			Status:      experiment.Status,
 			Message:     experiment.Message,
 		}
+		if expData.Progress, err = s.GetProgress(experiment.UUID, experiment.Status); err != nil {
+			log.Error(err)
+		}
 
 		for _, label := range labels {
 			labelModel := namespace.Label{Id: label.LabelID, NamespaceId: expData.NamespaceId}
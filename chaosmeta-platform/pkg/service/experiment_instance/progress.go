@@ -0,0 +1,104 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package experiment_instance
+
+import (
+	"chaosmeta-platform/pkg/models/experiment_instance"
+	"time"
+)
+
+// Progress is estimated from the durations of the nodes, the nodes of a row run one by one and the rows run in parallel
+type Progress struct {
+	Percent int `json:"percent"`
+	// ETA is the estimated seconds left
+	ETA            int64 `json:"eta"`
+	CompletedNodes int   `json:"completed_nodes"`
+	TotalNodes     int   `json:"total_nodes"`
+}
+
+var finishedStatus = map[string]bool{
+	"Succeeded": true,
+	"Failed":    true,
+	"Error":     true,
+	"Skipped":   true,
+	"Omitted":   true,
+}
+
+func (s *ExperimentInstanceService) GetProgress(uuid, status string) (*Progress, error) {
+	nodes, err := experiment_instance.GetWorkflowNodeInstancesByExperimentUUID(uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	return calculateProgress(status, nodes, time.Now()), nil
+}
+
+// calculateProgress weights a node by its duration, at least one second so that the nodes without duration still count.
+// A running node is done as the time elapsed since its start, and the ETA is the most remaining time of the rows
+func calculateProgress(status string, nodes []*experiment_instance.WorkflowNodeInstance, now time.Time) *Progress {
+	progress := &Progress{TotalNodes: len(nodes)}
+	var total, done time.Duration
+	rowRemaining := make(map[int]time.Duration)
+	for _, node := range nodes {
+		weight, _ := time.ParseDuration(node.Duration)
+		if weight < time.Second {
+			weight = time.Second
+		}
+		total += weight
+
+		switch {
+		case finishedStatus[node.Status]:
+			done += weight
+			progress.CompletedNodes++
+		case node.Status == string(experiment_instance.Running):
+			start := node.StartTime
+			if start.IsZero() {
+				start = node.UpdateTime
+			}
+			elapsed := now.Sub(start)
+			if elapsed < 0 {
+				elapsed = 0
+			}
+			if elapsed > weight {
+				elapsed = weight
+			}
+			done += elapsed
+			rowRemaining[node.Row] += weight - elapsed
+		default:
+			rowRemaining[node.Row] += weight
+		}
+	}
+
+	if finishedStatus[status] || total == 0 {
+		progress.Percent = 100
+		return progress
+	}
+
+	var eta time.Duration
+	for _, remaining := range rowRemaining {
+		if remaining > eta {
+			eta = remaining
+		}
+	}
+	progress.ETA = int64(eta.Seconds())
+	// 100 is only for a finished instance, the last node may be recovering
+	progress.Percent = int(done * 100 / total)
+	if progress.Percent > 99 {
+		progress.Percent = 99
+	}
+	return progress
+}
@@ -0,0 +1,56 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package experiment_instance
+
+import (
+	"chaosmeta-platform/pkg/models/experiment_instance"
+	"testing"
+	"time"
+)
+
+func TestCalculateProgress(t *testing.T) {
+	now := time.Now()
+	node := func(row int, duration, status string, started time.Duration) *experiment_instance.WorkflowNodeInstance {
+		n := &experiment_instance.WorkflowNodeInstance{Row: row, Duration: duration, Status: status}
+		if started > 0 {
+			n.StartTime = now.Add(-started)
+		}
+		return n
+	}
+
+	tests := []struct {
+		name        string
+		status      string
+		nodes       []*experiment_instance.WorkflowNodeInstance
+		wantPercent int
+		wantETA     int64
+	}{
+		{name: "not started", status: "Pending", nodes: []*experiment_instance.WorkflowNodeInstance{node(0, "60s", "", 0), node(0, "40s", "", 0)}, wantPercent: 0, wantETA: 100},
+		{name: "running", status: "Running", nodes: []*experiment_instance.WorkflowNodeInstance{node(0, "60s", "Succeeded", 0), node(0, "40s", "Running", 20*time.Second)}, wantPercent: 80, wantETA: 20},
+		{name: "parallel rows", status: "Running", nodes: []*experiment_instance.WorkflowNodeInstance{node(0, "30s", "Running", 10*time.Second), node(1, "1m", "", 0)}, wantPercent: 11, wantETA: 60},
+		{name: "overrun", status: "Running", nodes: []*experiment_instance.WorkflowNodeInstance{node(0, "10s", "Running", time.Minute)}, wantPercent: 99, wantETA: 0},
+		{name: "finished", status: "Failed", nodes: []*experiment_instance.WorkflowNodeInstance{node(0, "10s", "Failed", 0), node(0, "10s", "", 0)}, wantPercent: 100, wantETA: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := calculateProgress(tt.status, tt.nodes, now)
+			if got.Percent != tt.wantPercent || got.ETA != tt.wantETA {
+				t.Errorf("calculateProgress() = %d%% eta %d, want %d%% eta %d", got.Percent, got.ETA, tt.wantPercent, tt.wantETA)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package experiment_instance
+
+import (
+	"chaosmeta-platform/util/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// TargetError is the structured error of a failed target in a fault node
+type TargetError struct {
+	Phase            string            `json:"phase"`
+	InjectObjectName string            `json:"inject_object_name"`
+	Message          string            `json:"message"`
+	Error            *errors.ErrorInfo `json:"error"`
+}
+
+// faultNodeStatus is the part of the fault CR status saved as the message of a fault node
+type faultNodeStatus struct {
+	Detail struct {
+		Inject  []faultTargetStatus `yaml:"inject"`
+		Recover []faultTargetStatus `yaml:"recover"`
+	} `yaml:"detail"`
+}
+
+type faultTargetStatus struct {
+	InjectObjectName string            `yaml:"injectobjectname"`
+	Message          string            `yaml:"message"`
+	Error            *errors.ErrorInfo `yaml:"error"`
+}
+
+// parseTargetErrors picks the structured errors of the targets from the message of a fault node,
+// the message is not always a status, so a message that can not be parsed has no target errors
+func parseTargetErrors(message string) []TargetError {
+	var status faultNodeStatus
+	if err := yaml.Unmarshal([]byte(message), &status); err != nil {
+		return nil
+	}
+
+	targetErrors := getTargetErrors("inject", status.Detail.Inject)
+	return append(targetErrors, getTargetErrors("recover", status.Detail.Recover)...)
+}
+
+func getTargetErrors(phase string, targets []faultTargetStatus) []TargetError {
+	var targetErrors []TargetError
+	for _, target := range targets {
+		if target.Error != nil {
+			targetErrors = append(targetErrors, TargetError{Phase: phase, InjectObjectName: target.InjectObjectName, Message: target.Message, Error: target.Error})
+		}
+	}
+
+	return targetErrors
+}
@@ -0,0 +1,71 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package experiment_instance
+
+import (
+	"chaosmeta-platform/util/errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseTargetErrors(t *testing.T) {
+	message := `phase: inject
+status: failed
+detail:
+  inject:
+  - injectobjectname: node/a
+    message: "experiment inject error: err code: {1}, err msg: args error"
+    status: failed
+    error:
+      code: BadArgs
+      category: InvalidArgument
+      component: chaosmetad
+  - injectobjectname: node/b
+    status: success
+  recover:
+  - injectobjectname: node/a
+    message: experiment recover network error, timeout
+    status: failed
+    error:
+      code: Timeout
+      category: Timeout
+      component: operator
+      retryable: true
+`
+	want := []TargetError{
+		{
+			Phase:            "inject",
+			InjectObjectName: "node/a",
+			Message:          "experiment inject error: err code: {1}, err msg: args error",
+			Error:            &errors.ErrorInfo{Code: "BadArgs", Category: errors.CategoryInvalidArgument, Component: "chaosmetad"},
+		},
+		{
+			Phase:            "recover",
+			InjectObjectName: "node/a",
+			Message:          "experiment recover network error, timeout",
+			Error:            &errors.ErrorInfo{Code: "Timeout", Category: errors.CategoryTimeout, Component: "operator", Retryable: true},
+		},
+	}
+
+	if got := parseTargetErrors(message); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseTargetErrors() = %v, want %v", got, want)
+	}
+
+	if got := parseTargetErrors("workflow node failed"); got != nil {
+		t.Errorf("parseTargetErrors() of a plain message = %v, want nil", got)
+	}
+}
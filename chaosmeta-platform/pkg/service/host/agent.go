@@ -0,0 +1,156 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package host
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+const (
+	agentSucCode   = 0
+	agentTimeout   = 30 * time.Second
+	injectPath     = "/v1/experiment/inject"
+	recoverPath    = "/v1/experiment/recover"
+	versionPath    = "/v1/version"
+	agentCreator   = "chaosmeta-platform"
+	agentSchemeFmt = "http://%s:%d%s"
+)
+
+// AgentClient calls the http api of the chaosmetad server on a host
+type AgentClient struct {
+	IP   string
+	Port int
+}
+
+type agentInjectRequest struct {
+	Target  string `json:"target"`
+	Fault   string `json:"fault"`
+	Timeout string `json:"timeout"`
+	Creator string `json:"creator"`
+	Args    string `json:"args"`
+	Uid     string `json:"uid"`
+}
+
+type agentRecoverRequest struct {
+	Uid string `json:"uid"`
+}
+
+type agentResponse struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type agentVersionResponse struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    *struct {
+		Version string `json:"version"`
+	} `json:"data,omitempty"`
+}
+
+// Inject args are the fault args with "_" separated keys, eg: {"percent": 90}
+func (a *AgentClient) Inject(ctx context.Context, uid, target, fault, timeout string, args map[string]interface{}) error {
+	argsBytes, err := json.Marshal(args)
+	if err != nil {
+		return fmt.Errorf("args to json string error: %s", err.Error())
+	}
+
+	var resp agentResponse
+	if err := a.post(ctx, injectPath, agentInjectRequest{
+		Target:  target,
+		Fault:   fault,
+		Timeout: timeout,
+		Creator: agentCreator,
+		Args:    string(argsBytes),
+		Uid:     uid,
+	}, &resp); err != nil {
+		return err
+	}
+
+	if resp.Code != agentSucCode {
+		return fmt.Errorf("err code: %d, err msg: %s", resp.Code, resp.Message)
+	}
+	return nil
+}
+
+func (a *AgentClient) Recover(ctx context.Context, uid string) error {
+	var resp agentResponse
+	if err := a.post(ctx, recoverPath, agentRecoverRequest{Uid: uid}, &resp); err != nil {
+		return err
+	}
+
+	if resp.Code != agentSucCode {
+		return fmt.Errorf("err code: %d, err msg: %s", resp.Code, resp.Message)
+	}
+	return nil
+}
+
+func (a *AgentClient) Version(ctx context.Context) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf(agentSchemeFmt, a.IP, a.Port, versionPath), nil)
+	if err != nil {
+		return "", err
+	}
+
+	var resp agentVersionResponse
+	if err := a.do(req, &resp); err != nil {
+		return "", err
+	}
+
+	if resp.Code != agentSucCode || resp.Data == nil {
+		return "", fmt.Errorf("query version error: %s", resp.Message)
+	}
+	return resp.Data.Version, nil
+}
+
+func (a *AgentClient) post(ctx context.Context, path string, body interface{}, resp interface{}) error {
+	bodyBytes, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("request to json error: %s", err.Error())
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf(agentSchemeFmt, a.IP, a.Port, path), bytes.NewReader(bodyBytes))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return a.do(req, resp)
+}
+
+func (a *AgentClient) do(req *http.Request, resp interface{}) error {
+	client := http.Client{Timeout: agentTimeout}
+	res, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request agent %s:%d error: %s", a.IP, a.Port, err.Error())
+	}
+	defer res.Body.Close()
+
+	resBytes, err := io.ReadAll(res.Body)
+	if err != nil {
+		return fmt.Errorf("read response error: %s", err.Error())
+	}
+
+	if err := json.Unmarshal(resBytes, resp); err != nil {
+		return fmt.Errorf("resp[%s] format error: %s", string(resBytes), err.Error())
+	}
+	return nil
+}
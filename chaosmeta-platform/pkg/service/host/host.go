@@ -0,0 +1,252 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package host
+
+import (
+	"chaosmeta-platform/config"
+	hostModel "chaosmeta-platform/pkg/models/host"
+	"chaosmeta-platform/pkg/service/user"
+	platformErrors "chaosmeta-platform/util/errors"
+	"chaosmeta-platform/util/log"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/robfig/cron"
+	"gopkg.in/yaml.v2"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+func Init() {
+	if !config.DefaultRunOptIns.RunMode.IsStandalone() {
+		return
+	}
+
+	if path := config.DefaultRunOptIns.Standalone.InventoryPath; path != "" {
+		s := HostService{}
+		if err := s.ImportInventory(path); err != nil {
+			log.Errorf("import host inventory[%s] error: %s", path, err.Error())
+		}
+	}
+
+	hr := HostRoutine{context: context.Background()}
+	go hr.Start()
+}
+
+type HostService struct{}
+
+// HostCreate is also the item format of the inventory file
+type HostCreate struct {
+	Hostname  string            `json:"hostname" yaml:"hostname"`
+	IP        string            `json:"ip" yaml:"ip"`
+	SSHPort   int               `json:"sshPort" yaml:"sshPort"`
+	SSHUser   string            `json:"sshUser" yaml:"sshUser"`
+	AgentPort int               `json:"agentPort" yaml:"agentPort"`
+	Labels    map[string]string `json:"labels" yaml:"labels"`
+}
+
+type Inventory struct {
+	Hosts []HostCreate `yaml:"hosts"`
+}
+
+func (s *HostService) ImportInventory(path string) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var inventory Inventory
+	if err := yaml.Unmarshal(content, &inventory); err != nil {
+		return fmt.Errorf("inventory format error: %s", err.Error())
+	}
+
+	for _, h := range inventory.Hosts {
+		if _, err := s.create(&h); err != nil {
+			return fmt.Errorf("import host[%s] error: %s", h.Hostname, err.Error())
+		}
+	}
+	log.Infof("import %d hosts from inventory[%s]", len(inventory.Hosts), path)
+	return nil
+}
+
+// Create registers the host, or updates it if the hostname exists. Only admins can manage the inventory
+func (s *HostService) Create(ctx context.Context, userName string, h *HostCreate) (int, error) {
+	userService := user.UserService{}
+	if !userService.IsAdmin(ctx, userName) {
+		return 0, platformErrors.ErrPermissionDenied
+	}
+	return s.create(h)
+}
+
+func (s *HostService) create(h *HostCreate) (int, error) {
+	if h.Hostname == "" || h.IP == "" {
+		return 0, errors.New("hostname and ip are required")
+	}
+	if h.SSHPort == 0 {
+		h.SSHPort = 22
+	}
+	if h.AgentPort == 0 {
+		h.AgentPort = config.DefaultRunOptIns.Standalone.AgentPort
+	}
+
+	hostCreate := &hostModel.Host{
+		Hostname:  h.Hostname,
+		IP:        h.IP,
+		SSHPort:   h.SSHPort,
+		SSHUser:   h.SSHUser,
+		AgentPort: h.AgentPort,
+		Labels:    formatLabels(h.Labels),
+		Status:    hostModel.StatusOffline,
+	}
+	if err := hostModel.InsertOrUpdateHost(hostCreate); err != nil {
+		return 0, err
+	}
+	return hostCreate.Id, nil
+}
+
+func (s *HostService) Get(id int) (*hostModel.Host, error) {
+	return hostModel.GetHostById(id)
+}
+
+func (s *HostService) Delete(ctx context.Context, userName string, id int) error {
+	userService := user.UserService{}
+	if !userService.IsAdmin(ctx, userName) {
+		return platformErrors.ErrPermissionDenied
+	}
+	return hostModel.DeleteHostById(id)
+}
+
+func (s *HostService) Query(hostname, ip, status, orderBy string, page, pageSize int) (int64, []hostModel.Host, error) {
+	return hostModel.QueryHosts(hostname, ip, status, orderBy, page, pageSize)
+}
+
+// SelectHosts returns the hosts matching all the given conditions, ips and hostnames are comma separated,
+// labels are in "key:value,key:value" format. At least one condition is required to avoid selecting the whole inventory
+func (s *HostService) SelectHosts(ips, hostnames, labels string) ([]hostModel.Host, error) {
+	if ips == "" && hostnames == "" && labels == "" {
+		return nil, errors.New("at least one of target ip, hostname and label is required")
+	}
+
+	hosts, err := hostModel.ListAllHosts()
+	if err != nil {
+		return nil, err
+	}
+
+	var selected []hostModel.Host
+	for _, h := range hosts {
+		if ips != "" && !containsItem(ips, h.IP) {
+			continue
+		}
+		if hostnames != "" && !containsItem(hostnames, h.Hostname) {
+			continue
+		}
+		if labels != "" && !matchLabels(parseLabels(h.Labels), parseLabels(labels)) {
+			continue
+		}
+		selected = append(selected, h)
+	}
+
+	if len(selected) == 0 {
+		return nil, errors.New("no host in the inventory matches the target")
+	}
+	return selected, nil
+}
+
+func containsItem(list, item string) bool {
+	for _, unit := range strings.Split(list, ",") {
+		if strings.TrimSpace(unit) == item {
+			return true
+		}
+	}
+	return false
+}
+
+func matchLabels(hostLabels, selector map[string]string) bool {
+	for k, v := range selector {
+		if hostLabels[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+func parseLabels(labels string) map[string]string {
+	labelMap := make(map[string]string)
+	for _, pair := range strings.Split(labels, ",") {
+		parts := strings.SplitN(pair, ":", 2)
+		if len(parts) == 2 {
+			labelMap[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+		}
+	}
+	return labelMap
+}
+
+func formatLabels(labels map[string]string) string {
+	var pairs []string
+	for k, v := range labels {
+		pairs = append(pairs, fmt.Sprintf("%s:%s", k, v))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+// HostRoutine checks whether the chaosmetad server of each host is reachable
+type HostRoutine struct {
+	context   context.Context
+	localCron *cron.Cron
+}
+
+func (r *HostRoutine) CheckHosts() {
+	hosts, err := hostModel.ListAllHosts()
+	if err != nil {
+		log.Error(err)
+		return
+	}
+
+	for _, h := range hosts {
+		status, version := hostModel.StatusOnline, ""
+		client := AgentClient{IP: h.IP, Port: h.AgentPort}
+		if version, err = client.Version(r.context); err != nil {
+			log.Warnf("host[%s] is offline: %s", h.Hostname, err.Error())
+			status = hostModel.StatusOffline
+		}
+
+		if err := hostModel.UpdateHostStatus(h.Id, status, version, time.Now()); err != nil {
+			log.Error(err)
+		}
+	}
+}
+
+func (r *HostRoutine) Start() {
+	r.CheckHosts()
+
+	localCron := cron.New()
+	if err := localCron.AddFunc("@every 1m", r.CheckHosts); err != nil {
+		log.Error(err)
+		return
+	}
+
+	localCron.Start()
+	r.localCron = localCron
+
+	select {
+	case <-r.context.Done():
+		log.Info("Receive stop signal")
+	}
+}
@@ -83,6 +83,8 @@ func InitTarget(ctx context.Context, scope basic.Scope) error {
 		CpuTarget       = basic.Target{Name: "cpu", NameCn: "cpu", Description: "Fault injection capabilities related to cpu faults", DescriptionCn: "cpu故障相关的故障注入能力"}
 		MemTarget       = basic.Target{Name: "mem", NameCn: "mem", Description: "Fault injection capabilities related to memory faults", DescriptionCn: "内存故障相关的故障注入能力"}
 		DnsTarget       = basic.Target{Name: "dns", NameCn: "dns", Description: "Fault injection capabilities related to dns faults", DescriptionCn: "dns故障相关的故障注入能力"}
+		HttpTarget      = basic.Target{Name: "http", NameCn: "http", Description: "Fault injection capabilities related to http traffic", DescriptionCn: "http流量相关的故障注入能力"}
+		GrpcTarget      = basic.Target{Name: "grpc", NameCn: "grpc", Description: "Fault injection capabilities related to grpc calls", DescriptionCn: "grpc调用相关的故障注入能力"}
 		DiskTarget      = basic.Target{Name: "disk", NameCn: "disk", Description: "Fault injection capabilities related to disk failures", DescriptionCn: "磁盘故障相关的故障注入能力"}
 		DiskioTarget    = basic.Target{Name: "diskIO", NameCn: "diskIO", Description: "Fault injection capabilities related to disk IO faults", DescriptionCn: "磁盘IO故障相关的故障注入能力"}
 		NetworkTarget   = basic.Target{Name: "network", NameCn: "network", Description: "Fault injection capabilities related to disk failures", DescriptionCn: "磁盘故障相关的故障注入能力"}
@@ -91,6 +93,7 @@ func InitTarget(ctx context.Context, scope basic.Scope) error {
 		KernelTarget    = basic.Target{Name: "kernel", NameCn: "kernel", Description: "Kernel-related fault injection capabilities", DescriptionCn: "内核相关的故障注入能力"}
 		JvmTarget       = basic.Target{Name: "jvm", NameCn: "jvm", Description: "Jvm related fault injection capabilities", DescriptionCn: "围绕jvm相关的故障注入能力"}
 		ContainerTarget = basic.Target{Name: "container", NameCn: "container", Description: "Container runtime-related fault injection capabilities", DescriptionCn: "容器运行时相关的故障注入能力"}
+		TimeTarget      = basic.Target{Name: "time", NameCn: "time", Description: "Clock-related fault injection capabilities", DescriptionCn: "时钟相关的故障注入能力"}
 	)
 
 	CpuTarget.ScopeId = scope.ID
@@ -114,6 +117,20 @@ func InitTarget(ctx context.Context, scope basic.Scope) error {
 	if err := InitDnsFault(ctx, DnsTarget); err != nil {
 		return err
 	}
+	HttpTarget.ScopeId = scope.ID
+	if err := basic.InsertTarget(ctx, &HttpTarget); err != nil {
+		return err
+	}
+	if err := InitHttpFault(ctx, HttpTarget); err != nil {
+		return err
+	}
+	GrpcTarget.ScopeId = scope.ID
+	if err := basic.InsertTarget(ctx, &GrpcTarget); err != nil {
+		return err
+	}
+	if err := InitGrpcFault(ctx, GrpcTarget); err != nil {
+		return err
+	}
 	DiskTarget.ScopeId = scope.ID
 	if err := basic.InsertTarget(ctx, &DiskTarget); err != nil {
 		return err
@@ -167,7 +184,14 @@ func InitTarget(ctx context.Context, scope basic.Scope) error {
 	if err := basic.InsertTarget(ctx, &ContainerTarget); err != nil {
 		return err
 	}
-	return InitContainerFault(ctx, ContainerTarget)
+	if err := InitContainerFault(ctx, ContainerTarget); err != nil {
+		return err
+	}
+	TimeTarget.ScopeId = scope.ID
+	if err := basic.InsertTarget(ctx, &TimeTarget); err != nil {
+		return err
+	}
+	return InitTimeFault(ctx, TimeTarget)
 }
 
 func InitCpuFault(ctx context.Context, cpuTarget basic.Target) error {
@@ -193,8 +217,12 @@ func InitCpuTargetArgsBurn(ctx context.Context, cpuFault basic.Fault) error {
 		CpuArgsPercent = basic.Args{InjectId: cpuFault.ID, ExecType: ExecInject, Key: "percent", KeyCn: "使用率", Unit: "", UnitCn: "", Description: "Target cpu usage", DescriptionCn: "目标cpu使用率", ValueType: "int", ValueRule: "1-100"}
 		CpuArgsCount   = basic.Args{InjectId: cpuFault.ID, ExecType: ExecInject, Key: "count", KeyCn: "核数", Unit: "", UnitCn: "", DefaultValue: "0", Description: "Number of faulty CPU cores, 0 means all cores", DescriptionCn: "故障cpu核数,0表示全部核", ValueType: "int", ValueRule: ">=0"}
 		CpuArgsList    = basic.Args{InjectId: cpuFault.ID, ExecType: ExecInject, Key: "list", KeyCn: "列表", Unit: "", UnitCn: "", Description: "Faulty cpu list, comma separated core number list, can be confirmed from /proc/cpuinfo", DescriptionCn: "故障cpu列表,逗号分隔的核编号列表,可以从/proc/cpuinfo确认", ValueType: "string"}
+		CpuArgsProfile = basic.Args{InjectId: cpuFault.ID, ExecType: ExecInject, Key: "profile", KeyCn: "负载波形", DefaultValue: "constant", Description: "Load waveform between min-percent and percent: constant, square wave, sine or random walk", DescriptionCn: "在min-percent和percent之间的负载波形:恒定、方波、正弦或随机游走", ValueType: "string", ValueRule: "constant,square,sine,random"}
+		CpuArgsMin     = basic.Args{InjectId: cpuFault.ID, ExecType: ExecInject, Key: "min-percent", KeyCn: "最低使用率", DefaultValue: "0", Description: "The lowest cpu usage of the waveform", DescriptionCn: "波形的最低cpu使用率", ValueType: "int", ValueRule: "0-100"}
+		CpuArgsPeriod  = basic.Args{InjectId: cpuFault.ID, ExecType: ExecInject, Key: "period", KeyCn: "波形周期", Unit: "s", UnitCn: "s", DefaultValue: "60", Description: "Seconds of a wave of the square and sine profiles, the random walk steps every period/10", DescriptionCn: "方波和正弦波形的周期秒数,随机游走每period/10变化一次", ValueType: "int", ValueRule: ">0"}
+		CpuArgsRamp    = basic.Args{InjectId: cpuFault.ID, ExecType: ExecInject, Key: "ramp", KeyCn: "爬坡时长", Unit: "s", UnitCn: "s", DefaultValue: "0", Description: "Seconds to raise the load linearly from 0 to the waveform, 0 means no ramp-up", DescriptionCn: "负载从0线性升至波形的秒数,0表示不爬坡", ValueType: "int", ValueRule: ">=0"}
 	)
-	return basic.InsertArgsMulti(ctx, []*basic.Args{&CpuArgsPercent, &CpuArgsCount, &CpuArgsList})
+	return basic.InsertArgsMulti(ctx, []*basic.Args{&CpuArgsPercent, &CpuArgsCount, &CpuArgsList, &CpuArgsProfile, &CpuArgsMin, &CpuArgsPeriod, &CpuArgsRamp})
 }
 
 func InitLoadTargetArgsLoad(ctx context.Context, loadFault basic.Fault) error {
@@ -204,8 +232,9 @@ func InitLoadTargetArgsLoad(ctx context.Context, loadFault basic.Fault) error {
 
 func InitMemFault(ctx context.Context, memTarget basic.Target) error {
 	var (
-		MemFaultFill = basic.Fault{TargetId: memTarget.ID, Name: "fill", NameCn: "内存填充", Description: "Memory usage spikes, when percentage and bytes arguments are both provided, same as percentage, bytes ignored", DescriptionCn: "内存使用率飙高,percent和bytes参数都提供的时候,以percent为准,忽略bytes"}
-		MemFaultOom  = basic.Fault{TargetId: memTarget.ID, Name: "oom", NameCn: "内存oom", Description: "The system memory oom will cause the machine to hang up", DescriptionCn: "系统内存oom,会使机器宕机挂掉"}
+		MemFaultFill       = basic.Fault{TargetId: memTarget.ID, Name: "fill", NameCn: "内存填充", Description: "Memory usage spikes, when percentage and bytes arguments are both provided, same as percentage, bytes ignored", DescriptionCn: "内存使用率飙高,percent和bytes参数都提供的时候,以percent为准,忽略bytes"}
+		MemFaultOom        = basic.Fault{TargetId: memTarget.ID, Name: "oom", NameCn: "内存oom", Description: "The system memory oom will cause the machine to hang up", DescriptionCn: "系统内存oom,会使机器宕机挂掉"}
+		MemFaultCgroupFill = basic.Fault{TargetId: memTarget.ID, Name: "cgroupfill", NameCn: "cgroup内存填充", Description: "Fill memory in a dedicated cgroup charged to the container, staying below its memory limit or going beyond it to trigger its OOM killer, supports cgroup v1 and v2", DescriptionCn: "在容器内存cgroup下的独立cgroup中填充内存,可保持在内存限制以下或超出限制触发容器的OOM,支持cgroup v1和v2"}
 	)
 
 	if err := basic.InsertFault(ctx, &MemFaultFill); err != nil {
@@ -218,7 +247,24 @@ func InitMemFault(ctx context.Context, memTarget basic.Target) error {
 		return err
 	}
 
-	return InitMemTargetArgsOom(ctx, MemFaultOom)
+	if err := InitMemTargetArgsOom(ctx, MemFaultOom); err != nil {
+		return err
+	}
+	if err := basic.InsertFault(ctx, &MemFaultCgroupFill); err != nil {
+		return err
+	}
+
+	return InitMemTargetArgsCgroupFill(ctx, MemFaultCgroupFill)
+}
+
+func InitMemTargetArgsCgroupFill(ctx context.Context, memFault basic.Fault) error {
+	var (
+		MemArgsPercent = basic.Args{InjectId: memFault.ID, ExecType: ExecInject, Key: "percent", KeyCn: "内存使用率", Description: "Target mem usage of the memory limit", DescriptionCn: "占内存限制的目标内存使用率", ValueType: "int", ValueRule: "1-100"}
+		MemArgsBytes   = basic.Args{InjectId: memFault.ID, ExecType: ExecInject, Key: "bytes", KeyCn: "填充量", Unit: "KB,MB,GB,TB", UnitCn: "KB,MB,GB,TB", Description: "Memory fill", DescriptionCn: "内存填充量", ValueType: "string"}
+		MemArgsOOM     = basic.Args{InjectId: memFault.ID, ExecType: ExecInject, Key: "oom", KeyCn: "触发OOM", DefaultValue: "false", Description: "Fill beyond the memory limit of the container to trigger its OOM killer, otherwise the fill stays below the limit", DescriptionCn: "超出容器内存限制填充以触发容器的OOM,否则填充保持在限制以下", ValueType: "bool", ValueRule: "true,false"}
+		MemArgsReserve = basic.Args{InjectId: memFault.ID, ExecType: ExecInject, Key: "reserve", KeyCn: "与限制的距离", Unit: "KB,MB,GB,TB", UnitCn: "KB,MB,GB,TB", DefaultValue: "32MB", Description: "Distance to the memory limit, below it without oom and beyond it with oom", DescriptionCn: "与内存限制的距离,不触发OOM时低于限制,触发OOM时超出限制", ValueType: "string"}
+	)
+	return basic.InsertArgsMulti(ctx, []*basic.Args{&MemArgsPercent, &MemArgsBytes, &MemArgsOOM, &MemArgsReserve})
 }
 
 func InitMemTargetArgsFill(ctx context.Context, memFault basic.Fault) error {
@@ -237,8 +283,11 @@ func InitMemTargetArgsOom(ctx context.Context, memFault basic.Fault) error {
 
 func InitDnsFault(ctx context.Context, memTarget basic.Target) error {
 	var (
-		DnsFaultRecord = basic.Fault{TargetId: memTarget.ID, Name: "record", NameCn: "篡改dns记录", Description: "Modify the /etc/hosts file to mutate the target domain name resolution", DescriptionCn: "修改/etc/hosts文件,使目标域名解析变异"}
-		DnsFaultServer = basic.Fault{TargetId: memTarget.ID, Name: "server", NameCn: "dns服务器篡改", Description: "Modify /etc/resolv.conf to make the dns server abnormal", DescriptionCn: "修改/etc/resolv.conf，使dns服务器异常"}
+		DnsFaultRecord   = basic.Fault{TargetId: memTarget.ID, Name: "record", NameCn: "篡改dns记录", Description: "Modify the /etc/hosts file to mutate the target domain name resolution", DescriptionCn: "修改/etc/hosts文件,使目标域名解析变异"}
+		DnsFaultServer   = basic.Fault{TargetId: memTarget.ID, Name: "server", NameCn: "dns服务器篡改", Description: "Modify /etc/resolv.conf to make the dns server abnormal", DescriptionCn: "修改/etc/resolv.conf，使dns服务器异常"}
+		DnsFaultHijack   = basic.Fault{TargetId: memTarget.ID, Name: "hijack", NameCn: "域名劫持", Description: "Resolve the target domains to the given ip by /etc/hosts or a local dnsmasq", DescriptionCn: "通过/etc/hosts或本地dnsmasq将目标域名解析到指定ip"}
+		DnsFaultNxdomain = basic.Fault{TargetId: memTarget.ID, Name: "nxdomain", NameCn: "域名不存在", Description: "Return NXDOMAIN for the target domains by a local dnsmasq", DescriptionCn: "通过本地dnsmasq使目标域名解析返回NXDOMAIN"}
+		DnsFaultDelay    = basic.Fault{TargetId: memTarget.ID, Name: "delay", NameCn: "域名解析延迟", Description: "Delay the dns queries sent to port 53", DescriptionCn: "延迟发往53端口的dns查询"}
 	)
 
 	if err := basic.InsertFault(ctx, &DnsFaultRecord); err != nil {
@@ -250,8 +299,26 @@ func InitDnsFault(ctx context.Context, memTarget basic.Target) error {
 	if err := basic.InsertFault(ctx, &DnsFaultServer); err != nil {
 		return err
 	}
+	if err := InitDnsTargetArgsServer(ctx, DnsFaultServer); err != nil {
+		return err
+	}
+	if err := basic.InsertFault(ctx, &DnsFaultHijack); err != nil {
+		return err
+	}
+	if err := InitDnsTargetArgsHijack(ctx, DnsFaultHijack); err != nil {
+		return err
+	}
+	if err := basic.InsertFault(ctx, &DnsFaultNxdomain); err != nil {
+		return err
+	}
+	if err := InitDnsTargetArgsNxdomain(ctx, DnsFaultNxdomain); err != nil {
+		return err
+	}
+	if err := basic.InsertFault(ctx, &DnsFaultDelay); err != nil {
+		return err
+	}
 
-	return InitDnsTargetArgsServer(ctx, DnsFaultServer)
+	return InitDnsTargetArgsDelay(ctx, DnsFaultDelay)
 }
 
 func InitDnsTargetArgsRecord(ctx context.Context, dnsFault basic.Fault) error {
@@ -271,6 +338,98 @@ func InitDnsTargetArgsServer(ctx context.Context, dnsFault basic.Fault) error {
 	return basic.InsertArgsMulti(ctx, []*basic.Args{&DiskArgsIp, &DiskArgsMode})
 }
 
+func InitDnsTargetArgsHijack(ctx context.Context, dnsFault basic.Fault) error {
+	var (
+		DnsArgsDomain    = basic.Args{InjectId: dnsFault.ID, ExecType: ExecInject, Key: "domain", KeyCn: "目标域名", Description: "Target domain names, separated by commas", DescriptionCn: "目标域名,多个以逗号分隔", ValueType: "string", Required: true}
+		DnsArgsIp        = basic.Args{InjectId: dnsFault.ID, ExecType: ExecInject, Key: "ip", KeyCn: "劫持到的ip", Description: "The ip which domain names are resolved to", DescriptionCn: "域名被解析到的ip", ValueType: "string", Required: true}
+		DnsArgsIntercept = basic.Args{InjectId: dnsFault.ID, ExecType: ExecInject, Key: "intercept", KeyCn: "拦截方式", DefaultValue: "hosts", Description: "Intercept mode, dnsmasq needs to be installed in target", DescriptionCn: "拦截方式,dnsmasq需要目标环境已安装", ValueType: "string", ValueRule: "hosts,dnsmasq"}
+	)
+	return basic.InsertArgsMulti(ctx, []*basic.Args{&DnsArgsDomain, &DnsArgsIp, &DnsArgsIntercept})
+}
+
+func InitDnsTargetArgsNxdomain(ctx context.Context, dnsFault basic.Fault) error {
+	var (
+		DnsArgsDomain    = basic.Args{InjectId: dnsFault.ID, ExecType: ExecInject, Key: "domain", KeyCn: "目标域名", Description: "Target domain names, separated by commas", DescriptionCn: "目标域名,多个以逗号分隔", ValueType: "string", Required: true}
+		DnsArgsIntercept = basic.Args{InjectId: dnsFault.ID, ExecType: ExecInject, Key: "intercept", KeyCn: "拦截方式", DefaultValue: "dnsmasq", Description: "Intercept mode, dnsmasq needs to be installed in target", DescriptionCn: "拦截方式,dnsmasq需要目标环境已安装", ValueType: "string", ValueRule: "dnsmasq"}
+	)
+	return basic.InsertArgsMulti(ctx, []*basic.Args{&DnsArgsDomain, &DnsArgsIntercept})
+}
+
+func InitDnsTargetArgsDelay(ctx context.Context, dnsFault basic.Fault) error {
+	var (
+		DnsArgsInterface = basic.Args{InjectId: dnsFault.ID, ExecType: ExecInject, Key: "interface", KeyCn: "网卡", Description: "The network card which dns queries go out from, such as eth0", DescriptionCn: "dns查询流出的网卡,比如eth0", Required: true, ValueType: "string"}
+		DnsArgsLatency   = basic.Args{InjectId: dnsFault.ID, ExecType: ExecInject, Key: "latency", KeyCn: "延迟时间", Unit: "us,ms,s", UnitCn: "us,ms,s", Description: "Delay time", DescriptionCn: "延迟时间", ValueType: "int", Required: true}
+		DnsArgsJitter    = basic.Args{InjectId: dnsFault.ID, ExecType: ExecInject, Key: "jitter", KeyCn: "抖动值", Unit: "us,ms,s", UnitCn: "us,ms,s", Description: "Jitter value, the fluctuation range of each delay", DescriptionCn: "抖动值,每次延迟的波动范围", DefaultValue: "0", ValueType: "int"}
+		DnsArgsServer    = basic.Args{InjectId: dnsFault.ID, ExecType: ExecInject, Key: "server", KeyCn: "dns服务器ip", Description: "Only delay the queries sent to these dns servers, separated by commas", DescriptionCn: "只延迟发往这些dns服务器的查询,多个以逗号分隔", ValueType: "string"}
+		DnsArgsForce     = basic.Args{InjectId: dnsFault.ID, ExecType: ExecInject, Key: "force", KeyCn: "是否强制覆盖", DefaultValue: "false", Description: "Whether to force overwrite", DescriptionCn: "是否强制覆盖", ValueType: "bool", ValueRule: "true,false"}
+	)
+	return basic.InsertArgsMulti(ctx, []*basic.Args{&DnsArgsInterface, &DnsArgsLatency, &DnsArgsJitter, &DnsArgsServer, &DnsArgsForce})
+}
+
+func InitHttpFault(ctx context.Context, httpTarget basic.Target) error {
+	var (
+		HttpFaultDelay    = basic.Fault{TargetId: httpTarget.ID, Name: "delay", NameCn: "http响应延迟", Description: "Delay the http responses of the target port by a transparent proxy", DescriptionCn: "通过透明代理延迟目标端口的http响应"}
+		HttpFaultCode     = basic.Fault{TargetId: httpTarget.ID, Name: "code", NameCn: "http状态码篡改", Description: "Rewrite the status code of the http responses of the target port by a transparent proxy", DescriptionCn: "通过透明代理篡改目标端口http响应的状态码"}
+		HttpFaultTruncate = basic.Fault{TargetId: httpTarget.ID, Name: "truncate", NameCn: "http响应截断", Description: "Truncate the body of the http responses of the target port by a transparent proxy", DescriptionCn: "通过透明代理截断目标端口http响应的body"}
+	)
+
+	if err := basic.InsertFault(ctx, &HttpFaultDelay); err != nil {
+		return err
+	}
+	if err := InitHttpTargetArgs(ctx, HttpFaultDelay, basic.Args{InjectId: HttpFaultDelay.ID, ExecType: ExecInject, Key: "latency", KeyCn: "延迟时间", Unit: "ms,s", UnitCn: "ms,s", Description: "Delay of the response", DescriptionCn: "响应的延迟时间", ValueType: "string", Required: true}); err != nil {
+		return err
+	}
+	if err := basic.InsertFault(ctx, &HttpFaultCode); err != nil {
+		return err
+	}
+	if err := InitHttpTargetArgs(ctx, HttpFaultCode, basic.Args{InjectId: HttpFaultCode.ID, ExecType: ExecInject, Key: "code", KeyCn: "状态码", Description: "Status code the responses are rewritten to", DescriptionCn: "响应被篡改成的状态码", ValueType: "int", Required: true, ValueRule: "100-599"}); err != nil {
+		return err
+	}
+	if err := basic.InsertFault(ctx, &HttpFaultTruncate); err != nil {
+		return err
+	}
+	return InitHttpTargetArgs(ctx, HttpFaultTruncate, basic.Args{InjectId: HttpFaultTruncate.ID, ExecType: ExecInject, Key: "bytes", KeyCn: "保留字节数", Unit: "B,KB,MB", UnitCn: "B,KB,MB", Description: "Bytes of the response body to keep", DescriptionCn: "响应body保留的字节数", ValueType: "string", Required: true})
+}
+
+// InitHttpTargetArgs the http faults share the proxy args, only the fault value differs
+func InitHttpTargetArgs(ctx context.Context, httpFault basic.Fault, valueArgs basic.Args) error {
+	var (
+		HttpArgsPort      = basic.Args{InjectId: httpFault.ID, ExecType: ExecInject, Key: "port", KeyCn: "目标端口", Description: "Target http port", DescriptionCn: "目标http端口", ValueType: "int", Required: true, ValueRule: "1-65535"}
+		HttpArgsDirection = basic.Args{InjectId: httpFault.ID, ExecType: ExecInject, Key: "direction", KeyCn: "流量方向", DefaultValue: "in", Description: "in: requests to the local server on port, out: requests from local clients to remote port", DescriptionCn: "in:发往本地服务端口的请求, out:本地客户端发往远端端口的请求", ValueType: "string", ValueRule: "in,out"}
+		HttpArgsPath      = basic.Args{InjectId: httpFault.ID, ExecType: ExecInject, Key: "path", KeyCn: "路径前缀", DefaultValue: "/", Description: "Only inject requests whose path has this prefix", DescriptionCn: "只对路径带有该前缀的请求注入", ValueType: "string"}
+	)
+	return basic.InsertArgsMulti(ctx, []*basic.Args{&HttpArgsPort, &HttpArgsDirection, &HttpArgsPath, &valueArgs})
+}
+
+func InitGrpcFault(ctx context.Context, grpcTarget basic.Target) error {
+	var (
+		GrpcFaultDelay = basic.Fault{TargetId: grpcTarget.ID, Name: "delay", NameCn: "grpc调用延迟", Description: "Delay the grpc calls of the target service or method by a transparent http/2 proxy", DescriptionCn: "通过透明http/2代理延迟目标服务或方法的grpc调用"}
+		GrpcFaultCode  = basic.Fault{TargetId: grpcTarget.ID, Name: "code", NameCn: "grpc错误码", Description: "Fail the grpc calls of the target service or method with a status code by a transparent http/2 proxy", DescriptionCn: "通过透明http/2代理使目标服务或方法的grpc调用返回错误码"}
+	)
+
+	if err := basic.InsertFault(ctx, &GrpcFaultDelay); err != nil {
+		return err
+	}
+	if err := InitGrpcTargetArgs(ctx, GrpcFaultDelay, basic.Args{InjectId: GrpcFaultDelay.ID, ExecType: ExecInject, Key: "latency", KeyCn: "延迟时间", Unit: "ms,s", UnitCn: "ms,s", Description: "Delay of the grpc calls", DescriptionCn: "grpc调用的延迟时间", ValueType: "string", Required: true}); err != nil {
+		return err
+	}
+	if err := basic.InsertFault(ctx, &GrpcFaultCode); err != nil {
+		return err
+	}
+	return InitGrpcTargetArgs(ctx, GrpcFaultCode, basic.Args{InjectId: GrpcFaultCode.ID, ExecType: ExecInject, Key: "code", KeyCn: "grpc状态码", Description: "Grpc status code the calls fail with, eg: 14(UNAVAILABLE)", DescriptionCn: "grpc调用返回的状态码,如:14(UNAVAILABLE)", ValueType: "int", Required: true, ValueRule: "1-16"})
+}
+
+// InitGrpcTargetArgs the grpc calls are selected by service and method instead of the http path
+func InitGrpcTargetArgs(ctx context.Context, grpcFault basic.Fault, valueArgs basic.Args) error {
+	var (
+		GrpcArgsPort      = basic.Args{InjectId: grpcFault.ID, ExecType: ExecInject, Key: "port", KeyCn: "目标端口", Description: "Target grpc port", DescriptionCn: "目标grpc端口", ValueType: "int", Required: true, ValueRule: "1-65535"}
+		GrpcArgsDirection = basic.Args{InjectId: grpcFault.ID, ExecType: ExecInject, Key: "direction", KeyCn: "流量方向", DefaultValue: "in", Description: "in: calls to the local server on port, out: calls from local clients to remote port", DescriptionCn: "in:发往本地服务端口的调用, out:本地客户端发往远端端口的调用", ValueType: "string", ValueRule: "in,out"}
+		GrpcArgsService   = basic.Args{InjectId: grpcFault.ID, ExecType: ExecInject, Key: "service", KeyCn: "grpc服务", Description: "Full name of the grpc service, eg: helloworld.Greeter", DescriptionCn: "grpc服务全名,如:helloworld.Greeter", ValueType: "string", Required: true}
+		GrpcArgsMethod    = basic.Args{InjectId: grpcFault.ID, ExecType: ExecInject, Key: "method", KeyCn: "grpc方法", Description: "Grpc method name, all methods of the service if empty", DescriptionCn: "grpc方法名,为空表示服务的所有方法", ValueType: "string"}
+	)
+	return basic.InsertArgsMulti(ctx, []*basic.Args{&GrpcArgsPort, &GrpcArgsDirection, &GrpcArgsService, &GrpcArgsMethod, &valueArgs})
+}
+
 func InitDiskFault(ctx context.Context, diskTarget basic.Target) error {
 	var DiskFaultFill = basic.Fault{TargetId: diskTarget.ID, Name: "fill", NameCn: "磁盘填充", Description: "The disk usage is so high, when both the percent and bytes parameters are provided, the percent will prevail and bytes will be ignored", DescriptionCn: "磁盘使用率飙高,percent和bytes参数都提供的时候,以percent为准,忽略bytes"}
 	if err := basic.InsertFault(ctx, &DiskFaultFill); err != nil {
@@ -284,8 +443,10 @@ func InitDiskTargetArgsFill(ctx context.Context, diskFault basic.Fault) error {
 		DiskArgsPercent = basic.Args{InjectId: diskFault.ID, ExecType: ExecInject, Key: "percent", KeyCn: "磁盘使用率", Unit: "", UnitCn: "", Description: "Target disk usage", DescriptionCn: "目标磁盘使用率", ValueType: "int", Required: true, ValueRule: "1-100"}
 		DiskArgsBytes   = basic.Args{InjectId: diskFault.ID, ExecType: ExecInject, Key: "bytes", KeyCn: "填充量", Unit: "KB,MB,GB,TB", UnitCn: "KB,MB,GB,TB", Description: "Memory fill", DescriptionCn: "磁盘填充量", ValueType: "string"}
 		DiskArgsDir     = basic.Args{InjectId: diskFault.ID, ExecType: ExecInject, Key: "dir", KeyCn: "目录", Unit: "", UnitCn: "", DefaultValue: "/tmp", Description: "Target population directory", DescriptionCn: "目标填充目录", ValueType: "string"}
+		DiskArgsReserve = basic.Args{InjectId: diskFault.ID, ExecType: ExecInject, Key: "reserve", KeyCn: "保留空间", Unit: "KB,MB,GB,TB", UnitCn: "KB,MB,GB,TB", DefaultValue: "100MB", Description: "Free space always kept, the target usage is lowered to keep it", DescriptionCn: "始终保留的空闲空间,会降低目标使用率来保证", ValueType: "string"}
+		DiskArgsForce   = basic.Args{InjectId: diskFault.ID, ExecType: ExecInject, Key: "force", KeyCn: "允许填充根文件系统", DefaultValue: "false", Description: "Allow to fill the root filesystem, not needed for the default dir /tmp", DescriptionCn: "是否允许填充根文件系统,默认目录/tmp无需设置", ValueType: "bool", ValueRule: "true,false"}
 	)
-	return basic.InsertArgsMulti(ctx, []*basic.Args{&DiskArgsPercent, &DiskArgsBytes, &DiskArgsDir})
+	return basic.InsertArgsMulti(ctx, []*basic.Args{&DiskArgsPercent, &DiskArgsBytes, &DiskArgsDir, &DiskArgsReserve, &DiskArgsForce})
 }
 
 func InitDiskioFault(ctx context.Context, diskioTarget basic.Target) error {
@@ -293,6 +454,8 @@ func InitDiskioFault(ctx context.Context, diskioTarget basic.Target) error {
 		DiskioFaultBurn  = basic.Fault{TargetId: diskioTarget.ID, Name: "burn", NameCn: "磁盘IO高负载", Description: "Disk IO soars", DescriptionCn: "磁盘IO飙高"}
 		DiskioFaultHang  = basic.Fault{TargetId: diskioTarget.ID, Name: "hang", NameCn: "磁盘IO hang", Description: "The target process generates a disk IO hang; provide at least one of the pid-list and key parameters. When both are provided, the pid-list will prevail and the key will be ignored; the principle of this injection capability is to limit the process to only 1byte of IO per second.Therefore, it has little impact on processes with too small IO", DescriptionCn: "目标进程产生磁盘IO hang;pid-list和key参数至少提供一个,都提供的时候,以pid-list为准,忽略key;此注入能力的原理是限制进程每秒只能进行1byte大小的IO,所以对IO过小的进程影响不大"}
 		DiskioFaultLimit = basic.Fault{TargetId: diskioTarget.ID, Name: "limit", NameCn: "磁盘IO limit", Description: "The target process generates a disk IO limit; when both pid-list and key parameters are provided, the pid-list shall prevail and the key shall be ignored; at least one of the four limit parameters must be provided, and multiple limits are in an \"AND\" relationship", DescriptionCn: "目标进程产生磁盘IO limit;pid-list和key参数都提供的时候,以pid-list为准,忽略key;四个限制参数至少提供一个,多个限制是“与”的关系"}
+		DiskioFaultDelay = basic.Fault{TargetId: diskioTarget.ID, Name: "delay", NameCn: "磁盘IO延迟", Description: "Add latency to the IO of a device-mapper device(such as lvm) by dm-delay; one of dev and path must be provided, only supported on host", DescriptionCn: "通过dm-delay为device-mapper设备(如lvm)的IO增加延迟;dev和path至少提供一个,只支持物理机"}
+		DiskioFaultError = basic.Fault{TargetId: diskioTarget.ID, Name: "error", NameCn: "磁盘IO错误", Description: "Make the IO of a device-mapper device(such as lvm) fail with EIO by dm-flakey; one of dev and path must be provided, only supported on host", DescriptionCn: "通过dm-flakey使device-mapper设备(如lvm)的IO返回EIO错误;dev和path至少提供一个,只支持物理机"}
 	)
 	if err := basic.InsertFault(ctx, &DiskioFaultBurn); err != nil {
 		return err
@@ -310,7 +473,35 @@ func InitDiskioFault(ctx context.Context, diskioTarget basic.Target) error {
 	if err := basic.InsertFault(ctx, &DiskioFaultLimit); err != nil {
 		return err
 	}
-	return InitDiskioTargetArgsLimit(ctx, DiskioFaultLimit)
+	if err := InitDiskioTargetArgsLimit(ctx, DiskioFaultLimit); err != nil {
+		return err
+	}
+	if err := basic.InsertFault(ctx, &DiskioFaultDelay); err != nil {
+		return err
+	}
+	if err := InitDiskioTargetArgsDM(ctx, DiskioFaultDelay, basic.Args{InjectId: DiskioFaultDelay.ID, ExecType: ExecInject, Key: "latency", KeyCn: "延迟时间", Unit: "ms,s", UnitCn: "ms,s", Description: "Latency added to each IO", DescriptionCn: "每次IO增加的延迟", ValueType: "string", Required: true}); err != nil {
+		return err
+	}
+	if err := basic.InsertFault(ctx, &DiskioFaultError); err != nil {
+		return err
+	}
+	return InitDiskioTargetArgsDM(ctx, DiskioFaultError,
+		basic.Args{InjectId: DiskioFaultError.ID, ExecType: ExecInject, Key: "up-interval", KeyCn: "正常时长", Unit: "s", UnitCn: "s", DefaultValue: "0", Description: "Seconds the IO works normally in each cycle, 0 means IO always fails", DescriptionCn: "每个周期内IO正常的秒数,0表示IO一直失败", ValueType: "int"},
+		basic.Args{InjectId: DiskioFaultError.ID, ExecType: ExecInject, Key: "down-interval", KeyCn: "错误时长", Unit: "s", UnitCn: "s", DefaultValue: "1", Description: "Seconds the IO fails in each cycle", DescriptionCn: "每个周期内IO失败的秒数", ValueType: "int"})
+}
+
+// InitDiskioTargetArgsDM the faults based on device-mapper share the device args
+func InitDiskioTargetArgsDM(ctx context.Context, diskioFault basic.Fault, faultArgs ...basic.Args) error {
+	var (
+		DiskioArgsDev  = basic.Args{InjectId: diskioFault.ID, ExecType: ExecInject, Key: "dev", KeyCn: "dm设备", Description: "Target device-mapper device, such as /dev/mapper/vg-data", DescriptionCn: "目标device-mapper设备,比如/dev/mapper/vg-data", ValueType: "string"}
+		DiskioArgsPath = basic.Args{InjectId: diskioFault.ID, ExecType: ExecInject, Key: "path", KeyCn: "路径", Description: "Use the device which this path is located on, dev will be ignored if provided", DescriptionCn: "使用该路径所在的设备,提供时忽略dev", ValueType: "string"}
+		DiskioArgsMode = basic.Args{InjectId: diskioFault.ID, ExecType: ExecInject, Key: "mode", KeyCn: "IO模式", DefaultValue: "all", Description: "Affected IO operation", DescriptionCn: "受影响的IO操作", ValueType: "string", ValueRule: "all,read,write"}
+	)
+	args := []*basic.Args{&DiskioArgsDev, &DiskioArgsPath, &DiskioArgsMode}
+	for i := range faultArgs {
+		args = append(args, &faultArgs[i])
+	}
+	return basic.InsertArgsMulti(ctx, args)
 }
 
 func InitDiskioTargetArgsBurn(ctx context.Context, diskioFault basic.Fault) error {
@@ -355,6 +546,7 @@ func InitNetworkFault(ctx context.Context, networkTarget basic.Target) error {
 
 		NetworkFaultDuplicate = basic.Fault{TargetId: networkTarget.ID, Name: "duplicate", NameCn: "网络包重复", Description: "Repeat for network packet injection packets in the outflow direction from the faulty machine; interface must be provided, packet filtering parameters can be optionally provided, \"relationship with\"", DescriptionCn: "对从故障机器流出方向的网络数据包注入包重复;interface必须提供，数据包筛选参数可以选择性提供,“与”的关系"}
 		NetworkFaultReorder   = basic.Fault{TargetId: networkTarget.ID, Name: "reorder", NameCn: "网络包乱序", Description: "Inject packet reordering into network data packets flowing out from the faulty machine; the interface must be provided, and the packet filtering parameters can be optionally provided, the relationship between \"and\"", DescriptionCn: "对从故障机器流出方向的网络数据包注入包乱序;interface必须提供，数据包筛选参数可以选择性提供,“与”的关系"}
+		NetworkFaultPartition = basic.Fault{TargetId: networkTarget.ID, Name: "partition", NameCn: "网络分区", Description: "Drop the traffic of both directions between two ip groups on the faulty machine, used to simulate split-brain", DescriptionCn: "在故障机器上丢弃两组ip之间双向的网络流量,用于模拟脑裂"}
 	)
 
 	if err := basic.InsertFault(ctx, &NetworkFaultOccupy); err != nil {
@@ -396,20 +588,27 @@ func InitNetworkFault(ctx context.Context, networkTarget basic.Target) error {
 	if err := basic.InsertFault(ctx, &NetworkFaultReorder); err != nil {
 		return err
 	}
-	return InitNetworkTargetArgsReorder(ctx, NetworkFaultReorder)
+	if err := InitNetworkTargetArgsReorder(ctx, NetworkFaultReorder); err != nil {
+		return err
+	}
+	if err := basic.InsertFault(ctx, &NetworkFaultPartition); err != nil {
+		return err
+	}
+	return InitNetworkTargetArgsPartition(ctx, NetworkFaultPartition)
 }
 
 func getNetworkCommonFilterParameters(fault basic.Fault) []*basic.Args {
 	var (
 		NetworkArgsInterface = basic.Args{InjectId: fault.ID, ExecType: ExecInject, Key: "interface", KeyCn: "网卡", Description: "The network card included in the faulty machine, such as eth0", DescriptionCn: "故障机器包含的网卡,比如eth0", Required: true, ValueType: "string"}
-		NetworkArgsDstIP     = basic.Args{InjectId: fault.ID, ExecType: ExecInject, Key: "dst-ip", KeyCn: "目标ip列表", Description: "Target IP list, such as: 1.2.3.4, 2.3.4.5, 192.168.1.1/24", DescriptionCn: "目标ip列表,比如1.2.3.4,2.3.4.5,192.168.1.1/24", ValueType: "stringlist"}
-		NetworkArgsSrcIP     = basic.Args{InjectId: fault.ID, ExecType: ExecInject, Key: "src-ip", KeyCn: "数据包筛选参数：源ip列表", Description: "Source IP list, for example: 1.2.3.4,2.3.4.5,192.168.1.1/24", DescriptionCn: "源ip列表，比如1.2.3.4,2.3.4.5,192.168.1.1/24", ValueType: "stringlist"}
+		NetworkArgsDstIP     = basic.Args{InjectId: fault.ID, ExecType: ExecInject, Key: "dst-ip", KeyCn: "目标ip列表", Description: "Target IP list, ipv4 and ipv6 are supported, such as: 1.2.3.4, 2.3.4.5, 192.168.1.1/24, fd00::1/64", DescriptionCn: "目标ip列表,支持ipv4和ipv6,比如1.2.3.4,2.3.4.5,192.168.1.1/24,fd00::1/64", ValueType: "stringlist"}
+		NetworkArgsSrcIP     = basic.Args{InjectId: fault.ID, ExecType: ExecInject, Key: "src-ip", KeyCn: "数据包筛选参数：源ip列表", Description: "Source IP list, ipv4 and ipv6 are supported, for example: 1.2.3.4,2.3.4.5,192.168.1.1/24,fd00::1/64", DescriptionCn: "源ip列表，支持ipv4和ipv6,比如1.2.3.4,2.3.4.5,192.168.1.1/24,fd00::1/64", ValueType: "stringlist"}
 		NetworkArgsDstPort   = basic.Args{InjectId: fault.ID, ExecType: ExecInject, Key: "dst-port", KeyCn: "数据包筛选参数：目标端口列表", Description: "Target port list, for example: 8080-8090,8095,9099", DescriptionCn: "目标端口列表，比如8080-8090,8095,9099", ValueType: "stringlist"}
 		NetworkArgsSrcPort   = basic.Args{InjectId: fault.ID, ExecType: ExecInject, Key: "src-port", KeyCn: "数据包筛选参数：源端口列表", Description: "Source port list, such as 8080-8090,8095,9099", DescriptionCn: "源端口列表，比如8080-8090,8095,9099", ValueType: "stringlist"}
+		NetworkArgsProtocol  = basic.Args{InjectId: fault.ID, ExecType: ExecInject, Key: "protocol", KeyCn: "数据包筛选参数：协议", Description: "Protocol, ports can only be used with tcp and udp", DescriptionCn: "协议,端口筛选只能和tcp、udp一起使用", ValueType: "string", ValueRule: "tcp,udp,icmp"}
 		NetworkArgsMode      = basic.Args{InjectId: fault.ID, ExecType: ExecInject, Key: "mode", KeyCn: "数据包筛选模式", Unit: "", UnitCn: "", DefaultValue: "normal", Description: "Normal: inject fault to selected targets, exclude: do not inject fault to selected targets", DescriptionCn: "normal:对选中的目标注入故障,exclude:对选中的目标不注入故障", ValueType: "string", ValueRule: "normal,exclude"}
 		NetworkArgsForce     = basic.Args{InjectId: fault.ID, ExecType: ExecInject, Key: "force", KeyCn: "是否强制覆盖", DefaultValue: "false", Description: "Whether to force overwrite", DescriptionCn: "是否强制覆盖", ValueType: "bool", ValueRule: "true,false"}
 	)
-	return []*basic.Args{&NetworkArgsInterface, &NetworkArgsDstIP, &NetworkArgsSrcIP, &NetworkArgsDstPort, &NetworkArgsSrcPort, &NetworkArgsMode, &NetworkArgsForce}
+	return []*basic.Args{&NetworkArgsInterface, &NetworkArgsDstIP, &NetworkArgsSrcIP, &NetworkArgsDstPort, &NetworkArgsSrcPort, &NetworkArgsProtocol, &NetworkArgsMode, &NetworkArgsForce}
 }
 
 func InitNetworkTargetArgsOccupy(ctx context.Context, networkFault basic.Fault) error {
@@ -475,10 +674,22 @@ func InitNetworkTargetArgsReorder(ctx context.Context, networkFault basic.Fault)
 	return basic.InsertArgsMulti(ctx, argList)
 }
 
+func InitNetworkTargetArgsPartition(ctx context.Context, networkFault basic.Fault) error {
+	var (
+		NetworkArgsGroupA  = basic.Args{InjectId: networkFault.ID, ExecType: ExecInject, Key: "group-a", KeyCn: "分区A的ip列表", Description: "IP list of one side of the partition, such as: 1.2.3.4,192.168.1.1/24", DescriptionCn: "分区一侧的ip列表,比如1.2.3.4,192.168.1.1/24", ValueType: "stringlist", Required: true}
+		NetworkArgsGroupB  = basic.Args{InjectId: networkFault.ID, ExecType: ExecInject, Key: "group-b", KeyCn: "分区B的ip列表", Description: "IP list of the other side of the partition, such as: 2.3.4.5,192.168.2.1/24", DescriptionCn: "分区另一侧的ip列表,比如2.3.4.5,192.168.2.1/24", ValueType: "stringlist", Required: true}
+		NetworkArgsBackend = basic.Args{InjectId: networkFault.ID, ExecType: ExecInject, Key: "backend", KeyCn: "防火墙后端", DefaultValue: "auto", Description: "Firewall backend, auto means detecting iptables-legacy, iptables-nft or nft automatically", DescriptionCn: "防火墙后端,auto表示自动识别iptables-legacy、iptables-nft或nft", ValueType: "string", ValueRule: "auto,iptables-legacy,iptables-nft,nft"}
+	)
+	return basic.InsertArgsMulti(ctx, []*basic.Args{&NetworkArgsGroupA, &NetworkArgsGroupB, &NetworkArgsBackend})
+}
+
 func InitProcessFault(ctx context.Context, processTarget basic.Target) error {
 	var (
-		processFaultKill = basic.Fault{TargetId: processTarget.ID, Name: "kill", NameCn: "杀进程", Description: "To kill the target process, provide at least one of the pid and key parameters. When both are provided, the pid will prevail and the key will be ignored.", DescriptionCn: "把目标进程杀掉,pid和key参数至少提供一个,都提供的时候,以pid为准,忽略key"}
-		processFaultStop = basic.Fault{TargetId: processTarget.ID, Name: "stop", NameCn: "停止进程", Description: "Stop the target process. Provide at least one of the pid and key parameters. When both are provided, the pid will prevail and the key will be ignored.", DescriptionCn: "停止目标进程,pid和key参数至少提供一个,都提供的时候,以pid为准,忽略key"}
+		processFaultKill      = basic.Fault{TargetId: processTarget.ID, Name: "kill", NameCn: "杀进程", Description: "To kill the target process, provide at least one of the pid and key parameters. When both are provided, the pid will prevail and the key will be ignored.", DescriptionCn: "把目标进程杀掉,pid和key参数至少提供一个,都提供的时候,以pid为准,忽略key"}
+		processFaultStop      = basic.Fault{TargetId: processTarget.ID, Name: "stop", NameCn: "停止进程", Description: "Stop the target process. Provide at least one of the pid and key parameters. When both are provided, the pid will prevail and the key will be ignored.", DescriptionCn: "停止目标进程,pid和key参数至少提供一个,都提供的时候,以pid为准,忽略key"}
+		processFaultFdfull    = basic.Fault{TargetId: processTarget.ID, Name: "fdfull", NameCn: "进程fd耗尽", Description: "Lower the soft limit of open files of the target processes to their open fd count, so that opening new fds fails", DescriptionCn: "把目标进程的打开文件数软限制降低到当前已打开的fd数,使打开新fd失败"}
+		processFaultPriority  = basic.Fault{TargetId: processTarget.ID, Name: "priority", NameCn: "进程优先级", Description: "Change the cpu priority(nice) and io priority(ionice) of all threads of the target processes", DescriptionCn: "修改目标进程所有线程的cpu优先级(nice)和io优先级(ionice)"}
+		processFaultFuncDelay = basic.Fault{TargetId: processTarget.ID, Name: "funcdelay", NameCn: "函数调用延迟", Description: "Delay the calls of a libc or application function of the target process through eBPF uprobes, the whole process is paused during the delay. Requires bpftrace and kernel 5.3+", DescriptionCn: "通过eBPF uprobe延迟目标进程对libc或应用函数的调用,延迟期间整个进程暂停。依赖bpftrace和5.3以上内核"}
 	)
 	if err := basic.InsertFault(ctx, &processFaultKill); err != nil {
 		return err
@@ -490,7 +701,28 @@ func InitProcessFault(ctx context.Context, processTarget basic.Target) error {
 	if err := basic.InsertFault(ctx, &processFaultStop); err != nil {
 		return err
 	}
-	return InitProcessTargetArgsStop(ctx, processFaultStop)
+	if err := InitProcessTargetArgsStop(ctx, processFaultStop); err != nil {
+		return err
+	}
+
+	if err := basic.InsertFault(ctx, &processFaultFdfull); err != nil {
+		return err
+	}
+	if err := InitProcessTargetArgsFdfull(ctx, processFaultFdfull); err != nil {
+		return err
+	}
+
+	if err := basic.InsertFault(ctx, &processFaultPriority); err != nil {
+		return err
+	}
+	if err := InitProcessTargetArgsPriority(ctx, processFaultPriority); err != nil {
+		return err
+	}
+
+	if err := basic.InsertFault(ctx, &processFaultFuncDelay); err != nil {
+		return err
+	}
+	return InitProcessTargetArgsFuncDelay(ctx, processFaultFuncDelay)
 }
 
 func InitProcessTargetArgsKill(ctx context.Context, processFault basic.Fault) error {
@@ -498,26 +730,63 @@ func InitProcessTargetArgsKill(ctx context.Context, processFault basic.Fault) er
 		ProcessArgsKey        = basic.Args{InjectId: processFault.ID, ExecType: ExecInject, Key: "key", KeyCn: "关键词", Description: "Keywords used to filter affected processes; Will use ps -ef | grep [key] to filter", DescriptionCn: "用来筛选受影响进程的关键词;会使用ps -ef | grep [key]来筛选", ValueType: "string"}
 		ProcessArgsPid        = basic.Args{InjectId: processFault.ID, ExecType: ExecInject, Key: "pid", KeyCn: "进程pid", Description: "The pid of the living process", DescriptionCn: "存活进程的pid", ValueType: "int"}
 		ProcessArgsSignal     = basic.Args{InjectId: processFault.ID, ExecType: ExecInject, Key: "signal", KeyCn: "信号", DefaultValue: "9", Description: "The signal sent to the process; consistent with the signal value supported by the kill command", DescriptionCn: "对进程发送的信号;和kill命令支持的信号数值一致", ValueType: "int"}
+		ProcessArgsRegex      = basic.Args{InjectId: processFault.ID, ExecType: ExecInject, Key: "regex", KeyCn: "正则", Description: "Regular expression matching the whole command line of affected processes, ignored when pid or key is provided", DescriptionCn: "匹配受影响进程完整命令行的正则表达式,提供pid或key时忽略", ValueType: "string"}
+		ProcessArgsDryRun     = basic.Args{InjectId: processFault.ID, ExecType: ExecInject, Key: "dry-run", KeyCn: "演练模式", DefaultValue: "false", Description: "Only list the target processes without sending any signal", DescriptionCn: "只列出目标进程,不发送信号", ValueType: "bool", ValueRule: "true,false"}
 		ProcessArgsRecoverCmd = basic.Args{InjectId: processFault.ID, ExecType: ExecInject, Key: "recover-cmd", KeyCn: "恢复命令", Description: "Resume command, it will be executed last when resuming operation", DescriptionCn: "恢复命令，恢复操作时会最后执行", ValueType: "string"}
 	)
-	return basic.InsertArgsMulti(ctx, []*basic.Args{&ProcessArgsKey, &ProcessArgsPid, &ProcessArgsSignal, &ProcessArgsRecoverCmd})
+	return basic.InsertArgsMulti(ctx, []*basic.Args{&ProcessArgsKey, &ProcessArgsPid, &ProcessArgsRegex, &ProcessArgsSignal, &ProcessArgsDryRun, &ProcessArgsRecoverCmd})
 }
 
 func InitProcessTargetArgsStop(ctx context.Context, processFault basic.Fault) error {
 	var (
-		ProcessArgsKey = basic.Args{InjectId: processFault.ID, ExecType: ExecInject, Key: "key", KeyCn: "关键词", Description: "Keywords used to filter affected processes, will use ps -ef | grep [key] to filter", DescriptionCn: "用来筛选受影响进程的关键词;会使用ps -ef | grep [key]来筛选", ValueType: "string"}
-		ProcessArgsPid = basic.Args{InjectId: processFault.ID, ExecType: ExecInject, Key: "pid", KeyCn: "进程", Description: "The pid of the living process", DescriptionCn: "存活进程的pid", ValueType: "int"}
+		ProcessArgsKey   = basic.Args{InjectId: processFault.ID, ExecType: ExecInject, Key: "key", KeyCn: "关键词", Description: "Keywords used to filter affected processes, will use ps -ef | grep [key] to filter", DescriptionCn: "用来筛选受影响进程的关键词;会使用ps -ef | grep [key]来筛选", ValueType: "string"}
+		ProcessArgsPid   = basic.Args{InjectId: processFault.ID, ExecType: ExecInject, Key: "pid", KeyCn: "进程", Description: "The pid of the living process", DescriptionCn: "存活进程的pid", ValueType: "int"}
+		ProcessArgsRegex = basic.Args{InjectId: processFault.ID, ExecType: ExecInject, Key: "regex", KeyCn: "正则", Description: "Regular expression matching the whole command line of affected processes, ignored when pid or key is provided", DescriptionCn: "匹配受影响进程完整命令行的正则表达式,提供pid或key时忽略", ValueType: "string"}
 	)
-	return basic.InsertArgsMulti(ctx, []*basic.Args{&ProcessArgsKey, &ProcessArgsPid})
+	return basic.InsertArgsMulti(ctx, []*basic.Args{&ProcessArgsKey, &ProcessArgsPid, &ProcessArgsRegex})
+}
+
+func InitProcessTargetArgsFdfull(ctx context.Context, processFault basic.Fault) error {
+	var (
+		ProcessArgsPidList = basic.Args{InjectId: processFault.ID, ExecType: ExecInject, Key: "pid-list", KeyCn: "pid列表", Description: "The pid list of the living processes in host, separated by commas, not supported in container", DescriptionCn: "宿主机上存活进程的pid列表,逗号分隔,不支持容器", ValueType: "string"}
+		ProcessArgsKey     = basic.Args{InjectId: processFault.ID, ExecType: ExecInject, Key: "key", KeyCn: "关键词", Description: "Keywords used to filter affected processes, will use ps -ef | grep [key] to filter", DescriptionCn: "用来筛选受影响进程的关键词;会使用ps -ef | grep [key]来筛选", ValueType: "string"}
+		ProcessArgsCount   = basic.Args{InjectId: processFault.ID, ExecType: ExecInject, Key: "count", KeyCn: "剩余fd数", DefaultValue: "0", Description: "Count of fds the target processes can still open", DescriptionCn: "目标进程还能打开的fd数", ValueType: "int", ValueRule: ">=0"}
+	)
+	return basic.InsertArgsMulti(ctx, []*basic.Args{&ProcessArgsPidList, &ProcessArgsKey, &ProcessArgsCount})
+}
+
+func InitProcessTargetArgsPriority(ctx context.Context, processFault basic.Fault) error {
+	var (
+		ProcessArgsPidList = basic.Args{InjectId: processFault.ID, ExecType: ExecInject, Key: "pid-list", KeyCn: "pid列表", Description: "The pid list of the living processes in host, separated by commas, not supported in container", DescriptionCn: "宿主机上存活进程的pid列表,逗号分隔,不支持容器", ValueType: "string"}
+		ProcessArgsKey     = basic.Args{InjectId: processFault.ID, ExecType: ExecInject, Key: "key", KeyCn: "关键词", Description: "Keywords used to filter affected processes, will use ps -ef | grep [key] to filter", DescriptionCn: "用来筛选受影响进程的关键词;会使用ps -ef | grep [key]来筛选", ValueType: "string"}
+		ProcessArgsNice    = basic.Args{InjectId: processFault.ID, ExecType: ExecInject, Key: "nice", KeyCn: "nice值", DefaultValue: "19", Description: "Nice value, the larger the lower cpu priority", DescriptionCn: "nice值,越大cpu优先级越低", ValueType: "int", ValueRule: "-20-19"}
+		ProcessArgsIoClass = basic.Args{InjectId: processFault.ID, ExecType: ExecInject, Key: "io-class", KeyCn: "io调度类", Description: "Io scheduling class, empty means not changed", DescriptionCn: "io调度类,为空表示不修改", ValueType: "string", ValueRule: "none,realtime,best-effort,idle"}
+		ProcessArgsIoLevel = basic.Args{InjectId: processFault.ID, ExecType: ExecInject, Key: "io-level", KeyCn: "io优先级", DefaultValue: "0", Description: "Io priority of class realtime and best-effort, the larger the lower priority", DescriptionCn: "realtime和best-effort类的io优先级,越大优先级越低", ValueType: "int", ValueRule: "0-7"}
+	)
+	return basic.InsertArgsMulti(ctx, []*basic.Args{&ProcessArgsPidList, &ProcessArgsKey, &ProcessArgsNice, &ProcessArgsIoClass, &ProcessArgsIoLevel})
+}
+
+func InitProcessTargetArgsFuncDelay(ctx context.Context, processFault basic.Fault) error {
+	var (
+		ProcessArgsKey      = basic.Args{InjectId: processFault.ID, ExecType: ExecInject, Key: "key", KeyCn: "关键词", Description: "Keywords used to filter affected processes, will use ps -ef | grep [key] to filter. In a container, the main process is the target if neither pid nor key is provided", DescriptionCn: "用来筛选受影响进程的关键词;会使用ps -ef | grep [key]来筛选。容器中pid和key都不提供时以主进程为目标", ValueType: "string"}
+		ProcessArgsPid      = basic.Args{InjectId: processFault.ID, ExecType: ExecInject, Key: "pid", KeyCn: "进程", Description: "The pid of the living process in host, not supported in container", DescriptionCn: "宿主机上存活进程的pid,不支持容器", ValueType: "int"}
+		ProcessArgsBinary   = basic.Args{InjectId: processFault.ID, ExecType: ExecInject, Key: "binary", KeyCn: "二进制文件", DefaultValue: "libc", Description: "Path of the binary or library containing the function in the target's filesystem, libc means the libc loaded by the target", DescriptionCn: "目标文件系统中包含该函数的二进制或库的路径,libc表示目标加载的libc", ValueType: "string"}
+		ProcessArgsFunction = basic.Args{InjectId: processFault.ID, ExecType: ExecInject, Key: "function", KeyCn: "函数", Description: "Symbol of the function to delay, such as connect", DescriptionCn: "要延迟的函数符号,如connect", ValueType: "string", Required: true}
+		ProcessArgsDelay    = basic.Args{InjectId: processFault.ID, ExecType: ExecInject, Key: "delay", KeyCn: "延迟", Description: "Delay of each call, such as 100ms, 1s", DescriptionCn: "每次调用的延迟,如100ms、1s", ValueType: "string", Required: true}
+		ProcessArgsPercent  = basic.Args{InjectId: processFault.ID, ExecType: ExecInject, Key: "percent", KeyCn: "比例", DefaultValue: "100", Description: "Percent of the calls to delay", DescriptionCn: "被延迟的调用比例", ValueType: "int", ValueRule: "1-100"}
+	)
+	return basic.InsertArgsMulti(ctx, []*basic.Args{&ProcessArgsKey, &ProcessArgsPid, &ProcessArgsBinary, &ProcessArgsFunction, &ProcessArgsDelay, &ProcessArgsPercent})
 }
 
 func InitFileFault(ctx context.Context, fileTarget basic.Target) error {
 	var (
-		fileFaultChmod  = basic.Fault{TargetId: fileTarget.ID, Name: "chmod", NameCn: "篡改权限", Description: "File access permissions have been modified", DescriptionCn: "文件访问权限被修改"}
-		fileFaultDelete = basic.Fault{TargetId: fileTarget.ID, Name: "del", NameCn: "删除文件", Description: "Delete target file", DescriptionCn: "删除目标文件"}
-		fileFaultAppend = basic.Fault{TargetId: fileTarget.ID, Name: "append", NameCn: "追加文件", Description: "Append content to the target file, often used for exception log injection", DescriptionCn: "对目标文件追加内容，常用于异常日志注入"}
-		fileFaultAdd    = basic.Fault{TargetId: fileTarget.ID, Name: "add", NameCn: "增加文件", Description: "Add file", DescriptionCn: "增加文件"}
-		fileFaultMv     = basic.Fault{TargetId: fileTarget.ID, Name: "mv", NameCn: "移动文件", Description: "Move file", DescriptionCn: "移动文件"}
+		fileFaultChmod    = basic.Fault{TargetId: fileTarget.ID, Name: "chmod", NameCn: "篡改权限", Description: "File access permissions have been modified", DescriptionCn: "文件访问权限被修改"}
+		fileFaultDelete   = basic.Fault{TargetId: fileTarget.ID, Name: "del", NameCn: "删除文件", Description: "Delete target file", DescriptionCn: "删除目标文件"}
+		fileFaultAppend   = basic.Fault{TargetId: fileTarget.ID, Name: "append", NameCn: "追加文件", Description: "Append content to the target file, often used for exception log injection", DescriptionCn: "对目标文件追加内容，常用于异常日志注入"}
+		fileFaultAdd      = basic.Fault{TargetId: fileTarget.ID, Name: "add", NameCn: "增加文件", Description: "Add file", DescriptionCn: "增加文件"}
+		fileFaultMv       = basic.Fault{TargetId: fileTarget.ID, Name: "mv", NameCn: "移动文件", Description: "Move file", DescriptionCn: "移动文件"}
+		fileFaultCorrupt  = basic.Fault{TargetId: fileTarget.ID, Name: "corrupt", NameCn: "损坏文件", Description: "Overwrite bytes of the target file with random data, the file is backed up and restored when recovering", DescriptionCn: "用随机数据覆盖目标文件的部分字节,注入前自动备份,恢复时还原"}
+		fileFaultTruncate = basic.Fault{TargetId: fileTarget.ID, Name: "truncate", NameCn: "截断文件", Description: "Truncate the target file to the specified size, the file is backed up and restored when recovering", DescriptionCn: "将目标文件截断到指定大小,注入前自动备份,恢复时还原"}
 	)
 	if err := basic.InsertFault(ctx, &fileFaultChmod); err != nil {
 		return err
@@ -552,7 +821,21 @@ func InitFileFault(ctx context.Context, fileTarget basic.Target) error {
 		return err
 	}
 
-	return InitFileTargetArgsMove(ctx, fileFaultMv)
+	if err := InitFileTargetArgsMove(ctx, fileFaultMv); err != nil {
+		return err
+	}
+
+	if err := basic.InsertFault(ctx, &fileFaultCorrupt); err != nil {
+		return err
+	}
+	if err := InitFileTargetArgsCorrupt(ctx, fileFaultCorrupt); err != nil {
+		return err
+	}
+
+	if err := basic.InsertFault(ctx, &fileFaultTruncate); err != nil {
+		return err
+	}
+	return InitFileTargetArgsTruncate(ctx, fileFaultTruncate)
 }
 
 func InitFileTargetArgsChmod(ctx context.Context, fileFault basic.Fault) error {
@@ -575,8 +858,9 @@ func InitFileTargetArgsAppend(ctx context.Context, fileFault basic.Fault) error
 		FileArgsRaw      = basic.Args{InjectId: fileFault.ID, ExecType: ExecInject, Key: "raw", KeyCn: "是否追加纯字符串", DefaultValue: "false", Description: "Whether to append pure string. By default, it will add some additional identifiers to delete the appended content when recovering; if true, it will append pure string, and the appended content will not be deleted when recovering", DescriptionCn: "是否追加纯字符串,默认false会添加一些额外标识,用于恢复时删掉追加的内容;true追加纯字符串，则恢复时不删掉追加的内容", ValueType: "bool", ValueRule: "true,false"}
 		FileArgsCount    = basic.Args{InjectId: fileFault.ID, ExecType: ExecInject, Key: "count", KeyCn: "循环次数", Description: "", DescriptionCn: "", DefaultValue: "1", ValueType: "int", ValueRule: ">0"}
 		FileArgsInterval = basic.Args{InjectId: fileFault.ID, ExecType: ExecInject, Key: "interval", KeyCn: "循环间隔", Description: "Unit: seconds", DescriptionCn: "单位:秒", DefaultValue: "0", ValueType: "int", ValueRule: ">=0"}
+		FileArgsGarbage  = basic.Args{InjectId: fileFault.ID, ExecType: ExecInject, Key: "garbage", KeyCn: "追加随机数据大小", Description: "Append random bytes of this size instead of content, the file is backed up and restored when recovering. Supported units: B, KB, MB, GB, TB (default B)", DescriptionCn: "追加该大小的随机数据代替追加内容,注入前自动备份,恢复时还原。支持单位:B、KB、MB、GB、TB(默认B)", ValueType: "string"}
 	)
-	return basic.InsertArgsMulti(ctx, []*basic.Args{&FileArgsPath, &FileArgsContent, &FileArgsRaw, &FileArgsCount, &FileArgsInterval})
+	return basic.InsertArgsMulti(ctx, []*basic.Args{&FileArgsPath, &FileArgsContent, &FileArgsRaw, &FileArgsCount, &FileArgsInterval, &FileArgsGarbage})
 }
 
 func InitFileTargetArgsAdd(ctx context.Context, fileFault basic.Fault) error {
@@ -597,6 +881,23 @@ func InitFileTargetArgsMove(ctx context.Context, fileFault basic.Fault) error {
 	return basic.InsertArgsMulti(ctx, []*basic.Args{&FileArgsSrc, &FileArgsDst})
 }
 
+func InitFileTargetArgsCorrupt(ctx context.Context, fileFault basic.Fault) error {
+	var (
+		FileArgsPath   = basic.Args{InjectId: fileFault.ID, ExecType: ExecInject, Key: "path", KeyCn: "目标文件", Description: "Target file path", DescriptionCn: "目标文件路径", ValueType: "string", Required: true}
+		FileArgsSize   = basic.Args{InjectId: fileFault.ID, ExecType: ExecInject, Key: "size", KeyCn: "损坏字节数", DefaultValue: "1KB", Description: "Size of the corrupted bytes. Supported units: B, KB, MB, GB, TB (default B)", DescriptionCn: "被损坏的字节数。支持单位:B、KB、MB、GB、TB(默认B)", ValueType: "string"}
+		FileArgsOffset = basic.Args{InjectId: fileFault.ID, ExecType: ExecInject, Key: "offset", KeyCn: "偏移", Description: "Offset of the corrupted bytes, empty means a random offset. Supported units: B, KB, MB, GB, TB (default B)", DescriptionCn: "被损坏字节的偏移,为空表示随机偏移。支持单位:B、KB、MB、GB、TB(默认B)", ValueType: "string"}
+	)
+	return basic.InsertArgsMulti(ctx, []*basic.Args{&FileArgsPath, &FileArgsSize, &FileArgsOffset})
+}
+
+func InitFileTargetArgsTruncate(ctx context.Context, fileFault basic.Fault) error {
+	var (
+		FileArgsPath = basic.Args{InjectId: fileFault.ID, ExecType: ExecInject, Key: "path", KeyCn: "目标文件", Description: "Target file path", DescriptionCn: "目标文件路径", ValueType: "string", Required: true}
+		FileArgsSize = basic.Args{InjectId: fileFault.ID, ExecType: ExecInject, Key: "size", KeyCn: "截断后大小", DefaultValue: "0", Description: "Size of the file after truncating. Supported units: B, KB, MB, GB, TB (default B)", DescriptionCn: "截断后的文件大小。支持单位:B、KB、MB、GB、TB(默认B)", ValueType: "string"}
+	)
+	return basic.InsertArgsMulti(ctx, []*basic.Args{&FileArgsPath, &FileArgsSize})
+}
+
 func InitKernelFault(ctx context.Context, kernelTarget basic.Target) error {
 	var (
 		kernelFaultFdfull = basic.Fault{TargetId: kernelTarget.ID, Name: "fdfull", NameCn: "系统fd耗尽", Description: "System fd exhausted, cannot use new fd (open file, create socket, create process), only affects non-root processes", DescriptionCn: "系统fd耗尽,无法使用新fd(打开文件、新建socket、新建进程),只对非root进程有影响;fill模式下,如果系统最大fd数过大可能会先导致oom"}
@@ -630,6 +931,23 @@ func InitKernelTargetArgsNproc(ctx context.Context, KernelFault basic.Fault) err
 	return basic.InsertArgsMulti(ctx, []*basic.Args{&KernelArgsCount, &KernelArgsUser})
 }
 
+func InitTimeFault(ctx context.Context, timeTarget basic.Target) error {
+	var timeFaultSkew = basic.Fault{TargetId: timeTarget.ID, Name: "skew", NameCn: "时钟偏移", Description: "Shift the clock by an offset or a drift rate with libfaketime, only the processes started after the injection are affected, so the target process needs a restart", DescriptionCn: "通过libfaketime使时钟偏移指定时间或按指定速率漂移,只影响注入后启动的进程,目标进程需要重启"}
+	if err := basic.InsertFault(ctx, &timeFaultSkew); err != nil {
+		return err
+	}
+	return InitTimeTargetArgsSkew(ctx, timeFaultSkew)
+}
+
+func InitTimeTargetArgsSkew(ctx context.Context, timeFault basic.Fault) error {
+	var (
+		TimeArgsOffset = basic.Args{InjectId: timeFault.ID, ExecType: ExecInject, Key: "offset", KeyCn: "偏移量", Description: "Offset of the clock, support unit: s, m, h, eg: -2h means 2 hours ago", DescriptionCn: "时钟偏移量,支持单位:s、m、h,如-2h表示2小时前", ValueType: "string"}
+		TimeArgsRate   = basic.Args{InjectId: timeFault.ID, ExecType: ExecInject, Key: "rate", KeyCn: "漂移速率", DefaultValue: "1", Description: "Drift rate of the clock, eg: 2 means twice as fast as the real clock", DescriptionCn: "时钟漂移速率,如2表示比真实时钟快一倍", ValueType: "string"}
+		TimeArgsLib    = basic.Args{InjectId: timeFault.ID, ExecType: ExecInject, Key: "lib", KeyCn: "libfaketime路径", Description: "Path of libfaketime on the host, it must match the libc of the target container", DescriptionCn: "宿主机上libfaketime的路径,需与目标容器的libc匹配", ValueType: "string"}
+	)
+	return basic.InsertArgsMulti(ctx, []*basic.Args{&TimeArgsOffset, &TimeArgsRate, &TimeArgsLib})
+}
+
 func InitJvmFault(ctx context.Context, jvmTarget basic.Target) error {
 	var (
 		jvmFaultMethodDelay      = basic.Fault{TargetId: jvmTarget.ID, Name: "methoddelay", NameCn: "Java运行时方法调用延迟", Description: "inject method call delay into running Java process", DescriptionCn: "对运行中的Java进程注入方法调用延迟"}
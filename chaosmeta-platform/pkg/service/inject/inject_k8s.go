@@ -60,16 +60,27 @@ func InitK8STarget(ctx context.Context, scope basic.Scope) error {
 // pod
 func InitPodFault(ctx context.Context, podTarget basic.Target) error {
 	var (
-		podFaultDelete         = basic.Fault{TargetId: podTarget.ID, Name: "delete", NameCn: "删除Pod", Description: "Delete the target Pod instance", DescriptionCn: "删除目标Pod实例"}
-		podFaultLabel          = basic.Fault{TargetId: podTarget.ID, Name: "label", NameCn: "增删Pod标签", Description: "Add or delete the label of the target Pod instance", DescriptionCn: "增删目标Pod实例的标签"}
-		podFaultFinalizer      = basic.Fault{TargetId: podTarget.ID, Name: "finalizer", NameCn: "Pod增加finalizer", Description: "Add a finalizer to the target Pod instance", DescriptionCn: "为目标Pod实例增加finalizer"}
-		podFaultContainerKill  = basic.Fault{TargetId: podTarget.ID, Name: "containerkill", NameCn: "杀掉Pod中的容器", Description: "Kill the specified container in the target Pod instance", DescriptionCn: "杀掉目标Pod实例中指定的容器"}
-		podFaultContainerPause = basic.Fault{TargetId: podTarget.ID, Name: "containerpause", NameCn: "暂停Pod中的容器", Description: "Pauses the specified container in the target Pod instance", DescriptionCn: "暂停目标Pod实例中指定的容器"}
-		podFaultContainerImage = basic.Fault{TargetId: podTarget.ID, Name: "containerimage", NameCn: "修改Pod容器镜像", Description: "Modify the image of the specified container in the target Pod instance", DescriptionCn: "修改目标Pod实例中指定容器的镜像"}
+		podFaultDelete            = basic.Fault{TargetId: podTarget.ID, Name: "delete", NameCn: "删除Pod", Description: "Delete the target Pod instance", DescriptionCn: "删除目标Pod实例"}
+		podFaultEvict             = basic.Fault{TargetId: podTarget.ID, Name: "evict", NameCn: "驱逐Pod", Description: "Evict the target Pod instance by Eviction API, respecting PodDisruptionBudgets", DescriptionCn: "通过Eviction API驱逐目标Pod实例,遵守PodDisruptionBudget"}
+		podFaultLabel             = basic.Fault{TargetId: podTarget.ID, Name: "label", NameCn: "增删Pod标签", Description: "Add or delete the label of the target Pod instance", DescriptionCn: "增删目标Pod实例的标签"}
+		podFaultFinalizer         = basic.Fault{TargetId: podTarget.ID, Name: "finalizer", NameCn: "Pod增加finalizer", Description: "Add a finalizer to the target Pod instance", DescriptionCn: "为目标Pod实例增加finalizer"}
+		podFaultContainerKill     = basic.Fault{TargetId: podTarget.ID, Name: "containerkill", NameCn: "杀掉Pod中的容器", Description: "Kill the specified container in the target Pod instance", DescriptionCn: "杀掉目标Pod实例中指定的容器"}
+		podFaultContainerKillLoop = basic.Fault{TargetId: podTarget.ID, Name: "containerkillloop", NameCn: "循环杀掉Pod中的容器", Description: "Kill the specified container in the target Pod instance repeatedly at an interval until the experiment ends, to simulate a crash-looping dependency", DescriptionCn: "在实验期间按间隔反复杀掉目标Pod实例中指定的容器,模拟依赖服务反复崩溃"}
+		podFaultContainerPause    = basic.Fault{TargetId: podTarget.ID, Name: "containerpause", NameCn: "暂停Pod中的容器", Description: "Pauses the specified container in the target Pod instance", DescriptionCn: "暂停目标Pod实例中指定的容器"}
+		podFaultContainerImage    = basic.Fault{TargetId: podTarget.ID, Name: "containerimage", NameCn: "修改Pod容器镜像", Description: "Modify the image of the specified container in the target Pod instance", DescriptionCn: "修改目标Pod实例中指定容器的镜像"}
 	)
 	if err := basic.InsertFault(ctx, &podFaultDelete); err != nil {
 		return err
 	}
+	if err := InitPodTargetArgsDelete(ctx, podFaultDelete); err != nil {
+		return err
+	}
+	if err := basic.InsertFault(ctx, &podFaultEvict); err != nil {
+		return err
+	}
+	if err := InitPodTargetArgsEvict(ctx, podFaultEvict); err != nil {
+		return err
+	}
 
 	if err := basic.InsertFault(ctx, &podFaultLabel); err != nil {
 		return err
@@ -89,6 +100,12 @@ func InitPodFault(ctx context.Context, podTarget basic.Target) error {
 	if err := InitPodTargetArgsContainerKillAndPause(ctx, podFaultContainerKill); err != nil {
 		return err
 	}
+	if err := basic.InsertFault(ctx, &podFaultContainerKillLoop); err != nil {
+		return err
+	}
+	if err := InitPodTargetArgsContainerKillLoop(ctx, podFaultContainerKillLoop); err != nil {
+		return err
+	}
 	if err := basic.InsertFault(ctx, &podFaultContainerPause); err != nil {
 		return err
 	}
@@ -101,6 +118,25 @@ func InitPodFault(ctx context.Context, podTarget basic.Target) error {
 	return InitPodTargetArgsContainerImage(ctx, podFaultContainerImage)
 }
 
+func InitPodTargetArgsDelete(ctx context.Context, podFault basic.Fault) error {
+	argsPDBPolicy := basic.Args{InjectId: podFault.ID, ExecType: ExecInject, Key: "pdbPolicy", KeyCn: "PDB策略", ValueType: "string", DefaultValue: "override", ValueRule: "override,respect", Description: "override: delete the pod directly even if a PodDisruptionBudget protects it; respect: evict the pod and back off when the PodDisruptionBudget blocks", DescriptionCn: "override:忽略PodDisruptionBudget直接删除;respect:通过驱逐删除,被PodDisruptionBudget阻止时退避重试"}
+	argsPDBTimeout := basic.Args{InjectId: podFault.ID, ExecType: ExecInject, Key: "pdbTimeout", KeyCn: "PDB等待超时", ValueType: "string", DefaultValue: "5m", Description: "How long to back off when the eviction is blocked, such as 30s, 5m", DescriptionCn: "驱逐被阻止时的最长等待时间,比如30s、5m"}
+	argsGracePeriod, argsInterval := getPodDeleteLoopArgs(podFault)
+	return basic.InsertArgsMulti(ctx, []*basic.Args{&argsPDBPolicy, &argsPDBTimeout, &argsGracePeriod, &argsInterval})
+}
+
+func InitPodTargetArgsEvict(ctx context.Context, podFault basic.Fault) error {
+	argsPDBTimeout := basic.Args{InjectId: podFault.ID, ExecType: ExecInject, Key: "pdbTimeout", KeyCn: "PDB等待超时", ValueType: "string", DefaultValue: "5m", Description: "How long to back off when the eviction is blocked, such as 30s, 5m", DescriptionCn: "驱逐被阻止时的最长等待时间,比如30s、5m"}
+	argsGracePeriod, argsInterval := getPodDeleteLoopArgs(podFault)
+	return basic.InsertArgsMulti(ctx, []*basic.Args{&argsPDBTimeout, &argsGracePeriod, &argsInterval})
+}
+
+func getPodDeleteLoopArgs(podFault basic.Fault) (basic.Args, basic.Args) {
+	argsGracePeriod := basic.Args{InjectId: podFault.ID, ExecType: ExecInject, Key: "gracePeriod", KeyCn: "优雅退出时间", Unit: "s", UnitCn: "s", ValueType: "int", ValueRule: ">=0", Description: "Grace period of the pod termination in seconds, 0 means force deletion; empty means the default of the pod", DescriptionCn: "Pod优雅退出的秒数,0表示强制删除;为空表示使用Pod默认值"}
+	argsInterval := basic.Args{InjectId: podFault.ID, ExecType: ExecInject, Key: "interval", KeyCn: "重复删除间隔", ValueType: "string", Description: "Delete the pod recreated with the same name again after it lives for the interval until the experiment ends, such as 30s, 1m; empty means delete once", DescriptionCn: "同名重建的Pod存活超过该间隔后再次删除,直到实验结束,比如30s、1m;为空表示只删除一次"}
+	return argsGracePeriod, argsInterval
+}
+
 func InitPodTargetArgsLabel(ctx context.Context, podFault basic.Fault) error {
 	argsAdd := basic.Args{InjectId: podFault.ID, ExecType: ExecInject, Key: "add", KeyCn: "增加的标签", ValueType: "string", Description: "Added labels;comma-separated key-value pair list", DescriptionCn: "增加的标签;逗号分隔的键值对列表"}
 	argsDelete := basic.Args{InjectId: podFault.ID, ExecType: ExecInject, Key: "delete", KeyCn: "删除的标签key", ValueType: "string", Description: "Deleted label key; comma-separated key-value pair list", DescriptionCn: "删除的标签"}
@@ -118,6 +154,12 @@ func InitPodTargetArgsContainerKillAndPause(ctx context.Context, podFault basic.
 	return basic.InsertArgs(ctx, &argsContainerName)
 }
 
+func InitPodTargetArgsContainerKillLoop(ctx context.Context, podFault basic.Fault) error {
+	argsContainerName := basic.Args{InjectId: podFault.ID, ExecType: ExecInject, Key: "containername", KeyCn: "目标容器名称", ValueType: "string", DefaultValue: "", Description: "Target container name; specific container name, or 'firstcontainer' which represents the first container in the pod", DescriptionCn: "目标容器名称;具体的容器名称,或者“firstcontainer”,表示pod中第一个容器"}
+	argsInterval := basic.Args{InjectId: podFault.ID, ExecType: ExecInject, Key: "interval", KeyCn: "杀掉间隔", ValueType: "string", DefaultValue: "30s", Description: "Kill the container again after it has been running for the interval, such as 30s, 1m", DescriptionCn: "容器重启后运行超过该间隔再次杀掉,比如30s、1m"}
+	return basic.InsertArgsMulti(ctx, []*basic.Args{&argsContainerName, &argsInterval})
+}
+
 func InitPodTargetArgsContainerImage(ctx context.Context, podFault basic.Fault) error {
 	argsContainerName := basic.Args{InjectId: podFault.ID, ExecType: ExecInject, Key: "containername", KeyCn: "目标容器名称", ValueType: "string", Description: "Target container name; specific container name, or 'firstcontainer' which represents the first container in the pod", DescriptionCn: "目标容器名称;具体的容器名称,或者“firstcontainer”,表示pod中第一个容器"}
 	argsImage := basic.Args{InjectId: podFault.ID, ExecType: ExecInject, Key: "image", KeyCn: "目标镜像名称", UnitCn: "", ValueType: "string", DefaultValue: "", Description: "Target image name", DescriptionCn: "目标镜像名称"}
@@ -181,8 +223,10 @@ func InitDeploymentReplicasArgs(ctx context.Context, deploymentFault basic.Fault
 // node
 func InitNodeFault(ctx context.Context, nodeTarget basic.Target) error {
 	var (
-		nodeFaultLabel = basic.Fault{TargetId: nodeTarget.ID, Name: "label", NameCn: "修改node标签", Description: "The label of the node instance is dynamically modified", DescriptionCn: "node实例的label被动态修改"}
-		nodeFaultTaint = basic.Fault{TargetId: nodeTarget.ID, Name: "taint", NameCn: "为node增加taint", Description: "Add specified stains to node instances to affect pod scheduling logic", DescriptionCn: "给node实例增加指定的污点,影响pod调度逻辑"}
+		nodeFaultLabel  = basic.Fault{TargetId: nodeTarget.ID, Name: "label", NameCn: "修改node标签", Description: "The label of the node instance is dynamically modified", DescriptionCn: "node实例的label被动态修改"}
+		nodeFaultTaint  = basic.Fault{TargetId: nodeTarget.ID, Name: "taint", NameCn: "为node增加taint", Description: "Add specified stains to node instances to affect pod scheduling logic", DescriptionCn: "给node实例增加指定的污点,影响pod调度逻辑"}
+		nodeFaultCordon = basic.Fault{TargetId: nodeTarget.ID, Name: "cordon", NameCn: "node禁止调度", Description: "Mark the node unschedulable, and uncordon it on recover", DescriptionCn: "将node标记为不可调度,恢复时解除"}
+		nodeFaultDrain  = basic.Fault{TargetId: nodeTarget.ID, Name: "drain", NameCn: "排空node", Description: "Cordon the node and delete or evict its pods except the ones of DaemonSets and the static pods, and uncordon it on recover", DescriptionCn: "将node标记为不可调度并删除或驱逐其上除DaemonSet和静态Pod外的Pod,恢复时解除不可调度"}
 	)
 	if err := basic.InsertFault(ctx, &nodeFaultLabel); err != nil {
 		return err
@@ -193,7 +237,16 @@ func InitNodeFault(ctx context.Context, nodeTarget basic.Target) error {
 	if err := basic.InsertFault(ctx, &nodeFaultTaint); err != nil {
 		return err
 	}
-	return InitNodeTaintArgs(ctx, nodeFaultTaint)
+	if err := InitNodeTaintArgs(ctx, nodeFaultTaint); err != nil {
+		return err
+	}
+	if err := basic.InsertFault(ctx, &nodeFaultCordon); err != nil {
+		return err
+	}
+	if err := basic.InsertFault(ctx, &nodeFaultDrain); err != nil {
+		return err
+	}
+	return InitNodeDrainArgs(ctx, nodeFaultDrain)
 }
 
 func InitNodeLabelArgs(ctx context.Context, nodeFault basic.Fault) error {
@@ -208,6 +261,14 @@ func InitNodeTaintArgs(ctx context.Context, nodeFault basic.Fault) error {
 	return basic.InsertArgsMulti(ctx, []*basic.Args{&argsAdd, &argsDelete})
 }
 
+func InitNodeDrainArgs(ctx context.Context, nodeFault basic.Fault) error {
+	argsPDBPolicy := basic.Args{InjectId: nodeFault.ID, ExecType: ExecInject, Key: "pdbPolicy", KeyCn: "PDB策略", ValueType: "string", DefaultValue: "respect", ValueRule: "override,respect", Description: "override: delete the pods directly even if a PodDisruptionBudget protects them; respect: evict the pods and back off when the PodDisruptionBudget blocks", DescriptionCn: "override:忽略PodDisruptionBudget直接删除;respect:通过驱逐删除,被PodDisruptionBudget阻止时退避重试"}
+	argsPDBTimeout := basic.Args{InjectId: nodeFault.ID, ExecType: ExecInject, Key: "pdbTimeout", KeyCn: "PDB等待超时", ValueType: "string", DefaultValue: "5m", Description: "How long to back off when the eviction is blocked, such as 30s, 5m", DescriptionCn: "驱逐被阻止时的最长等待时间,比如30s、5m"}
+	argsGracePeriod := basic.Args{InjectId: nodeFault.ID, ExecType: ExecInject, Key: "gracePeriod", KeyCn: "优雅退出时间", Unit: "s", UnitCn: "s", ValueType: "int", ValueRule: ">=0", Description: "Grace period of the pod termination in seconds, 0 means force deletion; empty means the default of the pod", DescriptionCn: "Pod优雅退出的秒数,0表示强制删除;为空表示使用Pod默认值"}
+	argsForce := basic.Args{InjectId: nodeFault.ID, ExecType: ExecInject, Key: "force", KeyCn: "强制排空", DefaultValue: "false", Description: "Also drain the pods not managed by a controller and the pods using emptyDir, whose data is lost", DescriptionCn: "同时排空不受控制器管理的Pod和使用emptyDir的Pod,其数据将丢失", ValueType: "bool", ValueRule: "true,false"}
+	return basic.InsertArgsMulti(ctx, []*basic.Args{&argsPDBPolicy, &argsPDBTimeout, &argsGracePeriod, &argsForce})
+}
+
 //func GetSelectorArgs(selectorTypeName string, fault basic.Fault) []*basic.Args {
 //	argsNamespace := basic.Args{InjectId: fault.ID, ExecType: ExecInject, Key: "namespace", KeyCn: "命名空间", ValueType: "string", DefaultValue: "", Description: "removed taint; a comma-separated list of taints in the format: k1=v1:NoSchedule,k2=v2:NoSchedule", DescriptionCn: "删除的taint;逗号分隔的taint列表，格式为：k1=v1:NoSchedule,k2=v2:NoSchedule"}
 //
@@ -28,9 +28,13 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"sort"
+	"strconv"
 	"sync"
 )
 
+const deploymentRevisionAnnotation = "deployment.kubernetes.io/revision"
+
 // DeploymentService defines the interface contains deployment manages methods.
 type DeploymentService interface {
 	List(namespace string, opts metav1.ListOptions, dsQuery *page.DataSelectQuery) (*DeploymentResponse, error)
@@ -43,6 +47,7 @@ type DeploymentService interface {
 	GetRawPods(namespace, name string) ([]corev1.Pod, error)
 	GetPods(namespace, name string, dsQuery *page.DataSelectQuery) (*PodResponse, error)
 	GetEvents(namespace, name string, dsQuery *page.DataSelectQuery) (*EventResponse, error)
+	Rollback(namespace, name string) (fromRevision, toRevision int64, err error)
 }
 
 type deploymentService struct {
@@ -298,3 +303,54 @@ func (dp *deploymentService) GetEvents(namespace, name string, dsQuery *page.Dat
 	}
 	return eventResponse, nil
 }
+
+// Rollback rolls the deployment back to the pod template of its previous revision, like "kubectl rollout undo"
+func (dp *deploymentService) Rollback(namespace, name string) (int64, int64, error) {
+	ctx := context.TODO()
+	dep, err := dp.param.KubernetesClient.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(dep.Spec.Selector)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	rsList, err := dp.param.KubernetesClient.AppsV1().ReplicaSets(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	revisions := map[int64]*appsv1.ReplicaSet{}
+	var revs []int64
+	for i := range rsList.Items {
+		rs := &rsList.Items[i]
+		if !metav1.IsControlledBy(rs, dep) {
+			continue
+		}
+
+		rev, err := strconv.ParseInt(rs.Annotations[deploymentRevisionAnnotation], 10, 64)
+		if err != nil {
+			continue
+		}
+		revisions[rev] = rs
+		revs = append(revs, rev)
+	}
+
+	sort.Slice(revs, func(i, j int) bool { return revs[i] > revs[j] })
+	if len(revs) < 2 {
+		return 0, 0, fmt.Errorf("deployment %s/%s has no previous revision", namespace, name)
+	}
+	currentRev, previousRev := revs[0], revs[1]
+	previous := revisions[previousRev]
+
+	template := previous.Spec.Template.DeepCopy()
+	delete(template.Labels, appsv1.DefaultDeploymentUniqueLabelKey)
+	dep.Spec.Template = *template
+	if _, err := dp.param.KubernetesClient.AppsV1().Deployments(namespace).Update(ctx, dep, metav1.UpdateOptions{}); err != nil {
+		return currentRev, previousRev, err
+	}
+
+	return currentRev, previousRev, nil
+}
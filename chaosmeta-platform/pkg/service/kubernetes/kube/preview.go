@@ -0,0 +1,436 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package kube
+
+import (
+	"chaosmeta-platform/pkg/service/kubernetes"
+	"context"
+	"fmt"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"math"
+	"sort"
+)
+
+const (
+	RangeTypeAll     = "all"
+	RangeTypePercent = "percent"
+	RangeTypeCount   = "count"
+
+	RiskLevelLow    = "low"
+	RiskLevelMedium = "medium"
+	RiskLevelHigh   = "high"
+
+	WorkloadKindDeployment  = "Deployment"
+	WorkloadKindStatefulSet = "StatefulSet"
+	WorkloadKindDaemonSet   = "DaemonSet"
+	WorkloadKindReplicaSet  = "ReplicaSet"
+	WorkloadKindPod         = "Pod"
+)
+
+type TargetSelector struct {
+	Namespace string            `json:"namespace"`
+	Name      []string          `json:"name,omitempty"`
+	Label     map[string]string `json:"label,omitempty"`
+}
+
+type TargetRangeMode struct {
+	Type  string `json:"type"`
+	Value int    `json:"value,omitempty"`
+}
+
+type TargetPreviewRequest struct {
+	Selector  []TargetSelector `json:"selector"`
+	RangeMode *TargetRangeMode `json:"rangeMode,omitempty"`
+}
+
+type TargetPod struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	NodeName  string `json:"nodeName"`
+	PodIP     string `json:"podIP"`
+	Phase     string `json:"phase"`
+	OwnerKind string `json:"ownerKind"`
+	OwnerName string `json:"ownerName"`
+}
+
+type PDBInfo struct {
+	Name               string `json:"name"`
+	MinAvailable       string `json:"minAvailable,omitempty"`
+	MaxUnavailable     string `json:"maxUnavailable,omitempty"`
+	DisruptionsAllowed int32  `json:"disruptionsAllowed"`
+}
+
+type HPAInfo struct {
+	Name            string `json:"name"`
+	MinReplicas     int32  `json:"minReplicas"`
+	MaxReplicas     int32  `json:"maxReplicas"`
+	CurrentReplicas int32  `json:"currentReplicas"`
+}
+
+// WorkloadRisk is the cost-of-failure estimation of a workload which owns some of the target pods
+type WorkloadRisk struct {
+	Namespace     string    `json:"namespace"`
+	Kind          string    `json:"kind"`
+	Name          string    `json:"name"`
+	Replicas      int32     `json:"replicas"`
+	ReadyReplicas int32     `json:"readyReplicas"`
+	Matched       int       `json:"matched"`
+	MaxSelected   int       `json:"maxSelected"`
+	Singleton     bool      `json:"singleton"`
+	PDBs          []PDBInfo `json:"pdbs,omitempty"`
+	HPA           *HPAInfo  `json:"hpa,omitempty"`
+	RiskLevel     string    `json:"riskLevel"`
+	Hints         []string  `json:"hints,omitempty"`
+}
+
+type TargetPreviewResponse struct {
+	Total     int            `json:"total"`
+	Selected  int            `json:"selected"`
+	RiskLevel string         `json:"riskLevel"`
+	Pods      []TargetPod    `json:"pods"`
+	Workloads []WorkloadRisk `json:"workloads"`
+	Hints     []string       `json:"hints,omitempty"`
+}
+
+type TargetPreviewService interface {
+	Preview(ctx context.Context, req *TargetPreviewRequest) (*TargetPreviewResponse, error)
+}
+
+type targetPreviewService struct {
+	kubernetesParam *kubernetes.KubernetesParam
+}
+
+func NewTargetPreviewService(kubernetesParam *kubernetes.KubernetesParam) TargetPreviewService {
+	return &targetPreviewService{kubernetesParam: kubernetesParam}
+}
+
+type workloadKey struct {
+	namespace, kind, name string
+}
+
+func (t *targetPreviewService) Preview(ctx context.Context, req *TargetPreviewRequest) (*TargetPreviewResponse, error) {
+	if req == nil || len(req.Selector) == 0 {
+		return nil, fmt.Errorf("selector is empty")
+	}
+
+	pods, err := t.resolvePods(ctx, req.Selector)
+	if err != nil {
+		return nil, err
+	}
+
+	selected, err := GetSelectedCount(len(pods), req.RangeMode)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &TargetPreviewResponse{
+		Total:     len(pods),
+		Selected:  selected,
+		RiskLevel: RiskLevelLow,
+		Pods:      make([]TargetPod, 0, len(pods)),
+	}
+
+	matched := make(map[workloadKey]int)
+	var keys []workloadKey
+	for _, pod := range pods {
+		kind, name := t.getPodOwner(ctx, pod)
+		resp.Pods = append(resp.Pods, TargetPod{
+			Namespace: pod.Namespace,
+			Name:      pod.Name,
+			NodeName:  pod.Spec.NodeName,
+			PodIP:     pod.Status.PodIP,
+			Phase:     string(pod.Status.Phase),
+			OwnerKind: kind,
+			OwnerName: name,
+		})
+
+		key := workloadKey{namespace: pod.Namespace, kind: kind, name: name}
+		if _, ok := matched[key]; !ok {
+			keys = append(keys, key)
+		}
+		matched[key]++
+	}
+
+	for _, key := range keys {
+		risk, err := t.getWorkloadRisk(ctx, key, matched[key], selected)
+		if err != nil {
+			return nil, err
+		}
+
+		resp.Workloads = append(resp.Workloads, *risk)
+		resp.RiskLevel = maxRiskLevel(resp.RiskLevel, risk.RiskLevel)
+		for _, hint := range risk.Hints {
+			resp.Hints = append(resp.Hints, fmt.Sprintf("%s %s/%s: %s", risk.Kind, risk.Namespace, risk.Name, hint))
+		}
+	}
+
+	return resp, nil
+}
+
+// resolvePods returns the pods matched by selectors, the internal part of unit is "AND", and the external part is "OR"
+func (t *targetPreviewService) resolvePods(ctx context.Context, selectors []TargetSelector) ([]corev1.Pod, error) {
+	var (
+		exist = make(map[string]bool)
+		pods  []corev1.Pod
+	)
+	for _, unit := range selectors {
+		if unit.Namespace == "" {
+			return nil, fmt.Errorf("namespace of selector is empty")
+		}
+
+		list, err := t.kubernetesParam.KubernetesClient.CoreV1().Pods(unit.Namespace).List(ctx, metav1.ListOptions{
+			LabelSelector: labels.SelectorFromSet(unit.Label).String(),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("list pods in namespace[%s] error: %s", unit.Namespace, err.Error())
+		}
+
+		names := make(map[string]bool, len(unit.Name))
+		for _, name := range unit.Name {
+			names[name] = true
+		}
+
+		for _, pod := range list.Items {
+			if len(names) > 0 && !names[pod.Name] {
+				continue
+			}
+
+			key := pod.Namespace + "/" + pod.Name
+			if !exist[key] {
+				exist[key] = true
+				pods = append(pods, pod)
+			}
+		}
+	}
+
+	sort.Slice(pods, func(i, j int) bool {
+		if pods[i].Namespace != pods[j].Namespace {
+			return pods[i].Namespace < pods[j].Namespace
+		}
+		return pods[i].Name < pods[j].Name
+	})
+
+	return pods, nil
+}
+
+// getPodOwner returns the top workload of pod, ReplicaSet is resolved to its Deployment
+func (t *targetPreviewService) getPodOwner(ctx context.Context, pod corev1.Pod) (string, string) {
+	owner := metav1.GetControllerOf(&pod)
+	if owner == nil {
+		return WorkloadKindPod, pod.Name
+	}
+
+	if owner.Kind != WorkloadKindReplicaSet {
+		return owner.Kind, owner.Name
+	}
+
+	rs, err := t.kubernetesParam.KubernetesClient.AppsV1().ReplicaSets(pod.Namespace).Get(ctx, owner.Name, metav1.GetOptions{})
+	if err != nil {
+		return owner.Kind, owner.Name
+	}
+
+	if rsOwner := metav1.GetControllerOf(rs); rsOwner != nil && rsOwner.Kind == WorkloadKindDeployment {
+		return rsOwner.Kind, rsOwner.Name
+	}
+
+	return owner.Kind, owner.Name
+}
+
+func (t *targetPreviewService) getWorkloadRisk(ctx context.Context, key workloadKey, matched, selected int) (*WorkloadRisk, error) {
+	client := t.kubernetesParam.KubernetesClient
+	risk := &WorkloadRisk{
+		Namespace:   key.namespace,
+		Kind:        key.kind,
+		Name:        key.name,
+		Matched:     matched,
+		MaxSelected: int(math.Min(float64(matched), float64(selected))),
+	}
+
+	var podSelector *metav1.LabelSelector
+	switch key.kind {
+	case WorkloadKindDeployment:
+		d, err := client.AppsV1().Deployments(key.namespace).Get(ctx, key.name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("get deployment[%s/%s] error: %s", key.namespace, key.name, err.Error())
+		}
+		risk.Replicas, risk.ReadyReplicas, podSelector = d.Status.Replicas, d.Status.ReadyReplicas, d.Spec.Selector
+	case WorkloadKindStatefulSet:
+		s, err := client.AppsV1().StatefulSets(key.namespace).Get(ctx, key.name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("get statefulset[%s/%s] error: %s", key.namespace, key.name, err.Error())
+		}
+		risk.Replicas, risk.ReadyReplicas, podSelector = s.Status.Replicas, s.Status.ReadyReplicas, s.Spec.Selector
+	case WorkloadKindDaemonSet:
+		d, err := client.AppsV1().DaemonSets(key.namespace).Get(ctx, key.name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("get daemonset[%s/%s] error: %s", key.namespace, key.name, err.Error())
+		}
+		risk.Replicas, risk.ReadyReplicas, podSelector = d.Status.DesiredNumberScheduled, d.Status.NumberReady, d.Spec.Selector
+	case WorkloadKindReplicaSet:
+		r, err := client.AppsV1().ReplicaSets(key.namespace).Get(ctx, key.name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("get replicaset[%s/%s] error: %s", key.namespace, key.name, err.Error())
+		}
+		risk.Replicas, risk.ReadyReplicas, podSelector = r.Status.Replicas, r.Status.ReadyReplicas, r.Spec.Selector
+	default:
+		risk.Replicas, risk.ReadyReplicas = 1, 1
+	}
+
+	if podSelector != nil {
+		pdbs, err := t.getMatchedPDBs(ctx, key.namespace, podSelector)
+		if err != nil {
+			return nil, err
+		}
+		risk.PDBs = pdbs
+	}
+
+	hpa, err := t.getHPA(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	risk.HPA = hpa
+
+	EstimateWorkloadRisk(risk)
+	return risk, nil
+}
+
+// getMatchedPDBs returns the PodDisruptionBudgets which protect the pods of the workload
+func (t *targetPreviewService) getMatchedPDBs(ctx context.Context, namespace string, podSelector *metav1.LabelSelector) ([]PDBInfo, error) {
+	list, err := t.kubernetesParam.KubernetesClient.PolicyV1().PodDisruptionBudgets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("list pdb in namespace[%s] error: %s", namespace, err.Error())
+	}
+
+	var pdbs []PDBInfo
+	podLabels := labels.Set(podSelector.MatchLabels)
+	for _, pdb := range list.Items {
+		selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+		if err != nil || selector.Empty() || !selector.Matches(podLabels) {
+			continue
+		}
+
+		info := PDBInfo{Name: pdb.Name, DisruptionsAllowed: pdb.Status.DisruptionsAllowed}
+		if pdb.Spec.MinAvailable != nil {
+			info.MinAvailable = pdb.Spec.MinAvailable.String()
+		}
+		if pdb.Spec.MaxUnavailable != nil {
+			info.MaxUnavailable = pdb.Spec.MaxUnavailable.String()
+		}
+		pdbs = append(pdbs, info)
+	}
+
+	return pdbs, nil
+}
+
+func (t *targetPreviewService) getHPA(ctx context.Context, key workloadKey) (*HPAInfo, error) {
+	if key.kind != WorkloadKindDeployment && key.kind != WorkloadKindStatefulSet && key.kind != WorkloadKindReplicaSet {
+		return nil, nil
+	}
+
+	list, err := t.kubernetesParam.KubernetesClient.AutoscalingV1().HorizontalPodAutoscalers(key.namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("list hpa in namespace[%s] error: %s", key.namespace, err.Error())
+	}
+
+	for _, hpa := range list.Items {
+		ref := hpa.Spec.ScaleTargetRef
+		if ref.Kind != key.kind || ref.Name != key.name {
+			continue
+		}
+
+		info := &HPAInfo{Name: hpa.Name, MinReplicas: 1, MaxReplicas: hpa.Spec.MaxReplicas, CurrentReplicas: hpa.Status.CurrentReplicas}
+		if hpa.Spec.MinReplicas != nil {
+			info.MinReplicas = *hpa.Spec.MinReplicas
+		}
+		return info, nil
+	}
+
+	return nil, nil
+}
+
+// GetSelectedCount returns the count of pods which will be injected according to the range mode
+func GetSelectedCount(total int, rangeMode *TargetRangeMode) (int, error) {
+	if rangeMode == nil || rangeMode.Type == "" || rangeMode.Type == RangeTypeAll {
+		return total, nil
+	}
+
+	switch rangeMode.Type {
+	case RangeTypeCount:
+		if rangeMode.Value < 0 {
+			return 0, fmt.Errorf("count must not be negative")
+		}
+		return int(math.Min(float64(total), float64(rangeMode.Value))), nil
+	case RangeTypePercent:
+		if rangeMode.Value < 0 || rangeMode.Value > 100 {
+			return 0, fmt.Errorf("percent must be in [0, 100]")
+		}
+		return int(math.Ceil(float64(total) * float64(rangeMode.Value) / 100)), nil
+	default:
+		return 0, fmt.Errorf("not support range type: %s", rangeMode.Type)
+	}
+}
+
+// EstimateWorkloadRisk flags risky selections of the workload. The worst case is assumed,
+// that is all selected pods may belong to this workload
+func EstimateWorkloadRisk(risk *WorkloadRisk) {
+	risk.RiskLevel = RiskLevelLow
+	risk.Singleton = risk.Replicas <= 1
+	if risk.MaxSelected == 0 {
+		return
+	}
+
+	flag := func(level, hint string) {
+		risk.RiskLevel = maxRiskLevel(risk.RiskLevel, level)
+		risk.Hints = append(risk.Hints, hint)
+	}
+
+	if risk.Singleton {
+		flag(RiskLevelHigh, "target is a singleton, the fault makes it fully unavailable")
+	} else if risk.MaxSelected >= int(risk.Replicas) {
+		if len(risk.PDBs) > 0 {
+			flag(RiskLevelHigh, fmt.Sprintf("this selector hits 100%% of %d replicas of a PDB-protected workload", risk.Replicas))
+		} else {
+			flag(RiskLevelHigh, fmt.Sprintf("this selector hits 100%% of %d replicas", risk.Replicas))
+		}
+	} else if risk.MaxSelected*2 >= int(risk.Replicas) {
+		flag(RiskLevelMedium, fmt.Sprintf("this selector hits %d of %d replicas", risk.MaxSelected, risk.Replicas))
+	}
+
+	for _, pdb := range risk.PDBs {
+		if int32(risk.MaxSelected) > pdb.DisruptionsAllowed {
+			flag(RiskLevelHigh, fmt.Sprintf("%d pods may be disrupted but PDB[%s] only allows %d disruptions", risk.MaxSelected, pdb.Name, pdb.DisruptionsAllowed))
+		}
+	}
+
+	if risk.HPA != nil && risk.Replicas-int32(risk.MaxSelected) < risk.HPA.MinReplicas {
+		flag(RiskLevelMedium, fmt.Sprintf("remaining replicas fall below HPA[%s] minReplicas %d", risk.HPA.Name, risk.HPA.MinReplicas))
+	}
+
+	if risk.ReadyReplicas < risk.Replicas {
+		flag(RiskLevelMedium, fmt.Sprintf("only %d of %d replicas are ready before injection", risk.ReadyReplicas, risk.Replicas))
+	}
+}
+
+func maxRiskLevel(a, b string) string {
+	order := map[string]int{RiskLevelLow: 0, RiskLevelMedium: 1, RiskLevelHigh: 2}
+	if order[b] > order[a] {
+		return b
+	}
+	return a
+}
@@ -0,0 +1,76 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package kube
+
+import (
+	"testing"
+)
+
+func TestGetSelectedCount(t *testing.T) {
+	tests := []struct {
+		name      string
+		total     int
+		rangeMode *TargetRangeMode
+		want      int
+		wantErr   bool
+	}{
+		{name: "nil", total: 5, rangeMode: nil, want: 5},
+		{name: "all", total: 5, rangeMode: &TargetRangeMode{Type: RangeTypeAll}, want: 5},
+		{name: "count", total: 5, rangeMode: &TargetRangeMode{Type: RangeTypeCount, Value: 2}, want: 2},
+		{name: "count over total", total: 5, rangeMode: &TargetRangeMode{Type: RangeTypeCount, Value: 8}, want: 5},
+		{name: "percent", total: 5, rangeMode: &TargetRangeMode{Type: RangeTypePercent, Value: 50}, want: 3},
+		{name: "bad percent", total: 5, rangeMode: &TargetRangeMode{Type: RangeTypePercent, Value: 150}, wantErr: true},
+		{name: "bad type", total: 5, rangeMode: &TargetRangeMode{Type: "half"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetSelectedCount(tt.total, tt.rangeMode)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetSelectedCount() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("GetSelectedCount() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEstimateWorkloadRisk(t *testing.T) {
+	tests := []struct {
+		name      string
+		risk      *WorkloadRisk
+		wantLevel string
+		wantHints int
+	}{
+		{name: "singleton", risk: &WorkloadRisk{Replicas: 1, ReadyReplicas: 1, MaxSelected: 1}, wantLevel: RiskLevelHigh, wantHints: 1},
+		{name: "all replicas with pdb", risk: &WorkloadRisk{Replicas: 3, ReadyReplicas: 3, MaxSelected: 3,
+			PDBs: []PDBInfo{{Name: "pdb", DisruptionsAllowed: 1}}}, wantLevel: RiskLevelHigh, wantHints: 2},
+		{name: "half replicas", risk: &WorkloadRisk{Replicas: 4, ReadyReplicas: 4, MaxSelected: 2}, wantLevel: RiskLevelMedium, wantHints: 1},
+		{name: "below hpa min", risk: &WorkloadRisk{Replicas: 10, ReadyReplicas: 10, MaxSelected: 2,
+			HPA: &HPAInfo{Name: "hpa", MinReplicas: 9}}, wantLevel: RiskLevelMedium, wantHints: 1},
+		{name: "low", risk: &WorkloadRisk{Replicas: 10, ReadyReplicas: 10, MaxSelected: 1}, wantLevel: RiskLevelLow, wantHints: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			EstimateWorkloadRisk(tt.risk)
+			if tt.risk.RiskLevel != tt.wantLevel || len(tt.risk.Hints) != tt.wantHints {
+				t.Errorf("EstimateWorkloadRisk() = %v %v, want %v with %d hints", tt.risk.RiskLevel, tt.risk.Hints, tt.wantLevel, tt.wantHints)
+			}
+		})
+	}
+}
@@ -0,0 +1,330 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package namespace
+
+import (
+	"bytes"
+	"chaosmeta-platform/config"
+	"chaosmeta-platform/pkg/models/experiment_instance"
+	namespaceModel "chaosmeta-platform/pkg/models/namespace"
+	platformErrors "chaosmeta-platform/util/errors"
+	"chaosmeta-platform/util/log"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/beego/beego/v2/client/orm"
+	"github.com/robfig/cron"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultBudgetThresholds = "80,100"
+	budgetMonthLayout       = "2006-01"
+)
+
+type BudgetConsumption struct {
+	NamespaceId          int     `json:"namespaceId"`
+	Month                string  `json:"month"`
+	Runs                 int64   `json:"runs"`
+	MaxRuns              int     `json:"maxRuns"`
+	RunsPercent          float64 `json:"runsPercent"`
+	InjectMinutes        float64 `json:"injectMinutes"`
+	MaxInjectMinutes     int     `json:"maxInjectMinutes"`
+	InjectMinutesPercent float64 `json:"injectMinutesPercent"`
+	Thresholds           []int   `json:"thresholds"`
+	// Level is the highest threshold reached this month, 0 means none
+	Level int `json:"level"`
+}
+
+type BudgetAlert struct {
+	NamespaceId   int                `json:"namespaceId"`
+	NamespaceName string             `json:"namespaceName"`
+	Level         int                `json:"level"`
+	Consumption   *BudgetConsumption `json:"consumption"`
+}
+
+func (s *NamespaceService) GetBudget(ctx context.Context, namespaceId int) (*namespaceModel.Budget, error) {
+	budget := namespaceModel.Budget{NamespaceId: namespaceId}
+	if err := namespaceModel.GetBudgetByNamespaceId(ctx, &budget); err != nil {
+		if err == orm.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &budget, nil
+}
+
+func (s *NamespaceService) SetBudget(ctx context.Context, userName string, namespaceId int, maxRuns, maxInjectMinutes int, thresholds []int) error {
+	if !s.IsAdmin(ctx, namespaceId, userName) {
+		return platformErrors.ErrPermissionDenied
+	}
+	if maxRuns < 0 || maxInjectMinutes < 0 {
+		return errors.New("budget must not be negative")
+	}
+	if maxRuns == 0 && maxInjectMinutes == 0 {
+		return errors.New("at least one of maxRuns and maxInjectMinutes is required")
+	}
+
+	thresholdStr, err := formatThresholds(thresholds)
+	if err != nil {
+		return err
+	}
+
+	namespace := namespaceModel.Namespace{Id: namespaceId}
+	if err := namespaceModel.GetNamespaceById(ctx, &namespace); err != nil {
+		return err
+	}
+
+	// a changed budget is evaluated again from scratch, so that raising it does not swallow the next alert
+	return namespaceModel.InsertOrUpdateBudget(ctx, &namespaceModel.Budget{
+		NamespaceId:      namespaceId,
+		MaxRuns:          maxRuns,
+		MaxInjectMinutes: maxInjectMinutes,
+		Thresholds:       thresholdStr,
+		Creator:          userName,
+	})
+}
+
+func (s *NamespaceService) DeleteBudget(ctx context.Context, userName string, namespaceId int) error {
+	if !s.IsAdmin(ctx, namespaceId, userName) {
+		return platformErrors.ErrPermissionDenied
+	}
+	return namespaceModel.DeleteBudgetByNamespaceId(ctx, namespaceId)
+}
+
+// GetConsumption returns the consumption of the current month, the budget part is empty if the namespace has no budget
+func (s *NamespaceService) GetConsumption(ctx context.Context, namespaceId int) (*BudgetConsumption, error) {
+	budget, err := s.GetBudget(ctx, namespaceId)
+	if err != nil {
+		return nil, err
+	}
+	if budget == nil {
+		budget = &namespaceModel.Budget{NamespaceId: namespaceId}
+	}
+	return getConsumption(budget, time.Now())
+}
+
+// ListConsumption returns the consumption of the current month of all namespaces with a budget
+func (s *NamespaceService) ListConsumption(ctx context.Context) ([]*BudgetConsumption, error) {
+	budgets, err := namespaceModel.ListBudgets(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var consumptions []*BudgetConsumption
+	now := time.Now()
+	for i := range budgets {
+		consumption, err := getConsumption(&budgets[i], now)
+		if err != nil {
+			return nil, err
+		}
+		consumptions = append(consumptions, consumption)
+	}
+	return consumptions, nil
+}
+
+func getConsumption(budget *namespaceModel.Budget, now time.Time) (*BudgetConsumption, error) {
+	start := monthStart(now)
+	runs, err := experiment_instance.CountExperimentInstancesSince(budget.NamespaceId, start)
+	if err != nil {
+		return nil, fmt.Errorf("count experiment instances error: %s", err.Error())
+	}
+
+	durations, err := experiment_instance.ListFaultDurationsSince(budget.NamespaceId, start)
+	if err != nil {
+		return nil, fmt.Errorf("list fault durations error: %s", err.Error())
+	}
+
+	var injectMinutes float64
+	for _, d := range durations {
+		duration, err := time.ParseDuration(d)
+		if err != nil {
+			continue
+		}
+		injectMinutes += duration.Minutes()
+	}
+
+	thresholds, err := parseThresholds(budget.Thresholds)
+	if err != nil {
+		return nil, err
+	}
+
+	consumption := &BudgetConsumption{
+		NamespaceId:      budget.NamespaceId,
+		Month:            now.Format(budgetMonthLayout),
+		Runs:             runs,
+		MaxRuns:          budget.MaxRuns,
+		InjectMinutes:    injectMinutes,
+		MaxInjectMinutes: budget.MaxInjectMinutes,
+		Thresholds:       thresholds,
+	}
+	consumption.RunsPercent = usagePercent(float64(runs), budget.MaxRuns)
+	consumption.InjectMinutesPercent = usagePercent(injectMinutes, budget.MaxInjectMinutes)
+	consumption.Level = reachedLevel(thresholds, consumption.RunsPercent, consumption.InjectMinutesPercent)
+	return consumption, nil
+}
+
+func monthStart(now time.Time) time.Time {
+	return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+}
+
+// usagePercent returns 0 when the limit is not set
+func usagePercent(used float64, limit int) float64 {
+	if limit <= 0 {
+		return 0
+	}
+	return used * 100 / float64(limit)
+}
+
+// reachedLevel returns the highest threshold reached by any of the percents, 0 means none
+func reachedLevel(thresholds []int, percents ...float64) int {
+	level := 0
+	for _, p := range percents {
+		for _, t := range thresholds {
+			if p >= float64(t) && t > level {
+				level = t
+			}
+		}
+	}
+	return level
+}
+
+func parseThresholds(thresholdStr string) ([]int, error) {
+	if thresholdStr == "" {
+		thresholdStr = DefaultBudgetThresholds
+	}
+
+	var thresholds []int
+	for _, t := range strings.Split(thresholdStr, ",") {
+		value, err := strconv.Atoi(strings.TrimSpace(t))
+		if err != nil {
+			return nil, fmt.Errorf("invalid threshold[%s]: %s", t, err.Error())
+		}
+		thresholds = append(thresholds, value)
+	}
+	return thresholds, nil
+}
+
+func formatThresholds(thresholds []int) (string, error) {
+	if len(thresholds) == 0 {
+		return DefaultBudgetThresholds, nil
+	}
+
+	sorted := append([]int{}, thresholds...)
+	sort.Ints(sorted)
+	var strList []string
+	for _, t := range sorted {
+		if t <= 0 {
+			return "", fmt.Errorf("threshold must be greater than 0, got %d", t)
+		}
+		strList = append(strList, strconv.Itoa(t))
+	}
+	return strings.Join(strList, ","), nil
+}
+
+type BudgetRoutine struct {
+	context   context.Context
+	localCron *cron.Cron
+}
+
+// CheckBudgets notifies once per month and threshold when a namespace reaches a threshold of its budget
+func (r *BudgetRoutine) CheckBudgets() {
+	budgets, err := namespaceModel.ListBudgets(r.context)
+	if err != nil {
+		log.Error(err)
+		return
+	}
+
+	now := time.Now()
+	month := now.Format(budgetMonthLayout)
+	for i := range budgets {
+		budget := &budgets[i]
+		consumption, err := getConsumption(budget, now)
+		if err != nil {
+			log.Errorf("get consumption of namespace[%d] error: %s", budget.NamespaceId, err.Error())
+			continue
+		}
+
+		notifiedLevel := budget.NotifiedLevel
+		if budget.NotifiedMonth != month {
+			notifiedLevel = 0
+		}
+		if consumption.Level <= notifiedLevel {
+			continue
+		}
+
+		namespace := namespaceModel.Namespace{Id: budget.NamespaceId}
+		if err := namespaceModel.GetNamespaceById(r.context, &namespace); err != nil {
+			log.Errorf("get namespace[%d] error: %s", budget.NamespaceId, err.Error())
+			continue
+		}
+		if err := notifyBudgetAlert(&BudgetAlert{NamespaceId: namespace.Id, NamespaceName: namespace.Name, Level: consumption.Level, Consumption: consumption}); err != nil {
+			log.Errorf("notify budget alert of namespace[%d] error: %s", budget.NamespaceId, err.Error())
+			continue
+		}
+
+		if err := namespaceModel.UpdateBudgetNotified(r.context, budget.Id, month, consumption.Level); err != nil {
+			log.Error(err)
+		}
+	}
+}
+
+func notifyBudgetAlert(alert *BudgetAlert) error {
+	log.Warnf("namespace[%s] has reached %d%% of its budget in %s, runs: %d/%d, inject minutes: %.1f/%d", alert.NamespaceName, alert.Level,
+		alert.Consumption.Month, alert.Consumption.Runs, alert.Consumption.MaxRuns, alert.Consumption.InjectMinutes, alert.Consumption.MaxInjectMinutes)
+
+	webhookUrl := config.DefaultRunOptIns.Budget.WebhookUrl
+	if webhookUrl == "" {
+		return nil
+	}
+
+	body, err := json.Marshal(alert)
+	if err != nil {
+		return err
+	}
+	client := http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Post(webhookUrl, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("webhook returns status code %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (r *BudgetRoutine) Start() {
+	localCron := cron.New()
+	if err := localCron.AddFunc("@every 10m", r.CheckBudgets); err != nil {
+		log.Error(err)
+		return
+	}
+
+	localCron.Start()
+	r.localCron = localCron
+
+	select {
+	case <-r.context.Done():
+		log.Info("Receive stop signal")
+	}
+}
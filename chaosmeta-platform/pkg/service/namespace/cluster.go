@@ -19,21 +19,21 @@ package namespace
 import (
 	"chaosmeta-platform/pkg/models/cluster"
 	"chaosmeta-platform/pkg/models/namespace"
+	"chaosmeta-platform/util/errors"
 	"chaosmeta-platform/util/sort"
 	"context"
-	"errors"
 )
 
 func (s *NamespaceService) SetAttackableCluster(ctx context.Context, namespaceId int, username string, clusterId int) error {
 	if !s.IsAdmin(ctx, namespaceId, username) {
-		return errors.New("permission denied")
+		return errors.ErrPermissionDenied
 	}
 	return namespace.SetClusterIDsForNamespace(namespaceId, []int{clusterId})
 }
 
 func (s *NamespaceService) ClearAttackableCluster(ctx context.Context, namespaceId int, username string) error {
 	if !s.IsAdmin(ctx, namespaceId, username) {
-		return errors.New("permission denied")
+		return errors.ErrPermissionDenied
 	}
 	return namespace.ClearClusterIDsForNamespace(namespaceId)
 }
@@ -18,13 +18,14 @@ package namespace
 
 import (
 	namespaceModel "chaosmeta-platform/pkg/models/namespace"
+	platformErrors "chaosmeta-platform/util/errors"
 	"context"
 	"errors"
 )
 
 func (s *NamespaceService) CreateLabel(ctx context.Context, namespaceId int, username, name, color string) (int64, error) {
 	if !s.IsAdmin(ctx, namespaceId, username) {
-		return 0, errors.New("permission denied")
+		return 0, platformErrors.ErrPermissionDenied
 	}
 	label := namespaceModel.Label{Name: name, NamespaceId: namespaceId, Color: color, Creator: username}
 	if err := namespaceModel.GetLabelByName(ctx, &label); err == nil {
@@ -35,7 +36,7 @@ func (s *NamespaceService) CreateLabel(ctx context.Context, namespaceId int, use
 
 func (s *NamespaceService) DeleteLabel(ctx context.Context, namespaceId int, username string, labelId int) error {
 	if !s.IsAdmin(ctx, namespaceId, username) {
-		return errors.New("permission denied")
+		return platformErrors.ErrPermissionDenied
 	}
 	label := namespaceModel.Label{Id: labelId}
 	if err := namespaceModel.GetLabelById(ctx, &label); err != nil {
@@ -0,0 +1,319 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package namespace
+
+import (
+	"chaosmeta-platform/config"
+	clusterModel "chaosmeta-platform/pkg/models/cluster"
+	namespaceModel "chaosmeta-platform/pkg/models/namespace"
+	"chaosmeta-platform/pkg/models/user"
+	"chaosmeta-platform/pkg/service/cluster"
+	platformErrors "chaosmeta-platform/util/errors"
+	"chaosmeta-platform/util/log"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/beego/beego/v2/client/orm"
+	"github.com/robfig/cron"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	defaultMirrorInterval = "10m"
+	mirrorDescription     = "Mirrored from kubernetes namespace %s"
+)
+
+var defaultMirrorAdminRoles = []string{"admin"}
+
+type MirrorRoutine struct {
+	context   context.Context
+	localCron *cron.Cron
+}
+
+func (r *MirrorRoutine) SyncAll() {
+	s := NamespaceService{}
+	if err := s.SyncMirrors(r.context); err != nil {
+		log.Errorf("sync namespace mirrors error: %s", err.Error())
+	}
+}
+
+func (r *MirrorRoutine) Start() {
+	mirrorConfig := config.DefaultRunOptIns.NamespaceMirror
+	if !mirrorConfig.Enabled {
+		return
+	}
+	if mirrorConfig.LabelSelector == "" {
+		log.Error("namespace mirror is enabled but label selector is empty, mirroring all kubernetes namespaces is not allowed")
+		return
+	}
+
+	interval := mirrorConfig.Interval
+	if interval == "" {
+		interval = defaultMirrorInterval
+	}
+
+	r.SyncAll()
+
+	localCron := cron.New()
+	if err := localCron.AddFunc(fmt.Sprintf("@every %s", interval), r.SyncAll); err != nil {
+		log.Error(err)
+		return
+	}
+
+	localCron.Start()
+	r.localCron = localCron
+
+	select {
+	case <-r.context.Done():
+		log.Info("Receive stop signal")
+	}
+}
+
+// SyncMirrors creates platform namespaces for the kubernetes namespaces matching the label selector of every cluster,
+// and seeds their members from the rolebindings. Members are only added or promoted, never removed, and a platform
+// namespace is kept when its kubernetes namespace is gone because experiments may still refer to it
+func (s *NamespaceService) SyncMirrors(ctx context.Context) error {
+	mirrorConfig := config.DefaultRunOptIns.NamespaceMirror
+	if mirrorConfig.LabelSelector == "" {
+		return errors.New("label selector of namespace mirror is empty")
+	}
+
+	var errMsg string
+	for _, clusterId := range getMirrorClusterIds() {
+		if err := s.syncClusterMirrors(ctx, clusterId, mirrorConfig.LabelSelector); err != nil {
+			errMsg = fmt.Sprintf("%scluster[%d]: %s; ", errMsg, clusterId, err.Error())
+		}
+	}
+	if errMsg != "" {
+		return errors.New(errMsg)
+	}
+	return nil
+}
+
+// TriggerSyncMirrors lets a global admin sync the mirrors without waiting for the next round
+func (s *NamespaceService) TriggerSyncMirrors(ctx context.Context, userName string) error {
+	u := user.User{Email: userName}
+	if err := user.GetUser(ctx, &u); err != nil || !s.IsGlobalAdmin(ctx, u.ID) {
+		return platformErrors.ErrPermissionDenied
+	}
+	if !config.DefaultRunOptIns.NamespaceMirror.Enabled {
+		return errors.New("namespace mirror is not enabled")
+	}
+	return s.SyncMirrors(ctx)
+}
+
+func (s *NamespaceService) ListMirrors(ctx context.Context) ([]namespaceModel.Mirror, error) {
+	return namespaceModel.ListMirrors(ctx, nil)
+}
+
+// getMirrorClusterIds returns the registered clusters, or the cluster of the run mode if none is registered
+func getMirrorClusterIds() []int {
+	clusters, err := clusterModel.ListCluster()
+	if err != nil {
+		log.Error(err)
+	}
+	if len(clusters) == 0 {
+		return []int{config.DefaultRunOptIns.RunMode.Int()}
+	}
+
+	var ids []int
+	for _, c := range clusters {
+		ids = append(ids, c.ID)
+	}
+	return ids
+}
+
+func (s *NamespaceService) syncClusterMirrors(ctx context.Context, clusterId int, labelSelector string) error {
+	clusterService := cluster.ClusterService{}
+	kubeClient, _, err := clusterService.GetRestConfig(ctx, clusterId)
+	if err != nil {
+		return err
+	}
+
+	kubeNamespaces, err := kubeClient.CoreV1().Namespaces().List(ctx, metav1.ListOptions{LabelSelector: labelSelector})
+	if err != nil {
+		return fmt.Errorf("list kubernetes namespaces error: %s", err.Error())
+	}
+
+	mirrors, err := namespaceModel.ListMirrors(ctx, &clusterId)
+	if err != nil {
+		return err
+	}
+	mirrorMap := make(map[string]*namespaceModel.Mirror)
+	for i := range mirrors {
+		mirrorMap[mirrors[i].KubeNamespace] = &mirrors[i]
+	}
+
+	creator := user.User{Email: "admin"}
+	if err := user.GetUser(ctx, &creator); err != nil {
+		return fmt.Errorf("get creator of mirrored namespaces error: %s", err.Error())
+	}
+
+	for _, kubeNamespace := range kubeNamespaces.Items {
+		name := kubeNamespace.Name
+		namespaceId, err := s.mirrorNamespace(ctx, clusterId, name, mirrorMap[name], creator.ID)
+		if err != nil {
+			log.Errorf("mirror kubernetes namespace[%s] of cluster[%d] error: %s", name, clusterId, err.Error())
+			continue
+		}
+		delete(mirrorMap, name)
+
+		if err := seedMirrorMembers(ctx, kubeClient, name, namespaceId); err != nil {
+			log.Errorf("seed members of namespace[%d] from rolebindings of kubernetes namespace[%s] error: %s", namespaceId, name, err.Error())
+		}
+	}
+
+	// the remaining mirrors no longer match the selector
+	for _, mirror := range mirrorMap {
+		log.Infof("kubernetes namespace[%s] of cluster[%d] is no longer mirrored, keep platform namespace[%d]", mirror.KubeNamespace, clusterId, mirror.NamespaceId)
+		if err := namespaceModel.DeleteMirror(ctx, mirror.Id); err != nil {
+			log.Error(err)
+		}
+	}
+	return nil
+}
+
+// mirrorNamespace returns the platform namespace of the kubernetes namespace, creating it if not exists.
+// A manually created platform namespace with the same name is never taken over
+func (s *NamespaceService) mirrorNamespace(ctx context.Context, clusterId int, kubeNamespace string, mirror *namespaceModel.Mirror, creatorId int) (int, error) {
+	if mirror != nil {
+		namespace := namespaceModel.Namespace{Id: mirror.NamespaceId}
+		if err := namespaceModel.GetNamespaceById(ctx, &namespace); err == nil {
+			return namespace.Id, addAttackableCluster(namespace.Id, clusterId)
+		} else if err != orm.ErrNoRows {
+			return 0, err
+		}
+	}
+
+	namespace := namespaceModel.Namespace{Name: kubeNamespace}
+	err := namespaceModel.GetNamespaceByName(ctx, &namespace)
+	if err == nil {
+		// the same kubernetes namespace may be mirrored from several clusters
+		if !namespaceModel.IsNamespaceMirrored(ctx, namespace.Id) {
+			return 0, fmt.Errorf("platform namespace[%s] already exists and is not mirrored", kubeNamespace)
+		}
+	} else if err == orm.ErrNoRows {
+		namespace = namespaceModel.Namespace{
+			Name:        kubeNamespace,
+			Description: fmt.Sprintf(mirrorDescription, kubeNamespace),
+			Creator:     creatorId,
+		}
+		if _, err := namespaceModel.InsertNamespace(ctx, &namespace); err != nil {
+			return 0, err
+		}
+		if err := namespaceModel.AddUsersInNamespace(namespace.Id, namespaceModel.AddUsersParam{
+			Users: []namespaceModel.UserData{{
+				Id:         creatorId,
+				Permission: int(namespaceModel.AdminPermission),
+			}},
+		}); err != nil {
+			return 0, err
+		}
+		log.Infof("create platform namespace[%d] mirrored from kubernetes namespace[%s] of cluster[%d]", namespace.Id, kubeNamespace, clusterId)
+	} else {
+		return 0, err
+	}
+
+	if mirror != nil {
+		err = namespaceModel.UpdateMirrorNamespaceId(ctx, mirror.Id, namespace.Id)
+	} else {
+		err = namespaceModel.InsertMirror(ctx, &namespaceModel.Mirror{NamespaceId: namespace.Id, ClusterId: clusterId, KubeNamespace: kubeNamespace})
+	}
+	if err != nil {
+		return 0, err
+	}
+	return namespace.Id, addAttackableCluster(namespace.Id, clusterId)
+}
+
+func addAttackableCluster(namespaceId, clusterId int) error {
+	// the cluster of the run mode is not a registered cluster
+	if clusterId <= 0 {
+		return nil
+	}
+
+	clusterIds, err := namespaceModel.GetClusterIDsByNamespaceID(namespaceId)
+	if err != nil {
+		return err
+	}
+	for _, id := range clusterIds {
+		if id == clusterId {
+			return nil
+		}
+	}
+	return namespaceModel.SetClusterIDsForNamespace(namespaceId, append(clusterIds, clusterId))
+}
+
+// seedMirrorMembers adds the platform users bound by the rolebindings of the kubernetes namespace,
+// users bound to an admin role become admins, existing members are promoted but never demoted
+func seedMirrorMembers(ctx context.Context, kubeClient kubernetes.Interface, kubeNamespace string, namespaceId int) error {
+	roleBindings, err := kubeClient.RbacV1().RoleBindings(kubeNamespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("list rolebindings error: %s", err.Error())
+	}
+
+	adminRoles := config.DefaultRunOptIns.NamespaceMirror.AdminRoles
+	if len(adminRoles) == 0 {
+		adminRoles = defaultMirrorAdminRoles
+	}
+
+	permissions := make(map[string]namespaceModel.Permission)
+	for _, roleBinding := range roleBindings.Items {
+		permission := namespaceModel.NormalPermission
+		for _, role := range adminRoles {
+			if roleBinding.RoleRef.Name == role {
+				permission = namespaceModel.AdminPermission
+				break
+			}
+		}
+
+		for _, subject := range roleBinding.Subjects {
+			if subject.Kind != rbacv1.UserKind {
+				continue
+			}
+			if existed, ok := permissions[subject.Name]; !ok || existed < permission {
+				permissions[subject.Name] = permission
+			}
+		}
+	}
+
+	for userName, permission := range permissions {
+		u := user.User{Email: userName}
+		if err := user.GetUser(ctx, &u); err != nil {
+			// only users who have logged in to the platform can be members
+			continue
+		}
+
+		member := namespaceModel.UserNamespace{UserId: u.ID, NamespaceId: namespaceId}
+		if err := namespaceModel.GetUserNamespace(&member); err != nil {
+			if err := namespaceModel.AddUsersInNamespace(namespaceId, namespaceModel.AddUsersParam{
+				Users: []namespaceModel.UserData{{Id: u.ID, Permission: int(permission)}},
+			}); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if member.Permission < permission {
+			if err := namespaceModel.UpdateUserPermissionInNamespace(namespaceId, u.ID, permission); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
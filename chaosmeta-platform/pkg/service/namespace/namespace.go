@@ -20,6 +20,7 @@ import (
 	"chaosmeta-platform/pkg/models/experiment"
 	namespaceModel "chaosmeta-platform/pkg/models/namespace"
 	"chaosmeta-platform/pkg/models/user"
+	platformErrors "chaosmeta-platform/util/errors"
 	"chaosmeta-platform/util/log"
 	"context"
 	"errors"
@@ -29,6 +30,12 @@ func Init() {
 	namespace := namespaceModel.Namespace{}
 	ctx := context.Background()
 
+	br := BudgetRoutine{context: ctx}
+	go br.Start()
+
+	mr := MirrorRoutine{context: ctx}
+	go mr.Start()
+
 	if err := namespaceModel.GetDefaultNamespace(ctx, &namespace); err == nil {
 		return
 	}
@@ -89,7 +96,7 @@ func (s *NamespaceService) Create(ctx context.Context, name, description string,
 
 func (s *NamespaceService) Update(ctx context.Context, userName string, namespaceId int, namespaceName string, namespaceDescription string) error {
 	if !s.IsAdmin(ctx, namespaceId, userName) {
-		return errors.New("permission denied")
+		return platformErrors.ErrPermissionDenied
 	}
 
 	namespace := namespaceModel.Namespace{Id: namespaceId}
@@ -127,19 +134,26 @@ func (s *NamespaceService) Delete(ctx context.Context, userName string, namespac
 		return errors.New("default namespace, remove users are not allowed")
 	}
 	if !s.IsAdmin(ctx, namespaceId, userName) {
-		return errors.New("permission denied")
+		return platformErrors.ErrPermissionDenied
 	}
 	namespace := namespaceModel.Namespace{Id: namespaceId}
 	if err := namespaceModel.GetNamespaceById(ctx, &namespace); err != nil {
 		return errors.New("namespace not found")
 	}
+	return s.Purge(ctx, namespaceId)
+}
 
+// Purge deletes the namespace with its members, clusters and mirrors without any permission check
+func (s *NamespaceService) Purge(ctx context.Context, namespaceId int) error {
 	if _, err := namespaceModel.DeleteNamespace(ctx, namespaceId); err != nil {
 		return err
 	}
 	if err := namespaceModel.ClearClusterIDsForNamespace(namespaceId); err != nil {
 		return err
 	}
+	if err := namespaceModel.DeleteMirrorsByNamespaceId(ctx, namespaceId); err != nil {
+		return err
+	}
 	return namespaceModel.UsersOrNamespacesDelete(nil, []int{namespaceId})
 }
 
@@ -156,7 +170,7 @@ func (s *NamespaceService) AddUsers(ctx context.Context, userName string, namesp
 		return errors.New("default namespace, add users are not allowed")
 	}
 	if !s.IsAdmin(ctx, namespaceId, userName) {
-		return errors.New("permission denied")
+		return platformErrors.ErrPermissionDenied
 	}
 	return namespaceModel.AddUsersInNamespace(namespaceId, addUsersParam)
 }
@@ -302,7 +316,7 @@ func (s *NamespaceService) RemoveUsers(ctx context.Context, userName string, use
 		return errors.New("default namespace, remove users are not allowed")
 	}
 	if !s.IsAdmin(ctx, namespaceId, userName) {
-		return errors.New("permission denied")
+		return platformErrors.ErrPermissionDenied
 	}
 	return namespaceModel.RemoveUsersFromNamespace(namespaceId, userIds)
 }
@@ -312,7 +326,7 @@ func (s *NamespaceService) ChangeUsersPermission(ctx context.Context, userName s
 		return errors.New("default namespace, permission changes are not allowed")
 	}
 	if !s.IsAdmin(ctx, namespaceId, userName) {
-		return errors.New("permission denied")
+		return platformErrors.ErrPermissionDenied
 	}
 	return namespaceModel.UpdateUsersPermissionInNamespace(namespaceId, userIds, permission)
 }
@@ -0,0 +1,251 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sandbox
+
+import (
+	"chaosmeta-platform/config"
+	namespaceModel "chaosmeta-platform/pkg/models/namespace"
+	"chaosmeta-platform/pkg/service/cluster"
+	"chaosmeta-platform/pkg/service/experiment"
+	"chaosmeta-platform/pkg/service/namespace"
+	platformErrors "chaosmeta-platform/util/errors"
+	"chaosmeta-platform/util/log"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/robfig/cron"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"strings"
+	"time"
+)
+
+const (
+	defaultTTL      = 4 * time.Hour
+	defaultMaxTTL   = 72 * time.Hour
+	defaultInterval = "1m"
+	sandboxLabelKey = "sandbox"
+)
+
+func Init() {
+	r := SandboxRoutine{context: context.Background()}
+	go r.Start()
+}
+
+type SandboxRoutine struct {
+	context   context.Context
+	localCron *cron.Cron
+}
+
+func (r *SandboxRoutine) TeardownExpired() {
+	sandboxes, err := namespaceModel.ListExpiredSandboxes(r.context, time.Now())
+	if err != nil {
+		log.Errorf("list expired sandboxes error: %s", err.Error())
+		return
+	}
+	for i := range sandboxes {
+		log.Infof("sandbox namespace[%d] expired at %s, tear down", sandboxes[i].NamespaceId, sandboxes[i].ExpireTime.Format(time.RFC3339))
+		if err := teardown(r.context, &sandboxes[i]); err != nil {
+			log.Errorf("tear down sandbox namespace[%d] error: %s", sandboxes[i].NamespaceId, err.Error())
+		}
+	}
+}
+
+func (r *SandboxRoutine) Start() {
+	interval := config.DefaultRunOptIns.Sandbox.Interval
+	if interval == "" {
+		interval = defaultInterval
+	}
+
+	localCron := cron.New()
+	if err := localCron.AddFunc(fmt.Sprintf("@every %s", interval), r.TeardownExpired); err != nil {
+		log.Error(err)
+		return
+	}
+
+	localCron.Start()
+	r.localCron = localCron
+
+	select {
+	case <-r.context.Done():
+		log.Info("Receive stop signal")
+	}
+}
+
+type SandboxService struct{}
+
+// Create creates a platform namespace administrated by the user which is torn down after ttl, and a kubernetes
+// namespace for it if a prefix is configured
+func (s *SandboxService) Create(ctx context.Context, userName, name, description, ttl string) (*namespaceModel.Sandbox, error) {
+	duration, err := parseTTL(ttl)
+	if err != nil {
+		return nil, err
+	}
+
+	var kubeNamespace string
+	prefix := config.DefaultRunOptIns.Sandbox.KubeNamespacePrefix
+	if prefix != "" && !config.DefaultRunOptIns.RunMode.IsStandalone() {
+		kubeNamespace = prefix + name
+		if errs := validation.IsDNS1123Label(kubeNamespace); len(errs) > 0 {
+			return nil, fmt.Errorf("invalid kubernetes namespace name[%s]: %s", kubeNamespace, strings.Join(errs, ", "))
+		}
+	}
+
+	namespaceService := namespace.NamespaceService{}
+	namespaceId, err := namespaceService.Create(ctx, name, description, userName)
+	if err != nil {
+		return nil, err
+	}
+
+	sandbox := &namespaceModel.Sandbox{
+		NamespaceId: int(namespaceId),
+		ClusterId:   config.DefaultRunOptIns.RunMode.Int(),
+		ExpireTime:  time.Now().Add(duration),
+	}
+	if err := createKubeNamespace(ctx, sandbox, kubeNamespace); err != nil {
+		rollback(ctx, sandbox)
+		return nil, err
+	}
+	if err := namespaceModel.InsertSandbox(ctx, sandbox); err != nil {
+		rollback(ctx, sandbox)
+		return nil, err
+	}
+	return sandbox, nil
+}
+
+// rollback removes what is created for a sandbox which failed to be recorded, nothing can run in it yet
+func rollback(ctx context.Context, sandbox *namespaceModel.Sandbox) {
+	if err := deleteKubeNamespace(ctx, sandbox); err != nil {
+		log.Error(err)
+	}
+	namespaceService := namespace.NamespaceService{}
+	if err := namespaceService.Purge(ctx, sandbox.NamespaceId); err != nil {
+		log.Errorf("purge namespace[%d] error: %s", sandbox.NamespaceId, err.Error())
+	}
+}
+
+func (s *SandboxService) List(ctx context.Context) ([]namespaceModel.Sandbox, error) {
+	return namespaceModel.ListSandboxes(ctx)
+}
+
+// Teardown lets an admin of the sandbox tear it down before it expires
+func (s *SandboxService) Teardown(ctx context.Context, userName string, namespaceId int) error {
+	namespaceService := namespace.NamespaceService{}
+	if !namespaceService.IsAdmin(ctx, namespaceId, userName) {
+		return platformErrors.ErrPermissionDenied
+	}
+	sandbox := namespaceModel.Sandbox{NamespaceId: namespaceId}
+	if err := namespaceModel.GetSandboxByNamespaceId(ctx, &sandbox); err != nil {
+		return errors.New("namespace is not a sandbox")
+	}
+	return teardown(ctx, &sandbox)
+}
+
+// parseTTL returns the default ttl if not given, the ttl can not exceed the configured max ttl
+func parseTTL(ttl string) (time.Duration, error) {
+	sandboxConfig := config.DefaultRunOptIns.Sandbox
+	if ttl == "" {
+		ttl = sandboxConfig.DefaultTTL
+	}
+	duration := defaultTTL
+	if ttl != "" {
+		d, err := time.ParseDuration(ttl)
+		if err != nil {
+			return 0, fmt.Errorf("invalid ttl[%s]: %s", ttl, err.Error())
+		}
+		duration = d
+	}
+	if duration <= 0 {
+		return 0, fmt.Errorf("ttl[%s] must be positive", ttl)
+	}
+
+	maxTTL := defaultMaxTTL
+	if sandboxConfig.MaxTTL != "" {
+		d, err := time.ParseDuration(sandboxConfig.MaxTTL)
+		if err != nil {
+			return 0, fmt.Errorf("invalid max ttl[%s]: %s", sandboxConfig.MaxTTL, err.Error())
+		}
+		maxTTL = d
+	}
+	if duration > maxTTL {
+		return 0, fmt.Errorf("ttl[%s] exceeds the max ttl[%s]", duration, maxTTL)
+	}
+	return duration, nil
+}
+
+// createKubeNamespace only records the kubernetes namespace once created, so that an existing one is never rolled back
+func createKubeNamespace(ctx context.Context, sandbox *namespaceModel.Sandbox, name string) error {
+	if name == "" {
+		return nil
+	}
+	clusterService := cluster.ClusterService{}
+	kubeClient, _, err := clusterService.GetRestConfig(ctx, sandbox.ClusterId)
+	if err != nil {
+		return err
+	}
+	kubeNamespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			Labels: map[string]string{
+				"app.kubernetes.io/managed-by": experiment.ManagedByValue,
+				fmt.Sprintf("%s/%s", config.DefaultRunOptIns.Naming.LabelDomain, sandboxLabelKey): "true",
+			},
+		},
+	}
+	if _, err := kubeClient.CoreV1().Namespaces().Create(ctx, kubeNamespace, metav1.CreateOptions{}); err != nil {
+		return fmt.Errorf("create kubernetes namespace[%s] error: %s", name, err.Error())
+	}
+	sandbox.KubeNamespace = name
+
+	// the cluster of the run mode is not a registered cluster
+	if sandbox.ClusterId > 0 {
+		return namespaceModel.SetClusterIDsForNamespace(sandbox.NamespaceId, []int{sandbox.ClusterId})
+	}
+	return nil
+}
+
+func deleteKubeNamespace(ctx context.Context, sandbox *namespaceModel.Sandbox) error {
+	if sandbox.KubeNamespace == "" {
+		return nil
+	}
+	clusterService := cluster.ClusterService{}
+	kubeClient, _, err := clusterService.GetRestConfig(ctx, sandbox.ClusterId)
+	if err != nil {
+		return err
+	}
+	if err := kubeClient.CoreV1().Namespaces().Delete(ctx, sandbox.KubeNamespace, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("delete kubernetes namespace[%s] error: %s", sandbox.KubeNamespace, err.Error())
+	}
+	return nil
+}
+
+// teardown keeps the sandbox record until everything is cleaned up, so that a failed teardown is retried next round
+func teardown(ctx context.Context, sandbox *namespaceModel.Sandbox) error {
+	if err := experiment.TeardownNamespace(ctx, sandbox.NamespaceId); err != nil {
+		return err
+	}
+	if err := deleteKubeNamespace(ctx, sandbox); err != nil {
+		return err
+	}
+	namespaceService := namespace.NamespaceService{}
+	if err := namespaceService.Purge(ctx, sandbox.NamespaceId); err != nil {
+		return err
+	}
+	return namespaceModel.DeleteSandbox(ctx, sandbox.Id)
+}
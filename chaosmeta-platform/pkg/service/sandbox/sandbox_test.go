@@ -0,0 +1,56 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sandbox
+
+import (
+	"chaosmeta-platform/config"
+	"testing"
+	"time"
+)
+
+func TestParseTTL(t *testing.T) {
+	tests := []struct {
+		name       string
+		defaultTTL string
+		maxTTL     string
+		ttl        string
+		want       time.Duration
+		wantErr    bool
+	}{
+		{name: "builtin default", want: defaultTTL},
+		{name: "configured default", defaultTTL: "2h", want: 2 * time.Hour},
+		{name: "given", defaultTTL: "2h", ttl: "30m", want: 30 * time.Minute},
+		{name: "builtin max", ttl: "73h", wantErr: true},
+		{name: "configured max", maxTTL: "1h", ttl: "90m", wantErr: true},
+		{name: "negative", ttl: "-1h", wantErr: true},
+		{name: "invalid", ttl: "1 day", wantErr: true},
+	}
+	config.DefaultRunOptIns = &config.Config{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config.DefaultRunOptIns.Sandbox.DefaultTTL = tt.defaultTTL
+			config.DefaultRunOptIns.Sandbox.MaxTTL = tt.maxTTL
+			got, err := parseTTL(tt.ttl)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseTTL() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("parseTTL() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,261 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package scenario
+
+import (
+	experimentModel "chaosmeta-platform/pkg/models/experiment"
+	scenarioModel "chaosmeta-platform/pkg/models/scenario"
+	"chaosmeta-platform/pkg/service/experiment"
+	"chaosmeta-platform/pkg/service/experiment_instance"
+	"chaosmeta-platform/util/log"
+	"chaosmeta-platform/util/snowflake"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+type ScenarioService struct{}
+
+// TimelineEntry is how a node of the source experiment instance actually went
+type TimelineEntry struct {
+	Name       string `json:"name"`
+	Row        int    `json:"row"`
+	Column     int    `json:"column"`
+	ExecType   string `json:"exec_type"`
+	ExecName   string `json:"exec_name"`
+	Duration   string `json:"duration"`
+	Status     string `json:"status"`
+	Message    string `json:"message"`
+	CreateTime string `json:"create_time"`
+	UpdateTime string `json:"update_time"`
+}
+
+type ScenarioContent struct {
+	// WorkflowNodes keeps the resolved targets and the fault parameters of the source experiment instance
+	WorkflowNodes []*experiment.WorkflowNode `json:"workflow_nodes"`
+	Timeline      []TimelineEntry            `json:"timeline"`
+}
+
+type ScenarioCreate struct {
+	Name               string `json:"name"`
+	Incident           string `json:"incident"`
+	Description        string `json:"description"`
+	SourceInstanceUUID string `json:"source_instance_uuid"`
+}
+
+type ScenarioGet struct {
+	scenarioModel.Scenario
+	ScenarioContent
+}
+
+func createNodeUUID(creator int) string {
+	nodeSnow, err := snowflake.NewNode(1)
+	if err != nil {
+		log.Error(err)
+		return ""
+	}
+	return fmt.Sprintf("%d%d", nodeSnow.Generate(), creator)
+}
+
+// Record saves an experiment instance as a replayable scenario
+func (s *ScenarioService) Record(param *ScenarioCreate, creator int) (int, error) {
+	if param == nil {
+		return 0, errors.New("scenario param is nil")
+	}
+	if param.Name == "" || param.Incident == "" {
+		return 0, errors.New("name and incident are required")
+	}
+
+	instanceService := experiment_instance.ExperimentInstanceService{}
+	instance, err := instanceService.GetExperimentInstanceByUUID(param.SourceInstanceUUID)
+	if err != nil {
+		return 0, err
+	}
+
+	nodes, err := instanceService.GetWorkflowNodeInstanceDetailList(param.SourceInstanceUUID)
+	if err != nil {
+		return 0, err
+	}
+
+	content := convertToScenarioContent(nodes)
+	contentBytes, err := json.Marshal(content)
+	if err != nil {
+		return 0, err
+	}
+
+	scenario := scenarioModel.Scenario{
+		Name:               param.Name,
+		Incident:           param.Incident,
+		Description:        param.Description,
+		NamespaceID:        instance.NamespaceId,
+		SourceInstanceUUID: param.SourceInstanceUUID,
+		Content:            string(contentBytes),
+		Creator:            creator,
+	}
+	if err := scenarioModel.CreateScenario(&scenario); err != nil {
+		return 0, err
+	}
+	return scenario.Id, nil
+}
+
+func convertToScenarioContent(nodes []*experiment_instance.WorkflowNodesDetail) *ScenarioContent {
+	content := &ScenarioContent{}
+	for _, node := range nodes {
+		workflowNode := &experiment.WorkflowNode{
+			WorkflowNode: experimentModel.WorkflowNode{
+				Name:     node.Name,
+				Row:      node.Row,
+				Column:   node.Column,
+				Duration: node.Duration,
+				ScopeId:  node.ScopeId,
+				TargetId: node.TargetId,
+				ExecName: node.ExecName,
+				ExecType: node.ExecType,
+				ExecID:   node.ExecId,
+			},
+		}
+		for _, arg := range node.ArgsValues {
+			workflowNode.ArgsValue = append(workflowNode.ArgsValue, &experimentModel.ArgsValue{ArgsID: arg.ArgsId, Value: arg.Value})
+		}
+
+		switch node.ExecType {
+		case string(experiment.FaultExecType):
+			if node.Subtasks != nil {
+				workflowNode.FaultRange = &experimentModel.FaultRange{
+					TargetName:      node.Subtasks.TargetName,
+					TargetIP:        node.Subtasks.TargetIP,
+					TargetHostname:  node.Subtasks.TargetHostname,
+					TargetLabel:     node.Subtasks.TargetLabel,
+					TargetApp:       node.Subtasks.TargetApp,
+					TargetNamespace: node.Subtasks.TargetNamespace,
+					RangeType:       node.Subtasks.RangeType,
+				}
+			}
+		case string(experiment.FlowExecType):
+			if node.FlowSubtasks != nil {
+				workflowNode.FlowRange = &experimentModel.FlowRange{
+					Source:      node.FlowSubtasks.Source,
+					Parallelism: node.FlowSubtasks.Parallelism,
+					Duration:    node.FlowSubtasks.Duration,
+					FlowType:    node.FlowSubtasks.FlowType,
+				}
+			}
+		case string(experiment.MeasureExecType):
+			if node.MeasureSubtasks != nil {
+				workflowNode.MeasureRange = &experimentModel.MeasureRange{
+					JudgeValue:   node.MeasureSubtasks.JudgeValue,
+					JudgeType:    node.MeasureSubtasks.JudgeType,
+					FailedCount:  node.MeasureSubtasks.FailedCount,
+					SuccessCount: node.MeasureSubtasks.SuccessCount,
+					Interval:     node.MeasureSubtasks.Interval,
+					Duration:     node.MeasureSubtasks.Duration,
+					MeasureType:  node.MeasureSubtasks.MeasureType,
+				}
+			}
+		}
+		content.WorkflowNodes = append(content.WorkflowNodes, workflowNode)
+
+		content.Timeline = append(content.Timeline, TimelineEntry{
+			Name:       node.Name,
+			Row:        node.Row,
+			Column:     node.Column,
+			ExecType:   node.ExecType,
+			ExecName:   node.ExecName,
+			Duration:   node.Duration,
+			Status:     node.Status,
+			Message:    node.Message,
+			CreateTime: node.CreateTime,
+			UpdateTime: node.UpdateTime,
+		})
+	}
+	return content
+}
+
+func (s *ScenarioService) Get(id int) (*ScenarioGet, error) {
+	scenario, err := scenarioModel.GetScenarioById(id)
+	if err != nil {
+		return nil, err
+	}
+	if scenario == nil {
+		return nil, fmt.Errorf("no scenario found with id %d", id)
+	}
+
+	scenarioGet := &ScenarioGet{Scenario: *scenario}
+	if err := json.Unmarshal([]byte(scenario.Content), &scenarioGet.ScenarioContent); err != nil {
+		return nil, fmt.Errorf("unmarshal content of scenario[%d] error: %s", id, err.Error())
+	}
+	return scenarioGet, nil
+}
+
+func (s *ScenarioService) Search(namespaceId int, name, incident, orderBy string, page, pageSize int) (int64, []*scenarioModel.Scenario, error) {
+	return scenarioModel.SearchScenarios(namespaceId, name, incident, orderBy, page, pageSize)
+}
+
+func (s *ScenarioService) Delete(id int) error {
+	return scenarioModel.DeleteScenarioById(id)
+}
+
+// Replay creates a manual experiment with the recorded targets, parameters and timeline of the scenario and starts it
+func (s *ScenarioService) Replay(id int, creator int, creatorName string) (string, error) {
+	scenario, err := s.Get(id)
+	if err != nil {
+		return "", err
+	}
+	if len(scenario.WorkflowNodes) == 0 {
+		return "", fmt.Errorf("scenario[%d] has no workflow node", id)
+	}
+
+	// the node uuids are regenerated since they are unique per experiment, and the recorded secrets are decrypted to be
+	// encrypted again by the replay experiment
+	for _, node := range scenario.WorkflowNodes {
+		node.UUID = createNodeUUID(creator)
+		if err := experiment.DecryptSensitiveArgsValues(scenario.NamespaceID, node.ArgsValue); err != nil {
+			return "", err
+		}
+	}
+
+	experimentService := experiment.ExperimentService{}
+	experimentUUID, err := experimentService.CreateExperiment(&experiment.ExperimentCreate{
+		ExperimentInfo: experiment.ExperimentInfo{
+			Name:         fmt.Sprintf("%s-replay-%s", scenario.Name, time.Now().Format("20060102150405")),
+			Description:  fmt.Sprintf("replay of incident %s", scenario.Incident),
+			ScheduleType: string(experimentModel.ManualMode),
+			NamespaceID:  scenario.NamespaceID,
+			Creator:      creator,
+		},
+		WorkflowNodes: scenario.WorkflowNodes,
+	})
+	if err != nil {
+		return "", fmt.Errorf("create replay experiment error: %s", err.Error())
+	}
+
+	if err := scenarioModel.UpdateScenarioLastReplay(id, experimentUUID); err != nil {
+		log.Error(err)
+	}
+
+	if err := experimentService.UpdateExperimentStatusAndLastInstance(experimentUUID, int(experimentModel.ToBeExecuted), time.Now().Format(experimentModel.TimeLayout)); err != nil {
+		log.Error(err)
+	}
+	if err := experiment.StartExperiment(experimentUUID, creatorName); err != nil {
+		if err := experimentService.UpdateExperimentStatusAndLastInstance(experimentUUID, int(experimentModel.Executed), time.Now().Format(experimentModel.TimeLayout)); err != nil {
+			log.Error(err)
+		}
+		return experimentUUID, fmt.Errorf("start replay experiment error: %s", err.Error())
+	}
+	return experimentUUID, nil
+}
@@ -0,0 +1,130 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package trigger
+
+import (
+	"bufio"
+	"chaosmeta-platform/util/log"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const natsReconnectInterval = 5 * time.Second
+
+// NatsSubscriber is a minimal client of the nats text protocol, it only subscribes to Subjects and hands the
+// messages to Handler, reconnecting until ctx is done
+type NatsSubscriber struct {
+	Url      string
+	Subjects []string
+	Token    string
+	Handler  func(subject string, payload []byte)
+}
+
+func (n *NatsSubscriber) Run(ctx context.Context) {
+	for {
+		if err := n.subscribe(ctx); err != nil {
+			log.Errorf("nats subscriber of %s error: %s", n.Url, err.Error())
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(natsReconnectInterval):
+		}
+	}
+}
+
+func (n *NatsSubscriber) subscribe(ctx context.Context) error {
+	addr := strings.TrimPrefix(n.Url, "nats://")
+	conn, err := net.DialTimeout("tcp", addr, 10*time.Second)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	// closes the connection to unblock the read when ctx is done, and exits with this connection on reconnect
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-done:
+		}
+	}()
+
+	reader := bufio.NewReader(conn)
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return err
+	}
+	if !strings.HasPrefix(line, "INFO") {
+		return fmt.Errorf("unexpected greeting: %s", strings.TrimSpace(line))
+	}
+
+	connectOpts, _ := json.Marshal(map[string]interface{}{
+		"verbose":    false,
+		"pedantic":   false,
+		"name":       "chaosmeta-platform",
+		"lang":       "go",
+		"auth_token": n.Token,
+	})
+	if _, err := fmt.Fprintf(conn, "CONNECT %s\r\n", connectOpts); err != nil {
+		return err
+	}
+	for i, subject := range n.Subjects {
+		if _, err := fmt.Fprintf(conn, "SUB %s %d\r\n", subject, i+1); err != nil {
+			return err
+		}
+	}
+	log.Infof("nats subscriber connected to %s, subjects: %v", n.Url, n.Subjects)
+
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return err
+		}
+		line = strings.TrimRight(line, "\r\n")
+		switch {
+		case strings.HasPrefix(line, "PING"):
+			if _, err := io.WriteString(conn, "PONG\r\n"); err != nil {
+				return err
+			}
+		case strings.HasPrefix(line, "-ERR"):
+			return fmt.Errorf("server error: %s", line)
+		case strings.HasPrefix(line, "MSG"):
+			// MSG <subject> <sid> [reply-to] <#bytes>
+			fields := strings.Fields(line)
+			if len(fields) < 4 {
+				return fmt.Errorf("invalid message: %s", line)
+			}
+			size, err := strconv.Atoi(fields[len(fields)-1])
+			if err != nil {
+				return fmt.Errorf("invalid message size: %s", line)
+			}
+			payload := make([]byte, size+2)
+			if _, err := io.ReadFull(reader, payload); err != nil {
+				return err
+			}
+			go n.Handler(fields[1], payload[:size])
+		}
+	}
+}
@@ -0,0 +1,226 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package trigger
+
+import (
+	"chaosmeta-platform/config"
+	triggerModel "chaosmeta-platform/pkg/models/trigger"
+	"chaosmeta-platform/pkg/service/experiment"
+	"chaosmeta-platform/pkg/service/user"
+	"chaosmeta-platform/util/log"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+func Init() {
+	if config.DefaultRunOptIns.EventTrigger.Nats.Url == "" {
+		return
+	}
+	subscriber := NatsSubscriber{
+		Url:      config.DefaultRunOptIns.EventTrigger.Nats.Url,
+		Subjects: config.DefaultRunOptIns.EventTrigger.Nats.Subjects,
+		Token:    config.DefaultRunOptIns.EventTrigger.Nats.Token,
+		Handler: func(subject string, payload []byte) {
+			if _, err := Dispatch(subject, payload); err != nil {
+				log.Errorf("dispatch event of subject[%s] error: %s", subject, err.Error())
+			}
+		},
+	}
+	go subscriber.Run(context.Background())
+}
+
+type EventTriggerService struct{}
+
+type EventTriggerCreate struct {
+	Name           string `json:"name"`
+	Topic          string `json:"topic"`
+	Filter         string `json:"filter"`
+	ExperimentUUID string `json:"experiment_uuid"`
+	Enabled        *bool  `json:"enabled"`
+}
+
+func checkAdmin(ctx context.Context, userName string) error {
+	userService := user.UserService{}
+	if !userService.IsAdmin(ctx, userName) {
+		return errors.New("only admins can manage event triggers")
+	}
+	return nil
+}
+
+func validateTrigger(param *EventTriggerCreate) error {
+	if param.Topic == "" || param.ExperimentUUID == "" {
+		return errors.New("topic and experiment_uuid are required")
+	}
+	if _, _, err := parseFilter(param.Filter); err != nil {
+		return err
+	}
+	experimentService := experiment.ExperimentService{}
+	experimentGet, err := experimentService.GetExperimentByUUID(param.ExperimentUUID)
+	if err != nil || experimentGet == nil {
+		return fmt.Errorf("experiment[%s] not found", param.ExperimentUUID)
+	}
+	return nil
+}
+
+func (s *EventTriggerService) Create(ctx context.Context, userName string, param *EventTriggerCreate) (int64, error) {
+	if err := checkAdmin(ctx, userName); err != nil {
+		return 0, err
+	}
+	if param == nil {
+		return 0, errors.New("event trigger param is nil")
+	}
+	if param.Name == "" {
+		return 0, errors.New("name is required")
+	}
+	if err := validateTrigger(param); err != nil {
+		return 0, err
+	}
+
+	enabled := true
+	if param.Enabled != nil {
+		enabled = *param.Enabled
+	}
+	return triggerModel.CreateEventTrigger(&triggerModel.EventTrigger{
+		Name:           param.Name,
+		Topic:          param.Topic,
+		Filter:         param.Filter,
+		ExperimentUUID: param.ExperimentUUID,
+		Enabled:        enabled,
+		Creator:        userName,
+	})
+}
+
+func (s *EventTriggerService) Update(ctx context.Context, userName string, id int, param *EventTriggerCreate) error {
+	if err := checkAdmin(ctx, userName); err != nil {
+		return err
+	}
+	if param == nil {
+		return errors.New("event trigger param is nil")
+	}
+	eventTrigger, err := triggerModel.GetEventTriggerById(id)
+	if err != nil {
+		return err
+	}
+	if eventTrigger == nil {
+		return fmt.Errorf("event trigger[%d] not found", id)
+	}
+	if err := validateTrigger(param); err != nil {
+		return err
+	}
+
+	eventTrigger.Topic, eventTrigger.Filter, eventTrigger.ExperimentUUID = param.Topic, param.Filter, param.ExperimentUUID
+	if param.Enabled != nil {
+		eventTrigger.Enabled = *param.Enabled
+	}
+	return triggerModel.UpdateEventTrigger(eventTrigger)
+}
+
+func (s *EventTriggerService) Delete(ctx context.Context, userName string, id int) error {
+	if err := checkAdmin(ctx, userName); err != nil {
+		return err
+	}
+	return triggerModel.DeleteEventTriggerById(id)
+}
+
+func (s *EventTriggerService) List() ([]*triggerModel.EventTrigger, error) {
+	return triggerModel.ListEventTriggers()
+}
+
+// Receive handles an event posted by an argo events http trigger or any other webhook source
+func (s *EventTriggerService) Receive(ctx context.Context, userName string, topic string, payload []byte) ([]string, error) {
+	if err := checkAdmin(ctx, userName); err != nil {
+		return nil, err
+	}
+	return Dispatch(topic, payload)
+}
+
+// Dispatch starts the experiments of the enabled triggers of topic whose filter matches payload,
+// and returns the uuid of the started experiments
+func Dispatch(topic string, payload []byte) ([]string, error) {
+	triggers, err := triggerModel.ListEnabledEventTriggersByTopic(topic)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		started []string
+		errMsgs []string
+	)
+	for _, t := range triggers {
+		matched, err := matchFilter(t.Filter, payload)
+		if err != nil {
+			errMsgs = append(errMsgs, fmt.Sprintf("trigger[%s]: %s", t.Name, err.Error()))
+			continue
+		}
+		if !matched {
+			continue
+		}
+
+		log.Infof("event of topic[%s] triggers experiment[%s] by trigger[%s]", topic, t.ExperimentUUID, t.Name)
+		if err := experiment.StartExperiment(t.ExperimentUUID, t.Creator); err != nil {
+			errMsgs = append(errMsgs, fmt.Sprintf("trigger[%s] start experiment[%s]: %s", t.Name, t.ExperimentUUID, err.Error()))
+			continue
+		}
+		started = append(started, t.ExperimentUUID)
+		if err := triggerModel.UpdateEventTriggerLastTriggered(t.Id, time.Now()); err != nil {
+			log.Error(err)
+		}
+	}
+
+	if len(errMsgs) > 0 {
+		return started, errors.New(strings.Join(errMsgs, "; "))
+	}
+	return started, nil
+}
+
+// parseFilter splits a "path=value" filter, path is dot separated keys of the json payload
+func parseFilter(filter string) (string, string, error) {
+	if filter == "" {
+		return "", "", nil
+	}
+	kv := strings.SplitN(filter, "=", 2)
+	if len(kv) != 2 || strings.TrimSpace(kv[0]) == "" {
+		return "", "", fmt.Errorf("filter[%s] is not in format path=value", filter)
+	}
+	return strings.TrimSpace(kv[0]), strings.TrimSpace(kv[1]), nil
+}
+
+func matchFilter(filter string, payload []byte) (bool, error) {
+	path, expected, err := parseFilter(filter)
+	if err != nil || path == "" {
+		return err == nil, err
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(payload, &value); err != nil {
+		return false, fmt.Errorf("payload is not json: %s", err.Error())
+	}
+	for _, key := range strings.Split(path, ".") {
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return false, nil
+		}
+		if value, ok = m[key]; !ok {
+			return false, nil
+		}
+	}
+	return fmt.Sprint(value) == expected, nil
+}
@@ -0,0 +1,82 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package trigger
+
+import (
+	"testing"
+)
+
+func TestParseFilter(t *testing.T) {
+	tests := []struct {
+		name      string
+		filter    string
+		wantPath  string
+		wantValue string
+		wantErr   bool
+	}{
+		{name: "empty", filter: ""},
+		{name: "path and value", filter: "body.env=prod", wantPath: "body.env", wantValue: "prod"},
+		{name: "spaces are trimmed", filter: " env = prod ", wantPath: "env", wantValue: "prod"},
+		{name: "value with equal sign", filter: "query=a=b", wantPath: "query", wantValue: "a=b"},
+		{name: "empty value", filter: "env=", wantPath: "env"},
+		{name: "no equal sign", filter: "env", wantErr: true},
+		{name: "empty path", filter: " =prod", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, value, err := parseFilter(tt.filter)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseFilter() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if path != tt.wantPath || value != tt.wantValue {
+				t.Errorf("parseFilter() = %q, %q, want %q, %q", path, value, tt.wantPath, tt.wantValue)
+			}
+		})
+	}
+}
+
+func TestMatchFilter(t *testing.T) {
+	payload := []byte(`{"env":"prod","body":{"replicas":3,"ready":true,"tags":["a"]}}`)
+	tests := []struct {
+		name    string
+		filter  string
+		payload []byte
+		want    bool
+		wantErr bool
+	}{
+		{name: "empty filter matches all", filter: "", payload: []byte("not json"), want: true},
+		{name: "top level", filter: "env=prod", payload: payload, want: true},
+		{name: "top level mismatch", filter: "env=test", payload: payload},
+		{name: "nested number", filter: "body.replicas=3", payload: payload, want: true},
+		{name: "nested bool", filter: "body.ready=true", payload: payload, want: true},
+		{name: "missing key", filter: "body.name=x", payload: payload},
+		{name: "path through non object", filter: "env.name=prod", payload: payload},
+		{name: "invalid filter", filter: "env", payload: payload, wantErr: true},
+		{name: "payload not json", filter: "env=prod", payload: []byte("env=prod"), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := matchFilter(tt.filter, tt.payload)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("matchFilter() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("matchFilter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
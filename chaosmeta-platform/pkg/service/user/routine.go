@@ -0,0 +1,80 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package user
+
+import (
+	"chaosmeta-platform/config"
+	"chaosmeta-platform/pkg/models/user"
+	"chaosmeta-platform/util/log"
+	"context"
+	"github.com/robfig/cron"
+	"time"
+)
+
+type InactiveUserRoutine struct {
+	context   context.Context
+	localCron *cron.Cron
+}
+
+// DisableInactiveUsers disables the normal users who have been inactive for more than userPolicy.inactiveDays, admins are never disabled
+func (r *InactiveUserRoutine) DisableInactiveUsers() {
+	inactiveDays := config.DefaultRunOptIns.UserPolicy.InactiveDays
+	if inactiveDays <= 0 {
+		return
+	}
+
+	now := time.Now()
+	if err := user.FillUsersActiveTime(r.context, now); err != nil {
+		log.Error(err)
+		return
+	}
+
+	users, err := user.ListInactiveUsers(r.context, now.AddDate(0, 0, -inactiveDays))
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	if len(users) == 0 {
+		return
+	}
+
+	var ids []int
+	for _, u := range users {
+		ids = append(ids, u.ID)
+		log.Infof("user[%s] has been inactive since %s, disable it", u.Email, u.LastActiveTime.Format("2006-01-02 15:04:05"))
+	}
+
+	if err := user.UpdateUsersDisabled(r.context, ids, true, user.DisabledReasonInactive); err != nil {
+		log.Error(err)
+	}
+}
+
+func (r *InactiveUserRoutine) Start() {
+	localCron := cron.New()
+	if err := localCron.AddFunc("@every 1h", r.DisableInactiveUsers); err != nil {
+		log.Error(err)
+		return
+	}
+
+	localCron.Start()
+	r.localCron = localCron
+
+	select {
+	case <-r.context.Done():
+		log.Info("Receive stop signal")
+	}
+}
@@ -38,6 +38,8 @@ var (
 
 type UserRole string
 
+const activityRecordInterval = time.Minute
+
 const (
 	NormalRole = UserRole("normal")
 	AdminRole  = UserRole("admin")
@@ -56,6 +58,9 @@ func Init() {
 	if err != nil {
 		log.Error(err)
 	}
+
+	ir := InactiveUserRoutine{context: ctx}
+	go ir.Start()
 }
 
 type UserService struct{}
@@ -85,7 +90,13 @@ func (a *UserService) Login(ctx context.Context, name, password string) (string,
 	if err := user.GetUser(ctx, &userGet); err != nil {
 		return "", "", fmt.Errorf("user not registered")
 	}
-	if userGet.Disabled || userGet.IsDeleted {
+	if userGet.IsDeleted {
+		return "", "", errors.ErrUnauthorized()
+	}
+	if userGet.Disabled {
+		if userGet.DisabledReason == user.DisabledReasonInactive {
+			return "", "", errors.ErrUnauthorized().WithMessage("user is disabled due to inactivity, please contact admin to re-activate")
+		}
 		return "", "", errors.ErrUnauthorized()
 	}
 	if !VerifyPassword(password, userGet.Password) {
@@ -93,6 +104,7 @@ func (a *UserService) Login(ctx context.Context, name, password string) (string,
 	}
 
 	userGet.LastLoginTime = time.Now()
+	userGet.LastActiveTime = userGet.LastLoginTime
 	if err := user.UpdateUser(ctx, &userGet); err != nil {
 		return "", "", err
 	}
@@ -174,11 +186,12 @@ func (a *UserService) Create(ctx context.Context, name, password, role string) (
 	}
 
 	userCreate := user.User{
-		Email:     name,
-		Password:  hash,
-		Role:      role,
-		Disabled:  false,
-		IsDeleted: false,
+		Email:          name,
+		Password:       hash,
+		Role:           role,
+		Disabled:       false,
+		IsDeleted:      false,
+		LastActiveTime: time.Now(),
 	}
 
 	_, err = user.InsertUser(ctx, &userCreate)
@@ -323,6 +336,60 @@ func (a *UserService) UpdateListNamespace(ctx context.Context, name string, ids
 	return user.UpdateUsersRole(ctx, ids, role)
 }
 
+func (a *UserService) ActivateList(ctx context.Context, name string, ids []int) error {
+	if !a.IsAdmin(ctx, name) {
+		return fmt.Errorf("not admin")
+	}
+
+	return user.UpdateUsersDisabled(ctx, ids, false, "")
+}
+
+func (a *UserService) DisableList(ctx context.Context, name string, ids []int) error {
+	if !a.IsAdmin(ctx, name) {
+		return fmt.Errorf("not admin")
+	}
+	userGet := user.User{Email: name}
+	if err := user.GetUser(ctx, &userGet); err != nil {
+		return err
+	}
+	for _, id := range ids {
+		if id == userGet.ID {
+			return fmt.Errorf("admin cannot disable own account")
+		}
+	}
+
+	return user.UpdateUsersDisabled(ctx, ids, true, "")
+}
+
+// RecordActivity refreshes the user's last activity time, at most once per activityRecordInterval, and rejects disabled users
+func (a *UserService) RecordActivity(ctx context.Context, name string) error {
+	userGet, err := a.Get(ctx, name)
+	if err != nil {
+		return errors.ErrUnauthorized()
+	}
+	if userGet.Disabled || userGet.IsDeleted {
+		return errors.ErrUnauthorized()
+	}
+
+	now := time.Now()
+	if now.Sub(userGet.LastActiveTime) < activityRecordInterval {
+		return nil
+	}
+	return user.UpdateUserActiveTime(ctx, userGet.ID, now)
+}
+
+func (a *UserService) GetActivityList(ctx context.Context, name, userName string, inactiveDays int, disabled *bool, orderBy string, page, pageSize int) (int64, []user.User, error) {
+	if !a.IsAdmin(ctx, name) {
+		return 0, nil, fmt.Errorf("not admin")
+	}
+
+	var inactiveBefore time.Time
+	if inactiveDays > 0 {
+		inactiveBefore = time.Now().AddDate(0, 0, -inactiveDays)
+	}
+	return user.QueryUserActivity(ctx, userName, inactiveBefore, disabled, orderBy, page, pageSize)
+}
+
 func (a *UserService) CheckToken(ctx context.Context, token string) (string, error) {
 	if token == "" {
 		return "", errors.ErrUnauthorized()
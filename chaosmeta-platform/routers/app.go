@@ -0,0 +1,31 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package routers
+
+import (
+	"chaosmeta-platform/pkg/gateway/apiserver/v1alpha1/app"
+	beego "github.com/beego/beego/v2/server/web"
+)
+
+func appInit() {
+	beego.Router(NewWebServicePath("apps/list"), &app.AppController{}, "get:GetList")
+	beego.Router(NewWebServicePath("apps/discovery/list"), &app.AppController{}, "get:ListDiscovered")
+	beego.Router(NewWebServicePath("apps/discovery/scan"), &app.AppController{}, "post:Scan")
+	beego.Router(NewWebServicePath("apps/discovery/adopt"), &app.AppController{}, "post:Adopt")
+	beego.Router(NewWebServicePath("apps/:id"), &app.AppController{}, "get:Get")
+	beego.Router(NewWebServicePath("apps/:id"), &app.AppController{}, "delete:Delete")
+}
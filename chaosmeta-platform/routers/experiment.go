@@ -22,12 +22,16 @@ import (
 )
 
 func experimentInit() {
+	beego.Router(NewWebServicePath("experiments/lint"), &experiment.ExperimentController{}, "post:LintExperiment")
 	beego.Router(NewWebServicePath("experiments"), &experiment.ExperimentController{}, "get:GetExperimentList")
 	beego.Router(NewWebServicePath("experiments/:uuid"), &experiment.ExperimentController{}, "get:GetExperimentDetail")
 	beego.Router(NewWebServicePath("experiments"), &experiment.ExperimentController{}, "post:CreateExperiment")
 	beego.Router(NewWebServicePath("experiments/:uuid"), &experiment.ExperimentController{}, "post:UpdateExperiment")
 	beego.Router(NewWebServicePath("experiments/:uuid"), &experiment.ExperimentController{}, "delete:DeleteExperiment")
 
+	beego.Router(NewWebServicePath("experiments/:uuid/lint"), &experiment.ExperimentController{}, "get:LintExistingExperiment")
 	beego.Router(NewWebServicePath("experiments/:uuid/start"), &experiment.ExperimentController{}, "post:StartExperiment")
 	beego.Router(NewWebServicePath("experiments/:uuid/stop"), &experiment.ExperimentController{}, "post:StopExperiment")
+	beego.Router(NewWebServicePath("experiments/:uuid/edit-session"), &experiment.ExperimentController{}, "post:KeepEditSession")
+	beego.Router(NewWebServicePath("experiments/:uuid/edit-session"), &experiment.ExperimentController{}, "delete:EndEditSession")
 }
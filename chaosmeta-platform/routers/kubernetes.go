@@ -33,4 +33,5 @@ func kubernetesInit() {
 	beego.Router(NewWebServicePath("kubernetes/cluster/:id/namespaces"), &kube.KubeController{}, "get:ListNamespaces")
 	beego.Router(NewWebServicePath("kubernetes/cluster/:id/namespace/:ns_name/pods"), &kube.KubeController{}, "get:ListPods")
 	beego.Router(NewWebServicePath("kubernetes/cluster/:id/namespace/:ns_name/deployments"), &kube.KubeController{}, "get:ListDeployments")
+	beego.Router(NewWebServicePath("kubernetes/cluster/:id/target/preview"), &kube.KubeController{}, "post:PreviewTargets")
 }
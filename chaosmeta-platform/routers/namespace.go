@@ -26,6 +26,16 @@ func nameSpaceInit() {
 	beego.Router(NewWebServicePath("namespaces/:id"), &namespace.NamespaceController{}, "get:Get")
 	beego.Router(NewWebServicePath("namespaces/:id/permission"), &namespace.NamespaceController{}, "get:GetPermission")
 	beego.Router(NewWebServicePath("namespaces/:id/overview"), &namespace.NamespaceController{}, "get:GetOverview")
+	beego.Router(NewWebServicePath("namespaces/:id/budget"), &namespace.NamespaceController{}, "get:GetBudget")
+	beego.Router(NewWebServicePath("namespaces/:id/budget"), &namespace.NamespaceController{}, "post:SetBudget")
+	beego.Router(NewWebServicePath("namespaces/:id/budget"), &namespace.NamespaceController{}, "delete:DeleteBudget")
+	beego.Router(NewWebServicePath("namespaces/:id/budget/consumption"), &namespace.NamespaceController{}, "get:GetConsumption")
+	beego.Router(NewWebServicePath("namespaces/budget/consumption"), &namespace.NamespaceController{}, "get:ListConsumption")
+	beego.Router(NewWebServicePath("namespaces/mirrors"), &namespace.NamespaceController{}, "get:ListMirrors")
+	beego.Router(NewWebServicePath("namespaces/mirrors/sync"), &namespace.NamespaceController{}, "post:SyncMirrors")
+	beego.Router(NewWebServicePath("namespaces/sandboxes"), &namespace.NamespaceController{}, "post:CreateSandbox")
+	beego.Router(NewWebServicePath("namespaces/sandboxes"), &namespace.NamespaceController{}, "get:ListSandboxes")
+	beego.Router(NewWebServicePath("namespaces/:id/sandbox"), &namespace.NamespaceController{}, "delete:TeardownSandbox")
 	beego.Router(NewWebServicePath("namespaces/list"), &namespace.NamespaceController{}, "get:GetList")
 	beego.Router(NewWebServicePath("namespaces/query"), &namespace.NamespaceController{}, "get:QueryList")
 	beego.Router(NewWebServicePath("namespaces/:id"), &namespace.NamespaceController{}, "post:Update")
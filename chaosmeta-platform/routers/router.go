@@ -37,6 +37,10 @@ func routerInit() {
 	injectInit()
 	experimentInit()
 	experimentInstanceInit()
+	appInit()
+	scenarioInit()
+	hostInit()
+	triggerInit()
 }
 
 func Init() {
@@ -60,6 +64,11 @@ func CheckTokenMiddleware(ctx *beecontext.Context) {
 		ctx.Output.JSON(errors.ErrUnauthorized().WithMessage(err.Error()), false, false)
 		return
 	}
+	if err := a.RecordActivity(context.Background(), userName); err != nil {
+		log.Error(err)
+		ctx.Output.JSON(errors.ErrUnauthorized().WithMessage(err.Error()), false, false)
+		return
+	}
 	ctx.Input.SetData("userName", userName)
 }
 
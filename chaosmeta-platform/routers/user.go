@@ -26,6 +26,9 @@ func userInit() {
 	beego.Router("/users/token/login", &user.UserController{}, "post:Login")
 	beego.Router("/users/token/refresh", &user.UserController{}, "post:RefreshToken")
 	beego.Router(NewWebServicePath("users/list"), &user.UserController{}, "get:GetList")
+	beego.Router(NewWebServicePath("users/activity/list"), &user.UserController{}, "get:GetActivityList")
+	beego.Router(NewWebServicePath("users/activate"), &user.UserController{}, "post:ActivateList")
+	beego.Router(NewWebServicePath("users/disable"), &user.UserController{}, "post:DisableList")
 	beego.Router(NewWebServicePath("users/namespace/list"), &user.UserController{}, "get:GetNamespaceList")
 	beego.Router(NewWebServicePath("users/namespace/:id/user_list"), &user.UserController{}, "get:GetListWithNamespaceInfo")
 	beego.Router(NewWebServicePath("users/:name"), &user.UserController{}, "get:Get")
@@ -21,6 +21,7 @@ import (
 	"crypto/cipher"
 	"crypto/rand"
 	"encoding/base64"
+	"errors"
 	"io"
 )
 
@@ -53,6 +54,10 @@ func Decrypt(data []byte, key []byte) ([]byte, error) {
 		return nil, err
 	}
 
+	if len(cipherText) < aes.BlockSize {
+		return nil, errors.New("cipher text is too short")
+	}
+
 	iv := cipherText[:aes.BlockSize]
 	cipherText = cipherText[aes.BlockSize:]
 
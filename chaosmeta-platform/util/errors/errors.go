@@ -26,6 +26,7 @@ var _ Error = (*Err)(nil)
 type Error interface {
 	WithMessage(msg string) Error
 	WithData(data interface{}) Error
+	WithErrorInfo(info *ErrorInfo) Error
 	GetErrorCode() int
 	GetErrorMessage() string
 	ToString() string
@@ -41,6 +42,8 @@ type BaseErr struct {
 	Message  string `json:"message"`            // 错误描述
 	TraceId  string `json:"trace_id"`           // traceID
 	ShowType int    `json:"showType,omitempty"` // 错误信息展示方式： 0 静默处理; 1 警告; 2 错误; 4 通知; 9 跳转错误页
+	// Error 结构化的错误原因，code 字段保持兼容
+	ErrorInfo *ErrorInfo `json:"error,omitempty"`
 }
 
 type Err struct {
@@ -77,6 +80,11 @@ func (e *Err) WithData(data interface{}) Error {
 	return e
 }
 
+func (e *Err) WithErrorInfo(info *ErrorInfo) Error {
+	e.ErrorInfo = info
+	return e
+}
+
 func (e *Err) WithMessage(msg string) Error {
 	e.Message = msg
 	return e
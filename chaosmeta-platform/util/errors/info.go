@@ -0,0 +1,116 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package errors
+
+import (
+	"errors"
+	"github.com/beego/beego/v2/client/orm"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+const ComponentPlatform = "platform"
+
+// error categories shared by chaosmetad, the inject operator and the platform
+const (
+	CategoryInvalidArgument  = "InvalidArgument"
+	CategoryNotFound         = "NotFound"
+	CategoryPermissionDenied = "PermissionDenied"
+	CategoryUnsupported      = "Unsupported"
+	CategoryUnavailable      = "Unavailable"
+	CategoryTimeout          = "Timeout"
+	CategoryExecutionFailed  = "ExecutionFailed"
+	CategoryInternal         = "Internal"
+	CategoryUnknown          = "Unknown"
+)
+
+// error codes raised by the platform itself, the errors of chaosmetad and the operator come with their info in the
+// status of the experiment
+const (
+	CodeNotFound         = "NotFound"
+	CodePermissionDenied = "PermissionDenied"
+	CodeInternal         = "InternalError"
+)
+
+// ErrPermissionDenied is returned by the services when the user has no permission on the resource
+var ErrPermissionDenied = errors.New("permission denied")
+
+// errorInfoMap maps a code to its category in the same way as the catalog in pkg/common/errcode.go of the operator
+var errorInfoMap = map[string]ErrorInfo{
+	CodeNotFound:         {Category: CategoryNotFound},
+	CodePermissionDenied: {Category: CategoryPermissionDenied},
+	CodeInternal:         {Category: CategoryInternal},
+}
+
+// ErrorInfo is the machine-readable cause of an error, so that the UI and automation do not need to parse the message
+type ErrorInfo struct {
+	Code      string `json:"code" yaml:"code"`
+	Category  string `json:"category" yaml:"category"`
+	Component string `json:"component" yaml:"component"`
+	Retryable bool   `json:"retryable,omitempty" yaml:"retryable,omitempty"`
+}
+
+// CodedError carries an ErrorInfo from the service layer to the api response
+type CodedError struct {
+	Info ErrorInfo
+	Err  error
+}
+
+func (e *CodedError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *CodedError) Unwrap() error {
+	return e.Err
+}
+
+// NewCodedError attaches the info of code to err, an unknown code is treated as an internal error
+func NewCodedError(code string, err error) error {
+	return &CodedError{Info: *GetCodeErrorInfo(code), Err: err}
+}
+
+// GetCodeErrorInfo looks up the category of code in the catalog
+func GetCodeErrorInfo(code string) *ErrorInfo {
+	info, ok := errorInfoMap[code]
+	if !ok {
+		code, info = CodeInternal, errorInfoMap[CodeInternal]
+	}
+	info.Code, info.Component = code, ComponentPlatform
+	return &info
+}
+
+// GetErrorInfo returns the info carried by err, or classifies err by the typed errors of the services, the orm and
+// the kubernetes api
+func GetErrorInfo(err error) *ErrorInfo {
+	if err == nil {
+		return nil
+	}
+
+	var codedErr *CodedError
+	if errors.As(err, &codedErr) {
+		info := codedErr.Info
+		return &info
+	}
+
+	switch {
+	case errors.Is(err, orm.ErrNoRows) || apierrors.IsNotFound(err):
+		return GetCodeErrorInfo(CodeNotFound)
+	case errors.Is(err, ErrPermissionDenied) || apierrors.IsForbidden(err):
+		return GetCodeErrorInfo(CodePermissionDenied)
+	default:
+		return GetCodeErrorInfo(CodeInternal)
+	}
+}
@@ -0,0 +1,56 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"github.com/beego/beego/v2/client/orm"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"testing"
+)
+
+func TestGetErrorInfo(t *testing.T) {
+	pods := schema.GroupResource{Resource: "pods"}
+	tests := []struct {
+		name         string
+		err          error
+		wantCode     string
+		wantCategory string
+	}{
+		{name: "orm no rows", err: fmt.Errorf("get user error: %w", orm.ErrNoRows), wantCode: CodeNotFound, wantCategory: CategoryNotFound},
+		{name: "kubernetes not found", err: apierrors.NewNotFound(pods, "a"), wantCode: CodeNotFound, wantCategory: CategoryNotFound},
+		{name: "permission denied", err: ErrPermissionDenied, wantCode: CodePermissionDenied, wantCategory: CategoryPermissionDenied},
+		{name: "kubernetes forbidden", err: apierrors.NewForbidden(pods, "a", errors.New("rbac")), wantCode: CodePermissionDenied, wantCategory: CategoryPermissionDenied},
+		{name: "message is not classified", err: errors.New("user not found"), wantCode: CodeInternal, wantCategory: CategoryInternal},
+		{name: "coded error", err: fmt.Errorf("wrap: %w", NewCodedError(CodePermissionDenied, errors.New("x"))), wantCode: CodePermissionDenied, wantCategory: CategoryPermissionDenied},
+		{name: "unknown code", err: NewCodedError("Unknown", errors.New("x")), wantCode: CodeInternal, wantCategory: CategoryInternal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info := GetErrorInfo(tt.err)
+			if info.Code != tt.wantCode || info.Category != tt.wantCategory || info.Component != ComponentPlatform {
+				t.Errorf("GetErrorInfo() = %+v, want code %s and category %s", info, tt.wantCode, tt.wantCategory)
+			}
+		})
+	}
+
+	if GetErrorInfo(nil) != nil {
+		t.Errorf("GetErrorInfo(nil) should be nil")
+	}
+}
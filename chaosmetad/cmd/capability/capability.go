@@ -0,0 +1,60 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package capability
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/doctor"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/errutil"
+)
+
+func NewCapabilityCommand() *cobra.Command {
+	var cr, containerId string
+	cmd := &cobra.Command{
+		Use:   "capability [target] [fault]",
+		Short: "report the health of chaosmetad and the capabilities of the host as json, only the faults of target and fault are reported if provided",
+		Args:  cobra.MaximumNArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx := utils.GetCtxWithTraceId(context.Background(), utils.TraceId)
+			var target, fault string
+			if len(args) > 0 {
+				target = args[0]
+			}
+			if len(args) > 1 {
+				fault = args[1]
+			}
+
+			report := doctor.GetReport(ctx)
+			report.FilterFaults(target, fault)
+			report.CheckContainer(ctx, cr, containerId)
+			reBytes, _ := json.Marshal(report)
+			fmt.Println(string(reBytes))
+
+			if !report.Health.Healthy {
+				errutil.SolveErr(ctx, errutil.InternalErr, "chaosmetad is unhealthy")
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&cr, "container-runtime", "", "check whether the container is running if provided, support: docker, pouch, containerd")
+	cmd.Flags().StringVar(&containerId, "container-id", "", "the container to check, used with \"container-runtime\"")
+	return cmd
+}
@@ -0,0 +1,36 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package doctor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/doctor"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+)
+
+func NewDoctorCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "doctor",
+		Short: "report kernel, cgroup mode, available tools, container runtimes and usable faults of the host as json",
+		Run: func(cmd *cobra.Command, args []string) {
+			reBytes, _ := json.Marshal(doctor.GetReport(utils.GetCtxWithTraceId(context.Background(), utils.TraceId)))
+			fmt.Println(string(reBytes))
+		},
+	}
+}
@@ -25,11 +25,18 @@ import (
 	_ "github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector/diskio"
 	_ "github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector/dns"
 	_ "github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector/file"
+	_ "github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector/grpc"
+	_ "github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector/host"
+	_ "github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector/http"
 	_ "github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector/jvm"
 	_ "github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector/kernel"
 	_ "github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector/mem"
+	_ "github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector/middleware"
 	_ "github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector/network"
 	_ "github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector/process"
+	_ "github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector/scenario"
+	_ "github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector/systemd"
+	_ "github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector/time"
 )
 
 // NewInjectCommand injectCmd represents the inject command
@@ -43,12 +50,15 @@ func NewInjectCommand() *cobra.Command {
 
 	var args = &injector.BaseInfo{}
 	injectCmd.PersistentFlags().StringVarP(&args.Timeout, "timeout", "t", "", "experiment's duration, support unit: \"s、m、h\"(default s)")
+	injectCmd.PersistentFlags().StringVar(&args.Pulse, "pulse", "", "pulse mode, the fault is active and idle in turn until timeout, format: [active],[idle], eg: 10s,20s")
 	injectCmd.PersistentFlags().StringVar(&args.Creator, "creator", "", "experiment's creator（default the cmd exec user）")
 
 	injectCmd.PersistentFlags().StringVar(&args.ContainerRuntime, "container-runtime", "", "if attack a container of local host, need to provide the container runtime of target container")
 	injectCmd.PersistentFlags().StringVar(&args.ContainerId, "container-id", "", "if attack a container of local host, need to provide the container id of target container")
 
 	injectCmd.PersistentFlags().StringVar(&args.Uid, "uid", "", "if not provide, it will automatically generate an uid")
+	injectCmd.PersistentFlags().StringVar(&args.PreHook, "pre-hook", "", "bash command executed on the host before inject, such as a health check, the experiment fails if it fails. Only allowed with env CHAOSMETAD_ENABLE_HOOKS=true")
+	injectCmd.PersistentFlags().StringVar(&args.PostHook, "post-hook", "", "bash command executed on the host after recover, such as a cache warm. Only allowed with env CHAOSMETAD_ENABLE_HOOKS=true")
 	//var args = make([]string, 2)
 	//injectCmd.PersistentFlags().StringVarP(&args[0], "timeout", "t", "", "experiment's duration（default 0, means need to stop manually）")
 	//injectCmd.PersistentFlags().StringVar(&args[1], "creator", "", "experiment's creator（default the cmd exec user）")
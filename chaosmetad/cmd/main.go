@@ -17,14 +17,24 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"github.com/spf13/cobra"
+	"github.com/traas-stack/chaosmeta/chaosmetad/cmd/capability"
+	"github.com/traas-stack/chaosmeta/chaosmetad/cmd/doctor"
 	"github.com/traas-stack/chaosmeta/chaosmetad/cmd/inject"
+	"github.com/traas-stack/chaosmeta/chaosmetad/cmd/pause"
+	"github.com/traas-stack/chaosmeta/chaosmetad/cmd/pulse"
 	"github.com/traas-stack/chaosmeta/chaosmetad/cmd/query"
 	"github.com/traas-stack/chaosmeta/chaosmetad/cmd/recover"
+	"github.com/traas-stack/chaosmeta/chaosmetad/cmd/resume"
+	"github.com/traas-stack/chaosmeta/chaosmetad/cmd/scenario"
 	"github.com/traas-stack/chaosmeta/chaosmetad/cmd/server"
+	"github.com/traas-stack/chaosmeta/chaosmetad/cmd/snapshot"
+	"github.com/traas-stack/chaosmeta/chaosmetad/cmd/upgrade"
 	"github.com/traas-stack/chaosmeta/chaosmetad/cmd/version"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/log"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/remote"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/errutil"
 	"os"
@@ -40,12 +50,43 @@ func initRootCmd() {
 	rootCmd.PersistentFlags().StringVar(&log.Level, "log-level", "info", "value support: debug, info, warn, error")
 	rootCmd.PersistentFlags().StringVar(&log.Path, "log-path", "", "log file's path, eg: /tmp/chaosmetad.log")
 	rootCmd.PersistentFlags().StringVar(&utils.TraceId, "trace-id", "", "trace id")
+	rootCmd.PersistentFlags().StringVar(&remote.Addr, remote.FlagRemote, "", "execute the command on a remote host without agent by ssh, the bundle is copied if changed, eg: root@10.0.0.1:22")
+	rootCmd.PersistentFlags().StringVar(&remote.Key, remote.FlagSSHKey, "", "private key file of ssh for the remote host(default the key of ssh client config)")
+	rootCmd.PersistentFlags().StringVar(&remote.Dir, remote.FlagRemoteDir, remote.DefaultRemoteDir, "dir of the bundle on the remote host")
+	rootCmd.PersistentPreRun = runRemote
 
 	rootCmd.AddCommand(inject.NewInjectCommand())
 	rootCmd.AddCommand(query.NewQueryCommand())
 	rootCmd.AddCommand(recover.NewRecoverCommand())
+	rootCmd.AddCommand(pause.NewPauseCommand())
+	rootCmd.AddCommand(resume.NewResumeCommand())
+	rootCmd.AddCommand(pulse.NewPulseCommand())
+	rootCmd.AddCommand(scenario.NewScenarioCommand())
 	rootCmd.AddCommand(server.NewServerCommand())
 	rootCmd.AddCommand(version.NewVersionCommand())
+	rootCmd.AddCommand(doctor.NewDoctorCommand())
+	rootCmd.AddCommand(capability.NewCapabilityCommand())
+	rootCmd.AddCommand(upgrade.NewUpgradeCommand())
+	rootCmd.AddCommand(snapshot.NewSnapshotCommand())
+}
+
+// runRemote executes the command on the remote host instead, and exits with its exit code
+func runRemote(cmd *cobra.Command, args []string) {
+	if remote.Addr == "" {
+		return
+	}
+
+	ctx := utils.GetCtxWithTraceId(context.Background(), utils.TraceId)
+	if cmd.Name() == "server" {
+		errutil.SolveErr(ctx, errutil.BadArgsErr, fmt.Sprintf("\"%s\" is not supported by server", remote.FlagRemote))
+	}
+
+	code, err := remote.Run(ctx, remote.StripFlags(os.Args[1:]))
+	if err != nil {
+		errutil.SolveErr(ctx, errutil.InternalErr, fmt.Sprintf("remote exec error: %s", err.Error()))
+	}
+
+	os.Exit(code)
 }
 
 func main() {
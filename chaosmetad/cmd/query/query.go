@@ -41,14 +41,35 @@ func NewQueryCommand() *cobra.Command {
 	}
 
 	queryCmd.Flags().StringVarP(&optionQuery.Uid, "uid", "u", "", "query experiment by uid, eg: chaosmetad query -u [uid]")
-	queryCmd.Flags().StringVarP(&optionQuery.Status, "status", "s", "", "query experiment by status, eg: chaosmetad query -s success")
+	queryCmd.Flags().StringVarP(&optionQuery.Status, "status", "s", "", "query experiment by status, multiple statuses are joined by \",\", eg: chaosmetad query -s success,idle")
 	queryCmd.Flags().StringVarP(&optionQuery.Target, "target", "t", "", "query experiment by target, eg: chaosmetad query -t cpu")
 	queryCmd.Flags().StringVarP(&optionQuery.Fault, "fault", "f", "", "query experiment by target and fault, eg: chaosmetad query -t cpu -f burn")
 	queryCmd.Flags().StringVarP(&optionQuery.Creator, "creator", "c", "", "query experiment by creator, eg: chaosmetad query -c root")
+	queryCmd.Flags().StringVar(&optionQuery.StartTime, "start-time", "", "query experiment created not before the time, eg: chaosmetad query --start-time \"2023-01-01 00:00:00\"")
+	queryCmd.Flags().StringVar(&optionQuery.EndTime, "end-time", "", "query experiment created not after the time, eg: chaosmetad query --end-time \"2023-01-02 00:00:00\"")
 	queryCmd.Flags().UintVarP(&optionQuery.Offset, "offset", "o", 0, "query experiment records with offset, eg: chaosmetad query -o 5")
 	queryCmd.Flags().UintVarP(&optionQuery.Limit, "limit", "l", 10, "query experiment records with limit, eg: chaosmetad query -o 5 -l 5")
 	queryCmd.Flags().BoolVarP(&ifAll, "all", "a", false, "if show all")
 	queryCmd.Flags().StringVar(&format, "format", query.TableFormat, fmt.Sprintf("data show format, support: %s(default), %s", query.TableFormat, query.JsonFormat))
 
+	queryCmd.AddCommand(newAuditCommand())
+
 	return queryCmd
 }
+
+func newAuditCommand() *cobra.Command {
+	var format string
+
+	auditCmd := &cobra.Command{
+		Use:   "audit [uid]",
+		Short: "dump the executed system commands of an experiment, eg: chaosmetad query audit [uid]",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			query.PrintAuditByUid(utils.GetCtxWithTraceId(context.Background(), utils.TraceId), args[0], format)
+		},
+	}
+
+	auditCmd.Flags().StringVar(&format, "format", query.TableFormat, fmt.Sprintf("data show format, support: %s(default), %s", query.TableFormat, query.JsonFormat))
+
+	return auditCmd
+}
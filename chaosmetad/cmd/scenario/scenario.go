@@ -0,0 +1,62 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package scenario
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector/scenario"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/errutil"
+	"strconv"
+)
+
+// NewScenarioCommand scenarioCmd is started in background by a scenario to inject the fault with offset, not for manual use
+func NewScenarioCommand() *cobra.Command {
+	scenarioCmd := &cobra.Command{
+		Use:    "scenario",
+		Short:  "scenario fault start command",
+		Long:   "scenario fault start command, usage: scenario [uid] [index]",
+		Hidden: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx := utils.GetCtxWithTraceId(context.Background(), utils.TraceId)
+			uid, index, err := parseArgs(args)
+			if err != nil {
+				errutil.SolveErr(ctx, errutil.BadArgsErr, err.Error())
+			}
+
+			code, msg := scenario.ProcessStart(ctx, uid, index)
+			errutil.SolveErr(ctx, code, msg)
+		},
+	}
+
+	return scenarioCmd
+}
+
+func parseArgs(args []string) (uid string, index int, err error) {
+	if len(args) != 2 {
+		return "", 0, fmt.Errorf("please add scenario's uid and fault index, eg: scenario [uid] [index]")
+	}
+
+	index, err = strconv.Atoi(args[1])
+	if err != nil {
+		return "", 0, fmt.Errorf("index[%s] is not a num", args[1])
+	}
+
+	return args[0], index, nil
+}
@@ -0,0 +1,46 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package scenario
+
+import (
+	"testing"
+)
+
+func Test_parseArgs(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      []string
+		wantUid   string
+		wantIndex int
+		wantErr   bool
+	}{
+		{name: "valid", args: []string{"a1b2c3d4", "2"}, wantUid: "a1b2c3d4", wantIndex: 2},
+		{name: "missing index", args: []string{"a1b2c3d4"}, wantErr: true},
+		{name: "too many args", args: []string{"a1b2c3d4", "1", "2"}, wantErr: true},
+		{name: "index not a num", args: []string{"a1b2c3d4", "x"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uid, index, err := parseArgs(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseArgs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if uid != tt.wantUid || index != tt.wantIndex {
+				t.Errorf("parseArgs() = %s, %d, want %s, %d", uid, index, tt.wantUid, tt.wantIndex)
+			}
+		})
+	}
+}
@@ -19,7 +19,9 @@ package server
 import (
 	"context"
 	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/config"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/log"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/rpc"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/errutil"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/process"
@@ -52,15 +54,34 @@ func watchSignal(ctx context.Context) {
 
 // NewServerCommand serverCmd represents the server command
 func NewServerCommand() *cobra.Command {
-	var addr, port string
+	var addr, port, grpcPort string
 	//var cert, key string
-	var isPprof bool
+	var isPprof, disableHTTP bool
 	cmd := &cobra.Command{
 		Use:   "server",
 		Short: "start up daemon service",
 		Run: func(cmd *cobra.Command, args []string) {
 			ctx := utils.GetCtxWithTraceId(context.Background(), "system")
 			go watchSignal(ctx)
+			go process.StartExecnsJanitor(ctx, process.JanitorInterval)
+
+			if c, err := config.Get(); err != nil {
+				log.GetLogger(ctx).Warnf("load runtime config error: %s", err.Error())
+			} else {
+				config.Apply(ctx, c)
+			}
+
+			if disableHTTP {
+				if grpcPort == "" {
+					log.GetLogger(ctx).Fatalf("grpc port is required when the http service is disabled")
+				}
+				startGRPCService(ctx, addr, grpcPort)
+				return
+			}
+
+			if grpcPort != "" {
+				go startGRPCService(ctx, addr, grpcPort)
+			}
 
 			//if cert != "" && key != "" {
 			//	startHTTPSServer(addr, port, isPprof, cert, key)
@@ -72,6 +93,9 @@ func NewServerCommand() *cobra.Command {
 
 	cmd.Flags().StringVarP(&addr, "addr", "a", "0.0.0.0", "service bind addr")
 	cmd.Flags().StringVarP(&port, "port", "p", "29595", "service bind port")
+	cmd.Flags().StringVar(&grpcPort, "grpc-port", "", fmt.Sprintf("grpc service bind port, the grpc service is disabled if empty. The token of callers is required by env %s, "+
+		"and the mutual TLS certificate, key and client CA by env %s, %s and %s", rpc.TokenEnv, rpc.TLSCertEnv, rpc.TLSKeyEnv, rpc.TLSClientCAEnv))
+	cmd.Flags().BoolVar(&disableHTTP, "disable-http", false, "only start the grpc service")
 	cmd.Flags().BoolVar(&isPprof, "enable-pprof", true, "if open pprof service")
 	//cmd.Flags().StringVarP(&cert, "cert", "c", "", "path to certificate file")
 	//cmd.Flags().StringVarP(&key, "key", "k", "", "path to private key file")
@@ -92,6 +116,17 @@ func startHTTPService(ctx context.Context, addr string, port string, isPprof boo
 	}
 }
 
+func startGRPCService(ctx context.Context, addr string, port string) {
+	tlsConfig, err := rpc.NewTLSConfig(os.Getenv(rpc.TLSCertEnv), os.Getenv(rpc.TLSKeyEnv), os.Getenv(rpc.TLSClientCAEnv))
+	if err != nil {
+		log.GetLogger(ctx).Fatalf("load tls config of grpc service fail: %s", err.Error())
+	}
+
+	if err := rpc.Serve(ctx, addr, port, os.Getenv(rpc.TokenEnv), tlsConfig); err != nil {
+		log.GetLogger(ctx).Fatalf("start grpc service fail: %s", err.Error())
+	}
+}
+
 //func startHTTPSServer(addr string, port string, isPprof bool, cert, key string) {
 //	logger := tools.GetLogger()
 //	logger.Infof("HTTPS Service Listen on %s:%s, pprof: %t, cert: %s, key: %s", addr, port, isPprof, cert, key)
@@ -0,0 +1,42 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package snapshot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/snapshot"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+)
+
+func NewSnapshotCommand() *cobra.Command {
+	var cr, cId string
+	cmd := &cobra.Command{
+		Use:   "snapshot",
+		Short: "report top processes, load, memory and tcp connections of the host or a container as json",
+		Run: func(cmd *cobra.Command, args []string) {
+			reBytes, _ := json.Marshal(snapshot.Take(utils.GetCtxWithTraceId(context.Background(), utils.TraceId), cr, cId))
+			fmt.Println(string(reBytes))
+		},
+	}
+
+	cmd.Flags().StringVar(&cr, "container-runtime", "", "container runtime of the target container, empty means the host")
+	cmd.Flags().StringVar(&cId, "container-id", "", "container id of the target container")
+	return cmd
+}
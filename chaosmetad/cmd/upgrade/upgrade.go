@@ -0,0 +1,51 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package upgrade
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/upgrade"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/errutil"
+)
+
+func NewUpgradeCommand() *cobra.Command {
+	var opt upgrade.Options
+	cmd := &cobra.Command{
+		Use:   "upgrade",
+		Short: "upgrade chaosmetad to a signed release and restart the daemon, the experiments in progress are preserved",
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx := utils.GetCtxWithTraceId(context.Background(), utils.TraceId)
+			if err := upgrade.Upgrade(ctx, &opt); err != nil {
+				errutil.SolveErr(ctx, errutil.InternalErr, fmt.Sprintf("upgrade error: %s", err.Error()))
+			}
+
+			errutil.SolveErr(ctx, errutil.NoErr, "upgrade success")
+		},
+	}
+
+	cmd.Flags().StringVarP(&opt.Version, "version", "v", "", "target version of chaosmetad. eg: v0.6.0")
+	cmd.Flags().StringVar(&opt.Registry, "registry", "", "https address of the releases, \"release_registry\" of the runtime config is used if empty")
+	cmd.Flags().StringVar(&opt.PublicKey, "public-key", "", "base64 ed25519 public key to verify the release, the root-only "+upgrade.ReleaseKeyFile+" is used if empty")
+	cmd.Flags().StringVar(&opt.Service, "service", upgrade.DefaultService, "systemd service of the daemon")
+	cmd.Flags().BoolVar(&opt.NoRestart, "no-restart", false, "only replace the binary without restarting the daemon")
+	_ = cmd.MarkFlagRequired("version")
+
+	return cmd
+}
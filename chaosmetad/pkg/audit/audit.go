@@ -0,0 +1,162 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package audit
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+	"os"
+	"path"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	auditFile = "chaosmetad_audit.log"
+
+	ExitCodeUnknown = -1
+)
+
+// internal packages which are skipped when looking for the caller of a command
+var skipCallerPkgs = []string{"/pkg/utils/cmdexec.", "/pkg/audit."}
+
+var mutex sync.Mutex
+
+// Record is one executed system command, appended as a json line to the audit file
+type Record struct {
+	Time     string `json:"time"`
+	Uid      string `json:"uid,omitempty"`
+	TraceId  string `json:"trace_id,omitempty"`
+	Caller   string `json:"caller"`
+	Method   string `json:"method"`
+	Cmd      string `json:"cmd"`
+	Pid      int    `json:"pid,omitempty"`
+	ExitCode int    `json:"exit_code"`
+	Error    string `json:"error,omitempty"`
+}
+
+func GetAuditPath() string {
+	return path.Join(utils.GetRunPath(), auditFile)
+}
+
+// Write appends a command record to the audit file. The file is only ever opened in append mode
+func Write(ctx context.Context, method, cmd string, pid, exitCode int, err error) error {
+	r := &Record{
+		Time:     time.Now().Format(utils.TimeFormat),
+		Uid:      utils.GetUid(ctx),
+		TraceId:  utils.GetTraceId(ctx),
+		Caller:   getCaller(),
+		Method:   method,
+		Cmd:      cmd,
+		Pid:      pid,
+		ExitCode: exitCode,
+	}
+	if err != nil {
+		r.Error = err.Error()
+	}
+
+	line, mErr := json.Marshal(r)
+	if mErr != nil {
+		return fmt.Errorf("marshal audit record error: %s", mErr.Error())
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	f, oErr := os.OpenFile(GetAuditPath(), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if oErr != nil {
+		return fmt.Errorf("open audit file error: %s", oErr.Error())
+	}
+	defer f.Close()
+
+	if _, wErr := f.Write(append(line, '\n')); wErr != nil {
+		return fmt.Errorf("write audit file error: %s", wErr.Error())
+	}
+
+	return nil
+}
+
+// QueryByUid returns the command records of the experiment in the order they were executed
+func QueryByUid(uid string) ([]*Record, error) {
+	f, err := os.Open(GetAuditPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("open audit file error: %s", err.Error())
+	}
+	defer f.Close()
+
+	var records []*Record
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var r Record
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			continue
+		}
+
+		if uid == "" || r.Uid == uid {
+			records = append(records, &r)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read audit file error: %s", err.Error())
+	}
+
+	return records, nil
+}
+
+func getCaller() string {
+	pcs := make([]uintptr, 16)
+	n := runtime.Callers(3, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		if !isSkipCaller(frame.Function) {
+			return fmt.Sprintf("%s:%d", trimModule(frame.Function), frame.Line)
+		}
+
+		if !more {
+			return ""
+		}
+	}
+}
+
+func isSkipCaller(function string) bool {
+	for _, pkg := range skipCallerPkgs {
+		if strings.Contains(function, pkg) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func trimModule(function string) string {
+	index := strings.Index(function, "/pkg/")
+	if index < 0 {
+		return function
+	}
+
+	return function[index+1:]
+}
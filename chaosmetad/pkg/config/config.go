@@ -0,0 +1,313 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/log"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/storage"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+	"net/url"
+	"os"
+	"path"
+	"sync"
+	"time"
+)
+
+const (
+	ConfigFile = "chaosmetad_config.json"
+
+	// EnableHooksEnv set to "true" allows the pre and post hooks of the experiments, which run any bash command as root.
+	// The switches in the env of the daemon can not be changed by the api, unlike the runtime config
+	EnableHooksEnv = "CHAOSMETAD_ENABLE_HOOKS"
+	// EnablePowerFaultsEnv set to "true" allows the faults rebooting, shutting down or panicking the host
+	EnablePowerFaultsEnv = "CHAOSMETAD_ENABLE_POWER_FAULTS"
+)
+
+var (
+	current *RuntimeConfig
+	mutex   sync.Mutex
+)
+
+// RuntimeConfig can be changed by the daemon api without restarting, it is persisted in the run path to be shared with the cli
+type RuntimeConfig struct {
+	// Version only increases, an update with a version not larger than the current one is rejected
+	Version  int64  `json:"version"`
+	LogLevel string `json:"log_level,omitempty"`
+	// EnabledTargets are the fault families allowed to inject, empty means all
+	EnabledTargets []string `json:"enabled_targets,omitempty"`
+	// MaxRunningExperiments limits the experiments in progress at the same time, 0 means no limit
+	MaxRunningExperiments int `json:"max_running_experiments,omitempty"`
+	// MaxInjectPerMinute limits the experiments created in the last minute, 0 means no limit
+	MaxInjectPerMinute int `json:"max_inject_per_minute,omitempty"`
+	// MaxTimeout limits the timeout of each experiment, the experiments without timeout are rejected if set, empty means no limit
+	MaxTimeout string `json:"max_timeout,omitempty"`
+	// ToolCpuLimit is the cpu ceiling of all the fault tool processes in percent of one core, e.g. 200 means 2 cores, 0 means no limit
+	ToolCpuLimit int `json:"tool_cpu_limit,omitempty"`
+	// ToolMemLimit is the memory ceiling of all the fault tool processes, e.g. 2GB, empty means no limit
+	ToolMemLimit string `json:"tool_mem_limit,omitempty"`
+	// ToolRegistry is the https address to fetch the missing tools, eg: https://bucket.oss.example.com/chaosmeta/0.5.0
+	ToolRegistry string `json:"tool_registry,omitempty"`
+	// ReleaseRegistry is the https address of the releases used by upgrade, a release is in "[registry]/[version]/".
+	// The public key verifying the releases is never part of the runtime config, see upgrade.ReleaseKeyFile
+	ReleaseRegistry string `json:"release_registry,omitempty"`
+	// ProtectedHosts are the hostname patterns that the host level destructive faults can never target, eg: "master-*"
+	ProtectedHosts []string `json:"protected_hosts,omitempty"`
+}
+
+func getConfigPath() string {
+	return fmt.Sprintf("%s/%s", utils.GetRunPath(), ConfigFile)
+}
+
+// Get returns the current config, the zero config with version 0 is returned if never set
+func Get() (*RuntimeConfig, error) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	return get()
+}
+
+// get is called with the mutex held
+func get() (*RuntimeConfig, error) {
+	if current != nil {
+		c := *current
+		return &c, nil
+	}
+
+	c := &RuntimeConfig{}
+	data, err := os.ReadFile(getConfigPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return c, nil
+		}
+		return nil, fmt.Errorf("read config file error: %s", err.Error())
+	}
+
+	if err := json.Unmarshal(data, c); err != nil {
+		return nil, fmt.Errorf("config file format error: %s", err.Error())
+	}
+
+	current = c
+	re := *c
+	return &re, nil
+}
+
+func (c *RuntimeConfig) validate() error {
+	if c.LogLevel != "" && c.LogLevel != log.Debug && c.LogLevel != log.Info && c.LogLevel != log.Warn && c.LogLevel != log.Error {
+		return fmt.Errorf("\"log_level\" only support: %s, %s, %s, %s", log.Debug, log.Info, log.Warn, log.Error)
+	}
+
+	if c.MaxRunningExperiments < 0 {
+		return fmt.Errorf("\"max_running_experiments\" must not be less than 0")
+	}
+
+	if c.MaxInjectPerMinute < 0 {
+		return fmt.Errorf("\"max_inject_per_minute\" must not be less than 0")
+	}
+
+	if c.MaxTimeout != "" {
+		if _, err := utils.GetTimeSecond(c.MaxTimeout); err != nil {
+			return fmt.Errorf("\"max_timeout\" is invalid: %s", err.Error())
+		}
+	}
+
+	if c.ToolCpuLimit < 0 {
+		return fmt.Errorf("\"tool_cpu_limit\" must not be less than 0")
+	}
+
+	if c.ToolRegistry != "" {
+		if u, err := url.Parse(c.ToolRegistry); err != nil || u.Scheme != "https" || u.Host == "" {
+			return fmt.Errorf("\"tool_registry\" must be an https address")
+		}
+	}
+
+	if c.ReleaseRegistry != "" {
+		if u, err := url.Parse(c.ReleaseRegistry); err != nil || u.Scheme != "https" || u.Host == "" {
+			return fmt.Errorf("\"release_registry\" must be an https address")
+		}
+	}
+
+	if c.ToolMemLimit != "" {
+		if bytes, err := utils.GetBytes(c.ToolMemLimit); err != nil || bytes <= 0 {
+			return fmt.Errorf("\"tool_mem_limit\" is invalid: %s", c.ToolMemLimit)
+		}
+	}
+
+	for _, p := range c.ProtectedHosts {
+		if _, err := path.Match(p, ""); err != nil {
+			return fmt.Errorf("\"protected_hosts\" has invalid pattern[%s]: %s", p, err.Error())
+		}
+	}
+
+	return nil
+}
+
+// Update replaces the whole config and applies it immediately
+func Update(ctx context.Context, c *RuntimeConfig) error {
+	if err := c.validate(); err != nil {
+		return err
+	}
+
+	// the version is compared and the config is written under the same lock, otherwise two updates with the
+	// same version can both pass the comparison
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	old, err := get()
+	if err != nil {
+		return err
+	}
+
+	if c.Version <= old.Version {
+		return fmt.Errorf("config version %d is not newer than current version %d", c.Version, old.Version)
+	}
+
+	data, err := json.Marshal(c)
+	if err != nil {
+		return fmt.Errorf("config to json error: %s", err.Error())
+	}
+
+	if err := os.WriteFile(getConfigPath(), data, 0600); err != nil {
+		return fmt.Errorf("write config file error: %s", err.Error())
+	}
+
+	re := *c
+	current = &re
+	Apply(ctx, &re)
+	log.GetLogger(ctx).Infof("runtime config is updated from version %d to %d", old.Version, c.Version)
+	return nil
+}
+
+// Apply makes the settings that affect the running process take effect
+func Apply(ctx context.Context, c *RuntimeConfig) {
+	if c.LogLevel != "" {
+		log.SetLevel(c.LogLevel)
+	}
+}
+
+// CheckInject checks whether a new experiment is allowed by the config
+func CheckInject(ctx context.Context, target, timeout string) error {
+	c, err := Get()
+	if err != nil {
+		return err
+	}
+
+	if len(c.EnabledTargets) > 0 && !utils.StrListContain(c.EnabledTargets, target) {
+		return fmt.Errorf("target[%s] is disabled by config of version %d", target, c.Version)
+	}
+
+	if c.MaxTimeout != "" {
+		maxSecond, _ := utils.GetTimeSecond(c.MaxTimeout)
+		if timeout == "" {
+			return fmt.Errorf("timeout is required since the max timeout is %s", c.MaxTimeout)
+		}
+
+		timeoutSecond, err := utils.GetTimeSecond(timeout)
+		if err != nil {
+			return fmt.Errorf("timeout[%s] is invalid: %s", timeout, err.Error())
+		}
+
+		if timeoutSecond > maxSecond {
+			return fmt.Errorf("timeout[%s] exceeds the max timeout %s", timeout, c.MaxTimeout)
+		}
+	}
+
+	return nil
+}
+
+// CheckHooks rejects the experiments with hooks unless they are enabled by the env of the daemon
+func CheckHooks(preHook, postHook string) error {
+	if (preHook != "" || postHook != "") && os.Getenv(EnableHooksEnv) != "true" {
+		return fmt.Errorf("hooks are disabled, set env %s=true for the daemon to enable them", EnableHooksEnv)
+	}
+
+	return nil
+}
+
+// CheckPowerFaults rejects the faults taking the host down unless they are enabled by the env of the daemon
+func CheckPowerFaults(fault string) error {
+	if os.Getenv(EnablePowerFaultsEnv) != "true" {
+		return fmt.Errorf("fault \"%s\" is disabled, set env %s=true for the daemon to enable it", fault, EnablePowerFaultsEnv)
+	}
+
+	return nil
+}
+
+// CheckLimit checks whether the count of experiments reaches the limits of the config, so that an upstream can not
+// stack too many faults on a host
+func CheckLimit(ctx context.Context) error {
+	c, err := Get()
+	if err != nil {
+		return err
+	}
+
+	if c.MaxRunningExperiments > 0 {
+		db, err := storage.GetExperimentStore()
+		if err != nil {
+			return fmt.Errorf("connect db error: %s", err.Error())
+		}
+
+		_, running, err := db.QueryByFilter(&storage.ExperimentFilter{
+			Status: []string{utils.StatusCreated, utils.StatusSuccess, utils.StatusIdle, utils.StatusPaused},
+		})
+		if err != nil {
+			return fmt.Errorf("query running experiments error: %s", err.Error())
+		}
+
+		if running >= int64(c.MaxRunningExperiments) {
+			return fmt.Errorf("running experiments count %d reaches the max %d", running, c.MaxRunningExperiments)
+		}
+	}
+
+	if c.MaxInjectPerMinute > 0 {
+		db, err := storage.GetExperimentStore()
+		if err != nil {
+			return fmt.Errorf("connect db error: %s", err.Error())
+		}
+
+		_, recent, err := db.QueryByFilter(&storage.ExperimentFilter{
+			StartTime: time.Now().Add(-time.Minute).Format(utils.TimeFormat),
+		})
+		if err != nil {
+			return fmt.Errorf("query recent experiments error: %s", err.Error())
+		}
+
+		if recent >= int64(c.MaxInjectPerMinute) {
+			return fmt.Errorf("experiments count %d created in the last minute reaches the max %d", recent, c.MaxInjectPerMinute)
+		}
+	}
+
+	return nil
+}
+
+// CheckHostProtected checks whether the host is in the protection list of the config
+func CheckHostProtected(ctx context.Context, hostname string) error {
+	c, err := Get()
+	if err != nil {
+		return err
+	}
+
+	for _, p := range c.ProtectedHosts {
+		if match, _ := path.Match(p, hostname); match {
+			return fmt.Errorf("host[%s] is protected by pattern[%s] of config version %d", hostname, p, c.Version)
+		}
+	}
+
+	return nil
+}
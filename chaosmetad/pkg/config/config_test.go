@@ -0,0 +1,76 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import "testing"
+
+func TestRuntimeConfig_validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		c       RuntimeConfig
+		wantErr bool
+	}{
+		{name: "empty", c: RuntimeConfig{Version: 1}},
+		{name: "full", c: RuntimeConfig{Version: 1, LogLevel: "debug", EnabledTargets: []string{"cpu", "mem"}, MaxRunningExperiments: 3, MaxInjectPerMinute: 10, MaxTimeout: "10m", ToolCpuLimit: 200, ToolMemLimit: "2GB", ToolRegistry: "https://oss.example.com/chaosmeta", ProtectedHosts: []string{"master-*"}}},
+		{name: "bad log level", c: RuntimeConfig{Version: 1, LogLevel: "trace"}, wantErr: true},
+		{name: "negative running", c: RuntimeConfig{Version: 1, MaxRunningExperiments: -1}, wantErr: true},
+		{name: "negative inject rate", c: RuntimeConfig{Version: 1, MaxInjectPerMinute: -1}, wantErr: true},
+		{name: "bad timeout", c: RuntimeConfig{Version: 1, MaxTimeout: "10x"}, wantErr: true},
+		{name: "negative tool cpu", c: RuntimeConfig{Version: 1, ToolCpuLimit: -1}, wantErr: true},
+		{name: "bad tool registry", c: RuntimeConfig{Version: 1, ToolRegistry: "oss.example.com"}, wantErr: true},
+		{name: "http tool registry", c: RuntimeConfig{Version: 1, ToolRegistry: "http://oss.example.com/chaosmeta"}, wantErr: true},
+		{name: "release", c: RuntimeConfig{Version: 1, ReleaseRegistry: "https://oss.example.com/chaosmeta"}},
+		{name: "http release registry", c: RuntimeConfig{Version: 1, ReleaseRegistry: "http://oss.example.com/chaosmeta"}, wantErr: true},
+		{name: "bad tool mem", c: RuntimeConfig{Version: 1, ToolMemLimit: "2XB"}, wantErr: true},
+		{name: "bad protected host", c: RuntimeConfig{Version: 1, ProtectedHosts: []string{"master-["}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.c.validate(); (err != nil) != tt.wantErr {
+				t.Errorf("validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckHooks(t *testing.T) {
+	if err := CheckHooks("", ""); err != nil {
+		t.Errorf("CheckHooks() without hooks error = %v", err)
+	}
+
+	t.Setenv(EnableHooksEnv, "")
+	if err := CheckHooks("curl -f http://127.0.0.1/health", ""); err == nil {
+		t.Errorf("CheckHooks() should fail when hooks are not enabled")
+	}
+
+	t.Setenv(EnableHooksEnv, "true")
+	if err := CheckHooks("", "curl -f http://127.0.0.1/health"); err != nil {
+		t.Errorf("CheckHooks() error = %v when hooks are enabled", err)
+	}
+}
+
+func TestCheckPowerFaults(t *testing.T) {
+	t.Setenv(EnablePowerFaultsEnv, "")
+	if err := CheckPowerFaults("reboot"); err == nil {
+		t.Errorf("CheckPowerFaults() should fail when power faults are not enabled")
+	}
+
+	t.Setenv(EnablePowerFaultsEnv, "true")
+	if err := CheckPowerFaults("reboot"); err != nil {
+		t.Errorf("CheckPowerFaults() error = %v when power faults are enabled", err)
+	}
+}
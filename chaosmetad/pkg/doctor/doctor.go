@@ -0,0 +1,297 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/crclient"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/storage"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/cmdexec"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/filesys"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/net"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/tool"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/user"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/version"
+	"runtime"
+	"sort"
+	"strings"
+	"syscall"
+)
+
+const (
+	CgroupV1     = "v1"
+	CgroupV2     = "v2"
+	CgroupHybrid = "hybrid"
+	CgroupNone   = "none"
+)
+
+// Report describes what the host supports, so that the experiments are only scheduled to the hosts able to run them
+type Report struct {
+	Version           string          `json:"version"`
+	Health            *Health         `json:"health"`
+	Kernel            string          `json:"kernel"`
+	Arch              string          `json:"arch"`
+	CgroupMode        string          `json:"cgroup_mode"`
+	User              string          `json:"user"`
+	Capabilities      []string        `json:"capabilities"`
+	FirewallBackend   string          `json:"firewall_backend,omitempty"`
+	Cmds              map[string]bool `json:"cmds"`
+	Tools             map[string]bool `json:"tools"`
+	ContainerRuntimes []string        `json:"container_runtimes"`
+	Faults            []*FaultReport  `json:"faults"`
+	// Container is only reported when the container of the experiment is provided
+	Container *ContainerReport `json:"container,omitempty"`
+}
+
+// Health describes whether chaosmetad itself is able to run experiments on the host
+type Health struct {
+	Healthy bool     `json:"healthy"`
+	Errors  []string `json:"errors,omitempty"`
+}
+
+type FaultReport struct {
+	Target  string   `json:"target"`
+	Fault   string   `json:"fault"`
+	Usable  bool     `json:"usable"`
+	Missing []string `json:"missing,omitempty"`
+}
+
+type ContainerReport struct {
+	Runtime string `json:"runtime"`
+	Id      string `json:"id"`
+	Running bool   `json:"running"`
+	Error   string `json:"error,omitempty"`
+}
+
+// requirement cmds are groups of alternatives, one cmd of each group must exist
+type requirement struct {
+	cmds      [][]string
+	cgroup    bool
+	container bool
+}
+
+// requirements is keyed by target or "target fault", the requirements of both are needed by a fault
+var requirements = map[string]requirement{
+	"mem":               {cmds: [][]string{{"fallocate", "dd"}, {"mount"}}},
+	"disk":              {cmds: [][]string{{"fallocate", "dd"}}},
+	"diskio hang":       {cgroup: true},
+	"diskio limit":      {cgroup: true},
+	"diskio delay":      {cmds: [][]string{{"dmsetup"}, {"blockdev"}}},
+	"diskio error":      {cmds: [][]string{{"dmsetup"}, {"blockdev"}}},
+	"network":           {cmds: [][]string{{"tc"}}},
+	"network partition": {cmds: [][]string{{"iptables", "iptables-legacy", "iptables-nft", "nft"}}},
+	"network nicdown":   {cmds: [][]string{{"ip"}}},
+	"network blackhole": {cmds: [][]string{{"iptables", "iptables-legacy", "iptables-nft"}}},
+	"dns delay":         {cmds: [][]string{{"tc"}}},
+	"http":              {cmds: [][]string{{"iptables"}}},
+	"grpc":              {cmds: [][]string{{"iptables"}}},
+	"mysql":             {cmds: [][]string{{"iptables"}}},
+	"redis":             {cmds: [][]string{{"iptables"}}},
+	"host reboot":       {cmds: [][]string{{"systemctl"}}},
+	"host shutdown":     {cmds: [][]string{{"systemctl"}}},
+	"container":         {container: true},
+}
+
+var runtimeSockets = map[string]string{
+	crclient.CrDocker:     "/var/run/docker.sock",
+	crclient.CrContainerd: "/run/containerd/containerd.sock",
+	crclient.CrPouch:      "/var/run/pouchd.sock",
+}
+
+func GetReport(ctx context.Context) *Report {
+	report := &Report{
+		Version:           version.GetVersion().Version,
+		Health:            getHealth(),
+		Kernel:            getKernel(),
+		Arch:              runtime.GOARCH,
+		CgroupMode:        getCgroupMode(),
+		User:              user.GetUser(),
+		Cmds:              map[string]bool{},
+		Tools:             map[string]bool{},
+		ContainerRuntimes: getContainerRuntimes(),
+	}
+	if caps, err := user.GetEffectiveCapNames(); err == nil {
+		report.Capabilities = caps
+	}
+	if backend, err := net.DetectFirewallBackend(ctx, "", ""); err == nil {
+		report.FirewallBackend = backend
+	}
+
+	for _, r := range requirements {
+		for _, group := range r.cmds {
+			for _, c := range group {
+				report.Cmds[c] = cmdexec.SupportCmd(c)
+			}
+		}
+	}
+	report.Cmds["taskset"] = cmdexec.SupportCmd("taskset")
+	for _, t := range injector.GetAllTools() {
+		report.Tools[t] = tool.Exist(t)
+	}
+
+	targets := injector.GetTargets()
+	sort.Strings(targets)
+	for _, target := range targets {
+		faults := injector.GetFaultsByTarget(target)
+		sort.Strings(faults)
+		for _, fault := range faults {
+			report.Faults = append(report.Faults, report.checkFault(target, fault))
+		}
+	}
+
+	return report
+}
+
+// FilterFaults only keeps the faults of target, and of fault if it is not empty
+func (r *Report) FilterFaults(target, fault string) {
+	if target == "" {
+		return
+	}
+
+	faults := make([]*FaultReport, 0)
+	for _, f := range r.Faults {
+		if f.Target == target && (fault == "" || f.Fault == fault) {
+			faults = append(faults, f)
+		}
+	}
+
+	r.Faults = faults
+}
+
+// CheckContainer reports whether the container is running, the same check as the injection of a container
+func (r *Report) CheckContainer(ctx context.Context, cr, containerId string) {
+	if cr == "" {
+		return
+	}
+
+	r.Container = &ContainerReport{Runtime: cr, Id: containerId}
+	client, err := crclient.GetClient(ctx, cr)
+	if err != nil {
+		r.Container.Error = fmt.Sprintf("create container runtime client error: %s", err.Error())
+		return
+	}
+
+	if _, err := client.GetPidById(ctx, containerId); err != nil {
+		r.Container.Error = err.Error()
+		return
+	}
+
+	r.Container.Running = true
+}
+
+func (r *Report) checkFault(target, fault string) *FaultReport {
+	re := &FaultReport{Target: target, Fault: fault}
+	for _, t := range injector.GetRequiredTools(target, fault, false) {
+		if !r.Tools[t] {
+			re.Missing = append(re.Missing, fmt.Sprintf("tool: %s", t))
+		}
+	}
+	for _, c := range injector.GetRequiredCaps(target, fault, false) {
+		if !utils.StrListContain(r.Capabilities, c) {
+			re.Missing = append(re.Missing, fmt.Sprintf("capability: %s", c))
+		}
+	}
+
+	for _, key := range []string{target, fmt.Sprintf("%s %s", target, fault)} {
+		req, ok := requirements[key]
+		if !ok {
+			continue
+		}
+
+		for _, group := range req.cmds {
+			if !r.anyCmd(group) {
+				re.Missing = append(re.Missing, fmt.Sprintf("cmd: %s", strings.Join(group, "|")))
+			}
+		}
+		if req.cgroup && r.CgroupMode == CgroupNone {
+			re.Missing = append(re.Missing, "cgroup")
+		}
+		if req.container && len(r.ContainerRuntimes) == 0 {
+			re.Missing = append(re.Missing, "container runtime")
+		}
+	}
+
+	re.Usable = len(re.Missing) == 0
+	return re
+}
+
+func (r *Report) anyCmd(cmds []string) bool {
+	for _, c := range cmds {
+		if r.Cmds[c] {
+			return true
+		}
+	}
+
+	return false
+}
+
+func getHealth() *Health {
+	health := &Health{}
+	if _, err := storage.GetExperimentStore(); err != nil {
+		health.Errors = append(health.Errors, fmt.Sprintf("storage: %s", err.Error()))
+	}
+
+	health.Healthy = len(health.Errors) == 0
+	return health
+}
+
+func getKernel() string {
+	var uts syscall.Utsname
+	if err := syscall.Uname(&uts); err != nil {
+		return ""
+	}
+
+	var buf strings.Builder
+	for _, c := range uts.Release {
+		if c == 0 {
+			break
+		}
+		buf.WriteByte(byte(c))
+	}
+
+	return buf.String()
+}
+
+func getCgroupMode() string {
+	if exist, _ := filesys.ExistPathLocal("/sys/fs/cgroup/cgroup.controllers"); exist {
+		return CgroupV2
+	}
+
+	if exist, _ := filesys.ExistPathLocal("/sys/fs/cgroup/unified/cgroup.controllers"); exist {
+		return CgroupHybrid
+	}
+
+	if exist, _ := filesys.ExistPathLocal("/sys/fs/cgroup/cpu"); exist {
+		return CgroupV1
+	}
+
+	return CgroupNone
+}
+
+func getContainerRuntimes() []string {
+	runtimes := make([]string, 0)
+	for cr, socket := range runtimeSockets {
+		if exist, _ := filesys.ExistPathLocal(socket); exist {
+			runtimes = append(runtimes, cr)
+		}
+	}
+
+	sort.Strings(runtimes)
+	return runtimes
+}
@@ -29,10 +29,6 @@ import (
 	"strconv"
 )
 
-const (
-	FillFileName = "chaosmeta_fill"
-)
-
 // [func] [fault] [level] [args]
 func main() {
 	var (
@@ -50,39 +46,57 @@ func main() {
 	case utils.MethodRecover:
 		err = execRecover(ctx, args)
 	default:
-		errutil.ExitExpectedErr(fmt.Sprintf("not support method: %s", fName))
+		errutil.ExitToolErr(errutil.ToolArgsErr, fmt.Sprintf("not support method: %s", fName))
 	}
 
 	if err != nil {
-		errutil.ExitExpectedErr(err.Error())
+		code := errutil.ToolRuntimeErr
+		if fName == utils.MethodValidator {
+			code = errutil.ToolArgsErr
+		}
+		errutil.ExitToolErr(code, err.Error())
 	}
 }
 
+// [percent] [bytes] [dir] [reserve] [force]
 func execValidator(ctx context.Context, args []string) error {
-	percentStr, bytes, dir := args[0], args[1], args[2]
+	percentStr, bytes, dir, reserve, forceStr := args[0], args[1], args[2], args[3], args[4]
 	percent, err := strconv.Atoi(percentStr)
 	if err != nil {
 		return fmt.Errorf("percent is not a num")
 	}
 
-	return validatorDiskFill(ctx, percent, bytes, dir)
+	force, err := strconv.ParseBool(forceStr)
+	if err != nil {
+		return fmt.Errorf("force is not a bool")
+	}
+
+	return validatorDiskFill(ctx, percent, bytes, dir, reserve, force)
 }
 
+// [percent] [bytes] [dir] [uid] [reserve]
 func execInject(ctx context.Context, args []string) error {
-	percentStr, bytes, dir, uid := args[0], args[1], args[2], args[3]
+	percentStr, bytes, dir, uid, reserve := args[0], args[1], args[2], args[3], args[4]
 	percent, err := strconv.Atoi(percentStr)
 	if err != nil {
 		return fmt.Errorf("pecent is not a num")
 	}
 
-	return injectDiskFill(ctx, percent, bytes, dir, uid)
+	return injectDiskFill(ctx, percent, bytes, dir, uid, reserve)
 }
 
+// [file]...
 func execRecover(ctx context.Context, args []string) error {
-	return recoverDiskFill(ctx, args[0], args[1])
+	for _, file := range args {
+		if err := recoverDiskFill(ctx, file); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
-func validatorDiskFill(ctx context.Context, percent int, bytes, dir string) error {
+func validatorDiskFill(ctx context.Context, percent int, bytes, dir, reserve string, force bool) error {
 	if percent == 0 && bytes == "" {
 		return fmt.Errorf("must provide \"percent\" or \"bytes\"")
 	}
@@ -101,7 +115,22 @@ func validatorDiskFill(ctx context.Context, percent int, bytes, dir string) erro
 		return fmt.Errorf("\"dir\"[%s] check error: %s", dir, err.Error())
 	}
 
-	if _, err := disk.GetFillKBytes(dir, percent, bytes); err != nil {
+	if !force {
+		onRoot, err := disk.IsOnRootFS(dir)
+		if err != nil {
+			return fmt.Errorf("check filesystem of \"dir\"[%s] error: %s", dir, err.Error())
+		}
+		if onRoot {
+			return fmt.Errorf("\"dir\"[%s] is on the root filesystem, provide \"force\" to fill it", dir)
+		}
+	}
+
+	reserveKb, err := utils.GetKBytes(reserve)
+	if err != nil || reserveKb < 0 {
+		return fmt.Errorf("\"reserve\"[%s] is invalid", reserve)
+	}
+
+	if _, err := disk.GetFillKBytes(dir, percent, bytes, reserveKb); err != nil {
 		return fmt.Errorf("calculate fill bytes error: %s", err.Error())
 	}
 
@@ -112,14 +141,14 @@ func validatorDiskFill(ctx context.Context, percent int, bytes, dir string) erro
 	return nil
 }
 
-func getFillFileName(uid string) string {
-	return fmt.Sprintf("%s%s.dat", FillFileName, uid)
-}
-
-func injectDiskFill(ctx context.Context, percent int, bytes, dir, uid string) error {
+func injectDiskFill(ctx context.Context, percent int, bytes, dir, uid, reserve string) error {
 	logger := log.GetLogger(ctx)
-	fillFile := fmt.Sprintf("%s/%s", dir, getFillFileName(uid))
-	bytesKb, _ := disk.GetFillKBytes(dir, percent, bytes)
+	fillFile := disk.GetFillFile(dir, uid)
+	reserveKb, _ := utils.GetKBytes(reserve)
+	bytesKb, err := disk.GetFillKBytes(dir, percent, bytes, reserveKb)
+	if err != nil {
+		return fmt.Errorf("calculate fill bytes error: %s", err.Error())
+	}
 
 	if err := disk.RunFillDisk(ctx, bytesKb, fillFile); err != nil {
 		if err := os.Remove(fillFile); err != nil {
@@ -131,8 +160,7 @@ func injectDiskFill(ctx context.Context, percent int, bytes, dir, uid string) er
 	return nil
 }
 
-func recoverDiskFill(ctx context.Context, dir, uid string) error {
-	fillFile := fmt.Sprintf("%s/%s", dir, getFillFileName(uid))
+func recoverDiskFill(ctx context.Context, fillFile string) error {
 	isExist, err := filesys.ExistPathLocal(fillFile)
 	if err != nil {
 		return fmt.Errorf("check file[%s] exist error: %s", fillFile, err.Error())
@@ -50,11 +50,15 @@ func main() {
 	case utils.MethodRecover:
 		err = execRecover(ctx, fault, args)
 	default:
-		errutil.ExitExpectedErr(fmt.Sprintf("not support method: %s", fName))
+		errutil.ExitToolErr(errutil.ToolArgsErr, fmt.Sprintf("not support method: %s", fName))
 	}
 
 	if err != nil {
-		errutil.ExitExpectedErr(err.Error())
+		code := errutil.ToolRuntimeErr
+		if fName == utils.MethodValidator {
+			code = errutil.ToolArgsErr
+		}
+		errutil.ExitToolErr(code, err.Error())
 	}
 }
 
@@ -0,0 +1,121 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package injector
+
+import (
+	"context"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/user"
+	"strings"
+)
+
+// requiredCaps is keyed by target or "target fault", the capabilities of both are needed by a fault
+var requiredCaps = map[string][]string{
+	"network delay":        {user.CapNetAdmin},
+	"network loss":         {user.CapNetAdmin},
+	"network duplicate":    {user.CapNetAdmin},
+	"network corrupt":      {user.CapNetAdmin},
+	"network reorder":      {user.CapNetAdmin},
+	"network limit":        {user.CapNetAdmin},
+	"network partition":    {user.CapNetAdmin},
+	"network nicdown":      {user.CapNetAdmin},
+	"network blackhole":    {user.CapNetAdmin},
+	"network occupy":       {user.CapNetAdmin},
+	"dns record":           {user.CapDacOverride},
+	"dns server":           {user.CapDacOverride},
+	"dns hijack":           {user.CapDacOverride},
+	"dns nxdomain":         {user.CapDacOverride},
+	"dns delay":            {user.CapNetAdmin},
+	"http":                 {user.CapNetAdmin},
+	"grpc":                 {user.CapNetAdmin},
+	"mysql":                {user.CapNetAdmin},
+	"redis":                {user.CapNetAdmin},
+	"diskio hang":          {user.CapDacOverride},
+	"diskio limit":         {user.CapDacOverride},
+	"diskio delay":         {user.CapSysAdmin},
+	"diskio error":         {user.CapSysAdmin},
+	"mem cgroupfill":       {user.CapDacOverride},
+	"kernel fdfull":        {user.CapSysAdmin},
+	"process fdfull":       {user.CapSysResource},
+	"process priority":     {user.CapSysNice},
+	"process funcdelay":    {user.CapSysPtrace},
+	"jvm":                  {user.CapSysPtrace},
+	"time":                 {user.CapSysTime},
+	"systemd":              {user.CapSysAdmin},
+	"host reboot":          {user.CapSysBoot},
+	"host shutdown":        {user.CapSysBoot},
+	"host panic":           {user.CapSysAdmin},
+	containerCapabilityKey: {user.CapSysAdmin, user.CapSysPtrace},
+}
+
+// ICapability is implemented by the faults whose capabilities depend on the args, such as CAP_SYS_ADMIN to mount the
+// tmpfs of mem fill in cache mode, or CAP_KILL to signal the processes of other users
+type ICapability interface {
+	GetExtraCaps(ctx context.Context) ([]string, error)
+}
+
+// containerCapabilityKey is the extra requirement of the faults executed in the namespaces of a container
+const containerCapabilityKey = "<container>"
+
+// GetRequiredCaps returns the capabilities needed by a fault, inContainer means the fault is injected into a container
+func GetRequiredCaps(target, fault string, inContainer bool) []string {
+	keys := []string{target, fmt.Sprintf("%s %s", target, fault)}
+	// the faults of target container are executed by the container runtime instead of entering the namespaces
+	if inContainer && target != "container" {
+		keys = append(keys, containerCapabilityKey)
+	}
+
+	var caps []string
+	for _, key := range keys {
+		for _, c := range requiredCaps[key] {
+			if !utils.StrListContain(caps, c) {
+				caps = append(caps, c)
+			}
+		}
+	}
+
+	return caps
+}
+
+// CheckCapabilities fails before injecting if the current process lacks the capabilities of the fault,
+// so that a fault run by an unprivileged user is rejected clearly instead of failing inside tc, iptables and so on
+func CheckCapabilities(ctx context.Context, i IInjector, target, fault string, inContainer bool) error {
+	caps := GetRequiredCaps(target, fault, inContainer)
+	if c, ok := i.(ICapability); ok {
+		extra, err := c.GetExtraCaps(ctx)
+		if err != nil {
+			return fmt.Errorf("get capabilities of args error: %s", err.Error())
+		}
+		for _, unit := range extra {
+			if !utils.StrListContain(caps, unit) {
+				caps = append(caps, unit)
+			}
+		}
+	}
+
+	missing, err := user.GetMissingCaps(caps)
+	if err != nil {
+		return fmt.Errorf("get capabilities error: %s", err.Error())
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("missing capabilities: %s", strings.Join(missing, ","))
+	}
+
+	return nil
+}
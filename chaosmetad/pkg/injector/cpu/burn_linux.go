@@ -25,8 +25,10 @@ import (
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/cgroup"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/cmdexec"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/containercgroup"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/namespace"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/process"
+	"math"
 )
 
 func init() {
@@ -43,9 +45,18 @@ type BurnArgs struct {
 	Percent int    `json:"percent"`
 	Count   int    `json:"count,omitempty"`
 	List    string `json:"list,omitempty"`
+	// Profile is the load waveform between MinPercent and Percent, Period is the seconds of a wave
+	Profile    string `json:"profile,omitempty"`
+	MinPercent int    `json:"min_percent,omitempty"`
+	Period     int    `json:"period,omitempty"`
+	// Ramp is the seconds to raise the load linearly from 0 to the waveform
+	Ramp int `json:"ramp,omitempty"`
 }
 
 type BurnRuntime struct {
+	CoreList []int `json:"core_list"`
+	// CoreUsage is the usage percent of each burned core sampled after the injection
+	CoreUsage map[int]float64 `json:"core_usage,omitempty"`
 }
 
 func (i *BurnInjector) GetArgs() interface{} {
@@ -64,12 +75,28 @@ func (i *BurnInjector) getCmdExecutor() *cmdexec.CmdExecutor {
 	}
 }
 
+func (i *BurnInjector) SetDefault() {
+	i.BaseInjector.SetDefault()
+
+	if i.Args.Profile == "" {
+		i.Args.Profile = ProfileConstant
+	}
+
+	if i.Args.Period == 0 {
+		i.Args.Period = DefaultProfilePeriod
+	}
+}
+
 func (i *BurnInjector) SetOption(cmd *cobra.Command) {
 	// i.BaseInjector.SetOption(cmd)
 
 	cmd.Flags().IntVarP(&i.Args.Percent, "percent", "p", 0, "cpu burn usage percent to add, an integer in (0,100] without \"%\", eg: \"30\" means \"30%\"")
 	cmd.Flags().StringVarP(&i.Args.List, "list", "l", "", "cpu burn core number list, start from 0, eg: \"0-2,6\" means \"0,1,2,6\" core")
 	cmd.Flags().IntVarP(&i.Args.Count, "count", "c", 0, "cpu burn core count（default 0, means all core）. if provide args \"list\", \"count\" will be ignored.")
+	cmd.Flags().StringVarP(&i.Args.Profile, "profile", "P", "", fmt.Sprintf("load waveform between \"min-percent\" and \"percent\", support: %s、%s、%s、%s（default %s）", ProfileConstant, ProfileSquare, ProfileSine, ProfileRandom, ProfileConstant))
+	cmd.Flags().IntVarP(&i.Args.MinPercent, "min-percent", "m", 0, "the lowest usage percent of the waveform, an integer in [0,\"percent\"]")
+	cmd.Flags().IntVarP(&i.Args.Period, "period", "T", 0, fmt.Sprintf("seconds of a wave of the square and sine profiles, step interval of the random profile is period/10（default %d）", DefaultProfilePeriod))
+	cmd.Flags().IntVarP(&i.Args.Ramp, "ramp", "r", 0, "seconds to raise the load linearly from 0 to the waveform（default 0, means no ramp-up）")
 }
 
 // Validator list > count
@@ -82,6 +109,22 @@ func (i *BurnInjector) Validator(ctx context.Context) error {
 		return fmt.Errorf("\"percent\"[%d] must be in (0,100]", i.Args.Percent)
 	}
 
+	if i.Args.Profile != ProfileConstant && i.Args.Profile != ProfileSquare && i.Args.Profile != ProfileSine && i.Args.Profile != ProfileRandom {
+		return fmt.Errorf("\"profile\" is not support: %s, only support: %s、%s、%s、%s", i.Args.Profile, ProfileConstant, ProfileSquare, ProfileSine, ProfileRandom)
+	}
+
+	if i.Args.MinPercent < 0 || i.Args.MinPercent > i.Args.Percent {
+		return fmt.Errorf("\"min-percent\"[%d] must be in [0,%d]", i.Args.MinPercent, i.Args.Percent)
+	}
+
+	if i.Args.Period <= 0 {
+		return fmt.Errorf("\"period\"[%d] must larger than 0", i.Args.Period)
+	}
+
+	if i.Args.Ramp < 0 {
+		return fmt.Errorf("\"ramp\"[%d] can not less than 0", i.Args.Ramp)
+	}
+
 	cpuList, err := getAllCpuList(ctx, i.Info.ContainerRuntime, i.Info.ContainerId)
 	if err != nil {
 		return fmt.Errorf("get all available cpu list error: %s", err.Error())
@@ -149,7 +192,8 @@ func (i *BurnInjector) Inject(ctx context.Context) error {
 	}
 
 	for c := 0; c < len(coreList); c++ {
-		cmd := fmt.Sprintf("taskset -c %d %s %s %d %d %d %d", coreList[c], utils.GetToolPath(CpuBurnKey), i.Info.Uid, coreList[c], i.Args.Percent, targetPid, timeout)
+		cmd := fmt.Sprintf("taskset -c %d %s %s %d %d %d %d %s %d %d %d", coreList[c], utils.GetToolPath(CpuBurnKey), i.Info.Uid, coreList[c], i.Args.Percent, targetPid, timeout,
+			i.Args.Profile, i.Args.MinPercent, i.Args.Period, i.Args.Ramp)
 		if err := e.StartCmdAndWait(ctx, cmd); err != nil {
 			if err := i.Recover(ctx); err != nil {
 				logger.Warnf("undo error: %s", err.Error())
@@ -157,6 +201,15 @@ func (i *BurnInjector) Inject(ctx context.Context) error {
 			return fmt.Errorf("burn cpu of core[%d] error: %s", coreList[c], err.Error())
 		}
 	}
+	i.Runtime.CoreList = coreList
+
+	coreUsage, err := getCoreUsage(targetPid, coreList)
+	if err != nil {
+		logger.Warnf("get usage of burned cores error: %s", err.Error())
+		return nil
+	}
+	i.Runtime.CoreUsage = coreUsage
+	logger.Infof("usage of burned cores: %v", coreUsage)
 
 	return nil
 }
@@ -185,6 +238,24 @@ func getAllCpuList(ctx context.Context, cr, cId string) (cpuList []int, err erro
 	return getCpuList(ctx, cpusetPath)
 }
 
+// getCoreUsage returns the usage percent of the cores in the cgroup of targetPid, the whole host if targetPid is -1
+func getCoreUsage(targetPid int, coreList []int) (map[int]float64, error) {
+	perUsage, err := containercgroup.CalculateNowPercent(targetPid)
+	if err != nil {
+		return nil, err
+	}
+
+	coreUsage := make(map[int]float64, len(coreList))
+	for _, core := range coreList {
+		if core >= len(perUsage) {
+			return nil, fmt.Errorf("core[%d] is not in the usage stat of %d cores", core, len(perUsage))
+		}
+		coreUsage[core] = math.Round(perUsage[core]*100) / 100
+	}
+
+	return coreUsage, nil
+}
+
 func getCpuList(ctx context.Context, path string) ([]int, error) {
 	//cpusetFile := fmt.Sprintf("%s/%s%s/%s", containercgroup.RootCgroupPath, cgroup.CPUSET, path, cgroup.CpusetCoreFile)
 	//reByte, err := os.ReadFile(cpusetFile)
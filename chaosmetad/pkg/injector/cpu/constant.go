@@ -22,6 +22,13 @@ const (
 	FaultCpuBurn = "burn"
 	CpuBurnKey   = "chaosmeta_cpuburn"
 
+	ProfileConstant = "constant"
+	ProfileSquare   = "square"
+	ProfileSine     = "sine"
+	ProfileRandom   = "random"
+
+	DefaultProfilePeriod = 60
+
 	FaultCpuLoad = "load"
 	CpuLoadKey   = "chaosmeta_cpuload"
 )
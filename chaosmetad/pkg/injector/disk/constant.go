@@ -21,7 +21,9 @@ const (
 
 	FaultDiskFill = "fill"
 
-	DefaultDir    = "/tmp"
+	DefaultDir = "/tmp"
+	// DefaultReserve the free space kept after filling, so that the system can still write logs and recover
+	DefaultReserve = "100MB"
 
 	DiskFillExec = "chaosmeta_diskfill"
 )
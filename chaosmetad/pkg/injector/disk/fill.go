@@ -23,8 +23,10 @@ import (
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/cmdexec"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/disk"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/filesys"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/namespace"
+	"path/filepath"
 )
 
 func init() {
@@ -41,9 +43,13 @@ type FillArgs struct {
 	Percent int    `json:"percent,omitempty"`
 	Bytes   string `json:"bytes,omitempty"`
 	Dir     string `json:"dir,omitempty"`
+	Reserve string `json:"reserve,omitempty"`
+	Force   bool   `json:"force,omitempty"`
 }
 
+// FillRuntime File is registered before filling, so that a half created file is removed by recover as well
 type FillRuntime struct {
+	File string `json:"file,omitempty"`
 }
 
 func (i *FillInjector) GetArgs() interface{} {
@@ -60,6 +66,10 @@ func (i *FillInjector) SetDefault() {
 	if i.Args.Dir == "" {
 		i.Args.Dir = DefaultDir
 	}
+
+	if i.Args.Reserve == "" {
+		i.Args.Reserve = DefaultReserve
+	}
 }
 
 func (i *FillInjector) SetOption(cmd *cobra.Command) {
@@ -68,6 +78,8 @@ func (i *FillInjector) SetOption(cmd *cobra.Command) {
 	cmd.Flags().IntVarP(&i.Args.Percent, "percent", "p", 0, "disk fill target percent, an integer in (0,100] without \"%\", eg: \"30\" means \"30%\"")
 	cmd.Flags().StringVarP(&i.Args.Bytes, "bytes", "b", "", "disk fill bytes to add, support unit: KB/MB/GB/TB（default KB）")
 	cmd.Flags().StringVarP(&i.Args.Dir, "dir", "d", "", fmt.Sprintf("disk fill target dir（default %s）", DefaultDir))
+	cmd.Flags().StringVarP(&i.Args.Reserve, "reserve", "r", "", fmt.Sprintf("free space always kept, \"percent\" is lowered to keep it, support unit: KB/MB/GB/TB（default %s）", DefaultReserve))
+	cmd.Flags().BoolVarP(&i.Args.Force, "force", "f", false, fmt.Sprintf("allow to fill the root filesystem, not needed for the default dir %s", DefaultDir))
 }
 
 func (i *FillInjector) getCmdExecutor(method, args string) *cmdexec.CmdExecutor {
@@ -91,11 +103,18 @@ func (i *FillInjector) Validator(ctx context.Context) error {
 		return fmt.Errorf("\"dir\" must provide absolute path")
 	}
 
-	return i.getCmdExecutor(utils.MethodValidator, fmt.Sprintf("%d '%s' %s", i.Args.Percent, i.Args.Bytes, i.Args.Dir)).ExecTool(ctx)
+	if _, err := utils.GetKBytes(i.Args.Reserve); err != nil {
+		return fmt.Errorf("\"reserve\"[%s] is invalid: %s", i.Args.Reserve, err.Error())
+	}
+
+	// the default dir is usually on the root filesystem, it is allowed as the reserve keeps the filesystem writable
+	force := i.Args.Force || filepath.Clean(i.Args.Dir) == DefaultDir
+	return i.getCmdExecutor(utils.MethodValidator, fmt.Sprintf("%d '%s' %s %s %t", i.Args.Percent, i.Args.Bytes, i.Args.Dir, i.Args.Reserve, force)).ExecTool(ctx)
 }
 
 func (i *FillInjector) Inject(ctx context.Context) error {
-	return i.getCmdExecutor(utils.MethodInject, fmt.Sprintf("%d '%s' %s %s", i.Args.Percent, i.Args.Bytes, i.Args.Dir, i.Info.Uid)).ExecTool(ctx)
+	i.Runtime.File = disk.GetFillFile(i.Args.Dir, i.Info.Uid)
+	return i.getCmdExecutor(utils.MethodInject, fmt.Sprintf("%d '%s' %s %s %s", i.Args.Percent, i.Args.Bytes, i.Args.Dir, i.Info.Uid, i.Args.Reserve)).ExecTool(ctx)
 }
 
 func (i *FillInjector) Recover(ctx context.Context) error {
@@ -103,5 +122,11 @@ func (i *FillInjector) Recover(ctx context.Context) error {
 		return nil
 	}
 
-	return i.getCmdExecutor(utils.MethodRecover, fmt.Sprintf("%s %s", i.Args.Dir, i.Info.Uid)).ExecTool(ctx)
+	// experiments injected by older versions have no registered file
+	file := i.Runtime.File
+	if file == "" {
+		file = disk.GetFillFile(i.Args.Dir, i.Info.Uid)
+	}
+
+	return i.getCmdExecutor(utils.MethodRecover, file).ExecTool(ctx)
 }
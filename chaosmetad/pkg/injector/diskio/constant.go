@@ -37,5 +37,8 @@ const (
 
 	TmpCgroup = "/user.slice"
 
+	FaultDiskIODelay = "delay"
+	FaultDiskIOError = "error"
+
 	DiskIOExec = "chaosmeta_diskio"
 )
@@ -0,0 +1,102 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package diskio
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/disk"
+	"time"
+)
+
+func init() {
+	injector.Register(TargetDiskIO, FaultDiskIODelay, func() injector.IInjector { return &DelayInjector{} })
+}
+
+type DelayInjector struct {
+	injector.BaseInjector
+	Args    DelayArgs
+	Runtime DelayRuntime
+}
+
+type DelayArgs struct {
+	DMArgs
+	Latency string `json:"latency"`
+}
+
+type DelayRuntime struct {
+	DMRuntime
+}
+
+func (i *DelayInjector) GetArgs() interface{} {
+	return &i.Args
+}
+
+func (i *DelayInjector) GetRuntime() interface{} {
+	return &i.Runtime
+}
+
+func (i *DelayInjector) SetDefault() {
+	i.BaseInjector.SetDefault()
+
+	setDMDefault(&i.Args.DMArgs)
+}
+
+func (i *DelayInjector) SetOption(cmd *cobra.Command) {
+	setDMOption(cmd, &i.Args.DMArgs)
+	cmd.Flags().StringVarP(&i.Args.Latency, "latency", "l", "", "latency added to each io, eg: 50ms, 1s")
+}
+
+func (i *DelayInjector) Validator(ctx context.Context) error {
+	if err := i.BaseInjector.Validator(ctx); err != nil {
+		return err
+	}
+
+	if i.Args.Latency == "" {
+		return fmt.Errorf("\"latency\" must provide")
+	}
+
+	if d, err := time.ParseDuration(i.Args.Latency); err != nil || d.Milliseconds() <= 0 {
+		return fmt.Errorf("\"latency\" is invalid, must be at least 1ms, eg: 50ms, 1s")
+	}
+
+	return validateDMArgs(ctx, &i.BaseInjector, &i.Args.DMArgs, disk.DMTargetDelay)
+}
+
+func (i *DelayInjector) Inject(ctx context.Context) error {
+	latency, _ := time.ParseDuration(i.Args.Latency)
+	readDelay, writeDelay := latency.Milliseconds(), latency.Milliseconds()
+	switch i.Args.Mode {
+	case ModeRead:
+		writeDelay = 0
+	case ModeWrite:
+		readDelay = 0
+	}
+
+	return injectDM(ctx, &i.BaseInjector, &i.Args.DMArgs, &i.Runtime.DMRuntime, func(innerDev, sectors string) string {
+		return fmt.Sprintf("0 %s %s %s 0 %d %s 0 %d", sectors, disk.DMTargetDelay, innerDev, readDelay, innerDev, writeDelay)
+	})
+}
+
+func (i *DelayInjector) Recover(ctx context.Context) error {
+	if i.BaseInjector.Recover(ctx) == nil {
+		return nil
+	}
+
+	return recoverDM(ctx, &i.BaseInjector, &i.Runtime.DMRuntime)
+}
@@ -0,0 +1,102 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package diskio
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/cmdexec"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/disk"
+)
+
+// DMArgs are the common args of the faults based on device-mapper
+type DMArgs struct {
+	Dev  string `json:"dev,omitempty"`
+	Path string `json:"path,omitempty"`
+	Mode string `json:"mode,omitempty"`
+}
+
+type DMRuntime struct {
+	Name string `json:"name,omitempty"`
+}
+
+func setDMDefault(args *DMArgs) {
+	if args.Mode == "" {
+		args.Mode = ModeAll
+	}
+}
+
+func setDMOption(cmd *cobra.Command, args *DMArgs) {
+	cmd.Flags().StringVarP(&args.Dev, "dev", "d", "", "target device-mapper device, eg: /dev/mapper/vg-data, /dev/dm-0")
+	cmd.Flags().StringVarP(&args.Path, "path", "p", "", "use the device which this path is located on, if provided, \"dev\" will be ignored")
+	cmd.Flags().StringVarP(&args.Mode, "mode", "m", "", fmt.Sprintf("io to inject, support: %s、%s、%s(default %s)", ModeRead, ModeWrite, ModeAll, ModeAll))
+}
+
+// validateDMArgs the preflight checks, wrapping a wrong device makes the filesystem on it unusable
+func validateDMArgs(ctx context.Context, info *injector.BaseInjector, args *DMArgs, target string) error {
+	if info.Info.ContainerId != "" || info.Info.ContainerRuntime != "" {
+		return fmt.Errorf("diskio fault based on device-mapper not support in container")
+	}
+
+	if args.Mode != ModeRead && args.Mode != ModeWrite && args.Mode != ModeAll {
+		return fmt.Errorf("\"mode\" only support: %s、%s、%s", ModeRead, ModeWrite, ModeAll)
+	}
+
+	for _, c := range []string{"dmsetup", "blockdev"} {
+		if !cmdexec.SupportCmd(c) {
+			return fmt.Errorf("not support cmd \"%s\"", c)
+		}
+	}
+
+	if args.Dev == "" && args.Path == "" {
+		return fmt.Errorf("must provide \"dev\" or \"path\"")
+	}
+
+	name, err := disk.GetDMName(ctx, args.Dev, args.Path)
+	if err != nil {
+		return err
+	}
+
+	wrapped, err := disk.IsDMWrapped(ctx, name)
+	if err != nil {
+		return fmt.Errorf("check device[%s] error: %s", name, err.Error())
+	}
+	if wrapped {
+		return fmt.Errorf("device[%s] is injected by another diskio fault", name)
+	}
+
+	return disk.CheckDMTarget(ctx, target)
+}
+
+func injectDM(ctx context.Context, info *injector.BaseInjector, args *DMArgs, runtime *DMRuntime, getTable func(innerDev, sectors string) string) error {
+	name, err := disk.GetDMName(ctx, args.Dev, args.Path)
+	if err != nil {
+		return err
+	}
+
+	runtime.Name = name
+	return disk.WrapDM(ctx, name, disk.GetDMInnerName(info.Info.Uid), getTable)
+}
+
+func recoverDM(ctx context.Context, info *injector.BaseInjector, runtime *DMRuntime) error {
+	if runtime.Name == "" {
+		return nil
+	}
+
+	return disk.UnwrapDM(ctx, runtime.Name, disk.GetDMInnerName(info.Info.Uid))
+}
@@ -0,0 +1,102 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package diskio
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/disk"
+)
+
+func init() {
+	injector.Register(TargetDiskIO, FaultDiskIOError, func() injector.IInjector { return &ErrorInjector{} })
+}
+
+// ErrorInjector makes io of the device fail with EIO by dm-flakey, "mode" read needs kernel 6.2+ and write needs 4.12+
+type ErrorInjector struct {
+	injector.BaseInjector
+	Args    ErrorArgs
+	Runtime ErrorRuntime
+}
+
+type ErrorArgs struct {
+	DMArgs
+	UpInterval   int `json:"up_interval,omitempty"`
+	DownInterval int `json:"down_interval,omitempty"`
+}
+
+type ErrorRuntime struct {
+	DMRuntime
+}
+
+func (i *ErrorInjector) GetArgs() interface{} {
+	return &i.Args
+}
+
+func (i *ErrorInjector) GetRuntime() interface{} {
+	return &i.Runtime
+}
+
+func (i *ErrorInjector) SetDefault() {
+	i.BaseInjector.SetDefault()
+
+	setDMDefault(&i.Args.DMArgs)
+	if i.Args.DownInterval == 0 {
+		i.Args.DownInterval = 1
+	}
+}
+
+func (i *ErrorInjector) SetOption(cmd *cobra.Command) {
+	setDMOption(cmd, &i.Args.DMArgs)
+	cmd.Flags().IntVar(&i.Args.UpInterval, "up-interval", 0, "seconds the io works normally in each cycle(default 0, means io always fails)")
+	cmd.Flags().IntVar(&i.Args.DownInterval, "down-interval", 0, "seconds the io fails in each cycle(default 1)")
+}
+
+func (i *ErrorInjector) Validator(ctx context.Context) error {
+	if err := i.BaseInjector.Validator(ctx); err != nil {
+		return err
+	}
+
+	if i.Args.UpInterval < 0 || i.Args.DownInterval <= 0 {
+		return fmt.Errorf("\"up-interval\" must not be less than 0 and \"down-interval\" must larger than 0")
+	}
+
+	return validateDMArgs(ctx, &i.BaseInjector, &i.Args.DMArgs, disk.DMTargetFlakey)
+}
+
+func (i *ErrorInjector) Inject(ctx context.Context) error {
+	var feature string
+	switch i.Args.Mode {
+	case ModeRead:
+		feature = " 1 error_reads"
+	case ModeWrite:
+		feature = " 1 error_writes"
+	}
+
+	return injectDM(ctx, &i.BaseInjector, &i.Args.DMArgs, &i.Runtime.DMRuntime, func(innerDev, sectors string) string {
+		return fmt.Sprintf("0 %s %s %s 0 %d %d%s", sectors, disk.DMTargetFlakey, innerDev, i.Args.UpInterval, i.Args.DownInterval, feature)
+	})
+}
+
+func (i *ErrorInjector) Recover(ctx context.Context) error {
+	if i.BaseInjector.Recover(ctx) == nil {
+		return nil
+	}
+
+	return recoverDM(ctx, &i.BaseInjector, &i.Runtime.DMRuntime)
+}
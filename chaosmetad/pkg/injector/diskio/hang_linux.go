@@ -23,7 +23,6 @@ import (
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/log"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/cgroup"
-	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/containercgroup"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/disk"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/filesys"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/process"
@@ -136,7 +135,15 @@ func (i *HangInjector) Inject(ctx context.Context) error {
 	}
 
 	blkioPath := cgroup.GetBlkioCPath(i.Info.Uid, containerCgroup)
-	if err := cgroup.NewCgroup(ctx, blkioPath, cgroup.GetBlkioConfig(ctx, devList, rByte, wByte, 0, 0, blkioPath)); err != nil {
+	if err := cgroup.EnableIoController(ctx, blkioPath); err != nil {
+		if err := i.Recover(ctx); err != nil {
+			logger.Warnf("undo error: %s", err.Error())
+		}
+
+		return fmt.Errorf("enable io controller for cgroup[%s] error: %s", blkioPath, err.Error())
+	}
+
+	if err := cgroup.NewCgroup(ctx, blkioPath, cgroup.GetIoLimitConfig(ctx, devList, rByte, wByte, 0, 0, blkioPath)); err != nil {
 		if err := i.Recover(ctx); err != nil {
 			logger.Warnf("undo error: %s", err.Error())
 		}
@@ -182,7 +189,7 @@ func (i *HangInjector) Recover(ctx context.Context) error {
 	}
 
 	if !isCgroupExist {
-		return nil
+		return cgroup.DisableIoController(ctx, cgroupPath)
 	}
 
 	pidList, err := cgroup.GetPidStrListByCgroup(ctx, cgroupPath)
@@ -197,7 +204,7 @@ func (i *HangInjector) Recover(ctx context.Context) error {
 			oldPath = tmpPath
 		}
 
-		if err := cgroup.MoveTaskToCgroup(ctx, pid, fmt.Sprintf("%s/%s%s", containercgroup.RootCgroupPath, cgroup.BLKIO, oldPath)); err != nil {
+		if err := cgroup.MoveTaskToCgroup(ctx, pid, cgroup.GetIoRecoverPath(cgroupPath, oldPath)); err != nil {
 			return fmt.Errorf("recover pid[%d] error: %s", pid, err.Error())
 		}
 	}
@@ -206,5 +213,5 @@ func (i *HangInjector) Recover(ctx context.Context) error {
 		return fmt.Errorf("remove cgroup[%s] error: %s", cgroupPath, err.Error())
 	}
 
-	return nil
+	return cgroup.DisableIoController(ctx, cgroupPath)
 }
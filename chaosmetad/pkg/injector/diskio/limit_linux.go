@@ -24,7 +24,6 @@ import (
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/log"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/cgroup"
-	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/containercgroup"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/disk"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/filesys"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/process"
@@ -139,7 +138,15 @@ func (i *LimitInjector) Inject(ctx context.Context) error {
 	}
 
 	blkioPath := cgroup.GetBlkioCPath(i.Info.Uid, containerCgroup)
-	if err := cgroup.NewCgroup(ctx, blkioPath, cgroup.GetBlkioConfig(ctx, devList, i.Args.ReadBytes, i.Args.WriteBytes, i.Args.ReadIO, i.Args.WriteIO, blkioPath)); err != nil {
+	if err := cgroup.EnableIoController(ctx, blkioPath); err != nil {
+		if err := i.Recover(ctx); err != nil {
+			logger.Warnf("undo error: %s", err.Error())
+		}
+
+		return fmt.Errorf("enable io controller for cgroup[%s] error: %s", blkioPath, err.Error())
+	}
+
+	if err := cgroup.NewCgroup(ctx, blkioPath, cgroup.GetIoLimitConfig(ctx, devList, i.Args.ReadBytes, i.Args.WriteBytes, i.Args.ReadIO, i.Args.WriteIO, blkioPath)); err != nil {
 		if err := i.Recover(ctx); err != nil {
 			logger.Warnf("undo error: %s", err.Error())
 		}
@@ -184,7 +191,7 @@ func (i *LimitInjector) Recover(ctx context.Context) error {
 	}
 
 	if !isCgroupExist {
-		return nil
+		return cgroup.DisableIoController(ctx, cgroupPath)
 	}
 
 	pidList, err := cgroup.GetPidStrListByCgroup(ctx, cgroupPath)
@@ -199,7 +206,7 @@ func (i *LimitInjector) Recover(ctx context.Context) error {
 			oldPath = tmpPath
 		}
 
-		if err := cgroup.MoveTaskToCgroup(ctx, pid, fmt.Sprintf("%s/%s%s", containercgroup.RootCgroupPath, cgroup.BLKIO, oldPath)); err != nil {
+		if err := cgroup.MoveTaskToCgroup(ctx, pid, cgroup.GetIoRecoverPath(cgroupPath, oldPath)); err != nil {
 			return fmt.Errorf("recover pid[%d] error: %s", pid, err.Error())
 		}
 	}
@@ -208,5 +215,5 @@ func (i *LimitInjector) Recover(ctx context.Context) error {
 		return fmt.Errorf("remove cgroup[%s] error: %s", cgroupPath, err.Error())
 	}
 
-	return nil
+	return cgroup.DisableIoController(ctx, cgroupPath)
 }
@@ -19,8 +19,11 @@ package dns
 const (
 	TargetDNS = "dns"
 
-	FaultDNSRecord = "record"
-	FaultDNSServer = "server"
+	FaultDNSRecord   = "record"
+	FaultDNSServer   = "server"
+	FaultDNSHijack   = "hijack"
+	FaultDNSNxdomain = "nxdomain"
+	FaultDNSDelay    = "delay"
 
 	ModeAdd    = "add"
 	ModeDelete = "delete"
@@ -29,4 +32,12 @@ const (
 	ConfServer    = "/etc/resolv.conf"
 	ConfRecordBak = "/etc/hosts.chaosmeta"
 	ConfServerBak = "/etc/resolv.conf.chaosmeta"
+
+	InterceptHosts   = "hosts"
+	InterceptDnsmasq = "dnsmasq"
+
+	DnsmasqListenIp = "127.0.0.1"
+	DnsmasqPidFile  = "/tmp/chaosmeta_dnsmasq_%s.pid"
+	DNSPort         = "53"
+	DomainListSplit = ","
 )
@@ -0,0 +1,162 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package dns
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/log"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/cmdexec"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/net"
+)
+
+func init() {
+	injector.Register(TargetDNS, FaultDNSDelay, func() injector.IInjector { return &DelayInjector{} })
+}
+
+type DelayInjector struct {
+	injector.BaseInjector
+	Args    DelayArgs
+	Runtime DelayRuntime
+}
+
+type DelayArgs struct {
+	Interface string `json:"interface"`
+	Latency   string `json:"latency"`
+	Jitter    string `json:"jitter"`
+	Server    string `json:"server,omitempty"`
+	Force     bool   `json:"force,omitempty"`
+}
+
+type DelayRuntime struct {
+}
+
+func (i *DelayInjector) GetArgs() interface{} {
+	return &i.Args
+}
+
+func (i *DelayInjector) GetRuntime() interface{} {
+	return &i.Runtime
+}
+
+func (i *DelayInjector) SetOption(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&i.Args.Interface, "interface", "i", "", "network interface which dns queries go out from. eg: eth0")
+	cmd.Flags().StringVarP(&i.Args.Latency, "latency", "l", "", "resolution delay time value, support unit: \"s、ms、us\"(default us)")
+	cmd.Flags().StringVarP(&i.Args.Jitter, "jitter", "j", "0", "jitter time value, support unit: \"s、ms、us\"(default us)")
+	cmd.Flags().StringVarP(&i.Args.Server, "server", "s", "", "only delay queries to these dns servers(default all). eg: 10.0.0.10,8.8.8.8")
+	cmd.Flags().BoolVarP(&i.Args.Force, "force", "f", false, "force will overwrite the network rule if old rule exist")
+}
+
+// Validator resolution delay is a tc rule on dns port, so it conflicts with other tc network faults
+func (i *DelayInjector) Validator(ctx context.Context) error {
+	if err := i.BaseInjector.Validator(ctx); err != nil {
+		return err
+	}
+
+	if i.Args.Latency == "" {
+		return fmt.Errorf("\"latency\" must provide")
+	}
+
+	if err := utils.CheckTimeValue(i.Args.Latency); err != nil {
+		return fmt.Errorf("\"latency\" is invalid: %s", err.Error())
+	}
+
+	if i.Args.Jitter != "" {
+		if err := utils.CheckTimeValue(i.Args.Jitter); err != nil {
+			return fmt.Errorf("\"jitter\" is invalid: %s", err.Error())
+		}
+	}
+
+	if !cmdexec.SupportCmd("tc") {
+		return fmt.Errorf("not support command \"tc\"")
+	}
+
+	if i.Args.Interface == "" {
+		return fmt.Errorf("\"interface\" is empty")
+	}
+
+	if i.Args.Server != "" {
+		if _, err := net.GetValidIPList(i.Args.Server, false); err != nil {
+			return fmt.Errorf("\"server\"[%s] is invalid: %s", i.Args.Server, err.Error())
+		}
+	}
+
+	exist, err := net.ExistTCRootQdisc(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Args.Interface)
+	if err != nil {
+		return fmt.Errorf("check tc rule error: %s", err.Error())
+	}
+
+	if exist && !i.Args.Force {
+		return fmt.Errorf("has other tc root rule, if want to force to execute, please provide [-f] or [--force] args")
+	}
+
+	return nil
+}
+
+func (i *DelayInjector) Inject(ctx context.Context) error {
+	cr, cId, netInterface := i.Info.ContainerRuntime, i.Info.ContainerId, i.Args.Interface
+	if i.Args.Force {
+		exist, _ := net.ExistTCRootQdisc(ctx, cr, cId, netInterface)
+		if exist {
+			if err := net.ClearTcRule(ctx, cr, cId, netInterface); err != nil {
+				return fmt.Errorf("reset tc rule for %s error: %s", netInterface, err.Error())
+			}
+		}
+	}
+
+	if err := net.AddPrioQdisc(ctx, cr, cId, netInterface, "", "1:"); err != nil {
+		return fmt.Errorf("add root prio qdisc for %s error: %s", netInterface, err.Error())
+	}
+
+	parent := "1:4"
+	if err := net.AddNetemQdisc(ctx, cr, cId, netInterface, parent, FaultDNSDelay, fmt.Sprintf("%s %s", i.Args.Latency, i.Args.Jitter)); err != nil {
+		return i.undoWithErr(ctx, fmt.Sprintf("add parent %s netem qdisc for %s error: %s", parent, netInterface, err.Error()))
+	}
+
+	if err := net.AddFilter(ctx, cr, cId, netInterface, parent, "", i.Args.Server, "", DNSPort, ""); err != nil {
+		return i.undoWithErr(ctx, fmt.Sprintf("add filter for %s error: %s", netInterface, err.Error()))
+	}
+
+	return nil
+}
+
+func (i *DelayInjector) Recover(ctx context.Context) error {
+	if i.BaseInjector.Recover(ctx) == nil {
+		return nil
+	}
+
+	exist, err := net.ExistTCRootQdisc(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Args.Interface)
+	if err != nil {
+		return fmt.Errorf("check tc rule exist error: %s", err.Error())
+	}
+
+	if exist {
+		return net.ClearTcRule(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Args.Interface)
+	}
+
+	return nil
+}
+
+func (i *DelayInjector) undoWithErr(ctx context.Context, msg string) error {
+	if err := net.ClearTcRule(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Args.Interface); err != nil {
+		log.GetLogger(ctx).Warnf("undo tc rule error: %s", err.Error())
+	}
+
+	return fmt.Errorf(msg)
+}
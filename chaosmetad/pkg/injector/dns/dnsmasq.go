@@ -0,0 +1,132 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package dns
+
+import (
+	"context"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/log"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/cmdexec"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/namespace"
+	"strings"
+)
+
+// dnsmasq runs in the net namespace of target and takes over the resolution by being the first nameserver
+
+var dnsmasqNs = []string{namespace.MNT, namespace.NET, namespace.PID}
+
+func getDomainList(domainStr string) []string {
+	var domains []string
+	for _, domain := range strings.Split(domainStr, DomainListSplit) {
+		domain = strings.TrimSpace(domain)
+		if domain != "" {
+			domains = append(domains, domain)
+		}
+	}
+
+	return domains
+}
+
+func checkIntercept(intercept string) error {
+	if intercept != InterceptHosts && intercept != InterceptDnsmasq {
+		return fmt.Errorf("args \"intercept\" only support: %s, %s", InterceptHosts, InterceptDnsmasq)
+	}
+
+	return nil
+}
+
+func checkDnsmasq(ctx context.Context, cr, cId string) error {
+	if _, err := cmdexec.ExecCommonWithNS(ctx, cr, cId, "command -v dnsmasq", []string{namespace.MNT}); err != nil {
+		return fmt.Errorf("not support command \"dnsmasq\"")
+	}
+
+	return nil
+}
+
+// getUpstreamServers returns the nameservers which dnsmasq forwards the not intercepted queries to
+func getUpstreamServers(ctx context.Context, cr, cId string) ([]string, error) {
+	re, err := cmdexec.ExecCommonWithNS(ctx, cr, cId, fmt.Sprintf("grep '^nameserver' %s", ConfServer), []string{namespace.MNT})
+	if err != nil {
+		return nil, fmt.Errorf("get nameserver from %s error: %s", ConfServer, err.Error())
+	}
+
+	var servers []string
+	for _, line := range strings.Split(re, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || fields[1] == DnsmasqListenIp {
+			continue
+		}
+		servers = append(servers, fields[1])
+	}
+
+	return servers, nil
+}
+
+// getDnsmasqStartCmd ip is empty means return NXDOMAIN for the domains
+func getDnsmasqStartCmd(uid string, domains []string, ip string, upstreams []string) string {
+	var args []string
+	for _, server := range upstreams {
+		args = append(args, fmt.Sprintf("--server=%s", server))
+	}
+
+	for _, domain := range domains {
+		args = append(args, fmt.Sprintf("--address=/%s/%s", domain, ip))
+	}
+
+	return fmt.Sprintf("dnsmasq --listen-address=%s --bind-interfaces --port=%s --no-resolv --user=root --pid-file=%s %s",
+		DnsmasqListenIp, DNSPort, fmt.Sprintf(DnsmasqPidFile, uid), strings.Join(args, " "))
+}
+
+func getDnsmasqStopCmd(uid string) string {
+	pidFile := fmt.Sprintf(DnsmasqPidFile, uid)
+	return fmt.Sprintf("if [ -f %s ]; then cat %s | xargs kill; rm -f %s; fi", pidFile, pidFile, pidFile)
+}
+
+func startDnsmasq(ctx context.Context, cr, cId, uid string, domains []string, ip string) error {
+	upstreams, err := getUpstreamServers(ctx, cr, cId)
+	if err != nil {
+		return err
+	}
+
+	if _, err := cmdexec.ExecCommonWithNS(ctx, cr, cId, getDnsmasqStartCmd(uid, domains, ip, upstreams), dnsmasqNs); err != nil {
+		return fmt.Errorf("start dnsmasq error: %s", err.Error())
+	}
+
+	if _, err := cmdexec.ExecCommonWithNS(ctx, cr, cId, getServerAddInjectCmd(uid, DnsmasqListenIp), []string{namespace.MNT}); err != nil {
+		if err := stopDnsmasq(ctx, cr, cId, uid); err != nil {
+			log.GetLogger(ctx).Warnf("undo: stop dnsmasq error: %s", err.Error())
+		}
+		return fmt.Errorf("add dnsmasq to %s error: %s", ConfServer, err.Error())
+	}
+
+	return nil
+}
+
+func stopDnsmasq(ctx context.Context, cr, cId, uid string) error {
+	if _, err := cmdexec.ExecCommonWithNS(ctx, cr, cId, getDnsmasqStopCmd(uid), dnsmasqNs); err != nil {
+		return fmt.Errorf("stop dnsmasq error: %s", err.Error())
+	}
+
+	return nil
+}
+
+func recoverDnsmasq(ctx context.Context, cr, cId, uid string) error {
+	if _, err := cmdexec.ExecCommonWithNS(ctx, cr, cId, getServerAddRecoverCmd(uid), []string{namespace.MNT}); err != nil {
+		return fmt.Errorf("remove dnsmasq from %s error: %s", ConfServer, err.Error())
+	}
+
+	return stopDnsmasq(ctx, cr, cId, uid)
+}
@@ -0,0 +1,123 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package dns
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/log"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/cmdexec"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/namespace"
+	"net"
+)
+
+func init() {
+	injector.Register(TargetDNS, FaultDNSHijack, func() injector.IInjector { return &HijackInjector{} })
+}
+
+type HijackInjector struct {
+	injector.BaseInjector
+	Args    HijackArgs
+	Runtime HijackRuntime
+}
+
+type HijackArgs struct {
+	Domain    string `json:"domain"`
+	Ip        string `json:"ip"`
+	Intercept string `json:"intercept"`
+}
+
+type HijackRuntime struct {
+}
+
+func (i *HijackInjector) GetArgs() interface{} {
+	return &i.Args
+}
+
+func (i *HijackInjector) GetRuntime() interface{} {
+	return &i.Runtime
+}
+
+func (i *HijackInjector) SetDefault() {
+	i.BaseInjector.SetDefault()
+
+	if i.Args.Intercept == "" {
+		i.Args.Intercept = InterceptHosts
+	}
+}
+
+func (i *HijackInjector) SetOption(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&i.Args.Domain, "domain", "d", "", "domains to hijack, eg: a.com,b.com. dnsmasq also matches their subdomains")
+	cmd.Flags().StringVarP(&i.Args.Ip, "ip", "i", "", "the ip which domains are resolved to")
+	cmd.Flags().StringVar(&i.Args.Intercept, "intercept", "", fmt.Sprintf("intercept mode, support: %s(default), %s", InterceptHosts, InterceptDnsmasq))
+}
+
+func (i *HijackInjector) Validator(ctx context.Context) error {
+	if err := i.BaseInjector.Validator(ctx); err != nil {
+		return err
+	}
+
+	if len(getDomainList(i.Args.Domain)) == 0 {
+		return fmt.Errorf("must provide args \"domain\"")
+	}
+
+	if net.ParseIP(i.Args.Ip) == nil {
+		return fmt.Errorf("args \"ip\"[%s] is not a valid ip", i.Args.Ip)
+	}
+
+	if err := checkIntercept(i.Args.Intercept); err != nil {
+		return err
+	}
+
+	if i.Args.Intercept == InterceptDnsmasq {
+		return checkDnsmasq(ctx, i.Info.ContainerRuntime, i.Info.ContainerId)
+	}
+
+	return nil
+}
+
+func (i *HijackInjector) Inject(ctx context.Context) error {
+	domains := getDomainList(i.Args.Domain)
+	if i.Args.Intercept == InterceptDnsmasq {
+		return startDnsmasq(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Info.Uid, domains, i.Args.Ip)
+	}
+
+	for _, domain := range domains {
+		if _, err := cmdexec.ExecCommonWithNS(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, getRecordAddInjectCmd(i.Info.Uid, domain, i.Args.Ip), []string{namespace.MNT}); err != nil {
+			if err := i.Recover(ctx); err != nil {
+				log.GetLogger(ctx).Warnf("undo: recover hosts record error: %s", err.Error())
+			}
+			return fmt.Errorf("add hosts record for %s error: %s", domain, err.Error())
+		}
+	}
+
+	return nil
+}
+
+func (i *HijackInjector) Recover(ctx context.Context) error {
+	if i.BaseInjector.Recover(ctx) == nil {
+		return nil
+	}
+
+	if i.Args.Intercept == InterceptDnsmasq {
+		return recoverDnsmasq(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Info.Uid)
+	}
+
+	_, err := cmdexec.ExecCommonWithNS(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, getRecordAddRecoverCmd(i.Info.Uid), []string{namespace.MNT})
+	return err
+}
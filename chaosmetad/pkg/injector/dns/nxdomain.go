@@ -0,0 +1,91 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package dns
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
+)
+
+func init() {
+	injector.Register(TargetDNS, FaultDNSNxdomain, func() injector.IInjector { return &NxdomainInjector{} })
+}
+
+type NxdomainInjector struct {
+	injector.BaseInjector
+	Args    NxdomainArgs
+	Runtime NxdomainRuntime
+}
+
+type NxdomainArgs struct {
+	Domain    string `json:"domain"`
+	Intercept string `json:"intercept"`
+}
+
+type NxdomainRuntime struct {
+}
+
+func (i *NxdomainInjector) GetArgs() interface{} {
+	return &i.Args
+}
+
+func (i *NxdomainInjector) GetRuntime() interface{} {
+	return &i.Runtime
+}
+
+func (i *NxdomainInjector) SetDefault() {
+	i.BaseInjector.SetDefault()
+
+	if i.Args.Intercept == "" {
+		i.Args.Intercept = InterceptDnsmasq
+	}
+}
+
+func (i *NxdomainInjector) SetOption(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&i.Args.Domain, "domain", "d", "", "domains and their subdomains to return NXDOMAIN, eg: a.com,b.com")
+	cmd.Flags().StringVar(&i.Args.Intercept, "intercept", "", fmt.Sprintf("intercept mode, support: %s(default)", InterceptDnsmasq))
+}
+
+// Validator hosts file can not express a nonexistent domain, so only dnsmasq is supported
+func (i *NxdomainInjector) Validator(ctx context.Context) error {
+	if err := i.BaseInjector.Validator(ctx); err != nil {
+		return err
+	}
+
+	if len(getDomainList(i.Args.Domain)) == 0 {
+		return fmt.Errorf("must provide args \"domain\"")
+	}
+
+	if i.Args.Intercept != InterceptDnsmasq {
+		return fmt.Errorf("args \"intercept\" only support: %s", InterceptDnsmasq)
+	}
+
+	return checkDnsmasq(ctx, i.Info.ContainerRuntime, i.Info.ContainerId)
+}
+
+func (i *NxdomainInjector) Inject(ctx context.Context) error {
+	return startDnsmasq(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Info.Uid, getDomainList(i.Args.Domain), "")
+}
+
+func (i *NxdomainInjector) Recover(ctx context.Context) error {
+	if i.BaseInjector.Recover(ctx) == nil {
+		return nil
+	}
+
+	return recoverDnsmasq(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Info.Uid)
+}
@@ -21,6 +21,8 @@ import (
 	"fmt"
 	"github.com/spf13/cobra"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/log"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/filesys"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/process"
 	"strings"
@@ -42,6 +44,7 @@ type AppendArgs struct {
 	Raw      bool   `json:"raw,omitempty"`
 	Count    int    `json:"count,omitempty"`
 	Interval int    `json:"interval,omitempty"`
+	Garbage  string `json:"garbage,omitempty"`
 }
 
 type AppendRuntime struct {
@@ -69,6 +72,7 @@ func (i *AppendInjector) SetOption(cmd *cobra.Command) {
 	cmd.Flags().BoolVarP(&i.Args.Raw, "raw", "r", false, "if raw content, raw content can not recover")
 	cmd.Flags().IntVarP(&i.Args.Count, "count", "C", 1, "repeat times")
 	cmd.Flags().IntVarP(&i.Args.Interval, "interval", "i", 0, "repeat interval, unit is second")
+	cmd.Flags().StringVarP(&i.Args.Garbage, "garbage", "g", "", "append random bytes of this size instead of content, the file is backed up and restored when recovering, support unit: B、KB、MB、GB、TB(default B)")
 }
 
 func (i *AppendInjector) Validator(ctx context.Context) error {
@@ -92,7 +96,20 @@ func (i *AppendInjector) Validator(ctx context.Context) error {
 		return fmt.Errorf("\"path\" must provide absolute path")
 	}
 
-	if i.Args.Content == "" {
+	if i.Args.Garbage != "" {
+		if i.Args.Content != "" {
+			return fmt.Errorf("\"content\" and \"garbage\" can not be provided at the same time")
+		}
+
+		size, err := utils.GetBytes(i.Args.Garbage)
+		if err != nil {
+			return fmt.Errorf("\"garbage\"[%s] is invalid: %s", i.Args.Garbage, err.Error())
+		}
+
+		if size <= 0 {
+			return fmt.Errorf("\"garbage\" must larger than 0")
+		}
+	} else if i.Args.Content == "" {
 		return fmt.Errorf("\"content\" can not be empty")
 	}
 
@@ -109,6 +126,10 @@ func (i *AppendInjector) Validator(ctx context.Context) error {
 }
 
 func (i *AppendInjector) Inject(ctx context.Context) error {
+	if i.Args.Garbage != "" {
+		return i.injectGarbage(ctx)
+	}
+
 	flag := getAppendFlag(i.Info.Uid)
 
 	if !i.Args.Raw {
@@ -133,6 +154,10 @@ func (i *AppendInjector) Recover(ctx context.Context) error {
 		return fmt.Errorf("kill append process with key[%s] error: %s", getAppendFlag(i.Info.Uid), err.Error())
 	}
 
+	if i.Args.Garbage != "" {
+		return restoreFile(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Info.Uid, i.Args.Path)
+	}
+
 	if i.Args.Raw {
 		return nil
 	}
@@ -148,3 +173,20 @@ func (i *AppendInjector) Recover(ctx context.Context) error {
 
 	return nil
 }
+
+func (i *AppendInjector) injectGarbage(ctx context.Context) error {
+	if err := backupFile(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Info.Uid, i.Args.Path); err != nil {
+		return err
+	}
+
+	size, _ := utils.GetBytes(i.Args.Garbage)
+	if err := filesys.AppendRandomBytes(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Args.Path, size); err != nil {
+		if err := restoreFile(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Info.Uid, i.Args.Path); err != nil {
+			log.GetLogger(ctx).Warnf("undo error: %s", err.Error())
+		}
+
+		return fmt.Errorf("append garbage to %s error: %s", i.Args.Path, err.Error())
+	}
+
+	return nil
+}
@@ -0,0 +1,60 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package file
+
+import (
+	"context"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/filesys"
+	"path/filepath"
+)
+
+func getBackupFile(uid, path string) string {
+	return fmt.Sprintf("%s/%s", getBackupDir(uid), filepath.Base(path))
+}
+
+// backupFile copies path into the backup dir of the experiment before it is mutated
+func backupFile(ctx context.Context, cr, cId, uid, path string) error {
+	backupDir := getBackupDir(uid)
+	if err := filesys.MkdirForce(ctx, cr, cId, backupDir); err != nil {
+		return fmt.Errorf("create backup dir[%s] error: %s", backupDir, err.Error())
+	}
+
+	backup := getBackupFile(uid, path)
+	if err := filesys.CopyFile(ctx, cr, cId, path, backup); err != nil {
+		return fmt.Errorf("backup file[%s] to[%s] error: %s", path, backup, err.Error())
+	}
+
+	return nil
+}
+
+// restoreFile copies the backup over path and removes the backup dir, nothing is done if there is no backup
+func restoreFile(ctx context.Context, cr, cId, uid, path string) error {
+	backup := getBackupFile(uid, path)
+	exist, err := filesys.CheckFile(ctx, cr, cId, backup)
+	if err != nil {
+		return fmt.Errorf("check exist file[%s] error: %s", backup, err.Error())
+	}
+
+	if exist {
+		if err := filesys.CopyFile(ctx, cr, cId, backup, path); err != nil {
+			return fmt.Errorf("restore file[%s] from[%s] error: %s", path, backup, err.Error())
+		}
+	}
+
+	return filesys.RemoveRF(ctx, cr, cId, getBackupDir(uid))
+}
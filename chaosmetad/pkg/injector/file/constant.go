@@ -33,6 +33,16 @@ const (
 	FaultFileDelete = "del"
 
 	FaultFileChmod = "chmod"
+
+	FaultFileCorrupt = "corrupt"
+
+	FaultFileTruncate = "truncate"
+
+	FaultFileFlood       = "flood"
+	DefaultFloodRate     = 100
+	DefaultFloodLineSize = "1KB"
+	MaxFloodLineSize     = 1024 * 1024
+	ContainerStdoutPath  = "/proc/1/fd/1"
 	//FileExec       = "chaosmeta_file"
 
 	BackUpDir = "/tmp/chaosmeta_backup_file"
@@ -42,6 +52,10 @@ func getAppendFlag(uid string) string {
 	return fmt.Sprintf(" %s-%s", utils.RootName, uid)
 }
 
+func getFloodFlag(uid string) string {
+	return fmt.Sprintf("%s-flood-%s", utils.RootName, uid)
+}
+
 func getBackupDir(uid string) string {
 	return fmt.Sprintf("%s%s", BackUpDir, uid)
 }
@@ -0,0 +1,178 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package file
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/log"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/filesys"
+	"math/rand"
+	"time"
+)
+
+func init() {
+	injector.Register(TargetFile, FaultFileCorrupt, func() injector.IInjector { return &CorruptInjector{} })
+}
+
+type CorruptInjector struct {
+	injector.BaseInjector
+	Args    CorruptArgs
+	Runtime CorruptRuntime
+}
+
+type CorruptArgs struct {
+	Path   string `json:"path"`
+	Size   string `json:"size,omitempty"`
+	Offset string `json:"offset,omitempty"`
+}
+
+type CorruptRuntime struct {
+	Offset int64 `json:"offset,omitempty"`
+	Size   int64 `json:"size,omitempty"`
+}
+
+func (i *CorruptInjector) GetArgs() interface{} {
+	return &i.Args
+}
+
+func (i *CorruptInjector) GetRuntime() interface{} {
+	return &i.Runtime
+}
+
+func (i *CorruptInjector) SetDefault() {
+	i.BaseInjector.SetDefault()
+
+	if i.Args.Size == "" {
+		i.Args.Size = "1KB"
+	}
+}
+
+func (i *CorruptInjector) SetOption(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&i.Args.Path, "path", "p", "", "file path, include dir and file name")
+	cmd.Flags().StringVarP(&i.Args.Size, "size", "s", "", "size of the corrupted bytes, support unit: B、KB、MB、GB、TB(default B), default 1KB")
+	cmd.Flags().StringVarP(&i.Args.Offset, "offset", "o", "", "offset of the corrupted bytes, support unit: B、KB、MB、GB、TB(default B), empty means a random offset")
+}
+
+func (i *CorruptInjector) Validator(ctx context.Context) error {
+	if err := i.BaseInjector.Validator(ctx); err != nil {
+		return err
+	}
+
+	if i.Args.Path == "" {
+		return fmt.Errorf("\"path\" is empty")
+	}
+
+	if !filesys.IfPathAbs(ctx, i.Args.Path) {
+		return fmt.Errorf("\"path\" must provide absolute path")
+	}
+
+	size, err := utils.GetBytes(i.Args.Size)
+	if err != nil {
+		return fmt.Errorf("\"size\"[%s] is invalid: %s", i.Args.Size, err.Error())
+	}
+
+	if size <= 0 {
+		return fmt.Errorf("\"size\" must larger than 0")
+	}
+
+	var offset int64 = -1
+	if i.Args.Offset != "" {
+		offset, err = utils.GetBytes(i.Args.Offset)
+		if err != nil {
+			return fmt.Errorf("\"offset\"[%s] is invalid: %s", i.Args.Offset, err.Error())
+		}
+
+		if offset < 0 {
+			return fmt.Errorf("\"offset\" can not less than 0")
+		}
+	}
+
+	exist, err := filesys.CheckFile(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Args.Path)
+	if err != nil {
+		return fmt.Errorf("check exist file[%s] error: %s", i.Args.Path, err.Error())
+	}
+
+	if !exist {
+		return fmt.Errorf("file[%s] is not exist", i.Args.Path)
+	}
+
+	fileSize, err := filesys.GetFileSize(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Args.Path)
+	if err != nil {
+		return fmt.Errorf("get size of file[%s] error: %s", i.Args.Path, err.Error())
+	}
+
+	if fileSize == 0 {
+		return fmt.Errorf("file[%s] is empty", i.Args.Path)
+	}
+
+	if offset >= fileSize {
+		return fmt.Errorf("\"offset\"[%d] is out of file size[%d]", offset, fileSize)
+	}
+
+	return nil
+}
+
+func (i *CorruptInjector) Inject(ctx context.Context) error {
+	fileSize, err := filesys.GetFileSize(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Args.Path)
+	if err != nil {
+		return fmt.Errorf("get size of file[%s] error: %s", i.Args.Path, err.Error())
+	}
+
+	size, _ := utils.GetBytes(i.Args.Size)
+	var offset int64 = -1
+	if i.Args.Offset != "" {
+		offset, _ = utils.GetBytes(i.Args.Offset)
+	}
+
+	if offset < 0 {
+		if size > fileSize {
+			size = fileSize
+		}
+		offset = rand.New(rand.NewSource(time.Now().UnixNano())).Int63n(fileSize - size + 1)
+	}
+
+	if offset+size > fileSize {
+		size = fileSize - offset
+	}
+
+	i.Runtime.Offset, i.Runtime.Size = offset, size
+	if err := backupFile(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Info.Uid, i.Args.Path); err != nil {
+		return err
+	}
+
+	if err := filesys.WriteRandomBytes(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Args.Path, offset, size); err != nil {
+		if err := i.Recover(ctx); err != nil {
+			log.GetLogger(ctx).Warnf("undo error: %s", err.Error())
+		}
+
+		return fmt.Errorf("corrupt file[%s] error: %s", i.Args.Path, err.Error())
+	}
+
+	return nil
+}
+
+func (i *CorruptInjector) Recover(ctx context.Context) error {
+	if i.BaseInjector.Recover(ctx) == nil {
+		return nil
+	}
+
+	return restoreFile(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Info.Uid, i.Args.Path)
+}
@@ -0,0 +1,181 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package file
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/cmdexec"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/filesys"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/process"
+	"path/filepath"
+)
+
+// Garbage log lines are written by a background loop until recover, every line ends with a flag of the experiment,
+// so that only the generated lines are deleted when recovering
+func init() {
+	injector.Register(TargetFile, FaultFileFlood, func() injector.IInjector { return &FloodInjector{} })
+}
+
+type FloodInjector struct {
+	injector.BaseInjector
+	Args    FloodArgs
+	Runtime FloodRuntime
+}
+
+type FloodArgs struct {
+	Path     string `json:"path,omitempty"`
+	Rate     int    `json:"rate,omitempty"`
+	LineSize string `json:"line_size,omitempty"`
+}
+
+type FloodRuntime struct {
+	Created bool `json:"created,omitempty"`
+}
+
+func (i *FloodInjector) GetArgs() interface{} {
+	return &i.Args
+}
+
+func (i *FloodInjector) GetRuntime() interface{} {
+	return &i.Runtime
+}
+
+func (i *FloodInjector) SetDefault() {
+	i.BaseInjector.SetDefault()
+
+	if i.Args.Rate == 0 {
+		i.Args.Rate = DefaultFloodRate
+	}
+
+	if i.Args.LineSize == "" {
+		i.Args.LineSize = DefaultFloodLineSize
+	}
+
+	if i.Args.Path == "" && i.Info.ContainerRuntime != "" {
+		i.Args.Path = ContainerStdoutPath
+	}
+}
+
+func (i *FloodInjector) SetOption(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&i.Args.Path, "path", "p", "", fmt.Sprintf("log file to flood, created if not exist, default is the stdout of the container[%s] in container mode", ContainerStdoutPath))
+	cmd.Flags().IntVarP(&i.Args.Rate, "rate", "r", 0, fmt.Sprintf("lines written per second, default %d", DefaultFloodRate))
+	cmd.Flags().StringVarP(&i.Args.LineSize, "line-size", "s", "", fmt.Sprintf("size of every line, support unit: B、KB、MB(default B), default %s", DefaultFloodLineSize))
+}
+
+func (i *FloodInjector) Validator(ctx context.Context) error {
+	if err := i.BaseInjector.Validator(ctx); err != nil {
+		return err
+	}
+
+	if i.Args.Path == "" {
+		return fmt.Errorf("\"path\" can not be empty")
+	}
+
+	if !filesys.IfPathAbs(ctx, i.Args.Path) {
+		return fmt.Errorf("\"path\" must provide absolute path")
+	}
+
+	if i.Args.Rate <= 0 {
+		return fmt.Errorf("\"rate\" must larger than 0")
+	}
+
+	size, err := utils.GetBytes(i.Args.LineSize)
+	if err != nil {
+		return fmt.Errorf("\"line-size\"[%s] is invalid: %s", i.Args.LineSize, err.Error())
+	}
+
+	if size <= 0 || size > MaxFloodLineSize {
+		return fmt.Errorf("\"line-size\" must in (0, %d]", MaxFloodLineSize)
+	}
+
+	if i.Args.Path == ContainerStdoutPath {
+		return nil
+	}
+
+	dir := filepath.Dir(i.Args.Path)
+	dirExist, err := filesys.CheckDir(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, dir)
+	if err != nil {
+		return fmt.Errorf("check exist dir[%s] error: %s", dir, err.Error())
+	}
+
+	if !dirExist {
+		return fmt.Errorf("dir[%s] is not exist", dir)
+	}
+
+	return nil
+}
+
+func (i *FloodInjector) Inject(ctx context.Context) error {
+	if i.Args.Path != ContainerStdoutPath {
+		exist, err := filesys.CheckFile(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Args.Path)
+		if err != nil {
+			return fmt.Errorf("check exist file[%s] error: %s", i.Args.Path, err.Error())
+		}
+
+		i.Runtime.Created = !exist
+	}
+
+	size, _ := utils.GetBytes(i.Args.LineSize)
+	cmd := cmdexec.GetExecnsBashCmd(i.Info.ContainerRuntime, getFloodCmd(getFloodFlag(i.Info.Uid), i.Args.Path, i.Args.Rate, size))
+	if err := cmdexec.ExecBackGroundCommon(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, cmd); err != nil {
+		return fmt.Errorf("start flood process of %s error: %s", i.Args.Path, err.Error())
+	}
+
+	return nil
+}
+
+func (i *FloodInjector) Recover(ctx context.Context) error {
+	if i.BaseInjector.Recover(ctx) == nil {
+		return nil
+	}
+
+	flag := getFloodFlag(i.Info.Uid)
+	if err := process.CheckExistAndKillByKey(ctx, flag); err != nil {
+		return fmt.Errorf("kill flood process with key[%s] error: %s", flag, err.Error())
+	}
+
+	// the stdout is collected by the container runtime, which can not be cleaned by the agent
+	if i.Args.Path == ContainerStdoutPath {
+		return nil
+	}
+
+	exist, err := filesys.CheckFile(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Args.Path)
+	if err != nil {
+		return fmt.Errorf("check exist file[%s] error: %s", i.Args.Path, err.Error())
+	}
+
+	if !exist {
+		return nil
+	}
+
+	if i.Runtime.Created {
+		return filesys.RemoveFile(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Args.Path)
+	}
+
+	return filesys.DeleteLineByKey(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Args.Path, flag)
+}
+
+// getFloodCmd generates one random line and writes it "rate" times every second, write errors such as a full disk are
+// ignored so that the flooding goes on
+func getFloodCmd(flag, path string, rate int, size int64) string {
+	return fmt.Sprintf(": %s; line=$(head -c %d /dev/urandom | base64 -w0 | head -c %d); while true; do for ((j=0;j<%d;j++)); do echo \"${line} %s\"; done >> %s 2>/dev/null; sleep 1; done",
+		flag, size, size, rate, flag, path)
+}
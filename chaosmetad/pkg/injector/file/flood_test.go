@@ -0,0 +1,64 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package file
+
+import (
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/cmdexec"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func Test_getFloodCmdInContainer(t *testing.T) {
+	var (
+		path = filepath.Join(t.TempDir(), "flood.log")
+		flag = getFloodFlag("test")
+		size = 16
+	)
+
+	// the same quoting as the execns command, the shell in the container is simulated by bash
+	cmd := cmdexec.GetExecnsBashCmd("docker", getFloodCmd(flag, path, 3, int64(size)))
+	c := exec.Command("/bin/bash", "-c", fmt.Sprintf("/bin/bash -c \"%s\"", cmd))
+	c.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	if err := c.Start(); err != nil {
+		t.Fatalf("start flood error: %s", err.Error())
+	}
+	time.Sleep(500 * time.Millisecond)
+	_ = syscall.Kill(-c.Process.Pid, syscall.SIGKILL)
+	_ = c.Wait()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read flood file error: %s", err.Error())
+	}
+
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("flood %d lines in the first second, want 3: %q", len(lines), data)
+	}
+
+	for _, line := range lines {
+		if len(line) != size+1+len(flag) || line != lines[0] || !strings.HasSuffix(line, " "+flag) {
+			t.Errorf("flood line = %q, want the same random line of %d bytes and the flag", line, size)
+		}
+	}
+}
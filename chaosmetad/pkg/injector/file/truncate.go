@@ -0,0 +1,125 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package file
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/log"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/filesys"
+)
+
+func init() {
+	injector.Register(TargetFile, FaultFileTruncate, func() injector.IInjector { return &TruncateInjector{} })
+}
+
+type TruncateInjector struct {
+	injector.BaseInjector
+	Args    TruncateArgs
+	Runtime TruncateRuntime
+}
+
+type TruncateArgs struct {
+	Path string `json:"path"`
+	Size string `json:"size,omitempty"`
+}
+
+type TruncateRuntime struct {
+}
+
+func (i *TruncateInjector) GetArgs() interface{} {
+	return &i.Args
+}
+
+func (i *TruncateInjector) GetRuntime() interface{} {
+	return &i.Runtime
+}
+
+func (i *TruncateInjector) SetDefault() {
+	i.BaseInjector.SetDefault()
+
+	if i.Args.Size == "" {
+		i.Args.Size = "0"
+	}
+}
+
+func (i *TruncateInjector) SetOption(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&i.Args.Path, "path", "p", "", "file path, include dir and file name")
+	cmd.Flags().StringVarP(&i.Args.Size, "size", "s", "", "size of the file after truncating, support unit: B、KB、MB、GB、TB(default B), default 0")
+}
+
+func (i *TruncateInjector) Validator(ctx context.Context) error {
+	if err := i.BaseInjector.Validator(ctx); err != nil {
+		return err
+	}
+
+	if i.Args.Path == "" {
+		return fmt.Errorf("\"path\" is empty")
+	}
+
+	if !filesys.IfPathAbs(ctx, i.Args.Path) {
+		return fmt.Errorf("\"path\" must provide absolute path")
+	}
+
+	size, err := utils.GetBytes(i.Args.Size)
+	if err != nil {
+		return fmt.Errorf("\"size\"[%s] is invalid: %s", i.Args.Size, err.Error())
+	}
+
+	if size < 0 {
+		return fmt.Errorf("\"size\" can not less than 0")
+	}
+
+	exist, err := filesys.CheckFile(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Args.Path)
+	if err != nil {
+		return fmt.Errorf("check exist file[%s] error: %s", i.Args.Path, err.Error())
+	}
+
+	if !exist {
+		return fmt.Errorf("file[%s] is not exist", i.Args.Path)
+	}
+
+	return nil
+}
+
+func (i *TruncateInjector) Inject(ctx context.Context) error {
+	if err := backupFile(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Info.Uid, i.Args.Path); err != nil {
+		return err
+	}
+
+	size, _ := utils.GetBytes(i.Args.Size)
+	if err := filesys.TruncateFile(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Args.Path, size); err != nil {
+		if err := i.Recover(ctx); err != nil {
+			log.GetLogger(ctx).Warnf("undo error: %s", err.Error())
+		}
+
+		return fmt.Errorf("truncate file[%s] to size[%d] error: %s", i.Args.Path, size, err.Error())
+	}
+
+	return nil
+}
+
+func (i *TruncateInjector) Recover(ctx context.Context) error {
+	if i.BaseInjector.Recover(ctx) == nil {
+		return nil
+	}
+
+	return restoreFile(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Info.Uid, i.Args.Path)
+}
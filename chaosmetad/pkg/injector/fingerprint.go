@@ -0,0 +1,82 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package injector
+
+import (
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/storage"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+)
+
+// IFingerprint is implemented by the faults occupying an exclusive resource, such as the tc root qdisc of an interface
+// or the redirect rule of a port. The same resource can not be injected twice, and the partially applied rules of a
+// failed experiment can be cleaned by recover, because the recover of these faults checks the resource before undo
+type IFingerprint interface {
+	GetFingerprint() string
+	SetCleanPartial()
+}
+
+// SetCleanPartial makes Recover undo the rules of a failed experiment instead of skipping it
+func (i *BaseInjector) SetCleanPartial() {
+	i.cleanPartial = true
+}
+
+// GetResourceFingerprint the fingerprint is "[container id or host]/[kind]/[resource]"
+func (i *BaseInjector) GetResourceFingerprint(kind, resource string) string {
+	scope := "host"
+	if i.Info.ContainerId != "" {
+		scope = i.Info.ContainerId
+	}
+
+	return fmt.Sprintf("%s/%s/%s", scope, kind, resource)
+}
+
+func getFingerprint(i IInjector) string {
+	if f, ok := i.(IFingerprint); ok {
+		return f.GetFingerprint()
+	}
+
+	return ""
+}
+
+// checkFingerprint rejects the fault if an experiment in progress occupies the same resource
+func checkFingerprint(fingerprint string) error {
+	if fingerprint == "" {
+		return nil
+	}
+
+	db, err := storage.GetExperimentStore()
+	if err != nil {
+		return fmt.Errorf("connect db error: %s", err.Error())
+	}
+
+	exps, _, err := db.QueryByFilter(&storage.ExperimentFilter{
+		Fingerprint: fingerprint,
+		Status:      []string{utils.StatusCreated, utils.StatusSuccess, utils.StatusIdle, utils.StatusPaused},
+		Limit:       1,
+	})
+	if err != nil {
+		return fmt.Errorf("query experiments by fingerprint error: %s", err.Error())
+	}
+
+	if len(exps) > 0 {
+		return fmt.Errorf("resource[%s] is occupied by %s experiment[%s] of %s %s, please recover it first",
+			fingerprint, exps[0].Status, exps[0].Uid, exps[0].Target, exps[0].Fault)
+	}
+
+	return nil
+}
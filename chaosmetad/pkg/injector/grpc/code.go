@@ -0,0 +1,96 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector/http"
+)
+
+func init() {
+	injector.Register(TargetGRPC, FaultGRPCCode, func() injector.IInjector { return &CodeInjector{} })
+}
+
+type CodeInjector struct {
+	injector.BaseInjector
+	Args    CodeArgs
+	Runtime CodeRuntime
+}
+
+type CodeArgs struct {
+	MethodArgs
+	Code int `json:"code"`
+}
+
+type CodeRuntime struct {
+	http.ProxyRuntime
+}
+
+func (i *CodeInjector) GetArgs() interface{} {
+	return &i.Args
+}
+
+func (i *CodeInjector) GetRuntime() interface{} {
+	return &i.Runtime
+}
+
+func (i *CodeInjector) SetDefault() {
+	i.BaseInjector.SetDefault()
+
+	setMethodDefault(&i.Args.MethodArgs)
+}
+
+func (i *CodeInjector) SetOption(cmd *cobra.Command) {
+	setMethodOption(cmd, &i.Args.MethodArgs)
+	cmd.Flags().IntVarP(&i.Args.Code, "code", "c", 0, "grpc status code the calls fail with, eg: 14(UNAVAILABLE)")
+}
+
+func (i *CodeInjector) Validator(ctx context.Context) error {
+	if err := i.BaseInjector.Validator(ctx); err != nil {
+		return err
+	}
+
+	if i.Args.Code < 1 || i.Args.Code > MaxStatusCode {
+		return fmt.Errorf("\"code\" must in [1, %d]", MaxStatusCode)
+	}
+
+	return validateMethodArgs(ctx, &i.BaseInjector, &i.Args.MethodArgs)
+}
+
+func (i *CodeInjector) Inject(ctx context.Context) error {
+	proxyPort, err := http.StartProxy(ctx, &i.BaseInjector, &i.Args.ProxyArgs, http.ModeGRPCCode, fmt.Sprintf("%d", i.Args.Code))
+	if err != nil {
+		return err
+	}
+
+	i.Runtime.ProxyPort = proxyPort
+	return nil
+}
+
+func (i *CodeInjector) Recover(ctx context.Context) error {
+	if i.BaseInjector.Recover(ctx) == nil {
+		return nil
+	}
+
+	return http.StopProxy(ctx, &i.BaseInjector, &i.Args.ProxyArgs, i.Runtime.ProxyPort)
+}
+
+func (i *CodeInjector) GetFingerprint() string {
+	return http.GetProxyFingerprint(&i.BaseInjector, &i.Args.ProxyArgs)
+}
@@ -0,0 +1,64 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector/http"
+	"strings"
+)
+
+// MethodArgs selects the grpc calls by the full service name and an optional method name, grpc calls are http/2
+// requests whose path is "/{service}/{method}", so that the http proxy is reused
+type MethodArgs struct {
+	http.ProxyArgs
+	Service string `json:"service"`
+	Method  string `json:"method,omitempty"`
+}
+
+func setMethodDefault(args *MethodArgs) {
+	args.Path = getMethodPath(args.Service, args.Method)
+	http.SetProxyDefault(&args.ProxyArgs)
+}
+
+func setMethodOption(cmd *cobra.Command, args *MethodArgs) {
+	http.SetProxyPortOption(cmd, &args.ProxyArgs)
+	cmd.Flags().StringVarP(&args.Service, "service", "s", "", "full name of the grpc service, eg: helloworld.Greeter")
+	cmd.Flags().StringVarP(&args.Method, "method", "m", "", "grpc method name, eg: SayHello(default all methods of the service)")
+}
+
+func validateMethodArgs(ctx context.Context, info *injector.BaseInjector, args *MethodArgs) error {
+	if args.Service == "" {
+		return fmt.Errorf("\"service\" must provide")
+	}
+
+	if strings.ContainsAny(args.Service, "/ ") || strings.ContainsAny(args.Method, "/ ") {
+		return fmt.Errorf("\"service\" and \"method\" can not contain \"/\" or space")
+	}
+
+	return http.ValidateProxyArgs(ctx, info, &args.ProxyArgs)
+}
+
+func getMethodPath(service, method string) string {
+	if service == "" {
+		return ""
+	}
+
+	return fmt.Sprintf("/%s/%s", service, method)
+}
@@ -0,0 +1,68 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package injector
+
+import (
+	"context"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/log"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/storage"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/cmdexec"
+)
+
+const (
+	HookPre  = "pre"
+	HookPost = "post"
+
+	// MaxHookOutput is the max length of the output of one hook kept in the experiment, the tail is kept
+	MaxHookOutput = 2048
+)
+
+// runPreHook executes the pre hook before inject, the experiment fails if the hook fails
+func runPreHook(ctx context.Context, exp *storage.Experiment) error {
+	return runHook(ctx, exp, HookPre, exp.PreHook)
+}
+
+// runPostHook executes the post hook after recover
+func runPostHook(ctx context.Context, exp *storage.Experiment) error {
+	return runHook(ctx, exp, HookPost, exp.PostHook)
+}
+
+// runHook executes the hook on the host in cmdexec.ExecWaitTimeout, the output is appended to the hook output of the
+// experiment whether it fails or not
+func runHook(ctx context.Context, exp *storage.Experiment, name, cmd string) error {
+	if cmd == "" {
+		return nil
+	}
+
+	output, err := cmdexec.RunBashCmdWithTimeout(ctx, cmd, cmdexec.ExecWaitTimeout)
+	if len(output) > MaxHookOutput {
+		output = output[len(output)-MaxHookOutput:]
+	}
+
+	exp.HookOutput = fmt.Sprintf("%s[%s]%s\n", exp.HookOutput, name, output)
+	db, dbErr := storage.GetExperimentStore()
+	if dbErr == nil {
+		dbErr = db.UpdateHookOutput(exp.Uid, exp.HookOutput)
+	}
+
+	if dbErr != nil {
+		log.GetLogger(ctx).Warnf("record output of %s hook error: %s", name, dbErr.Error())
+	}
+
+	return err
+}
@@ -0,0 +1,152 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package host
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/config"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/cmdexec"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/process"
+	"os"
+)
+
+// The host is rebooted, shut down or panicked by a background process after "delay" seconds, so that the experiment
+// can be recorded and reported before the host goes down. Nothing can be recovered once the action is executed,
+// recovering within the delay cancels the action. They are only allowed when enabled by the env of the daemon
+func init() {
+	injector.Register(TargetHost, FaultHostReboot, func() injector.IInjector { return &PowerInjector{} })
+	injector.Register(TargetHost, FaultHostShutdown, func() injector.IInjector { return &PowerInjector{} })
+	injector.Register(TargetHost, FaultHostPanic, func() injector.IInjector { return &PowerInjector{} })
+}
+
+type PowerInjector struct {
+	injector.BaseInjector
+	Args    PowerArgs
+	Runtime PowerRuntime
+}
+
+type PowerArgs struct {
+	Confirm bool `json:"confirm"`
+	Delay   int  `json:"delay,omitempty"`
+}
+
+type PowerRuntime struct {
+	Hostname string `json:"hostname,omitempty"`
+}
+
+func (i *PowerInjector) GetArgs() interface{} {
+	return &i.Args
+}
+
+func (i *PowerInjector) GetRuntime() interface{} {
+	return &i.Runtime
+}
+
+func (i *PowerInjector) SetDefault() {
+	i.BaseInjector.SetDefault()
+
+	if i.Args.Delay == 0 {
+		i.Args.Delay = DefaultPowerDelay
+	}
+}
+
+func (i *PowerInjector) SetOption(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&i.Args.Confirm, "confirm", false, "confirm to execute the destructive fault on this host. Only allowed with env CHAOSMETAD_ENABLE_POWER_FAULTS=true")
+	cmd.Flags().IntVarP(&i.Args.Delay, "delay", "d", 0, fmt.Sprintf("seconds to wait before executing, recover in this period to cancel, default %d", DefaultPowerDelay))
+}
+
+func (i *PowerInjector) Validator(ctx context.Context) error {
+	if i.Info.ContainerId != "" || i.Info.ContainerRuntime != "" {
+		return fmt.Errorf("fault \"%s\" not support in container", i.Info.Fault)
+	}
+
+	if err := i.BaseInjector.Validator(ctx); err != nil {
+		return err
+	}
+
+	if err := config.CheckPowerFaults(i.Info.Fault); err != nil {
+		return err
+	}
+
+	if !i.Args.Confirm {
+		return fmt.Errorf("fault \"%s\" is destructive, \"confirm\" must be set", i.Info.Fault)
+	}
+
+	if i.Args.Delay < 0 {
+		return fmt.Errorf("\"delay\" can not less than 0")
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		return fmt.Errorf("get hostname error: %s", err.Error())
+	}
+
+	if err := config.CheckHostProtected(ctx, hostname); err != nil {
+		return err
+	}
+
+	if i.Info.Fault == FaultHostPanic {
+		if !cmdexec.SupportCmd("sync") {
+			return fmt.Errorf("not support cmd \"sync\"")
+		}
+	} else if !cmdexec.SupportCmd("systemctl") {
+		return fmt.Errorf("not support cmd \"systemctl\"")
+	}
+
+	i.Runtime.Hostname = hostname
+	return nil
+}
+
+// getPowerKey is in the command line of the background process, to cancel it in recover
+func (i *PowerInjector) getPowerKey() string {
+	return fmt.Sprintf("%s_%s", PowerKey, i.Info.Uid)
+}
+
+func (i *PowerInjector) Inject(ctx context.Context) error {
+	cmd := fmt.Sprintf(": %s; sleep %d; sync; %s", i.getPowerKey(), i.Args.Delay, getPowerCmd(i.Info.Fault))
+	if err := cmdexec.StartBashCmd(ctx, cmd); err != nil {
+		return fmt.Errorf("start %s process error: %s", i.Info.Fault, err.Error())
+	}
+
+	return nil
+}
+
+func (i *PowerInjector) Recover(ctx context.Context) error {
+	if i.BaseInjector.Recover(ctx) == nil {
+		return nil
+	}
+
+	if err := process.CheckExistAndKillByKey(ctx, i.getPowerKey()); err != nil {
+		return fmt.Errorf("cancel %s process error: %s", i.Info.Fault, err.Error())
+	}
+
+	return nil
+}
+
+func getPowerCmd(fault string) string {
+	switch fault {
+	case FaultHostReboot:
+		return "systemctl reboot"
+	case FaultHostShutdown:
+		return "systemctl poweroff"
+	default:
+		return fmt.Sprintf("echo 1 > %s; echo c > %s", SysrqPath, SysrqTriggerPath)
+	}
+}
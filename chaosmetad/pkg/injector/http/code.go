@@ -0,0 +1,96 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package http
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
+)
+
+func init() {
+	injector.Register(TargetHTTP, FaultHTTPCode, func() injector.IInjector { return &CodeInjector{} })
+}
+
+type CodeInjector struct {
+	injector.BaseInjector
+	Args    CodeArgs
+	Runtime CodeRuntime
+}
+
+type CodeArgs struct {
+	ProxyArgs
+	Code int `json:"code"`
+}
+
+type CodeRuntime struct {
+	ProxyRuntime
+}
+
+func (i *CodeInjector) GetArgs() interface{} {
+	return &i.Args
+}
+
+func (i *CodeInjector) GetRuntime() interface{} {
+	return &i.Runtime
+}
+
+func (i *CodeInjector) SetDefault() {
+	i.BaseInjector.SetDefault()
+
+	SetProxyDefault(&i.Args.ProxyArgs)
+}
+
+func (i *CodeInjector) SetOption(cmd *cobra.Command) {
+	setProxyOption(cmd, &i.Args.ProxyArgs)
+	cmd.Flags().IntVarP(&i.Args.Code, "code", "c", 0, "status code the responses are rewritten to, eg: 500")
+}
+
+func (i *CodeInjector) Validator(ctx context.Context) error {
+	if err := i.BaseInjector.Validator(ctx); err != nil {
+		return err
+	}
+
+	if i.Args.Code < 100 || i.Args.Code > 599 {
+		return fmt.Errorf("\"code\" must in [100, 599]")
+	}
+
+	return ValidateProxyArgs(ctx, &i.BaseInjector, &i.Args.ProxyArgs)
+}
+
+func (i *CodeInjector) Inject(ctx context.Context) error {
+	proxyPort, err := StartProxy(ctx, &i.BaseInjector, &i.Args.ProxyArgs, ModeCode, fmt.Sprintf("%d", i.Args.Code))
+	if err != nil {
+		return err
+	}
+
+	i.Runtime.ProxyPort = proxyPort
+	return nil
+}
+
+func (i *CodeInjector) Recover(ctx context.Context) error {
+	if i.BaseInjector.Recover(ctx) == nil {
+		return nil
+	}
+
+	return StopProxy(ctx, &i.BaseInjector, &i.Args.ProxyArgs, i.Runtime.ProxyPort)
+}
+
+func (i *CodeInjector) GetFingerprint() string {
+	return GetProxyFingerprint(&i.BaseInjector, &i.Args.ProxyArgs)
+}
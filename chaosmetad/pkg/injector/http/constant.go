@@ -0,0 +1,44 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package http
+
+const (
+	TargetHTTP = "http"
+
+	FaultHTTPDelay    = "delay"
+	FaultHTTPCode     = "code"
+	FaultHTTPTruncate = "truncate"
+
+	ModeDelay    = "delay"
+	ModeCode     = "code"
+	ModeTruncate = "truncate"
+	// ModeGRPCDelay and ModeGRPCCode make the proxy speak h2c to the real server, value of ModeGRPCCode is a grpc status code
+	ModeGRPCDelay = "grpc-delay"
+	ModeGRPCCode  = "grpc-code"
+
+	DirectionIn  = "in"
+	DirectionOut = "out"
+
+	ProxyKey = "chaosmeta_httpproxy"
+	// ProxyMark marks the connections of the proxy to the real server, so that they are not redirected to the proxy again
+	ProxyMark = 0x636d
+
+	ProxyPortBase     = 30000
+	ProxyPortRange    = 10000
+	ProxyPortRetry    = 10
+	RuleCommentPrefix = "chaosmeta_http_"
+)
@@ -0,0 +1,102 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package http
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
+	"time"
+)
+
+func init() {
+	injector.Register(TargetHTTP, FaultHTTPDelay, func() injector.IInjector { return &DelayInjector{} })
+}
+
+type DelayInjector struct {
+	injector.BaseInjector
+	Args    DelayArgs
+	Runtime DelayRuntime
+}
+
+type DelayArgs struct {
+	ProxyArgs
+	Latency string `json:"latency"`
+}
+
+type DelayRuntime struct {
+	ProxyRuntime
+}
+
+func (i *DelayInjector) GetArgs() interface{} {
+	return &i.Args
+}
+
+func (i *DelayInjector) GetRuntime() interface{} {
+	return &i.Runtime
+}
+
+func (i *DelayInjector) SetDefault() {
+	i.BaseInjector.SetDefault()
+
+	SetProxyDefault(&i.Args.ProxyArgs)
+}
+
+func (i *DelayInjector) SetOption(cmd *cobra.Command) {
+	setProxyOption(cmd, &i.Args.ProxyArgs)
+	cmd.Flags().StringVarP(&i.Args.Latency, "latency", "l", "", "delay of the response, eg: 200ms, 3s")
+}
+
+func (i *DelayInjector) Validator(ctx context.Context) error {
+	if err := i.BaseInjector.Validator(ctx); err != nil {
+		return err
+	}
+
+	if i.Args.Latency == "" {
+		return fmt.Errorf("\"latency\" must provide")
+	}
+
+	if d, err := time.ParseDuration(i.Args.Latency); err != nil || d <= 0 {
+		return fmt.Errorf("\"latency\" is invalid, eg: 200ms, 3s")
+	}
+
+	return ValidateProxyArgs(ctx, &i.BaseInjector, &i.Args.ProxyArgs)
+}
+
+func (i *DelayInjector) Inject(ctx context.Context) error {
+	latency, _ := time.ParseDuration(i.Args.Latency)
+	proxyPort, err := StartProxy(ctx, &i.BaseInjector, &i.Args.ProxyArgs, ModeDelay, fmt.Sprintf("%d", latency.Milliseconds()))
+	if err != nil {
+		return err
+	}
+
+	i.Runtime.ProxyPort = proxyPort
+	return nil
+}
+
+func (i *DelayInjector) Recover(ctx context.Context) error {
+	if i.BaseInjector.Recover(ctx) == nil {
+		return nil
+	}
+
+	return StopProxy(ctx, &i.BaseInjector, &i.Args.ProxyArgs, i.Runtime.ProxyPort)
+}
+
+func (i *DelayInjector) GetFingerprint() string {
+	return GetProxyFingerprint(&i.BaseInjector, &i.Args.ProxyArgs)
+}
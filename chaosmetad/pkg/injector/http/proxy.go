@@ -0,0 +1,218 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package http
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/cmdexec"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/namespace"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/net"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/process"
+	"hash/fnv"
+	"strings"
+)
+
+// ProxyArgs are the common args of http faults: the traffic of the target port is redirected to a local proxy by iptables
+type ProxyArgs struct {
+	Port      int    `json:"port"`
+	Direction string `json:"direction,omitempty"`
+	Path      string `json:"path,omitempty"`
+	ProxyPort int    `json:"proxy_port,omitempty"`
+}
+
+type ProxyRuntime struct {
+	ProxyPort int `json:"proxy_port,omitempty"`
+}
+
+// SetProxyDefault is shared by the faults based on the proxy, such as grpc faults
+func SetProxyDefault(args *ProxyArgs) {
+	if args.Direction == "" {
+		args.Direction = DirectionIn
+	}
+
+	if args.Path == "" {
+		args.Path = "/"
+	}
+}
+
+func setProxyOption(cmd *cobra.Command, args *ProxyArgs) {
+	SetProxyPortOption(cmd, args)
+	cmd.Flags().StringVarP(&args.Path, "path", "P", "", "only inject requests whose path has this prefix(default \"/\")")
+}
+
+// SetProxyPortOption sets the flags of the redirected traffic, the proxied requests are selected by the caller
+func SetProxyPortOption(cmd *cobra.Command, args *ProxyArgs) {
+	cmd.Flags().IntVarP(&args.Port, "port", "p", 0, "target port")
+	cmd.Flags().StringVarP(&args.Direction, "direction", "d", "", fmt.Sprintf("traffic direction, %s: requests to the local server on port, %s: requests from local clients to remote port(default %s)", DirectionIn, DirectionOut, DirectionIn))
+	cmd.Flags().IntVar(&args.ProxyPort, "proxy-port", 0, "listen port of the proxy(default a free port chosen by uid)")
+}
+
+func ValidateProxyArgs(ctx context.Context, info *injector.BaseInjector, args *ProxyArgs) error {
+	if args.Port <= 0 || args.Port > 65535 {
+		return fmt.Errorf("\"port\" must in (0, 65535]")
+	}
+
+	if args.Direction != DirectionIn && args.Direction != DirectionOut {
+		return fmt.Errorf("\"direction\" only support: %s, %s", DirectionIn, DirectionOut)
+	}
+
+	if args.ProxyPort < 0 || args.ProxyPort > 65535 || args.ProxyPort == args.Port {
+		return fmt.Errorf("\"proxy-port\" must in [0, 65535] and differ from \"port\"")
+	}
+
+	if !cmdexec.SupportCmd("iptables") {
+		return fmt.Errorf("not support cmd \"iptables\"")
+	}
+
+	if args.ProxyPort != 0 {
+		pid, err := net.GetPidByPort(ctx, info.Info.ContainerRuntime, info.Info.ContainerId, args.ProxyPort, net.ProtocolTCP)
+		if err != nil {
+			return fmt.Errorf("check proxy port[%d] error: %s", args.ProxyPort, err.Error())
+		}
+
+		if pid != utils.NoPid {
+			return fmt.Errorf("proxy port[%d] is occupied by process[%d]", args.ProxyPort, pid)
+		}
+	}
+
+	exist, err := existRule(ctx, info, args)
+	if err != nil {
+		return err
+	}
+	if exist {
+		return fmt.Errorf("proxy fault of port[%d] exists", args.Port)
+	}
+
+	return nil
+}
+
+// StartProxy starts the proxy before adding the redirect rule, so that no request is redirected to a closed port
+func StartProxy(ctx context.Context, info *injector.BaseInjector, args *ProxyArgs, mode, value string) (int, error) {
+	return StartToolProxy(ctx, info, args, ProxyKey, fmt.Sprintf("%s %s %s", mode, value, args.Path))
+}
+
+// StartToolProxy starts a proxy tool with args "[uid] [proxy-port] [mark] [toolArgs...] [timeout]", such as the proxy of
+// the middleware protocols
+func StartToolProxy(ctx context.Context, info *injector.BaseInjector, args *ProxyArgs, tool, toolArgs string) (int, error) {
+	proxyPort := args.ProxyPort
+	if proxyPort == 0 {
+		var err error
+		if proxyPort, err = getFreeProxyPort(ctx, info); err != nil {
+			return 0, err
+		}
+	}
+
+	var timeout int64
+	if info.Info.Timeout != "" {
+		timeout, _ = utils.GetTimeSecond(info.Info.Timeout)
+	}
+
+	cmd := fmt.Sprintf("%s %s %d %d %s %d", utils.GetToolPath(tool), info.Info.Uid, proxyPort, ProxyMark, toolArgs, timeout)
+	if err := cmdexec.WaitCommonWithNS(ctx, info.Info.ContainerRuntime, info.Info.ContainerId, cmd, []string{namespace.NET, namespace.PID}); err != nil {
+		return 0, fmt.Errorf("start proxy error: %s", err.Error())
+	}
+
+	if _, err := cmdexec.ExecCommonWithNS(ctx, info.Info.ContainerRuntime, info.Info.ContainerId, getRuleCmd("-I", info.Info.Uid, args, proxyPort), []string{namespace.NET}); err != nil {
+		if kErr := process.CheckExistAndKillByKey(ctx, getProxyKey(tool, info.Info.Uid)); kErr != nil {
+			return 0, fmt.Errorf("add redirect rule error: %s, stop proxy error: %s", err.Error(), kErr.Error())
+		}
+		return 0, fmt.Errorf("add redirect rule error: %s", err.Error())
+	}
+
+	return proxyPort, nil
+}
+
+func StopProxy(ctx context.Context, info *injector.BaseInjector, args *ProxyArgs, proxyPort int) error {
+	return StopToolProxy(ctx, info, args, proxyPort, ProxyKey)
+}
+
+func StopToolProxy(ctx context.Context, info *injector.BaseInjector, args *ProxyArgs, proxyPort int, tool string) error {
+	exist, err := existRule(ctx, info, args)
+	if err != nil {
+		return err
+	}
+
+	if exist {
+		if _, err := cmdexec.ExecCommonWithNS(ctx, info.Info.ContainerRuntime, info.Info.ContainerId, getRuleCmd("-D", info.Info.Uid, args, proxyPort), []string{namespace.NET}); err != nil {
+			return fmt.Errorf("delete redirect rule error: %s", err.Error())
+		}
+	}
+
+	return process.CheckExistAndKillByKey(ctx, getProxyKey(tool, info.Info.Uid))
+}
+
+// GetProxyFingerprint the traffic of a port in one direction can only be redirected to one proxy
+func GetProxyFingerprint(info *injector.BaseInjector, args *ProxyArgs) string {
+	return info.GetResourceFingerprint("proxy", fmt.Sprintf("%s/%d", args.Direction, args.Port))
+}
+
+func existRule(ctx context.Context, info *injector.BaseInjector, args *ProxyArgs) (bool, error) {
+	re, err := cmdexec.ExecCommonWithNS(ctx, info.Info.ContainerRuntime, info.Info.ContainerId,
+		fmt.Sprintf("iptables -t nat -S %s | grep -c %s%d_ || true", getChain(args.Direction), RuleCommentPrefix, args.Port), []string{namespace.NET})
+	if err != nil {
+		return false, fmt.Errorf("check redirect rule error: %s", err.Error())
+	}
+
+	return strings.TrimSpace(re) != "" && strings.TrimSpace(re) != "0", nil
+}
+
+// getFreeProxyPort starts from a port derived from uid, so that concurrent injections rarely probe the same ports
+func getFreeProxyPort(ctx context.Context, info *injector.BaseInjector) (int, error) {
+	h := fnv.New32a()
+	h.Write([]byte(info.Info.Uid))
+	base := ProxyPortBase + int(h.Sum32()%ProxyPortRange)
+	for i := 0; i < ProxyPortRetry; i++ {
+		port := ProxyPortBase + (base-ProxyPortBase+i)%ProxyPortRange
+		pid, err := net.GetPidByPort(ctx, info.Info.ContainerRuntime, info.Info.ContainerId, port, net.ProtocolTCP)
+		if err != nil {
+			return 0, fmt.Errorf("check port[%d] error: %s", port, err.Error())
+		}
+
+		if pid == utils.NoPid {
+			return port, nil
+		}
+	}
+
+	return 0, fmt.Errorf("no free proxy port found, please provide \"proxy-port\"")
+}
+
+func getChain(direction string) string {
+	if direction == DirectionOut {
+		return "OUTPUT"
+	}
+	return "PREROUTING"
+}
+
+// getRuleCmd the comment contains the target port, so that only one proxy fault is allowed on a port
+func getRuleCmd(op, uid string, args *ProxyArgs, proxyPort int) string {
+	comment := fmt.Sprintf("%s%d_%s", RuleCommentPrefix, args.Port, uid)
+	if args.Direction == DirectionOut {
+		return fmt.Sprintf("iptables -t nat %s OUTPUT -p tcp --dport %d -m mark ! --mark %d -m comment --comment %s -j REDIRECT --to-ports %d",
+			op, args.Port, ProxyMark, comment, proxyPort)
+	}
+
+	return fmt.Sprintf("iptables -t nat %s PREROUTING -p tcp --dport %d -m comment --comment %s -j REDIRECT --to-ports %d",
+		op, args.Port, comment, proxyPort)
+}
+
+func getProxyKey(tool, uid string) string {
+	return fmt.Sprintf("%s %s", tool, uid)
+}
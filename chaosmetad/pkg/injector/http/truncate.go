@@ -0,0 +1,103 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package http
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+)
+
+func init() {
+	injector.Register(TargetHTTP, FaultHTTPTruncate, func() injector.IInjector { return &TruncateInjector{} })
+}
+
+type TruncateInjector struct {
+	injector.BaseInjector
+	Args    TruncateArgs
+	Runtime TruncateRuntime
+}
+
+type TruncateArgs struct {
+	ProxyArgs
+	Bytes string `json:"bytes"`
+}
+
+type TruncateRuntime struct {
+	ProxyRuntime
+}
+
+func (i *TruncateInjector) GetArgs() interface{} {
+	return &i.Args
+}
+
+func (i *TruncateInjector) GetRuntime() interface{} {
+	return &i.Runtime
+}
+
+func (i *TruncateInjector) SetDefault() {
+	i.BaseInjector.SetDefault()
+
+	SetProxyDefault(&i.Args.ProxyArgs)
+}
+
+func (i *TruncateInjector) SetOption(cmd *cobra.Command) {
+	setProxyOption(cmd, &i.Args.ProxyArgs)
+	cmd.Flags().StringVarP(&i.Args.Bytes, "bytes", "b", "", "bytes of the response body to keep, support unit: b, kb, mb(default b)")
+}
+
+// Validator truncating to 0 bytes returns an empty body
+func (i *TruncateInjector) Validator(ctx context.Context) error {
+	if err := i.BaseInjector.Validator(ctx); err != nil {
+		return err
+	}
+
+	if i.Args.Bytes == "" {
+		return fmt.Errorf("\"bytes\" must provide")
+	}
+
+	if b, err := utils.GetBytes(i.Args.Bytes); err != nil || b < 0 {
+		return fmt.Errorf("\"bytes\" is invalid")
+	}
+
+	return ValidateProxyArgs(ctx, &i.BaseInjector, &i.Args.ProxyArgs)
+}
+
+func (i *TruncateInjector) Inject(ctx context.Context) error {
+	bytes, _ := utils.GetBytes(i.Args.Bytes)
+	proxyPort, err := StartProxy(ctx, &i.BaseInjector, &i.Args.ProxyArgs, ModeTruncate, fmt.Sprintf("%d", bytes))
+	if err != nil {
+		return err
+	}
+
+	i.Runtime.ProxyPort = proxyPort
+	return nil
+}
+
+func (i *TruncateInjector) Recover(ctx context.Context) error {
+	if i.BaseInjector.Recover(ctx) == nil {
+		return nil
+	}
+
+	return StopProxy(ctx, &i.BaseInjector, &i.Args.ProxyArgs, i.Runtime.ProxyPort)
+}
+
+func (i *TruncateInjector) GetFingerprint() string {
+	return GetProxyFingerprint(&i.BaseInjector, &i.Args.ProxyArgs)
+}
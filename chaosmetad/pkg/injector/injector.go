@@ -21,6 +21,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/spf13/cobra"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/config"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/crclient"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/log"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/storage"
@@ -30,6 +31,7 @@ import (
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/user"
 	"runtime/debug"
 	"strings"
+	"sync"
 )
 
 type IInjector interface {
@@ -52,6 +54,8 @@ type IInjector interface {
 
 type BaseInjector struct {
 	Info BaseInfo
+	// cleanPartial makes the recover of a failed experiment undo its partially applied rules
+	cleanPartial bool
 }
 
 type BaseInfo struct {
@@ -62,6 +66,8 @@ type BaseInfo struct {
 	Status  string `json:"status"`
 	Error   string `json:"error"`
 	Timeout string `json:"timeout"`
+	// Pulse format "active,idle", eg: 10s,20s
+	Pulse string `json:"pulse"`
 	// configuration information
 	Target string `json:"target"`
 	Fault  string `json:"fault"`
@@ -69,6 +75,9 @@ type BaseInfo struct {
 	ContainerId      string `json:"container_id"`
 	ContainerRuntime string `json:"container_runtime"`
 	//ContainerNs      []string `json:"container_ns"`
+	// hooks are bash commands executed on the host before inject and after recover
+	PreHook  string `json:"pre_hook"`
+	PostHook string `json:"post_hook"`
 }
 
 func (i *BaseInjector) GetArgs() interface{} {
@@ -106,6 +115,10 @@ func (i *BaseInjector) SetCommonArgs(info *BaseInfo) {
 		i.Info.Timeout = info.Timeout
 	}
 
+	if info.Pulse != "" {
+		i.Info.Pulse = info.Pulse
+	}
+
 	if info.ContainerRuntime != "" {
 		i.Info.ContainerRuntime = info.ContainerRuntime
 	}
@@ -113,6 +126,14 @@ func (i *BaseInjector) SetCommonArgs(info *BaseInfo) {
 	if info.ContainerId != "" {
 		i.Info.ContainerId = info.ContainerId
 	}
+
+	if info.PreHook != "" {
+		i.Info.PreHook = info.PreHook
+	}
+
+	if info.PostHook != "" {
+		i.Info.PostHook = info.PostHook
+	}
 }
 
 func (i *BaseInjector) SetOption(cmd *cobra.Command) {
@@ -126,7 +147,12 @@ func (i *BaseInjector) Inject(ctx context.Context) error {
 }
 
 func (i *BaseInjector) Recover(ctx context.Context) error {
-	if i.Info.Status == utils.StatusDestroyed || i.Info.Status == utils.StatusError {
+	if i.Info.Status == utils.StatusError && i.cleanPartial {
+		return fmt.Errorf("not implemented")
+	}
+
+	if i.Info.Status == utils.StatusDestroyed || i.Info.Status == utils.StatusError || i.Info.Status == utils.StatusIdle ||
+		i.Info.Status == utils.StatusPaused {
 		return nil
 	}
 
@@ -172,6 +198,9 @@ func (i *BaseInjector) Validator(ctx context.Context) error {
 	}
 
 	if i.Info.Timeout == "" {
+		if i.Info.Pulse != "" {
+			return fmt.Errorf("\"pulse\" must be used with \"timeout\"")
+		}
 		return nil
 	}
 
@@ -179,6 +208,12 @@ func (i *BaseInjector) Validator(ctx context.Context) error {
 		return fmt.Errorf("\"timeout\" is not valid: %s", err.Error())
 	}
 
+	if i.Info.Pulse != "" {
+		if _, _, err := GetPulseSecond(i.Info.Pulse); err != nil {
+			return fmt.Errorf("\"pulse\" is not valid: %s", err.Error())
+		}
+	}
+
 	return nil
 }
 
@@ -202,8 +237,11 @@ func (i *BaseInjector) LoadInjector(exp *storage.Experiment, argsVar, rVar inter
 	i.Info.Error = exp.Error
 	i.Info.Creator = exp.Creator
 	i.Info.Timeout = exp.Timeout
+	i.Info.Pulse = exp.Pulse
 	i.Info.ContainerRuntime = exp.ContainerRuntime
 	i.Info.ContainerId = exp.ContainerId
+	i.Info.PreHook = exp.PreHook
+	i.Info.PostHook = exp.PostHook
 
 	return nil
 }
@@ -225,11 +263,14 @@ func (i *BaseInjector) OptionToExp(args, r interface{}) (*storage.Experiment, er
 		Status:           i.Info.Status,
 		Creator:          i.Info.Creator,
 		Timeout:          i.Info.Timeout,
+		Pulse:            i.Info.Pulse,
 		Error:            i.Info.Error,
 		Args:             string(argsByte),
 		Runtime:          string(runtimeByte),
 		ContainerRuntime: i.Info.ContainerRuntime,
 		ContainerId:      i.Info.ContainerId,
+		PreHook:          i.Info.PreHook,
+		PostHook:         i.Info.PostHook,
 	}
 
 	return exp, nil
@@ -237,6 +278,8 @@ func (i *BaseInjector) OptionToExp(args, r interface{}) (*storage.Experiment, er
 
 /*=======================================Main Process===================================================*/
 
+var injectMutex sync.Mutex
+
 func ProcessInject(ctx context.Context, i IInjector) (code int, msg string) {
 	logger := log.GetLogger(ctx)
 	defer func() {
@@ -262,12 +305,53 @@ func ProcessInject(ctx context.Context, i IInjector) (code int, msg string) {
 		return errutil.BadArgsErr, fmt.Sprintf("create experiment error: %s", err.Error())
 	}
 
-	if err := db.Insert(exp); err != nil {
+	exp.Fingerprint = getFingerprint(i)
+	if err := CheckCapabilities(ctx, i, exp.Target, exp.Fault, exp.ContainerId != ""); err != nil {
+		return errutil.PermissionErr, fmt.Sprintf("preflight error: %s", err.Error())
+	}
+
+	if err := PrepareTools(ctx, exp.Target, exp.Fault, exp.ContainerId != ""); err != nil {
+		return errutil.InternalErr, fmt.Sprintf("prepare tools error: %s", err.Error())
+	}
+
+	if err := config.CheckInject(ctx, exp.Target, exp.Timeout); err != nil {
+		return errutil.BadArgsErr, fmt.Sprintf("not allowed by config: %s", err.Error())
+	}
+
+	if err := config.CheckHooks(exp.PreHook, exp.PostHook); err != nil {
+		return errutil.BadArgsErr, fmt.Sprintf("not allowed by config: %s", err.Error())
+	}
+
+	// the check and the insert are serialized in the daemon, otherwise concurrent requests may all pass the limits
+	injectMutex.Lock()
+	if err := config.CheckLimit(ctx); err != nil {
+		injectMutex.Unlock()
+		return errutil.LimitErr, fmt.Sprintf("limited by config: %s", err.Error())
+	}
+
+	if err := checkFingerprint(exp.Fingerprint); err != nil {
+		injectMutex.Unlock()
+		return errutil.BadArgsErr, fmt.Sprintf("duplicate fault: %s", err.Error())
+	}
+
+	err = db.Insert(exp)
+	injectMutex.Unlock()
+	if err != nil {
 		return errutil.DBErr, fmt.Sprintf("insert new experiment error: %s", err.Error())
 	}
 
 	logger.Infof("uid: %s", exp.Uid)
 	logger.Infof("args: %s", exp.Args)
+	ctx = utils.GetCtxWithUid(ctx, exp.Uid)
+
+	if err := runPreHook(ctx, exp); err != nil {
+		errMsg := fmt.Sprintf("pre hook error: %s", err.Error())
+		if err := db.UpdateStatusAndErr(exp.Uid, utils.StatusError, errMsg); err != nil {
+			logger.Warnf("update status[%s] for experiment[%s] error: %s", utils.StatusError, exp.Uid, errMsg)
+		}
+
+		return errutil.InjectErr, errMsg
+	}
 
 	if err := i.Inject(ctx); err != nil {
 		errMsg := fmt.Sprintf("inject error: %s", err.Error())
@@ -275,7 +359,7 @@ func ProcessInject(ctx context.Context, i IInjector) (code int, msg string) {
 			logger.Warnf("update status[%s] for experiment[%s] error: %s", utils.StatusError, exp.Uid, errMsg)
 		}
 
-		return errutil.InjectErr, errMsg
+		return errutil.GetInjectErrCode(errMsg), errMsg
 	}
 
 	exp, _ = i.OptionToExp(i.GetArgs(), i.GetRuntime())
@@ -290,7 +374,12 @@ func ProcessInject(ctx context.Context, i IInjector) (code int, msg string) {
 
 	logger.Info("inject success")
 
-	if exp.Timeout != "" {
+	if exp.Pulse != "" {
+		// the pulse process recovers the experiment finally when timeout
+		if err := cmdexec.StartPulse(ctx, exp.Uid); err != nil {
+			logger.Warnf("inject success but start pulse process error: %s, please execute [chaosmetad recover -u %s] manually to recover", err.Error(), exp.Uid)
+		}
+	} else if exp.Timeout != "" {
 		timeSecond, _ := utils.GetTimeSecond(exp.Timeout)
 		if err := i.DelayRecover(ctx, timeSecond); err != nil {
 			logger.Warnf("inject success but auto delay recover cmd exec error: %s, please execute [chaosmetad recover -u %s] manually to recover", err.Error(), exp.Uid)
@@ -311,6 +400,7 @@ func ProcessRecover(ctx context.Context, uid string) (code int, msg string) {
 	}()
 
 	logger.Debugf("uid: %s", uid)
+	ctx = utils.GetCtxWithUid(ctx, uid)
 
 	db, err := storage.GetExperimentStore()
 	if err != nil {
@@ -331,15 +421,49 @@ func ProcessRecover(ctx context.Context, uid string) (code int, msg string) {
 		return errutil.InternalErr, fmt.Sprintf("load experiment to injector error: %s", err.Error())
 	}
 
+	// a failed experiment with fingerprint may leave partially applied rules, which are cleaned by recover, unless the
+	// resource is occupied by another experiment now. The status of the experiment is kept as it is
+	failedWithFingerprint := false
+	if f, ok := i.(IFingerprint); ok && exp.Status == utils.StatusError && exp.Fingerprint != "" {
+		if err := checkFingerprint(exp.Fingerprint); err != nil {
+			return errutil.NoErr, fmt.Sprintf("experiment is %s, rules are not cleaned: %s", exp.Status, err.Error())
+		}
+		f.SetCleanPartial()
+		failedWithFingerprint = true
+	}
+
 	if err := i.Recover(ctx); err != nil {
 		return errutil.RecoverErr, fmt.Sprintf("recover error: %s", err.Error())
 	}
 
+	if failedWithFingerprint {
+		logger.Info("partially applied rules are cleaned")
+		return errutil.NoErr, "success"
+	}
+
 	logger.Info("recover success")
 
-	if err := db.UpdateStatus(uid, utils.StatusDestroyed); err != nil {
+	if exp.Status == utils.StatusDestroyed || exp.Status == utils.StatusError {
+		return errutil.NoErr, "success"
+	}
+
+	// the post hook only runs in the call which moves the experiment to destroyed
+	transited, err := db.TransitStatus(uid, exp.Status, utils.StatusDestroyed)
+	if err != nil {
 		logger.Warnf("update status[%s] for experiment[%s] error: %s", utils.StatusDestroyed, uid, err.Error())
 	}
+	if !transited {
+		return errutil.NoErr, "success"
+	}
+
+	// the fault is recovered even if the post hook fails, the failure is only recorded
+	if err := runPostHook(ctx, exp); err != nil {
+		errMsg := fmt.Sprintf("post hook error: %s", err.Error())
+		logger.Warnf("recover success but %s", errMsg)
+		if err := db.UpdateStatusAndErr(uid, utils.StatusDestroyed, errMsg); err != nil {
+			logger.Warnf("update error for experiment[%s] error: %s", uid, err.Error())
+		}
+	}
 
 	return errutil.NoErr, "success"
 }
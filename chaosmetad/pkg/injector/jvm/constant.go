@@ -18,13 +18,18 @@ package jvm
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/log"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/cmdexec"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/filesys"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/namespace"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/process"
 	"os"
+	"regexp"
 	"strings"
+	"time"
 )
 
 const (
@@ -49,6 +54,8 @@ const (
 	JVMAgentTool = "ChaosMetaJVMAgent.jar"
 
 	TimeoutSec = 2
+	// DetachTimeoutSec the agent checks its rule file every 2s, then restores the classes before the attacher detaches
+	DetachTimeoutSec = 10
 )
 
 type JVMRuleConfig struct {
@@ -121,6 +128,16 @@ func checkJavaCmd(ctx context.Context, cr, cId string) error {
 	}
 }
 
+// getContainerProcessOwner returns "uid:gid" of a process in the pid namespace of the container
+func getContainerProcessOwner(ctx context.Context, cr, cId string, pid int) (string, error) {
+	re, err := cmdexec.ExecContainer(ctx, cr, cId, []string{namespace.MNT, namespace.PID}, fmt.Sprintf("stat -c %%u:%%g /proc/%d", pid), cmdexec.ExecRun)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(re), nil
+}
+
 func doInject(ctx context.Context, cr, cId string, pidList []int, ruleBytes []byte) error {
 	// create rule file
 	for _, pid := range pidList {
@@ -152,9 +169,16 @@ func doInject(ctx context.Context, cr, cId string, pidList []int, ruleBytes []by
 		}
 
 		for _, pid := range pidList {
-			cmd := fmt.Sprintf("cd %s && java -cp .:tools.jar %s %d %s %s", JVMContainerDir, AttacherTool, pid,
+			// the attach of hotspot is only allowed for the owner of the target jvm
+			owner, err := getContainerProcessOwner(ctx, cr, cId, pid)
+			if err != nil {
+				return fmt.Errorf("get owner of process[%d] error: %s", pid, err.Error())
+			}
+
+			cmd := fmt.Sprintf("java -cp .:tools.jar %s %d %s %s", AttacherTool, pid,
 				fmt.Sprintf("%s/%s", JVMContainerDir, JVMAgentTool), getContainerRuleFile(pid))
-			if _, err := cmdexec.ExecContainer(ctx, cr, cId, []string{namespace.MNT, namespace.PID, namespace.IPC, namespace.ENV}, cmd, cmdexec.ExecStart); err != nil {
+			opt := &cmdexec.ExecOption{User: owner, WorkDir: JVMContainerDir}
+			if _, err := cmdexec.ExecContainerWithOption(ctx, cr, cId, []string{namespace.MNT, namespace.PID, namespace.IPC, namespace.ENV}, cmd, cmdexec.ExecStart, opt); err != nil {
 				return fmt.Errorf("execute fault for process[%d] error: %s", pid, err.Error())
 			}
 		}
@@ -170,6 +194,71 @@ func doInject(ctx context.Context, cr, cId string, pidList []int, ruleBytes []by
 	return nil
 }
 
+// doRecover removes the rule files so that the agents restore the classes, then waits for the attachers to detach
+func doRecover(ctx context.Context, cr, cId string, pidList []int) error {
+	logger := log.GetLogger(ctx)
+	var errMsg string
+	for _, pid := range pidList {
+		targetRule := getRuleFile(cId, pid)
+		logger.Debugf("check file: %s", targetRule)
+		ifExist, err := filesys.ExistFile(targetRule)
+		if err != nil {
+			errMsg = fmt.Sprintf("%s. %s", errMsg, fmt.Sprintf("check file[%s] exist error: %s", targetRule, err.Error()))
+			continue
+		}
+
+		if ifExist {
+			if cr != "" {
+				if err := filesys.RemoveFile(ctx, cr, cId, getContainerRuleFile(pid)); err != nil {
+					errMsg = fmt.Sprintf("%s. %s", errMsg, fmt.Sprintf("remove rule[%s] error: %s", targetRule, err.Error()))
+					continue
+				}
+			}
+			if err := os.RemoveAll(targetRule); err != nil {
+				errMsg = fmt.Sprintf("%s. %s", errMsg, fmt.Sprintf("remove rule[%s] error: %s", targetRule, err.Error()))
+			}
+		}
+	}
+
+	if errMsg != "" {
+		return errors.New(errMsg)
+	}
+
+	return waitDetach(ctx, cr, cId, pidList)
+}
+
+// getAttacherRegex matches the command line of the attacher of the process, the literal part is quoted
+func getAttacherRegex(pid int) string {
+	return fmt.Sprintf(".*%s.*", regexp.QuoteMeta(fmt.Sprintf("%s %d ", AttacherTool, pid)))
+}
+
+// waitDetach the attacher blocks in loadAgent until the agent returns from restoring the classes, so the attacher
+// exiting means the classes of the process are restored and the jvm is detached
+func waitDetach(ctx context.Context, cr, cId string, pidList []int) error {
+	logger := log.GetLogger(ctx)
+	remaining := pidList
+	for i := 0; i < DetachTimeoutSec; i++ {
+		var attached []int
+		for _, pid := range remaining {
+			if _, err := process.GetProcessByRegex(ctx, cr, cId, getAttacherRegex(pid)); err != nil {
+				if !errors.Is(err, process.ErrNoProcessMatch) {
+					logger.Warnf("get attacher of process[%d] error: %s", pid, err.Error())
+				}
+				continue
+			}
+			attached = append(attached, pid)
+		}
+
+		if len(attached) == 0 {
+			return nil
+		}
+		remaining = attached
+		time.Sleep(time.Second)
+	}
+
+	return fmt.Errorf("jvm of process%v is not detached in %ds, the classes may not be restored yet", remaining, DetachTimeoutSec)
+}
+
 func getMethodList(methodStr string, fault string) (map[string][]*MethodJVMRule, error) {
 	var result = make(map[string][]*MethodJVMRule)
 	if methodStr == "" {
@@ -0,0 +1,40 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package jvm
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+)
+
+func Test_getAttacherRegex(t *testing.T) {
+	// the regex is anchored to the whole command line when processes are matched
+	reg := regexp.MustCompile(fmt.Sprintf("^(?:%s)$", getAttacherRegex(12)))
+	tests := []struct {
+		cmd  string
+		want bool
+	}{
+		{cmd: "java -cp .:tools.jar ChaosMetaJVMAttacher 12 /tmp/chaosmeta_jvm/ChaosMetaJVMAgent.jar /tmp/chaosmeta_jvm/12.json", want: true},
+		{cmd: "java -cp .:tools.jar ChaosMetaJVMAttacher 123 /tmp/chaosmeta_jvm/ChaosMetaJVMAgent.jar /tmp/chaosmeta_jvm/123.json", want: false},
+		{cmd: "java -jar app.jar", want: false},
+	}
+	for _, tt := range tests {
+		if got := reg.MatchString(tt.cmd); got != tt.want {
+			t.Errorf("getAttacherRegex() match %q = %v, want %v", tt.cmd, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,233 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mem
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/log"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/cgroup"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/cmdexec"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/filesys"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/memory"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/namespace"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/process"
+	"time"
+)
+
+func init() {
+	injector.Register(TargetMem, FaultMemCgroupFill, func() injector.IInjector { return &CgroupFillInjector{} })
+}
+
+// CgroupFillInjector allocates memory by a process in a dedicated cgroup under the memory cgroup of the target,
+// so the memory is charged to the container. Without "oom" the fill stays "reserve" below the limit of the target,
+// with "oom" it goes "reserve" beyond the limit to trigger the OOM killer of the container
+type CgroupFillInjector struct {
+	injector.BaseInjector
+	Args    CgroupFillArgs
+	Runtime CgroupFillRuntime
+}
+
+type CgroupFillArgs struct {
+	Percent int    `json:"percent,omitempty"`
+	Bytes   string `json:"bytes,omitempty"`
+	OOM     bool   `json:"oom,omitempty"`
+	Reserve string `json:"reserve,omitempty"`
+}
+
+type CgroupFillRuntime struct {
+	CgroupPath string `json:"cgroup_path,omitempty"`
+	FillKBytes int64  `json:"fill_kbytes,omitempty"`
+}
+
+func (i *CgroupFillInjector) GetArgs() interface{} {
+	return &i.Args
+}
+
+func (i *CgroupFillInjector) GetRuntime() interface{} {
+	return &i.Runtime
+}
+
+func (i *CgroupFillInjector) SetDefault() {
+	i.BaseInjector.SetDefault()
+
+	if i.Args.Reserve == "" {
+		i.Args.Reserve = DefaultCgroupFillReserve
+	}
+}
+
+func (i *CgroupFillInjector) SetOption(cmd *cobra.Command) {
+	cmd.Flags().IntVarP(&i.Args.Percent, "percent", "p", 0, "mem fill target percent of the memory limit, an integer in (0,100] without \"%\", eg: \"30\" means \"30%\"")
+	cmd.Flags().StringVarP(&i.Args.Bytes, "bytes", "b", "", "mem fill bytes to add, support unit: KB/MB/GB/TB（default KB）")
+	cmd.Flags().BoolVarP(&i.Args.OOM, "oom", "o", false, "fill beyond the memory limit of the container to trigger its OOM killer, otherwise the fill stays below the limit")
+	cmd.Flags().StringVarP(&i.Args.Reserve, "reserve", "r", "", fmt.Sprintf("distance to the memory limit, below it without \"oom\" and beyond it with \"oom\", support unit: KB/MB/GB/TB（default KB）, default %s", DefaultCgroupFillReserve))
+}
+
+func (i *CgroupFillInjector) Validator(ctx context.Context) error {
+	if err := i.BaseInjector.Validator(ctx); err != nil {
+		return err
+	}
+
+	if i.Args.OOM {
+		if i.Info.ContainerId == "" {
+			return fmt.Errorf("\"oom\" is only supported in container")
+		}
+	} else if i.Args.Percent == 0 && i.Args.Bytes == "" {
+		return fmt.Errorf("must provide \"percent\" or \"bytes\"")
+	}
+
+	if i.Args.Percent != 0 {
+		if i.Args.Percent < 0 || i.Args.Percent > 100 {
+			return fmt.Errorf("\"percent\" must be in (0,100]")
+		}
+	} else if i.Args.Bytes != "" {
+		if _, err := utils.GetKBytes(i.Args.Bytes); err != nil {
+			return fmt.Errorf("\"bytes\" is invalid: %s", err.Error())
+		}
+	}
+
+	if _, err := utils.GetKBytes(i.Args.Reserve); err != nil {
+		return fmt.Errorf("\"reserve\" is invalid: %s", err.Error())
+	}
+
+	parentPath, err := cgroup.GetContainerMemCgroupPath(ctx, i.Info.ContainerRuntime, i.Info.ContainerId)
+	if err != nil {
+		return fmt.Errorf("get memory cgroup error: %s", err.Error())
+	}
+
+	if _, _, err := memory.GetLimitAndUsageKBytes(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, parentPath); err != nil {
+		return fmt.Errorf("get memory limit error: %s", err.Error())
+	}
+
+	return nil
+}
+
+func getMemCgroupPath(parentPath, uid string) string {
+	return fmt.Sprintf("%s/%s_%s", parentPath, cgroup.MemCgroupName, uid)
+}
+
+// calculateFillKBytes is the requested fill capped "reserve" below the limit, or at least "reserve" beyond the limit with "oom"
+func (i *CgroupFillInjector) calculateFillKBytes(limit, usage int64) (int64, error) {
+	reserve, _ := utils.GetKBytes(i.Args.Reserve)
+	var fill int64
+	if i.Args.Percent != 0 {
+		fill = limit*int64(i.Args.Percent)/100 - usage
+	} else if i.Args.Bytes != "" {
+		fill, _ = utils.GetKBytes(i.Args.Bytes)
+	}
+
+	if i.Args.OOM {
+		if oomFill := limit - usage + reserve; fill < oomFill {
+			fill = oomFill
+		}
+	} else if maxFill := limit - usage - reserve; fill > maxFill {
+		fill = maxFill
+	}
+
+	if fill <= 0 {
+		return -1, fmt.Errorf("current mem usage is %dKB of limit %dKB, no need to fill any mem", usage, limit)
+	}
+
+	return fill, nil
+}
+
+func (i *CgroupFillInjector) Inject(ctx context.Context) error {
+	logger := log.GetLogger(ctx)
+	parentPath, err := cgroup.GetContainerMemCgroupPath(ctx, i.Info.ContainerRuntime, i.Info.ContainerId)
+	if err != nil {
+		return fmt.Errorf("get memory cgroup error: %s", err.Error())
+	}
+
+	limit, usage, err := memory.GetLimitAndUsageKBytes(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, parentPath)
+	if err != nil {
+		return fmt.Errorf("get memory limit error: %s", err.Error())
+	}
+
+	fillKBytes, err := i.calculateFillKBytes(limit, usage)
+	if err != nil {
+		return err
+	}
+
+	cgroupPath := getMemCgroupPath(parentPath, i.Info.Uid)
+	if err := filesys.MkdirP(ctx, cgroupPath); err != nil {
+		return fmt.Errorf("create cgroup[%s] error: %s", cgroupPath, err.Error())
+	}
+	i.Runtime.CgroupPath, i.Runtime.FillKBytes = cgroupPath, fillKBytes
+
+	if !i.Args.OOM {
+		// the dedicated limit keeps the fill process from pushing the target over its limit, it is killed by itself instead
+		reserve, _ := utils.GetKBytes(i.Args.Reserve)
+		if _, err := cgroup.SetMemCgroupLimit(ctx, cgroupPath, (fillKBytes+reserve/2)*1024); err != nil {
+			if err := i.Recover(ctx); err != nil {
+				logger.Warnf("undo error: %s", err.Error())
+			}
+
+			return fmt.Errorf("set memory limit of cgroup[%s] error: %s", cgroupPath, err.Error())
+		}
+	}
+
+	var timeout int64
+	if i.Info.Timeout != "" {
+		timeout, _ = utils.GetTimeSecond(i.Info.Timeout)
+	}
+
+	args := fmt.Sprintf("'%s' %d %d '%dKB' %d", i.Info.Uid, -999, 0, fillKBytes, timeout)
+	cmd := fmt.Sprintf("echo 0 > %s/%s && exec %s %s", cgroupPath, cgroup.CgroupProcsFile, utils.GetToolPath(MemFillKey), args)
+	if err := cmdexec.WaitCommonWithNS(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, cmd, []string{namespace.PID}); err != nil {
+		if err := i.Recover(ctx); err != nil {
+			logger.Warnf("undo error: %s", err.Error())
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func (i *CgroupFillInjector) Recover(ctx context.Context) error {
+	if i.BaseInjector.Recover(ctx) == nil {
+		return nil
+	}
+
+	if err := process.CheckExistAndKillByKey(ctx, fmt.Sprintf("%s %s", MemFillKey, i.Info.Uid)); err != nil {
+		return err
+	}
+
+	if i.Runtime.CgroupPath == "" {
+		return nil
+	}
+
+	isExist, err := filesys.ExistPathLocal(i.Runtime.CgroupPath)
+	if err != nil {
+		return fmt.Errorf("check cgroup[%s] exist error: %s", i.Runtime.CgroupPath, err.Error())
+	}
+
+	if !isExist {
+		return nil
+	}
+
+	// the killed process may still be exiting
+	for retry := 0; ; retry++ {
+		if err = cgroup.RemoveCgroup(ctx, i.Runtime.CgroupPath); err == nil || retry >= 5 {
+			return err
+		}
+		time.Sleep(time.Second)
+	}
+}
@@ -23,6 +23,8 @@ const (
 	FaultMemOOM  = "oom"
 	PercentOOM   = 101
 
+	FaultMemCgroupFill = "cgroupfill"
+
 	ModeRam   = "ram"
 	ModeCache = "cache"
 
@@ -32,5 +34,7 @@ const (
 
 	MemFillKey = "chaosmeta_memfill"
 
+	DefaultCgroupFillReserve = "32MB"
+
 	MemExec = "chaosmeta_mem"
 )
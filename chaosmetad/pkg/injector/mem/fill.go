@@ -28,6 +28,7 @@ import (
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/memory"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/namespace"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/process"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/user"
 )
 
 func init() {
@@ -79,6 +80,15 @@ func (i *FillInjector) SetOption(cmd *cobra.Command) {
 }
 
 // Validator percent > bytes
+// GetExtraCaps the tmpfs of cache mode is mounted
+func (i *FillInjector) GetExtraCaps(ctx context.Context) ([]string, error) {
+	if i.Args.Mode == ModeCache {
+		return []string{user.CapSysAdmin}, nil
+	}
+
+	return nil, nil
+}
+
 func (i *FillInjector) Validator(ctx context.Context) error {
 	if err := i.BaseInjector.Validator(ctx); err != nil {
 		return err
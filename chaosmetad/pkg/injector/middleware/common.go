@@ -0,0 +1,83 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector/http"
+	"regexp"
+	"strings"
+)
+
+var commandRegexp = regexp.MustCompile(`^[A-Za-z_]+(,[A-Za-z_]+)*$`)
+
+// CommandArgs selects the commands of the middleware protocol, the traffic of the port is redirected to a proxy which
+// parses the protocol, so that the faults are injected per command without touching the server
+type CommandArgs struct {
+	http.ProxyArgs
+	Command string `json:"command,omitempty"`
+}
+
+func setCommandDefault(target string, args *CommandArgs) {
+	if args.Port == 0 {
+		args.Port = getDefaultPort(target)
+	}
+
+	// the clients of the middleware are usually on the host, so the outgoing requests are proxied by default
+	if args.Direction == "" {
+		args.Direction = http.DirectionOut
+	}
+
+	http.SetProxyDefault(&args.ProxyArgs)
+}
+
+func setCommandOption(cmd *cobra.Command, args *CommandArgs) {
+	http.SetProxyPortOption(cmd, &args.ProxyArgs)
+	cmd.Flags().StringVarP(&args.Command, "command", "c", "", "only inject these commands, separated by \",\", the statement keyword for mysql, eg: SELECT,UPDATE, the command name for redis, eg: GET,SET(default all)")
+}
+
+func validateCommandArgs(ctx context.Context, info *injector.BaseInjector, args *CommandArgs) error {
+	if args.Command != "" && !commandRegexp.MatchString(args.Command) {
+		return fmt.Errorf("\"command\" is invalid, eg: GET,SET")
+	}
+
+	return http.ValidateProxyArgs(ctx, info, &args.ProxyArgs)
+}
+
+func startProxy(ctx context.Context, info *injector.BaseInjector, args *CommandArgs, mode, value string) (int, error) {
+	command := CommandAll
+	if args.Command != "" {
+		command = strings.ToUpper(args.Command)
+	}
+
+	return http.StartToolProxy(ctx, info, &args.ProxyArgs, ProxyKey, fmt.Sprintf("%s %s %s %s", info.Info.Target, mode, value, command))
+}
+
+func stopProxy(ctx context.Context, info *injector.BaseInjector, args *CommandArgs, proxyPort int) error {
+	return http.StopToolProxy(ctx, info, &args.ProxyArgs, proxyPort, ProxyKey)
+}
+
+func getDefaultPort(target string) int {
+	if target == TargetMySQL {
+		return DefaultMySQLPort
+	}
+
+	return DefaultRedisPort
+}
@@ -0,0 +1,39 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package middleware
+
+const (
+	TargetMySQL = "mysql"
+	TargetRedis = "redis"
+
+	FaultDelay = "delay"
+	FaultError = "error"
+
+	ProxyKey = "chaosmeta_dbproxy"
+
+	ModeDelay = "delay"
+	ModeError = "error"
+
+	// CommandAll is passed to the proxy when no command is selected
+	CommandAll = "all"
+
+	DefaultMySQLPort = 3306
+	DefaultRedisPort = 6379
+	// DefaultMySQLCode is ER_LOCK_WAIT_TIMEOUT
+	DefaultMySQLCode   = 1205
+	DefaultRedisPrefix = "ERR"
+)
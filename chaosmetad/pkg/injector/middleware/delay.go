@@ -0,0 +1,104 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector/http"
+	"time"
+)
+
+func init() {
+	injector.Register(TargetMySQL, FaultDelay, func() injector.IInjector { return &DelayInjector{} })
+	injector.Register(TargetRedis, FaultDelay, func() injector.IInjector { return &DelayInjector{} })
+}
+
+type DelayInjector struct {
+	injector.BaseInjector
+	Args    DelayArgs
+	Runtime DelayRuntime
+}
+
+type DelayArgs struct {
+	CommandArgs
+	Latency string `json:"latency"`
+}
+
+type DelayRuntime struct {
+	http.ProxyRuntime
+}
+
+func (i *DelayInjector) GetArgs() interface{} {
+	return &i.Args
+}
+
+func (i *DelayInjector) GetRuntime() interface{} {
+	return &i.Runtime
+}
+
+func (i *DelayInjector) SetDefault() {
+	i.BaseInjector.SetDefault()
+
+	setCommandDefault(i.Info.Target, &i.Args.CommandArgs)
+}
+
+func (i *DelayInjector) SetOption(cmd *cobra.Command) {
+	setCommandOption(cmd, &i.Args.CommandArgs)
+	cmd.Flags().StringVarP(&i.Args.Latency, "latency", "l", "", "delay before the command is sent to the server, eg: 200ms, 3s")
+}
+
+func (i *DelayInjector) Validator(ctx context.Context) error {
+	if err := i.BaseInjector.Validator(ctx); err != nil {
+		return err
+	}
+
+	if i.Args.Latency == "" {
+		return fmt.Errorf("\"latency\" must provide")
+	}
+
+	if d, err := time.ParseDuration(i.Args.Latency); err != nil || d < time.Millisecond {
+		return fmt.Errorf("\"latency\" is invalid, eg: 200ms, 3s")
+	}
+
+	return validateCommandArgs(ctx, &i.BaseInjector, &i.Args.CommandArgs)
+}
+
+func (i *DelayInjector) Inject(ctx context.Context) error {
+	latency, _ := time.ParseDuration(i.Args.Latency)
+	proxyPort, err := startProxy(ctx, &i.BaseInjector, &i.Args.CommandArgs, ModeDelay, fmt.Sprintf("%d", latency.Milliseconds()))
+	if err != nil {
+		return err
+	}
+
+	i.Runtime.ProxyPort = proxyPort
+	return nil
+}
+
+func (i *DelayInjector) Recover(ctx context.Context) error {
+	if i.BaseInjector.Recover(ctx) == nil {
+		return nil
+	}
+
+	return stopProxy(ctx, &i.BaseInjector, &i.Args.CommandArgs, i.Runtime.ProxyPort)
+}
+
+func (i *DelayInjector) GetFingerprint() string {
+	return http.GetProxyFingerprint(&i.BaseInjector, &i.Args.ProxyArgs)
+}
@@ -0,0 +1,101 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector/http"
+)
+
+// The matched queries are answered by an error packet of the proxy instead of the server
+func init() {
+	injector.Register(TargetMySQL, FaultError, func() injector.IInjector { return &MySQLErrorInjector{} })
+}
+
+type MySQLErrorInjector struct {
+	injector.BaseInjector
+	Args    MySQLErrorArgs
+	Runtime MySQLErrorRuntime
+}
+
+type MySQLErrorArgs struct {
+	CommandArgs
+	Code int `json:"code,omitempty"`
+}
+
+type MySQLErrorRuntime struct {
+	http.ProxyRuntime
+}
+
+func (i *MySQLErrorInjector) GetArgs() interface{} {
+	return &i.Args
+}
+
+func (i *MySQLErrorInjector) GetRuntime() interface{} {
+	return &i.Runtime
+}
+
+func (i *MySQLErrorInjector) SetDefault() {
+	i.BaseInjector.SetDefault()
+
+	setCommandDefault(TargetMySQL, &i.Args.CommandArgs)
+	if i.Args.Code == 0 {
+		i.Args.Code = DefaultMySQLCode
+	}
+}
+
+func (i *MySQLErrorInjector) SetOption(cmd *cobra.Command) {
+	setCommandOption(cmd, &i.Args.CommandArgs)
+	cmd.Flags().IntVarP(&i.Args.Code, "code", "C", 0, fmt.Sprintf("mysql error code of the reply(default %d)", DefaultMySQLCode))
+}
+
+func (i *MySQLErrorInjector) Validator(ctx context.Context) error {
+	if err := i.BaseInjector.Validator(ctx); err != nil {
+		return err
+	}
+
+	if i.Args.Code <= 0 || i.Args.Code > 65535 {
+		return fmt.Errorf("\"code\" must in (0, 65535]")
+	}
+
+	return validateCommandArgs(ctx, &i.BaseInjector, &i.Args.CommandArgs)
+}
+
+func (i *MySQLErrorInjector) Inject(ctx context.Context) error {
+	proxyPort, err := startProxy(ctx, &i.BaseInjector, &i.Args.CommandArgs, ModeError, fmt.Sprintf("%d", i.Args.Code))
+	if err != nil {
+		return err
+	}
+
+	i.Runtime.ProxyPort = proxyPort
+	return nil
+}
+
+func (i *MySQLErrorInjector) Recover(ctx context.Context) error {
+	if i.BaseInjector.Recover(ctx) == nil {
+		return nil
+	}
+
+	return stopProxy(ctx, &i.BaseInjector, &i.Args.CommandArgs, i.Runtime.ProxyPort)
+}
+
+func (i *MySQLErrorInjector) GetFingerprint() string {
+	return http.GetProxyFingerprint(&i.BaseInjector, &i.Args.ProxyArgs)
+}
@@ -0,0 +1,104 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector/http"
+	"regexp"
+)
+
+var prefixRegexp = regexp.MustCompile(`^[A-Z]+$`)
+
+// The matched commands are answered by an error reply of the proxy instead of the server
+func init() {
+	injector.Register(TargetRedis, FaultError, func() injector.IInjector { return &RedisErrorInjector{} })
+}
+
+type RedisErrorInjector struct {
+	injector.BaseInjector
+	Args    RedisErrorArgs
+	Runtime RedisErrorRuntime
+}
+
+type RedisErrorArgs struct {
+	CommandArgs
+	Prefix string `json:"prefix,omitempty"`
+}
+
+type RedisErrorRuntime struct {
+	http.ProxyRuntime
+}
+
+func (i *RedisErrorInjector) GetArgs() interface{} {
+	return &i.Args
+}
+
+func (i *RedisErrorInjector) GetRuntime() interface{} {
+	return &i.Runtime
+}
+
+func (i *RedisErrorInjector) SetDefault() {
+	i.BaseInjector.SetDefault()
+
+	setCommandDefault(TargetRedis, &i.Args.CommandArgs)
+	if i.Args.Prefix == "" {
+		i.Args.Prefix = DefaultRedisPrefix
+	}
+}
+
+func (i *RedisErrorInjector) SetOption(cmd *cobra.Command) {
+	setCommandOption(cmd, &i.Args.CommandArgs)
+	cmd.Flags().StringVarP(&i.Args.Prefix, "prefix", "P", "", fmt.Sprintf("error prefix of the reply, eg: LOADING, READONLY, BUSY(default %s)", DefaultRedisPrefix))
+}
+
+func (i *RedisErrorInjector) Validator(ctx context.Context) error {
+	if err := i.BaseInjector.Validator(ctx); err != nil {
+		return err
+	}
+
+	if !prefixRegexp.MatchString(i.Args.Prefix) {
+		return fmt.Errorf("\"prefix\" must be upper case letters, eg: ERR")
+	}
+
+	return validateCommandArgs(ctx, &i.BaseInjector, &i.Args.CommandArgs)
+}
+
+func (i *RedisErrorInjector) Inject(ctx context.Context) error {
+	proxyPort, err := startProxy(ctx, &i.BaseInjector, &i.Args.CommandArgs, ModeError, i.Args.Prefix)
+	if err != nil {
+		return err
+	}
+
+	i.Runtime.ProxyPort = proxyPort
+	return nil
+}
+
+func (i *RedisErrorInjector) Recover(ctx context.Context) error {
+	if i.BaseInjector.Recover(ctx) == nil {
+		return nil
+	}
+
+	return stopProxy(ctx, &i.BaseInjector, &i.Args.CommandArgs, i.Runtime.ProxyPort)
+}
+
+func (i *RedisErrorInjector) GetFingerprint() string {
+	return http.GetProxyFingerprint(&i.BaseInjector, &i.Args.ProxyArgs)
+}
@@ -0,0 +1,162 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package network
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/net"
+	"strconv"
+	"strings"
+)
+
+// iptables -w -S | grep CHAOSMETA_BH_
+
+func init() {
+	injector.Register(TargetNetwork, FaultBlackhole, func() injector.IInjector { return &BlackholeInjector{} })
+}
+
+type BlackholeInjector struct {
+	injector.BaseInjector
+	Args    BlackholeArgs
+	Runtime BlackholeRuntime
+}
+
+type BlackholeArgs struct {
+	AllowIp   string `json:"allow_ip,omitempty"`
+	AllowPort string `json:"allow_port,omitempty"`
+	Backend   string `json:"backend,omitempty"`
+}
+
+type BlackholeRuntime struct {
+	Backend string `json:"backend,omitempty"`
+}
+
+func (i *BlackholeInjector) GetArgs() interface{} {
+	return &i.Args
+}
+
+func (i *BlackholeInjector) GetRuntime() interface{} {
+	return &i.Runtime
+}
+
+func (i *BlackholeInjector) SetDefault() {
+	i.BaseInjector.SetDefault()
+
+	if i.Args.Backend == "" {
+		i.Args.Backend = net.FirewallBackendAuto
+	}
+}
+
+func (i *BlackholeInjector) SetOption(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&i.Args.AllowIp, "allow-ip", "", "ip list still reachable, eg: the node ip for the probes of kubelet. eg: 10.10.0.0/16,192.168.2.5")
+	cmd.Flags().StringVar(&i.Args.AllowPort, "allow-port", "", "local port list still reachable, eg: the health check port. eg: 8080,15021")
+	cmd.Flags().StringVar(&i.Args.Backend, "backend", "", fmt.Sprintf("firewall backend, support: %s, %s, %s(default, detect automatically)",
+		net.FirewallBackendIptablesLegacy, net.FirewallBackendIptablesNft, net.FirewallBackendAuto))
+}
+
+func (i *BlackholeInjector) Validator(ctx context.Context) error {
+	if err := i.BaseInjector.Validator(ctx); err != nil {
+		return err
+	}
+
+	// dropping all the traffic of the host would cut off chaosmetad itself
+	if i.Info.ContainerId == "" {
+		return fmt.Errorf("only support container, please provide \"container-id\"")
+	}
+
+	if i.Args.AllowIp != "" {
+		allowIPs, err := net.GetValidIPList(i.Args.AllowIp, true)
+		if err != nil {
+			return fmt.Errorf("\"allow-ip\"[%s] is invalid: %s", i.Args.AllowIp, err.Error())
+		}
+		i.Args.AllowIp = strings.Join(allowIPs, ",")
+	}
+
+	if _, err := i.getAllowPorts(); err != nil {
+		return fmt.Errorf("\"allow-port\"[%s] is invalid: %s", i.Args.AllowPort, err.Error())
+	}
+
+	if i.Args.Backend == net.FirewallBackendNft {
+		return fmt.Errorf("\"backend\" only support: %s, %s, %s", net.FirewallBackendIptablesLegacy, net.FirewallBackendIptablesNft, net.FirewallBackendAuto)
+	}
+
+	if err := net.CheckFirewallBackend(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Args.Backend, []string{net.FamilyIPv4}); err != nil {
+		return fmt.Errorf("\"backend\"[%s] is invalid: %s", i.Args.Backend, err.Error())
+	}
+
+	return nil
+}
+
+func (i *BlackholeInjector) getAllowPorts() ([]int, error) {
+	var ports []int
+	if i.Args.AllowPort == "" {
+		return ports, nil
+	}
+
+	for _, unit := range strings.Split(i.Args.AllowPort, ",") {
+		port, err := strconv.Atoi(strings.TrimSpace(unit))
+		if err != nil || port <= 0 || port > 65535 {
+			return nil, fmt.Errorf("%s is not a valid port", unit)
+		}
+		ports = append(ports, port)
+	}
+
+	return ports, nil
+}
+
+func (i *BlackholeInjector) getBlackhole() *net.Blackhole {
+	b := &net.Blackhole{Uid: i.Info.Uid}
+	if i.Args.AllowIp != "" {
+		b.AllowIPs = strings.Split(i.Args.AllowIp, ",")
+	}
+	b.AllowPorts, _ = i.getAllowPorts()
+
+	return b
+}
+
+func (i *BlackholeInjector) Inject(ctx context.Context) error {
+	i.Runtime.Backend = i.Args.Backend
+	if i.Runtime.Backend == net.FirewallBackendAuto {
+		backend, err := net.DetectFirewallBackend(ctx, i.Info.ContainerRuntime, i.Info.ContainerId)
+		if err != nil {
+			return fmt.Errorf("detect firewall backend error: %s", err.Error())
+		}
+		i.Runtime.Backend = backend
+	}
+
+	return net.AddBlackhole(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Runtime.Backend, i.getBlackhole())
+}
+
+func (i *BlackholeInjector) Recover(ctx context.Context) error {
+	if i.BaseInjector.Recover(ctx) == nil {
+		return nil
+	}
+
+	// the backend is unknown if inject failed before detecting, the rules are not added in this case
+	if i.Runtime.Backend == "" {
+		return nil
+	}
+
+	return net.DeleteBlackhole(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Runtime.Backend, i.getBlackhole())
+}
+
+func (i *BlackholeInjector) GetFingerprint() string {
+	return i.GetResourceFingerprint(FirewallResource, FaultBlackhole)
+}
@@ -27,6 +27,11 @@ const (
 	TargetNetwork = "network"
 	DirectionOut  = "out"
 
+	// TcResource the root qdisc of an interface is occupied by one tc fault
+	TcResource       = "tc"
+	LinkResource     = "link"
+	FirewallResource = "firewall"
+
 	FaultOccupy = "occupy"
 	OccupyKey   = "chaosmeta_occupy"
 
@@ -40,10 +45,18 @@ const (
 
 	FaultCorrupt = "corrupt"
 
+	FaultPartition   = "partition"
+	PartitionComment = "chaosmeta_partition_%s"
+
 	FaultReorder   = "reorder"
 	DefaultGap     = 3
 	DefaultLatency = "1s"
 
+	FaultNicDown = "nicdown"
+	NicDownKey   = "chaosmeta_nicdown"
+
+	FaultBlackhole = "blackhole"
+
 	//NetworkExec = "chaosmeta_network"
 )
 
@@ -62,7 +75,7 @@ func execRecover(ctx context.Context, cr, cId, netInterface string) error {
 	}
 
 	if isTcExist {
-		return net.ClearTcRule(ctx, cr, cId, netInterface)
+		return net.ClearTcRootQdisc(ctx, cr, cId, netInterface)
 	}
 
 	return nil
@@ -44,6 +44,7 @@ type DuplicateArgs struct {
 	DstIp     string `json:"dst_ip,omitempty"`
 	SrcPort   string `json:"src_port,omitempty"`
 	DstPort   string `json:"dst_port,omitempty"`
+	Protocol  string `json:"protocol,omitempty"`
 	Force     bool   `json:"force,omitempty"`
 }
 
@@ -82,6 +83,7 @@ func (i *DuplicateInjector) SetOption(cmd *cobra.Command) {
 	cmd.Flags().StringVar(&i.Args.DstIp, "dst-ip", "", "filter condition: destination ip. eg: 10.10.0.0/16,192.168.2.5,192.168.1.0/24")
 	cmd.Flags().StringVar(&i.Args.SrcPort, "src-port", "", "filter condition: source port. eg: 8080,9090,12000/8")
 	cmd.Flags().StringVar(&i.Args.DstPort, "dst-port", "", "filter condition: destination port. eg: 8080,9090,12000/8")
+	cmd.Flags().StringVar(&i.Args.Protocol, "protocol", "", fmt.Sprintf("filter condition: protocol, support: %s, %s, %s", net.ProtocolTCP, net.ProtocolUDP, net.ProtocolICMP))
 }
 
 // Validator Only one tc network failure can be executed at the same time
@@ -133,6 +135,12 @@ func (i *DuplicateInjector) Validator(ctx context.Context) error {
 		}
 	}
 
+	if i.Args.Protocol != "" {
+		if _, err := net.GetValidProtocol(i.Args.Protocol, i.Args.SrcPort != "" || i.Args.DstPort != ""); err != nil {
+			return fmt.Errorf("\"protocol\"[%s] is invalid: %s", i.Args.Protocol, err.Error())
+		}
+	}
+
 	exist, err := net.ExistTCRootQdisc(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Args.Interface)
 	if err != nil {
 		return fmt.Errorf("check tc rule error: %s", err.Error())
@@ -155,7 +163,7 @@ func (i *DuplicateInjector) Inject(ctx context.Context) error {
 		}
 	}
 
-	if i.Args.SrcIp == "" && i.Args.DstIp == "" && i.Args.SrcPort == "" && i.Args.DstPort == "" {
+	if i.Args.SrcIp == "" && i.Args.DstIp == "" && i.Args.SrcPort == "" && i.Args.DstPort == "" && i.Args.Protocol == "" {
 		return net.AddNetemQdisc(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Args.Interface, "", FaultDuplicate, fmt.Sprintf("%d", i.Args.Percent))
 	}
 
@@ -177,7 +185,7 @@ func (i *DuplicateInjector) Inject(ctx context.Context) error {
 		}
 	}
 
-	if err := net.AddFilter(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Args.Interface, "1:4", i.Args.SrcIp, i.Args.DstIp, i.Args.SrcPort, i.Args.DstPort); err != nil {
+	if err := net.AddFilter(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Args.Interface, "1:4", i.Args.SrcIp, i.Args.DstIp, i.Args.SrcPort, i.Args.DstPort, i.Args.Protocol); err != nil {
 		return undoTcWithErr(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Args.Interface, fmt.Sprintf("add filter for %s error: %s", i.Args.Interface, err.Error()))
 	}
 
@@ -191,3 +199,7 @@ func (i *DuplicateInjector) Recover(ctx context.Context) error {
 
 	return execRecover(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Args.Interface)
 }
+
+func (i *DuplicateInjector) GetFingerprint() string {
+	return i.GetResourceFingerprint(TcResource, i.Args.Interface)
+}
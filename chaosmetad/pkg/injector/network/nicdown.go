@@ -0,0 +1,183 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package network
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/log"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/cmdexec"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/namespace"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/net"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/process"
+)
+
+// The interface is turned down and up by a detached process, which brings the interface up by itself when the
+// experiment timeout is reached, so the recovery does not depend on any connection through the interface
+func init() {
+	injector.Register(TargetNetwork, FaultNicDown, func() injector.IInjector { return &NicDownInjector{} })
+}
+
+type NicDownInjector struct {
+	injector.BaseInjector
+	Args    NicDownArgs
+	Runtime NicDownRuntime
+}
+
+type NicDownArgs struct {
+	Interface string `json:"interface"`
+	Duration  string `json:"duration,omitempty"`
+	Interval  string `json:"interval,omitempty"`
+}
+
+type NicDownRuntime struct{}
+
+func (i *NicDownInjector) GetArgs() interface{} {
+	return &i.Args
+}
+
+func (i *NicDownInjector) GetRuntime() interface{} {
+	return &i.Runtime
+}
+
+func (i *NicDownInjector) SetOption(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&i.Args.Interface, "interface", "i", "", "network interface to turn down. eg: eth0")
+	cmd.Flags().StringVarP(&i.Args.Duration, "duration", "d", "", "how long the interface is down each time, default until timeout. eg: 10s")
+	cmd.Flags().StringVarP(&i.Args.Interval, "interval", "I", "", "flap the interface, how long the interface is up between two downs, default not flap. eg: 30s")
+}
+
+func (i *NicDownInjector) Validator(ctx context.Context) error {
+	if err := i.BaseInjector.Validator(ctx); err != nil {
+		return err
+	}
+
+	if i.Info.Timeout == "" {
+		return fmt.Errorf("\"timeout\" is required, the interface is brought up by itself when the timeout is reached")
+	}
+
+	timeout, _ := utils.GetTimeSecond(i.Info.Timeout)
+	if i.Args.Duration != "" {
+		duration, err := utils.GetTimeSecond(i.Args.Duration)
+		if err != nil {
+			return fmt.Errorf("\"duration\"[%s] is invalid: %s", i.Args.Duration, err.Error())
+		}
+
+		if duration <= 0 || duration > timeout {
+			return fmt.Errorf("\"duration\" must in (0, timeout]")
+		}
+	}
+
+	if i.Args.Interval != "" {
+		if i.Args.Duration == "" {
+			return fmt.Errorf("\"duration\" is required when \"interval\" is provided")
+		}
+
+		interval, err := utils.GetTimeSecond(i.Args.Interval)
+		if err != nil {
+			return fmt.Errorf("\"interval\"[%s] is invalid: %s", i.Args.Interval, err.Error())
+		}
+
+		if interval <= 0 {
+			return fmt.Errorf("\"interval\" must larger than 0")
+		}
+	}
+
+	if !cmdexec.SupportCmd("ip") {
+		return fmt.Errorf("not support command \"ip\"")
+	}
+
+	if i.Args.Interface == "" {
+		return fmt.Errorf("\"interface\" is empty")
+	}
+
+	exist, err := net.ExistInterface(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Args.Interface)
+	if err != nil {
+		return fmt.Errorf("check interface[%s] exist error: %s", i.Args.Interface, err.Error())
+	}
+
+	if !exist {
+		return fmt.Errorf("interface[%s] is not exist", i.Args.Interface)
+	}
+
+	return nil
+}
+
+// getNicDownKey is in the command line of the detached process, to find it in recover
+func (i *NicDownInjector) getNicDownKey() string {
+	return fmt.Sprintf("%s_%s", NicDownKey, i.Info.Uid)
+}
+
+func (i *NicDownInjector) Inject(ctx context.Context) error {
+	timeout, _ := utils.GetTimeSecond(i.Info.Timeout)
+	duration, interval := timeout, int64(0)
+	if i.Args.Duration != "" {
+		duration, _ = utils.GetTimeSecond(i.Args.Duration)
+	}
+
+	if i.Args.Interval != "" {
+		interval, _ = utils.GetTimeSecond(i.Args.Interval)
+	}
+
+	cmd := cmdexec.GetExecnsBashCmd(i.Info.ContainerRuntime, getNicDownCmd(i.getNicDownKey(), i.Args.Interface, timeout, duration, interval))
+	var err error
+	if i.Info.ContainerRuntime == "" {
+		err = cmdexec.StartBashCmd(ctx, cmd)
+	} else {
+		_, err = cmdexec.ExecContainer(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, []string{namespace.NET}, cmd, cmdexec.ExecStart)
+	}
+
+	if err != nil {
+		if err := i.Recover(ctx); err != nil {
+			log.GetLogger(ctx).Warnf("undo error: %s", err.Error())
+		}
+
+		return fmt.Errorf("start nic down process error: %s", err.Error())
+	}
+
+	return nil
+}
+
+func (i *NicDownInjector) Recover(ctx context.Context) error {
+	if i.BaseInjector.Recover(ctx) == nil {
+		return nil
+	}
+
+	if err := process.CheckExistAndKillByKey(ctx, i.getNicDownKey()); err != nil {
+		return fmt.Errorf("kill nic down process error: %s", err.Error())
+	}
+
+	return net.SetLinkUp(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Args.Interface)
+}
+
+func (i *NicDownInjector) GetFingerprint() string {
+	return i.GetResourceFingerprint(LinkResource, i.Args.Interface)
+}
+
+// getNicDownCmd turns the interface down for duration seconds, then up for interval seconds if flapping, until the
+// deadline of timeout seconds, the interface is always up when the command exits
+func getNicDownCmd(key, netInterface string, timeout, duration, interval int64) string {
+	flap := "break"
+	if interval > 0 {
+		flap = fmt.Sprintf("d=$((end-SECONDS)); [ $d -gt %d ] && d=%d; sleep $d", interval, interval)
+	}
+
+	return fmt.Sprintf(": %s; end=$((SECONDS+%d)); while [ $SECONDS -lt $end ]; do %s; d=$((end-SECONDS)); [ $d -gt %d ] && d=%d; sleep $d; %s; %s; done; %s",
+		key, timeout, net.GetLinkDownCmd(netInterface), duration, duration, net.GetLinkUpCmd(netInterface), flap, net.GetLinkUpCmd(netInterface))
+}
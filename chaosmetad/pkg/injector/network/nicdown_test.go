@@ -0,0 +1,53 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package network
+
+import (
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/cmdexec"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func Test_getNicDownCmdInContainer(t *testing.T) {
+	// the fake ip records the link operations instead of changing the interface
+	dir := t.TempDir()
+	record := filepath.Join(dir, "ip.log")
+	if err := os.WriteFile(filepath.Join(dir, "ip"), []byte(fmt.Sprintf("#!/bin/bash\necho \"$*\" >> %s\n", record)), 0755); err != nil {
+		t.Fatalf("write fake ip error: %s", err.Error())
+	}
+
+	// the same quoting as the execns command, the shell in the container is simulated by bash
+	cmd := cmdexec.GetExecnsBashCmd("docker", getNicDownCmd("chaosmeta-nicdown-test", "eth0", 2, 1, 0))
+	c := exec.Command("/bin/bash", "-c", fmt.Sprintf("/bin/bash -c \"%s\"", cmd))
+	c.Env = append(os.Environ(), fmt.Sprintf("PATH=%s:%s", dir, os.Getenv("PATH")))
+	if out, err := c.CombinedOutput(); err != nil {
+		t.Fatalf("exec nic down error: %s, output: %s", err.Error(), out)
+	}
+
+	data, err := os.ReadFile(record)
+	if err != nil {
+		t.Fatalf("read record error: %s", err.Error())
+	}
+
+	want := "link set dev eth0 down\nlink set dev eth0 up\nlink set dev eth0 up\n"
+	if string(data) != want {
+		t.Errorf("link operations = %q, want %q", data, want)
+	}
+}
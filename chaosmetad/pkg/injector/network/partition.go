@@ -0,0 +1,209 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package network
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/log"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/net"
+	"strings"
+)
+
+// iptables -w -L INPUT -n | grep chaosmeta_partition
+// nft list table inet chaosmeta
+
+func init() {
+	injector.Register(TargetNetwork, FaultPartition, func() injector.IInjector { return &PartitionInjector{} })
+}
+
+type PartitionInjector struct {
+	injector.BaseInjector
+	Args    PartitionArgs
+	Runtime PartitionRuntime
+}
+
+type PartitionArgs struct {
+	GroupA  string `json:"group_a"`
+	GroupB  string `json:"group_b"`
+	Backend string `json:"backend,omitempty"`
+}
+
+type PartitionRuntime struct {
+	// Backend is the detected firewall backend, recover must use the same one as inject
+	Backend string `json:"backend,omitempty"`
+}
+
+func (i *PartitionInjector) GetArgs() interface{} {
+	return &i.Args
+}
+
+func (i *PartitionInjector) GetRuntime() interface{} {
+	return &i.Runtime
+}
+
+func (i *PartitionInjector) SetDefault() {
+	i.BaseInjector.SetDefault()
+
+	if i.Args.Backend == "" {
+		i.Args.Backend = net.FirewallBackendAuto
+	}
+}
+
+func (i *PartitionInjector) SetOption(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&i.Args.GroupA, "group-a", "a", "", "ip list of one side of the partition. eg: 10.10.0.0/16,192.168.2.5")
+	cmd.Flags().StringVarP(&i.Args.GroupB, "group-b", "b", "", "ip list of the other side of the partition. eg: 192.168.1.0/24,192.168.3.6")
+	cmd.Flags().StringVar(&i.Args.Backend, "backend", "", fmt.Sprintf("firewall backend, support: %s, %s, %s, %s(default, detect automatically)",
+		net.FirewallBackendIptablesLegacy, net.FirewallBackendIptablesNft, net.FirewallBackendNft, net.FirewallBackendAuto))
+}
+
+func (i *PartitionInjector) Validator(ctx context.Context) error {
+	if err := i.BaseInjector.Validator(ctx); err != nil {
+		return err
+	}
+
+	if i.Args.GroupA == "" || i.Args.GroupB == "" {
+		return fmt.Errorf("\"group-a\" and \"group-b\" are both required")
+	}
+
+	groupA, err := net.GetValidIPList(i.Args.GroupA, true)
+	if err != nil {
+		return fmt.Errorf("\"group-a\"[%s] is invalid: %s", i.Args.GroupA, err.Error())
+	}
+
+	groupB, err := net.GetValidIPList(i.Args.GroupB, true)
+	if err != nil {
+		return fmt.Errorf("\"group-b\"[%s] is invalid: %s", i.Args.GroupB, err.Error())
+	}
+
+	for _, a := range groupA {
+		for _, b := range groupB {
+			if a == b {
+				return fmt.Errorf("%s is in both \"group-a\" and \"group-b\"", a)
+			}
+		}
+	}
+
+	// normalize to make the rules of recover the same as inject
+	i.Args.GroupA, i.Args.GroupB = strings.Join(groupA, ","), strings.Join(groupB, ",")
+
+	families := i.getFamilies(groupA, groupB)
+	if len(families) == 0 {
+		return fmt.Errorf("\"group-a\" and \"group-b\" have no ip of the same family")
+	}
+
+	if err := net.CheckFirewallBackend(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Args.Backend, families); err != nil {
+		return fmt.Errorf("\"backend\"[%s] is invalid: %s", i.Args.Backend, err.Error())
+	}
+
+	return nil
+}
+
+// getFamilies returns the ip families that both groups have
+func (i *PartitionInjector) getFamilies(groupA, groupB []string) []string {
+	var families []string
+	for _, family := range []string{net.FamilyIPv4, net.FamilyIPv6} {
+		if len(net.FilterIPListByFamily(groupA, family)) > 0 && len(net.FilterIPListByFamily(groupB, family)) > 0 {
+			families = append(families, family)
+		}
+	}
+
+	return families
+}
+
+// getRules drops the traffic of both directions between the two groups, FORWARD covers the traffic routed by this host, eg: to containers
+func (i *PartitionInjector) getRules() []*net.FirewallRule {
+	comment := fmt.Sprintf(PartitionComment, i.Info.Uid)
+	groupA, groupB := strings.Split(i.Args.GroupA, ","), strings.Split(i.Args.GroupB, ",")
+	var rules []*net.FirewallRule
+	for _, family := range i.getFamilies(groupA, groupB) {
+		a := strings.Join(net.FilterIPListByFamily(groupA, family), ",")
+		b := strings.Join(net.FilterIPListByFamily(groupB, family), ",")
+		isV6 := family == net.FamilyIPv6
+		for _, chain := range []string{net.ChainInput, net.ChainOutput, net.ChainForward} {
+			rules = append(rules,
+				&net.FirewallRule{Chain: chain, Src: a, Dst: b, Comment: comment, IPv6: isV6},
+				&net.FirewallRule{Chain: chain, Src: b, Dst: a, Comment: comment, IPv6: isV6},
+			)
+		}
+	}
+
+	return rules
+}
+
+func (i *PartitionInjector) Inject(ctx context.Context) error {
+	i.Runtime.Backend = i.Args.Backend
+	if i.Runtime.Backend == net.FirewallBackendAuto {
+		backend, err := net.DetectFirewallBackend(ctx, i.Info.ContainerRuntime, i.Info.ContainerId)
+		if err != nil {
+			return fmt.Errorf("detect firewall backend error: %s", err.Error())
+		}
+		i.Runtime.Backend = backend
+	}
+
+	firewall, err := net.GetFirewall(i.Runtime.Backend)
+	if err != nil {
+		return err
+	}
+
+	log.GetLogger(ctx).Debugf("use firewall backend: %s", i.Runtime.Backend)
+	for _, rule := range i.getRules() {
+		if err := firewall.AddDropRule(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, rule); err != nil {
+			if undoErr := i.clearRules(ctx); undoErr != nil {
+				log.GetLogger(ctx).Warnf("undo partition rules error: %s", undoErr.Error())
+			}
+			return fmt.Errorf("add drop rule to chain %s error: %s", rule.Chain, err.Error())
+		}
+	}
+
+	return nil
+}
+
+// clearRules uses the backend of inject, experiments injected before the backend was recorded use the "iptables" command
+func (i *PartitionInjector) clearRules(ctx context.Context) error {
+	firewall, err := net.GetFirewall(i.Runtime.Backend)
+	if err != nil {
+		return err
+	}
+
+	var errList []string
+	for _, rule := range i.getRules() {
+		if !firewall.ExistDropRule(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, rule) {
+			continue
+		}
+
+		if err := firewall.DeleteDropRule(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, rule); err != nil {
+			errList = append(errList, fmt.Sprintf("delete drop rule from chain %s error: %s", rule.Chain, err.Error()))
+		}
+	}
+
+	if len(errList) > 0 {
+		return fmt.Errorf("%s", strings.Join(errList, "; "))
+	}
+
+	return nil
+}
+
+func (i *PartitionInjector) Recover(ctx context.Context) error {
+	if i.BaseInjector.Recover(ctx) == nil {
+		return nil
+	}
+
+	return i.clearRules(ctx)
+}
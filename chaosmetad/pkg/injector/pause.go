@@ -0,0 +1,129 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package injector
+
+import (
+	"context"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/log"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/storage"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/errutil"
+	"runtime/debug"
+)
+
+// scenarioTarget is the target of the scenario injector, which imports this package
+const scenarioTarget = "scenario"
+
+// ProcessPause lifts the fault of an experiment temporarily and keeps its record, the timeout and the pulse still
+// take effect, a paused experiment is finished directly when recovered
+func ProcessPause(ctx context.Context, uid string) (code int, msg string) {
+	logger := log.GetLogger(ctx)
+	defer func() {
+		if err := recover(); err != any(nil) {
+			logger.Debug(string(debug.Stack()))
+			code, msg = errutil.UnknownErr, fmt.Sprintf("ProcessPause Exception: %v", err)
+		}
+	}()
+
+	logger.Debugf("uid: %s", uid)
+	ctx = utils.GetCtxWithUid(ctx, uid)
+	db, err := storage.GetExperimentStore()
+	if err != nil {
+		return errutil.DBErr, fmt.Sprintf("connect db error: %s", err.Error())
+	}
+
+	exp, err := db.GetByUid(uid)
+	if err != nil {
+		return errutil.DBErr, fmt.Sprintf("query experiment by uid[%s] error: %s", uid, err.Error())
+	}
+
+	// the faults of a scenario are experiments with their own uid, which are paused one by one
+	if exp.Target == scenarioTarget {
+		return errutil.BadArgsErr, fmt.Sprintf("experiment[%s] is a scenario, please pause its faults instead", uid)
+	}
+
+	if exp.Status != utils.StatusSuccess && exp.Status != utils.StatusIdle {
+		return errutil.BadArgsErr, fmt.Sprintf("experiment[%s] is %s, only %s or %s experiment can be paused", uid, exp.Status, utils.StatusSuccess, utils.StatusIdle)
+	}
+
+	// the fault of an idle pulse experiment is already recovered
+	if exp.Status == utils.StatusSuccess {
+		i, err := NewInjector(exp.Target, exp.Fault)
+		if err != nil {
+			return errutil.InternalErr, fmt.Sprintf("find injector by target[%s] and fault[%s] error: %s", exp.Target, exp.Fault, err.Error())
+		}
+
+		if err := i.LoadInjector(exp, i.GetArgs(), i.GetRuntime()); err != nil {
+			return errutil.InternalErr, fmt.Sprintf("load experiment to injector error: %s", err.Error())
+		}
+
+		if err := i.Recover(ctx); err != nil {
+			return errutil.RecoverErr, fmt.Sprintf("pause recover error: %s", err.Error())
+		}
+	}
+
+	if err := db.UpdateStatus(uid, utils.StatusPaused); err != nil {
+		return errutil.DBErr, fmt.Sprintf("update status[%s] for experiment[%s] error: %s", utils.StatusPaused, uid, err.Error())
+	}
+
+	logger.Info("pause success")
+	return errutil.NoErr, "success"
+}
+
+// ProcessResume injects the fault of a paused experiment again
+func ProcessResume(ctx context.Context, uid string) (code int, msg string) {
+	logger := log.GetLogger(ctx)
+	defer func() {
+		if err := recover(); err != any(nil) {
+			logger.Debug(string(debug.Stack()))
+			code, msg = errutil.UnknownErr, fmt.Sprintf("ProcessResume Exception: %v", err)
+		}
+	}()
+
+	logger.Debugf("uid: %s", uid)
+	ctx = utils.GetCtxWithUid(ctx, uid)
+	db, err := storage.GetExperimentStore()
+	if err != nil {
+		return errutil.DBErr, fmt.Sprintf("connect db error: %s", err.Error())
+	}
+
+	exp, err := db.GetByUid(uid)
+	if err != nil {
+		return errutil.DBErr, fmt.Sprintf("query experiment by uid[%s] error: %s", uid, err.Error())
+	}
+
+	if exp.Status != utils.StatusPaused {
+		return errutil.BadArgsErr, fmt.Sprintf("experiment[%s] is %s, only %s experiment can be resumed", uid, exp.Status, utils.StatusPaused)
+	}
+
+	i, err := NewInjector(exp.Target, exp.Fault)
+	if err != nil {
+		return errutil.InternalErr, fmt.Sprintf("find injector by target[%s] and fault[%s] error: %s", exp.Target, exp.Fault, err.Error())
+	}
+
+	if err := i.LoadInjector(exp, i.GetArgs(), i.GetRuntime()); err != nil {
+		return errutil.InternalErr, fmt.Sprintf("load experiment to injector error: %s", err.Error())
+	}
+
+	if code, msg := reInject(ctx, i, uid, "resume"); code != errutil.NoErr {
+		return code, msg
+	}
+
+	logger.Info("resume success")
+	return errutil.NoErr, "success"
+}
@@ -23,5 +23,18 @@ const (
 
 	FaultProcessStop = "stop"
 
+	FaultProcessFdfull = "fdfull"
+
+	FaultProcessPriority = "priority"
+	DefaultNice          = 19
+	IoClassNone          = "none"
+	IoClassRealtime      = "realtime"
+	IoClassBestEffort    = "best-effort"
+	IoClassIdle          = "idle"
+
+	FaultProcessFuncDelay = "funcdelay"
+	FuncDelayScriptPrefix = "/tmp/chaosmeta_funcdelay_"
+	DefaultFuncDelayLib   = "libc"
+
 	//ProcessExec = "chaosmeta_process"
 )
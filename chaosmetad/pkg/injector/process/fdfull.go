@@ -0,0 +1,154 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package process
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/log"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/cmdexec"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/process"
+	"os"
+	"strings"
+)
+
+// Only the soft limit of open files is lowered, so the target process itself is able to raise it back, which is rare
+func init() {
+	injector.Register(TargetProcess, FaultProcessFdfull, func() injector.IInjector { return &FdfullInjector{} })
+}
+
+type FdfullInjector struct {
+	injector.BaseInjector
+	Args    FdfullArgs
+	Runtime FdfullRuntime
+}
+
+type FdfullArgs struct {
+	PidList string `json:"pid_list,omitempty"`
+	Key     string `json:"key,omitempty"`
+	Count   int    `json:"count,omitempty"`
+}
+
+type FdfullRuntime struct {
+	// OldSoftLimit pid to the soft limit of open files before injection
+	OldSoftLimit map[int]string `json:"old_soft_limit,omitempty"`
+}
+
+func (i *FdfullInjector) GetArgs() interface{} {
+	return &i.Args
+}
+
+func (i *FdfullInjector) GetRuntime() interface{} {
+	return &i.Runtime
+}
+
+func (i *FdfullInjector) SetOption(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&i.Args.PidList, "pid-list", "p", "", "target process's pid, list split by \",\", eg: 9595,9696")
+	cmd.Flags().StringVarP(&i.Args.Key, "key", "k", "", "the key used to grep to get target process, the effect is equivalent to \"ps -ef | grep [key]\". if \"pid-list\" provided, \"key\" will be ignored")
+	cmd.Flags().IntVarP(&i.Args.Count, "count", "c", 0, "count of fd the target process can still open（default 0, means any new fd fails）")
+}
+
+func (i *FdfullInjector) Validator(ctx context.Context) error {
+	if err := i.BaseInjector.Validator(ctx); err != nil {
+		return err
+	}
+
+	if !cmdexec.SupportCmd("prlimit") {
+		return fmt.Errorf("not support cmd \"prlimit\"")
+	}
+
+	if i.Args.Count < 0 {
+		return fmt.Errorf("\"count\" can not less than 0")
+	}
+
+	if _, err := process.GetPidListByListStrAndKey(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Args.PidList, i.Args.Key); err != nil {
+		return fmt.Errorf("\"pid-list\" or \"key\" is invalid: %s", err.Error())
+	}
+
+	return nil
+}
+
+func getSoftNofile(ctx context.Context, pid int) (string, error) {
+	re, err := cmdexec.RunBashCmdWithOutput(ctx, fmt.Sprintf("prlimit --pid %d --nofile --noheadings --output SOFT", pid))
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(re), nil
+}
+
+func setSoftNofile(ctx context.Context, pid int, limit string) error {
+	return cmdexec.RunBashCmdWithoutOutput(ctx, fmt.Sprintf("prlimit --pid %d --nofile=%s:", pid, limit))
+}
+
+func (i *FdfullInjector) Inject(ctx context.Context) error {
+	pidList, err := process.GetPidListByListStrAndKey(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Args.PidList, i.Args.Key)
+	if err != nil {
+		return err
+	}
+
+	i.Runtime.OldSoftLimit = make(map[int]string)
+	for _, pid := range pidList {
+		oldLimit, err := getSoftNofile(ctx, pid)
+		if err != nil {
+			return i.getErrWithUndo(ctx, fmt.Sprintf("get nofile limit of pid[%d] error: %s", pid, err.Error()))
+		}
+
+		fdList, err := os.ReadDir(fmt.Sprintf("/proc/%d/fd", pid))
+		if err != nil {
+			return i.getErrWithUndo(ctx, fmt.Sprintf("get fd of pid[%d] error: %s", pid, err.Error()))
+		}
+
+		if err := setSoftNofile(ctx, pid, fmt.Sprintf("%d", len(fdList)+i.Args.Count)); err != nil {
+			return i.getErrWithUndo(ctx, fmt.Sprintf("set nofile limit of pid[%d] error: %s", pid, err.Error()))
+		}
+		i.Runtime.OldSoftLimit[pid] = oldLimit
+	}
+
+	return nil
+}
+
+func (i *FdfullInjector) getErrWithUndo(ctx context.Context, msg string) error {
+	if err := i.Recover(ctx); err != nil {
+		log.GetLogger(ctx).Warnf("undo error: %s", err.Error())
+	}
+
+	return errors.New(msg)
+}
+
+func (i *FdfullInjector) Recover(ctx context.Context) error {
+	if i.BaseInjector.Recover(ctx) == nil {
+		return nil
+	}
+
+	logger := log.GetLogger(ctx)
+	for pid, limit := range i.Runtime.OldSoftLimit {
+		if exist, _ := process.ExistPid(ctx, pid); !exist {
+			logger.Warnf("pid[%d] is not exist, skip", pid)
+			continue
+		}
+
+		if err := setSoftNofile(ctx, pid, limit); err != nil {
+			return fmt.Errorf("restore nofile limit of pid[%d] to %s error: %s", pid, limit, err.Error())
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,277 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package process
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/crclient"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/log"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/cmdexec"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/filesys"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/process"
+	"os"
+	"strings"
+	"time"
+)
+
+// A bpf program attached to a uprobe can not sleep, so the calling process is stopped by bpf_send_signal(SIGSTOP) at
+// the entry of the function, and continued by bpftrace after the delay. Note that all threads of the process are
+// stopped, not only the calling one. Requires bpftrace and kernel 5.3+
+func init() {
+	injector.Register(TargetProcess, FaultProcessFuncDelay, func() injector.IInjector { return &FuncDelayInjector{} })
+}
+
+type FuncDelayInjector struct {
+	injector.BaseInjector
+	Args    FuncDelayArgs
+	Runtime FuncDelayRuntime
+}
+
+type FuncDelayArgs struct {
+	Pid      int    `json:"pid,omitempty"`
+	Key      string `json:"key,omitempty"`
+	Binary   string `json:"binary,omitempty"`
+	Function string `json:"function"`
+	Delay    string `json:"delay"`
+	Percent  int    `json:"percent,omitempty"`
+}
+
+type FuncDelayRuntime struct {
+	PidList    []int  `json:"pid_list,omitempty"`
+	BinaryPath string `json:"binary_path,omitempty"`
+}
+
+func (i *FuncDelayInjector) GetArgs() interface{} {
+	return &i.Args
+}
+
+func (i *FuncDelayInjector) GetRuntime() interface{} {
+	return &i.Runtime
+}
+
+func (i *FuncDelayInjector) SetDefault() {
+	i.BaseInjector.SetDefault()
+
+	if i.Args.Binary == "" {
+		i.Args.Binary = DefaultFuncDelayLib
+	}
+
+	if i.Args.Percent == 0 {
+		i.Args.Percent = 100
+	}
+}
+
+func (i *FuncDelayInjector) SetOption(cmd *cobra.Command) {
+	cmd.Flags().IntVarP(&i.Args.Pid, "pid", "p", 0, "target process's pid in host, not supported in container")
+	cmd.Flags().StringVarP(&i.Args.Key, "key", "k", "", "the key used to grep to get target processes, if neither \"pid\" nor \"key\" provided in container, the main process of the container is the target")
+	cmd.Flags().StringVarP(&i.Args.Binary, "binary", "b", "", fmt.Sprintf("path of the binary or library in the target's filesystem which contains the function, default \"%s\" means the libc loaded by the target", DefaultFuncDelayLib))
+	cmd.Flags().StringVarP(&i.Args.Function, "function", "f", "", "symbol of the function to delay, such as \"connect\"")
+	cmd.Flags().StringVarP(&i.Args.Delay, "delay", "d", "", "delay of each call, such as \"100ms\", \"1s\"")
+	cmd.Flags().IntVarP(&i.Args.Percent, "percent", "P", 0, "percent of the calls to delay, default 100")
+}
+
+func (i *FuncDelayInjector) Validator(ctx context.Context) error {
+	if err := i.BaseInjector.Validator(ctx); err != nil {
+		return err
+	}
+
+	if !cmdexec.SupportCmd("bpftrace") {
+		return fmt.Errorf("not support cmd \"bpftrace\"")
+	}
+
+	if i.Args.Function == "" {
+		return fmt.Errorf("\"function\" is empty")
+	}
+
+	delay, err := time.ParseDuration(i.Args.Delay)
+	if err != nil || delay <= 0 {
+		return fmt.Errorf("\"delay\" is not a valid duration: %s", i.Args.Delay)
+	}
+
+	if i.Args.Percent < 1 || i.Args.Percent > 100 {
+		return fmt.Errorf("\"percent\" must be in range [1, 100]")
+	}
+
+	pidList, err := i.getPidList(ctx)
+	if err != nil {
+		return err
+	}
+
+	binaryPath, err := getBinaryPath(pidList[0], i.Args.Binary)
+	if err != nil {
+		return err
+	}
+
+	probe := fmt.Sprintf("uprobe:%s:%s", binaryPath, i.Args.Function)
+	re, err := cmdexec.RunBashCmdWithOutput(ctx, fmt.Sprintf("bpftrace -l '%s'", probe))
+	if err != nil || strings.TrimSpace(re) == "" {
+		return fmt.Errorf("probe[%s] not found, check \"binary\" and \"function\"", probe)
+	}
+
+	i.Runtime.PidList, i.Runtime.BinaryPath = pidList, binaryPath
+	return nil
+}
+
+// getPidList return pidList in host's pid ns, which is what the bpf program sees
+func (i *FuncDelayInjector) getPidList(ctx context.Context) ([]int, error) {
+	if i.Info.ContainerRuntime == "" {
+		if i.Args.Pid > 0 {
+			if _, err := process.GetProcessByPid(ctx, "", "", i.Args.Pid); err != nil {
+				return nil, fmt.Errorf("get process by pid[%d] error: %s", i.Args.Pid, err.Error())
+			}
+			return []int{i.Args.Pid}, nil
+		}
+
+		if i.Args.Key == "" {
+			return nil, fmt.Errorf("must provide \"pid\" or \"key\"")
+		}
+	} else if i.Args.Pid > 0 {
+		return nil, fmt.Errorf("\"pid\" is not supported in container, use \"key\" instead")
+	}
+
+	if i.Args.Key != "" {
+		pidList, err := process.GetPidListByKey(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Args.Key)
+		if err != nil {
+			return nil, fmt.Errorf("get process by key[%s] error: %s", i.Args.Key, err.Error())
+		}
+		if len(pidList) == 0 {
+			return nil, fmt.Errorf("no process grep by key: %s", i.Args.Key)
+		}
+		return pidList, nil
+	}
+
+	client, err := crclient.GetClient(ctx, i.Info.ContainerRuntime)
+	if err != nil {
+		return nil, fmt.Errorf("get %s client error: %s", i.Info.ContainerRuntime, err.Error())
+	}
+
+	pid, err := client.GetPidById(ctx, i.Info.ContainerId)
+	if err != nil {
+		return nil, fmt.Errorf("get pid of container[%s] error: %s", i.Info.ContainerId, err.Error())
+	}
+	return []int{pid}, nil
+}
+
+// getBinaryPath return the path of the binary seen from the host, through the root of the target process
+func getBinaryPath(pid int, binary string) (string, error) {
+	root := fmt.Sprintf("/proc/%d/root", pid)
+	if binary != DefaultFuncDelayLib {
+		return root + binary, nil
+	}
+
+	maps, err := os.ReadFile(fmt.Sprintf("/proc/%d/maps", pid))
+	if err != nil {
+		return "", fmt.Errorf("read maps of pid[%d] error: %s", pid, err.Error())
+	}
+
+	for _, line := range strings.Split(string(maps), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 6 {
+			continue
+		}
+
+		path := fields[5]
+		name := path[strings.LastIndex(path, "/")+1:]
+		if strings.HasPrefix(name, "libc.so") || strings.HasPrefix(name, "libc-") {
+			return root + path, nil
+		}
+	}
+
+	return "", fmt.Errorf("libc is not loaded by pid[%d], provide \"binary\"", pid)
+}
+
+func (i *FuncDelayInjector) getScriptPath() string {
+	return fmt.Sprintf("%s%s.bt", FuncDelayScriptPrefix, i.Info.Uid)
+}
+
+func (i *FuncDelayInjector) getScript() string {
+	delay, _ := time.ParseDuration(i.Args.Delay)
+
+	var pidFilter []string
+	for _, pid := range i.Runtime.PidList {
+		pidFilter = append(pidFilter, fmt.Sprintf("pid == %d", pid))
+	}
+
+	filter := fmt.Sprintf("(%s)", strings.Join(pidFilter, " || "))
+	if i.Args.Percent < 100 {
+		filter = fmt.Sprintf("%s && rand %% 100 < %d", filter, i.Args.Percent)
+	}
+
+	script := fmt.Sprintf("BEGIN { printf(\"[success]inject success\\n\"); }\n"+
+		"uprobe:%s:%s /%s/ { signal(\"SIGSTOP\"); system(\"(sleep %.3f; kill -CONT %%d) &\", pid); }\n",
+		i.Runtime.BinaryPath, i.Args.Function, filter, delay.Seconds())
+
+	if i.Info.Timeout != "" {
+		timeout, _ := utils.GetTimeSecond(i.Info.Timeout)
+		script += fmt.Sprintf("interval:s:%d { exit(); }\n", timeout)
+	}
+
+	return script
+}
+
+func (i *FuncDelayInjector) Inject(ctx context.Context) error {
+	scriptPath := i.getScriptPath()
+	if err := os.WriteFile(scriptPath, []byte(i.getScript()), 0644); err != nil {
+		return fmt.Errorf("write bpftrace script[%s] error: %s", scriptPath, err.Error())
+	}
+
+	if _, err := cmdexec.StartBashCmdAndWaitPid(ctx, fmt.Sprintf("bpftrace --unsafe %s", scriptPath), 0); err != nil {
+		if err := i.Recover(ctx); err != nil {
+			log.GetLogger(ctx).Warnf("undo error: %s", err.Error())
+		}
+
+		return fmt.Errorf("start bpftrace error: %s", err.Error())
+	}
+
+	return nil
+}
+
+func (i *FuncDelayInjector) Recover(ctx context.Context) error {
+	if i.BaseInjector.Recover(ctx) == nil {
+		return nil
+	}
+
+	scriptPath := i.getScriptPath()
+	if err := process.CheckExistAndKillByKey(ctx, scriptPath); err != nil {
+		return fmt.Errorf("kill bpftrace of %s error: %s", scriptPath, err.Error())
+	}
+
+	// a process stopped right before bpftrace exits would never be continued
+	logger := log.GetLogger(ctx)
+	for _, pid := range i.Runtime.PidList {
+		if exist, _ := process.ExistPid(ctx, pid); exist {
+			if err := process.KillPidWithSignal(ctx, pid, process.SIGCONT); err != nil {
+				logger.Warnf("continue pid[%d] error: %s", pid, err.Error())
+			}
+		}
+	}
+
+	exist, err := filesys.ExistFile(scriptPath)
+	if err != nil {
+		return fmt.Errorf("check file[%s] exist error: %s", scriptPath, err.Error())
+	}
+
+	if exist {
+		return os.Remove(scriptPath)
+	}
+
+	return nil
+}
@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"github.com/spf13/cobra"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/log"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/cmdexec"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/process"
 )
@@ -38,11 +39,15 @@ type KillInjector struct {
 type KillArgs struct {
 	Pid        int    `json:"pid,omitempty"`
 	Key        string `json:"key,omitempty"`
+	Regex      string `json:"regex,omitempty"`
 	Signal     int    `json:"signal,omitempty"`
 	RecoverCmd string `json:"recover_cmd,omitempty"`
+	DryRun     bool   `json:"dry_run,omitempty"`
 }
 
 type KillRuntime struct {
+	// Targets the processes killed, or would be killed in dry run
+	Targets []string `json:"targets,omitempty"`
 }
 
 func (i *KillInjector) GetArgs() interface{} {
@@ -66,8 +71,14 @@ func (i *KillInjector) SetOption(cmd *cobra.Command) {
 
 	cmd.Flags().IntVarP(&i.Args.Pid, "pid", "p", 0, "target process's pid")
 	cmd.Flags().StringVarP(&i.Args.Key, "key", "k", "", "the key used to grep to get target process, the effect is equivalent to \"ps -ef | grep [key]\". if \"pid\" provided, \"key\" will be ignored")
+	cmd.Flags().StringVarP(&i.Args.Regex, "regex", "e", "", "the regular expression matching the whole command line of target processes. if \"pid\" or \"key\" provided, \"regex\" will be ignored")
 	cmd.Flags().IntVarP(&i.Args.Signal, "signal", "s", 0, fmt.Sprintf("send target signal to the target process（default %d）", process.SIGKILL))
 	cmd.Flags().StringVarP(&i.Args.RecoverCmd, "recover-cmd", "r", "", "the cmd which execute in the recover stage")
+	cmd.Flags().BoolVar(&i.Args.DryRun, "dry-run", false, "only list the target processes in the experiment's runtime, without sending any signal")
+}
+
+func (i *KillInjector) GetExtraCaps(ctx context.Context) ([]string, error) {
+	return getSignalCaps(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Args.Pid, i.Args.Key, i.Args.Regex)
 }
 
 func (i *KillInjector) Validator(ctx context.Context) error {
@@ -79,30 +90,39 @@ func (i *KillInjector) Validator(ctx context.Context) error {
 		return fmt.Errorf("signal[%d] is invalid, must larget than 0", i.Args.Signal)
 	}
 
-	if i.Args.Pid > 0 {
-		if _, err := process.GetProcessByPid(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Args.Pid); err != nil {
-			return fmt.Errorf("get process by pid[%d] error: %s", i.Args.Pid, err.Error())
-		}
-	} else if i.Args.Key != "" {
-		if _, err := process.GetProcessByKey(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Args.Key); err != nil {
-			return fmt.Errorf("get process by key[%s] error: %s", i.Args.Key, err.Error())
-		}
-	} else {
-		return fmt.Errorf("must provide \"pid\" or \"key\"")
+	if _, err := getTargetProcess(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Args.Pid, i.Args.Key, i.Args.Regex); err != nil {
+		return err
 	}
 
 	return nil
 }
 
 func (i *KillInjector) Inject(ctx context.Context) error {
+	proList, err := getTargetProcess(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Args.Pid, i.Args.Key, i.Args.Regex)
+	if err != nil {
+		return err
+	}
+
+	i.Runtime.Targets = formatProcessList(proList)
+	if i.Args.DryRun {
+		log.GetLogger(ctx).Infof("dry run, target processes: %v", i.Runtime.Targets)
+		return nil
+	}
+
 	if i.Args.Pid > 0 {
 		if err := process.SignalProcessByPid(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Args.Pid, i.Args.Signal); err != nil {
 			return err
 		}
-	} else {
+	} else if i.Args.Key != "" {
 		if err := process.SignalProcessByKey(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Args.Key, i.Args.Signal); err != nil {
 			return err
 		}
+	} else {
+		for _, unit := range proList {
+			if err := process.SignalProcessByPid(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, unit.Pid, i.Args.Signal); err != nil {
+				return fmt.Errorf("signal pid[%d] error: %s", unit.Pid, err.Error())
+			}
+		}
 	}
 
 	return nil
@@ -113,7 +133,7 @@ func (i *KillInjector) Recover(ctx context.Context) error {
 		return nil
 	}
 
-	if i.Args.RecoverCmd != "" {
+	if i.Args.RecoverCmd != "" && !i.Args.DryRun {
 		return cmdexec.ExecBackGroundCommon(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Args.RecoverCmd)
 	}
 
@@ -0,0 +1,266 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package process
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/log"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/cmdexec"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/process"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// nice and ionice are attributes of threads, so they are changed on all threads of the target processes.
+// Threads created after injection inherit the new priority from the thread which creates them
+func init() {
+	injector.Register(TargetProcess, FaultProcessPriority, func() injector.IInjector { return &PriorityInjector{} })
+}
+
+var ioClassNum = map[string]int{
+	IoClassNone:       0,
+	IoClassRealtime:   1,
+	IoClassBestEffort: 2,
+	IoClassIdle:       3,
+}
+
+type PriorityInjector struct {
+	injector.BaseInjector
+	Args    PriorityArgs
+	Runtime PriorityRuntime
+}
+
+type PriorityArgs struct {
+	PidList string `json:"pid_list,omitempty"`
+	Key     string `json:"key,omitempty"`
+	Nice    int    `json:"nice,omitempty"`
+	IoClass string `json:"io_class,omitempty"`
+	IoLevel int    `json:"io_level,omitempty"`
+}
+
+type PriorityRuntime struct {
+	OldNice    map[int]int    `json:"old_nice,omitempty"`
+	OldIoClass map[int]string `json:"old_io_class,omitempty"`
+	OldIoLevel map[int]int    `json:"old_io_level,omitempty"`
+}
+
+func (i *PriorityInjector) GetArgs() interface{} {
+	return &i.Args
+}
+
+func (i *PriorityInjector) GetRuntime() interface{} {
+	return &i.Runtime
+}
+
+func (i *PriorityInjector) SetDefault() {
+	i.BaseInjector.SetDefault()
+
+	if i.Args.Nice == 0 {
+		i.Args.Nice = DefaultNice
+	}
+}
+
+func (i *PriorityInjector) SetOption(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&i.Args.PidList, "pid-list", "p", "", "target process's pid, list split by \",\", eg: 9595,9696")
+	cmd.Flags().StringVarP(&i.Args.Key, "key", "k", "", "the key used to grep to get target process, the effect is equivalent to \"ps -ef | grep [key]\". if \"pid-list\" provided, \"key\" will be ignored")
+	cmd.Flags().IntVarP(&i.Args.Nice, "nice", "n", 0, fmt.Sprintf("nice value in range [-20, 19], the larger the lower cpu priority（default %d）", DefaultNice))
+	cmd.Flags().StringVarP(&i.Args.IoClass, "io-class", "c", "", fmt.Sprintf("io scheduling class, support: %s, %s, %s, %s（default not change）", IoClassNone, IoClassRealtime, IoClassBestEffort, IoClassIdle))
+	cmd.Flags().IntVarP(&i.Args.IoLevel, "io-level", "l", 0, fmt.Sprintf("io priority in range [0, 7] of class \"%s\" and \"%s\", the larger the lower priority", IoClassRealtime, IoClassBestEffort))
+}
+
+func (i *PriorityInjector) Validator(ctx context.Context) error {
+	if err := i.BaseInjector.Validator(ctx); err != nil {
+		return err
+	}
+
+	if i.Args.Nice < -20 || i.Args.Nice > 19 {
+		return fmt.Errorf("\"nice\" must be in range [-20, 19]")
+	}
+
+	if i.Args.IoClass != "" {
+		if _, ok := ioClassNum[i.Args.IoClass]; !ok {
+			return fmt.Errorf("\"io-class\" is not support: %s", i.Args.IoClass)
+		}
+
+		if !cmdexec.SupportCmd("ionice") {
+			return fmt.Errorf("not support cmd \"ionice\"")
+		}
+	}
+
+	if i.Args.IoLevel < 0 || i.Args.IoLevel > 7 {
+		return fmt.Errorf("\"io-level\" must be in range [0, 7]")
+	}
+
+	if !cmdexec.SupportCmd("renice") {
+		return fmt.Errorf("not support cmd \"renice\"")
+	}
+
+	if _, err := process.GetPidListByListStrAndKey(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Args.PidList, i.Args.Key); err != nil {
+		return fmt.Errorf("\"pid-list\" or \"key\" is invalid: %s", err.Error())
+	}
+
+	return nil
+}
+
+func getTidList(pid int) (string, error) {
+	taskList, err := os.ReadDir(fmt.Sprintf("/proc/%d/task", pid))
+	if err != nil {
+		return "", err
+	}
+
+	var tidList []string
+	for _, task := range taskList {
+		tidList = append(tidList, task.Name())
+	}
+
+	return strings.Join(tidList, " "), nil
+}
+
+func getNice(ctx context.Context, pid int) (int, error) {
+	re, err := cmdexec.RunBashCmdWithOutput(ctx, fmt.Sprintf("ps -o ni= -p %d", pid))
+	if err != nil {
+		return 0, err
+	}
+
+	return strconv.Atoi(strings.TrimSpace(re))
+}
+
+func setNice(ctx context.Context, pid, nice int) error {
+	tidList, err := getTidList(pid)
+	if err != nil {
+		return fmt.Errorf("get threads of pid[%d] error: %s", pid, err.Error())
+	}
+
+	return cmdexec.RunBashCmdWithoutOutput(ctx, fmt.Sprintf("renice %d -p %s", nice, tidList))
+}
+
+// getIoPriority parses the output of "ionice -p", such as "best-effort: prio 4", "idle"
+func getIoPriority(ctx context.Context, pid int) (string, int, error) {
+	re, err := cmdexec.RunBashCmdWithOutput(ctx, fmt.Sprintf("ionice -p %d", pid))
+	if err != nil {
+		return "", 0, err
+	}
+
+	re = strings.TrimSpace(re)
+	class, level, _ := strings.Cut(re, ": prio ")
+	if _, ok := ioClassNum[class]; !ok {
+		return "", 0, fmt.Errorf("unexpected output: %s", re)
+	}
+
+	if level == "" {
+		return class, 0, nil
+	}
+
+	levelInt, err := strconv.Atoi(level)
+	if err != nil {
+		return "", 0, fmt.Errorf("unexpected output: %s", re)
+	}
+
+	return class, levelInt, nil
+}
+
+func setIoPriority(ctx context.Context, pid int, class string, level int) error {
+	tidList, err := getTidList(pid)
+	if err != nil {
+		return fmt.Errorf("get threads of pid[%d] error: %s", pid, err.Error())
+	}
+
+	cmd := fmt.Sprintf("ionice -c %d -p %s", ioClassNum[class], tidList)
+	if class == IoClassRealtime || class == IoClassBestEffort {
+		cmd = fmt.Sprintf("ionice -c %d -n %d -p %s", ioClassNum[class], level, tidList)
+	}
+
+	return cmdexec.RunBashCmdWithoutOutput(ctx, cmd)
+}
+
+func (i *PriorityInjector) Inject(ctx context.Context) error {
+	pidList, err := process.GetPidListByListStrAndKey(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Args.PidList, i.Args.Key)
+	if err != nil {
+		return err
+	}
+
+	i.Runtime.OldNice, i.Runtime.OldIoClass, i.Runtime.OldIoLevel = make(map[int]int), make(map[int]string), make(map[int]int)
+	for _, pid := range pidList {
+		oldNice, err := getNice(ctx, pid)
+		if err != nil {
+			return i.getErrWithUndo(ctx, fmt.Sprintf("get nice of pid[%d] error: %s", pid, err.Error()))
+		}
+
+		if err := setNice(ctx, pid, i.Args.Nice); err != nil {
+			return i.getErrWithUndo(ctx, fmt.Sprintf("set nice of pid[%d] error: %s", pid, err.Error()))
+		}
+		i.Runtime.OldNice[pid] = oldNice
+
+		if i.Args.IoClass == "" {
+			continue
+		}
+
+		oldClass, oldLevel, err := getIoPriority(ctx, pid)
+		if err != nil {
+			return i.getErrWithUndo(ctx, fmt.Sprintf("get io priority of pid[%d] error: %s", pid, err.Error()))
+		}
+
+		if err := setIoPriority(ctx, pid, i.Args.IoClass, i.Args.IoLevel); err != nil {
+			return i.getErrWithUndo(ctx, fmt.Sprintf("set io priority of pid[%d] error: %s", pid, err.Error()))
+		}
+		i.Runtime.OldIoClass[pid], i.Runtime.OldIoLevel[pid] = oldClass, oldLevel
+	}
+
+	return nil
+}
+
+func (i *PriorityInjector) getErrWithUndo(ctx context.Context, msg string) error {
+	if err := i.Recover(ctx); err != nil {
+		log.GetLogger(ctx).Warnf("undo error: %s", err.Error())
+	}
+
+	return errors.New(msg)
+}
+
+func (i *PriorityInjector) Recover(ctx context.Context) error {
+	if i.BaseInjector.Recover(ctx) == nil {
+		return nil
+	}
+
+	logger := log.GetLogger(ctx)
+	for pid, nice := range i.Runtime.OldNice {
+		if exist, _ := process.ExistPid(ctx, pid); !exist {
+			logger.Warnf("pid[%d] is not exist, skip", pid)
+			continue
+		}
+
+		if err := setNice(ctx, pid, nice); err != nil {
+			return fmt.Errorf("restore nice of pid[%d] to %d error: %s", pid, nice, err.Error())
+		}
+
+		class, ok := i.Runtime.OldIoClass[pid]
+		if !ok {
+			continue
+		}
+
+		if err := setIoPriority(ctx, pid, class, i.Runtime.OldIoLevel[pid]); err != nil {
+			return fmt.Errorf("restore io priority of pid[%d] to %s error: %s", pid, class, err.Error())
+		}
+	}
+
+	return nil
+}
@@ -18,9 +18,11 @@ package process
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/spf13/cobra"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/log"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/process"
 )
 
@@ -35,11 +37,14 @@ type StopInjector struct {
 }
 
 type StopArgs struct {
-	Pid int    `json:"pid,omitempty"`
-	Key string `json:"key,omitempty"`
+	Pid   int    `json:"pid,omitempty"`
+	Key   string `json:"key,omitempty"`
+	Regex string `json:"regex,omitempty"`
 }
 
 type StopRuntime struct {
+	// PidList the processes stopped by "regex", which are continued in recover even if their command lines have changed
+	PidList []int `json:"pid_list,omitempty"`
 }
 
 func (i *StopInjector) GetArgs() interface{} {
@@ -55,6 +60,11 @@ func (i *StopInjector) SetOption(cmd *cobra.Command) {
 
 	cmd.Flags().IntVarP(&i.Args.Pid, "pid", "p", 0, "target process's pid")
 	cmd.Flags().StringVarP(&i.Args.Key, "key", "k", "", "the key used to grep to get target process, the effect is equivalent to \"ps -ef | grep [key]\". if \"pid\" provided, \"key\" will be ignored")
+	cmd.Flags().StringVarP(&i.Args.Regex, "regex", "e", "", "the regular expression matching the whole command line of target processes. if \"pid\" or \"key\" provided, \"regex\" will be ignored")
+}
+
+func (i *StopInjector) GetExtraCaps(ctx context.Context) ([]string, error) {
+	return getSignalCaps(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Args.Pid, i.Args.Key, i.Args.Regex)
 }
 
 func (i *StopInjector) Validator(ctx context.Context) error {
@@ -62,16 +72,8 @@ func (i *StopInjector) Validator(ctx context.Context) error {
 		return err
 	}
 
-	if i.Args.Pid > 0 {
-		if _, err := process.GetProcessByPid(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Args.Pid); err != nil {
-			return fmt.Errorf("get process by pid[%d] error: %s", i.Args.Pid, err.Error())
-		}
-	} else if i.Args.Key != "" {
-		if _, err := process.GetProcessByKey(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Args.Key); err != nil {
-			return fmt.Errorf("get process by key[%s] error: %s", i.Args.Key, err.Error())
-		}
-	} else {
-		return fmt.Errorf("must provide \"pid\" or \"key\"")
+	if _, err := getTargetProcess(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Args.Pid, i.Args.Key, i.Args.Regex); err != nil {
+		return err
 	}
 
 	return nil
@@ -82,10 +84,22 @@ func (i *StopInjector) Inject(ctx context.Context) error {
 		if err := process.SignalProcessByPid(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Args.Pid, process.SIGSTOP); err != nil {
 			return err
 		}
-	} else {
+	} else if i.Args.Key != "" {
 		if err := process.SignalProcessByKey(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Args.Key, process.SIGSTOP); err != nil {
 			return err
 		}
+	} else {
+		proList, err := process.GetProcessByRegex(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Args.Regex)
+		if err != nil {
+			return err
+		}
+
+		for _, unit := range proList {
+			if err := process.SignalProcessByPid(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, unit.Pid, process.SIGSTOP); err != nil {
+				return i.getErrWithUndo(ctx, fmt.Sprintf("stop pid[%d] error: %s", unit.Pid, err.Error()))
+			}
+			i.Runtime.PidList = append(i.Runtime.PidList, unit.Pid)
+		}
 	}
 
 	return nil
@@ -100,11 +114,25 @@ func (i *StopInjector) Recover(ctx context.Context) error {
 		if err := process.SignalProcessByPid(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Args.Pid, process.SIGCONT); err != nil {
 			return err
 		}
-	} else {
+	} else if i.Args.Key != "" {
 		if err := process.SignalProcessByKey(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Args.Key, process.SIGCONT); err != nil {
 			return err
 		}
+	} else {
+		for _, pid := range i.Runtime.PidList {
+			if err := process.SignalProcessByPid(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, pid, process.SIGCONT); err != nil {
+				log.GetLogger(ctx).Warnf("continue pid[%d] error: %s", pid, err.Error())
+			}
+		}
 	}
 
 	return nil
 }
+
+func (i *StopInjector) getErrWithUndo(ctx context.Context, msg string) error {
+	if err := i.Recover(ctx); err != nil {
+		log.GetLogger(ctx).Warnf("undo error: %s", err.Error())
+	}
+
+	return errors.New(msg)
+}
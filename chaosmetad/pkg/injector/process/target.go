@@ -0,0 +1,113 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package process
+
+import (
+	"context"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/crclient/base"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/cmdexec"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/namespace"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/process"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/user"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// getTargetProcess returns the target processes in container's pid namespace, by "pid", "key" or "regex" in order of priority
+func getTargetProcess(ctx context.Context, cr, cId string, pid int, key, regex string) ([]base.SimpleProcess, error) {
+	if pid > 0 {
+		if _, err := process.GetProcessByPid(ctx, cr, cId, pid); err != nil {
+			return nil, fmt.Errorf("get process by pid[%d] error: %s", pid, err.Error())
+		}
+
+		return []base.SimpleProcess{{Pid: pid}}, nil
+	}
+
+	if key != "" {
+		pidList, err := process.GetProcessByKey(ctx, cr, cId, key)
+		if err != nil {
+			return nil, fmt.Errorf("get process by key[%s] error: %s", key, err.Error())
+		}
+
+		var proList []base.SimpleProcess
+		for _, unitPid := range pidList {
+			proList = append(proList, base.SimpleProcess{Pid: unitPid})
+		}
+		return proList, nil
+	}
+
+	if regex != "" {
+		proList, err := process.GetProcessByRegex(ctx, cr, cId, regex)
+		if err != nil {
+			return nil, fmt.Errorf("get process by regex[%s] error: %s", regex, err.Error())
+		}
+		return proList, nil
+	}
+
+	return nil, fmt.Errorf("must provide \"pid\", \"key\" or \"regex\"")
+}
+
+// getSignalCaps CAP_KILL is only required to signal the processes owned by other users
+func getSignalCaps(ctx context.Context, cr, cId string, pid int, key, regex string) ([]string, error) {
+	proList, err := getTargetProcess(ctx, cr, cId, pid, key, regex)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, unit := range proList {
+		uid, err := getProcessUid(ctx, cr, cId, unit.Pid)
+		if err != nil {
+			return nil, fmt.Errorf("get owner of process[%d] error: %s", unit.Pid, err.Error())
+		}
+
+		if uid != os.Geteuid() {
+			return []string{user.CapKill}, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// getProcessUid returns the owner of the process, the pid is in the pid namespace of the container if cr is not empty
+func getProcessUid(ctx context.Context, cr, cId string, pid int) (int, error) {
+	if cr == "" {
+		info, err := os.Stat(fmt.Sprintf("/proc/%d", pid))
+		if err != nil {
+			return -1, err
+		}
+
+		return int(info.Sys().(*syscall.Stat_t).Uid), nil
+	}
+
+	re, err := cmdexec.ExecContainer(ctx, cr, cId, []string{namespace.MNT, namespace.PID}, fmt.Sprintf("stat -c %%u /proc/%d", pid), cmdexec.ExecRun)
+	if err != nil {
+		return -1, err
+	}
+
+	return strconv.Atoi(strings.TrimSpace(re))
+}
+
+func formatProcessList(proList []base.SimpleProcess) []string {
+	var re []string
+	for _, unit := range proList {
+		re = append(re, strings.TrimSpace(fmt.Sprintf("%d %s", unit.Pid, unit.Cmd)))
+	}
+	return re
+}
@@ -0,0 +1,41 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package process
+
+import (
+	"context"
+	"os"
+	"testing"
+)
+
+func Test_getSignalCaps(t *testing.T) {
+	caps, err := getSignalCaps(context.Background(), "", "", os.Getpid(), "", "")
+	if err != nil {
+		t.Fatalf("getSignalCaps() error: %s", err.Error())
+	}
+	if len(caps) != 0 {
+		t.Errorf("getSignalCaps() = %v, no capability is required to signal the processes of the same user", caps)
+	}
+
+	uid, err := getProcessUid(context.Background(), "", "", os.Getpid())
+	if err != nil {
+		t.Fatalf("getProcessUid() error: %s", err.Error())
+	}
+	if uid != os.Geteuid() {
+		t.Errorf("getProcessUid() = %d, want %d", uid, os.Geteuid())
+	}
+}
@@ -0,0 +1,203 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package injector
+
+import (
+	"context"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/log"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/storage"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/errutil"
+	"runtime/debug"
+	"strings"
+	"time"
+)
+
+const pulseSplit = ","
+
+// GetPulseSecond parse pulse args with format "[active],[idle]"
+func GetPulseSecond(pulse string) (active, idle int64, err error) {
+	pulseArr := strings.Split(pulse, pulseSplit)
+	if len(pulseArr) != 2 {
+		return -1, -1, fmt.Errorf("format must be [active],[idle], eg: 10s,20s")
+	}
+
+	active, err = utils.GetTimeSecond(strings.TrimSpace(pulseArr[0]))
+	if err != nil || active <= 0 {
+		return -1, -1, fmt.Errorf("active time[%s] must be a positive time value", pulseArr[0])
+	}
+
+	idle, err = utils.GetTimeSecond(strings.TrimSpace(pulseArr[1]))
+	if err != nil || idle <= 0 {
+		return -1, -1, fmt.Errorf("idle time[%s] must be a positive time value", pulseArr[1])
+	}
+
+	return active, idle, nil
+}
+
+// ProcessPulse runs in background after a pulse experiment is injected. It recovers and re-injects the fault
+// in turn, and recovers the experiment finally when timeout. Manual recover in any phase stops the pulse
+func ProcessPulse(ctx context.Context, uid string) (code int, msg string) {
+	logger := log.GetLogger(ctx)
+	defer func() {
+		if err := recover(); err != any(nil) {
+			logger.Debug(string(debug.Stack()))
+			code, msg = errutil.UnknownErr, fmt.Sprintf("ProcessPulse Exception: %v", err)
+		}
+	}()
+
+	ctx = utils.GetCtxWithUid(ctx, uid)
+	db, err := storage.GetExperimentStore()
+	if err != nil {
+		return errutil.DBErr, fmt.Sprintf("connect db error: %s", err.Error())
+	}
+
+	exp, err := db.GetByUid(uid)
+	if err != nil {
+		return errutil.DBErr, fmt.Sprintf("query experiment by uid[%s] error: %s", uid, err.Error())
+	}
+
+	active, idle, err := GetPulseSecond(exp.Pulse)
+	if err != nil {
+		return errutil.BadArgsErr, fmt.Sprintf("\"pulse\" of experiment[%s] is not valid: %s", uid, err.Error())
+	}
+
+	timeout, err := utils.GetTimeSecond(exp.Timeout)
+	if err != nil {
+		return errutil.BadArgsErr, fmt.Sprintf("\"timeout\" of experiment[%s] is not valid: %s", uid, err.Error())
+	}
+
+	deadline := time.Now().Add(time.Duration(timeout) * time.Second)
+	for {
+		if !sleepBefore(time.Duration(active)*time.Second, deadline) {
+			break
+		}
+
+		if finished, code, msg := pulseSwitch(ctx, uid, false); finished {
+			return code, msg
+		}
+
+		if !sleepBefore(time.Duration(idle)*time.Second, deadline) {
+			break
+		}
+
+		if finished, code, msg := pulseSwitch(ctx, uid, true); finished {
+			return code, msg
+		}
+	}
+
+	return ProcessRecover(ctx, uid)
+}
+
+// sleepBefore returns false if deadline is reached
+func sleepBefore(d time.Duration, deadline time.Time) bool {
+	remain := time.Until(deadline)
+	if remain <= d {
+		time.Sleep(remain)
+		return false
+	}
+
+	time.Sleep(d)
+	return true
+}
+
+// pulseSwitch re-inject the fault if active, otherwise recover it temporarily
+func pulseSwitch(ctx context.Context, uid string, active bool) (finished bool, code int, msg string) {
+	logger := log.GetLogger(ctx)
+	db, err := storage.GetExperimentStore()
+	if err != nil {
+		return true, errutil.DBErr, fmt.Sprintf("connect db error: %s", err.Error())
+	}
+
+	exp, err := db.GetByUid(uid)
+	if err != nil {
+		return true, errutil.DBErr, fmt.Sprintf("query experiment by uid[%s] error: %s", uid, err.Error())
+	}
+
+	// a paused experiment keeps the pulse, the switch is skipped until resumed
+	if exp.Status == utils.StatusPaused {
+		logger.Infof("experiment[%s] is paused, skip pulse switch", uid)
+		return false, errutil.NoErr, ""
+	}
+
+	if exp.Status != utils.StatusSuccess && exp.Status != utils.StatusIdle {
+		logger.Infof("experiment[%s] is %s, stop pulse", uid, exp.Status)
+		return true, errutil.NoErr, "success"
+	}
+
+	i, err := NewInjector(exp.Target, exp.Fault)
+	if err != nil {
+		return true, errutil.InternalErr, fmt.Sprintf("find injector by target[%s] and fault[%s] error: %s", exp.Target, exp.Fault, err.Error())
+	}
+
+	if err := i.LoadInjector(exp, i.GetArgs(), i.GetRuntime()); err != nil {
+		return true, errutil.InternalErr, fmt.Sprintf("load experiment to injector error: %s", err.Error())
+	}
+
+	if !active {
+		if err := i.Recover(ctx); err != nil {
+			errMsg := fmt.Sprintf("pulse recover error: %s", err.Error())
+			if err := db.UpdateStatusAndErr(uid, utils.StatusError, errMsg); err != nil {
+				logger.Warnf("update status[%s] for experiment[%s] error: %s", utils.StatusError, uid, err.Error())
+			}
+			return true, errutil.RecoverErr, errMsg
+		}
+
+		if err := db.UpdateStatus(uid, utils.StatusIdle); err != nil {
+			return true, errutil.DBErr, fmt.Sprintf("update status[%s] for experiment[%s] error: %s", utils.StatusIdle, uid, err.Error())
+		}
+
+		logger.Infof("experiment[%s] pulse idle", uid)
+		return false, errutil.NoErr, ""
+	}
+
+	if code, msg := reInject(ctx, i, uid, "pulse"); code != errutil.NoErr {
+		return true, code, msg
+	}
+
+	logger.Infof("experiment[%s] pulse active", uid)
+	return false, errutil.NoErr, ""
+}
+
+// reInject injects the fault of a loaded experiment again and saves the new runtime with status success
+func reInject(ctx context.Context, i IInjector, uid, action string) (code int, msg string) {
+	logger := log.GetLogger(ctx)
+	db, err := storage.GetExperimentStore()
+	if err != nil {
+		return errutil.DBErr, fmt.Sprintf("connect db error: %s", err.Error())
+	}
+
+	if err := i.Inject(ctx); err != nil {
+		errMsg := fmt.Sprintf("%s inject error: %s", action, err.Error())
+		if err := db.UpdateStatusAndErr(uid, utils.StatusError, errMsg); err != nil {
+			logger.Warnf("update status[%s] for experiment[%s] error: %s", utils.StatusError, uid, err.Error())
+		}
+		return errutil.InjectErr, errMsg
+	}
+
+	// runtime may change after re-inject
+	newExp, _ := i.OptionToExp(i.GetArgs(), i.GetRuntime())
+	newExp.Status = utils.StatusSuccess
+	if err := db.Update(newExp); err != nil {
+		if err := i.Recover(ctx); err != nil {
+			logger.Warnf("recover error: %s", err.Error())
+		}
+		return errutil.DBErr, fmt.Sprintf("update status[%s] for experiment[%s] error: %s", utils.StatusSuccess, uid, err.Error())
+	}
+
+	return errutil.NoErr, "success"
+}
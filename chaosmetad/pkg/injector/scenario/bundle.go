@@ -0,0 +1,322 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package scenario
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/log"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/storage"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/cmdexec"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/errutil"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/process"
+	"gopkg.in/yaml.v3"
+	"os"
+)
+
+// A scenario injects several faults as one experiment, the faults are experiments with uid "[scenario uid]-[index]".
+// The faults without offset are injected at once, the others are started by background processes after the offset.
+// Recovering the scenario cancels the pending faults and recovers all the injected ones
+func init() {
+	injector.Register(TargetScenario, FaultBundle, func() injector.IInjector { return &BundleInjector{} })
+}
+
+type BundleInjector struct {
+	injector.BaseInjector
+	Args    BundleArgs
+	Runtime BundleRuntime
+}
+
+type BundleArgs struct {
+	File   string      `json:"file,omitempty"`
+	Faults []FaultUnit `json:"faults,omitempty" yaml:"faults"`
+}
+
+// FaultUnit the args are the same as the args of the inject api, Duration is the timeout of the fault
+type FaultUnit struct {
+	Target           string                 `json:"target" yaml:"target"`
+	Fault            string                 `json:"fault" yaml:"fault"`
+	Offset           string                 `json:"offset,omitempty" yaml:"offset"`
+	Duration         string                 `json:"duration,omitempty" yaml:"duration"`
+	ContainerRuntime string                 `json:"container_runtime,omitempty" yaml:"container_runtime"`
+	ContainerId      string                 `json:"container_id,omitempty" yaml:"container_id"`
+	Args             map[string]interface{} `json:"args,omitempty" yaml:"args"`
+}
+
+type BundleRuntime struct{}
+
+func (i *BundleInjector) GetArgs() interface{} {
+	return &i.Args
+}
+
+func (i *BundleInjector) GetRuntime() interface{} {
+	return &i.Runtime
+}
+
+func (i *BundleInjector) SetOption(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&i.Args.File, "file", "f", "", "yaml file of the scenario, eg:\n"+
+		"faults:\n"+
+		"- target: cpu\n"+
+		"  fault: burn\n"+
+		"  duration: 5m\n"+
+		"  args: {percent: 80}\n"+
+		"- target: network\n"+
+		"  fault: delay\n"+
+		"  offset: 1m\n"+
+		"  duration: 2m\n"+
+		"  args: {interface: eth0, latency: 100ms}")
+}
+
+func (i *BundleInjector) Validator(ctx context.Context) error {
+	if i.Info.ContainerId != "" || i.Info.ContainerRuntime != "" {
+		return fmt.Errorf("provide the container of each fault in the scenario instead")
+	}
+
+	if err := i.BaseInjector.Validator(ctx); err != nil {
+		return err
+	}
+
+	if i.Info.Pulse != "" {
+		return fmt.Errorf("\"pulse\" is not supported by scenario")
+	}
+
+	if i.Args.File != "" {
+		if err := i.loadFile(); err != nil {
+			return err
+		}
+	}
+
+	if len(i.Args.Faults) == 0 {
+		return fmt.Errorf("\"faults\" of the scenario is empty")
+	}
+
+	if len(getFaultUid(i.Info.Uid, len(i.Args.Faults)-1)) > utils.MaxUidLength {
+		return fmt.Errorf("\"uid\" is too long for the uid of the faults: [uid]-[index]")
+	}
+
+	var timeout int64
+	if i.Info.Timeout != "" {
+		timeout, _ = utils.GetTimeSecond(i.Info.Timeout)
+	}
+
+	for index := range i.Args.Faults {
+		unit := &i.Args.Faults[index]
+		if unit.Target == TargetScenario {
+			return fmt.Errorf("fault[%d]: scenario can not be nested", index)
+		}
+
+		offset, duration, err := unit.getSeconds()
+		if err != nil {
+			return fmt.Errorf("fault[%d]: %s", index, err.Error())
+		}
+
+		if timeout > 0 && offset+duration > timeout {
+			return fmt.Errorf("fault[%d]: offset + duration exceeds the timeout of the scenario", index)
+		}
+
+		f, err := i.newFaultInjector(index)
+		if err != nil {
+			return fmt.Errorf("fault[%d]: %s", index, err.Error())
+		}
+
+		f.SetDefault()
+		if err := f.Validator(ctx); err != nil {
+			return fmt.Errorf("fault[%d] %s %s: %s", index, unit.Target, unit.Fault, err.Error())
+		}
+	}
+
+	return nil
+}
+
+func (i *BundleInjector) loadFile() error {
+	data, err := os.ReadFile(i.Args.File)
+	if err != nil {
+		return fmt.Errorf("read scenario file error: %s", err.Error())
+	}
+
+	if err := yaml.Unmarshal(data, &i.Args); err != nil {
+		return fmt.Errorf("scenario file format error: %s", err.Error())
+	}
+
+	return nil
+}
+
+func (i *BundleInjector) Inject(ctx context.Context) error {
+	for index := range i.Args.Faults {
+		offset, _, _ := i.Args.Faults[index].getSeconds()
+		var err error
+		if offset == 0 {
+			err = i.injectFault(ctx, index)
+		} else if err = cmdexec.StartScenarioFault(ctx, offset, i.Info.Uid, index); err != nil {
+			err = fmt.Errorf("start fault[%d] with offset error: %s", index, err.Error())
+		}
+
+		if err != nil {
+			if err := i.Recover(ctx); err != nil {
+				log.GetLogger(ctx).Warnf("undo error: %s", err.Error())
+			}
+
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (i *BundleInjector) Recover(ctx context.Context) error {
+	if i.BaseInjector.Recover(ctx) == nil {
+		return nil
+	}
+
+	if err := process.CheckExistAndKillByKey(ctx, getStartKey(i.Info.Uid)); err != nil {
+		return fmt.Errorf("cancel the faults with offset error: %s", err.Error())
+	}
+
+	db, err := storage.GetExperimentStore()
+	if err != nil {
+		return fmt.Errorf("connect db error: %s", err.Error())
+	}
+
+	var errMsg string
+	for index := range i.Args.Faults {
+		uid := getFaultUid(i.Info.Uid, index)
+		if _, total, err := db.QueryByFilter(&storage.ExperimentFilter{Uid: uid}); err != nil || total == 0 {
+			continue
+		}
+
+		if code, msg := injector.ProcessRecover(ctx, uid); code != errutil.NoErr {
+			errMsg = fmt.Sprintf("%srecover fault[%s] error: %s; ", errMsg, uid, msg)
+		}
+	}
+
+	if errMsg != "" {
+		return fmt.Errorf("%s", errMsg)
+	}
+
+	return nil
+}
+
+func (i *BundleInjector) injectFault(ctx context.Context, index int) error {
+	f, err := i.newFaultInjector(index)
+	if err != nil {
+		return err
+	}
+
+	if code, msg := injector.ProcessInject(ctx, f); code != errutil.NoErr {
+		return fmt.Errorf("inject fault[%d] error: %s", index, msg)
+	}
+
+	return nil
+}
+
+// newFaultInjector loads the fault like the inject api
+func (i *BundleInjector) newFaultInjector(index int) (injector.IInjector, error) {
+	unit := &i.Args.Faults[index]
+	f, err := injector.NewInjector(unit.Target, unit.Fault)
+	if err != nil {
+		return nil, fmt.Errorf("get injector of target[%s] and fault[%s] error: %s", unit.Target, unit.Fault, err.Error())
+	}
+
+	args := []byte("{}")
+	if unit.Args != nil {
+		if args, err = json.Marshal(unit.Args); err != nil {
+			return nil, fmt.Errorf("args to json error: %s", err.Error())
+		}
+	}
+
+	if err := f.LoadInjector(&storage.Experiment{
+		Uid:              getFaultUid(i.Info.Uid, index),
+		Target:           unit.Target,
+		Fault:            unit.Fault,
+		Args:             string(args),
+		Timeout:          unit.Duration,
+		ContainerRuntime: unit.ContainerRuntime,
+		ContainerId:      unit.ContainerId,
+		Creator:          i.Info.Creator,
+		Runtime:          "{}",
+	}, f.GetArgs(), f.GetRuntime()); err != nil {
+		return nil, fmt.Errorf("load args error: %s", err.Error())
+	}
+
+	return f, nil
+}
+
+func (u *FaultUnit) getSeconds() (offset, duration int64, err error) {
+	if u.Offset != "" {
+		if offset, err = utils.GetTimeSecond(u.Offset); err != nil || offset < 0 {
+			return 0, 0, fmt.Errorf("\"offset\"[%s] is invalid", u.Offset)
+		}
+	}
+
+	if u.Duration != "" {
+		if duration, err = utils.GetTimeSecond(u.Duration); err != nil || duration <= 0 {
+			return 0, 0, fmt.Errorf("\"duration\"[%s] is invalid", u.Duration)
+		}
+	}
+
+	return offset, duration, nil
+}
+
+func getFaultUid(uid string, index int) string {
+	return fmt.Sprintf("%s-%d", uid, index)
+}
+
+// getStartKey is in the command line of the processes starting the faults with offset
+func getStartKey(uid string) string {
+	return fmt.Sprintf("%s scenario %s ", utils.RootName, uid)
+}
+
+// ProcessStart injects a fault with offset of the scenario, it is executed in background after the offset
+func ProcessStart(ctx context.Context, uid string, index int) (code int, msg string) {
+	db, err := storage.GetExperimentStore()
+	if err != nil {
+		return errutil.DBErr, fmt.Sprintf("connect db error: %s", err.Error())
+	}
+
+	exp, err := db.GetByUid(uid)
+	if err != nil {
+		return errutil.DBErr, fmt.Sprintf("query scenario by uid[%s] error: %s", uid, err.Error())
+	}
+
+	if exp.Target != TargetScenario {
+		return errutil.BadArgsErr, fmt.Sprintf("experiment[%s] is not a scenario", uid)
+	}
+
+	// the scenario may be recovered when the process is waiting
+	if exp.Status != utils.StatusSuccess && exp.Status != utils.StatusCreated {
+		return errutil.NoErr, fmt.Sprintf("scenario is %s, fault[%d] is skipped", exp.Status, index)
+	}
+
+	i := &BundleInjector{}
+	if err := i.LoadInjector(exp, i.GetArgs(), i.GetRuntime()); err != nil {
+		return errutil.InternalErr, fmt.Sprintf("load scenario error: %s", err.Error())
+	}
+
+	if index < 0 || index >= len(i.Args.Faults) {
+		return errutil.BadArgsErr, fmt.Sprintf("scenario has no fault[%d]", index)
+	}
+
+	if err := i.injectFault(utils.GetCtxWithUid(ctx, uid), index); err != nil {
+		return errutil.InjectErr, err.Error()
+	}
+
+	return errutil.NoErr, "success"
+}
@@ -0,0 +1,115 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package scenario
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFaultUnit_getSeconds(t *testing.T) {
+	tests := []struct {
+		name         string
+		unit         FaultUnit
+		wantOffset   int64
+		wantDuration int64
+		wantErr      bool
+	}{
+		{name: "empty", unit: FaultUnit{}},
+		{name: "offset and duration", unit: FaultUnit{Offset: "1m", Duration: "30s"}, wantOffset: 60, wantDuration: 30},
+		{name: "invalid offset", unit: FaultUnit{Offset: "1x"}, wantErr: true},
+		{name: "zero duration", unit: FaultUnit{Duration: "0s"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			offset, duration, err := tt.unit.getSeconds()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("getSeconds() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if offset != tt.wantOffset || duration != tt.wantDuration {
+				t.Errorf("getSeconds() = %d, %d, want %d, %d", offset, duration, tt.wantOffset, tt.wantDuration)
+			}
+		})
+	}
+}
+
+func TestBundleInjector_Validator(t *testing.T) {
+	tests := []struct {
+		name    string
+		timeout string
+		faults  []FaultUnit
+		wantErr string
+	}{
+		{name: "empty", wantErr: "\"faults\" of the scenario is empty"},
+		{name: "nested", faults: []FaultUnit{{Target: TargetScenario, Fault: FaultBundle}}, wantErr: "fault[0]: scenario can not be nested"},
+		{name: "invalid offset", faults: []FaultUnit{{Target: "cpu", Fault: "burn", Offset: "-1s"}}, wantErr: "fault[0]: \"offset\"[-1s] is invalid"},
+		{
+			name:    "exceeds timeout",
+			timeout: "1m",
+			faults:  []FaultUnit{{Target: "cpu", Fault: "burn", Offset: "30s", Duration: "1m"}},
+			wantErr: "fault[0]: offset + duration exceeds the timeout of the scenario",
+		},
+		{name: "unknown fault", faults: []FaultUnit{{Target: "cpu", Fault: "unknown"}}, wantErr: "fault[0]: get injector of target[cpu] and fault[unknown] error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			i := &BundleInjector{Args: BundleArgs{Faults: tt.faults}}
+			i.Info.Uid = "a1b2c3d4"
+			i.Info.Timeout = tt.timeout
+			err := i.Validator(context.Background())
+			if err == nil || !strings.HasPrefix(err.Error(), tt.wantErr) {
+				t.Errorf("Validator() error = %v, want %s", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBundleInjector_loadFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "scenario.yaml")
+	data := "faults:\n" +
+		"- target: cpu\n" +
+		"  fault: burn\n" +
+		"  duration: 5m\n" +
+		"  args: {percent: 80}\n" +
+		"- target: network\n" +
+		"  fault: delay\n" +
+		"  offset: 1m\n"
+	if err := os.WriteFile(file, []byte(data), 0644); err != nil {
+		t.Fatalf("write scenario file error: %s", err.Error())
+	}
+
+	i := &BundleInjector{Args: BundleArgs{File: file}}
+	if err := i.loadFile(); err != nil {
+		t.Fatalf("loadFile() error = %v", err)
+	}
+
+	if len(i.Args.Faults) != 2 {
+		t.Fatalf("loadFile() got %d faults, want 2", len(i.Args.Faults))
+	}
+	if unit := i.Args.Faults[0]; unit.Target != "cpu" || unit.Fault != "burn" || unit.Duration != "5m" || unit.Args["percent"] != 80 {
+		t.Errorf("loadFile() fault[0] = %+v", unit)
+	}
+	if unit := i.Args.Faults[1]; unit.Target != "network" || unit.Offset != "1m" {
+		t.Errorf("loadFile() fault[1] = %+v", unit)
+	}
+
+	if err := (&BundleInjector{Args: BundleArgs{File: filepath.Join(t.TempDir(), "none.yaml")}}).loadFile(); err == nil {
+		t.Errorf("loadFile() should fail for a missing file")
+	}
+}
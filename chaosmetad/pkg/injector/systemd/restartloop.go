@@ -0,0 +1,117 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package systemd
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/log"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/cmdexec"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/process"
+)
+
+// The unit is restarted by a background loop on the host every "interval" seconds until recover
+func init() {
+	injector.Register(TargetSystemd, FaultSystemdRestartLoop, func() injector.IInjector { return &RestartLoopInjector{} })
+}
+
+type RestartLoopInjector struct {
+	injector.BaseInjector
+	Args    RestartLoopArgs
+	Runtime RestartLoopRuntime
+}
+
+type RestartLoopArgs struct {
+	Unit     string `json:"unit"`
+	Interval int    `json:"interval,omitempty"`
+}
+
+type RestartLoopRuntime struct {
+	UnitState
+}
+
+func (i *RestartLoopInjector) GetArgs() interface{} {
+	return &i.Args
+}
+
+func (i *RestartLoopInjector) GetRuntime() interface{} {
+	return &i.Runtime
+}
+
+func (i *RestartLoopInjector) SetDefault() {
+	i.BaseInjector.SetDefault()
+
+	if i.Args.Interval == 0 {
+		i.Args.Interval = DefaultRestartInterval
+	}
+}
+
+func (i *RestartLoopInjector) SetOption(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&i.Args.Unit, "unit", "u", "", "name of the systemd unit on the host, such as \"nginx.service\"")
+	cmd.Flags().IntVarP(&i.Args.Interval, "interval", "i", 0, fmt.Sprintf("seconds between two restarts, default %d", DefaultRestartInterval))
+}
+
+func (i *RestartLoopInjector) Validator(ctx context.Context) error {
+	if err := i.BaseInjector.Validator(ctx); err != nil {
+		return err
+	}
+
+	if i.Args.Interval <= 0 {
+		return fmt.Errorf("\"interval\" must larger than 0")
+	}
+
+	return checkUnit(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Args.Unit)
+}
+
+// getLoopKey is in the command line of the loop, to find it in recover
+func (i *RestartLoopInjector) getLoopKey() string {
+	return fmt.Sprintf("%s_%s", RestartLoopKey, i.Info.Uid)
+}
+
+func (i *RestartLoopInjector) Inject(ctx context.Context) error {
+	state, err := getUnitState(ctx, i.Args.Unit)
+	if err != nil {
+		return err
+	}
+
+	i.Runtime.UnitState = *state
+	loopCmd := fmt.Sprintf(": %s; echo \"[success]inject success\"; while true; do systemctl restart %s > /dev/null 2>&1; sleep %d; done",
+		i.getLoopKey(), i.Args.Unit, i.Args.Interval)
+	if _, err := cmdexec.StartBashCmdAndWaitPid(ctx, loopCmd, 0); err != nil {
+		if err := i.Recover(ctx); err != nil {
+			log.GetLogger(ctx).Warnf("undo error: %s", err.Error())
+		}
+
+		return fmt.Errorf("start restart loop error: %s", err.Error())
+	}
+
+	return nil
+}
+
+func (i *RestartLoopInjector) Recover(ctx context.Context) error {
+	if i.BaseInjector.Recover(ctx) == nil {
+		return nil
+	}
+
+	if err := process.CheckExistAndKillByKey(ctx, i.getLoopKey()); err != nil {
+		return fmt.Errorf("kill restart loop of unit[%s] error: %s", i.Args.Unit, err.Error())
+	}
+
+	return restoreUnit(ctx, i.Args.Unit, &i.Runtime.UnitState)
+}
@@ -0,0 +1,79 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package systemd
+
+import (
+	"context"
+	"github.com/spf13/cobra"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
+)
+
+func init() {
+	injector.Register(TargetSystemd, FaultSystemdStop, func() injector.IInjector { return &StopInjector{} })
+}
+
+type StopInjector struct {
+	injector.BaseInjector
+	Args    StopArgs
+	Runtime StopRuntime
+}
+
+type StopArgs struct {
+	Unit string `json:"unit"`
+}
+
+type StopRuntime struct {
+	UnitState
+}
+
+func (i *StopInjector) GetArgs() interface{} {
+	return &i.Args
+}
+
+func (i *StopInjector) GetRuntime() interface{} {
+	return &i.Runtime
+}
+
+func (i *StopInjector) SetOption(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&i.Args.Unit, "unit", "u", "", "name of the systemd unit on the host, such as \"nginx.service\"")
+}
+
+func (i *StopInjector) Validator(ctx context.Context) error {
+	if err := i.BaseInjector.Validator(ctx); err != nil {
+		return err
+	}
+
+	return checkUnit(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Args.Unit)
+}
+
+func (i *StopInjector) Inject(ctx context.Context) error {
+	state, err := getUnitState(ctx, i.Args.Unit)
+	if err != nil {
+		return err
+	}
+
+	i.Runtime.UnitState = *state
+	return runSystemctl(ctx, "stop", i.Args.Unit)
+}
+
+func (i *StopInjector) Recover(ctx context.Context) error {
+	if i.BaseInjector.Recover(ctx) == nil {
+		return nil
+	}
+
+	return restoreUnit(ctx, i.Args.Unit, &i.Runtime.UnitState)
+}
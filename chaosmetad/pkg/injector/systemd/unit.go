@@ -0,0 +1,143 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package systemd
+
+import (
+	"context"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/log"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/cmdexec"
+	"regexp"
+	"strings"
+)
+
+var unitNameRegexp = regexp.MustCompile(`^[a-zA-Z0-9:_.@-]+$`)
+
+// UnitState is the state of a unit before injection, which is restored in recover
+type UnitState struct {
+	ActiveState   string `json:"active_state,omitempty"`
+	UnitFileState string `json:"unit_file_state,omitempty"`
+}
+
+// checkUnit is the preflight check of all systemd faults, the unit must exist on the host
+func checkUnit(ctx context.Context, cr, cId, unit string) error {
+	if cr != "" || cId != "" {
+		return fmt.Errorf("target \"%s\" not support in container", TargetSystemd)
+	}
+
+	if unit == "" {
+		return fmt.Errorf("\"unit\" is empty")
+	}
+
+	if !unitNameRegexp.MatchString(unit) {
+		return fmt.Errorf("\"unit\" is not a valid unit name: %s", unit)
+	}
+
+	if !cmdexec.SupportCmd("systemctl") {
+		return fmt.Errorf("not support cmd \"systemctl\"")
+	}
+
+	re, err := cmdexec.RunBashCmdWithOutput(ctx, fmt.Sprintf("systemctl show %s -p LoadState", unit))
+	if err != nil {
+		return fmt.Errorf("get load state of unit[%s] error: %s", unit, err.Error())
+	}
+
+	if strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(re), "LoadState=")) == "not-found" {
+		return fmt.Errorf("unit[%s] not found", unit)
+	}
+
+	return nil
+}
+
+func getUnitState(ctx context.Context, unit string) (*UnitState, error) {
+	re, err := cmdexec.RunBashCmdWithOutput(ctx, fmt.Sprintf("systemctl show %s -p ActiveState -p UnitFileState", unit))
+	if err != nil {
+		return nil, fmt.Errorf("get state of unit[%s] error: %s", unit, err.Error())
+	}
+
+	state := &UnitState{}
+	for _, line := range strings.Split(re, "\n") {
+		kv := strings.SplitN(strings.TrimSpace(line), "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+
+		switch kv[0] {
+		case "ActiveState":
+			state.ActiveState = kv[1]
+		case "UnitFileState":
+			state.UnitFileState = kv[1]
+		}
+	}
+
+	return state, nil
+}
+
+func runSystemctl(ctx context.Context, op, unit string) error {
+	if err := cmdexec.RunBashCmdWithoutOutput(ctx, fmt.Sprintf("systemctl %s %s", op, unit)); err != nil {
+		return fmt.Errorf("systemctl %s %s error: %s", op, unit, err.Error())
+	}
+
+	return nil
+}
+
+// restoreUnit unmasks the unit, then restores its enablement and active state recorded before injection
+func restoreUnit(ctx context.Context, unit string, origin *UnitState) error {
+	now, err := getUnitState(ctx, unit)
+	if err != nil {
+		return err
+	}
+
+	if now.UnitFileState == UnitFileMasked && origin.UnitFileState != UnitFileMasked {
+		if err := runSystemctl(ctx, "unmask", unit); err != nil {
+			return err
+		}
+
+		if now, err = getUnitState(ctx, unit); err != nil {
+			return err
+		}
+	}
+
+	if now.UnitFileState != origin.UnitFileState {
+		switch origin.UnitFileState {
+		case UnitFileEnabled:
+			err = runSystemctl(ctx, "enable", unit)
+		case UnitFileDisabled:
+			err = runSystemctl(ctx, "disable", unit)
+		default:
+			log.GetLogger(ctx).Warnf("unit file state of unit[%s] is %s, expected %s", unit, now.UnitFileState, origin.UnitFileState)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	if isActive(origin.ActiveState) && !isActive(now.ActiveState) {
+		return runSystemctl(ctx, "start", unit)
+	}
+
+	if !isActive(origin.ActiveState) && isActive(now.ActiveState) {
+		return runSystemctl(ctx, "stop", unit)
+	}
+
+	return nil
+}
+
+func isActive(activeState string) bool {
+	return activeState == ActiveStateActive || activeState == ActiveStateActivating || activeState == ActiveStateReloading
+}
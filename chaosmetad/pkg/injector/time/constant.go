@@ -0,0 +1,30 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package time
+
+const (
+	TargetTime = "time"
+
+	FaultTimeSkew = "skew"
+	DefaultRate   = "1"
+
+	PreloadFile    = "/etc/ld.so.preload"
+	FaketimeRcFile = "/etc/faketimerc"
+	// ContainerLibPrefix is where libfaketime is copied to in the target container, not in /tmp which may be noexec
+	ContainerLibPrefix = "/lib/chaosmeta_libfaketime_"
+	DefaultFaketimeLib = "/usr/lib/x86_64-linux-gnu/faketime/libfaketime.so.1"
+)
@@ -0,0 +1,288 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package time
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/crclient"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/log"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/cmdexec"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/filesys"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/namespace"
+	"strconv"
+	"strings"
+)
+
+// The clock is shifted by libfaketime preloaded with "/etc/ld.so.preload" and configured by "/etc/faketimerc".
+// A time namespace is not used because it only offsets the monotonic and boottime clocks, not the wall clock.
+// Only the processes started after the injection see the shifted clock, so the target process needs a restart.
+// Without a container, every process started on the host is shifted, so it has to be confirmed.
+
+func init() {
+	injector.Register(TargetTime, FaultTimeSkew, func() injector.IInjector { return &SkewInjector{} })
+}
+
+type SkewInjector struct {
+	injector.BaseInjector
+	Args    SkewArgs
+	Runtime SkewRuntime
+}
+
+type SkewArgs struct {
+	// Offset is such as "-2h", "+30m", "90s"
+	Offset string `json:"offset"`
+	// Rate is the speed of the shifted clock, such as 2 means twice as fast as the real one
+	Rate string `json:"rate,omitempty"`
+	Lib  string `json:"lib,omitempty"`
+	// Confirm is required to inject on the host instead of a container
+	Confirm bool `json:"confirm,omitempty"`
+}
+
+type SkewRuntime struct {
+	Lib           string `json:"lib"`
+	PreloadBackup string `json:"preload_backup,omitempty"`
+	// PreloadCreated the preload file did not exist before inject, so it is removed in recover
+	PreloadCreated bool `json:"preload_created,omitempty"`
+}
+
+func (i *SkewInjector) GetArgs() interface{} {
+	return &i.Args
+}
+
+func (i *SkewInjector) GetRuntime() interface{} {
+	return &i.Runtime
+}
+
+func (i *SkewInjector) SetDefault() {
+	i.BaseInjector.SetDefault()
+
+	if i.Args.Rate == "" {
+		i.Args.Rate = DefaultRate
+	}
+
+	if i.Args.Lib == "" {
+		i.Args.Lib = DefaultFaketimeLib
+	}
+}
+
+func (i *SkewInjector) SetOption(cmd *cobra.Command) {
+	// i.BaseInjector.SetOption(cmd)
+
+	cmd.Flags().StringVarP(&i.Args.Offset, "offset", "o", "", "offset of the clock, support unit: \"s、m、h\"(default s), eg: \"-2h\" means 2 hours ago")
+	cmd.Flags().StringVarP(&i.Args.Rate, "rate", "r", "", "drift rate of the clock, eg: \"2\" means twice as fast as the real clock（default 1）")
+	cmd.Flags().StringVarP(&i.Args.Lib, "lib", "l", "", fmt.Sprintf("path of libfaketime on the host, it is copied to the target container and must match its libc（default %s）", DefaultFaketimeLib))
+	cmd.Flags().BoolVar(&i.Args.Confirm, "confirm", false, "confirm to skew the clock of all the processes started on the host, required without a container")
+}
+
+func (i *SkewInjector) Validator(ctx context.Context) error {
+	if err := i.BaseInjector.Validator(ctx); err != nil {
+		return err
+	}
+
+	if i.Info.ContainerId == "" && !i.Args.Confirm {
+		return fmt.Errorf("fault \"%s\" without a container affects the whole host, \"confirm\" must be set", i.Info.Fault)
+	}
+
+	if _, err := getOffsetSecond(i.Args.Offset); err != nil {
+		return fmt.Errorf("\"offset\"[%s] is invalid: %s", i.Args.Offset, err.Error())
+	}
+
+	rate, err := strconv.ParseFloat(i.Args.Rate, 64)
+	if err != nil || rate <= 0 {
+		return fmt.Errorf("\"rate\"[%s] must be a num larger than 0", i.Args.Rate)
+	}
+
+	if i.Args.Offset == "" && rate == 1 {
+		return fmt.Errorf("must provide \"offset\" or \"rate\"")
+	}
+
+	exist, err := filesys.ExistFile(i.Args.Lib)
+	if err != nil {
+		return fmt.Errorf("check libfaketime[%s] error: %s", i.Args.Lib, err.Error())
+	}
+	if !exist {
+		return fmt.Errorf("libfaketime[%s] is not exist, please install it first", i.Args.Lib)
+	}
+
+	exist, err = filesys.ExistPath(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, FaketimeRcFile)
+	if err != nil {
+		return fmt.Errorf("check file[%s] error: %s", FaketimeRcFile, err.Error())
+	}
+	if exist {
+		return fmt.Errorf("file[%s] is exist, a time experiment may be running, please recover first", FaketimeRcFile)
+	}
+
+	return nil
+}
+
+func (i *SkewInjector) Inject(ctx context.Context) error {
+	logger := log.GetLogger(ctx)
+	cr, cId := i.Info.ContainerRuntime, i.Info.ContainerId
+
+	i.Runtime.Lib = i.Args.Lib
+	if cr != "" {
+		i.Runtime.Lib = ContainerLibPrefix + i.Info.Uid + ".so"
+		if err := copyLibToContainer(ctx, cr, cId, i.Args.Lib, i.Runtime.Lib); err != nil {
+			return fmt.Errorf("copy libfaketime to container error: %s", err.Error())
+		}
+	}
+
+	offset, _ := getOffsetSecond(i.Args.Offset)
+	rate, _ := strconv.ParseFloat(i.Args.Rate, 64)
+	if err := filesys.OverWriteFile(ctx, cr, cId, FaketimeRcFile, getFaketimeSpec(offset, rate)); err != nil {
+		if err := i.Recover(ctx); err != nil {
+			logger.Warnf("undo error: %s", err.Error())
+		}
+		return fmt.Errorf("write file[%s] error: %s", FaketimeRcFile, err.Error())
+	}
+
+	preload, err := i.backupPreload(ctx)
+	if err != nil {
+		if err := i.Recover(ctx); err != nil {
+			logger.Warnf("undo error: %s", err.Error())
+		}
+		return fmt.Errorf("backup file[%s] error: %s", PreloadFile, err.Error())
+	}
+
+	if err := filesys.OverWriteFile(ctx, cr, cId, PreloadFile, fmt.Sprintf("%s%s\n", preload, i.Runtime.Lib)); err != nil {
+		if err := i.Recover(ctx); err != nil {
+			logger.Warnf("undo error: %s", err.Error())
+		}
+		return fmt.Errorf("write file[%s] error: %s", PreloadFile, err.Error())
+	}
+
+	return nil
+}
+
+// backupPreload returns the content of the existing preload file, which is moved to the backup file
+func (i *SkewInjector) backupPreload(ctx context.Context) (string, error) {
+	cr, cId := i.Info.ContainerRuntime, i.Info.ContainerId
+	exist, err := filesys.CheckFile(ctx, cr, cId, PreloadFile)
+	if err != nil {
+		return "", err
+	}
+	if !exist {
+		i.Runtime.PreloadCreated = true
+		return "", nil
+	}
+
+	preload, err := cmdexec.ExecCommonWithNS(ctx, cr, cId, fmt.Sprintf("cat %s", PreloadFile), []string{namespace.MNT})
+	if err != nil {
+		return "", err
+	}
+
+	backup := fmt.Sprintf("%s.chaosmeta_%s", PreloadFile, i.Info.Uid)
+	if err := filesys.CopyFile(ctx, cr, cId, PreloadFile, backup); err != nil {
+		return "", err
+	}
+	i.Runtime.PreloadBackup = backup
+
+	if preload != "" && !strings.HasSuffix(preload, "\n") {
+		preload += "\n"
+	}
+	return preload, nil
+}
+
+func (i *SkewInjector) Recover(ctx context.Context) error {
+	if i.BaseInjector.Recover(ctx) == nil {
+		return nil
+	}
+
+	cr, cId := i.Info.ContainerRuntime, i.Info.ContainerId
+	// the preload file is restored first, otherwise new processes fail to load the removed lib
+	if i.Runtime.PreloadBackup != "" {
+		if err := filesys.MoveFile(ctx, cr, cId, i.Runtime.PreloadBackup, PreloadFile); err != nil {
+			return fmt.Errorf("restore file[%s] error: %s", PreloadFile, err.Error())
+		}
+	} else if i.Runtime.PreloadCreated {
+		if err := removeIfExist(ctx, cr, cId, PreloadFile); err != nil {
+			return err
+		}
+	}
+
+	if err := removeIfExist(ctx, cr, cId, FaketimeRcFile); err != nil {
+		return err
+	}
+
+	if cr != "" && i.Runtime.Lib != "" {
+		return removeIfExist(ctx, cr, cId, i.Runtime.Lib)
+	}
+
+	return nil
+}
+
+func removeIfExist(ctx context.Context, cr, cId, file string) error {
+	exist, err := filesys.ExistPath(ctx, cr, cId, file)
+	if err != nil {
+		return fmt.Errorf("check file[%s] error: %s", file, err.Error())
+	}
+
+	if exist {
+		if err := filesys.RemoveFile(ctx, cr, cId, file); err != nil {
+			return fmt.Errorf("remove file[%s] error: %s", file, err.Error())
+		}
+	}
+
+	return nil
+}
+
+// copyLibToContainer copies the lib of host into the root filesystem of the container through "/proc/[pid]/root"
+func copyLibToContainer(ctx context.Context, cr, cId, src, dst string) error {
+	client, err := crclient.GetClient(ctx, cr)
+	if err != nil {
+		return fmt.Errorf("get %s client error: %s", cr, err.Error())
+	}
+
+	pid, err := client.GetPidById(ctx, cId)
+	if err != nil {
+		return fmt.Errorf("get pid of container[%s] error: %s", cId, err.Error())
+	}
+
+	return filesys.CopyFile(ctx, "", "", src, fmt.Sprintf("/proc/%d/root%s", pid, dst))
+}
+
+func getOffsetSecond(offset string) (int64, error) {
+	if offset == "" {
+		return 0, nil
+	}
+
+	var sign int64 = 1
+	if strings.HasPrefix(offset, "-") {
+		sign = -1
+	}
+
+	second, err := utils.GetTimeSecond(strings.TrimLeft(offset, "+-"))
+	if err != nil {
+		return 0, err
+	}
+
+	return sign * second, nil
+}
+
+// getFaketimeSpec is the relative format of libfaketime, such as "-7200 x2"
+func getFaketimeSpec(offset int64, rate float64) string {
+	spec := fmt.Sprintf("%+d", offset)
+	if rate != 1 {
+		spec = fmt.Sprintf("%s x%g", spec, rate)
+	}
+
+	return spec
+}
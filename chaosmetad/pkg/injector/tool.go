@@ -0,0 +1,86 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package injector
+
+import (
+	"context"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/namespace"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/tool"
+	"sort"
+)
+
+// requiredTools is keyed by target or "target fault", the tools of both are needed by a fault
+var requiredTools = map[string][]string{
+	"cpu burn":       {"chaosmeta_cpuburn"},
+	"cpu load":       {"chaosmeta_cpuload"},
+	"mem":            {"chaosmeta_memfill"},
+	"disk":           {"chaosmeta_diskfill"},
+	"diskio burn":    {"chaosmeta_diskburn"},
+	"diskio hang":    {"chaosmeta_diskio"},
+	"diskio limit":   {"chaosmeta_diskio"},
+	"network occupy": {"chaosmeta_occupy"},
+	"http":           {"chaosmeta_httpproxy"},
+	"grpc":           {"chaosmeta_httpproxy"},
+	"mysql":          {"chaosmeta_dbproxy"},
+	"redis":          {"chaosmeta_dbproxy"},
+	"kernel fdfull":  {"chaosmeta_fd"},
+	"kernel nproc":   {"chaosmeta_nproc"},
+	"jvm": {"ChaosMetaJVMAgent.jar", "ChaosMetaJVMAttacher.class", "MANIFEST.MF", "tools.jar", "javassist.jar",
+		"json-20190722.jar"},
+}
+
+// GetRequiredTools returns the tools needed by a fault, inContainer means the fault is injected into a container
+func GetRequiredTools(target, fault string, inContainer bool) []string {
+	var tools []string
+	for _, key := range []string{target, fmt.Sprintf("%s %s", target, fault)} {
+		tools = append(tools, requiredTools[key]...)
+	}
+
+	if inContainer && target != "container" {
+		tools = append(tools, namespace.ExecnsKey)
+	}
+
+	return tools
+}
+
+// GetAllTools returns all the tools in sorted order
+func GetAllTools() []string {
+	var tools = []string{namespace.ExecnsKey}
+	for _, list := range requiredTools {
+		for _, t := range list {
+			if !utils.StrListContain(tools, t) {
+				tools = append(tools, t)
+			}
+		}
+	}
+
+	sort.Strings(tools)
+	return tools
+}
+
+// PrepareTools fetches the missing tools of a fault and verifies their checksums before injecting
+func PrepareTools(ctx context.Context, target, fault string, inContainer bool) error {
+	for _, t := range GetRequiredTools(target, fault, inContainer) {
+		if err := tool.Prepare(ctx, t); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
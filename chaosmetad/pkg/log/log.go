@@ -71,6 +71,17 @@ func GetLogger(ctx context.Context) *logrus.Entry {
 //	})
 //}
 
+// SetLevel changes the level of the running logger
+func SetLevel(lev string) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	Level = lev
+	if logger != nil {
+		logger.SetLevel(getLogLevel(lev))
+	}
+}
+
 func setLogger() {
 	logger = logrus.New()
 	logger.SetFormatter(&logrus.TextFormatter{
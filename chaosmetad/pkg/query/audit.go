@@ -0,0 +1,74 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/bndr/gotabulate"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/audit"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/log"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/errutil"
+)
+
+func PrintAuditByUid(ctx context.Context, uid string, format string) {
+	if format != TableFormat && format != JsonFormat {
+		errutil.SolveErr(ctx, errutil.BadArgsErr, fmt.Sprintf("not support format: %s", format))
+	}
+
+	if uid == "" {
+		errutil.SolveErr(ctx, errutil.BadArgsErr, "uid is empty")
+	}
+
+	records, err := audit.QueryByUid(uid)
+	if err != nil {
+		errutil.SolveErr(ctx, errutil.InternalErr, fmt.Sprintf("query audit records error: %s", err.Error()))
+	}
+
+	logger := log.GetLogger(ctx)
+	if format == JsonFormat {
+		reBytes, err := json.Marshal(records)
+		if err != nil {
+			errutil.SolveErr(ctx, errutil.InternalErr, fmt.Sprintf("audit records change to string error: %s", err.Error()))
+		}
+
+		if log.Path != "" {
+			logger.Info(string(reBytes))
+		} else {
+			fmt.Println(string(reBytes))
+		}
+		return
+	}
+
+	var formatData string
+	if len(records) != 0 {
+		var data [][]interface{}
+		for _, r := range records {
+			data = append(data, []interface{}{r.Time, r.Caller, r.Method, r.Cmd, r.Pid, r.ExitCode, r.Error})
+		}
+
+		t := gotabulate.Create(data)
+		t.SetHeaders([]string{"TIME", "CALLER", "METHOD", "CMD", "PID", "EXIT_CODE", "ERROR"})
+		t.SetEmptyString("None")
+		t.SetAlign("left")
+		t.SetWrapStrings(true)
+		formatData = t.Render("grid")
+	}
+
+	logger.Infof("total count of audit records for experiment[%s]: %d\n%s\n", uid, len(records), formatData)
+}
@@ -36,6 +36,8 @@ type OptionExpQuery struct {
 	Creator          string `json:"creator,omitempty"`
 	ContainerId      string `json:"container_id,omitempty"`
 	ContainerRuntime string `json:"container_runtime,omitempty"`
+	StartTime        string `json:"start_time,omitempty"`
+	EndTime          string `json:"end_time,omitempty"`
 	Offset           uint   `json:"offset"`
 	Limit            uint   `json:"limit"`
 }
@@ -63,20 +65,37 @@ func PrintExpByOption(ctx context.Context, o *OptionExpQuery, ifAll bool, format
 	if dbErr != nil {
 		errutil.SolveErr(ctx, errutil.DBErr, dbErr.Error())
 	}
-	exps, total, queryErr := db.QueryByOption(o.Uid, o.Status, o.Target, o.Fault, o.Creator, o.ContainerRuntime, o.ContainerId, o.Offset, o.Limit)
+	filter := &storage.ExperimentFilter{
+		Uid:              o.Uid,
+		Status:           storage.ParseStatusList(o.Status),
+		Target:           o.Target,
+		Fault:            o.Fault,
+		Creator:          o.Creator,
+		ContainerRuntime: o.ContainerRuntime,
+		ContainerId:      o.ContainerId,
+		StartTime:        o.StartTime,
+		EndTime:          o.EndTime,
+		Offset:           o.Offset,
+		Limit:            o.Limit,
+	}
+	if err := filter.Validate(); err != nil {
+		errutil.SolveErr(ctx, errutil.BadArgsErr, err.Error())
+	}
+
+	exps, total, queryErr := db.QueryByFilter(filter)
 	if queryErr != nil {
 		errutil.SolveErr(ctx, errutil.DBErr, queryErr.Error())
 	}
 
 	if format == JsonFormat {
-		printJson(ctx, exps, total)
+		printJson(ctx, exps, total, o)
 	} else {
 		log.GetLogger(ctx).Infof("query args: %s", string(temp))
 		printTable(ctx, exps, total, ifAll)
 	}
 }
 
-func printJson(ctx context.Context, exps []*storage.Experiment, total int64) {
+func printJson(ctx context.Context, exps []*storage.Experiment, total int64, o *OptionExpQuery) {
 	logger := log.GetLogger(ctx)
 	reList := make([]model.ExperimentDataUnit, len(exps))
 	for i, exp := range exps {
@@ -86,6 +105,8 @@ func printJson(ctx context.Context, exps []*storage.Experiment, total int64) {
 	res := &model.QueryResponseData{
 		Experiments: reList,
 		Total:       total,
+		Offset:      o.Offset,
+		Limit:       o.Limit,
 	}
 
 	reBytes, err := json.Marshal(res)
@@ -109,7 +130,7 @@ func printTable(ctx context.Context, exps []*storage.Experiment, total int64, if
 			var aData []interface{}
 			if ifAll {
 				aData = []interface{}{exp.Uid, exp.Status, exp.Target, exp.Fault, exp.Args, exp.Creator, exp.Runtime,
-					exp.ContainerId, exp.ContainerRuntime, exp.Timeout, exp.Error, exp.CreateTime, exp.UpdateTime}
+					exp.ContainerId, exp.ContainerRuntime, exp.Timeout, exp.Pulse, exp.Error, exp.CreateTime, exp.UpdateTime}
 			} else {
 				aData = []interface{}{exp.Uid, exp.Status, exp.Target, exp.Fault, exp.Args}
 			}
@@ -120,7 +141,7 @@ func printTable(ctx context.Context, exps []*storage.Experiment, total int64, if
 		t := gotabulate.Create(data)
 		if ifAll {
 			t.SetHeaders([]string{"UID", "STATUS", "TARGET", "FAULT", "ARGS", "CREATOR", "RUNTIME",
-				"CONTAINER_ID", "CONTAINER_RUNTIME", "TIMEOUT", "ERROR", "CREATE_TIME", "UPDATE_TIME"})
+				"CONTAINER_ID", "CONTAINER_RUNTIME", "TIMEOUT", "PULSE", "ERROR", "CREATE_TIME", "UPDATE_TIME"})
 		} else {
 			t.SetHeaders([]string{"UID", "STATUS", "TARGET", "FAULT", "ARGS"})
 		}
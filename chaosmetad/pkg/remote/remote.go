@@ -0,0 +1,286 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package remote
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/log"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	FlagRemote    = "remote"
+	FlagSSHKey    = "ssh-key"
+	FlagRemoteDir = "remote-dir"
+
+	DefaultSSHPort   = 22
+	DefaultSSHUser   = "root"
+	DefaultRemoteDir = "/var/lib/chaosmetad/remote"
+
+	// sshErrCode is the exit code of ssh itself, such as connection failure
+	sshErrCode = 255
+)
+
+var (
+	// Addr is the target host to execute the command remotely, format: [user@]host[:port]
+	Addr string
+	Key  string
+	Dir  string
+)
+
+type Target struct {
+	User string
+	Host string
+	Port int
+}
+
+// ParseAddr parses "[user@]host[:port]", the default user is root and the default port is 22
+func ParseAddr(addr string) (*Target, error) {
+	t := &Target{User: DefaultSSHUser, Port: DefaultSSHPort}
+	if index := strings.LastIndex(addr, "@"); index >= 0 {
+		t.User, addr = addr[:index], addr[index+1:]
+		if t.User == "" {
+			return nil, fmt.Errorf("user is empty")
+		}
+	}
+
+	t.Host = addr
+	if index := strings.LastIndex(addr, ":"); index >= 0 && !strings.HasSuffix(addr, "]") {
+		port, err := strconv.Atoi(addr[index+1:])
+		if err != nil || port <= 0 || port > 65535 {
+			return nil, fmt.Errorf("port[%s] is invalid", addr[index+1:])
+		}
+		t.Host, t.Port = addr[:index], port
+	}
+
+	t.Host = strings.TrimSuffix(strings.TrimPrefix(t.Host, "["), "]")
+	if t.Host == "" {
+		return nil, fmt.Errorf("host is empty")
+	}
+
+	return t, nil
+}
+
+// StripFlags removes the remote flags from the command line args, so that the rest is executed on the remote host
+func StripFlags(args []string) []string {
+	var re []string
+	for i := 0; i < len(args); i++ {
+		name, hasValue := getFlagName(args[i])
+		if name != FlagRemote && name != FlagSSHKey && name != FlagRemoteDir {
+			re = append(re, args[i])
+			continue
+		}
+
+		if !hasValue {
+			i++
+		}
+	}
+
+	return re
+}
+
+func getFlagName(arg string) (string, bool) {
+	if !strings.HasPrefix(arg, "--") {
+		return "", false
+	}
+
+	name := strings.TrimPrefix(arg, "--")
+	if index := strings.Index(name, "="); index >= 0 {
+		return name[:index], true
+	}
+
+	return name, false
+}
+
+// Run copies the bundle to the remote host if it is changed, and executes the command there. The exit code of the
+// remote command is returned
+func Run(ctx context.Context, args []string) (int, error) {
+	t, err := ParseAddr(Addr)
+	if err != nil {
+		return 0, fmt.Errorf("\"%s\"[%s] is invalid: %s", FlagRemote, Addr, err.Error())
+	}
+
+	dir := Dir
+	if dir == "" {
+		dir = DefaultRemoteDir
+	}
+
+	if err := syncBundle(ctx, t, dir); err != nil {
+		return 0, fmt.Errorf("copy bundle to %s error: %s", t.Host, err.Error())
+	}
+
+	quoted := []string{quote(fmt.Sprintf("%s/%s", dir, utils.RootName))}
+	for _, arg := range args {
+		quoted = append(quoted, quote(arg))
+	}
+
+	c := exec.Command("ssh", append(getSSHArgs(t, "-p"), fmt.Sprintf("%s@%s", t.User, t.Host), strings.Join(quoted, " "))...)
+	c.Stdin, c.Stdout, c.Stderr = os.Stdin, os.Stdout, os.Stderr
+	log.GetLogger(ctx).Debugf("remote exec on %s: %s", t.Host, strings.Join(quoted, " "))
+	if err := c.Run(); err != nil {
+		exitErr, ok := err.(*exec.ExitError)
+		if !ok || exitErr.ExitCode() == sshErrCode {
+			return 0, fmt.Errorf("ssh to %s error: %s", t.Host, err.Error())
+		}
+
+		return exitErr.ExitCode(), nil
+	}
+
+	return 0, nil
+}
+
+// syncBundle copies chaosmetad and its tools unless the remote copy has the same manifest, a changed tool is copied
+// even if chaosmetad is the same
+func syncBundle(ctx context.Context, t *Target, dir string) error {
+	local := fmt.Sprintf("%s/%s", utils.GetRunPath(), utils.RootName)
+	manifest, err := getBundleManifest(utils.GetRunPath())
+	if err != nil {
+		return err
+	}
+
+	if err := checkRemoteDir(t, dir); err != nil {
+		return err
+	}
+
+	re, _ := runSSH(t, fmt.Sprintf("cd %s && (%s 2>/dev/null || true)", quote(dir), getManifestCmd()))
+	if strings.TrimSpace(re) == manifest {
+		return nil
+	}
+
+	log.GetLogger(ctx).Infof("copy bundle to %s:%s", t.Host, dir)
+	// the removed tools are not left behind, otherwise the manifest never matches
+	if _, err := runSSH(t, fmt.Sprintf("rm -rf %s", quote(fmt.Sprintf("%s/tools", dir)))); err != nil {
+		return err
+	}
+	scpArgs := append(getSSHArgs(t, "-P"), "-r", "-p", local, fmt.Sprintf("%s/tools", utils.GetRunPath()), fmt.Sprintf("%s@%s:%s/", t.User, t.Host, dir))
+	if out, err := exec.Command("scp", scpArgs...).CombinedOutput(); err != nil {
+		return fmt.Errorf("scp error: %s, output: %s", err.Error(), string(out))
+	}
+
+	return nil
+}
+
+// checkRemoteDir creates the dir only accessible by the ssh user, and rejects an existing dir owned by another user
+// or accessible by others, in which the bundle could be replaced before it is executed
+func checkRemoteDir(t *Target, dir string) error {
+	re, err := runSSH(t, fmt.Sprintf("mkdir -p -m 700 %s && stat -c '%%u %%a' %s && id -u", quote(dir), quote(dir)))
+	if err != nil {
+		return fmt.Errorf("create remote dir error: %s", err.Error())
+	}
+
+	return checkDirStat(dir, re)
+}
+
+// checkDirStat output is "[owner uid] [mode]\n[uid of the ssh user]"
+func checkDirStat(dir, output string) error {
+	fields := strings.Fields(output)
+	if len(fields) != 3 {
+		return fmt.Errorf("unexpected stat of remote dir: %s", output)
+	}
+
+	if fields[0] != fields[2] || fields[1] != "700" {
+		return fmt.Errorf("remote dir[%s] must be owned by the ssh user with mode 700, but owner is %s and mode is %s", dir, fields[0], fields[1])
+	}
+
+	return nil
+}
+
+func runSSH(t *Target, cmd string) (string, error) {
+	out, err := exec.Command("ssh", append(getSSHArgs(t, "-p"), fmt.Sprintf("%s@%s", t.User, t.Host), cmd)...).CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("ssh error: %s, output: %s", err.Error(), string(out))
+	}
+
+	return string(out), nil
+}
+
+// getSSHArgs portFlag is "-p" for ssh and "-P" for scp
+func getSSHArgs(t *Target, portFlag string) []string {
+	args := []string{"-o", "BatchMode=yes", "-o", "StrictHostKeyChecking=accept-new", portFlag, strconv.Itoa(t.Port)}
+	if Key != "" {
+		args = append(args, "-i", Key)
+	}
+
+	return args
+}
+
+// getManifestCmd prints the manifest in the same format as getBundleManifest
+func getManifestCmd() string {
+	return fmt.Sprintf("find %s tools -type f -exec sha256sum {} + | LC_ALL=C sort -k 2", utils.RootName)
+}
+
+// getBundleManifest lists "[sha256]  [path]" of chaosmetad and every file of tools under root, sorted by path
+func getBundleManifest(root string) (string, error) {
+	paths := []string{utils.RootName}
+	if err := filepath.Walk(filepath.Join(root, "tools"), func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.Mode().IsRegular() {
+			rel, err := filepath.Rel(root, path)
+			if err != nil {
+				return err
+			}
+			paths = append(paths, rel)
+		}
+		return nil
+	}); err != nil {
+		return "", fmt.Errorf("walk tools error: %s", err.Error())
+	}
+	sort.Strings(paths)
+
+	lines := make([]string, len(paths))
+	for i, path := range paths {
+		sum, err := getFileSum(filepath.Join(root, path))
+		if err != nil {
+			return "", err
+		}
+		lines[i] = fmt.Sprintf("%s  %s", sum, path)
+	}
+
+	return strings.Join(lines, "\n"), nil
+}
+
+func getFileSum(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("open %s error: %s", path, err.Error())
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", fmt.Errorf("read %s error: %s", path, err.Error())
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// quote makes the arg a single word for the remote shell
+func quote(arg string) string {
+	return fmt.Sprintf("'%s'", strings.ReplaceAll(arg, "'", `'\''`))
+}
@@ -0,0 +1,117 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package remote
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseAddr(t *testing.T) {
+	tests := []struct {
+		addr    string
+		want    *Target
+		wantErr bool
+	}{
+		{addr: "10.0.0.1", want: &Target{User: "root", Host: "10.0.0.1", Port: 22}},
+		{addr: "admin@vm-1:2222", want: &Target{User: "admin", Host: "vm-1", Port: 2222}},
+		{addr: "[fe80::1]:2222", want: &Target{User: "root", Host: "fe80::1", Port: 2222}},
+		{addr: "vm-1:ssh", wantErr: true},
+		{addr: "admin@", wantErr: true},
+		{addr: "@vm-1", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			got, err := ParseAddr(tt.addr)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseAddr() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseAddr() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStripFlags(t *testing.T) {
+	args := []string{"inject", "--remote", "vm-1", "cpu", "burn", "--ssh-key=/root/.ssh/id_rsa", "-p", "50", "--remote-dir", "/opt/chaosmeta", "--timeout", "10s"}
+	want := []string{"inject", "cpu", "burn", "-p", "50", "--timeout", "10s"}
+	if got := StripFlags(args); !reflect.DeepEqual(got, want) {
+		t.Errorf("StripFlags() = %v, want %v", got, want)
+	}
+}
+
+func Test_quote(t *testing.T) {
+	if got := quote("it's"); got != `'it'\''s'` {
+		t.Errorf("quote() = %s", got)
+	}
+}
+
+func TestGetBundleManifest(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{"chaosmetad": "bin", "tools/chaosmeta_cpuburn": "a", "tools/b/chaosmeta_dns": "b", "tools/B": "c"}
+	for path, content := range files {
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(root, path)), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(root, path), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	manifest, err := getBundleManifest(root)
+	if err != nil {
+		t.Fatalf("getBundleManifest() error = %v", err)
+	}
+	if lines := strings.Split(manifest, "\n"); len(lines) != len(files) {
+		t.Fatalf("getBundleManifest() = %s, want %d lines", manifest, len(files))
+	}
+
+	// the remote manifest is printed by the shell, both must be the same for the same files
+	cmd := exec.Command("bash", "-c", getManifestCmd())
+	cmd.Dir = root
+	out, err := cmd.Output()
+	if err != nil {
+		t.Skipf("run manifest cmd error: %v", err)
+	}
+	if strings.TrimSpace(string(out)) != manifest {
+		t.Errorf("manifest of the shell:\n%s\nwant:\n%s", out, manifest)
+	}
+}
+
+func Test_checkDirStat(t *testing.T) {
+	tests := []struct {
+		output  string
+		wantErr bool
+	}{
+		{output: "0 700\n0\n"},
+		{output: "1000 700\n1000\n"},
+		{output: "0 777\n0\n", wantErr: true},
+		{output: "0 755\n0\n", wantErr: true},
+		{output: "1000 700\n0\n", wantErr: true},
+		{output: "stat: cannot stat", wantErr: true},
+	}
+	for _, tt := range tests {
+		if err := checkDirStat("/var/lib/chaosmetad/remote", tt.output); (err != nil) != tt.wantErr {
+			t.Errorf("checkDirStat(%q) error = %v, wantErr %v", tt.output, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,223 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rpc
+
+import (
+	"context"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/log"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/snapshot"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/version"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/web/handler"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/web/model"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"net"
+	"time"
+)
+
+const (
+	ServiceName = "chaosmetad.v1.Agent"
+	// TokenEnv is the env of the token shared with the callers, the grpc service is not started without it
+	TokenEnv = "CHAOSMETAD_GRPC_TOKEN"
+	// TokenMetadataKey every call carries "Bearer <token>" in the metadata of the key
+	TokenMetadataKey = "authorization"
+	// TLSCertEnv, TLSKeyEnv and TLSClientCAEnv are the PEM of the server certificate, its key and the CA of the callers.
+	// The grpc service only serves mutual TLS, so the token is never sent in plaintext. They are passed by env like the
+	// token because the DaemonSet agent runs in the mount namespace of the host, where the mounted secrets are not visible
+	TLSCertEnv     = "CHAOSMETAD_GRPC_TLS_CERT"
+	TLSKeyEnv      = "CHAOSMETAD_GRPC_TLS_KEY"
+	TLSClientCAEnv = "CHAOSMETAD_GRPC_TLS_CA"
+)
+
+type Empty struct{}
+
+// AgentServer is the grpc service of chaosmetad, the same as the experiment api of the http service
+type AgentServer interface {
+	Inject(ctx context.Context, req *model.InjectRequest) (*model.InjectResponse, error)
+	Recover(ctx context.Context, req *model.RecoverRequest) (*model.CommonResponse, error)
+	Query(ctx context.Context, req *model.QueryRequest) (*model.QueryResponse, error)
+	Version(ctx context.Context, req *Empty) (*model.VersionResponse, error)
+	Snapshot(ctx context.Context, req *model.SnapshotRequest) (*model.SnapshotResponse, error)
+	Capability(ctx context.Context, req *model.CapabilityRequest) (*model.DoctorResponse, error)
+}
+
+var ServiceDesc = grpc.ServiceDesc{
+	ServiceName: ServiceName,
+	HandlerType: (*AgentServer)(nil),
+	Methods: []grpc.MethodDesc{
+		newMethodDesc("Inject", func() interface{} { return &model.InjectRequest{} },
+			func(s AgentServer, ctx context.Context, req interface{}) (interface{}, error) {
+				return s.Inject(ctx, req.(*model.InjectRequest))
+			}),
+		newMethodDesc("Recover", func() interface{} { return &model.RecoverRequest{} },
+			func(s AgentServer, ctx context.Context, req interface{}) (interface{}, error) {
+				return s.Recover(ctx, req.(*model.RecoverRequest))
+			}),
+		newMethodDesc("Query", func() interface{} { return &model.QueryRequest{} },
+			func(s AgentServer, ctx context.Context, req interface{}) (interface{}, error) {
+				return s.Query(ctx, req.(*model.QueryRequest))
+			}),
+		newMethodDesc("Version", func() interface{} { return &Empty{} },
+			func(s AgentServer, ctx context.Context, req interface{}) (interface{}, error) {
+				return s.Version(ctx, req.(*Empty))
+			}),
+		newMethodDesc("Snapshot", func() interface{} { return &model.SnapshotRequest{} },
+			func(s AgentServer, ctx context.Context, req interface{}) (interface{}, error) {
+				return s.Snapshot(ctx, req.(*model.SnapshotRequest))
+			}),
+		newMethodDesc("Capability", func() interface{} { return &model.CapabilityRequest{} },
+			func(s AgentServer, ctx context.Context, req interface{}) (interface{}, error) {
+				return s.Capability(ctx, req.(*model.CapabilityRequest))
+			}),
+	},
+	Metadata: "chaosmetad/agent",
+}
+
+func newMethodDesc(name string, newReq func() interface{}, call func(AgentServer, context.Context, interface{}) (interface{}, error)) grpc.MethodDesc {
+	return grpc.MethodDesc{
+		MethodName: name,
+		Handler: func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+			req := newReq()
+			if err := dec(req); err != nil {
+				return nil, err
+			}
+
+			if interceptor == nil {
+				return call(srv.(AgentServer), ctx, req)
+			}
+
+			info := &grpc.UnaryServerInfo{Server: srv, FullMethod: fmt.Sprintf("/%s/%s", ServiceName, name)}
+			return interceptor(ctx, req, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+				return call(srv.(AgentServer), ctx, req)
+			})
+		},
+	}
+}
+
+type agentServer struct{}
+
+// Inject the injection is not canceled with the call, the same as the http service
+func (s *agentServer) Inject(ctx context.Context, req *model.InjectRequest) (*model.InjectResponse, error) {
+	return handler.InjectExperiment(utils.GetCtxWithTraceId(context.Background(), req.TraceId), req, getPeerAddr(ctx)), nil
+}
+
+func (s *agentServer) Recover(ctx context.Context, req *model.RecoverRequest) (*model.CommonResponse, error) {
+	return handler.RecoverExperiment(utils.GetCtxWithTraceId(context.Background(), req.TraceId), req), nil
+}
+
+func (s *agentServer) Query(ctx context.Context, req *model.QueryRequest) (*model.QueryResponse, error) {
+	return handler.QueryExperiments(utils.GetCtxWithTraceId(ctx, req.TraceId), req), nil
+}
+
+func (s *agentServer) Version(ctx context.Context, req *Empty) (*model.VersionResponse, error) {
+	return &model.VersionResponse{Code: 0, Message: "success", Data: version.GetVersion()}, nil
+}
+
+func (s *agentServer) Snapshot(ctx context.Context, req *model.SnapshotRequest) (*model.SnapshotResponse, error) {
+	ctx = utils.GetCtxWithTraceId(ctx, utils.TraceId)
+	return &model.SnapshotResponse{Code: 0, Message: "success", Data: snapshot.Take(ctx, req.ContainerRuntime, req.ContainerId)}, nil
+}
+
+func (s *agentServer) Capability(ctx context.Context, req *model.CapabilityRequest) (*model.DoctorResponse, error) {
+	return handler.GetCapability(utils.GetCtxWithTraceId(ctx, utils.TraceId), req), nil
+}
+
+func getPeerAddr(ctx context.Context) string {
+	if p, ok := peer.FromContext(ctx); ok {
+		return p.Addr.String()
+	}
+
+	return ""
+}
+
+func newLogInterceptor(ctx context.Context) grpc.UnaryServerInterceptor {
+	return func(callCtx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(callCtx, req)
+		log.GetLogger(ctx).Infof("GRPC %s %s", info.FullMethod, time.Since(start))
+		return resp, err
+	}
+}
+
+// newAuthInterceptor rejects the calls without the shared token, the service injects faults as root
+func newAuthInterceptor(token string) grpc.UnaryServerInterceptor {
+	expected := []byte("Bearer " + token)
+	return func(callCtx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		md, _ := metadata.FromIncomingContext(callCtx)
+		values := md.Get(TokenMetadataKey)
+		if len(values) != 1 || subtle.ConstantTimeCompare([]byte(values[0]), expected) != 1 {
+			return nil, status.Error(codes.Unauthenticated, "invalid token")
+		}
+
+		return handler(callCtx, req)
+	}
+}
+
+// NewTLSConfig returns the mutual TLS config of the grpc service, the callers must present a certificate signed by clientCA
+func NewTLSConfig(cert, key, clientCA string) (*tls.Config, error) {
+	if cert == "" || key == "" || clientCA == "" {
+		return nil, fmt.Errorf("certificate, key and client CA are required, set them by env %s, %s and %s", TLSCertEnv, TLSKeyEnv, TLSClientCAEnv)
+	}
+
+	keyPair, err := tls.X509KeyPair([]byte(cert), []byte(key))
+	if err != nil {
+		return nil, fmt.Errorf("load certificate error: %s", err.Error())
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM([]byte(clientCA)) {
+		return nil, fmt.Errorf("client CA is not a PEM certificate")
+	}
+
+	return &tls.Config{
+		Certificates: []tls.Certificate{keyPair},
+		ClientCAs:    pool,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		MinVersion:   tls.VersionTLS12,
+	}, nil
+}
+
+// Serve starts the grpc service of chaosmetad, it blocks until the service stops
+func Serve(ctx context.Context, addr, port, token string, tlsConfig *tls.Config) error {
+	if token == "" {
+		return fmt.Errorf("token is empty, set it by env %s", TokenEnv)
+	}
+
+	if tlsConfig == nil {
+		return fmt.Errorf("tls config is empty, the grpc service is not served in plaintext")
+	}
+
+	lis, err := net.Listen("tcp", fmt.Sprintf("%s:%s", addr, port))
+	if err != nil {
+		return fmt.Errorf("listen error: %s", err.Error())
+	}
+
+	server := grpc.NewServer(grpc.Creds(credentials.NewTLS(tlsConfig)),
+		grpc.ChainUnaryInterceptor(newLogInterceptor(ctx), newAuthInterceptor(token)))
+	server.RegisterService(&ServiceDesc, &agentServer{})
+	log.GetLogger(ctx).Infof("GRPC Service Listen on %s:%s", addr, port)
+	return server.Serve(lis)
+}
@@ -0,0 +1,237 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rpc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/version"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/web/model"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestVersion(t *testing.T) {
+	lis := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer()
+	server.RegisterService(&ServiceDesc, &agentServer{})
+	go server.Serve(lis)
+	defer server.Stop()
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, s string) (net.Conn, error) { return lis.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultCallOptions(grpc.CallContentSubtype(CodecName)))
+	if err != nil {
+		t.Fatalf("dial error: %s", err.Error())
+	}
+	defer conn.Close()
+
+	resp := &model.VersionResponse{}
+	if err := conn.Invoke(context.Background(), "/"+ServiceName+"/Version", &Empty{}, resp); err != nil {
+		t.Fatalf("invoke error: %s", err.Error())
+	}
+
+	if resp.Code != 0 || resp.Data == nil || resp.Data.Version != version.GetVersion().Version {
+		t.Errorf("Version() = %+v, want the version of chaosmetad", resp)
+	}
+}
+
+func TestAuthInterceptor(t *testing.T) {
+	lis := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer(grpc.UnaryInterceptor(newAuthInterceptor("secret")))
+	server.RegisterService(&ServiceDesc, &agentServer{})
+	go server.Serve(lis)
+	defer server.Stop()
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, s string) (net.Conn, error) { return lis.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultCallOptions(grpc.CallContentSubtype(CodecName)))
+	if err != nil {
+		t.Fatalf("dial error: %s", err.Error())
+	}
+	defer conn.Close()
+
+	tests := []struct {
+		name  string
+		token string
+		want  codes.Code
+	}{
+		{name: "no token", token: "", want: codes.Unauthenticated},
+		{name: "wrong token", token: "Bearer wrong", want: codes.Unauthenticated},
+		{name: "right token", token: "Bearer secret", want: codes.OK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.token != "" {
+				ctx = metadata.AppendToOutgoingContext(ctx, TokenMetadataKey, tt.token)
+			}
+
+			err := conn.Invoke(ctx, "/"+ServiceName+"/Version", &Empty{}, &model.VersionResponse{})
+			if got := status.Code(err); got != tt.want {
+				t.Errorf("Invoke() code = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestServeWithoutToken(t *testing.T) {
+	if err := Serve(context.Background(), "127.0.0.1", "0", "", &tls.Config{}); err == nil {
+		t.Errorf("Serve() without token should fail")
+	}
+}
+
+func TestServeWithoutTLS(t *testing.T) {
+	if err := Serve(context.Background(), "127.0.0.1", "0", "secret", nil); err == nil {
+		t.Errorf("Serve() without tls config should fail")
+	}
+}
+
+func TestNewTLSConfig(t *testing.T) {
+	ca, caKey, caPEM := newTestCA(t)
+	certPEM, keyPEM := newTestCert(t, ca, caKey, "chaosmetad")
+
+	if _, err := NewTLSConfig(certPEM, keyPEM, ""); err == nil {
+		t.Errorf("NewTLSConfig() without client CA should fail")
+	}
+
+	if _, err := NewTLSConfig(certPEM, keyPEM, "not a pem"); err == nil {
+		t.Errorf("NewTLSConfig() with invalid client CA should fail")
+	}
+
+	serverConfig, err := NewTLSConfig(certPEM, keyPEM, caPEM)
+	if err != nil {
+		t.Fatalf("NewTLSConfig() error: %s", err.Error())
+	}
+
+	lis := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer(grpc.Creds(credentials.NewTLS(serverConfig)))
+	server.RegisterService(&ServiceDesc, &agentServer{})
+	go server.Serve(lis)
+	defer server.Stop()
+
+	pool := x509.NewCertPool()
+	pool.AddCert(ca)
+	clientCertPEM, clientKeyPEM := newTestCert(t, ca, caKey, "operator")
+	clientCert, err := tls.X509KeyPair([]byte(clientCertPEM), []byte(clientKeyPEM))
+	if err != nil {
+		t.Fatalf("load client certificate error: %s", err.Error())
+	}
+
+	tests := []struct {
+		name    string
+		config  *tls.Config
+		wantErr bool
+	}{
+		{name: "no client certificate", config: &tls.Config{RootCAs: pool, ServerName: "chaosmetad"}, wantErr: true},
+		{name: "mutual tls", config: &tls.Config{RootCAs: pool, ServerName: "chaosmetad", Certificates: []tls.Certificate{clientCert}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			conn, err := grpc.DialContext(ctx, "bufnet",
+				grpc.WithContextDialer(func(ctx context.Context, s string) (net.Conn, error) { return lis.Dial() }),
+				grpc.WithTransportCredentials(credentials.NewTLS(tt.config)),
+				grpc.WithDefaultCallOptions(grpc.CallContentSubtype(CodecName)))
+			if err != nil {
+				t.Fatalf("dial error: %s", err.Error())
+			}
+			defer conn.Close()
+
+			err = conn.Invoke(ctx, "/"+ServiceName+"/Version", &Empty{}, &model.VersionResponse{})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Invoke() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func newTestCA(t *testing.T) (*x509.Certificate, *ecdsa.PrivateKey, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key error: %s", err.Error())
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "chaosmeta-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("create CA error: %s", err.Error())
+	}
+
+	ca, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("parse CA error: %s", err.Error())
+	}
+
+	return ca, key, string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
+
+func newTestCert(t *testing.T, ca *x509.Certificate, caKey *ecdsa.PrivateKey, name string) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key error: %s", err.Error())
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca, &key.PublicKey, caKey)
+	if err != nil {
+		t.Fatalf("create certificate error: %s", err.Error())
+	}
+
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("marshal key error: %s", err.Error())
+	}
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}))
+}
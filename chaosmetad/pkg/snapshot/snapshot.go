@@ -0,0 +1,114 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package snapshot
+
+import (
+	"context"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/cgroup"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/cmdexec"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/memory"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/namespace"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	TimeFormat   = "2006-01-02 15:04:05"
+	TopProcesses = 5
+
+	loadAvgCmd     = "cat /proc/loadavg"
+	connectionsCmd = "cat /proc/net/tcp /proc/net/tcp6 2>/dev/null | grep -v local_address | wc -l"
+)
+
+// Snapshot is a lightweight view of the target, taken right before the injection and right after the recovery,
+// so that the observed symptoms can be attributed to the fault. A failed item is recorded in Errors only
+type Snapshot struct {
+	Time         string   `json:"time"`
+	LoadAvg      string   `json:"load_avg,omitempty"`
+	MemLimitKB   int64    `json:"mem_limit_kb,omitempty"`
+	MemUsageKB   int64    `json:"mem_usage_kb,omitempty"`
+	Connections  int      `json:"connections"`
+	TopProcesses []string `json:"top_processes,omitempty"`
+	Errors       []string `json:"errors,omitempty"`
+}
+
+// Take a snapshot of the host, or of the container if cr is not empty
+func Take(ctx context.Context, cr, cId string) *Snapshot {
+	s := &Snapshot{Time: time.Now().Format(TimeFormat)}
+	ns := []string{namespace.MNT, namespace.PID, namespace.NET}
+
+	if re, err := cmdexec.ExecCommonWithNS(ctx, cr, cId, loadAvgCmd, ns); err != nil {
+		s.addError("load avg", err)
+	} else {
+		fields := strings.Fields(re)
+		if len(fields) >= 3 {
+			s.LoadAvg = strings.Join(fields[:3], " ")
+		}
+	}
+
+	if err := s.setMem(ctx, cr, cId); err != nil {
+		s.addError("mem", err)
+	}
+
+	if re, err := cmdexec.ExecCommonWithNS(ctx, cr, cId, connectionsCmd, ns); err != nil {
+		s.addError("connections", err)
+	} else if s.Connections, err = strconv.Atoi(strings.TrimSpace(re)); err != nil {
+		s.addError("connections", err)
+	}
+
+	topCmd := fmt.Sprintf("ps -eo pid,comm,%%cpu,%%mem --sort=-%%cpu | head -n %d", TopProcesses+1)
+	if re, err := cmdexec.ExecCommonWithNS(ctx, cr, cId, topCmd, ns); err != nil {
+		s.addError("top processes", err)
+	} else {
+		lines := strings.Split(strings.TrimSpace(re), "\n")
+		for _, line := range lines[1:] {
+			s.TopProcesses = append(s.TopProcesses, strings.Join(strings.Fields(line), " "))
+		}
+	}
+
+	return s
+}
+
+func (s *Snapshot) setMem(ctx context.Context, cr, cId string) error {
+	if cr == "" {
+		limit, usage, err := memory.GetLimitAndUsageKBytes(ctx, cr, cId, "")
+		s.MemLimitKB, s.MemUsageKB = limit, usage
+		return err
+	}
+
+	cgroupPath, err := cgroup.GetContainerMemCgroupPath(ctx, cr, cId)
+	if err != nil {
+		return err
+	}
+
+	limit, usage, err := cgroup.GetMemCgroupLimitAndUsage(cgroupPath)
+	if err != nil {
+		return err
+	}
+
+	if limit != cgroup.MemUnLimit {
+		s.MemLimitKB = limit / 1024
+	}
+	s.MemUsageKB = usage / 1024
+	return nil
+}
+
+func (s *Snapshot) addError(item string, err error) {
+	s.Errors = append(s.Errors, fmt.Sprintf("%s: %s", item, err.Error()))
+}
@@ -21,6 +21,8 @@ import (
 	"fmt"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
 	"gorm.io/gorm"
+	"math"
+	"strings"
 	"time"
 )
 
@@ -88,6 +90,30 @@ func (e *experimentStore) UpdateStatus(uid, status string) error {
 	return nil
 }
 
+// TransitStatus updates the status only if the experiment is still in status from, and reports whether it is updated,
+// so only one of the concurrent callers does the transition
+func (e *experimentStore) TransitStatus(uid, from, to string) (bool, error) {
+	result := e.db.Model(Experiment{}).
+		Where("uid = ? AND status = ?", uid, from).
+		Updates(Experiment{Status: to, UpdateTime: time.Now().Format(utils.TimeFormat)})
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+func (e *experimentStore) UpdateHookOutput(uid, output string) error {
+	if err := e.db.Model(Experiment{}).
+		Where("uid = ?", uid).
+		Updates(Experiment{HookOutput: output, UpdateTime: time.Now().Format(utils.TimeFormat)}).
+		Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (e *experimentStore) UpdateStatusAndErr(uid, status, errMsg string) error {
 	if err := e.db.Model(Experiment{}).
 		Where("uid = ?", uid).
@@ -114,43 +140,135 @@ func (e *experimentStore) GetByUid(uid string) (*Experiment, error) {
 	return exp, nil
 }
 
+// ExperimentFilter is the condition of querying experiments, the empty fields are not used
+type ExperimentFilter struct {
+	Uid              string
+	Status           []string
+	Target           string
+	Fault            string
+	Creator          string
+	ContainerRuntime string
+	ContainerId      string
+	Fingerprint      string
+	// StartTime and EndTime limit the create time in format utils.TimeFormat, both ends are included
+	StartTime string
+	EndTime   string
+	Offset    uint
+	// Limit 0 means no limit
+	Limit uint
+}
+
+// ParseStatusList splits the statuses joined by ","
+func ParseStatusList(status string) []string {
+	var re []string
+	for _, s := range strings.Split(status, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			re = append(re, s)
+		}
+	}
+
+	return re
+}
+
+func (f *ExperimentFilter) Validate() error {
+	var start, end time.Time
+	var err error
+	if f.StartTime != "" {
+		if start, err = time.Parse(utils.TimeFormat, f.StartTime); err != nil {
+			return fmt.Errorf("start time[%s] is not in format \"%s\"", f.StartTime, utils.TimeFormat)
+		}
+	}
+
+	if f.EndTime != "" {
+		if end, err = time.Parse(utils.TimeFormat, f.EndTime); err != nil {
+			return fmt.Errorf("end time[%s] is not in format \"%s\"", f.EndTime, utils.TimeFormat)
+		}
+	}
+
+	if f.StartTime != "" && f.EndTime != "" && start.After(end) {
+		return fmt.Errorf("start time[%s] is after end time[%s]", f.StartTime, f.EndTime)
+	}
+
+	return nil
+}
+
 func (e *experimentStore) QueryByOption(uid, status, target, fault, creator, cr, cId string, offset, limit uint) ([]*Experiment, int64, error) {
+	f := &ExperimentFilter{
+		Uid:              uid,
+		Target:           target,
+		Fault:            fault,
+		Creator:          creator,
+		ContainerRuntime: cr,
+		ContainerId:      cId,
+		Offset:           offset,
+		Limit:            limit,
+	}
+	if status != "" {
+		f.Status = []string{status}
+	}
+
+	return e.QueryByFilter(f)
+}
+
+// QueryByFilter returns the experiments of a page in the order of create time desc, and the total count of the filter
+func (e *experimentStore) QueryByFilter(f *ExperimentFilter) ([]*Experiment, int64, error) {
 	var exps []*Experiment
 	db := e.db.Model(Experiment{})
 
-	if uid != "" {
-		db = db.Where("uid = ?", uid)
+	if f.Uid != "" {
+		db = db.Where("uid = ?", f.Uid)
 	}
 
-	if status != "" {
-		db = db.Where("status = ?", status)
+	if len(f.Status) == 1 {
+		db = db.Where("status = ?", f.Status[0])
+	} else if len(f.Status) > 1 {
+		db = db.Where("status IN ?", f.Status)
+	}
+
+	if f.Creator != "" {
+		db = db.Where("creator = ?", f.Creator)
+	}
+
+	if f.Target != "" {
+		db = db.Where("target = ?", f.Target)
+	}
+
+	if f.Fault != "" {
+		db = db.Where("fault = ?", f.Fault)
+	}
+
+	if f.ContainerRuntime != "" {
+		db = db.Where("container_runtime = ?", f.ContainerRuntime)
 	}
 
-	if creator != "" {
-		db = db.Where("creator = ?", creator)
+	if f.ContainerId != "" {
+		db = db.Where("container_id = ?", f.ContainerId)
 	}
 
-	if target != "" {
-		db = db.Where("target = ?", target)
+	if f.Fingerprint != "" {
+		db = db.Where("fingerprint = ?", f.Fingerprint)
 	}
 
-	if fault != "" {
-		db = db.Where("fault = ?", fault)
+	// the time strings of utils.TimeFormat are in the same order as the time
+	if f.StartTime != "" {
+		db = db.Where("create_time >= ?", f.StartTime)
 	}
 
-	if cr != "" {
-		db = db.Where("container_runtime = ?", cr)
+	if f.EndTime != "" {
+		db = db.Where("create_time <= ?", f.EndTime)
 	}
 
-	if cId != "" {
-		db = db.Where("container_id = ?", cId)
+	limit := int(f.Limit)
+	if limit == 0 && f.Offset > 0 {
+		// offset is not allowed without limit
+		limit = math.MaxInt32
 	}
 
 	var total int64
 	if err := db.
 		Count(&total).
 		Order("create_time DESC").
-		Offset(int(offset)).Limit(int(limit)).
+		Offset(int(f.Offset)).Limit(limit).
 		Find(&exps).
 		Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
 		return nil, 0, err
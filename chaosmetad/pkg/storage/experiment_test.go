@@ -0,0 +1,99 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package storage
+
+import (
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseStatusList(t *testing.T) {
+	tests := []struct {
+		name   string
+		status string
+		want   []string
+	}{
+		{name: "empty", status: "", want: nil},
+		{name: "single", status: "success", want: []string{"success"}},
+		{name: "multiple", status: "success, idle,,paused", want: []string{"success", "idle", "paused"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseStatusList(tt.status); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseStatusList() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExperimentFilter_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		f       ExperimentFilter
+		wantErr bool
+	}{
+		{name: "empty", f: ExperimentFilter{}},
+		{name: "range", f: ExperimentFilter{StartTime: "2023-01-01 00:00:00", EndTime: "2023-01-02 00:00:00"}},
+		{name: "start only", f: ExperimentFilter{StartTime: "2023-01-01 00:00:00"}},
+		{name: "bad format", f: ExperimentFilter{StartTime: "2023-01-01T00:00:00Z"}, wantErr: true},
+		{name: "start after end", f: ExperimentFilter{StartTime: "2023-01-02 00:00:00", EndTime: "2023-01-01 00:00:00"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.f.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestExperimentStore_TransitStatus(t *testing.T) {
+	gormDB, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), storageFile)), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open db error: %s", err.Error())
+	}
+
+	store, err := newExperimentStore(&dbStorage{gormDB})
+	if err != nil {
+		t.Fatalf("new store error: %s", err.Error())
+	}
+
+	if err := store.Insert(&Experiment{Uid: "abc", Status: "success"}); err != nil {
+		t.Fatalf("insert error: %s", err.Error())
+	}
+
+	for _, want := range []bool{true, false} {
+		transited, err := store.TransitStatus("abc", "success", "destroyed")
+		if err != nil {
+			t.Fatalf("TransitStatus() error: %s", err.Error())
+		}
+		if transited != want {
+			t.Errorf("TransitStatus() = %v, want %v", transited, want)
+		}
+	}
+
+	exp, err := store.GetByUid("abc")
+	if err != nil {
+		t.Fatalf("GetByUid() error: %s", err.Error())
+	}
+	if exp.Status != "destroyed" {
+		t.Errorf("status = %s, want destroyed", exp.Status)
+	}
+}
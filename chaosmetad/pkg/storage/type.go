@@ -23,6 +23,7 @@ type Experiment struct {
 	Args             string `json:"args"`
 	Runtime          string `json:"runtime"`
 	Timeout          string `json:"timeout"`
+	Pulse            string `json:"pulse"`
 	Status           string `gorm:"index:status" json:"status"`
 	Creator          string `gorm:"index:creator" json:"creator"`
 	Error            string `json:"error"`
@@ -30,4 +31,9 @@ type Experiment struct {
 	UpdateTime       string `json:"update_time"`
 	ContainerId      string `json:"container_id"`
 	ContainerRuntime string `json:"container_runtime"`
+	PreHook          string `json:"pre_hook"`
+	PostHook         string `json:"post_hook"`
+	HookOutput       string `json:"hook_output"`
+	// Fingerprint identifies the exclusive resource occupied by the fault, such as the tc root qdisc of an interface
+	Fingerprint string `gorm:"index:fingerprint" json:"fingerprint"`
 }
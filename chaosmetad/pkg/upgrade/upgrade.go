@@ -0,0 +1,366 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package upgrade
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/config"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/log"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/storage"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/cmdexec"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/process"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/tool"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/version"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	// SignatureFile is the base64 ed25519 signature of the checksum file of a release
+	SignatureFile  = "SHA256SUMS.sig"
+	DefaultService = "chaosmetad"
+	// ReleaseKeyFile holds the base64 ed25519 public key of the releases. It must be owned by root and not writable by
+	// others, so the key can not be replaced through the api like the runtime config
+	ReleaseKeyFile = "/etc/chaosmetad/release.pub"
+
+	serverKey      = "chaosmetad server"
+	backupSuffix   = ".bak"
+	newSuffix      = ".new"
+	stopTimeout    = time.Second * 30
+	stopCheckDelay = time.Millisecond * 200
+)
+
+var versionReg = regexp.MustCompile(`^v?\d+\.\d+\.\d+(-[0-9A-Za-z.]+)?$`)
+
+type Options struct {
+	Version   string
+	Registry  string
+	PublicKey string
+	Service   string
+	NoRestart bool
+}
+
+// Upgrade downloads the release of the version, verifies its signature and checksum, swaps the running binary and
+// restarts the daemon. The experiments in progress are kept in the storage and the timeout recover processes call the
+// binary by path, so they are taken over by the new binary
+func Upgrade(ctx context.Context, opt *Options) error {
+	logger := log.GetLogger(ctx)
+	if err := fillOptions(opt); err != nil {
+		return err
+	}
+
+	current := version.GetVersion().Version
+	if strings.TrimPrefix(current, "v") == strings.TrimPrefix(opt.Version, "v") {
+		logger.Infof("chaosmetad is already %s", current)
+		return nil
+	}
+
+	// the new binary shares the storage, so it must be readable before swapping
+	db, err := storage.GetExperimentStore()
+	if err != nil {
+		return fmt.Errorf("connect db error: %s", err.Error())
+	}
+
+	_, total, err := db.QueryByFilter(&storage.ExperimentFilter{
+		Status: []string{utils.StatusCreated, utils.StatusSuccess, utils.StatusIdle, utils.StatusPaused},
+	})
+	if err != nil {
+		return fmt.Errorf("query experiments in progress error: %s", err.Error())
+	}
+	logger.Infof("%d experiments in progress are preserved", total)
+
+	data, err := download(ctx, opt)
+	if err != nil {
+		return err
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("get path of chaosmetad error: %s", err.Error())
+	}
+	if exe, err = filepath.EvalSymlinks(exe); err != nil {
+		return fmt.Errorf("resolve path of chaosmetad error: %s", err.Error())
+	}
+
+	if err := swap(ctx, exe, data); err != nil {
+		return err
+	}
+	logger.Infof("chaosmetad is upgraded from %s to %s", current, opt.Version)
+
+	if opt.NoRestart {
+		return nil
+	}
+
+	if err := restart(ctx, exe, opt.Service); err != nil {
+		logger.Warnf("restart error, roll back to %s: %s", current, err.Error())
+		if err := os.Rename(exe+backupSuffix, exe); err != nil {
+			return fmt.Errorf("roll back %s error: %s", exe, err.Error())
+		}
+
+		if err := restart(ctx, exe, opt.Service); err != nil {
+			return fmt.Errorf("restart after roll back error: %s", err.Error())
+		}
+
+		return fmt.Errorf("restart chaosmetad %s error, rolled back to %s", opt.Version, current)
+	}
+
+	return nil
+}
+
+func fillOptions(opt *Options) error {
+	if !versionReg.MatchString(opt.Version) {
+		return fmt.Errorf("version[%s] is invalid, eg: v0.5.0", opt.Version)
+	}
+
+	c, err := config.Get()
+	if err != nil {
+		return fmt.Errorf("get config error: %s", err.Error())
+	}
+
+	if opt.Registry == "" {
+		opt.Registry = c.ReleaseRegistry
+	}
+	if opt.Registry == "" {
+		return fmt.Errorf("no release registry is provided or configured")
+	}
+	if u, err := url.Parse(opt.Registry); err != nil || u.Scheme != "https" || u.Host == "" {
+		return fmt.Errorf("release registry[%s] must be an https address", opt.Registry)
+	}
+
+	if opt.PublicKey == "" {
+		if opt.PublicKey, err = readKeyFile(ReleaseKeyFile); err != nil {
+			return fmt.Errorf("no release public key is provided, the release can not be verified: %s", err.Error())
+		}
+	}
+
+	if opt.Service == "" {
+		opt.Service = DefaultService
+	}
+
+	return nil
+}
+
+// readKeyFile reads the public key from a file that only root can write
+func readKeyFile(path string) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", fmt.Errorf("stat %s error: %s", path, err.Error())
+	}
+
+	if err := checkKeyFileStat(info); err != nil {
+		return "", fmt.Errorf("%s is not trusted: %s", path, err.Error())
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("read %s error: %s", path, err.Error())
+	}
+
+	return strings.TrimSpace(string(data)), nil
+}
+
+func checkKeyFileStat(info os.FileInfo) error {
+	if !info.Mode().IsRegular() {
+		return fmt.Errorf("not a regular file")
+	}
+
+	if info.Mode().Perm()&0022 != 0 {
+		return fmt.Errorf("writable by group or others, mode: %o", info.Mode().Perm())
+	}
+
+	if st, ok := info.Sys().(*syscall.Stat_t); !ok || st.Uid != 0 {
+		return fmt.Errorf("not owned by root")
+	}
+
+	return nil
+}
+
+// download gets "[registry]/[version]/[arch]/chaosmetad", which is verified by "[registry]/[version]/SHA256SUMS"
+// and its signature
+func download(ctx context.Context, opt *Options) ([]byte, error) {
+	base := fmt.Sprintf("%s/%s", strings.TrimSuffix(opt.Registry, "/"), opt.Version)
+	name := fmt.Sprintf("%s/%s", runtime.GOARCH, utils.RootName)
+	log.GetLogger(ctx).Infof("download %s from %s", name, base)
+
+	sums, err := tool.Download(fmt.Sprintf("%s/%s", base, tool.ChecksumFile))
+	if err != nil {
+		return nil, err
+	}
+
+	sig, err := tool.Download(fmt.Sprintf("%s/%s", base, SignatureFile))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := VerifySignature(opt.PublicKey, sums, string(sig)); err != nil {
+		return nil, err
+	}
+
+	expected, ok := tool.ParseChecksums(string(sums))[name]
+	if !ok {
+		return nil, fmt.Errorf("no checksum of %s in %s", name, tool.ChecksumFile)
+	}
+
+	data, err := tool.Download(fmt.Sprintf("%s/%s", base, name))
+	if err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256(data)
+	if actual := hex.EncodeToString(sum[:]); actual != expected {
+		return nil, fmt.Errorf("checksum of downloaded %s is %s, expected %s", name, actual, expected)
+	}
+
+	return data, nil
+}
+
+// VerifySignature checks the base64 ed25519 signature of content
+func VerifySignature(publicKey string, content []byte, signature string) error {
+	key, err := base64.StdEncoding.DecodeString(publicKey)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return fmt.Errorf("public key is not a base64 ed25519 public key")
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(signature))
+	if err != nil {
+		return fmt.Errorf("signature is not base64: %s", err.Error())
+	}
+
+	if !ed25519.Verify(key, content, sig) {
+		return fmt.Errorf("signature of %s is invalid", tool.ChecksumFile)
+	}
+
+	return nil
+}
+
+// swap replaces exe by data, the old binary is kept as "[exe].bak". The new binary is checked to be executable on
+// this host before swapping
+func swap(ctx context.Context, exe string, data []byte) error {
+	newPath := exe + newSuffix
+	if err := os.WriteFile(newPath, data, 0755); err != nil {
+		return fmt.Errorf("write %s error: %s", newPath, err.Error())
+	}
+
+	if _, err := cmdexec.RunBashCmdWithTimeout(ctx, fmt.Sprintf("%s version", newPath), cmdexec.ExecWaitTimeout); err != nil {
+		_ = os.Remove(newPath)
+		return fmt.Errorf("new binary is not executable: %s", err.Error())
+	}
+
+	if err := os.Rename(exe, exe+backupSuffix); err != nil {
+		return fmt.Errorf("backup %s error: %s", exe, err.Error())
+	}
+
+	if err := os.Rename(newPath, exe); err != nil {
+		_ = os.Rename(exe+backupSuffix, exe)
+		return fmt.Errorf("replace %s error: %s", exe, err.Error())
+	}
+
+	return nil
+}
+
+// restart the daemon by systemd if it is a service, otherwise the daemon started by hand is stopped and started with
+// the same args
+func restart(ctx context.Context, exe, service string) error {
+	logger := log.GetLogger(ctx)
+	if cmdexec.SupportCmd("systemctl") {
+		if err := cmdexec.RunBashCmdWithoutOutput(ctx, fmt.Sprintf("systemctl is-active --quiet %s", service)); err == nil {
+			logger.Infof("restart service %s", service)
+			return cmdexec.RunBashCmdWithoutOutput(ctx, fmt.Sprintf("systemctl restart %s", service))
+		}
+	}
+
+	pids, err := process.GetPidListByKey(ctx, "", "", serverKey)
+	if err != nil {
+		return fmt.Errorf("get pid of %s error: %s", serverKey, err.Error())
+	}
+
+	if len(pids) == 0 {
+		logger.Infof("no running daemon to restart")
+		return nil
+	}
+
+	for _, pid := range pids {
+		args, err := getArgs(pid)
+		if err != nil {
+			return err
+		}
+
+		dir, err := os.Readlink(fmt.Sprintf("/proc/%d/cwd", pid))
+		if err != nil {
+			return fmt.Errorf("get work dir of %d error: %s", pid, err.Error())
+		}
+
+		logger.Infof("restart daemon[%d]: %s", pid, strings.Join(args, " "))
+		if err := stop(ctx, pid); err != nil {
+			return err
+		}
+
+		c := exec.Command(exe, args...)
+		c.Dir = dir
+		c.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+		if err := c.Start(); err != nil {
+			return fmt.Errorf("start daemon error: %s", err.Error())
+		}
+		_ = c.Process.Release()
+	}
+
+	return nil
+}
+
+// getArgs returns the args of a process without the binary
+func getArgs(pid int) ([]string, error) {
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/cmdline", pid))
+	if err != nil {
+		return nil, fmt.Errorf("read cmdline of %d error: %s", pid, err.Error())
+	}
+
+	args := strings.Split(strings.TrimSuffix(string(data), "\x00"), "\x00")
+	if len(args) < 2 {
+		return nil, fmt.Errorf("cmdline of %d is invalid: %s", pid, string(data))
+	}
+
+	return args[1:], nil
+}
+
+func stop(ctx context.Context, pid int) error {
+	if err := process.KillPidWithSignal(ctx, pid, process.SIGTERM); err != nil {
+		return fmt.Errorf("stop daemon[%d] error: %s", pid, err.Error())
+	}
+
+	deadline := time.Now().Add(stopTimeout)
+	for time.Now().Before(deadline) {
+		if exist, _ := process.ExistPid(ctx, pid); !exist {
+			return nil
+		}
+		time.Sleep(stopCheckDelay)
+	}
+
+	return fmt.Errorf("daemon[%d] is not stopped in %s", pid, stopTimeout)
+}
@@ -0,0 +1,116 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package upgrade
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestVerifySignature(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key error: %s", err.Error())
+	}
+
+	content := []byte("1f2e3d  amd64/chaosmetad\n")
+	publicKey := base64.StdEncoding.EncodeToString(pub)
+	signature := base64.StdEncoding.EncodeToString(ed25519.Sign(priv, content))
+
+	if err := VerifySignature(publicKey, content, signature+"\n"); err != nil {
+		t.Errorf("expected valid signature, got error: %s", err.Error())
+	}
+
+	if err := VerifySignature(publicKey, []byte("000000  amd64/chaosmetad\n"), signature); err == nil {
+		t.Errorf("expected error for changed content")
+	}
+
+	if err := VerifySignature("c2hvcnQ=", content, signature); err == nil {
+		t.Errorf("expected error for invalid public key")
+	}
+}
+
+func TestFillOptions(t *testing.T) {
+	for _, v := range []string{"0.6.0", "v0.6.0", "v1.0.0-rc.1"} {
+		if !versionReg.MatchString(v) {
+			t.Errorf("version[%s] is expected to be valid", v)
+		}
+	}
+
+	for _, v := range []string{"", "latest", "v1.0", "../v1.0.0"} {
+		if err := fillOptions(&Options{Version: v}); err == nil {
+			t.Errorf("version[%s] is expected to be invalid", v)
+		}
+	}
+}
+
+func TestFillOptionsRegistry(t *testing.T) {
+	for _, r := range []string{"http://oss.example.com/chaosmeta", "oss.example.com/chaosmeta", "https://"} {
+		if err := fillOptions(&Options{Version: "v0.6.0", Registry: r}); err == nil {
+			t.Errorf("registry[%s] is expected to be invalid", r)
+		}
+	}
+}
+
+func TestCheckKeyFileStat(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "release.pub")
+	if err := os.WriteFile(path, []byte("key"), 0644); err != nil {
+		t.Fatalf("write key file error: %s", err.Error())
+	}
+
+	tests := []struct {
+		name    string
+		mode    os.FileMode
+		wantErr bool
+	}{
+		{name: "owner writable", mode: 0644, wantErr: os.Getuid() != 0},
+		{name: "group writable", mode: 0664, wantErr: true},
+		{name: "others writable", mode: 0646, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := os.Chmod(path, tt.mode); err != nil {
+				t.Fatalf("chmod error: %s", err.Error())
+			}
+
+			info, err := os.Stat(path)
+			if err != nil {
+				t.Fatalf("stat error: %s", err.Error())
+			}
+
+			if err := checkKeyFileStat(info); (err != nil) != tt.wantErr {
+				t.Errorf("checkKeyFileStat() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if err := checkKeyFileStat(mustStat(t, filepath.Dir(path))); err == nil {
+		t.Errorf("expected error for a directory")
+	}
+}
+
+func mustStat(t *testing.T, path string) os.FileInfo {
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("stat error: %s", err.Error())
+	}
+	return info
+}
@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/log"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+	"strings"
 )
 
 func GetBlkioConfig(ctx context.Context, devList []string, rBytes, wBytes string, rIO, wIO int64, cgroupPath string) string {
@@ -47,6 +48,38 @@ func GetBlkioConfig(ctx context.Context, devList []string, rBytes, wBytes string
 	return re[:len(re)-len(utils.CmdSplit)]
 }
 
+// GetIoMaxConfig is the cgroup v2 version of GetBlkioConfig, the limits of a device are written in one line of io.max.
+// io.max only exists after EnableIoController
+func GetIoMaxConfig(ctx context.Context, devList []string, rBytes, wBytes string, rIO, wIO int64, cgroupPath string) string {
+	var limits []string
+	if rBytes != "" {
+		b, _ := utils.GetBytes(rBytes)
+		limits = append(limits, fmt.Sprintf("rbps=%d", b))
+	}
+
+	if wBytes != "" {
+		b, _ := utils.GetBytes(wBytes)
+		limits = append(limits, fmt.Sprintf("wbps=%d", b))
+	}
+
+	if rIO != 0 {
+		limits = append(limits, fmt.Sprintf("riops=%d", rIO))
+	}
+
+	if wIO != 0 {
+		limits = append(limits, fmt.Sprintf("wiops=%d", wIO))
+	}
+
+	var cmdList []string
+	for _, unitDev := range devList {
+		cmdList = append(cmdList, fmt.Sprintf("echo \"%s %s\" > %s/%s", unitDev, strings.Join(limits, " "), cgroupPath, IoMaxFile))
+	}
+
+	re := strings.Join(cmdList, utils.CmdSplit)
+	log.GetLogger(ctx).Debugf("io.max config: %s", re)
+	return re
+}
+
 func getThrottleDeviceCmdStr(devList []string, value int64, filename string) string {
 	var re string
 	for _, unitDec := range devList {
@@ -24,6 +24,7 @@ import (
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/cmdexec"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/containercgroup"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 )
@@ -36,14 +37,44 @@ func NewCgroup(ctx context.Context, cgroupPath string, configCmdStr string) erro
 	return nil
 }
 
+// GetIoLimitConfig returns the cmd to limit io of a new cgroup created by NewCgroup, for both cgroup v1 and v2
+func GetIoLimitConfig(ctx context.Context, devList []string, rBytes, wBytes string, rIO, wIO int64, cgroupPath string) string {
+	if IsCgroupV2() {
+		return GetIoMaxConfig(ctx, devList, rBytes, wBytes, rIO, wIO, cgroupPath)
+	}
+
+	return GetBlkioConfig(ctx, devList, rBytes, wBytes, rIO, wIO, cgroupPath)
+}
+
+// ReadCgroupFileStr reads a v1 file of the subsystem, the file with the same meaning is read on cgroup v2
 func ReadCgroupFileStr(ctx context.Context, path, subSys, fileName string) (string, error) {
-	cgroupFile := fmt.Sprintf("%s/%s%s/%s", containercgroup.RootCgroupPath, subSys, path, fileName)
+	if IsCgroupV2() {
+		if v2File, ok := v2FileMap[fileName]; ok {
+			fileName = v2File
+		}
+	}
+
+	cgroupFile := fmt.Sprintf("%s/%s", GetCgroupAbsPath(subSys, path), fileName)
 	reByte, err := os.ReadFile(cgroupFile)
 	if err != nil {
 		return "", fmt.Errorf("read from %s error: %s", cgroupFile, err.Error())
 	}
 
-	return strings.TrimSpace(string(reByte)), nil
+	re := strings.TrimSpace(string(reByte))
+	if re == "max" {
+		re = strconv.FormatInt(MemUnLimit, 10)
+	}
+
+	return re, nil
+}
+
+// GetCgroupAbsPath returns the absolute dir of a cgroup path relative to the hierarchy of the subsystem
+func GetCgroupAbsPath(subSys, path string) string {
+	if IsCgroupV2() {
+		return filepath.Join(containercgroup.RootCgroupPath, path)
+	}
+
+	return filepath.Join(containercgroup.RootCgroupPath, subSys, path)
 }
 
 func GetContainerCgroupPath(ctx context.Context, cr, containerID, subSys string) (string, error) {
@@ -65,10 +96,136 @@ func GetContainerCgroupPath(ctx context.Context, cr, containerID, subSys string)
 	return cPath, nil
 }
 
+// GetBlkioCPath returns the cgroup to limit io of the processes in cgroup prefix, which is a child of prefix so that
+// the processes stay under the limits of the container
 func GetBlkioCPath(uid string, prefix string) string {
+	if IsCgroupV2() {
+		return fmt.Sprintf("%s/%s_%s", filepath.Join(containercgroup.RootCgroupPath, prefix), BlkioCgroupName, uid)
+	}
+
 	return fmt.Sprintf("%s/%s%s/%s_%s", containercgroup.RootCgroupPath, BLKIO, prefix, BlkioCgroupName, uid)
 }
 
+// EnableIoController enables the io controller for the children of the parent of the io cgroup on cgroup v2. A
+// non-root cgroup with processes can not enable controllers for its children, so its processes are moved to its
+// child IoLeafCgroupName first, which is undone by DisableIoController
+func EnableIoController(ctx context.Context, cgroupPath string) error {
+	if !IsCgroupV2() {
+		return nil
+	}
+
+	parent := filepath.Dir(cgroupPath)
+	controllers, err := readCgroupFile(parent, SubtreeControlFile)
+	if err != nil {
+		return err
+	}
+
+	for _, c := range strings.Fields(controllers) {
+		if c == "io" {
+			return nil
+		}
+	}
+
+	if parent != filepath.Clean(containercgroup.RootCgroupPath) {
+		leaf := filepath.Join(parent, IoLeafCgroupName)
+		if err := os.MkdirAll(leaf, 0755); err != nil {
+			return fmt.Errorf("create cgroup[%s] error: %s", leaf, err.Error())
+		}
+
+		if err := movePidsOfCgroup(ctx, parent, leaf); err != nil {
+			return err
+		}
+	}
+
+	if err := cmdexec.RunBashCmdWithoutOutput(ctx, fmt.Sprintf("echo +io > %s/%s", parent, SubtreeControlFile)); err != nil {
+		return fmt.Errorf("enable io controller of cgroup[%s] error: %s", parent, err.Error())
+	}
+
+	return nil
+}
+
+// DisableIoController undoes EnableIoController after the io cgroup is removed, unless the io cgroups of other
+// experiments are still in the parent
+func DisableIoController(ctx context.Context, cgroupPath string) error {
+	if !IsCgroupV2() {
+		return nil
+	}
+
+	parent := filepath.Dir(cgroupPath)
+	leaf := filepath.Join(parent, IoLeafCgroupName)
+	if _, err := os.Stat(leaf); err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+
+		return fmt.Errorf("check cgroup[%s] exist error: %s", leaf, err.Error())
+	}
+
+	others, err := filepath.Glob(filepath.Join(parent, BlkioCgroupName+"_*"))
+	if err != nil {
+		return err
+	}
+	if len(others) > 0 {
+		return nil
+	}
+
+	if err := cmdexec.RunBashCmdWithoutOutput(ctx, fmt.Sprintf("echo -io > %s/%s", parent, SubtreeControlFile)); err != nil {
+		return fmt.Errorf("disable io controller of cgroup[%s] error: %s", parent, err.Error())
+	}
+
+	if err := movePidsOfCgroup(ctx, leaf, parent); err != nil {
+		return err
+	}
+
+	return RemoveCgroup(ctx, leaf)
+}
+
+// GetIoRecoverPath returns the absolute cgroup to move back a process of the io cgroup whose cgroup was oldPath.
+// The parent of the io cgroup can not hold processes before DisableIoController on cgroup v2
+func GetIoRecoverPath(cgroupPath, oldPath string) string {
+	oldPath = GetCgroupAbsPath(BLKIO, oldPath)
+	if !IsCgroupV2() {
+		return oldPath
+	}
+
+	parent := filepath.Dir(cgroupPath)
+	leaf := filepath.Join(parent, IoLeafCgroupName)
+	if filepath.Clean(oldPath) != parent {
+		return oldPath
+	}
+
+	if _, err := os.Stat(leaf); err != nil {
+		return oldPath
+	}
+
+	return leaf
+}
+
+// movePidsOfCgroup moves all processes of cgroup src to dst, it is retried because processes may fork meanwhile
+func movePidsOfCgroup(ctx context.Context, src, dst string) error {
+	for retry := 0; retry < moveRetryTimes; retry++ {
+		pidList, err := GetPidStrListByCgroup(ctx, src)
+		if err != nil {
+			return fmt.Errorf("get pid from cgroup[%s] error: %s", src, err.Error())
+		}
+
+		if len(pidList) == 0 {
+			return nil
+		}
+
+		for _, pid := range pidList {
+			if err := MoveTaskToCgroup(ctx, pid, dst); err != nil {
+				// the process may exit after listed
+				if _, statErr := os.Stat(fmt.Sprintf("/proc/%d", pid)); statErr == nil {
+					return fmt.Errorf("move pid[%d] to cgroup[%s] error: %s", pid, dst, err.Error())
+				}
+			}
+		}
+	}
+
+	return fmt.Errorf("processes keep being created in cgroup[%s]", src)
+}
+
 func CheckPidListBlkioCgroup(ctx context.Context, pidList []int) error {
 	for _, unitP := range pidList {
 		oldPath, err := GetpidCurCgroup(ctx, unitP, BLKIO)
@@ -111,7 +268,13 @@ func GetContainerCgroup(ctx context.Context, cr, cId string) (string, error) {
 	return GetpidCurCgroup(ctx, pid, BLKIO)
 }
 
+// GetpidCurCgroup returns the cgroup path of pid relative to the hierarchy of the subsystem, all subsystems share the
+// unified hierarchy on cgroup v2
 func GetpidCurCgroup(ctx context.Context, pid int, subSys string) (string, error) {
+	if IsCgroupV2() {
+		return containercgroup.GetUnifiedCgroup(pid)
+	}
+
 	re, err := cmdexec.RunBashCmdWithOutput(ctx, fmt.Sprintf("cat /proc/%d/cgroup | grep -w %s", pid, subSys))
 	if err != nil {
 		return "", fmt.Errorf("run cmd error: %s", err.Error())
@@ -137,7 +300,7 @@ func MovePidListToCgroup(ctx context.Context, pidList []int, cgroupPath string)
 }
 
 func MoveTaskToCgroup(ctx context.Context, pid int, cgroupPath string) error {
-	if err := cmdexec.RunBashCmdWithoutOutput(ctx, fmt.Sprintf("echo %d > %s/tasks", pid, cgroupPath)); err != nil {
+	if err := cmdexec.RunBashCmdWithoutOutput(ctx, fmt.Sprintf("echo %d > %s/%s", pid, cgroupPath, getTasksFile())); err != nil {
 		return err
 	}
 
@@ -153,7 +316,7 @@ func MoveTaskToCgroup(ctx context.Context, pid int, cgroupPath string) error {
 //}
 
 func GetPidStrListByCgroup(ctx context.Context, cgroupPath string) ([]int, error) {
-	re, err := cmdexec.RunBashCmdWithOutput(ctx, fmt.Sprintf("cat %s/tasks", cgroupPath))
+	re, err := cmdexec.RunBashCmdWithOutput(ctx, fmt.Sprintf("cat %s/%s", cgroupPath, getTasksFile()))
 	if err != nil {
 		return nil, fmt.Errorf("run cmd error: %s", err.Error())
 	}
@@ -183,3 +346,100 @@ func RemoveCgroup(ctx context.Context, cgroupPath string) error {
 
 	return nil
 }
+
+// IsCgroupV2 reports whether the unified hierarchy is mounted on the cgroup root
+func IsCgroupV2() bool {
+	return containercgroup.IsCgroupV2()
+}
+
+// getTasksFile returns the file to move a process into a cgroup, "tasks" only exists in cgroup v1
+func getTasksFile() string {
+	if IsCgroupV2() {
+		return CgroupProcsFile
+	}
+
+	return TasksFile
+}
+
+// GetContainerMemCgroupPath returns the absolute memory cgroup dir of the container, the root memory cgroup if cr is empty
+func GetContainerMemCgroupPath(ctx context.Context, cr, cId string) (string, error) {
+	var path = "/"
+	if cr != "" {
+		client, err := crclient.GetClient(ctx, cr)
+		if err != nil {
+			return "", fmt.Errorf("get %s client error: %s", cr, err.Error())
+		}
+
+		pid, err := client.GetPidById(ctx, cId)
+		if err != nil {
+			return "", fmt.Errorf("get pid of container[%s] error: %s", cId, err.Error())
+		}
+
+		if path, err = GetpidCurCgroup(ctx, pid, MEMORY); err != nil {
+			return "", fmt.Errorf("get memory cgroup of process[%d] error: %s", pid, err.Error())
+		}
+	}
+
+	return GetCgroupAbsPath(MEMORY, path), nil
+}
+
+// GetMemCgroupLimitAndUsage reads the memory limit and usage in bytes of the absolute cgroup dir, limit is
+// MemUnLimit if the cgroup has no limit
+func GetMemCgroupLimitAndUsage(cgroupPath string) (limit, usage int64, err error) {
+	limitFile, usageFile := MemoryLimitInBytesFile, MemoryUsageInBytesFile
+	if IsCgroupV2() {
+		limitFile, usageFile = MemoryMaxFile, MemoryCurrentFile
+	}
+
+	limitStr, err := readCgroupFile(cgroupPath, limitFile)
+	if err != nil {
+		return -1, -1, err
+	}
+
+	if limitStr == "max" {
+		limit = MemUnLimit
+	} else if limit, err = strconv.ParseInt(limitStr, 10, 64); err != nil {
+		return -1, -1, fmt.Errorf("limit[%s] is not a num: %s", limitStr, err.Error())
+	}
+
+	usageStr, err := readCgroupFile(cgroupPath, usageFile)
+	if err != nil {
+		return -1, -1, err
+	}
+
+	if usage, err = strconv.ParseInt(usageStr, 10, 64); err != nil {
+		return -1, -1, fmt.Errorf("usage[%s] is not a num: %s", usageStr, err.Error())
+	}
+
+	return limit, usage, nil
+}
+
+// SetMemCgroupLimit limits the memory of the absolute cgroup dir, it is skipped if the memory controller
+// is not enabled for the cgroup, which is the case for a child of a non-root cgroup v2 with processes
+func SetMemCgroupLimit(ctx context.Context, cgroupPath string, limitBytes int64) (bool, error) {
+	limitFile := MemoryLimitInBytesFile
+	if IsCgroupV2() {
+		limitFile = MemoryMaxFile
+	}
+
+	file := fmt.Sprintf("%s/%s", cgroupPath, limitFile)
+	if _, err := os.Stat(file); err != nil {
+		return false, nil
+	}
+
+	if err := cmdexec.RunBashCmdWithoutOutput(ctx, fmt.Sprintf("echo %d > %s", limitBytes, file)); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func readCgroupFile(cgroupPath, fileName string) (string, error) {
+	file := fmt.Sprintf("%s/%s", cgroupPath, fileName)
+	reByte, err := os.ReadFile(file)
+	if err != nil {
+		return "", fmt.Errorf("read from %s error: %s", file, err.Error())
+	}
+
+	return strings.TrimSpace(string(reByte)), nil
+}
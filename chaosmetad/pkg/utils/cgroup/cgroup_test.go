@@ -63,3 +63,38 @@ func TestGetBlkioConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestGetIoMaxConfig(t *testing.T) {
+	type args struct {
+		devList    []string
+		rBytes     string
+		wBytes     string
+		rIO        int64
+		wIO        int64
+		cgroupPath string
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{
+			args: args{
+				devList:    []string{"8:0", "8:1"},
+				rBytes:     "200kb",
+				wIO:        6,
+				cgroupPath: "/sys/fs/cgroup/kubepods/chaosmeta_blkio_1241q52",
+			},
+			want: "echo \"8:0 rbps=204800 wiops=6\" > /sys/fs/cgroup/kubepods/chaosmeta_blkio_1241q52/io.max &&" +
+				" echo \"8:1 rbps=204800 wiops=6\" > /sys/fs/cgroup/kubepods/chaosmeta_blkio_1241q52/io.max",
+		},
+	}
+	for _, tt := range tests {
+		ctx := context.Background()
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GetIoMaxConfig(ctx, tt.args.devList, tt.args.rBytes, tt.args.wBytes, tt.args.rIO, tt.args.wIO, tt.args.cgroupPath); got != tt.want {
+				t.Errorf("GetIoMaxConfig() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -33,4 +33,25 @@ const (
 	WriteIOFile            = "blkio.throttle.write_iops_device"
 	ReadIOFile             = "blkio.throttle.read_iops_device"
 	BlkioCgroupName        = "chaosmeta_blkio"
+	IoLeafCgroupName       = "chaosmeta_leaf"
+	MemCgroupName          = "chaosmeta_mem"
+	TasksFile              = "tasks"
+
+	// cgroup v2 files
+	CgroupControllersFile = "cgroup.controllers"
+	CgroupProcsFile       = "cgroup.procs"
+	MemoryMaxFile         = "memory.max"
+	MemoryCurrentFile     = "memory.current"
+	CpusetEffectiveFile   = "cpuset.cpus.effective"
+	IoMaxFile             = "io.max"
+	SubtreeControlFile    = "cgroup.subtree_control"
+
+	moveRetryTimes = 5
 )
+
+// v2FileMap maps a v1 file to the cgroup v2 file with the same meaning
+var v2FileMap = map[string]string{
+	MemoryLimitInBytesFile: MemoryMaxFile,
+	MemoryUsageInBytesFile: MemoryCurrentFile,
+	CpusetCoreFile:         CpusetEffectiveFile,
+}
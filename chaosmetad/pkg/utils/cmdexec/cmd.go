@@ -19,11 +19,14 @@ package cmdexec
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/audit"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/crclient"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/log"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/containercgroup"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/errutil"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/namespace"
 	"os/exec"
 	"strings"
@@ -34,6 +37,8 @@ import (
 const (
 	InjectCheckInterval = time.Millisecond * 200
 	cgroupWaitInterval  = time.Millisecond * 200
+	// ExecWaitTimeout bounds the waiting for the result of a started command, the process tree is killed when expired
+	ExecWaitTimeout = time.Second * 60
 
 	ExecWait  = "wait"
 	ExecStart = "start"
@@ -122,10 +127,24 @@ func CpContainerFile(ctx context.Context, cr, containerID, src, dst string) erro
 	return client.CpFile(ctx, containerID, src, dst)
 }
 
+// StartSleepRecover and StartPulse are not self-limited, the recover must not be killed with the tools by the ceilings
 func StartSleepRecover(ctx context.Context, sleepTime int64, uid string) error {
-	return StartBashCmd(ctx, utils.GetSleepRecoverCmd(sleepTime, uid))
+	return startBashCmd(ctx, utils.GetSleepRecoverCmd(sleepTime, uid))
 }
 
+func StartPulse(ctx context.Context, uid string) error {
+	return startBashCmd(ctx, utils.GetPulseCmd(uid))
+}
+
+// StartScenarioFault starts the fault of a scenario with a start offset, it is not self-limited like the pulse
+func StartScenarioFault(ctx context.Context, sleepTime int64, uid string, index int) error {
+	return startBashCmd(ctx, utils.GetScenarioStartCmd(sleepTime, uid, index))
+}
+
+var errWaitTimeout = errors.New("wait timeout")
+
+// waitProExec waits for the output of a started command. If timeoutSec > 0, the command is regarded as success without
+// error output in timeoutSec, otherwise "[success]" is expected in ExecWaitTimeout
 func waitProExec(ctx context.Context, stdout, stderr *bytes.Buffer, timeoutSec int) (err error) {
 	var msg, timer = "", time.NewTimer(InjectCheckInterval)
 	var startTime = time.Now()
@@ -141,13 +160,17 @@ func waitProExec(ctx context.Context, stdout, stderr *bytes.Buffer, timeoutSec i
 			break
 		}
 
+		if timeoutSec <= 0 && time.Now().After(startTime.Add(ExecWaitTimeout)) {
+			return fmt.Errorf("%w: no output in %s", errWaitTimeout, ExecWaitTimeout)
+		}
+
 		timer.Reset(InjectCheckInterval)
 	}
 
 	log.GetLogger(ctx).Debugf(msg)
 
 	if strings.Index(msg, "error") >= 0 || strings.Index(msg, "Error") >= 0 {
-		return fmt.Errorf("inject error: %s", msg)
+		return fmt.Errorf("inject error: %s", errutil.NewToolErr(msg).Error())
 	}
 
 	if timeoutSec <= 0 {
@@ -175,6 +198,7 @@ func RunBashCmdWithOutput(ctx context.Context, cmd string) (string, error) {
 	c := exec.Command("/bin/bash", "-c", cmd)
 
 	reByte, err := c.CombinedOutput()
+	auditCmd(ctx, ExecRun, cmd, c, err)
 	re := string(reByte)
 	errMsg := fmt.Sprintf("exit code: %d, output: %s, error: %v", c.ProcessState.Sys().(syscall.WaitStatus).ExitStatus(), re, err)
 	log.GetLogger(ctx).Debugf("exec result: %s", errMsg)
@@ -193,28 +217,67 @@ func RunBashCmdWithOutput(ctx context.Context, cmd string) (string, error) {
 	return re, nil
 }
 
+// RunBashCmdWithTimeout runs cmd and kills its process group if it is not finished in timeout, the output is returned
+// even if the cmd fails
+func RunBashCmdWithTimeout(ctx context.Context, cmd string, timeout time.Duration) (string, error) {
+	log.GetLogger(ctx).Debugf("run cmd with timeout %s: %s", timeout, cmd)
+	c := exec.Command("/bin/bash", "-c", cmd)
+	var output bytes.Buffer
+	c.Stdout, c.Stderr = &output, &output
+	c.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+
+	if err := c.Start(); err != nil {
+		auditCmd(ctx, ExecRun, cmd, c, err)
+		return "", fmt.Errorf("cmd start error: %s", err.Error())
+	}
+
+	err := waitWithTimeout(ctx, c, timeout)
+	auditCmd(ctx, ExecRun, cmd, c, err)
+	if err != nil {
+		return output.String(), fmt.Errorf("exit code: %d, error: %s", c.ProcessState.ExitCode(), err.Error())
+	}
+
+	return output.String(), nil
+}
+
 func RunBashCmdWithoutOutput(ctx context.Context, cmd string) error {
 	log.GetLogger(ctx).Debugf("run cmd: %s", cmd)
-	return exec.Command("/bin/bash", "-c", cmd).Run()
+	c := exec.Command("/bin/bash", "-c", cmd)
+	err := c.Run()
+	auditCmd(ctx, ExecRun, cmd, c, err)
+	return err
 }
 
 func StartBashCmd(ctx context.Context, cmd string) error {
+	return startBashCmd(ctx, getSelfLimitCmd(ctx, cmd))
+}
+
+func startBashCmd(ctx context.Context, cmd string) error {
 	log.GetLogger(ctx).Debugf("start cmd: %s", cmd)
-	return exec.Command("/bin/bash", "-c", cmd).Start()
+	c := exec.Command("/bin/bash", "-c", cmd)
+	err := c.Start()
+	auditCmd(ctx, ExecStart, cmd, c, err)
+	return err
 }
 
 func StartBashCmdAndWaitPid(ctx context.Context, cmd string, timeoutSec int) (int, error) {
+	cmd = getSelfLimitCmd(ctx, cmd)
 	log.GetLogger(ctx).Debugf("start cmd: %s", cmd)
 
 	c := exec.Command("/bin/bash", "-c", cmd)
 	var stdout, stderr bytes.Buffer
 	c.Stdout, c.Stderr = &stdout, &stderr
+	c.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
 
 	if err := c.Start(); err != nil {
+		auditCmd(ctx, ExecWait, cmd, c, err)
 		return utils.NoPid, fmt.Errorf("cmd start error: %s", err.Error())
 	}
 
-	if err := waitProExec(ctx, &stdout, &stderr, timeoutSec); err != nil {
+	err := waitProExec(ctx, &stdout, &stderr, timeoutSec)
+	auditCmd(ctx, ExecWait, cmd, c, err)
+	killTreeIfTimeout(ctx, c, err)
+	if err != nil {
 		return c.Process.Pid, fmt.Errorf("wait process exec error: %s", err.Error())
 	}
 
@@ -224,24 +287,52 @@ func StartBashCmdAndWaitPid(ctx context.Context, cmd string, timeoutSec int) (in
 func StartBashCmdAndWaitByUser(ctx context.Context, cmd, user string) error {
 	log.GetLogger(ctx).Debugf("user: %s, start cmd: %s", user, cmd)
 
-	c := exec.Command("runuser", "-l", user, "-c", cmd)
+	// the bash joins the self-limit cgroup as root, then execs runuser with the args passed as $0 and $1 to avoid quoting
+	c := exec.Command("/bin/bash", "-c", getSelfLimitCmd(ctx, "exec runuser -l \"$0\" -c \"$1\""), user, cmd)
 	var stdout, stderr bytes.Buffer
 	c.Stdout, c.Stderr = &stdout, &stderr
+	c.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
 	if err := c.Start(); err != nil {
+		auditCmd(ctx, ExecWait, c.String(), c, err)
 		return fmt.Errorf("cmd start error: %s", err.Error())
 	}
 
-	if err := waitProExec(ctx, &stdout, &stderr, 0); err != nil {
+	err := waitProExec(ctx, &stdout, &stderr, 0)
+	auditCmd(ctx, ExecWait, c.String(), c, err)
+	killTreeIfTimeout(ctx, c, err)
+	if err != nil {
 		return fmt.Errorf("wait process exec error: %s", err.Error())
 	}
 
 	return nil
 }
 
+// ExecOption is the process settings of a command executed in a container, the empty fields keep the default
+// settings: root user, the working directory of the target init process and the environment of execns
+type ExecOption struct {
+	// User is "uid" or "uid:gid" in the container, the numeric ids are used since the users of the container are
+	// unknown to the host. Command setpriv is required in the container
+	User    string
+	WorkDir string
+	// Env is a list of "key=value"
+	Env []string
+	// Timeout bounds the running of method ExecRun, the process tree is killed when expired, 0 means no limit. The
+	// waiting of method ExecWait is always bounded by ExecWaitTimeout
+	Timeout time.Duration
+}
+
 // finish: false[wait success], true[finish and get all output]
 
 func ExecContainer(ctx context.Context, cr, containerID string, namespaces []string, cmd string, method string) (string, error) {
+	return ExecContainerWithOption(ctx, cr, containerID, namespaces, cmd, method, nil)
+}
+
+func ExecContainerWithOption(ctx context.Context, cr, containerID string, namespaces []string, cmd string, method string, opt *ExecOption) (string, error) {
 	logger := log.GetLogger(ctx)
+	cmd, err := getOptionCmd(cmd, opt)
+	if err != nil {
+		return "", fmt.Errorf("exec option error: %s", err.Error())
+	}
 
 	// get container's init process
 	client, err := crclient.GetClient(ctx, cr)
@@ -255,13 +346,16 @@ func ExecContainer(ctx context.Context, cr, containerID string, namespaces []str
 	}
 
 	// exec ns
-	c := exec.Command("/bin/bash", "-c", fmt.Sprintf("%s -t %d %s -c \"%s\"",
-		utils.GetToolPath(namespace.ExecnsKey), targetPid, namespace.GetNsOption(namespaces), cmd))
+	execCmd := fmt.Sprintf("%s -t %d %s -c \"%s\"",
+		utils.GetToolPath(namespace.ExecnsKey), targetPid, namespace.GetNsOption(namespaces), cmd)
+	c := exec.Command("/bin/bash", "-c", execCmd)
 
 	var stdout, stderr bytes.Buffer
 	c.Stdout, c.Stderr = &stdout, &stderr
+	c.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
 	logger.Debugf("container exec cmd: %s", c.Args)
 	if err := c.Start(); err != nil {
+		auditCmd(ctx, method, execCmd, c, err)
 		return "", fmt.Errorf("start process error: %s", err.Error())
 	}
 
@@ -271,21 +365,32 @@ func ExecContainer(ctx context.Context, cr, containerID string, namespaces []str
 			logger.Warnf("undo: kill container exec process[%d] error: %s", c.Process.Pid, err.Error())
 		}
 
-		return "", fmt.Errorf("add process[%d] to container[%d] cgroup error: %s", c.Process.Pid, targetPid, err.Error())
+		err = fmt.Errorf("add process[%d] to container[%d] cgroup error: %s", c.Process.Pid, targetPid, err.Error())
+		auditCmd(ctx, method, execCmd, c, err)
+		return "", err
 	}
 
 	// signal continue
 	time.Sleep(cgroupWaitInterval)
 	if err := c.Process.Signal(syscall.SIGCONT); err != nil {
+		auditCmd(ctx, method, execCmd, c, err)
 		return "", err
 	}
 
 	// solve return
 	switch method {
 	case ExecWait:
-		return "", waitProExec(ctx, &stdout, &stderr, 0)
+		err = waitProExec(ctx, &stdout, &stderr, 0)
+		auditCmd(ctx, method, execCmd, c, err)
+		killTreeIfTimeout(ctx, c, err)
+		return "", err
 	case ExecRun:
-		err = c.Wait()
+		var timeout time.Duration
+		if opt != nil {
+			timeout = opt.Timeout
+		}
+		err = waitWithTimeout(ctx, c, timeout)
+		auditCmd(ctx, method, execCmd, c, err)
 		combinedOutput := stdout.String() + stderr.String()
 		errMsg := fmt.Sprintf("exit code: %d, output: %s， err: %v", c.ProcessState.Sys().(syscall.WaitStatus).ExitStatus(), combinedOutput, err)
 		logger.Debugf("container exec result: %s", errMsg)
@@ -296,6 +401,7 @@ func ExecContainer(ctx context.Context, cr, containerID string, namespaces []str
 		}
 
 	case ExecStart:
+		auditCmd(ctx, method, execCmd, c, nil)
 		return "", nil
 	default:
 		return "", fmt.Errorf("unknown exec method")
@@ -330,3 +436,54 @@ func ExecBackGroundCommon(ctx context.Context, cr, cId, cmd string) error {
 		return err
 	}
 }
+
+// auditCmd appends the executed command to the audit log, failure to audit never fails the command itself
+func auditCmd(ctx context.Context, method, cmd string, c *exec.Cmd, err error) {
+	pid, exitCode := 0, audit.ExitCodeUnknown
+	if c.Process != nil {
+		pid = c.Process.Pid
+	}
+
+	if c.ProcessState != nil {
+		exitCode = c.ProcessState.ExitCode()
+	}
+
+	if aErr := audit.Write(ctx, method, cmd, pid, exitCode, err); aErr != nil {
+		log.GetLogger(ctx).Warnf("write command audit error: %s", aErr.Error())
+	}
+}
+
+// killTreeIfTimeout kills the process group of a command whose waiting is expired, so that the execns and tool
+// processes of a failed injection are not leaked
+func killTreeIfTimeout(ctx context.Context, c *exec.Cmd, err error) {
+	if err == nil || !errors.Is(err, errWaitTimeout) || c.Process == nil {
+		return
+	}
+
+	log.GetLogger(ctx).Warnf("kill process tree of [%d] for wait timeout", c.Process.Pid)
+	if kErr := syscall.Kill(-c.Process.Pid, syscall.SIGKILL); kErr != nil {
+		log.GetLogger(ctx).Warnf("kill process group[%d] error: %s", c.Process.Pid, kErr.Error())
+	}
+}
+
+// waitWithTimeout waits for a started command to exit, the process tree is killed if timeout > 0 and expired
+func waitWithTimeout(ctx context.Context, c *exec.Cmd, timeout time.Duration) error {
+	if timeout <= 0 {
+		return c.Wait()
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- c.Wait()
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(timeout):
+		err := fmt.Errorf("%w: not exit in %s", errWaitTimeout, timeout)
+		killTreeIfTimeout(ctx, c, err)
+		<-done
+		return err
+	}
+}
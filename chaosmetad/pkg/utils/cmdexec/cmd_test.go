@@ -0,0 +1,38 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmdexec
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRunBashCmdWithTimeout(t *testing.T) {
+	ctx := context.Background()
+	output, err := RunBashCmdWithTimeout(ctx, "echo ready; exit 3", time.Second)
+	if err == nil || !strings.Contains(err.Error(), "exit code: 3") || output != "ready\n" {
+		t.Errorf("RunBashCmdWithTimeout() failed cmd = %q, %v", output, err)
+	}
+
+	start := time.Now()
+	output, err = RunBashCmdWithTimeout(ctx, "echo waiting; sleep 10", 200*time.Millisecond)
+	if err == nil || output != "waiting\n" || time.Since(start) > 5*time.Second {
+		t.Errorf("RunBashCmdWithTimeout() timeout cmd = %q, %v", output, err)
+	}
+}
@@ -0,0 +1,89 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmdexec
+
+import (
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+	"regexp"
+	"strings"
+)
+
+var (
+	execUserRegexp = regexp.MustCompile(`^[0-9]+(:[0-9]+)?$`)
+	envKeyRegexp   = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+)
+
+// getOptionCmd wraps cmd with the settings of opt. The result is put into the double quotes of the execns command, so
+// the added parts are escaped for both the host shell and the shell in the container, while cmd is kept as it is
+func getOptionCmd(cmd string, opt *ExecOption) (string, error) {
+	if opt == nil {
+		return cmd, nil
+	}
+
+	var prefix []string
+	if opt.WorkDir != "" {
+		prefix = append(prefix, fmt.Sprintf("cd %s", quoteInExecns(opt.WorkDir)))
+	}
+
+	for _, unitEnv := range opt.Env {
+		kv := strings.SplitN(unitEnv, "=", 2)
+		if len(kv) != 2 || !envKeyRegexp.MatchString(kv[0]) {
+			return "", fmt.Errorf("env[%s] is not in format key=value", unitEnv)
+		}
+		prefix = append(prefix, fmt.Sprintf("export %s=%s", kv[0], quoteInExecns(kv[1])))
+	}
+
+	if opt.User != "" {
+		if !execUserRegexp.MatchString(opt.User) {
+			return "", fmt.Errorf("user[%s] is not in format uid or uid:gid", opt.User)
+		}
+
+		ids := strings.SplitN(opt.User, ":", 2)
+		uid, gid := ids[0], ids[0]
+		if len(ids) == 2 {
+			gid = ids[1]
+		}
+
+		// the command runs as root already, setpriv which may be missing in the image is only used to switch to others
+		if uid != "0" || gid != "0" {
+			cmd = fmt.Sprintf("setpriv --reuid=%s --regid=%s --clear-groups -- /bin/bash -c '%s'", uid, gid, strings.ReplaceAll(cmd, "'", `'\''`))
+		}
+	}
+
+	if len(prefix) == 0 {
+		return cmd, nil
+	}
+
+	return fmt.Sprintf("%s%s%s", strings.Join(prefix, utils.CmdSplit), utils.CmdSplit, cmd), nil
+}
+
+// GetExecnsBashCmd keeps cmd from the host shell of the execns command. In a container, cmd is run by "/bin/bash -c"
+// quoted by quoteInExecns, so its variables, substitutions and quotes are only expanded by the shell in the container
+func GetExecnsBashCmd(cr, cmd string) string {
+	if cr == "" {
+		return cmd
+	}
+
+	return fmt.Sprintf("/bin/bash -c %s", quoteInExecns(cmd))
+}
+
+// quoteInExecns single quotes s for the shell in the container, then escapes it for the double quotes of the host shell
+func quoteInExecns(s string) string {
+	s = fmt.Sprintf("'%s'", strings.ReplaceAll(s, "'", `'\''`))
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "$", `\$`, "`", "\\`").Replace(s)
+}
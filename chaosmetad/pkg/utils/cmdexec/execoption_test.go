@@ -0,0 +1,86 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmdexec
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func Test_getOptionCmd(t *testing.T) {
+	tests := []struct {
+		name    string
+		cmd     string
+		opt     *ExecOption
+		want    string
+		wantErr bool
+	}{
+		{name: "no option", cmd: "pwd", want: "/\n"},
+		{name: "workdir and env", cmd: "printenv A; pwd", opt: &ExecOption{WorkDir: "/tmp", Env: []string{`A=it's $HOME "q" \`}}, want: "it's $HOME \"q\" \\\n/tmp\n"},
+		{name: "user", cmd: "echo 'a b'; id -u", opt: &ExecOption{User: "1:1"}, want: "a b\n1\n"},
+		{name: "root", cmd: "echo 'a b'; id -u", opt: &ExecOption{User: "0:0"}, want: "a b\n0\n"},
+		{name: "bad env", cmd: "pwd", opt: &ExecOption{Env: []string{"A B=1"}}, wantErr: true},
+		{name: "bad user", cmd: "pwd", opt: &ExecOption{User: "root"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := getOptionCmd(tt.cmd, tt.opt)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("getOptionCmd() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if tt.opt != nil && tt.opt.User == "0:0" && strings.Contains(got, "setpriv") {
+				t.Errorf("getOptionCmd() = %s, setpriv is not required for root", got)
+			}
+			if tt.opt != nil && tt.opt.User != "" && (os.Geteuid() != 0 || (tt.opt.User != "0:0" && !SupportCmd("setpriv"))) {
+				t.Skip("setpriv as root is required")
+			}
+
+			// the same quoting as the execns command, the shell in the container is simulated by bash
+			out, err := exec.Command("/bin/bash", "-c", fmt.Sprintf("cd / && /bin/bash -c \"%s\"", got)).CombinedOutput()
+			if err != nil {
+				t.Fatalf("exec %s error: %s, output: %s", got, err.Error(), out)
+			}
+			if string(out) != tt.want {
+				t.Errorf("getOptionCmd() output = %q, want %q", out, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetExecnsBashCmd(t *testing.T) {
+	cmd := `line=$(echo "a b"); d=$((1+1)); echo "${line} $d" 'q'`
+	if got := GetExecnsBashCmd("", cmd); got != cmd {
+		t.Errorf("GetExecnsBashCmd() on host = %s, want %s", got, cmd)
+	}
+
+	// the same quoting as the execns command, the shell in the container is simulated by bash
+	got := GetExecnsBashCmd("docker", cmd)
+	out, err := exec.Command("/bin/bash", "-c", fmt.Sprintf("cd / && /bin/bash -c \"%s\"", got)).CombinedOutput()
+	if err != nil {
+		t.Fatalf("exec %s error: %s, output: %s", got, err.Error(), out)
+	}
+	if string(out) != "a b 2 q\n" {
+		t.Errorf("GetExecnsBashCmd() output = %q, want %q", out, "a b 2 q\n")
+	}
+}
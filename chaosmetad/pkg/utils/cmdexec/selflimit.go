@@ -0,0 +1,141 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmdexec
+
+import (
+	"context"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/config"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/log"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/containercgroup"
+	"os"
+	"strconv"
+)
+
+const (
+	SelfLimitCgroup = "chaosmeta_tool"
+
+	cpuPeriodUs    = 100000
+	v1UnlimitValue = "-1"
+	v2UnlimitValue = "max"
+)
+
+// getSelfLimitCmd makes cmd join the self-limit cgroup before it starts, so that all the tool processes forked by cmd
+// share the cpu and memory ceilings of the config. cmd is returned as is if no ceiling is configured or the cgroup is
+// not available, the self-limit never fails the fault
+func getSelfLimitCmd(ctx context.Context, cmd string) string {
+	logger := log.GetLogger(ctx)
+	c, err := config.Get()
+	if err != nil {
+		logger.Warnf("get config error, start without self limit: %s", err.Error())
+		return cmd
+	}
+
+	if c.ToolCpuLimit <= 0 && c.ToolMemLimit == "" {
+		return cmd
+	}
+
+	var memBytes int64
+	if c.ToolMemLimit != "" {
+		memBytes, _ = utils.GetBytes(c.ToolMemLimit)
+	}
+
+	procsFiles, err := prepareSelfLimitCgroup(c.ToolCpuLimit, memBytes)
+	if err != nil {
+		logger.Warnf("prepare self limit cgroup error, start without self limit: %s", err.Error())
+		return cmd
+	}
+
+	var joinCmd string
+	for _, f := range procsFiles {
+		joinCmd += fmt.Sprintf("{ echo $$ > %s; } 2>/dev/null; ", f)
+	}
+
+	return joinCmd + cmd
+}
+
+// prepareSelfLimitCgroup creates the self-limit cgroup and applies the ceilings, a ceiling not larger than 0 means no
+// limit. The cgroup.procs files to join are returned
+func prepareSelfLimitCgroup(cpuPercent int, memBytes int64) ([]string, error) {
+	cpuQuota, memLimit := v1UnlimitValue, v1UnlimitValue
+	if cpuPercent > 0 {
+		cpuQuota = strconv.Itoa(cpuPercent * cpuPeriodUs / 100)
+	}
+	if memBytes > 0 {
+		memLimit = strconv.FormatInt(memBytes, 10)
+	}
+
+	if containercgroup.IsCgroupV2() {
+		if cpuPercent <= 0 {
+			cpuQuota = v2UnlimitValue
+		}
+		if memBytes <= 0 {
+			memLimit = v2UnlimitValue
+		}
+
+		if err := writeCgroupFile(containercgroup.RootCgroupPath, "cgroup.subtree_control", "+cpu +memory"); err != nil {
+			return nil, err
+		}
+
+		path := fmt.Sprintf("%s/%s", containercgroup.RootCgroupPath, SelfLimitCgroup)
+		if err := os.MkdirAll(path, 0755); err != nil {
+			return nil, fmt.Errorf("create cgroup %s error: %s", path, err.Error())
+		}
+
+		if err := writeCgroupFile(path, "cpu.max", fmt.Sprintf("%s %d", cpuQuota, cpuPeriodUs)); err != nil {
+			return nil, err
+		}
+
+		if err := writeCgroupFile(path, "memory.max", memLimit); err != nil {
+			return nil, err
+		}
+
+		return []string{fmt.Sprintf("%s/cgroup.procs", path)}, nil
+	}
+
+	cpuPath := fmt.Sprintf("%s/cpu/%s", containercgroup.RootCgroupPath, SelfLimitCgroup)
+	memPath := fmt.Sprintf("%s/memory/%s", containercgroup.RootCgroupPath, SelfLimitCgroup)
+	for _, path := range []string{cpuPath, memPath} {
+		if err := os.MkdirAll(path, 0755); err != nil {
+			return nil, fmt.Errorf("create cgroup %s error: %s", path, err.Error())
+		}
+	}
+
+	if err := writeCgroupFile(cpuPath, "cpu.cfs_period_us", strconv.Itoa(cpuPeriodUs)); err != nil {
+		return nil, err
+	}
+
+	if err := writeCgroupFile(cpuPath, "cpu.cfs_quota_us", cpuQuota); err != nil {
+		return nil, err
+	}
+
+	if err := writeCgroupFile(memPath, "memory.limit_in_bytes", memLimit); err != nil {
+		return nil, err
+	}
+
+	return []string{fmt.Sprintf("%s/cgroup.procs", cpuPath), fmt.Sprintf("%s/cgroup.procs", memPath)}, nil
+}
+
+func writeCgroupFile(path, file, value string) error {
+	filePath := fmt.Sprintf("%s/%s", path, file)
+	if err := os.WriteFile(filePath, []byte(value), 0644); err != nil {
+		return fmt.Errorf("write \"%s\" to %s error: %s", value, filePath, err.Error())
+	}
+
+	return nil
+}
@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strconv"
 	"strings"
 	"time"
@@ -36,6 +37,9 @@ const (
 	RootName   = "chaosmetad"
 	TimeFormat = "2006-01-02 15:04:05"
 	RecoverLog = "/tmp/chaosmetad_recover.log"
+
+	MinUidLength = 5
+	MaxUidLength = 36
 )
 
 // TraceId for command line
@@ -49,6 +53,7 @@ const (
 
 const (
 	CtxTraceId = "TraceId"
+	CtxUid     = "Uid"
 )
 
 const (
@@ -63,6 +68,10 @@ const (
 	StatusSuccess   = "success"
 	StatusError     = "error"
 	StatusDestroyed = "destroyed"
+	// StatusIdle the fault of a pulse experiment is recovered temporarily
+	StatusIdle = "idle"
+	// StatusPaused the fault is recovered temporarily by the user until resumed
+	StatusPaused = "paused"
 )
 
 func NewUid() string {
@@ -72,8 +81,8 @@ func NewUid() string {
 }
 
 func IsValidUid(uid string) error {
-	if len(uid) > 36 || len(uid) < 5 {
-		return fmt.Errorf("length should be in [5, 36]")
+	if len(uid) > MaxUidLength || len(uid) < MinUidLength {
+		return fmt.Errorf("length should be in [%d, %d]", MinUidLength, MaxUidLength)
 	}
 
 	for _, letter := range uid {
@@ -104,7 +113,14 @@ func GetRunPath() string {
 	return filepath.Dir(executablePath)
 }
 
+// GetToolPath prefers the binary of the host architecture in "tools/[arch]/", and falls back to "tools/" for the
+// single architecture bundle and the architecture independent tools
 func GetToolPath(tool string) string {
+	archPath := fmt.Sprintf("%s/tools/%s/%s", GetRunPath(), runtime.GOARCH, tool)
+	if _, err := os.Stat(archPath); err == nil {
+		return archPath
+	}
+
 	return fmt.Sprintf("%s/tools/%s", GetRunPath(), tool)
 }
 
@@ -120,6 +136,14 @@ func GetSleepRecoverCmd(sleepTime int64, uid string) string {
 	return fmt.Sprintf("sleep %ds; %s/%s recover %s >> %s 2>&1", sleepTime, GetRunPath(), RootName, uid, RecoverLog)
 }
 
+func GetPulseCmd(uid string) string {
+	return fmt.Sprintf("%s/%s pulse %s >> %s 2>&1", GetRunPath(), RootName, uid, RecoverLog)
+}
+
+func GetScenarioStartCmd(sleepTime int64, uid string, index int) string {
+	return fmt.Sprintf("sleep %ds; %s/%s scenario %s %d >> %s 2>&1", sleepTime, GetRunPath(), RootName, uid, index, RecoverLog)
+}
+
 func GetTraceId(ctx context.Context) string {
 	if ctx.Value(CtxTraceId) == nil {
 		return ""
@@ -136,6 +160,18 @@ func GetCtxWithTraceId(ctx context.Context, traceId string) context.Context {
 	return context.WithValue(ctx, CtxTraceId, traceId)
 }
 
+func GetUid(ctx context.Context) string {
+	if ctx.Value(CtxUid) == nil {
+		return ""
+	}
+
+	return ctx.Value(CtxUid).(string)
+}
+
+func GetCtxWithUid(ctx context.Context, uid string) context.Context {
+	return context.WithValue(ctx, CtxUid, uid)
+}
+
 func GetNumArrByList(listStr string) ([]int, error) {
 	var listArr []int
 	var ifExist = make(map[int]bool)
@@ -20,14 +20,57 @@ import (
 	"fmt"
 	"github.com/containerd/cgroups"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 )
 
 const (
 	RootCgroupPath = "/sys/fs/cgroup"
+
+	cgroupControllersFile = "cgroup.controllers"
+	cgroupProcsFile       = "cgroup.procs"
+	procStatFile          = "/proc/stat"
 )
 
+// IsCgroupV2 reports whether the unified hierarchy is mounted on the cgroup root, the controllers of a hybrid host are
+// still in v1 hierarchies
+func IsCgroupV2() bool {
+	_, err := os.Stat(fmt.Sprintf("%s/%s", RootCgroupPath, cgroupControllersFile))
+	return err == nil
+}
+
+// GetUnifiedCgroup returns the cgroup v2 path of pid relative to the cgroup root
+func GetUnifiedCgroup(pid int) (string, error) {
+	reByte, err := os.ReadFile(fmt.Sprintf("/proc/%d/cgroup", pid))
+	if err != nil {
+		return "", fmt.Errorf("read cgroup of process[%d] error: %s", pid, err.Error())
+	}
+
+	for _, line := range strings.Split(string(reByte), "\n") {
+		if strings.HasPrefix(line, "0::") {
+			return strings.TrimPrefix(line, "0::"), nil
+		}
+	}
+
+	return "", fmt.Errorf("not found unified cgroup of process[%d]", pid)
+}
+
 func AddToProCgroup(mPid, cPid int) error {
+	if IsCgroupV2() {
+		path, err := GetUnifiedCgroup(cPid)
+		if err != nil {
+			return err
+		}
+
+		procsFile := fmt.Sprintf("%s%s/%s", RootCgroupPath, path, cgroupProcsFile)
+		if err := os.WriteFile(procsFile, []byte(strconv.Itoa(mPid)), 0644); err != nil {
+			return fmt.Errorf("add process[%d] to %s error: %s", mPid, procsFile, err.Error())
+		}
+
+		return nil
+	}
+
 	cgroup, err := LoadCgroup(cPid)
 	if err != nil {
 		return fmt.Errorf("load cgroup of process[%d] error: %s", cPid, err.Error())
@@ -40,7 +83,13 @@ func AddToProCgroup(mPid, cPid int) error {
 	return nil
 }
 
+// CalculateNowPercent returns the usage percent of each core by the cgroup of targetPid. cgroup v2 has no usage per
+// core, so the usage of the whole host is returned instead
 func CalculateNowPercent(targetPid int) ([]float64, error) {
+	if IsCgroupV2() {
+		return calculateHostPercent()
+	}
+
 	cgroup, err := LoadCgroup(targetPid)
 	if err != nil {
 		return nil, fmt.Errorf("load cgroup of [%d] error: %s", targetPid, err.Error())
@@ -64,6 +113,75 @@ func CalculateNowPercent(targetPid int) ([]float64, error) {
 	return perUsage, nil
 }
 
+func calculateHostPercent() ([]float64, error) {
+	before, err := readCoreTicks()
+	if err != nil {
+		return nil, err
+	}
+
+	time.Sleep(time.Second * 2)
+	after, err := readCoreTicks()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(before) != len(after) {
+		return nil, fmt.Errorf("count of cores changed from %d to %d", len(before), len(after))
+	}
+
+	perUsage := make([]float64, len(before))
+	for i := range before {
+		total, idle := after[i][0]-before[i][0], after[i][1]-before[i][1]
+		if total > 0 {
+			perUsage[i] = float64(total-idle) / float64(total) * 100
+		}
+	}
+
+	return perUsage, nil
+}
+
+// readCoreTicks returns the total and idle(with iowait) ticks of each core from /proc/stat, indexed by the core id
+func readCoreTicks() ([][2]uint64, error) {
+	reByte, err := os.ReadFile(procStatFile)
+	if err != nil {
+		return nil, fmt.Errorf("read %s error: %s", procStatFile, err.Error())
+	}
+
+	var ticks [][2]uint64
+	for _, line := range strings.Split(string(reByte), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 9 || !strings.HasPrefix(fields[0], "cpu") || fields[0] == "cpu" {
+			continue
+		}
+
+		core, err := strconv.Atoi(strings.TrimPrefix(fields[0], "cpu"))
+		if err != nil {
+			return nil, fmt.Errorf("%s is not a valid core: %s", fields[0], err.Error())
+		}
+
+		// user, nice, system, idle, iowait, irq, softirq, steal. guest is already counted in user
+		var total, idle uint64
+		for i, field := range fields[1:9] {
+			v, err := strconv.ParseUint(field, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("%s of %s is not a num: %s", field, fields[0], err.Error())
+			}
+
+			total += v
+			if i == 3 || i == 4 {
+				idle += v
+			}
+		}
+
+		for len(ticks) <= core {
+			ticks = append(ticks, [2]uint64{})
+		}
+		ticks[core] = [2]uint64{total, idle}
+	}
+
+	return ticks, nil
+}
+
 func LoadCgroup(cPid int) (cgroups.Cgroup, error) {
 	if cPid == -1 {
 		return cgroups.Load(hierarchy(RootCgroupPath), cgroups.StaticPath("/"))
@@ -24,8 +24,11 @@ import (
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/cmdexec"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/namespace"
 	"strings"
+	"syscall"
 )
 
+const FillFileName = "chaosmeta_fill"
+
 func GetDevList(ctx context.Context, cr, cId string, devStr string) ([]string, error) {
 	if devStr == "" {
 		return nil, fmt.Errorf("args dev-list is empty")
@@ -70,6 +73,25 @@ func existDev(ctx context.Context, cr, cId string, devNum string) (bool, error)
 	return false, nil
 }
 
+// IsOnRootFS checks whether the path is on the same filesystem as "/"
+func IsOnRootFS(path string) (bool, error) {
+	var pathStat, rootStat syscall.Stat_t
+	if err := syscall.Stat(path, &pathStat); err != nil {
+		return false, fmt.Errorf("stat %s error: %s", path, err.Error())
+	}
+
+	if err := syscall.Stat("/", &rootStat); err != nil {
+		return false, fmt.Errorf("stat / error: %s", err.Error())
+	}
+
+	return pathStat.Dev == rootStat.Dev, nil
+}
+
+// GetFillFile the fill file of an experiment is determined by uid, so that recover can always find it
+func GetFillFile(dir, uid string) string {
+	return fmt.Sprintf("%s/%s%s.dat", dir, FillFileName, uid)
+}
+
 func RunFillDisk(ctx context.Context, size int64, file string) error {
 	unit := "K"
 	if size/1024 >= 100 {
@@ -88,7 +110,8 @@ func RunFillDisk(ctx context.Context, size int64, file string) error {
 	return fmt.Errorf("not support \"fallocate\" and \"dd\"")
 }
 
-func GetFillKBytes(dir string, percent int, bytes string) (int64, error) {
+// GetFillKBytes at least reserveKBytes is kept free: filling to percent is capped by it, filling bytes fails instead
+func GetFillKBytes(dir string, percent int, bytes string, reserveKBytes int64) (int64, error) {
 	var fillKBytes int64
 	usage, err := disk.Usage(dir)
 	if err != nil {
@@ -109,8 +132,11 @@ func GetFillKBytes(dir string, percent int, bytes string) (int64, error) {
 	}
 
 	freeKb := int64(usage.Free / 1024)
-	if fillKBytes > freeKb {
-		return -1, fmt.Errorf("space not enough, fill: %dKB, free: %dKB", fillKBytes, freeKb)
+	if fillKBytes > freeKb-reserveKBytes {
+		if percent == 0 || reserveKBytes <= 0 {
+			return -1, fmt.Errorf("space not enough, fill: %dKB, free: %dKB, reserve: %dKB", fillKBytes, freeKb, reserveKBytes)
+		}
+		fillKBytes = freeKb - reserveKBytes
 	}
 
 	// fix bug: If it is the disk where the database file is located, the database cannot be read when it is full.
@@ -0,0 +1,162 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package disk
+
+import (
+	"context"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/cmdexec"
+	"strings"
+)
+
+// The faults of device-mapper wrap a dm device in place: the original table is moved to a new inner device, and the
+// table of the target device is replaced by a dm-delay/dm-flakey target on the inner device, so that the mounted
+// filesystem sees the fault without being remounted. Only dm devices(lvm, luks, multipath...) are supported.
+
+const (
+	DMInnerPrefix = "chaosmeta_"
+
+	DMTargetDelay  = "delay"
+	DMTargetFlakey = "flakey"
+)
+
+// GetDMName returns the dm name of dev, dev is like "/dev/mapper/vg-lv" or "/dev/dm-0", if path provided, the device
+// which path is located on is used
+func GetDMName(ctx context.Context, dev, path string) (string, error) {
+	if path != "" {
+		re, err := cmdexec.RunBashCmdWithOutput(ctx, fmt.Sprintf("df --output=source %s | tail -1", path))
+		if err != nil {
+			return "", fmt.Errorf("get device of path[%s] error: %s", path, err.Error())
+		}
+		dev = strings.TrimSpace(re)
+	}
+
+	re, err := cmdexec.RunBashCmdWithOutput(ctx, fmt.Sprintf("dmsetup info -c --noheadings -o name %s", dev))
+	if err != nil {
+		return "", fmt.Errorf("device[%s] is not a device-mapper device: %s", dev, err.Error())
+	}
+
+	return strings.TrimSpace(re), nil
+}
+
+// CheckDMTarget loads the kernel module of the dm target if necessary
+func CheckDMTarget(ctx context.Context, target string) error {
+	re, err := cmdexec.RunBashCmdWithOutput(ctx, fmt.Sprintf("modprobe dm-%s >/dev/null 2>&1; dmsetup targets | awk '{print $1}' | grep -c -w %s || true", target, target))
+	if err != nil {
+		return err
+	}
+
+	if strings.TrimSpace(re) == "0" {
+		return fmt.Errorf("dm target \"%s\" is not supported by kernel", target)
+	}
+
+	return nil
+}
+
+func ExistDM(ctx context.Context, name string) bool {
+	_, err := cmdexec.RunBashCmdWithOutput(ctx, fmt.Sprintf("dmsetup info %s", name))
+	return err == nil
+}
+
+func GetDMTable(ctx context.Context, name string) (string, error) {
+	re, err := cmdexec.RunBashCmdWithOutput(ctx, fmt.Sprintf("dmsetup table %s", name))
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(re), nil
+}
+
+// GetDMSectors returns the size of the device in 512-byte sectors
+func GetDMSectors(ctx context.Context, name string) (string, error) {
+	re, err := cmdexec.RunBashCmdWithOutput(ctx, fmt.Sprintf("blockdev --getsz /dev/mapper/%s", name))
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(re), nil
+}
+
+// IsDMWrapped checks whether the device is wrapped by another fault already
+func IsDMWrapped(ctx context.Context, name string) (bool, error) {
+	table, err := GetDMTable(ctx, name)
+	if err != nil {
+		return false, err
+	}
+
+	return strings.Contains(table, "/dev/mapper/"+DMInnerPrefix), nil
+}
+
+func GetDMInnerName(uid string) string {
+	return DMInnerPrefix + uid
+}
+
+// WrapDM moves the table of the device to the inner device, then loads the fault table generated by getTable, which
+// gets the inner device path and the sectors of the device
+func WrapDM(ctx context.Context, name, inner string, getTable func(innerDev, sectors string) string) error {
+	table, err := GetDMTable(ctx, name)
+	if err != nil {
+		return fmt.Errorf("get table of device[%s] error: %s", name, err.Error())
+	}
+
+	sectors, err := GetDMSectors(ctx, name)
+	if err != nil {
+		return fmt.Errorf("get sectors of device[%s] error: %s", name, err.Error())
+	}
+
+	if err := cmdexec.RunBashCmdWithoutOutput(ctx, fmt.Sprintf("echo '%s' | dmsetup create %s", table, inner)); err != nil {
+		return fmt.Errorf("create inner device[%s] error: %s", inner, err.Error())
+	}
+
+	if err := loadDMTable(ctx, name, getTable("/dev/mapper/"+inner, sectors)); err != nil {
+		if rErr := cmdexec.RunBashCmdWithoutOutput(ctx, fmt.Sprintf("dmsetup remove %s", inner)); rErr != nil {
+			return fmt.Errorf("load fault table error: %s, remove inner device[%s] error: %s", err.Error(), inner, rErr.Error())
+		}
+		return fmt.Errorf("load fault table error: %s", err.Error())
+	}
+
+	return nil
+}
+
+// UnwrapDM restores the original table from the inner device and removes it, it is safe to call it repeatedly
+func UnwrapDM(ctx context.Context, name, inner string) error {
+	if !ExistDM(ctx, inner) {
+		return nil
+	}
+
+	table, err := GetDMTable(ctx, inner)
+	if err != nil {
+		return fmt.Errorf("get table of inner device[%s] error: %s", inner, err.Error())
+	}
+
+	if ExistDM(ctx, name) {
+		if err := loadDMTable(ctx, name, table); err != nil {
+			return fmt.Errorf("restore table of device[%s] error: %s", name, err.Error())
+		}
+	}
+
+	if _, err := cmdexec.RunBashCmdWithOutput(ctx, fmt.Sprintf("dmsetup remove %s", inner)); err != nil {
+		return fmt.Errorf("remove inner device[%s] error: %s", inner, err.Error())
+	}
+
+	return nil
+}
+
+// loadDMTable the device is always resumed, otherwise all io of the device hangs
+func loadDMTable(ctx context.Context, name, table string) error {
+	_, err := cmdexec.RunBashCmdWithOutput(ctx, fmt.Sprintf("dmsetup suspend %s && echo '%s' | dmsetup reload %s; re=$?; dmsetup resume %s && exit $re", name, table, name, name))
+	return err
+}
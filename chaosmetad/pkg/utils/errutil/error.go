@@ -31,6 +31,16 @@ const (
 	InternalErr
 	RecoverErr
 	UnknownErr
+	PermissionErr
+	LimitErr
+)
+
+// codes of the errors reported by the fault tools, which refine InjectErr by the cause
+const (
+	ToolArgsErr        = 11
+	ToolPermissionErr  = 12
+	ToolUnsupportedErr = 13
+	ToolRuntimeErr     = 14
 )
 
 const (
@@ -0,0 +1,72 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package errutil
+
+const Component = "chaosmetad"
+
+// error categories shared by chaosmetad, the inject operator and the platform
+const (
+	CategoryInvalidArgument  = "InvalidArgument"
+	CategoryPermissionDenied = "PermissionDenied"
+	CategoryUnsupported      = "Unsupported"
+	CategoryNotFound         = "NotFound"
+	CategoryUnavailable      = "Unavailable"
+	CategoryTimeout          = "Timeout"
+	CategoryExecutionFailed  = "ExecutionFailed"
+	CategoryInternal         = "Internal"
+	CategoryUnknown          = "Unknown"
+)
+
+// ErrorInfo is the machine-readable description of an error code, so that callers can branch on the failure cause
+// instead of parsing the message
+type ErrorInfo struct {
+	Code      string `json:"code"`
+	Category  string `json:"category"`
+	Component string `json:"component"`
+	Retryable bool   `json:"retryable"`
+}
+
+var errorInfoMap = map[int]ErrorInfo{
+	BadArgsErr:    {Code: "BadArgs", Category: CategoryInvalidArgument},
+	DBErr:         {Code: "StorageError", Category: CategoryUnavailable, Retryable: true},
+	InjectErr:     {Code: "InjectFailed", Category: CategoryExecutionFailed},
+	InternalErr:   {Code: "InternalError", Category: CategoryInternal},
+	RecoverErr:    {Code: "RecoverFailed", Category: CategoryExecutionFailed, Retryable: true},
+	UnknownErr:    {Code: "Unknown", Category: CategoryUnknown},
+	PermissionErr: {Code: "PermissionDenied", Category: CategoryPermissionDenied},
+	LimitErr:      {Code: "LimitExceeded", Category: CategoryUnavailable, Retryable: true},
+	ExpectedErr:   {Code: "ExpectedError", Category: CategoryExecutionFailed},
+
+	ToolArgsErr:        {Code: "ToolBadArgs", Category: CategoryInvalidArgument},
+	ToolPermissionErr:  {Code: "ToolPermissionDenied", Category: CategoryPermissionDenied},
+	ToolUnsupportedErr: {Code: "UnsupportedKernel", Category: CategoryUnsupported},
+	ToolRuntimeErr:     {Code: "ToolRuntimeError", Category: CategoryExecutionFailed, Retryable: true},
+}
+
+// GetErrorInfo returns nil for NoErr, and the info of UnknownErr for codes not in the catalog
+func GetErrorInfo(code int) *ErrorInfo {
+	if code == NoErr {
+		return nil
+	}
+
+	info, ok := errorInfoMap[code]
+	if !ok {
+		info = errorInfoMap[UnknownErr]
+	}
+	info.Component = Component
+	return &info
+}
@@ -0,0 +1,102 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package errutil
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ToolError is printed by the fault tools as "[error:<code>]<message>", so that chaosmetad reports the cause by code
+// instead of the free text of the tool
+type ToolError struct {
+	Code int
+	Msg  string
+}
+
+func (e *ToolError) Error() string {
+	return fmt.Sprintf("[error:%d]%s", e.Code, e.Msg)
+}
+
+func ExitToolErr(code int, msg string) {
+	fmt.Println((&ToolError{Code: code, Msg: msg}).Error())
+	os.Exit(code)
+}
+
+var toolErrReg = regexp.MustCompile(`\[error:(\d+)\]`)
+
+// the output of system tools has no code, it is classified by the well-known messages
+var (
+	permissionMsgs  = []string{"operation not permitted", "permission denied"}
+	unsupportedMsgs = []string{"qdisc kind is unknown", "not supported", "can't initialize iptables table",
+		"unknown filesystem type", "no such device"}
+)
+
+// GetToolErrCode returns the code of the first tool error in msg, and 0 if the cause is unknown
+func GetToolErrCode(msg string) int {
+	if re := toolErrReg.FindStringSubmatch(msg); len(re) == 2 {
+		if code, err := strconv.Atoi(re[1]); err == nil {
+			if _, ok := errorInfoMap[code]; ok {
+				return code
+			}
+		}
+	}
+
+	lowerMsg := strings.ToLower(msg)
+	for _, m := range permissionMsgs {
+		if strings.Contains(lowerMsg, m) {
+			return ToolPermissionErr
+		}
+	}
+
+	for _, m := range unsupportedMsgs {
+		if strings.Contains(lowerMsg, m) {
+			return ToolUnsupportedErr
+		}
+	}
+
+	return NoErr
+}
+
+// NewToolErr keeps the code printed by the tool, and classifies the output without code, which is regarded as a runtime
+// error if the cause is unknown
+func NewToolErr(output string) error {
+	output = strings.TrimSpace(output)
+	if toolErrReg.MatchString(output) {
+		return errors.New(output)
+	}
+
+	code := GetToolErrCode(output)
+	if code == NoErr {
+		code = ToolRuntimeErr
+	}
+
+	return &ToolError{Code: code, Msg: output}
+}
+
+// GetInjectErrCode refines InjectErr by the tool error in msg
+func GetInjectErrCode(msg string) int {
+	if code := GetToolErrCode(msg); code != NoErr {
+		return code
+	}
+
+	return InjectErr
+}
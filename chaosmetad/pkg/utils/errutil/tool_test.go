@@ -0,0 +1,53 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package errutil
+
+import "testing"
+
+func TestGetToolErrCode(t *testing.T) {
+	cases := []struct {
+		msg  string
+		code int
+	}{
+		{msg: "inject error: [error:11]must provide 4 args", code: ToolArgsErr},
+		{msg: "[error:13]netem is not loaded\n", code: ToolUnsupportedErr},
+		{msg: "[error:50]unknown code", code: NoErr},
+		{msg: "RTNETLINK answers: Operation not permitted", code: ToolPermissionErr},
+		{msg: "Error: Specified qdisc kind is unknown.", code: ToolUnsupportedErr},
+		{msg: "dd: error writing: No space left on device", code: NoErr},
+	}
+
+	for _, c := range cases {
+		if code := GetToolErrCode(c.msg); code != c.code {
+			t.Errorf("msg[%s]: expected code %d, got %d", c.msg, c.code, code)
+		}
+	}
+}
+
+func TestNewToolErr(t *testing.T) {
+	if err := NewToolErr("[error:12]listen on 80 error\n"); err.Error() != "[error:12]listen on 80 error" {
+		t.Errorf("unexpected error: %s", err.Error())
+	}
+
+	if err := NewToolErr("unexpected error"); err.Error() != "[error:14]unexpected error" {
+		t.Errorf("unexpected error: %s", err.Error())
+	}
+
+	if code := GetInjectErrCode("inject error: exit status 1"); code != InjectErr {
+		t.Errorf("expected code %d, got %d", InjectErr, code)
+	}
+}
@@ -77,6 +77,27 @@ func getMoveFileCmd(src, dst string) string {
 	return fmt.Sprintf("mv %s %s", src, dst)
 }
 
+func getCopyFileCmd(src, dst string) string {
+	return fmt.Sprintf("cp -a %s %s", src, dst)
+}
+
+func getFileSizeCmd(file string) string {
+	return "stat -c '%s' " + file
+}
+
+func getTruncateFileCmd(path string, size int64) string {
+	return fmt.Sprintf("truncate -s %d %s", size, path)
+}
+
+// getWriteRandomBytesCmd seek and count are in bytes while dd copies by blocks of 64KB instead of byte by byte
+func getWriteRandomBytesCmd(path string, offset, count int64) string {
+	return fmt.Sprintf("dd if=/dev/urandom of=%s bs=64K seek=%d count=%d iflag=count_bytes,fullblock oflag=seek_bytes conv=notrunc status=none", path, offset, count)
+}
+
+func getAppendRandomBytesCmd(path string, count int64) string {
+	return fmt.Sprintf("head -c %d /dev/urandom >> %s", count, path)
+}
+
 func getPermCmd(file string) string {
 	return "stat -c '%a' " + file
 }
@@ -103,6 +124,68 @@ func MoveFile(ctx context.Context, cr, cId string, src, dst string) error {
 	return err
 }
 
+// CopyFile in container's namespace, the mode, owner and timestamps are kept
+func CopyFile(ctx context.Context, cr, cId string, src, dst string) error {
+	if src == "" {
+		return fmt.Errorf("\"src\" can not be empty")
+	}
+
+	if dst == "" {
+		return fmt.Errorf("\"dst\" can not be empty")
+	}
+
+	_, err := cmdexec.ExecCommonWithNS(ctx, cr, cId, getCopyFileCmd(src, dst), []string{namespace.MNT})
+	return err
+}
+
+func GetFileSize(ctx context.Context, cr, cId string, file string) (int64, error) {
+	if file == "" {
+		return -1, fmt.Errorf("\"file\" can not be empty")
+	}
+
+	sizeStr, err := cmdexec.ExecCommonWithNS(ctx, cr, cId, getFileSizeCmd(file), []string{namespace.MNT})
+	if err != nil {
+		return -1, err
+	}
+
+	size, err := strconv.ParseInt(strings.TrimSpace(sizeStr), 10, 64)
+	if err != nil {
+		return -1, fmt.Errorf("size[%s] is not a num: %s", strings.TrimSpace(sizeStr), err.Error())
+	}
+
+	return size, nil
+}
+
+// TruncateFile shrinks or extends path to size bytes in container's namespace
+func TruncateFile(ctx context.Context, cr, cId string, path string, size int64) error {
+	if path == "" {
+		return fmt.Errorf("\"path\" can not be empty")
+	}
+
+	_, err := cmdexec.ExecCommonWithNS(ctx, cr, cId, getTruncateFileCmd(path, size), []string{namespace.MNT})
+	return err
+}
+
+// WriteRandomBytes overwrites count bytes of path from offset with random data in container's namespace
+func WriteRandomBytes(ctx context.Context, cr, cId string, path string, offset, count int64) error {
+	if path == "" {
+		return fmt.Errorf("\"path\" can not be empty")
+	}
+
+	_, err := cmdexec.ExecCommonWithNS(ctx, cr, cId, getWriteRandomBytesCmd(path, offset, count), []string{namespace.MNT})
+	return err
+}
+
+// AppendRandomBytes appends count bytes of random data to path in container's namespace
+func AppendRandomBytes(ctx context.Context, cr, cId string, path string, count int64) error {
+	if path == "" {
+		return fmt.Errorf("\"path\" can not be empty")
+	}
+
+	_, err := cmdexec.ExecCommonWithNS(ctx, cr, cId, getAppendRandomBytesCmd(path, count), []string{namespace.MNT})
+	return err
+}
+
 func RemoveFile(ctx context.Context, cr, cId string, file string) error {
 	if file == "" {
 		return fmt.Errorf("\"file\" can not be empty")
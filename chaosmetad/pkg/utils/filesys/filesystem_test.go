@@ -0,0 +1,55 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package filesys
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func Test_getWriteRandomBytesCmd(t *testing.T) {
+	var (
+		path          = filepath.Join(t.TempDir(), "target")
+		size          = 200000
+		offset, count = int64(100001), int64(70000)
+	)
+	if err := os.WriteFile(path, make([]byte, size), 0644); err != nil {
+		t.Fatalf("write file error: %s", err.Error())
+	}
+
+	if out, err := exec.Command("/bin/bash", "-c", getWriteRandomBytesCmd(path, offset, count)).CombinedOutput(); err != nil {
+		t.Fatalf("write random bytes error: %s, output: %s", err.Error(), out)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read file error: %s", err.Error())
+	}
+
+	if len(data) != size {
+		t.Fatalf("file size = %d, want %d", len(data), size)
+	}
+	// the bytes out of [offset, offset+count) are kept
+	if !bytes.Equal(data[:offset], make([]byte, offset)) || !bytes.Equal(data[offset+count:], make([]byte, int64(size)-offset-count)) {
+		t.Errorf("bytes out of the range are changed")
+	}
+	if bytes.Equal(data[offset:offset+count], make([]byte, count)) {
+		t.Errorf("bytes in the range are not changed")
+	}
+}
@@ -65,6 +65,35 @@ func CalculateFillKBytes(ctx context.Context, cr, cId string, percent int, fillB
 	return fillKBytes, nil
 }
 
+// GetLimitAndUsageKBytes returns the memory limit and usage of the container's memory cgroup at cgroupPath,
+// or of the host if cr is empty
+func GetLimitAndUsageKBytes(ctx context.Context, cr, cId, cgroupPath string) (int64, int64, error) {
+	if cr == "" {
+		total, err := getHostMemTotal(ctx, cr, cId)
+		if err != nil {
+			return -1, -1, fmt.Errorf("get total mem error: %s", err.Error())
+		}
+
+		avail, err := getHostMemAvailable(ctx, cr, cId)
+		if err != nil {
+			return -1, -1, fmt.Errorf("get avail mem error: %s", err.Error())
+		}
+
+		return int64(total), int64(total - avail), nil
+	}
+
+	limit, usage, err := cgroup.GetMemCgroupLimitAndUsage(cgroupPath)
+	if err != nil {
+		return -1, -1, err
+	}
+
+	if limit == cgroup.MemUnLimit {
+		return -1, -1, fmt.Errorf("container has not set a memory limit")
+	}
+
+	return limit / 1024, usage / 1024, nil
+}
+
 func FillCache(ctx context.Context, cr, cId string, percent int, bytes string, dir string, filename string) error {
 	fillKBytes, err := CalculateFillKBytes(ctx, cr, cId, percent, bytes)
 	if err != nil {
@@ -0,0 +1,145 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package net
+
+import (
+	"context"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/cmdexec"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/namespace"
+	"hash/fnv"
+	"strings"
+)
+
+// Blackhole drops all the traffic of a network namespace except the loopback, the allowed ips and the allowed local
+// ports. The rules are in the chains owned by the experiment, so that recover only needs to remove the chains
+type Blackhole struct {
+	Uid        string
+	AllowIPs   []string
+	AllowPorts []int
+}
+
+// getChains the chain name of iptables is limited to 28 chars, so the uid is hashed
+func (b *Blackhole) getChains() (string, string) {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(b.Uid))
+	return fmt.Sprintf("CHAOSMETA_BH_IN_%08x", h.Sum32()), fmt.Sprintf("CHAOSMETA_BH_OUT_%08x", h.Sum32())
+}
+
+// getAddCmd builds the chains first and hooks them at last, so that a half built blackhole never takes effect
+func (b *Blackhole) getAddCmd(cmd, family string) string {
+	inChain, outChain := b.getChains()
+	cmds := []string{
+		fmt.Sprintf("%s -w -N %s", cmd, inChain),
+		fmt.Sprintf("%s -w -N %s", cmd, outChain),
+		fmt.Sprintf("%s -w -A %s -i lo -j RETURN", cmd, inChain),
+		fmt.Sprintf("%s -w -A %s -o lo -j RETURN", cmd, outChain),
+	}
+
+	for _, ip := range FilterIPListByFamily(b.AllowIPs, family) {
+		cmds = append(cmds,
+			fmt.Sprintf("%s -w -A %s -s %s -j RETURN", cmd, inChain, ip),
+			fmt.Sprintf("%s -w -A %s -d %s -j RETURN", cmd, outChain, ip))
+	}
+
+	for _, port := range b.AllowPorts {
+		for _, protocol := range []string{ProtocolTCP, ProtocolUDP} {
+			cmds = append(cmds,
+				fmt.Sprintf("%s -w -A %s -p %s --dport %d -j RETURN", cmd, inChain, protocol, port),
+				fmt.Sprintf("%s -w -A %s -p %s --sport %d -j RETURN", cmd, outChain, protocol, port))
+		}
+	}
+
+	cmds = append(cmds,
+		fmt.Sprintf("%s -w -A %s -j DROP", cmd, inChain),
+		fmt.Sprintf("%s -w -A %s -j DROP", cmd, outChain),
+		fmt.Sprintf("%s -w -I %s -j %s", cmd, ChainInput, inChain),
+		fmt.Sprintf("%s -w -I %s -j %s", cmd, ChainOutput, outChain))
+
+	return strings.Join(cmds, " && ")
+}
+
+// getDeleteCmd is idempotent, the missing hooks and chains are skipped
+func (b *Blackhole) getDeleteCmd(cmd string) string {
+	inChain, outChain := b.getChains()
+	var cmds []string
+	for _, unit := range [][2]string{{ChainInput, inChain}, {ChainOutput, outChain}} {
+		cmds = append(cmds,
+			fmt.Sprintf("while %s -w -C %s -j %s 2>/dev/null; do %s -w -D %s -j %s; done", cmd, unit[0], unit[1], cmd, unit[0], unit[1]),
+			fmt.Sprintf("(%s -w -F %s 2>/dev/null; %s -w -X %s 2>/dev/null; true)", cmd, unit[1], cmd, unit[1]))
+	}
+
+	return strings.Join(cmds, "; ")
+}
+
+// getBlackholeCmds returns the iptables command of each family, the ipv6 traffic is dropped too if ip6tables exists
+func getBlackholeCmds(backend string) (map[string]string, error) {
+	if backend != FirewallBackendIptablesLegacy && backend != FirewallBackendIptablesNft {
+		return nil, fmt.Errorf("blackhole only supports iptables backend, not support: %s", backend)
+	}
+
+	f := newIptablesFirewall(backend)
+	cmds := map[string]string{FamilyIPv4: f.getCmd(false)}
+	if cmdexec.SupportCmd(f.getCmd(true)) {
+		cmds[FamilyIPv6] = f.getCmd(true)
+	}
+
+	return cmds, nil
+}
+
+func AddBlackhole(ctx context.Context, cr, cId, backend string, b *Blackhole) error {
+	cmds, err := getBlackholeCmds(backend)
+	if err != nil {
+		return err
+	}
+
+	for _, family := range []string{FamilyIPv4, FamilyIPv6} {
+		cmd, ok := cmds[family]
+		if !ok {
+			continue
+		}
+
+		if _, err := cmdexec.ExecCommonWithNS(ctx, cr, cId, b.getAddCmd(cmd, family), []string{namespace.NET}); err != nil {
+			if undoErr := DeleteBlackhole(ctx, cr, cId, backend, b); undoErr != nil {
+				return fmt.Errorf("add %s rules error: %s, undo error: %s", family, err.Error(), undoErr.Error())
+			}
+			return fmt.Errorf("add %s rules error: %s", family, err.Error())
+		}
+	}
+
+	return nil
+}
+
+func DeleteBlackhole(ctx context.Context, cr, cId, backend string, b *Blackhole) error {
+	cmds, err := getBlackholeCmds(backend)
+	if err != nil {
+		return err
+	}
+
+	var errList []string
+	for family, cmd := range cmds {
+		if _, err := cmdexec.ExecCommonWithNS(ctx, cr, cId, b.getDeleteCmd(cmd), []string{namespace.NET}); err != nil {
+			errList = append(errList, fmt.Sprintf("delete %s rules error: %s", family, err.Error()))
+		}
+	}
+
+	if len(errList) > 0 {
+		return fmt.Errorf("%s", strings.Join(errList, "; "))
+	}
+
+	return nil
+}
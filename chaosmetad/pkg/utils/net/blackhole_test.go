@@ -0,0 +1,55 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package net
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBlackhole_getAddCmd(t *testing.T) {
+	b := &Blackhole{Uid: "abc", AllowIPs: []string{"10.0.0.1", "2001:db8::1"}, AllowPorts: []int{8080}}
+	in, out := b.getChains()
+	if len(in) > 28 || len(out) > 28 {
+		t.Fatalf("chain name is too long: %s, %s", in, out)
+	}
+
+	cmds := strings.Split(b.getAddCmd("iptables", FamilyIPv4), " && ")
+	want := []string{
+		"iptables -w -N " + in,
+		"iptables -w -N " + out,
+		"iptables -w -A " + in + " -i lo -j RETURN",
+		"iptables -w -A " + out + " -o lo -j RETURN",
+		"iptables -w -A " + in + " -s 10.0.0.1 -j RETURN",
+		"iptables -w -A " + out + " -d 10.0.0.1 -j RETURN",
+		"iptables -w -A " + in + " -p tcp --dport 8080 -j RETURN",
+		"iptables -w -A " + out + " -p tcp --sport 8080 -j RETURN",
+		"iptables -w -A " + in + " -p udp --dport 8080 -j RETURN",
+		"iptables -w -A " + out + " -p udp --sport 8080 -j RETURN",
+		"iptables -w -A " + in + " -j DROP",
+		"iptables -w -A " + out + " -j DROP",
+		"iptables -w -I INPUT -j " + in,
+		"iptables -w -I OUTPUT -j " + out,
+	}
+	if strings.Join(cmds, "\n") != strings.Join(want, "\n") {
+		t.Errorf("getAddCmd() = %v, want %v", cmds, want)
+	}
+
+	if strings.Contains(b.getAddCmd("ip6tables", FamilyIPv6), "10.0.0.1") {
+		t.Errorf("ipv4 address should not be in the ipv6 rules")
+	}
+}
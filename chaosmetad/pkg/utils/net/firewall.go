@@ -0,0 +1,156 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package net
+
+import (
+	"context"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/log"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/cmdexec"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/namespace"
+	"strconv"
+	"strings"
+)
+
+const (
+	FirewallBackendAuto           = "auto"
+	FirewallBackendIptablesLegacy = "iptables-legacy"
+	FirewallBackendIptablesNft    = "iptables-nft"
+	FirewallBackendNft            = "nft"
+)
+
+// FirewallRule is a drop rule, "Src" and "Dst" are comma separated ip lists of the same family
+type FirewallRule struct {
+	Chain   string
+	Src     string
+	Dst     string
+	Comment string
+	IPv6    bool
+}
+
+type Firewall interface {
+	AddDropRule(ctx context.Context, cr, cId string, rule *FirewallRule) error
+	DeleteDropRule(ctx context.Context, cr, cId string, rule *FirewallRule) error
+	ExistDropRule(ctx context.Context, cr, cId string, rule *FirewallRule) bool
+}
+
+// GetFirewall returns the executor of the backend, empty backend means the default "iptables" command
+func GetFirewall(backend string) (Firewall, error) {
+	switch backend {
+	case "":
+		return &iptablesFirewall{cmd: "iptables", cmd6: "ip6tables"}, nil
+	case FirewallBackendIptablesLegacy, FirewallBackendIptablesNft:
+		return newIptablesFirewall(backend), nil
+	case FirewallBackendNft:
+		return &nftFirewall{}, nil
+	default:
+		return nil, fmt.Errorf("not support firewall backend: %s", backend)
+	}
+}
+
+// CheckFirewallBackend checks that the commands of the backend exist, "auto" is valid if any backend is available
+func CheckFirewallBackend(ctx context.Context, cr, cId, backend string, families []string) error {
+	if backend == FirewallBackendAuto {
+		var err error
+		if backend, err = DetectFirewallBackend(ctx, cr, cId); err != nil {
+			return err
+		}
+	}
+
+	var cmds []string
+	switch backend {
+	case FirewallBackendIptablesLegacy, FirewallBackendIptablesNft:
+		f := newIptablesFirewall(backend)
+		for _, family := range families {
+			cmds = append(cmds, f.getCmd(family == FamilyIPv6))
+		}
+	case FirewallBackendNft:
+		cmds = []string{"nft"}
+	default:
+		return fmt.Errorf("not support firewall backend: %s", backend)
+	}
+
+	for _, cmd := range cmds {
+		if !cmdexec.SupportCmd(cmd) {
+			return fmt.Errorf("not support command \"%s\"", cmd)
+		}
+	}
+
+	return nil
+}
+
+// DetectFirewallBackend prefers iptables, which may be built on legacy xtables or nf_tables, and falls back to native nft.
+// If both iptables-legacy and iptables-nft are installed, the one that already holds rules is used,
+// so that the injected rules take effect together with the existing ones
+func DetectFirewallBackend(ctx context.Context, cr, cId string) (string, error) {
+	hasLegacy, hasNft := cmdexec.SupportCmd("iptables-legacy"), cmdexec.SupportCmd("iptables-nft")
+	if hasLegacy && hasNft {
+		if countIptablesRules(ctx, cr, cId, "iptables-legacy-save") > countIptablesRules(ctx, cr, cId, "iptables-nft-save") {
+			return FirewallBackendIptablesLegacy, nil
+		}
+
+		return FirewallBackendIptablesNft, nil
+	}
+
+	if cmdexec.SupportCmd("iptables") {
+		// eg: "iptables v1.8.7 (nf_tables)", "iptables v1.8.7 (legacy)", old versions print no mode and are legacy
+		version, err := cmdexec.RunBashCmdWithOutput(ctx, "iptables -V")
+		if err != nil {
+			return "", fmt.Errorf("get iptables version error: %s", err.Error())
+		}
+
+		if strings.Contains(version, "nf_tables") {
+			return FirewallBackendIptablesNft, nil
+		}
+
+		return FirewallBackendIptablesLegacy, nil
+	}
+
+	if hasLegacy {
+		return FirewallBackendIptablesLegacy, nil
+	}
+
+	if hasNft {
+		return FirewallBackendIptablesNft, nil
+	}
+
+	if cmdexec.SupportCmd("nft") {
+		return FirewallBackendNft, nil
+	}
+
+	return "", fmt.Errorf("no firewall backend found, iptables or nft is required")
+}
+
+func countIptablesRules(ctx context.Context, cr, cId, saveCmd string) int {
+	if !cmdexec.SupportCmd(saveCmd) {
+		return 0
+	}
+
+	// grep exits with 1 if no rule is found
+	re, err := cmdexec.ExecCommonWithNS(ctx, cr, cId, fmt.Sprintf("%s 2>/dev/null | grep -c '^-'", saveCmd), []string{namespace.NET})
+	if err != nil {
+		return 0
+	}
+
+	count, err := strconv.Atoi(strings.TrimSpace(re))
+	if err != nil {
+		log.GetLogger(ctx).Warnf("count rules of %s error: %s", saveCmd, err.Error())
+		return 0
+	}
+
+	return count
+}
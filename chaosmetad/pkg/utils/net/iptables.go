@@ -0,0 +1,94 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package net
+
+import (
+	"context"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/cmdexec"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/namespace"
+)
+
+const (
+	ChainInput   = "INPUT"
+	ChainOutput  = "OUTPUT"
+	ChainForward = "FORWARD"
+
+	iptablesInsert = "-I"
+	iptablesDelete = "-D"
+	iptablesCheck  = "-C"
+)
+
+type iptablesFirewall struct {
+	cmd  string
+	cmd6 string
+}
+
+// newIptablesFirewall uses the command with the backend suffix if installed, eg: "iptables-nft", otherwise "iptables" is the backend itself
+func newIptablesFirewall(backend string) *iptablesFirewall {
+	suffix := backend[len("iptables"):]
+	f := &iptablesFirewall{cmd: "iptables", cmd6: "ip6tables"}
+	if cmdexec.SupportCmd(f.cmd + suffix) {
+		f.cmd = f.cmd + suffix
+	}
+
+	if cmdexec.SupportCmd(f.cmd6 + suffix) {
+		f.cmd6 = f.cmd6 + suffix
+	}
+
+	return f
+}
+
+func (f *iptablesFirewall) getCmd(isV6 bool) string {
+	if isV6 {
+		return f.cmd6
+	}
+
+	return f.cmd
+}
+
+func (f *iptablesFirewall) getRuleCmd(op string, rule *FirewallRule) string {
+	cmd := fmt.Sprintf("%s -w %s %s", f.getCmd(rule.IPv6), op, rule.Chain)
+	if rule.Src != "" {
+		cmd += fmt.Sprintf(" -s %s", rule.Src)
+	}
+	if rule.Dst != "" {
+		cmd += fmt.Sprintf(" -d %s", rule.Dst)
+	}
+	if rule.Comment != "" {
+		cmd += fmt.Sprintf(" -m comment --comment %s", rule.Comment)
+	}
+
+	return cmd + " -j DROP"
+}
+
+// AddDropRule inserts the rule at the head of the chain to take precedence over the existing accept rules,
+// iptables expands the ip lists into one rule per pair
+func (f *iptablesFirewall) AddDropRule(ctx context.Context, cr, cId string, rule *FirewallRule) error {
+	_, err := cmdexec.ExecCommonWithNS(ctx, cr, cId, f.getRuleCmd(iptablesInsert, rule), []string{namespace.NET})
+	return err
+}
+
+func (f *iptablesFirewall) DeleteDropRule(ctx context.Context, cr, cId string, rule *FirewallRule) error {
+	_, err := cmdexec.ExecCommonWithNS(ctx, cr, cId, f.getRuleCmd(iptablesDelete, rule), []string{namespace.NET})
+	return err
+}
+
+func (f *iptablesFirewall) ExistDropRule(ctx context.Context, cr, cId string, rule *FirewallRule) bool {
+	_, err := cmdexec.ExecCommonWithNS(ctx, cr, cId, f.getRuleCmd(iptablesCheck, rule), []string{namespace.NET})
+	return err == nil
+}
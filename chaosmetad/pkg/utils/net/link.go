@@ -0,0 +1,48 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package net
+
+import (
+	"context"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/cmdexec"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/namespace"
+	"strings"
+)
+
+func GetLinkDownCmd(netInterface string) string {
+	return fmt.Sprintf("ip link set dev %s down", netInterface)
+}
+
+func GetLinkUpCmd(netInterface string) string {
+	return fmt.Sprintf("ip link set dev %s up", netInterface)
+}
+
+// ExistInterface checks the interface in the network namespace of the container, or the host if cr is empty
+func ExistInterface(ctx context.Context, cr, cId, netInterface string) (bool, error) {
+	reStr, err := cmdexec.ExecCommonWithNS(ctx, cr, cId, fmt.Sprintf("ip -o link show dev %s > /dev/null 2>&1 && echo true || echo false", netInterface), []string{namespace.NET})
+	if err != nil {
+		return false, fmt.Errorf("exec cmd error: %s", err.Error())
+	}
+
+	return strings.TrimSpace(reStr) == "true", nil
+}
+
+func SetLinkUp(ctx context.Context, cr, cId, netInterface string) error {
+	_, err := cmdexec.ExecCommonWithNS(ctx, cr, cId, GetLinkUpCmd(netInterface), []string{namespace.NET})
+	return err
+}
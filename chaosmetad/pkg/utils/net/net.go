@@ -38,16 +38,56 @@ const (
 	ProtocolTCP6 = "tcp6"
 	ProtocolUDP  = "udp"
 	ProtocolUDP6 = "udp6"
+	ProtocolICMP = "icmp"
+
+	FamilyIPv4 = "ipv4"
+	FamilyIPv6 = "ipv6"
+
+	// TcRootHandle the handle of the root qdisc added by chaosmeta
+	TcRootHandle = "1:"
 )
 
+var protocolNumMap = map[string]int{
+	ProtocolICMP: 1,
+	ProtocolTCP:  6,
+	ProtocolUDP:  17,
+}
+
+func getProtocolNum(protocol, family string) int {
+	if protocol == ProtocolICMP && family == FamilyIPv6 {
+		return 58
+	}
+
+	return protocolNumMap[protocol]
+}
+
+// GetValidProtocol checks the protocol of filter condition, ports only make sense for tcp and udp
+func GetValidProtocol(protocol string, withPort bool) (int, error) {
+	num, ok := protocolNumMap[protocol]
+	if !ok {
+		return 0, fmt.Errorf("protocol only support: %s, %s, %s", ProtocolTCP, ProtocolUDP, ProtocolICMP)
+	}
+
+	if withPort && protocol == ProtocolICMP {
+		return 0, fmt.Errorf("protocol %s does not support port filter", ProtocolICMP)
+	}
+
+	return num, nil
+}
+
 func getExistTCRootQdiscCmd(netInterface string) string {
-	return fmt.Sprintf("tc qdisc ls dev %s | grep -w '1: root' | grep -v grep | wc -l", netInterface)
+	return fmt.Sprintf("tc qdisc ls dev %s | grep -w '%s root' | grep -v grep | wc -l", netInterface, TcRootHandle)
 }
 
 func GetClearTcRuleCmd(netInterface string) string {
 	return fmt.Sprintf("tc qdisc del dev %s root", netInterface)
 }
 
+// getClearTcRootQdiscCmd the kernel refuses to delete the root qdisc if its handle is not the specified one
+func getClearTcRootQdiscCmd(netInterface string) string {
+	return fmt.Sprintf("tc qdisc del dev %s root handle %s", netInterface, TcRootHandle)
+}
+
 func getAddNetemQdiscCmd(netInterface, parent, fault string, args string) string {
 	if parent == "" {
 		parent = "root handle 1:"
@@ -92,8 +132,8 @@ func AddHTBQdisc(ctx context.Context, cr, cId, netInterface string) error {
 	return err
 }
 
-func AddFilter(ctx context.Context, cr, cId, netInterface, target, srcIpListStr, dstIpListStr, srcPortListStr, dstPortListStr string) error {
-	cmd, err := getAddFilterCmd(ctx, netInterface, target, srcIpListStr, dstIpListStr, srcPortListStr, dstPortListStr)
+func AddFilter(ctx context.Context, cr, cId, netInterface, target, srcIpListStr, dstIpListStr, srcPortListStr, dstPortListStr, protocol string) error {
+	cmd, err := getAddFilterCmd(ctx, netInterface, target, srcIpListStr, dstIpListStr, srcPortListStr, dstPortListStr, protocol)
 	if err != nil {
 		return fmt.Errorf("get filter cmd error: %s", err.Error())
 	}
@@ -117,6 +157,12 @@ func ClearTcRule(ctx context.Context, cr, cId, netInterface string) error {
 	return err
 }
 
+// ClearTcRootQdisc only deletes the root qdisc with the handle of chaosmeta, the rules of others are kept
+func ClearTcRootQdisc(ctx context.Context, cr, cId, netInterface string) error {
+	_, err := cmdexec.ExecCommonWithNS(ctx, cr, cId, getClearTcRootQdiscCmd(netInterface), []string{namespace.NET})
+	return err
+}
+
 func ExistTCRootQdisc(ctx context.Context, cr, cId string, netInterface string) (bool, error) {
 	if netInterface == "" {
 		return false, fmt.Errorf("interface is empty")
@@ -136,7 +182,7 @@ func ExistTCRootQdisc(ctx context.Context, cr, cId string, netInterface string)
 	return count != 0, nil
 }
 
-// GetValidIPList only support ipv4
+// GetValidIPList support ipv4 and ipv6, the ip and subnet of both families can be mixed in one list
 func GetValidIPList(ipStr string, ifSubNet bool) ([]string, error) {
 	ipStrList := strings.Split(ipStr, ",")
 	var re = make([]string, len(ipStrList))
@@ -162,6 +208,26 @@ func GetValidIPList(ipStr string, ifSubNet bool) ([]string, error) {
 	return re, nil
 }
 
+// GetIPFamily returns the family of a valid ip or subnet
+func GetIPFamily(ipOrSubnet string) string {
+	if strings.Contains(ipOrSubnet, ":") {
+		return FamilyIPv6
+	}
+
+	return FamilyIPv4
+}
+
+func FilterIPListByFamily(ipList []string, family string) []string {
+	var re []string
+	for _, ip := range ipList {
+		if GetIPFamily(ip) == family {
+			re = append(re, ip)
+		}
+	}
+
+	return re
+}
+
 func GetValidPortList(portStr string) ([]string, error) {
 	portStrList := strings.Split(portStr, ",")
 	var re = make([]string, len(portStrList))
@@ -210,66 +276,36 @@ func getPortMask(mask int) string {
 	return fmt.Sprintf("0x%x", maskValue)
 }
 
-func getAddFilterCmd(ctx context.Context, netInterface, target, srcIpListStr, dstIpListStr, srcPortListStr, dstPortListStr string) (tcFilterStr string, err error) {
+func getAddFilterCmd(ctx context.Context, netInterface, target, srcIpListStr, dstIpListStr, srcPortListStr, dstPortListStr, protocol string) (tcFilterStr string, err error) {
 	srcIpList, dstIpList, srcPortList, dstPortList, err := getStrList(srcIpListStr, dstIpListStr, srcPortListStr, dstPortListStr)
 	if err != nil {
 		return
 	}
 
-	var si, di, sp, dp int
-	var ruleArr []string
-	var siLen, diLen, spLen, dpLen = len(srcIpList), len(dstIpList), len(srcPortList), len(dstPortList)
-	for {
-		if si >= siLen && di >= diLen && sp >= spLen && dp >= dpLen {
-			break
+	if protocol != "" {
+		if _, err = GetValidProtocol(protocol, len(srcPortList) > 0 || len(dstPortList) > 0); err != nil {
+			return
 		}
+	}
 
-		var ifCtn = true
-		if (siLen > 0 && si == siLen) || (diLen > 0 && di == diLen) || (spLen > 0 && sp == spLen) || (dpLen > 0 && dp == dpLen) {
-			ifCtn = false
+	var ruleArr []string
+	for _, family := range []string{FamilyIPv4, FamilyIPv6} {
+		srcIps, dstIps := FilterIPListByFamily(srcIpList, family), FilterIPListByFamily(dstIpList, family)
+		// the ip condition is provided, but has no ip of this family
+		if (len(srcIpList) > 0 && len(srcIps) == 0) || (len(dstIpList) > 0 && len(dstIps) == 0) {
+			continue
 		}
 
-		if ifCtn {
-			var args string
-			if siLen > 0 {
-				args += fmt.Sprintf("match ip src %s ", srcIpList[si])
-			}
-
-			if diLen > 0 {
-				args += fmt.Sprintf("match ip dst %s ", dstIpList[di])
-			}
-
-			if spLen > 0 {
-				portArr := strings.Split(srcPortList[sp], utils.PortSplit)
-				args += fmt.Sprintf("match ip sport %s %s ", portArr[0], portArr[1])
-			}
-
-			if dpLen > 0 {
-				portArr := strings.Split(dstPortList[dp], utils.PortSplit)
-				args += fmt.Sprintf("match ip dport %s %s ", portArr[0], portArr[1])
-			}
-
-			if args != "" {
-				ruleArr = append(ruleArr, fmt.Sprintf("tc filter add dev %s parent 1: prio 1 protocol ip u32 %sflowid %s", netInterface, args, target))
-				if len(ruleArr) > MaxRuleCount {
-					err = fmt.Errorf("filter rule count is larget than %d", MaxRuleCount)
-					return
-				}
-			}
+		ruleArr = append(ruleArr, getFamilyFilterRules(netInterface, target, family, srcIps, dstIps, srcPortList, dstPortList, protocol)...)
+		if len(ruleArr) > MaxRuleCount {
+			err = fmt.Errorf("filter rule count is larget than %d", MaxRuleCount)
+			return
 		}
+	}
 
-		if si < len(srcIpList) {
-			si++
-		} else if di < len(dstIpList) {
-			di++
-			si = 0
-		} else if sp < len(srcPortList) {
-			sp++
-			si, di = 0, 0
-		} else if dp < len(dstPortList) {
-			dp++
-			si, di, sp = 0, 0, 0
-		}
+	if len(ruleArr) == 0 && (len(srcIpList) > 0 || len(dstIpList) > 0) {
+		err = fmt.Errorf("src ip and dst ip have no ip of the same family")
+		return
 	}
 
 	log.GetLogger(ctx).Debugf("filter rule count: %d", len(ruleArr))
@@ -277,6 +313,55 @@ func getAddFilterCmd(ctx context.Context, netInterface, target, srcIpListStr, ds
 	return
 }
 
+// getFamilyFilterRules returns the cartesian product of the conditions, an empty list means no restriction on it
+func getFamilyFilterRules(netInterface, target, family string, srcIpList, dstIpList, srcPortList, dstPortList []string, protocol string) []string {
+	matchKey, tcProtocol, prio := "ip", "ip", 1
+	if family == FamilyIPv6 {
+		matchKey, tcProtocol, prio = "ip6", "ipv6", 2
+	}
+
+	conditions := []string{""}
+	if protocol != "" {
+		conditions = []string{fmt.Sprintf("match %s protocol %d 0xff ", matchKey, getProtocolNum(protocol, family))}
+	}
+
+	conditions = productCondition(conditions, srcIpList, func(ip string) string { return fmt.Sprintf("match %s src %s ", matchKey, ip) })
+	conditions = productCondition(conditions, dstIpList, func(ip string) string { return fmt.Sprintf("match %s dst %s ", matchKey, ip) })
+	conditions = productCondition(conditions, srcPortList, func(port string) string {
+		portArr := strings.Split(port, utils.PortSplit)
+		return fmt.Sprintf("match %s sport %s %s ", matchKey, portArr[0], portArr[1])
+	})
+	conditions = productCondition(conditions, dstPortList, func(port string) string {
+		portArr := strings.Split(port, utils.PortSplit)
+		return fmt.Sprintf("match %s dport %s %s ", matchKey, portArr[0], portArr[1])
+	})
+
+	var ruleArr []string
+	for _, args := range conditions {
+		if args == "" {
+			continue
+		}
+		ruleArr = append(ruleArr, fmt.Sprintf("tc filter add dev %s parent 1: prio %d protocol %s u32 %sflowid %s", netInterface, prio, tcProtocol, args, target))
+	}
+
+	return ruleArr
+}
+
+func productCondition(conditions, values []string, toArgs func(string) string) []string {
+	if len(values) == 0 {
+		return conditions
+	}
+
+	var re []string
+	for _, c := range conditions {
+		for _, v := range values {
+			re = append(re, c+toArgs(v))
+		}
+	}
+
+	return re
+}
+
 func getStrList(srcIpListStr, dstIpListStr, srcPortListStr, dstPortListStr string) (srcIpList, dstIpList, srcPortList, dstPortList []string, err error) {
 	if srcIpListStr != "" {
 		srcIpList, err = GetValidIPList(srcIpListStr, true)
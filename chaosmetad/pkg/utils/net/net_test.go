@@ -0,0 +1,99 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package net
+
+import (
+	"context"
+	"testing"
+)
+
+func Test_getAddFilterCmd(t *testing.T) {
+	type args struct {
+		srcIp, dstIp, srcPort, dstPort, protocol string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "protocol only",
+			args: args{protocol: "udp"},
+			want: "tc filter add dev eth0 parent 1: prio 1 protocol ip u32 match ip protocol 17 0xff flowid 1:4 && tc filter add dev eth0 parent 1: prio 2 protocol ipv6 u32 match ip6 protocol 17 0xff flowid 1:4",
+		},
+		{
+			name: "port with protocol",
+			args: args{dstPort: "3306", protocol: "tcp"},
+			want: "tc filter add dev eth0 parent 1: prio 1 protocol ip u32 match ip protocol 6 0xff match ip dport 3306 0xffff flowid 1:4 && tc filter add dev eth0 parent 1: prio 2 protocol ipv6 u32 match ip6 protocol 6 0xff match ip6 dport 3306 0xffff flowid 1:4",
+		},
+		{
+			name: "port without protocol",
+			args: args{dstPort: "3306"},
+			want: "tc filter add dev eth0 parent 1: prio 1 protocol ip u32 match ip dport 3306 0xffff flowid 1:4 && tc filter add dev eth0 parent 1: prio 2 protocol ipv6 u32 match ip6 dport 3306 0xffff flowid 1:4",
+		},
+		{
+			name: "ipv4 only",
+			args: args{dstIp: "10.0.0.0/8", dstPort: "80,443"},
+			want: "tc filter add dev eth0 parent 1: prio 1 protocol ip u32 match ip dst 10.0.0.0/8 match ip dport 80 0xffff flowid 1:4 && tc filter add dev eth0 parent 1: prio 1 protocol ip u32 match ip dst 10.0.0.0/8 match ip dport 443 0xffff flowid 1:4",
+		},
+		{
+			name: "ipv6 icmp",
+			args: args{dstIp: "2001:db8::/64", protocol: "icmp"},
+			want: "tc filter add dev eth0 parent 1: prio 2 protocol ipv6 u32 match ip6 protocol 58 0xff match ip6 dst 2001:db8::/64 flowid 1:4",
+		},
+		{
+			name: "mixed family",
+			args: args{srcIp: "10.0.0.1", dstIp: "10.0.0.2,fd00::2"},
+			want: "tc filter add dev eth0 parent 1: prio 1 protocol ip u32 match ip src 10.0.0.1 match ip dst 10.0.0.2 flowid 1:4",
+		},
+		{
+			name:    "no same family",
+			args:    args{srcIp: "10.0.0.1", dstIp: "fd00::2"},
+			wantErr: true,
+		},
+		{
+			name:    "icmp with port",
+			args:    args{dstPort: "3306", protocol: "icmp"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown protocol",
+			args:    args{protocol: "sctp"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := getAddFilterCmd(context.Background(), "eth0", "1:4", tt.args.srcIp, tt.args.dstIp, tt.args.srcPort, tt.args.dstPort, tt.args.protocol)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("getAddFilterCmd() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("getAddFilterCmd() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_getClearTcRootQdiscCmd(t *testing.T) {
+	want := "tc qdisc del dev eth0 root handle 1:"
+	if got := getClearTcRootQdiscCmd("eth0"); got != want {
+		t.Errorf("getClearTcRootQdiscCmd() = %v, want %v", got, want)
+	}
+}
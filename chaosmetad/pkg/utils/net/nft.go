@@ -0,0 +1,142 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package net
+
+import (
+	"context"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/cmdexec"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/namespace"
+	"hash/fnv"
+	"regexp"
+	"strings"
+)
+
+const (
+	// nftTable holds the rules of all experiments, an inet table matches both ipv4 and ipv6
+	nftTable = "inet chaosmeta"
+	// nftPriority runs the chains before the default filter chains, a drop verdict is final in any base chain
+	nftPriority = -10
+)
+
+var (
+	nftChainMap = map[string]string{
+		ChainInput:   "input",
+		ChainOutput:  "output",
+		ChainForward: "forward",
+	}
+	nftHandleRegexp = regexp.MustCompile(`# handle (\d+)`)
+)
+
+type nftFirewall struct{}
+
+func getNftChain(chain string) (string, error) {
+	nftChain, ok := nftChainMap[chain]
+	if !ok {
+		return "", fmt.Errorf("not support chain: %s", chain)
+	}
+
+	return nftChain, nil
+}
+
+// getNftComment makes the comment unique for each rule, nft deletes a rule by its handle which is found by the comment
+func getNftComment(rule *FirewallRule) string {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(fmt.Sprintf("%s|%s|%s", rule.Chain, rule.Src, rule.Dst)))
+	return fmt.Sprintf("%s_%08x", rule.Comment, h.Sum32())
+}
+
+func getNftAddRuleCmd(nftChain string, rule *FirewallRule) string {
+	ipType := "ip"
+	if rule.IPv6 {
+		ipType = "ip6"
+	}
+
+	// the chain creation is idempotent, the quotes prevent the shell from expanding the braces
+	cmd := fmt.Sprintf("nft add table %s && nft 'add chain %s %s { type filter hook %s priority %d ; }' && nft 'add rule %s %s",
+		nftTable, nftTable, nftChain, nftChain, nftPriority, nftTable, nftChain)
+	if rule.Src != "" {
+		cmd += fmt.Sprintf(" %s saddr { %s }", ipType, rule.Src)
+	}
+	if rule.Dst != "" {
+		cmd += fmt.Sprintf(" %s daddr { %s }", ipType, rule.Dst)
+	}
+
+	return cmd + fmt.Sprintf(" counter drop comment %s'", getNftComment(rule))
+}
+
+// getNftRuleHandle returns the handle of the rule from the output of "nft -a list chain", 0 means not found
+func getNftRuleHandle(chainInfo string, rule *FirewallRule) int {
+	comment := fmt.Sprintf("comment \"%s\"", getNftComment(rule))
+	for _, line := range strings.Split(chainInfo, "\n") {
+		if !strings.Contains(line, comment) {
+			continue
+		}
+
+		matches := nftHandleRegexp.FindStringSubmatch(line)
+		if len(matches) == 2 {
+			var handle int
+			_, _ = fmt.Sscanf(matches[1], "%d", &handle)
+			return handle
+		}
+	}
+
+	return 0
+}
+
+func (f *nftFirewall) getRuleHandle(ctx context.Context, cr, cId string, rule *FirewallRule) (string, int, error) {
+	nftChain, err := getNftChain(rule.Chain)
+	if err != nil {
+		return "", 0, err
+	}
+
+	chainInfo, err := cmdexec.ExecCommonWithNS(ctx, cr, cId, fmt.Sprintf("nft -a list chain %s %s", nftTable, nftChain), []string{namespace.NET})
+	if err != nil {
+		return nftChain, 0, fmt.Errorf("list chain %s error: %s", nftChain, err.Error())
+	}
+
+	return nftChain, getNftRuleHandle(chainInfo, rule), nil
+}
+
+func (f *nftFirewall) AddDropRule(ctx context.Context, cr, cId string, rule *FirewallRule) error {
+	nftChain, err := getNftChain(rule.Chain)
+	if err != nil {
+		return err
+	}
+
+	_, err = cmdexec.ExecCommonWithNS(ctx, cr, cId, getNftAddRuleCmd(nftChain, rule), []string{namespace.NET})
+	return err
+}
+
+func (f *nftFirewall) DeleteDropRule(ctx context.Context, cr, cId string, rule *FirewallRule) error {
+	nftChain, handle, err := f.getRuleHandle(ctx, cr, cId, rule)
+	if err != nil {
+		return err
+	}
+
+	if handle == 0 {
+		return fmt.Errorf("rule with comment %s not found in chain %s", getNftComment(rule), nftChain)
+	}
+
+	_, err = cmdexec.ExecCommonWithNS(ctx, cr, cId, fmt.Sprintf("nft delete rule %s %s handle %d", nftTable, nftChain, handle), []string{namespace.NET})
+	return err
+}
+
+func (f *nftFirewall) ExistDropRule(ctx context.Context, cr, cId string, rule *FirewallRule) bool {
+	_, handle, err := f.getRuleHandle(ctx, cr, cId, rule)
+	return err == nil && handle != 0
+}
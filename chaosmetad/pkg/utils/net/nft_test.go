@@ -0,0 +1,55 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package net
+
+import "testing"
+
+func Test_getNftAddRuleCmd(t *testing.T) {
+	rule := &FirewallRule{Chain: ChainInput, Src: "10.0.0.1,10.0.1.0/24", Dst: "10.0.2.1", Comment: "chaosmeta_partition_abc"}
+	want := "nft add table inet chaosmeta && nft 'add chain inet chaosmeta input { type filter hook input priority -10 ; }' && " +
+		"nft 'add rule inet chaosmeta input ip saddr { 10.0.0.1,10.0.1.0/24 } ip daddr { 10.0.2.1 } counter drop comment " + getNftComment(rule) + "'"
+	if got := getNftAddRuleCmd("input", rule); got != want {
+		t.Errorf("getNftAddRuleCmd() = %v, want %v", got, want)
+	}
+
+	rule6 := &FirewallRule{Chain: ChainOutput, Dst: "2001:db8::1", Comment: "c", IPv6: true}
+	want6 := "nft add table inet chaosmeta && nft 'add chain inet chaosmeta output { type filter hook output priority -10 ; }' && " +
+		"nft 'add rule inet chaosmeta output ip6 daddr { 2001:db8::1 } counter drop comment " + getNftComment(rule6) + "'"
+	if got := getNftAddRuleCmd("output", rule6); got != want6 {
+		t.Errorf("getNftAddRuleCmd() = %v, want %v", got, want6)
+	}
+}
+
+func Test_getNftRuleHandle(t *testing.T) {
+	a := &FirewallRule{Chain: ChainInput, Src: "10.0.0.1", Dst: "10.0.0.2", Comment: "chaosmeta_partition_abc"}
+	b := &FirewallRule{Chain: ChainInput, Src: "10.0.0.2", Dst: "10.0.0.1", Comment: "chaosmeta_partition_abc"}
+	if getNftComment(a) == getNftComment(b) {
+		t.Fatalf("comments of rules in different directions should be different")
+	}
+
+	chainInfo := "table inet chaosmeta {\n" +
+		"\tchain input { # handle 1\n" +
+		"\t\ttype filter hook input priority -10; policy accept;\n" +
+		"\t\tip saddr 10.0.0.1 ip daddr 10.0.0.2 counter packets 0 bytes 0 drop comment \"" + getNftComment(a) + "\" # handle 7\n" +
+		"\t}\n}"
+	if got := getNftRuleHandle(chainInfo, a); got != 7 {
+		t.Errorf("getNftRuleHandle() = %v, want 7", got)
+	}
+	if got := getNftRuleHandle(chainInfo, b); got != 0 {
+		t.Errorf("getNftRuleHandle() = %v, want 0", got)
+	}
+}
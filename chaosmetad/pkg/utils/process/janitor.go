@@ -0,0 +1,125 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package process
+
+import (
+	"context"
+	"fmt"
+	"github.com/shirou/gopsutil/process"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/log"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/cmdexec"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/namespace"
+	"path/filepath"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+const (
+	// JanitorInterval is the interval of checking leaked execns processes in the daemon
+	JanitorInterval = time.Minute * 5
+
+	statusStopped = "T"
+)
+
+// StartExecnsJanitor reaps the leaked execns processes periodically until ctx is done
+func StartExecnsJanitor(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if count := ReapLeakedExecns(ctx); count > 0 {
+			log.GetLogger(ctx).Infof("reap %d leaked %s processes", count, namespace.ExecnsKey)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ReapLeakedExecns kills the execns processes left by the failed injections: the ones whose target process is gone,
+// and the ones still stopped for waiting for SIGCONT after ExecWaitTimeout, which means their starter exited before
+// adding them to the cgroup of the container
+func ReapLeakedExecns(ctx context.Context) int {
+	logger := log.GetLogger(ctx)
+	procs, err := process.Processes()
+	if err != nil {
+		logger.Warnf("list processes error: %s", err.Error())
+		return 0
+	}
+
+	var count int
+	for _, p := range procs {
+		leaked, reason := isLeakedExecns(p)
+		if !leaked {
+			continue
+		}
+
+		logger.Warnf("kill leaked %s process[%d]: %s", namespace.ExecnsKey, p.Pid, reason)
+		if err := syscall.Kill(int(p.Pid), syscall.SIGKILL); err != nil {
+			logger.Warnf("kill process[%d] error: %s", p.Pid, err.Error())
+			continue
+		}
+		count++
+	}
+
+	return count
+}
+
+func isLeakedExecns(p *process.Process) (bool, string) {
+	args, err := p.CmdlineSlice()
+	if err != nil || len(args) == 0 || filepath.Base(args[0]) != namespace.ExecnsKey {
+		return false, ""
+	}
+
+	if targetPid := getExecnsTarget(args); targetPid > 0 {
+		if _, err := syscall.Getpgid(targetPid); err == syscall.ESRCH {
+			return true, fmt.Sprintf("target process[%d] not exists", targetPid)
+		}
+	}
+
+	status, err := p.Status()
+	if err != nil || status != statusStopped {
+		return false, ""
+	}
+
+	createMs, err := p.CreateTime()
+	if err != nil {
+		return false, ""
+	}
+
+	if age := time.Since(time.UnixMilli(createMs)); age > cmdexec.ExecWaitTimeout {
+		return true, fmt.Sprintf("stopped for %s", age.Truncate(time.Second))
+	}
+
+	return false, ""
+}
+
+// getExecnsTarget returns the value of option "-t", which is the pid of the container init process
+func getExecnsTarget(args []string) int {
+	for i := 1; i < len(args)-1; i++ {
+		if args[i] == "-t" {
+			pid, _ := strconv.Atoi(args[i+1])
+			return pid
+		}
+	}
+
+	return 0
+}
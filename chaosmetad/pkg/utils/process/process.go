@@ -18,14 +18,17 @@ package process
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/shirou/gopsutil/process"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/crclient"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/crclient/base"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/log"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/cmdexec"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/namespace"
 	"os"
+	"regexp"
 	"strconv"
 	"strings"
 	"syscall"
@@ -39,6 +42,8 @@ const (
 	SIGCONT = 18
 )
 
+const processListCmd = "ps -eo pid=,args="
+
 func getProcessPidCmd(pid int) string {
 	return fmt.Sprintf("ps -eo pid | grep -w %d", pid)
 }
@@ -129,6 +134,57 @@ func GetProcessByKey(ctx context.Context, cr, cId string, key string) ([]int, er
 	return pidList, nil
 }
 
+// ErrNoProcessMatch is returned by GetProcessByRegex when no process matches the regex
+var ErrNoProcessMatch = errors.New("no process matches regex")
+
+// compileCmdRegex anchors the regex, so that it must match the whole command line instead of a part of it
+func compileCmdRegex(regex string) (*regexp.Regexp, error) {
+	return regexp.Compile(fmt.Sprintf("^(?:%s)$", regex))
+}
+
+// GetProcessByRegex in container's pid namespace, the regex matches the whole command line
+func GetProcessByRegex(ctx context.Context, cr, cId string, regex string) ([]base.SimpleProcess, error) {
+	if regex == "" {
+		return nil, fmt.Errorf("\"regex\" can not be empty")
+	}
+
+	reg, err := compileCmdRegex(regex)
+	if err != nil {
+		return nil, fmt.Errorf("\"regex\" is invalid: %s", err.Error())
+	}
+
+	reStr, err := cmdexec.ExecCommonWithNS(ctx, cr, cId, processListCmd, []string{namespace.PID, namespace.MNT})
+	if err != nil {
+		return nil, fmt.Errorf("exec cmd error: %s", err.Error())
+	}
+
+	var proList []base.SimpleProcess
+	for _, line := range strings.Split(reStr, "\n") {
+		fields := strings.SplitN(strings.TrimSpace(line), " ", 2)
+		if len(fields) < 2 {
+			continue
+		}
+
+		cmd := strings.TrimSpace(fields[1])
+		if cmd == processListCmd || strings.Contains(cmd, utils.RootName+" inject") ||
+			strings.Contains(cmd, utils.RootName+" recover") || strings.Contains(cmd, "chaosmeta_execns ") || !reg.MatchString(cmd) {
+			continue
+		}
+
+		pid, err := strconv.Atoi(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("%s is not a valid pid: %s", fields[0], err.Error())
+		}
+		proList = append(proList, base.SimpleProcess{Pid: pid, Cmd: cmd})
+	}
+
+	if len(proList) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrNoProcessMatch, regex)
+	}
+
+	return proList, nil
+}
+
 // SignalProcessByPid in container's pid namespace
 func SignalProcessByPid(ctx context.Context, cr, cId string, pid, signal int) error {
 	if pid < 0 {
@@ -0,0 +1,44 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package process
+
+import (
+	"testing"
+)
+
+func Test_compileCmdRegex(t *testing.T) {
+	tests := []struct {
+		regex string
+		cmd   string
+		want  bool
+	}{
+		{regex: "nginx", cmd: "nginx", want: true},
+		{regex: "nginx", cmd: "nginx: worker process", want: false},
+		{regex: "nginx.*", cmd: "nginx: worker process", want: true},
+		{regex: "java|python", cmd: "python app.py", want: false},
+		{regex: "java|python .*", cmd: "java -jar app.jar", want: false},
+		{regex: "(java|python) .*", cmd: "java -jar app.jar", want: true},
+	}
+	for _, tt := range tests {
+		reg, err := compileCmdRegex(tt.regex)
+		if err != nil {
+			t.Fatalf("compileCmdRegex(%q) error = %v", tt.regex, err)
+		}
+		if got := reg.MatchString(tt.cmd); got != tt.want {
+			t.Errorf("compileCmdRegex(%q) match %q = %v, want %v", tt.regex, tt.cmd, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,238 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tool
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/config"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/log"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// ChecksumFile is in the format of sha256sum, the file names are relative to the tool dir, eg: amd64/chaosmeta_cpuburn
+	ChecksumFile = "SHA256SUMS"
+
+	fetchTimeout = time.Minute * 5
+)
+
+var (
+	// verified caches the checksum, mtime and size of the verified tools, the tool is verified again if changed
+	verified = map[string]string{}
+	mutex    sync.Mutex
+)
+
+// Prepare makes a tool ready to execute: the missing tool is fetched from the registry of the config, then the tool
+// is verified by the checksum file in the tool dir. The checksum file is shipped with the bundle, a tool without its
+// checksum is never executed
+func Prepare(ctx context.Context, tool string) error {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	path := utils.GetToolPath(tool)
+	if _, err := os.Stat(path); err != nil {
+		if !os.IsNotExist(err) {
+			return fmt.Errorf("check tool[%s] error: %s", path, err.Error())
+		}
+
+		c, err := config.Get()
+		if err != nil {
+			return fmt.Errorf("get config error: %s", err.Error())
+		}
+
+		if c.ToolRegistry == "" {
+			return fmt.Errorf("tool[%s] not found and no tool registry is configured", tool)
+		}
+
+		if path, err = fetch(ctx, c.ToolRegistry, tool); err != nil {
+			return fmt.Errorf("fetch tool[%s] error: %s", tool, err.Error())
+		}
+	}
+
+	return verify(ctx, path)
+}
+
+// Exist reports whether a tool is in the tool dir
+func Exist(tool string) bool {
+	_, err := os.Stat(utils.GetToolPath(tool))
+	return err == nil
+}
+
+func verify(ctx context.Context, path string) error {
+	sums, err := readChecksums(filepath.Join(utils.GetToolDir(), ChecksumFile))
+	if err != nil {
+		return err
+	}
+
+	if sums == nil {
+		return fmt.Errorf("no %s in tool dir to verify tool[%s]", ChecksumFile, path)
+	}
+
+	name, _ := filepath.Rel(utils.GetToolDir(), path)
+	expected, ok := sums[name]
+	if !ok {
+		return fmt.Errorf("no checksum of tool[%s] in %s", name, ChecksumFile)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("stat %s error: %s", path, err.Error())
+	}
+
+	cacheKey := fmt.Sprintf("%s %d %d", expected, info.ModTime().UnixNano(), info.Size())
+	if verified[path] == cacheKey {
+		return nil
+	}
+
+	actual, err := getChecksum(path)
+	if err != nil {
+		return err
+	}
+
+	if actual != expected {
+		return fmt.Errorf("checksum of tool[%s] is %s, expected %s", name, actual, expected)
+	}
+
+	verified[path] = cacheKey
+	return nil
+}
+
+// fetch downloads "[registry]/[arch]/[tool]" into "tools/[arch]/". The checksum is pinned by the local checksum file,
+// the one in the registry is not trusted because it can be replaced together with the tool
+func fetch(ctx context.Context, registry, tool string) (string, error) {
+	if !strings.HasPrefix(registry, "https://") {
+		return "", fmt.Errorf("tool registry[%s] must be an https address", registry)
+	}
+
+	registry = strings.TrimSuffix(registry, "/")
+	name := fmt.Sprintf("%s/%s", runtime.GOARCH, tool)
+	log.GetLogger(ctx).Infof("fetch tool %s from %s", name, registry)
+
+	sums, err := readChecksums(filepath.Join(utils.GetToolDir(), ChecksumFile))
+	if err != nil {
+		return "", err
+	}
+
+	expected, ok := sums[name]
+	if !ok {
+		return "", fmt.Errorf("no checksum of tool[%s] in %s, it can not be fetched", name, ChecksumFile)
+	}
+
+	data, err := Download(fmt.Sprintf("%s/%s", registry, name))
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(data)
+	if actual := hex.EncodeToString(sum[:]); actual != expected {
+		return "", fmt.Errorf("checksum of downloaded tool[%s] is %s, expected %s", name, actual, expected)
+	}
+
+	path := filepath.Join(utils.GetToolDir(), name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", fmt.Errorf("create dir of %s error: %s", path, err.Error())
+	}
+
+	// write to a temp file first, so that a broken tool is never executed
+	tmpPath := fmt.Sprintf("%s.tmp", path)
+	if err := os.WriteFile(tmpPath, data, 0755); err != nil {
+		return "", fmt.Errorf("write %s error: %s", tmpPath, err.Error())
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		return "", fmt.Errorf("rename %s error: %s", tmpPath, err.Error())
+	}
+
+	return path, nil
+}
+
+// Download gets the content of url, the release of chaosmetad is downloaded by it too
+func Download(url string) ([]byte, error) {
+	client := &http.Client{Timeout: fetchTimeout}
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("get %s error: %s", url, err.Error())
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("get %s error: status %d", url, resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read %s error: %s", url, err.Error())
+	}
+
+	return data, nil
+}
+
+// readChecksums returns nil if the checksum file not exists
+func readChecksums(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read %s error: %s", path, err.Error())
+	}
+
+	return ParseChecksums(string(data)), nil
+}
+
+// ParseChecksums parses the content in the format of sha256sum into a map from file name to checksum
+func ParseChecksums(content string) map[string]string {
+	sums := map[string]string{}
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+		// the file name is marked with "*" in binary mode of sha256sum
+		sums[strings.TrimPrefix(strings.TrimPrefix(fields[1], "*"), "./")] = strings.ToLower(fields[0])
+	}
+
+	return sums
+}
+
+func getChecksum(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("open %s error: %s", path, err.Error())
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", fmt.Errorf("read %s error: %s", path, err.Error())
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,71 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tool
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseChecksums(t *testing.T) {
+	content := "0a1B  chaosmeta_cpuburn\n" +
+		"2c3d *arm64/chaosmeta_cpuburn\n" +
+		"4e5f  ./tools.jar\n" +
+		"broken line with more fields\n"
+	want := map[string]string{
+		"chaosmeta_cpuburn":       "0a1b",
+		"arm64/chaosmeta_cpuburn": "2c3d",
+		"tools.jar":               "4e5f",
+	}
+	if got := ParseChecksums(content); !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseChecksums() = %v, want %v", got, want)
+	}
+}
+
+func Test_getChecksum(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tool")
+	if err := os.WriteFile(path, []byte("chaosmeta"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := getChecksum(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// echo -n chaosmeta | sha256sum
+	want := "18f6ef1b3f612e0b5276b60928a010a65bc7a153acec0072114d7cf269f7bcb4"
+	if got != want {
+		t.Errorf("getChecksum() = %s, want %s", got, want)
+	}
+}
+
+func Test_verifyWithoutChecksums(t *testing.T) {
+	// there is no checksum file in the tool dir of the test binary
+	if err := verify(context.Background(), filepath.Join(t.TempDir(), "tool")); err == nil {
+		t.Errorf("verify() should fail without the checksum file")
+	}
+}
+
+func Test_fetchHttp(t *testing.T) {
+	if _, err := fetch(context.Background(), "http://oss.example.com/chaosmeta", "chaosmeta_cpuburn"); err == nil {
+		t.Errorf("fetch() should fail with an http registry")
+	}
+}
@@ -0,0 +1,124 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package user
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const (
+	procSelfStatus = "/proc/self/status"
+	capEffKey      = "CapEff:"
+)
+
+// capabilities used by the faults, see capability.h for the bits
+const (
+	CapDacOverride = "DAC_OVERRIDE"
+	CapKill        = "KILL"
+	CapNetAdmin    = "NET_ADMIN"
+	CapSysPtrace   = "SYS_PTRACE"
+	CapSysAdmin    = "SYS_ADMIN"
+	CapSysBoot     = "SYS_BOOT"
+	CapSysNice     = "SYS_NICE"
+	CapSysResource = "SYS_RESOURCE"
+	CapSysTime     = "SYS_TIME"
+)
+
+var capBits = map[string]uint{
+	CapDacOverride: 1,
+	CapKill:        5,
+	CapNetAdmin:    12,
+	CapSysPtrace:   19,
+	CapSysAdmin:    21,
+	CapSysBoot:     22,
+	CapSysNice:     23,
+	CapSysResource: 24,
+	CapSysTime:     25,
+}
+
+// GetEffectiveCaps returns the bit set of the effective capabilities of the current process
+func GetEffectiveCaps() (uint64, error) {
+	data, err := os.ReadFile(procSelfStatus)
+	if err != nil {
+		return 0, fmt.Errorf("read %s error: %s", procSelfStatus, err.Error())
+	}
+
+	return parseEffectiveCaps(string(data))
+}
+
+func parseEffectiveCaps(status string) (uint64, error) {
+	for _, line := range strings.Split(status, "\n") {
+		if !strings.HasPrefix(line, capEffKey) {
+			continue
+		}
+
+		caps, err := strconv.ParseUint(strings.TrimSpace(strings.TrimPrefix(line, capEffKey)), 16, 64)
+		if err != nil {
+			return 0, fmt.Errorf("%s is not a hex value: %s", capEffKey, err.Error())
+		}
+
+		return caps, nil
+	}
+
+	return 0, fmt.Errorf("%s not found", capEffKey)
+}
+
+// GetEffectiveCapNames returns the names of the known capabilities that the current process has
+func GetEffectiveCapNames() ([]string, error) {
+	caps, err := GetEffectiveCaps()
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, name := range []string{CapDacOverride, CapKill, CapNetAdmin, CapSysPtrace, CapSysAdmin, CapSysNice, CapSysResource, CapSysTime} {
+		if caps&(1<<capBits[name]) != 0 {
+			names = append(names, name)
+		}
+	}
+
+	return names, nil
+}
+
+// GetMissingCaps returns the capabilities in required that the current process does not have
+func GetMissingCaps(required []string) ([]string, error) {
+	if len(required) == 0 {
+		return nil, nil
+	}
+
+	caps, err := GetEffectiveCaps()
+	if err != nil {
+		return nil, err
+	}
+
+	return getMissingCaps(caps, required), nil
+}
+
+func getMissingCaps(caps uint64, required []string) []string {
+	var missing []string
+	for _, name := range required {
+		bit, ok := capBits[name]
+		if !ok || caps&(1<<bit) == 0 {
+			missing = append(missing, name)
+		}
+	}
+
+	return missing
+}
@@ -0,0 +1,79 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package user
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_parseEffectiveCaps(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  string
+		want    uint64
+		wantErr bool
+	}{
+		{
+			name:   "root",
+			status: "Name:\tchaosmetad\nCapInh:\t0000000000000000\nCapEff:\t000001ffffffffff\nCapBnd:\t000001ffffffffff\n",
+			want:   0x1ffffffffff,
+		},
+		{
+			name:   "net admin only",
+			status: "CapPrm:\t0000000000001000\nCapEff:\t0000000000001000\n",
+			want:   1 << 12,
+		},
+		{
+			name:    "no CapEff",
+			status:  "Name:\tchaosmetad\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseEffectiveCaps(tt.status)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseEffectiveCaps() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("parseEffectiveCaps() got = %x, want %x", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_getMissingCaps(t *testing.T) {
+	tests := []struct {
+		name     string
+		caps     uint64
+		required []string
+		want     []string
+	}{
+		{name: "all present", caps: 1<<12 | 1<<21, required: []string{CapNetAdmin, CapSysAdmin}, want: nil},
+		{name: "partly missing", caps: 1 << 12, required: []string{CapNetAdmin, CapSysAdmin, CapSysPtrace}, want: []string{CapSysAdmin, CapSysPtrace}},
+		{name: "unknown cap", caps: ^uint64(0), required: []string{"NOT_EXIST"}, want: []string{"NOT_EXIST"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := getMissingCaps(tt.caps, tt.required); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("getMissingCaps() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package handler
+
+import (
+	"context"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/doctor"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/errutil"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/web/model"
+	"net/http"
+)
+
+// CapabilityGet reports the health of chaosmetad and the usable faults for preflight, the faults can be filtered by
+// "target" and "fault", and the container is checked if "container_runtime" and "container_id" are provided
+func CapabilityGet(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	w.WriteHeader(http.StatusOK)
+	ctx := utils.GetCtxWithTraceId(r.Context(), utils.TraceId)
+	query := r.URL.Query()
+	WriteResponse(ctx, w, GetCapability(ctx, &model.CapabilityRequest{
+		Target:           query.Get("target"),
+		Fault:            query.Get("fault"),
+		ContainerRuntime: query.Get("container_runtime"),
+		ContainerId:      query.Get("container_id"),
+	}))
+}
+
+func GetCapability(ctx context.Context, req *model.CapabilityRequest) *model.DoctorResponse {
+	report := doctor.GetReport(ctx)
+	report.FilterFaults(req.Target, req.Fault)
+	report.CheckContainer(ctx, req.ContainerRuntime, req.ContainerId)
+
+	res := &model.DoctorResponse{Code: errutil.NoErr, Message: "success", Data: report}
+	if !report.Health.Healthy {
+		res.Code, res.Message = errutil.InternalErr, "chaosmetad is unhealthy"
+		res.Error = errutil.GetErrorInfo(res.Code)
+	}
+
+	return res
+}
@@ -0,0 +1,71 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/config"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/errutil"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/web/model"
+	"net/http"
+)
+
+func ConfigGet(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	w.WriteHeader(http.StatusOK)
+	ctx := context.Background()
+
+	c, err := config.Get()
+	if err != nil {
+		WriteResponse(ctx, w, &model.ConfigResponse{Code: errutil.InternalErr, Message: fmt.Sprintf("get config error: %s", err.Error()), Error: errutil.GetErrorInfo(errutil.InternalErr)})
+		return
+	}
+
+	WriteResponse(ctx, w, &model.ConfigResponse{Code: errutil.NoErr, Message: "success", Data: c})
+}
+
+// ConfigPost replaces the runtime config, the version of the request must be larger than the current one
+func ConfigPost(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	w.WriteHeader(http.StatusOK)
+
+	var (
+		ctx       = context.Background()
+		configReq = &model.ConfigRequest{}
+		configRes = &model.ConfigResponse{Code: errutil.NoErr, Message: "success"}
+	)
+
+	if err := json.NewDecoder(r.Body).Decode(configReq); err != nil {
+		configRes.Code, configRes.Message = errutil.BadArgsErr, fmt.Sprintf("req body format error: %s", err.Error())
+	} else {
+		ctx = utils.GetCtxWithTraceId(ctx, configReq.TraceId)
+		if err := config.Update(ctx, &configReq.RuntimeConfig); err != nil {
+			configRes.Code, configRes.Message = errutil.BadArgsErr, fmt.Sprintf("update config error: %s", err.Error())
+		}
+	}
+
+	// always return the config in effect, so that the caller knows the current version
+	if c, err := config.Get(); err == nil {
+		configRes.Data = c
+	}
+	configRes.Error = errutil.GetErrorInfo(configRes.Code)
+	configRes.TraceId = utils.GetTraceId(ctx)
+	WriteResponse(ctx, w, configRes)
+}
@@ -0,0 +1,70 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/errutil"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/web/model"
+	"net/http"
+)
+
+func ExperimentPausePost(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	w.WriteHeader(http.StatusOK)
+
+	var (
+		ctx      = context.Background()
+		pauseReq = &model.PauseRequest{}
+		pauseRes *model.CommonResponse
+	)
+
+	if err := json.NewDecoder(r.Body).Decode(pauseReq); err != nil {
+		pauseRes = getCommonResponse(ctx, errutil.BadArgsErr, fmt.Sprintf("req body format error: %s", err.Error()))
+	} else {
+		ctx = utils.GetCtxWithTraceId(ctx, pauseReq.TraceId)
+		code, msg := injector.ProcessPause(ctx, pauseReq.Uid)
+		pauseRes = getCommonResponse(ctx, code, msg)
+	}
+
+	WriteResponse(ctx, w, pauseRes)
+}
+
+func ExperimentResumePost(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	w.WriteHeader(http.StatusOK)
+
+	var (
+		ctx       = context.Background()
+		resumeReq = &model.ResumeRequest{}
+		resumeRes *model.CommonResponse
+	)
+
+	if err := json.NewDecoder(r.Body).Decode(resumeReq); err != nil {
+		resumeRes = getCommonResponse(ctx, errutil.BadArgsErr, fmt.Sprintf("req body format error: %s", err.Error()))
+	} else {
+		ctx = utils.GetCtxWithTraceId(ctx, resumeReq.TraceId)
+		code, msg := injector.ProcessResume(ctx, resumeReq.Uid)
+		resumeRes = getCommonResponse(ctx, code, msg)
+	}
+
+	WriteResponse(ctx, w, resumeRes)
+}
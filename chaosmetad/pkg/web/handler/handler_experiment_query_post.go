@@ -41,29 +41,58 @@ func ExperimentQueryPost(w http.ResponseWriter, r *http.Request) {
 		queryRes = getExperimentQueryPostResponse(ctx, errutil.BadArgsErr, fmt.Sprintf("req body format error: %s", err.Error()), nil, 0)
 	} else {
 		ctx = utils.GetCtxWithTraceId(ctx, queryReq.TraceId)
-		db, dbErr := storage.GetExperimentStore()
-		if dbErr != nil {
-			queryRes = getExperimentQueryPostResponse(ctx, errutil.DBErr, fmt.Sprintf("get db error: %s", dbErr.Error()), nil, 0)
-		} else {
-			exps, total, qErr := db.QueryByOption(queryReq.Uid, queryReq.Status, queryReq.Target, queryReq.Fault,
-				queryReq.Creator, queryReq.ContainerRuntime, queryReq.ContainerId, uint(queryReq.Offset), uint(queryReq.Limit))
-			if qErr != nil {
-				queryRes = getExperimentQueryPostResponse(ctx, errutil.DBErr, fmt.Sprintf("db query error: %s", qErr.Error()), nil, 0)
-			}
-			queryRes = getExperimentQueryPostResponse(ctx, errutil.NoErr, "success", exps, total)
-		}
+		queryRes = QueryExperiments(ctx, queryReq)
 	}
 
 	WriteResponse(ctx, w, queryRes)
 }
 
+// QueryExperiments is shared by the http and grpc services
+func QueryExperiments(ctx context.Context, queryReq *model.QueryRequest) *model.QueryResponse {
+	if queryReq.Offset < 0 || queryReq.Limit < 0 {
+		return getExperimentQueryPostResponse(ctx, errutil.BadArgsErr, "offset and limit must not be less than 0", nil, 0)
+	}
+
+	filter := &storage.ExperimentFilter{
+		Uid:              queryReq.Uid,
+		Status:           storage.ParseStatusList(queryReq.Status),
+		Target:           queryReq.Target,
+		Fault:            queryReq.Fault,
+		Creator:          queryReq.Creator,
+		ContainerRuntime: queryReq.ContainerRuntime,
+		ContainerId:      queryReq.ContainerId,
+		StartTime:        queryReq.StartTime,
+		EndTime:          queryReq.EndTime,
+		Offset:           uint(queryReq.Offset),
+		Limit:            uint(queryReq.Limit),
+	}
+	if err := filter.Validate(); err != nil {
+		return getExperimentQueryPostResponse(ctx, errutil.BadArgsErr, err.Error(), nil, 0)
+	}
+
+	db, dbErr := storage.GetExperimentStore()
+	if dbErr != nil {
+		return getExperimentQueryPostResponse(ctx, errutil.DBErr, fmt.Sprintf("get db error: %s", dbErr.Error()), nil, 0)
+	}
+
+	exps, total, qErr := db.QueryByFilter(filter)
+	if qErr != nil {
+		return getExperimentQueryPostResponse(ctx, errutil.DBErr, fmt.Sprintf("db query error: %s", qErr.Error()), nil, 0)
+	}
+
+	re := getExperimentQueryPostResponse(ctx, errutil.NoErr, "success", exps, total)
+	re.Data.Offset, re.Data.Limit = filter.Offset, filter.Limit
+	return re
+}
+
 func getExperimentQueryPostResponse(ctx context.Context, code int, msg string, exps []*storage.Experiment, total int64) *model.QueryResponse {
 	var re = &model.QueryResponse{
 		Code:    code,
 		Message: msg,
+		Error:   errutil.GetErrorInfo(code),
 		TraceId: utils.GetTraceId(ctx),
 	}
-	if exps != nil {
+	if code == errutil.NoErr {
 		reList := make([]model.ExperimentDataUnit, len(exps))
 		for i, exp := range exps {
 			reList[i] = ExpToExperimentDataUnit(exp)
@@ -86,6 +115,7 @@ func ExpToExperimentDataUnit(exp *storage.Experiment) model.ExperimentDataUnit {
 		Args:             exp.Args,
 		Runtime:          exp.Runtime,
 		Timeout:          exp.Timeout,
+		Pulse:            exp.Pulse,
 		Status:           exp.Status,
 		Creator:          exp.Creator,
 		Error_:           exp.Error,
@@ -93,5 +123,8 @@ func ExpToExperimentDataUnit(exp *storage.Experiment) model.ExperimentDataUnit {
 		UpdateTime:       exp.UpdateTime,
 		ContainerId:      exp.ContainerId,
 		ContainerRuntime: exp.ContainerRuntime,
+		PreHook:          exp.PreHook,
+		PostHook:         exp.PostHook,
+		HookOutput:       exp.HookOutput,
 	}
 }
@@ -41,17 +41,23 @@ func ExperimentRecoverPost(w http.ResponseWriter, r *http.Request) {
 		recoverRes = getCommonResponse(ctx, errutil.BadArgsErr, fmt.Sprintf("req body format error: %s", err.Error()))
 	} else {
 		ctx = utils.GetCtxWithTraceId(ctx, recoverReq.TraceId)
-		code, msg := injector.ProcessRecover(ctx, recoverReq.Uid)
-		recoverRes = getCommonResponse(ctx, code, msg)
+		recoverRes = RecoverExperiment(ctx, recoverReq)
 	}
 
 	WriteResponse(ctx, w, recoverRes)
 }
 
+// RecoverExperiment is shared by the http and grpc services
+func RecoverExperiment(ctx context.Context, recoverReq *model.RecoverRequest) *model.CommonResponse {
+	code, msg := injector.ProcessRecover(ctx, recoverReq.Uid)
+	return getCommonResponse(ctx, code, msg)
+}
+
 func getCommonResponse(ctx context.Context, code int, msg string) *model.CommonResponse {
 	return &model.CommonResponse{
 		Code:    code,
 		Message: msg,
+		Error:   errutil.GetErrorInfo(code),
 		TraceId: utils.GetTraceId(ctx),
 	}
 }
@@ -0,0 +1,37 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package handler
+
+import (
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/snapshot"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/web/model"
+	"net/http"
+)
+
+// SnapshotGet takes a snapshot of the host, or of the container given by "container_runtime" and "container_id"
+func SnapshotGet(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	w.WriteHeader(http.StatusOK)
+	ctx := utils.GetCtxWithTraceId(r.Context(), utils.TraceId)
+	query := r.URL.Query()
+	WriteResponse(ctx, w, &model.SnapshotResponse{
+		Code:    0,
+		Message: "success",
+		Data:    snapshot.Take(ctx, query.Get("container_runtime"), query.Get("container_id")),
+	})
+}
@@ -42,50 +42,60 @@ func ExperimentInjectPost(w http.ResponseWriter, r *http.Request) {
 		injectRes = getExperimentInjectPostResponse(ctx, errutil.BadArgsErr, fmt.Sprintf("req body format error: %s", err.Error()), nil)
 	} else {
 		ctx = utils.GetCtxWithTraceId(ctx, injectReq.TraceId)
-		i, err := injector.NewInjector(injectReq.Target, injectReq.Fault)
-		if err != nil {
-			injectRes = getExperimentInjectPostResponse(ctx, errutil.BadArgsErr, fmt.Sprintf("get injector error: %s", err.Error()), nil)
-		} else {
-			creator := injectReq.Creator
-			if creator == "" {
-				creator = r.RemoteAddr
-			}
-
-			if err := i.LoadInjector(&storage.Experiment{
-				Uid:              injectReq.Uid,
-				Target:           injectReq.Target,
-				Fault:            injectReq.Fault,
-				Args:             injectReq.Args,
-				Timeout:          injectReq.Timeout,
-				ContainerRuntime: injectReq.ContainerRuntime,
-				ContainerId:      injectReq.ContainerId,
-				Creator:          creator,
-				Runtime:          "{}",
-			}, i.GetArgs(), i.GetRuntime()); err != nil {
-				injectRes = getExperimentInjectPostResponse(ctx, errutil.BadArgsErr, fmt.Sprintf("args load error: %s", err.Error()), nil)
-			} else {
-				code, msg := injector.ProcessInject(ctx, i)
-				if code == errutil.NoErr {
-					exp, err := i.OptionToExp(i.GetArgs(), i.GetRuntime())
-					if err != nil {
-						injectRes = getExperimentInjectPostResponse(ctx, errutil.NoErr, fmt.Sprintf("inject success but get exp info error: %s", err.Error()), nil)
-					} else {
-						injectRes = getExperimentInjectPostResponse(ctx, errutil.NoErr, "success", exp)
-					}
-				} else {
-					injectRes = getExperimentInjectPostResponse(ctx, errutil.InjectErr, fmt.Sprintf("injector error: %s", msg), nil)
-				}
-			}
-		}
+		injectRes = InjectExperiment(ctx, injectReq, r.RemoteAddr)
 	}
 
 	WriteResponse(ctx, w, injectRes)
 }
 
+// InjectExperiment is shared by the http and grpc services, remoteAddr is the creator if the request has no creator
+func InjectExperiment(ctx context.Context, injectReq *model.InjectRequest, remoteAddr string) *model.InjectResponse {
+	i, err := injector.NewInjector(injectReq.Target, injectReq.Fault)
+	if err != nil {
+		return getExperimentInjectPostResponse(ctx, errutil.BadArgsErr, fmt.Sprintf("get injector error: %s", err.Error()), nil)
+	}
+
+	creator := injectReq.Creator
+	if creator == "" {
+		creator = remoteAddr
+	}
+
+	if err := i.LoadInjector(&storage.Experiment{
+		Uid:              injectReq.Uid,
+		Target:           injectReq.Target,
+		Fault:            injectReq.Fault,
+		Args:             injectReq.Args,
+		Timeout:          injectReq.Timeout,
+		Pulse:            injectReq.Pulse,
+		ContainerRuntime: injectReq.ContainerRuntime,
+		ContainerId:      injectReq.ContainerId,
+		Creator:          creator,
+		PreHook:          injectReq.PreHook,
+		PostHook:         injectReq.PostHook,
+		Runtime:          "{}",
+	}, i.GetArgs(), i.GetRuntime()); err != nil {
+		return getExperimentInjectPostResponse(ctx, errutil.BadArgsErr, fmt.Sprintf("args load error: %s", err.Error()), nil)
+	}
+
+	code, msg := injector.ProcessInject(ctx, i)
+	if code != errutil.NoErr {
+		// keep the code of the failed step, so that the caller can tell a bad argument from an execution failure
+		return getExperimentInjectPostResponse(ctx, code, fmt.Sprintf("injector error: %s", msg), nil)
+	}
+
+	exp, err := i.OptionToExp(i.GetArgs(), i.GetRuntime())
+	if err != nil {
+		return getExperimentInjectPostResponse(ctx, errutil.NoErr, fmt.Sprintf("inject success but get exp info error: %s", err.Error()), nil)
+	}
+
+	return getExperimentInjectPostResponse(ctx, errutil.NoErr, "success", exp)
+}
+
 func getExperimentInjectPostResponse(ctx context.Context, code int, msg string, exp *storage.Experiment) *model.InjectResponse {
 	var re = &model.InjectResponse{
 		Code:    code,
 		Message: msg,
+		Error:   errutil.GetErrorInfo(code),
 		TraceId: utils.GetTraceId(ctx),
 	}
 
@@ -0,0 +1,24 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package model
+
+type CapabilityRequest struct {
+	Target           string `json:"target,omitempty"`
+	Fault            string `json:"fault,omitempty"`
+	ContainerRuntime string `json:"container_runtime,omitempty"`
+	ContainerId      string `json:"container_id,omitempty"`
+}
@@ -16,8 +16,11 @@
 
 package model
 
+import "github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/errutil"
+
 type CommonResponse struct {
-	Code    int    `json:"code"`
-	Message string `json:"message"`
-	TraceId string `json:"trace_id,omitempty"`
+	Code    int                `json:"code"`
+	Message string             `json:"message"`
+	Error   *errutil.ErrorInfo `json:"error,omitempty"`
+	TraceId string             `json:"trace_id,omitempty"`
 }
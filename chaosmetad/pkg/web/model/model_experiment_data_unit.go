@@ -25,9 +25,13 @@ type ExperimentDataUnit struct {
 	Status           string `json:"status"`
 	Creator          string `json:"creator"`
 	Timeout          string `json:"timeout,omitempty"`
+	Pulse            string `json:"pulse,omitempty"`
 	Error_           string `json:"error,omitempty"`
 	CreateTime       string `json:"create_time,omitempty"`
 	UpdateTime       string `json:"update_time,omitempty"`
 	ContainerId      string `json:"container_id,omitempty"`
 	ContainerRuntime string `json:"container_runtime,omitempty"`
+	PreHook          string `json:"pre_hook,omitempty"`
+	PostHook         string `json:"post_hook,omitempty"`
+	HookOutput       string `json:"hook_output,omitempty"`
 }
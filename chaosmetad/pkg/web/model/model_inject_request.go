@@ -20,10 +20,13 @@ type InjectRequest struct {
 	Target           string `json:"target"`
 	Fault            string `json:"fault"`
 	Timeout          string `json:"timeout"`
+	Pulse            string `json:"pulse,omitempty"`
 	Creator          string `json:"creator"`
 	Args             string `json:"args"`
 	ContainerId      string `json:"container_id"`
 	ContainerRuntime string `json:"container_runtime"`
 	TraceId          string `json:"trace_id"`
 	Uid              string `json:"uid"`
+	PreHook          string `json:"pre_hook,omitempty"`
+	PostHook         string `json:"post_hook,omitempty"`
 }
@@ -17,14 +17,18 @@
 package model
 
 type QueryRequest struct {
-	Uid              string `json:"uid,omitempty"`
+	Uid string `json:"uid,omitempty"`
+	// Status supports multiple statuses joined by ",", eg: success,idle
 	Status           string `json:"status,omitempty"`
 	Target           string `json:"target,omitempty"`
 	Fault            string `json:"fault,omitempty"`
 	Creator          string `json:"creator,omitempty"`
 	ContainerId      string `json:"container_id,omitempty"`
 	ContainerRuntime string `json:"container_runtime,omitempty"`
-	Offset           int32  `json:"offset,omitempty"`
-	Limit            int32  `json:"limit,omitempty"`
-	TraceId          string `json:"trace_id,omitempty"`
+	// StartTime and EndTime limit the create time, eg: 2023-01-01 00:00:00
+	StartTime string `json:"start_time,omitempty"`
+	EndTime   string `json:"end_time,omitempty"`
+	Offset    int32  `json:"offset,omitempty"`
+	Limit     int32  `json:"limit,omitempty"`
+	TraceId   string `json:"trace_id,omitempty"`
 }
@@ -16,9 +16,12 @@
 
 package model
 
+import "github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/errutil"
+
 type QueryResponse struct {
 	Code    int                `json:"code"`
 	Message string             `json:"message"`
+	Error   *errutil.ErrorInfo `json:"error,omitempty"`
 	Data    *QueryResponseData `json:"data,omitempty"`
 	TraceId string             `json:"trace_id,omitempty"`
 }
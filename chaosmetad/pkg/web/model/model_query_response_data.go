@@ -18,5 +18,7 @@ package model
 
 type QueryResponseData struct {
 	Total       int64                `json:"total"`
+	Offset      uint                 `json:"offset"`
+	Limit       uint                 `json:"limit"`
 	Experiments []ExperimentDataUnit `json:"experiments,omitempty"`
 }
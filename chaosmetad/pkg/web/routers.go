@@ -91,12 +91,61 @@ var routes = Routes{
 		handler.ExperimentRecoverPost,
 	},
 
+	Route{
+		"ExperimentPausePost",
+		strings.ToUpper("Post"),
+		"/v1/experiment/pause",
+		handler.ExperimentPausePost,
+	},
+
+	Route{
+		"ExperimentResumePost",
+		strings.ToUpper("Post"),
+		"/v1/experiment/resume",
+		handler.ExperimentResumePost,
+	},
+
 	Route{
 		"VersionGet",
 		strings.ToUpper("Get"),
 		"/v1/version",
 		handler.VersionGet,
 	},
+
+	Route{
+		"DoctorGet",
+		strings.ToUpper("Get"),
+		"/v1/doctor",
+		handler.DoctorGet,
+	},
+
+	Route{
+		"CapabilityGet",
+		strings.ToUpper("Get"),
+		"/v1/capability",
+		handler.CapabilityGet,
+	},
+
+	Route{
+		"SnapshotGet",
+		strings.ToUpper("Get"),
+		"/v1/snapshot",
+		handler.SnapshotGet,
+	},
+
+	Route{
+		"ConfigGet",
+		strings.ToUpper("Get"),
+		"/v1/config",
+		handler.ConfigGet,
+	},
+
+	Route{
+		"ConfigPost",
+		strings.ToUpper("Post"),
+		"/v1/config",
+		handler.ConfigPost,
+	},
 }
 
 var pprofRoutes = Routes{
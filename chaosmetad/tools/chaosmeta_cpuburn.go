@@ -20,43 +20,78 @@ import (
 	"fmt"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/containercgroup"
 	"github.com/traas-stack/chaosmeta/chaosmetad/tools/common"
+	"math"
+	"math/rand"
 	"os"
 	"strconv"
+	"sync/atomic"
 	"time"
 )
 
+const (
+	profileConstant = "constant"
+	profileSquare   = "square"
+	profileSine     = "sine"
+	profileRandom   = "random"
+
+	profileInterval = 200 * time.Millisecond
+)
+
 var nowTargetPercent, worktime, sleeptime int
 
-// uid core percent pid timeout
+// maxPercent is written by runProfile and read by adjustPercent in another goroutine
+var maxPercent int64
+
+// uid core percent pid timeout [profile min-percent period ramp]
 func main() {
 	args := os.Args
 	if len(args) < 6 {
-		common.ExitWithErr("must provide 5 args: uid、core、percent、target pid、timeout")
+		common.ExitWithArgsErr("must provide 5 args: uid、core、percent、target pid、timeout")
 	}
 
 	coreStr, percentStr, targetPidStr, timeoutStr := args[2], args[3], args[4], args[5]
 	core, err := strconv.Atoi(coreStr)
 	if err != nil {
-		common.ExitWithErr(fmt.Sprintf("core[%s] is not a num: %s", coreStr, err.Error()))
+		common.ExitWithArgsErr(fmt.Sprintf("core[%s] is not a num: %s", coreStr, err.Error()))
 	}
 
 	percent, err := strconv.Atoi(percentStr)
 	if err != nil {
-		common.ExitWithErr(fmt.Sprintf("percent[%s] is not a num: %s", percentStr, err.Error()))
+		common.ExitWithArgsErr(fmt.Sprintf("percent[%s] is not a num: %s", percentStr, err.Error()))
 	}
 
 	targetPid, err := strconv.Atoi(targetPidStr)
 	if err != nil {
-		common.ExitWithErr(fmt.Sprintf("pid[%s] is not a num: %s", targetPidStr, err.Error()))
+		common.ExitWithArgsErr(fmt.Sprintf("pid[%s] is not a num: %s", targetPidStr, err.Error()))
 	}
 
 	timeout, err := strconv.Atoi(timeoutStr)
 	if err != nil {
-		common.ExitWithErr(fmt.Sprintf("timeout[%s] is not a num: %s", timeoutStr, err.Error()))
+		common.ExitWithArgsErr(fmt.Sprintf("timeout[%s] is not a num: %s", timeoutStr, err.Error()))
+	}
+
+	var profile = profileConstant
+	var minPercent, period, ramp int
+	if len(args) >= 10 {
+		profile = args[6]
+		if minPercent, err = strconv.Atoi(args[7]); err != nil {
+			common.ExitWithArgsErr(fmt.Sprintf("min percent[%s] is not a num: %s", args[7], err.Error()))
+		}
+		if period, err = strconv.Atoi(args[8]); err != nil {
+			common.ExitWithArgsErr(fmt.Sprintf("period[%s] is not a num: %s", args[8], err.Error()))
+		}
+		if ramp, err = strconv.Atoi(args[9]); err != nil {
+			common.ExitWithArgsErr(fmt.Sprintf("ramp[%s] is not a num: %s", args[9], err.Error()))
+		}
 	}
 
-	if percent < 100 {
-		go adjustPercent(targetPid, core, percent)
+	atomic.StoreInt64(&maxPercent, int64(percent))
+	if profile != profileConstant || ramp > 0 {
+		atomic.StoreInt64(&maxPercent, 0)
+		go runProfile(profile, minPercent, percent, period, ramp)
+		go adjustPercent(targetPid, core)
+	} else if percent < 100 {
+		go adjustPercent(targetPid, core)
 	} else {
 		nowTargetPercent = 100
 	}
@@ -88,7 +123,43 @@ func burnCpu() {
 	}
 }
 
-func adjustPercent(targetPid, core, maxPercent int) {
+// runProfile updates maxPercent along the waveform between minPercent and percent, scaled linearly during the ramp-up
+func runProfile(profile string, minPercent, percent, period, ramp int) {
+	start, r := time.Now(), rand.New(rand.NewSource(time.Now().UnixNano()))
+	periodSec, walk, lastStep := float64(period), float64(minPercent+percent)/2, time.Now()
+	for {
+		elapsed := time.Since(start).Seconds()
+		var target float64
+		switch profile {
+		case profileSquare:
+			if math.Mod(elapsed, periodSec) < periodSec/2 {
+				target = float64(percent)
+			} else {
+				target = float64(minPercent)
+			}
+		case profileSine:
+			target = float64(minPercent) + float64(percent-minPercent)*(1+math.Sin(2*math.Pi*elapsed/periodSec))/2
+		case profileRandom:
+			if time.Since(lastStep).Seconds() >= periodSec/10 {
+				lastStep = time.Now()
+				walk += (r.Float64()*2 - 1) * float64(percent-minPercent) / 5
+				walk = math.Max(float64(minPercent), math.Min(float64(percent), walk))
+			}
+			target = walk
+		default:
+			target = float64(percent)
+		}
+
+		if ramp > 0 && elapsed < float64(ramp) {
+			target = target * elapsed / float64(ramp)
+		}
+
+		atomic.StoreInt64(&maxPercent, int64(target))
+		time.Sleep(profileInterval)
+	}
+}
+
+func adjustPercent(targetPid, core int) {
 	for {
 		p, err := containercgroup.CalculateNowPercent(targetPid)
 		//p, err := cpu.Percent(2*time.Second, true)
@@ -96,7 +167,7 @@ func adjustPercent(targetPid, core, maxPercent int) {
 			common.ExitWithErr(fmt.Sprintf("get cpu usage error: %s", err.Error()))
 		}
 
-		needAdd := maxPercent - int(p[core])
+		needAdd := int(atomic.LoadInt64(&maxPercent)) - int(p[core])
 		if needAdd+nowTargetPercent < 0 {
 			nowTargetPercent = 0
 		} else {
@@ -0,0 +1,450 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmetad/tools/common"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+const (
+	protocolMySQL = "mysql"
+	protocolRedis = "redis"
+
+	modeDelay = "delay"
+	modeError = "error"
+
+	commandAll = "all"
+
+	// soOriginalDst is SO_ORIGINAL_DST of netfilter, the destination before REDIRECT
+	soOriginalDst = 80
+
+	errMessage = "injected by chaosmeta"
+
+	mysqlComQuery     = 0x03
+	mysqlPacketOK     = 0x00
+	mysqlPacketErr    = 0xff
+	mysqlClientSSL    = 0x00000800
+	mysqlSSLReqLength = 32
+	mysqlSQLState     = "HY000"
+)
+
+type proxyConfig struct {
+	mark     int
+	protocol string
+	mode     string
+	delay    time.Duration
+	value    string
+	commands []string
+}
+
+// [uid] [proxy-port] [mark] [protocol] [mode] [value] [commands] [timeout]
+func main() {
+	args := os.Args
+	if len(args) < 9 {
+		common.ExitWithArgsErr("must provide 8 args: uid、proxy-port、mark、protocol、mode、value、commands、timeout")
+	}
+
+	proxyPort, err := strconv.Atoi(args[2])
+	if err != nil || proxyPort <= 0 {
+		common.ExitWithArgsErr("proxy-port is invalid")
+	}
+
+	mark, err := strconv.Atoi(args[3])
+	if err != nil {
+		common.ExitWithArgsErr("mark is invalid")
+	}
+
+	c := &proxyConfig{mark: mark, protocol: args[4], mode: args[5], value: args[6]}
+	if c.protocol != protocolMySQL && c.protocol != protocolRedis {
+		common.ExitWithArgsErr(fmt.Sprintf("protocol only support: %s、%s", protocolMySQL, protocolRedis))
+	}
+
+	switch c.mode {
+	case modeDelay:
+		ms, err := strconv.Atoi(c.value)
+		if err != nil || ms <= 0 {
+			common.ExitWithArgsErr("delay value is invalid")
+		}
+		c.delay = time.Duration(ms) * time.Millisecond
+	case modeError:
+		if c.protocol == protocolMySQL {
+			if code, err := strconv.Atoi(c.value); err != nil || code <= 0 || code > 65535 {
+				common.ExitWithArgsErr("mysql error code is invalid")
+			}
+		}
+	default:
+		common.ExitWithArgsErr(fmt.Sprintf("mode only support: %s、%s", modeDelay, modeError))
+	}
+
+	if args[7] != commandAll {
+		c.commands = strings.Split(strings.ToUpper(args[7]), ",")
+	}
+
+	timeout, err := strconv.Atoi(args[8])
+	if err != nil {
+		common.ExitWithArgsErr(fmt.Sprintf("timeout value is not a valid int, error: %s", err.Error()))
+	}
+
+	ln, err := net.Listen("tcp4", fmt.Sprintf(":%d", proxyPort))
+	if err != nil {
+		common.ExitWithErr(fmt.Sprintf("listen on %d error: %s", proxyPort, err.Error()))
+	}
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				common.ExitWithErr(fmt.Sprintf("proxy accept error: %s", err.Error()))
+			}
+			go handleConn(c, conn, proxyPort)
+		}
+	}()
+
+	fmt.Println("[success]inject success")
+
+	common.SleepWait(timeout)
+}
+
+func handleConn(c *proxyConfig, client net.Conn, proxyPort int) {
+	defer client.Close()
+
+	dst, err := getOriginalDst(client)
+	// a connection to the proxy port directly has itself as the original destination
+	if err != nil || strings.HasSuffix(dst, fmt.Sprintf(":%d", proxyPort)) {
+		return
+	}
+
+	server, err := dialWithMark(dst, c.mark)
+	if err != nil {
+		return
+	}
+	defer server.Close()
+
+	if c.protocol == protocolMySQL {
+		proxyMySQL(c, client, server)
+	} else {
+		proxyRedis(c, client, server)
+	}
+}
+
+func (c *proxyConfig) match(command string) bool {
+	if len(c.commands) == 0 {
+		return true
+	}
+
+	command = strings.ToUpper(command)
+	for _, unit := range c.commands {
+		if unit == command {
+			return true
+		}
+	}
+
+	return false
+}
+
+/*=======================================MySQL===================================================*/
+
+// proxyMySQL the text queries are only inspected after the server accepts the authentication, the mysql protocol is
+// request-response, so an error packet can be replied instead of the response of the server
+func proxyMySQL(c *proxyConfig, client, server net.Conn) {
+	var authed int32
+	go func() {
+		defer client.Close()
+		for atomic.LoadInt32(&authed) == 0 {
+			header, payload, err := readMySQLPacket(server)
+			if err != nil {
+				return
+			}
+
+			// the flag is set before the client gets the ok packet, so that its next packet is a command
+			if len(payload) > 0 && payload[0] == mysqlPacketOK {
+				atomic.StoreInt32(&authed, 1)
+			}
+
+			if _, err := client.Write(append(header, payload...)); err != nil {
+				return
+			}
+		}
+		_, _ = io.Copy(client, server)
+	}()
+
+	for {
+		header, payload, err := readMySQLPacket(client)
+		if err != nil {
+			return
+		}
+
+		// the tls connection can not be inspected, it is relayed as it is
+		if atomic.LoadInt32(&authed) == 0 && len(payload) == mysqlSSLReqLength && binary.LittleEndian.Uint32(payload)&mysqlClientSSL != 0 {
+			if _, err := server.Write(append(header, payload...)); err != nil {
+				return
+			}
+			atomic.StoreInt32(&authed, 1)
+			_, _ = io.Copy(server, client)
+			return
+		}
+
+		seq := header[3]
+		if atomic.LoadInt32(&authed) == 1 && seq == 0 && len(payload) > 0 && payload[0] == mysqlComQuery && c.match(getMySQLStatement(payload)) {
+			if c.mode == modeDelay {
+				time.Sleep(c.delay)
+			} else {
+				if _, err := client.Write(getMySQLErrPacket(c.value, seq+1)); err != nil {
+					return
+				}
+				continue
+			}
+		}
+
+		if _, err := server.Write(append(header, payload...)); err != nil {
+			return
+		}
+	}
+}
+
+func readMySQLPacket(r io.Reader) ([]byte, []byte, error) {
+	header := make([]byte, 4)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, nil, err
+	}
+
+	payload := make([]byte, int(header[0])|int(header[1])<<8|int(header[2])<<16)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return nil, nil, err
+	}
+
+	return header, payload, nil
+}
+
+// getMySQLStatement returns the first keyword of the statement of COM_QUERY, such as "SELECT"
+func getMySQLStatement(payload []byte) string {
+	fields := strings.Fields(string(payload[1:]))
+	if len(fields) == 0 {
+		return ""
+	}
+
+	return fields[0]
+}
+
+func getMySQLErrPacket(code string, seq byte) []byte {
+	errCode, _ := strconv.Atoi(code)
+	payload := []byte{mysqlPacketErr, byte(errCode), byte(errCode >> 8), '#'}
+	payload = append(payload, mysqlSQLState...)
+	payload = append(payload, errMessage...)
+
+	return append([]byte{byte(len(payload)), byte(len(payload) >> 8), byte(len(payload) >> 16), seq}, payload...)
+}
+
+/*=======================================Redis===================================================*/
+
+// proxyRedis relays one command and its reply at a time, so that the injected error replies keep the order of the
+// pipelined commands. The connection is relayed as it is after it enters the push mode, such as SUBSCRIBE
+func proxyRedis(c *proxyConfig, client, server net.Conn) {
+	clientReader, serverReader := bufio.NewReader(client), bufio.NewReader(server)
+	for {
+		raw, command, err := readRedisCommand(clientReader)
+		if err != nil {
+			return
+		}
+
+		if c.match(command) {
+			if c.mode == modeDelay {
+				time.Sleep(c.delay)
+			} else {
+				if _, err := client.Write([]byte(fmt.Sprintf("-%s %s\r\n", c.value, errMessage))); err != nil {
+					return
+				}
+				continue
+			}
+		}
+
+		if _, err := server.Write(raw); err != nil {
+			return
+		}
+
+		if isRedisPushCommand(command) {
+			go func() {
+				_, _ = io.Copy(server, clientReader)
+				server.Close()
+			}()
+			_, _ = io.Copy(client, serverReader)
+			return
+		}
+
+		reply, err := readRedisValue(serverReader)
+		if err != nil {
+			return
+		}
+
+		if _, err := client.Write(reply); err != nil {
+			return
+		}
+	}
+}
+
+func isRedisPushCommand(command string) bool {
+	switch strings.ToUpper(command) {
+	case "SUBSCRIBE", "PSUBSCRIBE", "SSUBSCRIBE", "MONITOR":
+		return true
+	}
+
+	return false
+}
+
+// readRedisCommand reads a command in array of bulk strings or inline format, the name of the command is returned
+func readRedisCommand(r *bufio.Reader) ([]byte, string, error) {
+	b, err := r.Peek(1)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if b[0] != '*' {
+		line, err := r.ReadBytes('\n')
+		if err != nil {
+			return nil, "", err
+		}
+
+		fields := strings.Fields(string(line))
+		if len(fields) == 0 {
+			return line, "", nil
+		}
+		return line, fields[0], nil
+	}
+
+	raw, err := readRedisValue(r)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// *<n>\r\n$<len>\r\n<name>\r\n
+	parts := strings.SplitN(string(raw), "\r\n", 4)
+	if len(parts) < 3 {
+		return raw, "", nil
+	}
+
+	return raw, parts[2], nil
+}
+
+// readRedisValue reads a whole RESP2/RESP3 value and returns its raw bytes
+func readRedisValue(r *bufio.Reader) ([]byte, error) {
+	line, err := r.ReadBytes('\n')
+	if err != nil {
+		return nil, err
+	}
+
+	if len(line) < 3 {
+		return nil, fmt.Errorf("invalid line: %q", line)
+	}
+
+	switch line[0] {
+	case '$', '!', '=':
+		n, err := strconv.Atoi(strings.TrimSpace(string(line[1:])))
+		if err != nil {
+			return nil, err
+		}
+		if n < 0 {
+			return line, nil
+		}
+
+		data := make([]byte, n+2)
+		if _, err := io.ReadFull(r, data); err != nil {
+			return nil, err
+		}
+		return append(line, data...), nil
+	case '*', '~', '>', '%', '|':
+		n, err := strconv.Atoi(strings.TrimSpace(string(line[1:])))
+		if err != nil {
+			return nil, err
+		}
+		if line[0] == '%' || line[0] == '|' {
+			n *= 2
+		}
+
+		for i := 0; i < n; i++ {
+			elem, err := readRedisValue(r)
+			if err != nil {
+				return nil, err
+			}
+			line = append(line, elem...)
+		}
+		return line, nil
+	default:
+		return line, nil
+	}
+}
+
+/*=======================================Common===================================================*/
+
+func dialWithMark(addr string, mark int) (net.Conn, error) {
+	dialer := &net.Dialer{
+		Timeout: 30 * time.Second,
+		// the mark makes the connections to the real server skip the redirect rule
+		Control: func(network, address string, c syscall.RawConn) error {
+			var sErr error
+			if err := c.Control(func(fd uintptr) {
+				sErr = syscall.SetsockoptInt(int(fd), syscall.SOL_SOCKET, syscall.SO_MARK, mark)
+			}); err != nil {
+				return err
+			}
+			return sErr
+		},
+	}
+
+	return dialer.Dial("tcp4", addr)
+}
+
+// getOriginalDst gets the ipv4 destination of the connection before it was redirected by iptables
+func getOriginalDst(c net.Conn) (string, error) {
+	tcpConn, ok := c.(*net.TCPConn)
+	if !ok {
+		return "", fmt.Errorf("not a tcp connection")
+	}
+
+	raw, err := tcpConn.SyscallConn()
+	if err != nil {
+		return "", err
+	}
+
+	var (
+		addr *syscall.IPv6Mreq
+		gErr error
+	)
+	if err := raw.Control(func(fd uintptr) {
+		// sockaddr_in fits in IPv6Mreq: family(2), port(2), ip(4)
+		addr, gErr = syscall.GetsockoptIPv6Mreq(int(fd), syscall.IPPROTO_IP, soOriginalDst)
+	}); err != nil {
+		return "", err
+	}
+	if gErr != nil {
+		return "", gErr
+	}
+
+	port := int(addr.Multiaddr[2])<<8 | int(addr.Multiaddr[3])
+	ip := net.IPv4(addr.Multiaddr[4], addr.Multiaddr[5], addr.Multiaddr[6], addr.Multiaddr[7])
+	return net.JoinHostPort(ip.String(), strconv.Itoa(port)), nil
+}
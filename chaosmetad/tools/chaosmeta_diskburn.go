@@ -31,13 +31,13 @@ var notFirst bool
 func main() {
 	args := os.Args
 	if len(args) < 7 {
-		common.ExitWithErr("args must at lease 7")
+		common.ExitWithArgsErr("args must at lease 7")
 	}
 	argsFile, argsMode, argsBs, argsCount, argsFlag, timeStr := args[2], args[3], args[4], args[5], args[6], args[7]
 
 	timeout, err := strconv.Atoi(timeStr)
 	if err != nil {
-		common.ExitWithErr(fmt.Sprintf("args timeout is not a num: %s", err.Error()))
+		common.ExitWithArgsErr(fmt.Sprintf("args timeout is not a num: %s", err.Error()))
 	}
 
 	if argsMode == "write" {
@@ -45,7 +45,7 @@ func main() {
 	} else if argsMode == "read" {
 		go burnReadDisk(argsFile, argsBs, argsCount, argsFlag)
 	} else {
-		common.ExitWithErr("only support one of read or write flag")
+		common.ExitWithArgsErr("only support one of read or write flag")
 	}
 
 	common.SleepWait(timeout)
@@ -27,7 +27,7 @@ import (
 func main() {
 	args := os.Args
 	if len(args) < 6 {
-		common.ExitWithErr("must provide 6 args: uid, dir prefix start end timeout")
+		common.ExitWithArgsErr("must provide 6 args: uid, dir prefix start end timeout")
 	}
 
 	dirname, prefix, startStr, endStr, timeoutStr := args[2], args[3], args[4], args[5], args[6]
@@ -35,17 +35,17 @@ func main() {
 	var timeout int
 	timeout, err := strconv.Atoi(timeoutStr)
 	if err != nil {
-		common.ExitWithErr(fmt.Sprintf("timeout value is not a valid int, error: %s\n", err.Error()))
+		common.ExitWithArgsErr(fmt.Sprintf("timeout value is not a valid int, error: %s\n", err.Error()))
 	}
 
 	start, err := strconv.Atoi(startStr)
 	if err != nil {
-		common.ExitWithErr(fmt.Sprintf("start value is not a valid int, error: %s\n", err.Error()))
+		common.ExitWithArgsErr(fmt.Sprintf("start value is not a valid int, error: %s\n", err.Error()))
 	}
 
 	end, err := strconv.Atoi(endStr)
 	if err != nil {
-		common.ExitWithErr(fmt.Sprintf("end value is not a valid int, error: %s\n", err.Error()))
+		common.ExitWithArgsErr(fmt.Sprintf("end value is not a valid int, error: %s\n", err.Error()))
 	}
 
 	for i := start; i < end; i++ {
@@ -0,0 +1,240 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmetad/tools/common"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	modeDelay    = "delay"
+	modeCode     = "code"
+	modeTruncate = "truncate"
+	// grpc modes talk h2c with the real server, and the grpc status is returned in the headers of a trailers-only response
+	modeGRPCDelay = "grpc-delay"
+	modeGRPCCode  = "grpc-code"
+
+	// soOriginalDst is SO_ORIGINAL_DST of netfilter, the destination before REDIRECT
+	soOriginalDst = 80
+)
+
+type origDstKey struct{}
+
+type truncatedBody struct {
+	io.Reader
+	io.Closer
+}
+
+// [uid] [proxy-port] [mark] [mode] [value] [path] [timeout]
+func main() {
+	args := os.Args
+	if len(args) < 8 {
+		common.ExitWithArgsErr("must provide 7 args: uid、proxy-port、mark、mode、value、path、timeout")
+	}
+
+	proxyPort, err := strconv.Atoi(args[2])
+	if err != nil || proxyPort <= 0 {
+		common.ExitWithArgsErr("proxy-port is invalid")
+	}
+
+	mark, err := strconv.Atoi(args[3])
+	if err != nil {
+		common.ExitWithArgsErr("mark is invalid")
+	}
+
+	mode, path := args[4], args[6]
+	if mode != modeDelay && mode != modeCode && mode != modeTruncate && mode != modeGRPCDelay && mode != modeGRPCCode {
+		common.ExitWithArgsErr(fmt.Sprintf("mode only support: %s、%s、%s、%s、%s", modeDelay, modeCode, modeTruncate, modeGRPCDelay, modeGRPCCode))
+	}
+
+	value, err := strconv.Atoi(args[5])
+	if err != nil || value < 0 {
+		common.ExitWithArgsErr("value is invalid")
+	}
+
+	timeout, err := strconv.Atoi(args[7])
+	if err != nil {
+		common.ExitWithArgsErr(fmt.Sprintf("timeout value is not a valid int, error: %s", err.Error()))
+	}
+
+	ln, err := net.Listen("tcp4", fmt.Sprintf(":%d", proxyPort))
+	if err != nil {
+		common.ExitWithErr(fmt.Sprintf("listen on %d error: %s", proxyPort, err.Error()))
+	}
+
+	server := &http.Server{
+		// h2c serves the http/2 requests without tls, such as grpc calls, and http/1 requests as before
+		Handler: h2c.NewHandler(getHandler(getReverseProxy(mark, mode, value, path), mode, value, path), &http2.Server{}),
+		ConnContext: func(ctx context.Context, c net.Conn) context.Context {
+			dst, err := getOriginalDst(c)
+			// a connection to the proxy port directly has itself as the original destination
+			if err != nil || strings.HasSuffix(dst, fmt.Sprintf(":%d", proxyPort)) {
+				return ctx
+			}
+			return context.WithValue(ctx, origDstKey{}, dst)
+		},
+	}
+	go func() {
+		if err := server.Serve(ln); err != nil {
+			common.ExitWithErr(fmt.Sprintf("proxy serve error: %s", err.Error()))
+		}
+	}()
+
+	fmt.Println("[success]inject success")
+
+	common.SleepWait(timeout)
+}
+
+func getHandler(proxy *httputil.ReverseProxy, mode string, value int, path string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.Context().Value(origDstKey{}).(string); !ok {
+			http.Error(w, "not a redirected request", http.StatusBadGateway)
+			return
+		}
+
+		if matchPath(mode, path, r.URL.Path) {
+			switch mode {
+			case modeDelay, modeGRPCDelay:
+				time.Sleep(time.Duration(value) * time.Millisecond)
+			case modeGRPCCode:
+				w.Header().Set("Content-Type", "application/grpc")
+				w.Header().Set("Grpc-Status", strconv.Itoa(value))
+				w.Header().Set("Grpc-Message", "injected by chaosmeta")
+				w.WriteHeader(http.StatusOK)
+				return
+			}
+		}
+		proxy.ServeHTTP(w, r)
+	})
+}
+
+// matchPath the http faults select the requests by the prefix of path, while a grpc method is matched exactly, so that
+// "/svc/Get" does not select "/svc/GetAll". The grpc path "/svc/" without a method selects all methods of the service
+func matchPath(mode, path, reqPath string) bool {
+	if (mode == modeGRPCDelay || mode == modeGRPCCode) && !strings.HasSuffix(path, "/") {
+		return reqPath == path
+	}
+
+	return strings.HasPrefix(reqPath, path)
+}
+
+func getReverseProxy(mark int, mode string, value int, path string) *httputil.ReverseProxy {
+	dialer := &net.Dialer{
+		Timeout: 30 * time.Second,
+		// the mark makes the connections to the real server skip the redirect rule
+		Control: func(network, address string, c syscall.RawConn) error {
+			var sErr error
+			if err := c.Control(func(fd uintptr) {
+				sErr = syscall.SetsockoptInt(int(fd), syscall.SOL_SOCKET, syscall.SO_MARK, mark)
+			}); err != nil {
+				return err
+			}
+			return sErr
+		},
+	}
+
+	var (
+		transport     http.RoundTripper
+		flushInterval time.Duration
+	)
+	if mode == modeGRPCDelay || mode == modeGRPCCode {
+		transport = &http2.Transport{
+			AllowHTTP: true,
+			DialTLS: func(network, addr string, cfg *tls.Config) (net.Conn, error) {
+				return dialer.Dial(network, addr)
+			},
+		}
+		// streaming calls must not be buffered
+		flushInterval = -1
+	} else {
+		transport = &http.Transport{
+			DialContext:     dialer.DialContext,
+			MaxIdleConns:    100,
+			IdleConnTimeout: 90 * time.Second,
+		}
+	}
+
+	return &httputil.ReverseProxy{
+		Director: func(r *http.Request) {
+			r.URL.Scheme = "http"
+			r.URL.Host = r.Context().Value(origDstKey{}).(string)
+		},
+		Transport:     transport,
+		FlushInterval: flushInterval,
+		ModifyResponse: func(resp *http.Response) error {
+			if !matchPath(mode, path, resp.Request.URL.Path) {
+				return nil
+			}
+
+			switch mode {
+			case modeCode:
+				resp.StatusCode = value
+				resp.Status = fmt.Sprintf("%d %s", value, http.StatusText(value))
+			case modeTruncate:
+				resp.Body = truncatedBody{Reader: io.LimitReader(resp.Body, int64(value)), Closer: resp.Body}
+				resp.ContentLength = -1
+				resp.Header.Del("Content-Length")
+			}
+			return nil
+		},
+	}
+}
+
+// getOriginalDst gets the ipv4 destination of the connection before it was redirected by iptables
+func getOriginalDst(c net.Conn) (string, error) {
+	tcpConn, ok := c.(*net.TCPConn)
+	if !ok {
+		return "", fmt.Errorf("not a tcp connection")
+	}
+
+	raw, err := tcpConn.SyscallConn()
+	if err != nil {
+		return "", err
+	}
+
+	var (
+		addr *syscall.IPv6Mreq
+		gErr error
+	)
+	if err := raw.Control(func(fd uintptr) {
+		// sockaddr_in fits in IPv6Mreq: family(2), port(2), ip(4)
+		addr, gErr = syscall.GetsockoptIPv6Mreq(int(fd), syscall.IPPROTO_IP, soOriginalDst)
+	}); err != nil {
+		return "", err
+	}
+	if gErr != nil {
+		return "", gErr
+	}
+
+	port := int(addr.Multiaddr[2])<<8 | int(addr.Multiaddr[3])
+	ip := net.IPv4(addr.Multiaddr[4], addr.Multiaddr[5], addr.Multiaddr[6], addr.Multiaddr[7])
+	return net.JoinHostPort(ip.String(), strconv.Itoa(port)), nil
+}
@@ -79,7 +79,7 @@ func getStrUnit(unit string) string {
 func writeScore(scoreStr string) error {
 	score, err := strconv.Atoi(scoreStr)
 	if err != nil {
-		return fmt.Errorf("[error]score is not a valid int: %s", err.Error())
+		return fmt.Errorf("score is not a valid int: %s", err.Error())
 	}
 
 	f, err := os.OpenFile("/proc/self/oom_score_adj", os.O_RDWR, 0644)
@@ -100,7 +100,7 @@ func main() {
 	debug.SetGCPercent(-1)
 	args := os.Args
 	if len(args) < 5 {
-		common.ExitWithErr("args must at lease 4. format: [uid] [score] [percent] [bytes] [timeout second]")
+		common.ExitWithArgsErr("args must at lease 4. format: [uid] [score] [percent] [bytes] [timeout second]")
 	}
 
 	score, bytes := args[2], args[4]
@@ -122,7 +122,7 @@ func main() {
 
 	value, unit, err := parseByteValue(fillKBytes)
 	if err != nil {
-		common.ExitWithErr(fmt.Sprintf("parse byte value error: %s", err.Error()))
+		common.ExitWithArgsErr(fmt.Sprintf("parse byte value error: %s", err.Error()))
 	}
 
 	fmt.Println("[success]inject success")
@@ -137,7 +137,7 @@ func main() {
 	if len(args) > 4 {
 		timeout, err = strconv.Atoi(args[5])
 		if err != nil {
-			common.ExitWithErr(fmt.Sprintf("timeout value is not a valid int, error: %s", err.Error()))
+			common.ExitWithArgsErr(fmt.Sprintf("timeout value is not a valid int, error: %s", err.Error()))
 		}
 	}
 
@@ -31,17 +31,17 @@ import (
 func main() {
 	args := os.Args
 	if len(args) < 5 {
-		common.ExitWithErr(fmt.Sprintf("args must provide: uid, user, count, timeout"))
+		common.ExitWithArgsErr(fmt.Sprintf("args must provide: uid, user, count, timeout"))
 	}
 	user, countStr, timeStr := args[2], args[3], args[4]
 	timeout, err := strconv.Atoi(timeStr)
 	if err != nil {
-		common.ExitWithErr(fmt.Sprintf("args timeout is not a num"))
+		common.ExitWithArgsErr(fmt.Sprintf("args timeout is not a num"))
 	}
 
 	count, err := strconv.Atoi(countStr)
 	if err != nil {
-		common.ExitWithErr(fmt.Sprintf("args count is not a num"))
+		common.ExitWithArgsErr(fmt.Sprintf("args count is not a num"))
 	}
 
 	nproc, err := getNproc()
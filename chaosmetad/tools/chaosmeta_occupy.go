@@ -28,17 +28,17 @@ import (
 func main() {
 	args := os.Args
 	if len(args) < 5 {
-		common.ExitWithErr("must provide 4 args: uid、port、protocol、timeout")
+		common.ExitWithArgsErr("must provide 4 args: uid、port、protocol、timeout")
 	}
 
 	p, proto, t := args[2], args[3], args[4]
 	port, err := strconv.Atoi(p)
 	if err != nil || port <= 0 {
-		common.ExitWithErr("port is invalid")
+		common.ExitWithArgsErr("port is invalid")
 	}
 
 	if proto != "tcp" && proto != "udp" && proto != "tcp6" && proto != "udp6" {
-		common.ExitWithErr("proto only support: udp、tcp、udp6、tcp6")
+		common.ExitWithArgsErr("proto only support: udp、tcp、udp6、tcp6")
 	}
 
 	if proto == "tcp" {
@@ -50,7 +50,7 @@ func main() {
 	var timeout int
 	timeout, err = strconv.Atoi(t)
 	if err != nil {
-		common.ExitWithErr(fmt.Sprintf("timeout value is not a valid int, error: %s", err.Error()))
+		common.ExitWithArgsErr(fmt.Sprintf("timeout value is not a valid int, error: %s", err.Error()))
 	}
 
 	if proto == "tcp4" || proto == "tcp6" {
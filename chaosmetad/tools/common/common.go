@@ -17,14 +17,23 @@
 package common
 
 import (
-	"fmt"
-	"os"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/errutil"
 	"time"
 )
 
+// ExitWithErr reports a runtime error, or the permission and unsupported errors found in msg
 func ExitWithErr(msg string) {
-	fmt.Printf("[error]%s\n", msg)
-	os.Exit(99)
+	code := errutil.GetToolErrCode(msg)
+	if code == errutil.NoErr {
+		code = errutil.ToolRuntimeErr
+	}
+
+	errutil.ExitToolErr(code, msg)
+}
+
+// ExitWithArgsErr reports the invalid args of the tool
+func ExitWithArgsErr(msg string) {
+	errutil.ExitToolErr(errutil.ToolArgsErr, msg)
 }
 
 func SleepWait(timeout int) {